@@ -317,7 +317,7 @@ func (c *DirectClient) Call(
 	if err != nil {
 		return nil, err
 	}
-	return c.ethApi.Call(ctx, *args, transport.BlockNumberOrHash(blockNrOrHash), stateOverride)
+	return c.ethApi.Call(ctx, *args, transport.BlockNumberOrHash(blockNrOrHash), stateOverride, nil)
 }
 
 func (c *DirectClient) EstimateFee(
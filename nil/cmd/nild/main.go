@@ -123,6 +123,20 @@ func parseArgs() *nildconfig.Config {
 	rootCmd.PersistentFlags().DurationVar(
 		&cfg.DB.GcFrequency, "db-gc-interval", cfg.DB.GcFrequency, "frequency for badger GC")
 	rootCmd.PersistentFlags().IntVar(&cfg.RPCPort, "http-port", cfg.RPCPort, "http port for rpc server")
+	rootCmd.PersistentFlags().BoolVar(
+		&cfg.EnableWebsocket,
+		"http-websocket",
+		cfg.EnableWebsocket,
+		"serve websocket connections on the http port, e.g., for subscriptions")
+	rootCmd.PersistentFlags().IntVar(
+		&cfg.EthCompatRPCPort,
+		"eth-compat-http-port",
+		cfg.EthCompatRPCPort,
+		"http port for Ethereum-compatible rpc server (disabled if zero)")
+	rootCmd.PersistentFlags().IntVar(
+		&cfg.RestPort, "rest-port", cfg.RestPort, "http port for read-only REST gateway (disabled if zero)")
+	rootCmd.PersistentFlags().BoolVar(
+		&cfg.EnableGraphQL, "graphql", cfg.EnableGraphQL, "serve GraphQL queries at /graphql of the REST gateway")
 	rootCmd.PersistentFlags().Var(
 		&cfg.BootstrapPeers,
 		"bootstrap-peers",
@@ -162,6 +176,24 @@ func parseArgs() *nildconfig.Config {
 	runCmd.Flags().StringVar(
 		&cfg.ValidatorKeysPath, "validator-keys-path", cfg.ValidatorKeysPath, "path to write validator keys")
 	runCmd.Flags().BoolVar(&cfg.EnableDevApi, "dev-api", cfg.EnableDevApi, "enable development API")
+	runCmd.Flags().BoolVar(&cfg.EnableDebugApi, "debug-api", cfg.EnableDebugApi, "enable transaction tracing API")
+	runCmd.Flags().BoolVar(&cfg.EnableSyncApi, "sync-api", cfg.EnableSyncApi, "serve state snapshots to other nodes")
+	runCmd.Flags().BoolVar(
+		&cfg.EnableTxpoolApi, "txpool-api", cfg.EnableTxpoolApi, "serve transaction pool content to other nodes")
+	runCmd.Flags().BoolVar(
+		&cfg.EnableClusterApi, "cluster-api", cfg.EnableClusterApi, "serve whole-network queries to other nodes")
+	runCmd.Flags().BoolVar(
+		&cfg.EnableProofApi, "proof-api", cfg.EnableProofApi, "serve zk proofs of main shard blocks to other nodes")
+	runCmd.Flags().Uint64Var(
+		&cfg.StateRetentionBlocks,
+		"state-retention-blocks",
+		cfg.StateRetentionBlocks,
+		"serve the state of the latest blocks only, all if zero")
+	runCmd.Flags().BoolVar(
+		&cfg.RejectInvalidTransactions,
+		"reject-invalid-transactions",
+		cfg.RejectInvalidTransactions,
+		"reject sent transactions that fail the checks instead of failing them in a block")
 	runCmd.Flags().StringVar(&cfg.IndexerConfig, "indexer-config", "", "path to Indexer config")
 
 	addBasicFlags(runCmd.Flags(), cfg)
@@ -187,6 +219,12 @@ func parseArgs() *nildconfig.Config {
 		},
 	}
 
+	archiveCmd.Flags().BoolVar(&cfg.EnableSyncApi, "sync-api", cfg.EnableSyncApi, "serve state snapshots to other nodes")
+	archiveCmd.Flags().BoolVar(
+		&cfg.EnableClusterApi, "cluster-api", cfg.EnableClusterApi, "serve whole-network queries to other nodes")
+	archiveCmd.Flags().BoolVar(
+		&cfg.EnableProofApi, "proof-api", cfg.EnableProofApi, "serve zk proofs of main shard blocks to other nodes")
+
 	addBasicFlags(archiveCmd.Flags(), cfg)
 	cmdflags.AddNetwork(archiveCmd.Flags(), cfg.Network)
 	cmdflags.AddTelemetry(archiveCmd.Flags(), cfg.Telemetry)
@@ -199,6 +237,9 @@ func parseArgs() *nildconfig.Config {
 		},
 	}
 	rpcCmd.Flags().BoolVar(&cfg.EnableDevApi, "dev-api", cfg.EnableDevApi, "enable development API")
+	rpcCmd.Flags().BoolVar(&cfg.EnableDebugApi, "debug-api", cfg.EnableDebugApi, "enable transaction tracing API")
+	rpcCmd.Flags().BoolVar(
+		&cfg.EnableTxpoolApi, "txpool-api", cfg.EnableTxpoolApi, "enable transaction pool content API")
 
 	addRpcNodeFlags(rpcCmd.Flags(), cfg)
 	addAllowDbClearFlag(rpcCmd.Flags(), cfg)
@@ -20,6 +20,7 @@ const (
 	FieldPeerId      = "peerId"
 	FieldTopic       = "topic"
 	FieldProtocolID  = "protocolId"
+	FieldIncidentId  = "incidentId"
 
 	FieldTransactionHash  = "txnHash"
 	FieldTransactionSeqno = "txnSeqno"
@@ -37,6 +37,7 @@ type Messages interface {
 		messageType proto.MessageType,
 		isValid func(*proto.IbftMessage) bool,
 	) []*proto.IbftMessage
+	GetMessages(view *proto.View, messageType proto.MessageType) []*proto.IbftMessage
 	GetExtendedRCC(
 		height uint64,
 		isValidMessage func(message *proto.IbftMessage) bool,
@@ -123,6 +124,10 @@ type IBFT struct {
 
 	// commitHistogram is the histogram for "commit" stage duration
 	commitHistogram metric.Float64Histogram
+
+	// timeouts are the latest round timeouts, see RecentTimeouts
+	timeouts     []RoundTimeout
+	timeoutsLock sync.Mutex
 }
 
 // NewIBFTWithMetrics creates a new instance of the IBFT consensus protocol with enabled metrics
@@ -428,6 +433,7 @@ func (i *IBFT) RunSequence(ctx context.Context, h uint64) {
 		case <-i.roundExpired:
 			teardown()
 			i.log.Info("round timeout expired", "round", currentRound)
+			i.recordTimeout(view)
 
 			newRound := currentRound + 1
 			i.moveToNewRound(newRound)
@@ -3065,6 +3065,49 @@ func TestIBFT_ExtendRoundTimer(t *testing.T) {
 	assert.Equal(t, additionalTimeout, i.additionalTimeout)
 }
 
+func TestIBFT_Status(t *testing.T) {
+	t.Parallel()
+
+	var (
+		log       = mockLogger{}
+		backend   = mockBackend{}
+		transport = mockTransport{}
+		view      = &proto.View{Height: 10, Round: 1}
+	)
+
+	i := NewIBFT(log, backend, transport)
+	i.state.reset(view.GetHeight())
+	i.state.setView(view)
+	i.state.changeState(prepare)
+
+	i.messages.AddMessage(&proto.IbftMessage{View: view, From: []byte("node 0"), Type: proto.MessageType_PREPARE})
+	i.messages.AddMessage(&proto.IbftMessage{View: view, From: []byte("node 1"), Type: proto.MessageType_COMMIT})
+	i.messages.AddMessage(&proto.IbftMessage{
+		View: &proto.View{Height: 10, Round: 3},
+		From: []byte("node 2"),
+		Type: proto.MessageType_ROUND_CHANGE,
+	})
+
+	status := i.Status()
+	assert.Equal(t, Status{
+		Height:           10,
+		Round:            1,
+		State:            "prepare",
+		Prepares:         [][]byte{[]byte("node 0")},
+		Commits:          [][]byte{[]byte("node 1")},
+		RoundChangeRound: 3,
+		RoundChanges:     [][]byte{[]byte("node 2")},
+	}, status)
+
+	for range maxRecentTimeouts + 1 {
+		i.recordTimeout(view)
+	}
+	timeouts := i.RecentTimeouts()
+	require.Len(t, timeouts, maxRecentTimeouts)
+	assert.Equal(t, "prepare", timeouts[0].State)
+	assert.Equal(t, view.GetRound(), timeouts[0].Round)
+}
+
 func Test_getRoundTimeout(t *testing.T) {
 	t.Parallel()
 
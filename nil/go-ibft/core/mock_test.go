@@ -267,6 +267,7 @@ type mockMessages struct {
 		isValidRCC func(round uint64, messages []*proto.IbftMessage) bool,
 	) []*proto.IbftMessage
 	getMostRoundChangeMessagesFn func(uint64, uint64) []*proto.IbftMessage
+	getMessagesFn                func(*proto.View, proto.MessageType) []*proto.IbftMessage
 
 	subscribeFn   func(details messages.SubscriptionDetails) *messages.Subscription
 	unsubscribeFn func(id messages.SubscriptionID)
@@ -284,6 +285,14 @@ func (m mockMessages) GetValidMessages(
 	return nil
 }
 
+func (m mockMessages) GetMessages(view *proto.View, messageType proto.MessageType) []*proto.IbftMessage {
+	if m.getMessagesFn != nil {
+		return m.getMessagesFn(view, messageType)
+	}
+
+	return nil
+}
+
 func (m mockMessages) Subscribe(details messages.SubscriptionDetails) *messages.Subscription {
 	if m.subscribeFn != nil {
 		return m.subscribeFn(details)
@@ -0,0 +1,101 @@
+package core
+
+import (
+	"time"
+
+	"github.com/NilFoundation/nil/nil/go-ibft/messages/proto"
+)
+
+// maxRecentTimeouts limits the number of the round timeouts kept for the status
+const maxRecentTimeouts = 32
+
+// Status is the snapshot of the sequence the IBFT instance runs, used to debug the liveness of the consensus
+type Status struct {
+	Height uint64
+	Round  uint64
+	State  string
+
+	// ProposalHash is the hash of the proposal accepted in the current round, nil if there is none yet
+	ProposalHash []byte
+
+	// Prepares and Commits are the senders of the messages received for the current view.
+	// The messages are not validated until the state machine gets to them
+	Prepares [][]byte
+	Commits  [][]byte
+
+	// RoundChangeRound is the round above the current one with the most ROUND_CHANGE messages,
+	// RoundChanges are their senders
+	RoundChangeRound uint64
+	RoundChanges     [][]byte
+}
+
+// RoundTimeout is an expiration of the timer of a round
+type RoundTimeout struct {
+	Height uint64
+	Round  uint64
+	// State is the state the round was in when its timer expired
+	State string
+	Time  time.Time
+}
+
+// Status returns the snapshot of the current sequence
+func (i *IBFT) Status() Status {
+	view := i.state.getView()
+	status := Status{
+		Height:       view.GetHeight(),
+		Round:        view.GetRound(),
+		State:        i.state.getStateName().String(),
+		ProposalHash: i.state.getProposalHash(),
+		Prepares:     i.getSenders(view, proto.MessageType_PREPARE),
+		Commits:      i.getSenders(view, proto.MessageType_COMMIT),
+	}
+
+	roundChanges := i.messages.GetMostRoundChangeMessages(view.GetRound()+1, view.GetHeight())
+	if len(roundChanges) != 0 {
+		status.RoundChangeRound = roundChanges[0].GetView().GetRound()
+	}
+	status.RoundChanges = make([][]byte, len(roundChanges))
+	for index, message := range roundChanges {
+		status.RoundChanges[index] = message.GetFrom()
+	}
+
+	return status
+}
+
+// RecentTimeouts returns the latest round timeouts, the oldest first
+func (i *IBFT) RecentTimeouts() []RoundTimeout {
+	i.timeoutsLock.Lock()
+	defer i.timeoutsLock.Unlock()
+
+	return append([]RoundTimeout(nil), i.timeouts...)
+}
+
+// recordTimeout keeps the expiration of the timer of the round for RecentTimeouts
+func (i *IBFT) recordTimeout(view *proto.View) {
+	i.timeoutsLock.Lock()
+	defer i.timeoutsLock.Unlock()
+
+	if len(i.timeouts) == maxRecentTimeouts {
+		i.timeouts = i.timeouts[1:]
+	}
+
+	i.timeouts = append(i.timeouts, RoundTimeout{
+		Height: view.GetHeight(),
+		Round:  view.GetRound(),
+		State:  i.state.getStateName().String(),
+		Time:   time.Now(),
+	})
+}
+
+// getSenders returns the senders of the messages of the type received for the view.
+// The messages are only read, so the status doesn't contend with the state machine for the queues
+func (i *IBFT) getSenders(view *proto.View, messageType proto.MessageType) [][]byte {
+	msgs := i.messages.GetMessages(view, messageType)
+
+	senders := make([][]byte, len(msgs))
+	for index, message := range msgs {
+		senders[index] = message.GetFrom()
+	}
+
+	return senders
+}
@@ -199,6 +199,23 @@ func (ms *Messages) GetValidMessages(
 	return validMessages
 }
 
+// GetMessages fetches all messages of a specific type for the specified view
+// without validating or pruning them out
+func (ms *Messages) GetMessages(view *proto.View, messageType proto.MessageType) []*proto.IbftMessage {
+	mux := ms.muxMap[messageType]
+	mux.RLock()
+	defer mux.RUnlock()
+
+	messages := ms.getProtoMessages(view, messageType)
+
+	result := make([]*proto.IbftMessage, 0, len(messages))
+	for _, message := range messages {
+		result = append(result, message)
+	}
+
+	return result
+}
+
 // GetExtendedRCC returns Round-Change-Certificate for the highest round
 func (ms *Messages) GetExtendedRCC(
 	height uint64,
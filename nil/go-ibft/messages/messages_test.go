@@ -265,6 +265,32 @@ func TestMessages_GetValidMessagesMessage(t *testing.T) {
 	}
 }
 
+// TestMessages_GetMessages makes sure
+// the messages are fetched without being pruned out
+func TestMessages_GetMessages(t *testing.T) {
+	t.Parallel()
+
+	var (
+		defaultView = &proto.View{
+			Height: 1,
+			Round:  0,
+		}
+		numMessages = 5
+	)
+
+	messages := NewMessages()
+	defer messages.Close()
+
+	for _, message := range generateRandomMessages(numMessages, defaultView, proto.MessageType_PREPARE) {
+		messages.AddMessage(message)
+	}
+
+	assert.Len(t, messages.GetMessages(defaultView, proto.MessageType_PREPARE), numMessages)
+	assert.Equal(t, numMessages, messages.numMessages(defaultView, proto.MessageType_PREPARE))
+	assert.Empty(t, messages.GetMessages(defaultView, proto.MessageType_COMMIT))
+	assert.Empty(t, messages.GetMessages(&proto.View{Height: 2, Round: 0}, proto.MessageType_PREPARE))
+}
+
 // TestMessages_GetExtendedRCC makes sure
 // Messages returns the ROUND-CHANGE messages for the highest round
 // where all messages are valid
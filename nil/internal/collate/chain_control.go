@@ -0,0 +1,251 @@
+package collate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	cerrors "github.com/NilFoundation/nil/nil/internal/collate/errors"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// The controls of the chain are only available without the consensus, i.e., in the single-node devnets.
+// They are serialized with the collation by the scheduler, so the blocks are never collated in between.
+
+// blockGeneratorParams returns the parameters of the block generator in the mode with the time offset applied.
+// The offset is the state of the devnet only, it is neither recorded in the blocks nor proposed to the other
+// validators, so it is only applied without the consensus.
+func (s *Validator) blockGeneratorParams(mode string) execution.BlockGeneratorParams {
+	params := s.params.BlockGeneratorParams
+	params.ExecutionMode = mode
+	if !s.params.DisableConsensus {
+		return params
+	}
+	if offset := s.timeOffset.Load(); offset != 0 {
+		params.BlockContextOverride = func(blockContext *vm.BlockContext) {
+			blockContext.Time += offset
+		}
+	}
+	return params
+}
+
+func (s *Validator) checkChainControlAllowed() error {
+	if !s.params.DisableConsensus {
+		return cerrors.ErrConsensusEnabled
+	}
+	return nil
+}
+
+// CollateBlock collates a block of the transactions from the pool without the consensus and returns its number.
+func (s *Validator) CollateBlock(ctx context.Context) (types.BlockNumber, error) {
+	if err := s.checkChainControlAllowed(); err != nil {
+		return 0, err
+	}
+
+	s.collateMutex.Lock()
+	defer s.collateMutex.Unlock()
+
+	proposal, err := s.BuildProposal(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.InsertProposal(ctx, proposal, &types.ConsensusParams{}); err != nil {
+		return 0, err
+	}
+
+	block, _, err := s.GetLastBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return block.Id, nil
+}
+
+// NextBlockTime returns the time the transactions of the next block see unless it is changed by SetNextBlockTime.
+// The time of the blocks is logical, it is the number of the previous block shifted by the offset set,
+// so the offset doesn't apply to the calls and the replays of the blocks collated before it was set.
+func (s *Validator) NextBlockTime(ctx context.Context) (uint64, error) {
+	if err := s.checkChainControlAllowed(); err != nil {
+		return 0, err
+	}
+
+	block, _, err := s.GetLastBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	// The next block is executed with the number of the last one as its time, see execution.NewEVMBlockContext.
+	return block.Id.Uint64() + s.timeOffset.Load(), nil
+}
+
+// SetNextBlockTime makes the transactions of the next block see the time, the later blocks advance from it.
+// The time can't go backwards, so the time of the next block is set instead if the time is before it,
+// e.g., if a block was collated since the time was checked with NextBlockTime. The time set is returned.
+func (s *Validator) SetNextBlockTime(ctx context.Context, time uint64) (uint64, error) {
+	if err := s.checkChainControlAllowed(); err != nil {
+		return 0, err
+	}
+
+	s.collateMutex.Lock()
+	defer s.collateMutex.Unlock()
+
+	block, _, err := s.GetLastBlock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	lastBlockId := block.Id.Uint64()
+	time = max(time, lastBlockId+s.timeOffset.Load())
+	s.timeOffset.Store(time - lastBlockId)
+	return time, nil
+}
+
+// SnapshotChain returns the state of the chain of the shard it can be reset to by ResetChain.
+func (s *Validator) SnapshotChain(ctx context.Context) (rawapitypes.ChainSnapshot, error) {
+	if err := s.checkChainControlAllowed(); err != nil {
+		return rawapitypes.ChainSnapshot{}, err
+	}
+
+	s.collateMutex.Lock()
+	defer s.collateMutex.Unlock()
+
+	block, hash, err := s.GetLastBlock(ctx)
+	if err != nil {
+		return rawapitypes.ChainSnapshot{}, err
+	}
+
+	tx, err := s.txFabric.CreateRoTx(ctx)
+	if err != nil {
+		return rawapitypes.ChainSnapshot{}, err
+	}
+	defer tx.Rollback()
+
+	state, err := db.ReadCollatorState(tx, s.params.ShardId)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return rawapitypes.ChainSnapshot{}, err
+	}
+	return rawapitypes.ChainSnapshot{
+		BlockHash:     hash,
+		BlockNumber:   block.Id,
+		CollatorState: state,
+		TimeOffset:    s.timeOffset.Load(),
+	}, nil
+}
+
+// ResetChain makes the block of the snapshot the last one of the shard, dropping the blocks collated after it.
+// The dropped blocks are kept in the database, but neither they are found by number nor their transactions,
+// receipts and errors are found by hash. The transactions of the dropped blocks are not returned to the pool.
+// The subscribers are notified of the reset with the number of the block of the snapshot, see SubscribeBlocks.
+func (s *Validator) ResetChain(ctx context.Context, snapshot rawapitypes.ChainSnapshot) error {
+	if err := s.checkChainControlAllowed(); err != nil {
+		return err
+	}
+
+	s.collateMutex.Lock()
+	defer s.collateMutex.Unlock()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	lastBlock, _, err := s.getLastBlockUnlocked(ctx)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.txFabric.CreateRwTx(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	hash, err := db.ReadBlockHashByNumber(tx, s.params.ShardId, snapshot.BlockNumber)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+	if hash != snapshot.BlockHash {
+		return fmt.Errorf("%w: %s", cerrors.ErrBlockNotInChain, snapshot.BlockHash)
+	}
+	block, err := db.ReadBlock(tx, s.params.ShardId, snapshot.BlockHash)
+	if err != nil {
+		return err
+	}
+
+	for id := snapshot.BlockNumber + 1; id <= lastBlock.Id; id++ {
+		droppedHash, err := db.ReadBlockHashByNumber(tx, s.params.ShardId, id)
+		if err != nil {
+			return err
+		}
+		if err := s.dropBlockTransactions(tx, droppedHash); err != nil {
+			return fmt.Errorf("failed to drop transactions of block %d: %w", id, err)
+		}
+		if err := tx.DeleteFromShard(s.params.ShardId, db.BlockHashByNumberIndex, id.Bytes()); err != nil {
+			return err
+		}
+	}
+	if err := db.WriteLastBlockHash(tx, s.params.ShardId, snapshot.BlockHash); err != nil {
+		return err
+	}
+	if err := db.WriteCollatorState(tx, s.params.ShardId, snapshot.CollatorState); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	s.setLastBlockUnlocked(block, snapshot.BlockHash)
+	s.timeOffset.Store(snapshot.TimeOffset)
+	s.metrics.RecordBlockId(ctx, block.Id)
+	s.notify(&event{resetType, block.Id})
+
+	s.logger.Info().
+		Uint64("fromBlock", uint64(lastBlock.Id)).
+		Uint64("toBlock", uint64(block.Id)).
+		Msg("Chain is reset")
+	return nil
+}
+
+// dropBlockTransactions deletes the transactions of the dropped block from the indexes by hash, so that
+// neither they nor their receipts and errors are found, and they can be included in the blocks again.
+func (s *Validator) dropBlockTransactions(tx db.RwTx, blockHash common.Hash) error {
+	data, err := execution.NewStateAccessor().Access(tx, s.params.ShardId).GetBlock().
+		WithInTransactions().WithOutTransactions().ByHash(blockHash)
+	if err != nil {
+		return err
+	}
+
+	drop := func(txns []*types.Transaction, table db.ShardedTableName) error {
+		for _, txn := range txns {
+			txnHash := txn.Hash()
+			value, err := tx.GetFromShard(s.params.ShardId, table, txnHash.Bytes())
+			if errors.Is(err, db.ErrKeyNotFound) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			var index db.BlockHashAndTransactionIndex
+			if err := index.UnmarshalSSZ(value); err != nil {
+				return err
+			}
+			// The refund transactions can be identical, so the hash may be indexed by a block that is kept.
+			if index.BlockHash != blockHash {
+				continue
+			}
+			if err := tx.DeleteFromShard(s.params.ShardId, table, txnHash.Bytes()); err != nil {
+				return err
+			}
+			if table == db.BlockHashAndInTransactionIndexByTransactionHash {
+				if err := db.DeleteError(tx, txnHash); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := drop(data.InTransactions(), db.BlockHashAndInTransactionIndexByTransactionHash); err != nil {
+		return err
+	}
+	return drop(data.OutTransactions(), db.BlockHashAndOutTransactionIndexByTransactionHash)
+}
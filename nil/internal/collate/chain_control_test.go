@@ -0,0 +1,255 @@
+package collate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	cerrors "github.com/NilFoundation/nil/nil/internal/collate/errors"
+	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	"github.com/stretchr/testify/suite"
+)
+
+type ChainControlTestSuite struct {
+	suite.Suite
+
+	shardId   types.ShardId
+	db        db.DB
+	pool      *MockTxnPool
+	validator *Validator
+}
+
+func (s *ChainControlTestSuite) SetupSuite() {
+	s.shardId = types.BaseShardId
+}
+
+func (s *ChainControlTestSuite) SetupTest() {
+	var err error
+	s.db, err = db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+
+	execution.GenerateZeroState(s.T(), types.MainShardId, s.db)
+	execution.GenerateZeroState(s.T(), s.shardId, s.db)
+
+	s.pool = &MockTxnPool{}
+	s.validator = s.newValidator(true)
+}
+
+func (s *ChainControlTestSuite) TearDownTest() {
+	s.db.Close()
+}
+
+func (s *ChainControlTestSuite) newValidator(disableConsensus bool) *Validator {
+	s.T().Helper()
+
+	params := &Params{
+		BlockGeneratorParams: execution.NewBlockGeneratorParams(s.shardId, 2),
+		Topology:             new(TrivialShardTopology),
+	}
+	params.DisableConsensus = disableConsensus
+	validator, err := NewValidator(params, nil, s.db, s.pool, nil)
+	s.Require().NoError(err)
+	return validator
+}
+
+func (s *ChainControlTestSuite) collateBlock() *types.Block {
+	s.T().Helper()
+
+	blockId, err := s.validator.CollateBlock(s.T().Context())
+	s.Require().NoError(err)
+
+	block, _, err := s.validator.GetLastBlock(s.T().Context())
+	s.Require().NoError(err)
+	s.Require().Equal(blockId, block.Id)
+	return block
+}
+
+func (s *ChainControlTestSuite) findTransaction(hash common.Hash) error {
+	s.T().Helper()
+
+	tx, err := s.db.CreateRoTx(s.T().Context())
+	s.Require().NoError(err)
+	defer tx.Rollback()
+
+	_, err = execution.NewStateAccessor().Access(tx, s.shardId).GetInTransaction().ByHash(hash)
+	return err
+}
+
+func (s *ChainControlTestSuite) receiveBlock(blocks <-chan types.BlockNumber) types.BlockNumber {
+	s.T().Helper()
+
+	select {
+	case blockId := <-blocks:
+		return blockId
+	case <-time.After(5 * time.Second):
+		s.FailNow("no block received")
+		return 0
+	}
+}
+
+func (s *ChainControlTestSuite) TestConsensusEnabled() {
+	validator := s.newValidator(false)
+
+	_, err := validator.CollateBlock(s.T().Context())
+	s.Require().ErrorIs(err, cerrors.ErrConsensusEnabled)
+	_, err = validator.SetNextBlockTime(s.T().Context(), 100)
+	s.Require().ErrorIs(err, cerrors.ErrConsensusEnabled)
+	_, err = validator.SnapshotChain(s.T().Context())
+	s.Require().ErrorIs(err, cerrors.ErrConsensusEnabled)
+}
+
+func (s *ChainControlTestSuite) TestCollateBlock() {
+	lastBlock, _, err := s.validator.GetLastBlock(s.T().Context())
+	s.Require().NoError(err)
+
+	to := contracts.CounterAddress(s.T(), s.shardId)
+	txn := execution.NewSendMoneyTransaction(s.T(), to, 0)
+	s.pool.Add(txn)
+
+	block := s.collateBlock()
+	s.Equal(lastBlock.Id+1, block.Id)
+	s.Require().NoError(s.findTransaction(txn.Hash()))
+
+	s.pool.Reset()
+	s.Equal(block.Id+1, s.collateBlock().Id)
+}
+
+func (s *ChainControlTestSuite) TestNextBlockTime() {
+	ctx := s.T().Context()
+
+	lastBlock, _, err := s.validator.GetLastBlock(ctx)
+	s.Require().NoError(err)
+
+	next, err := s.validator.NextBlockTime(ctx)
+	s.Require().NoError(err)
+	s.Equal(lastBlock.Id.Uint64(), next)
+
+	s.Run("Set", func() {
+		timeSet, err := s.validator.SetNextBlockTime(ctx, next+100)
+		s.Require().NoError(err)
+		s.Equal(next+100, timeSet)
+
+		// The transactions of the blocks are executed with the time shifted by the offset.
+		params := s.validator.blockGeneratorParams(execution.ModeProposal)
+		s.Require().NotNil(params.BlockContextOverride)
+		blockContext := &vm.BlockContext{Time: next}
+		params.BlockContextOverride(blockContext)
+		s.Equal(next+100, blockContext.Time)
+
+		block := s.collateBlock()
+		next, err = s.validator.NextBlockTime(ctx)
+		s.Require().NoError(err)
+		s.Equal(block.Id.Uint64()+100, next)
+	})
+
+	s.Run("KeptByNextBlocks", func() {
+		block := s.collateBlock()
+		next, err := s.validator.NextBlockTime(ctx)
+		s.Require().NoError(err)
+		s.Equal(block.Id.Uint64()+100, next)
+	})
+
+	s.Run("NotBackwards", func() {
+		next, err := s.validator.NextBlockTime(ctx)
+		s.Require().NoError(err)
+
+		timeSet, err := s.validator.SetNextBlockTime(ctx, next-10)
+		s.Require().NoError(err)
+		s.Equal(next, timeSet)
+	})
+
+	s.Run("Replay", func() {
+		block, hash, err := s.validator.GetLastBlock(ctx)
+		s.Require().NoError(err)
+
+		// The proposal of the block is verified with the offset applied the same way as it was collated with.
+		proposal := &execution.Proposal{
+			PrevBlockId:   block.Id - 1,
+			PrevBlockHash: block.PrevBlock,
+			MainShardHash: block.MainShardHash,
+		}
+		replayedHash, err := s.validator.buildBlockHashByProposal(ctx, proposal)
+		s.Require().NoError(err)
+		s.Equal(hash, replayedHash)
+	})
+
+	s.Run("NotWithConsensus", func() {
+		// The offset isn't proposed to the other validators, so it is never applied with the consensus.
+		validator := s.newValidator(false)
+		validator.timeOffset.Store(100)
+		s.Nil(validator.blockGeneratorParams(execution.ModeProposal).BlockContextOverride)
+	})
+}
+
+func (s *ChainControlTestSuite) TestResetChain() {
+	ctx := s.T().Context()
+
+	blocks := s.validator.SubscribeBlocks(ctx)
+
+	snapshot, err := s.validator.SnapshotChain(ctx)
+	s.Require().NoError(err)
+
+	to := contracts.CounterAddress(s.T(), s.shardId)
+	txn := execution.NewSendMoneyTransaction(s.T(), to, 0)
+	s.pool.Add(txn)
+
+	_, err = s.validator.SetNextBlockTime(ctx, snapshot.BlockNumber.Uint64()+100)
+	s.Require().NoError(err)
+	block := s.collateBlock()
+	s.Equal(block.Id, s.receiveBlock(blocks))
+	s.Require().NoError(s.findTransaction(txn.Hash()))
+
+	s.Require().NoError(s.validator.ResetChain(ctx, snapshot))
+
+	s.Run("LastBlock", func() {
+		lastBlock, hash, err := s.validator.GetLastBlock(ctx)
+		s.Require().NoError(err)
+		s.Equal(snapshot.BlockHash, hash)
+		s.Equal(snapshot.BlockNumber, lastBlock.Id)
+
+		next, err := s.validator.NextBlockTime(ctx)
+		s.Require().NoError(err)
+		s.Equal(lastBlock.Id.Uint64(), next)
+	})
+
+	s.Run("Notified", func() {
+		s.Equal(snapshot.BlockNumber, s.receiveBlock(blocks))
+	})
+
+	s.Run("DroppedBlock", func() {
+		tx, err := s.db.CreateRoTx(ctx)
+		s.Require().NoError(err)
+		defer tx.Rollback()
+
+		_, err = db.ReadBlockHashByNumber(tx, s.shardId, block.Id)
+		s.Require().ErrorIs(err, db.ErrKeyNotFound)
+		s.Require().ErrorIs(s.findTransaction(txn.Hash()), db.ErrKeyNotFound)
+	})
+
+	s.Run("IncludedAgain", func() {
+		block := s.collateBlock()
+		s.Equal(snapshot.BlockNumber+1, block.Id)
+		s.Require().NoError(s.findTransaction(txn.Hash()))
+
+		// The offset of the snapshot is restored as well.
+		next, err := s.validator.NextBlockTime(ctx)
+		s.Require().NoError(err)
+		s.Equal(block.Id.Uint64(), next)
+	})
+
+	s.Run("InvalidSnapshot", func() {
+		snapshot.BlockHash = common.EmptyHash
+		err := s.validator.ResetChain(ctx, snapshot)
+		s.Require().ErrorIs(err, cerrors.ErrBlockNotInChain)
+	})
+}
+
+func TestChainControl(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, &ChainControlTestSuite{})
+}
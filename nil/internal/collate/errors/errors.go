@@ -7,4 +7,8 @@ var (
 	ErrOutOfOrder          = errors.New("received block is out of order")
 	ErrHashMismatch        = errors.New("block hash mismatch")
 	ErrInvalidProposedHash = errors.New("invalid prposed hash")
+	ErrConsensusEnabled    = errors.New("blocks are collated by consensus")
+	ErrConsensusDisabled   = errors.New("blocks are not signed without consensus")
+	ErrTimeBeforeNextBlock = errors.New("time is before the time of the next block")
+	ErrBlockNotInChain     = errors.New("block is not in the chain")
 )
@@ -322,7 +322,24 @@ func (p *proposer) handleTransactionsFromPool() error {
 		return true, nil
 	}
 
-	for _, txn := range poolTxns {
+	for i := 0; i < len(poolTxns); i++ {
+		txn := poolTxns[i]
+		if bundle := p.pool.SameBlockBundle(txn.Hash()); len(bundle) > 0 {
+			if !isBundleAt(poolTxns[i:], bundle) {
+				continue
+			}
+			added, dropped, err := p.handleSameBlockBundle(poolTxns[i:i+len(bundle)], handle)
+			if err != nil {
+				return err
+			}
+			unverified = append(unverified, dropped...)
+			if !added && len(dropped) == 0 {
+				break
+			}
+			i += len(bundle) - 1
+			continue
+		}
+
 		if ok, err := handle(txn); err != nil {
 			return err
 		} else if ok {
@@ -350,6 +367,64 @@ func (p *proposer) handleTransactionsFromPool() error {
 	return nil
 }
 
+// isBundleAt reports whether the transactions start with the ones of the bundle in its order.
+// The pool keeps the transactions of a same-block bundle together, the ones that are not are skipped.
+func isBundleAt(txns []*types.TxnWithHash, bundle []common.Hash) bool {
+	if len(txns) < len(bundle) {
+		return false
+	}
+	for i, hash := range bundle {
+		if txns[i].Hash() != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// handleSameBlockBundle adds either all the transactions of the bundle to the proposal or none of them.
+// The bundle is dropped if any of its transactions fails the validation or it doesn't fit into a block at all,
+// the hashes of the transactions to drop are returned, except the failed one, which is dropped by the handler.
+// If it only doesn't fit into the rest of the block, it's left for the next one.
+func (p *proposer) handleSameBlockBundle(
+	txns []*types.TxnWithHash, handle func(*types.TxnWithHash) (bool, error),
+) (added bool, dropped []common.Hash, err error) {
+	checkpoint := p.executionState.Checkpoint()
+	gasUsed := p.executionState.GasUsed
+	for i, txn := range txns {
+		ok, err := handle(txn)
+		if err != nil {
+			return false, nil, err
+		}
+		if !ok {
+			p.logger.Debug().Stringer(logging.FieldTransactionHash, txn.Hash()).
+				Msg("Transaction of same-block bundle failed validation. Dropping the bundle...")
+			p.executionState.RevertToCheckpoint(checkpoint)
+			for j, t := range txns {
+				if j != i {
+					dropped = append(dropped, t.Hash())
+				}
+			}
+			return false, dropped, nil
+		}
+	}
+
+	if p.executionState.GasUsed > p.params.MaxGasInBlock {
+		bundleGas := p.executionState.GasUsed - gasUsed
+		p.executionState.RevertToCheckpoint(checkpoint)
+		if bundleGas > p.params.MaxGasInBlock {
+			for _, t := range txns {
+				dropped = append(dropped, t.Hash())
+			}
+		}
+		return false, dropped, nil
+	}
+
+	for _, txn := range txns {
+		p.proposal.ExternalTxns = append(p.proposal.ExternalTxns, txn.Transaction)
+	}
+	return true, nil, nil
+}
+
 func (p *proposer) handleTransactionsFromNeighbors(tx db.RoTx) error {
 	state, err := db.ReadCollatorState(tx, p.params.ShardId)
 	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
@@ -89,6 +89,60 @@ func (s *ProposerTestSuite) TestBlockGas() {
 	})
 }
 
+func (s *ProposerTestSuite) TestSameBlockBundle() {
+	s.Run("GenerateZeroState", func() {
+		execution.GenerateZeroState(s.T(), types.MainShardId, s.db)
+		execution.GenerateZeroState(s.T(), s.shardId, s.db)
+	})
+
+	to := contracts.CounterAddress(s.T(), s.shardId)
+	m1 := execution.NewSendMoneyTransaction(s.T(), to, 0)
+	m2 := execution.NewSendMoneyTransaction(s.T(), to, 1)
+	pool := &MockTxnPool{}
+	pool.AddSameBlockBundle(m1, m2)
+
+	params := s.newParams()
+	var bundleGas types.Gas
+
+	s.Run("Fits", func() {
+		p := newTestProposer(params, pool)
+
+		proposal := s.generateProposal(p)
+		s.Equal(pool.Txns, proposal.ExternalTxns)
+		s.Empty(pool.LastDiscarded)
+		bundleGas = p.executionState.GasUsed
+	})
+
+	s.Run("DoesNotFitRemainder", func() {
+		m0 := execution.NewSendMoneyTransaction(s.T(), to, 0)
+		m1 := execution.NewSendMoneyTransaction(s.T(), to, 1)
+		m2 := execution.NewSendMoneyTransaction(s.T(), to, 2)
+		pool := &MockTxnPool{}
+		pool.Add(m0)
+		pool.AddSameBlockBundle(m1, m2)
+
+		params := s.newParams()
+		params.MaxGasInBlock = bundleGas
+		p := newTestProposer(params, pool)
+
+		// The bundle is left for the next block, and the state is reverted.
+		proposal := s.generateProposal(p)
+		s.Equal([]*types.Transaction{m0}, proposal.ExternalTxns)
+		s.Empty(pool.LastDiscarded)
+		s.Less(p.executionState.GasUsed, bundleGas)
+	})
+
+	s.Run("DoesNotFit", func() {
+		params.MaxGasInBlock = 2000
+		p := newTestProposer(params, pool)
+
+		proposal := s.generateProposal(p)
+		s.Empty(proposal.ExternalTxns)
+		s.Equal([]common.Hash{m1.Hash(), m2.Hash()}, pool.LastDiscarded)
+		s.Equal(txnpool.Unverified, pool.LastReason)
+	})
+}
+
 func (s *ProposerTestSuite) TestCollator() {
 	to := contracts.CounterAddress(s.T(), s.shardId)
 
@@ -18,6 +18,9 @@ type TxnPool interface {
 	Peek(n int) ([]*types.TxnWithHash, error)
 	Discard(ctx context.Context, txns []common.Hash, reason txnpool.DiscardReason) error
 	OnCommitted(ctx context.Context, baseFee types.Value, committed []*types.Transaction) error
+	// SameBlockBundle returns the hashes of the transactions of the bundle that must be included in the same block
+	// with the transaction, in the order of the bundle. It's empty if the transaction is not in such a bundle.
+	SameBlockBundle(hash common.Hash) []common.Hash
 }
 
 type Consensus interface {
@@ -103,12 +106,8 @@ func (s *Scheduler) Run(ctx context.Context) error {
 
 func (s *Scheduler) doCollate(ctx context.Context) error {
 	if s.params.DisableConsensus {
-		proposal, err := s.validator.BuildProposal(ctx)
-		if err != nil {
-			return err
-		}
-
-		return s.validator.InsertProposal(ctx, proposal, &types.ConsensusParams{})
+		_, err := s.validator.CollateBlock(ctx)
+		return err
 	}
 
 	block, _, err := s.validator.GetLastBlock(ctx)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -18,6 +19,7 @@ import (
 	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/multiformats/go-multistream"
 	"google.golang.org/protobuf/proto"
 )
@@ -39,6 +41,9 @@ type SyncerConfig struct {
 	Timeout         time.Duration // pull blocks if no new blocks appear in the topic for this duration
 	BootstrapPeers  []network.AddrInfo
 	ZeroStateConfig *execution.ZeroStateConfig
+	// FetchSnapshot bootstraps the state of the shards instead of fetching the whole DB from a bootstrap peer,
+	// e.g., from the sync API served by the peers. It is only used by the main shard syncer.
+	FetchSnapshot func(ctx context.Context) error
 }
 
 // every n-th block will be reported to info log (to avoid spamming)
@@ -57,6 +62,10 @@ type Syncer struct {
 	waitForSync *sync.WaitGroup
 
 	validator *Validator
+
+	// phase and highestKnownBlock are reported by SyncProgress.
+	phase             atomic.Uint32
+	highestKnownBlock atomic.Uint64
 }
 
 func NewSyncer(cfg *SyncerConfig, validator *Validator, db db.DB, networkManager network.Manager) (*Syncer, error) {
@@ -88,6 +97,48 @@ func (s *Syncer) shardIsEmpty(ctx context.Context) (bool, error) {
 	return block == nil, nil
 }
 
+func (s *Syncer) Validator() *Validator {
+	return s.validator
+}
+
+// SyncProgress reports the phase of the synchronization, the highest block seen and the peers serving the shard.
+func (s *Syncer) SyncProgress() rawapitypes.SyncProgress {
+	progress := rawapitypes.SyncProgress{
+		Phase:             rawapitypes.SyncPhase(s.phase.Load()),
+		HighestKnownBlock: types.BlockNumber(s.highestKnownBlock.Load()),
+	}
+	if s.networkManager != nil {
+		progress.Peers = uint64(len(s.networkManager.GetPeersForProtocol(protocolShardBlock(s.config.ShardId))))
+	}
+	return progress
+}
+
+func (s *Syncer) setPhase(phase rawapitypes.SyncPhase) {
+	s.phase.Store(uint32(phase))
+}
+
+func (s *Syncer) observeBlock(id types.BlockNumber) {
+	for {
+		highest := s.highestKnownBlock.Load()
+		if uint64(id) <= highest || s.highestKnownBlock.CompareAndSwap(highest, uint64(id)) {
+			return
+		}
+	}
+}
+
+// observeAheadBlock takes into account the block announced ahead of the next one once its signature is verified,
+// so that the lag of the shard is known before the missed blocks are fetched.
+func (s *Syncer) observeAheadBlock(ctx context.Context, block *types.Block) {
+	if err := s.validator.VerifyAheadBlock(ctx, block); err != nil {
+		s.logger.Debug().
+			Err(err).
+			Stringer(logging.FieldBlockNumber, block.Id).
+			Msg("Announced block is not taken into account")
+		return
+	}
+	s.observeBlock(block.Id)
+}
+
 func (s *Syncer) WaitComplete(ctx context.Context) error {
 	c := make(chan struct{}, 1)
 	go func() {
@@ -151,6 +202,13 @@ func (s *Syncer) fetchRemoteVersion(ctx context.Context) (NodeVersion, error) {
 }
 
 func (s *Syncer) fetchSnapshot(ctx context.Context) error {
+	s.setPhase(rawapitypes.SyncPhaseDownloading)
+	defer s.setPhase(rawapitypes.SyncPhaseNone)
+
+	if s.config.FetchSnapshot != nil {
+		return s.config.FetchSnapshot(ctx)
+	}
+
 	var err error
 	for _, peer := range s.config.BootstrapPeers {
 		err = fetchSnapshot(ctx, s.networkManager, peer, s.db, s.logger)
@@ -310,6 +368,7 @@ func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool
 		switch {
 		case errors.Is(err, cerrors.ErrOutOfOrder):
 			// todo: queue the block for later processing
+			s.observeAheadBlock(ctx, block)
 			return false, nil
 		case errors.Is(err, cerrors.ErrOldBlock):
 			return false, nil
@@ -322,6 +381,9 @@ func (s *Syncer) processTopicTransaction(ctx context.Context, data []byte) (bool
 }
 
 func (s *Syncer) fetchBlocks(ctx context.Context) {
+	s.setPhase(rawapitypes.SyncPhaseReplaying)
+	defer s.setPhase(rawapitypes.SyncPhaseFollowing)
+
 	// todo: fetch blocks until the queue (see todo above) is empty
 	for {
 		s.logger.Trace().Msg("Fetching next blocks")
@@ -396,6 +458,9 @@ func (s *Syncer) saveBlock(ctx context.Context, block *types.BlockWithExtractedD
 	if err := s.validator.ReplayBlock(ctx, block); err != nil {
 		return err
 	}
+	// Only the validated blocks are taken into account, so that a peer can't make the node look lagging,
+	// see observeAheadBlock.
+	s.observeBlock(block.Id)
 
 	if uint64(block.Id)%uint64(blockReportInterval) == 0 {
 		s.logger.Info().
@@ -15,6 +15,8 @@ type MockTxnPool struct {
 	Txns     []*types.Transaction
 	MetaTxns []*types.TxnWithHash
 
+	Bundles [][]common.Hash
+
 	LastDiscarded []common.Hash
 	LastReason    txnpool.DiscardReason
 }
@@ -24,6 +26,7 @@ var _ TxnPool = (*MockTxnPool)(nil)
 func (m *MockTxnPool) Reset() {
 	m.Txns = m.Txns[:0]
 	m.MetaTxns = m.MetaTxns[:0]
+	m.Bundles = nil
 	m.LastDiscarded = nil
 	m.LastReason = 0
 }
@@ -45,6 +48,26 @@ func (m *MockTxnPool) OnCommitted(context.Context, types.Value, []*types.Transac
 	return nil
 }
 
+func (m *MockTxnPool) SameBlockBundle(hash common.Hash) []common.Hash {
+	for _, bundle := range m.Bundles {
+		if slices.Contains(bundle, hash) {
+			return bundle
+		}
+	}
+	return nil
+}
+
+// AddSameBlockBundle adds the transactions as a bundle to be included in the same block.
+func (m *MockTxnPool) AddSameBlockBundle(txns ...*types.Transaction) {
+	m.Add(txns...)
+
+	bundle := make([]common.Hash, len(txns))
+	for i, txn := range txns {
+		bundle[i] = txn.Hash()
+	}
+	m.Bundles = append(m.Bundles, bundle)
+}
+
 func (m *MockTxnPool) Add(txns ...*types.Transaction) {
 	m.Txns = append(m.Txns, txns...)
 
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -41,6 +42,8 @@ const (
 	commitType eventType = iota
 	/* Block inserted by syncer. */
 	replayType
+	/* Chain reset to the block by the controls of the chain, see ResetChain. */
+	resetType
 )
 
 type event struct {
@@ -62,6 +65,11 @@ type Validator struct {
 	lastBlockHash common.Hash     // +checklocks:mutex
 	metrics       *MetricsHandler // +checklocks:mutex
 
+	// collateMutex serializes the collation without the consensus with the controls of the chain, see CollateBlock.
+	collateMutex sync.Mutex
+	// timeOffset is added to the time the blocks are collated with, see SetNextBlockTime.
+	timeOffset atomic.Uint64
+
 	subsMutex sync.Mutex
 	subsId    uint64                 // +checklocks:subsMutex
 	subs      map[uint64]chan *event // +checklocks:subsMutex
@@ -174,8 +182,7 @@ func (s *Validator) buildBlockHashByProposal(ctx context.Context, proposal *exec
 		return common.EmptyHash, err
 	}
 
-	params := s.params.BlockGeneratorParams
-	params.ExecutionMode = execution.ModeVerify
+	params := s.blockGeneratorParams(execution.ModeVerify)
 	gen, err := execution.NewBlockGenerator(ctx, params, s.txFabric, prevBlock)
 	if err != nil {
 		return common.EmptyHash, fmt.Errorf("failed to create block generator: %w", err)
@@ -249,8 +256,7 @@ func (s *Validator) insertProposalUnlocked(
 		return err
 	}
 
-	params := s.params.BlockGeneratorParams
-	params.ExecutionMode = execution.ModeProposal
+	params := s.blockGeneratorParams(execution.ModeProposal)
 	gen, err := execution.NewBlockGenerator(ctx, params, s.txFabric, prevBlock)
 	if err != nil {
 		return fmt.Errorf("failed to create block generator: %w", err)
@@ -401,6 +407,24 @@ func (s *Validator) validateProposal(ctx context.Context, proposal *execution.Pr
 	return s.validateProposalUnlocked(ctx, proposal)
 }
 
+// VerifyAheadBlock verifies the signature of a block received ahead of the next block of the shard
+// by the validators of the next block, e.g., to know how far the shard is behind. So the blocks signed after
+// a change of the validators are not verified. The blocks are not signed if the consensus is disabled.
+func (s *Validator) VerifyAheadBlock(ctx context.Context, block *types.Block) error {
+	if s.params.DisableConsensus {
+		return cerrors.ErrConsensusDisabled
+	}
+	lastBlock, _, err := s.GetLastBlock(ctx)
+	if err != nil {
+		return err
+	}
+	params, err := config.GetConfigParams(ctx, s.txFabric, s.params.ShardId, lastBlock.Id.Uint64()+1)
+	if err != nil {
+		return err
+	}
+	return block.VerifySignature(params.PublicKeys.Keys(), s.params.ShardId)
+}
+
 func (s *Validator) ReplayBlock(ctx context.Context, block *types.BlockWithExtractedData) error {
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
@@ -515,6 +539,39 @@ func (s *Validator) Subscribe() (uint64, <-chan *event) {
 	return id, ch
 }
 
+// SubscribeBlocks returns a channel receiving the numbers of the blocks committed to the shard until ctx is done,
+// e.g., to serve the subscriptions of the API. Some numbers are skipped if the subscriber lags behind,
+// so it should read all the blocks up to the received one. A number before the ones received earlier
+// means that the chain is reset to the block, see ResetChain, so the blocks after it are replaced.
+func (s *Validator) SubscribeBlocks(ctx context.Context) <-chan types.BlockNumber {
+	subId, subChan := s.Subscribe()
+	blocks := make(chan types.BlockNumber, 1)
+	go func() {
+		defer s.Unsubscribe(subId)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-subChan:
+				blockNumber := ev.blockNumber
+				if ev.evType == resetType {
+					// The reset must not be skipped, it replaces the number not received yet, if any.
+					select {
+					case pending := <-blocks:
+						blockNumber = min(blockNumber, pending)
+					default:
+					}
+				}
+				select {
+				case blocks <- blockNumber:
+				default:
+				}
+			}
+		}
+	}()
+	return blocks
+}
+
 func (s *Validator) Unsubscribe(id uint64) {
 	s.subsMutex.Lock()
 	defer s.subsMutex.Unlock()
@@ -531,6 +588,22 @@ func (s *Validator) notify(ev *event) {
 		select {
 		case ch <- ev:
 		default:
+			if ev.evType != resetType {
+				continue
+			}
+			// The resets are never skipped, they replace the event not received yet instead, see SubscribeBlocks.
+			reset := ev
+			select {
+			case pending := <-ch:
+				if pending.evType == resetType && pending.blockNumber < reset.blockNumber {
+					reset = pending
+				}
+			default:
+			}
+			select {
+			case ch <- reset:
+			default:
+			}
 		}
 	}
 }
@@ -170,8 +170,8 @@ func (i *backendIBFT) InsertProposal(proposal *protoIBFT.Proposal, committedSeal
 		return // error is logged in buildSignature
 	}
 
-	prevProposerIndex := i.getPrevProposer(height)
-	_, proposerIndex, err := i.calcProposer(height, proposal.GetRound(), prevProposerIndex)
+	prevProposerIndex := i.getPrevProposer(i.ctx, height)
+	_, proposerIndex, err := i.calcProposer(i.ctx, height, proposal.GetRound(), prevProposerIndex)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to calculate current proposer")
 		return
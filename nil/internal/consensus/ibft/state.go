@@ -0,0 +1,51 @@
+package ibft
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// ConsensusState returns the state of the sequence the shard runs with the proposer of its current round.
+func (i *backendIBFT) ConsensusState(ctx context.Context) (rawapitypes.ConsensusState, error) {
+	status := i.consensus.Status()
+	state := rawapitypes.ConsensusState{
+		Height:           types.BlockNumber(status.Height),
+		Round:            status.Round,
+		Step:             status.State,
+		ProposalHash:     status.ProposalHash,
+		Prepares:         status.Prepares,
+		Commits:          status.Commits,
+		RoundChangeRound: status.RoundChangeRound,
+		RoundChanges:     status.RoundChanges,
+	}
+	// No sequence is started yet, the blocks are committed from the first one.
+	if status.Height == 0 {
+		return state, nil
+	}
+
+	proposer, proposerIndex, err := i.calcProposer(
+		ctx, status.Height, status.Round, i.getPrevProposer(ctx, status.Height))
+	if err != nil {
+		return rawapitypes.ConsensusState{}, err
+	}
+	state.ProposerIndex = proposerIndex
+	state.ProposerPublicKey = proposer.PublicKey[:]
+	return state, nil
+}
+
+// RoundTimeouts returns the latest expirations of the rounds of the shard, the oldest first.
+func (i *backendIBFT) RoundTimeouts() []rawapitypes.RoundTimeout {
+	timeouts := i.consensus.RecentTimeouts()
+	result := make([]rawapitypes.RoundTimeout, len(timeouts))
+	for index, timeout := range timeouts {
+		result[index] = rawapitypes.RoundTimeout{
+			Height: types.BlockNumber(timeout.Height),
+			Round:  timeout.Round,
+			Step:   timeout.State,
+			Time:   timeout.Time,
+		}
+	}
+	return result
+}
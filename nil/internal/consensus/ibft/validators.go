@@ -1,12 +1,16 @@
 package ibft
 
 import (
+	"context"
+
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/config"
 )
 
-func (i *backendIBFT) calcProposer(height, round uint64, prevValidator *uint64) (*config.ValidatorInfo, uint64, error) {
-	params, err := config.GetConfigParams(i.ctx, i.txFabric, i.shardId, height)
+func (i *backendIBFT) calcProposer(
+	ctx context.Context, height, round uint64, prevValidator *uint64,
+) (*config.ValidatorInfo, uint64, error) {
+	params, err := config.GetConfigParams(ctx, i.txFabric, i.shardId, height)
 	if err != nil {
 		i.logger.Error().
 			Err(err).
@@ -2,6 +2,7 @@ package ibft
 
 import (
 	"bytes"
+	"context"
 	"errors"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
@@ -92,14 +93,14 @@ func (i *backendIBFT) IsValidValidator(msg *protoIBFT.IbftMessage) bool {
 	return true
 }
 
-func (i *backendIBFT) getPrevProposer(height uint64) *uint64 {
+func (i *backendIBFT) getPrevProposer(ctx context.Context, height uint64) *uint64 {
 	// It doesn't make sense for 0 block
 	// For the first block we should start from the first validator (offset = 0)
 	if height < 2 {
 		return nil
 	}
 
-	block, _, err := i.validator.GetLastBlock(i.ctx)
+	block, _, err := i.validator.GetLastBlock(ctx)
 	if err != nil {
 		return nil
 	}
@@ -108,8 +109,8 @@ func (i *backendIBFT) getPrevProposer(height uint64) *uint64 {
 }
 
 func (i *backendIBFT) IsProposer(id []byte, height, round uint64) bool {
-	prevProposerIndex := i.getPrevProposer(height)
-	proposer, _, err := i.calcProposer(height, round, prevProposerIndex)
+	prevProposerIndex := i.getPrevProposer(i.ctx, height)
+	proposer, _, err := i.calcProposer(i.ctx, height, round, prevProposerIndex)
 	if err != nil {
 		i.logger.Error().
 			Err(err).
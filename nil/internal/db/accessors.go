@@ -163,6 +163,10 @@ func ReadError(tx RoTx, txnHash common.Hash) (string, error) {
 	return string(res), nil
 }
 
+func DeleteError(tx RwTx, txnHash common.Hash) error {
+	return tx.Delete(errorByTransactionHashTable, txnHash.Bytes())
+}
+
 func WriteCode(tx RwTx, shardId types.ShardId, hash common.Hash, code types.Code) error {
 	return tx.PutToShard(shardId, codeTable, hash.Bytes(), code[:])
 }
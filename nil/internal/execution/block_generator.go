@@ -13,6 +13,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
 )
 
 type BlockGeneratorParams struct {
@@ -23,6 +24,8 @@ type BlockGeneratorParams struct {
 	DisableConsensus bool
 	FeeCalculator    FeeCalculator
 	ExecutionMode    string
+	// BlockContextOverride modifies the block context the transactions of the block are executed in, if set
+	BlockContextOverride func(blockContext *vm.BlockContext)
 }
 
 func NewBlockGeneratorParams(shardId types.ShardId, nShards uint32) BlockGeneratorParams {
@@ -91,6 +94,7 @@ func NewBlockGenerator(
 		return nil, err
 	}
 	executionState.EvmTracingHooks = params.EvmTracingHooks
+	executionState.BlockContextOverride = params.BlockContextOverride
 
 	return NewBlockGeneratorWithEs(ctx, params, txFabric, rwTx, executionState)
 }
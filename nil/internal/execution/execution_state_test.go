@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"testing"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -332,6 +333,39 @@ func TestSnapshotEmpty(t *testing.T) {
 	s.RevertToSnapshot(s.Snapshot())
 }
 
+func TestCheckpoint(t *testing.T) {
+	t.Parallel()
+	s := newState(t)
+	defer s.tx.Rollback()
+	addr := types.GenerateRandomAddress(types.BaseShardId)
+
+	s.AddInTransaction(types.NewEmptyTransaction())
+	s.AddReceipt(NewExecutionResult())
+	hashes := slices.Clone(s.InTransactionHashes)
+	receipts := len(s.Receipts)
+	gasUsed := s.GasUsed
+	checkpoint := s.Checkpoint()
+
+	txn := types.NewEmptyTransaction()
+	txn.Seqno = 1
+	s.AddInTransaction(txn)
+	require.NoError(t, s.SetBalance(addr, types.NewValueFromUint64(42)))
+	s.AddReceipt(NewExecutionResult().SetError(types.NewError(types.ErrorExecution)))
+	s.GasUsed += 100
+
+	s.RevertToCheckpoint(checkpoint)
+	assert.Len(t, s.InTransactions, len(hashes))
+	assert.Equal(t, hashes, s.InTransactionHashes)
+	assert.Equal(t, hashes[len(hashes)-1], s.InTransactionHash)
+	assert.Len(t, s.Receipts, receipts)
+	assert.Empty(t, s.Errors)
+	assert.Equal(t, gasUsed, s.GasUsed)
+
+	exists, err := s.Exists(addr)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
 func TestCreateObjectRevert(t *testing.T) {
 	t.Parallel()
 	state := newState(t)
@@ -122,6 +122,8 @@ type ExecutionState struct {
 
 	// Tracing hooks set for every EVM created during execution
 	EvmTracingHooks *tracing.Hooks
+	// BlockContextOverride modifies the block context of every EVM created during execution, if set
+	BlockContextOverride func(blockContext *vm.BlockContext)
 
 	shardAccessor *shardAccessor
 
@@ -561,6 +563,43 @@ func (es *ExecutionState) RevertToSnapshot(revid int) {
 	es.validRevisions = es.validRevisions[:idx]
 }
 
+// Checkpoint identifies the state before a group of inbound transactions that must be handled altogether.
+type Checkpoint struct {
+	revision          int
+	inTransactions    int
+	inTransactionHash common.Hash
+	receipts          int
+	gasUsed           types.Gas
+}
+
+// Checkpoint returns a checkpoint of the state including the inbound transactions handled so far.
+func (es *ExecutionState) Checkpoint() Checkpoint {
+	return Checkpoint{
+		revision:          es.Snapshot(),
+		inTransactions:    len(es.InTransactions),
+		inTransactionHash: es.InTransactionHash,
+		receipts:          len(es.Receipts),
+		gasUsed:           es.GasUsed,
+	}
+}
+
+// RevertToCheckpoint drops the inbound transactions added since the checkpoint along with their effects.
+// The state changes, logs and outbound transactions are reverted with the journal.
+func (es *ExecutionState) RevertToCheckpoint(checkpoint Checkpoint) {
+	es.RevertToSnapshot(checkpoint.revision)
+
+	for _, hash := range es.InTransactionHashes[checkpoint.inTransactions:] {
+		delete(es.Errors, hash)
+		delete(es.DebugLogs, hash)
+		delete(es.OutTransactions, hash)
+	}
+	es.InTransactions = es.InTransactions[:checkpoint.inTransactions]
+	es.InTransactionHashes = es.InTransactionHashes[:checkpoint.inTransactions]
+	es.InTransactionHash = checkpoint.inTransactionHash
+	es.Receipts = es.Receipts[:checkpoint.receipts]
+	es.GasUsed = checkpoint.gasUsed
+}
+
 func (es *ExecutionState) GetStorageRoot(addr types.Address) (common.Hash, error) {
 	acc, err := es.GetAccount(addr)
 	if err != nil || acc == nil {
@@ -1913,6 +1952,9 @@ func (es *ExecutionState) newVm(internal bool, origin types.Address) error {
 	if err != nil {
 		return err
 	}
+	if es.BlockContextOverride != nil {
+		es.BlockContextOverride(blockContext)
+	}
 	es.evm = vm.NewEVM(blockContext, es, origin, es.GasPrice)
 	es.evm.IsAsyncCall = internal
 
@@ -57,6 +57,11 @@ func NewStateAccessor() *StateAccessor {
 	}
 }
 
+// Purge drops all the cached data and returns the number of the dropped entries.
+func (s *StateAccessor) Purge() int {
+	return s.cache.purge() + s.rawCache.purge()
+}
+
 func (s *StateAccessor) Access(tx db.RoTx, shardId types.ShardId) *shardAccessor {
 	return &shardAccessor{s.RawAccess(tx, shardId)}
 }
@@ -103,6 +108,15 @@ func newAccessorCache(
 	}
 }
 
+func (c *accessorCache) purge() int {
+	n := c.blocksLRU.Len() + c.inTransactionsLRU.Len() + c.outTransactionsLRU.Len() + c.receiptsLRU.Len()
+	c.blocksLRU.Purge()
+	c.inTransactionsLRU.Purge()
+	c.outTransactionsLRU.Purge()
+	c.receiptsLRU.Purge()
+	return n
+}
+
 type rawAccessorCache struct {
 	blocksLRU          *lru.Cache[common.Hash, []byte]
 	inTransactionsLRU  *lru.Cache[common.Hash, [][]byte]
@@ -146,6 +160,19 @@ func newRawAccessorCache(
 	}
 }
 
+func (c *rawAccessorCache) purge() int {
+	n := 0
+	for _, cache := range []*lru.Cache[common.Hash, [][]byte]{
+		c.inTransactionsLRU, c.inTxCountsLRU, c.outTransactionsLRU, c.outTxCountsLRU, c.receiptsLRU,
+	} {
+		n += cache.Len()
+		cache.Purge()
+	}
+	n += c.blocksLRU.Len()
+	c.blocksLRU.Purge()
+	return n
+}
+
 type shardAccessor struct {
 	*rawShardAccessor
 }
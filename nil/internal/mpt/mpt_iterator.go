@@ -1,44 +1,71 @@
 package mpt
 
 import (
+	"bytes"
 	"iter"
 )
 
 func (m *Reader) Iterate() iter.Seq2[[]byte, []byte] {
-	type Yield = func([]byte, []byte) bool
-	return func(yield Yield) {
-		var iter func(ref Reference, path *Path)
-		iter = func(ref Reference, path *Path) {
+	return m.IterateFrom(nil)
+}
+
+// IterateFrom iterates over the entries with the keys not less than start in the order of the keys.
+// The subtrees with all the keys less than start are skipped, so the iteration can be resumed cheaply.
+func (m *Reader) IterateFrom(start []byte) iter.Seq2[[]byte, []byte] {
+	startPath := newPath(start, false)
+	return func(yield func([]byte, []byte) bool) {
+		// iter returns false once the iteration is stopped by the caller
+		var iter func(ref Reference, path *Path) bool
+		iter = func(ref Reference, path *Path) bool {
+			if comparePrefix(path, startPath) < 0 {
+				return true
+			}
 			node, err := m.getNode(ref)
 			if err != nil {
-				return
+				return true
 			}
 			npath := node.Path()
 			if npath != nil {
 				path = path.Combine(npath)
 			}
+			if comparePrefix(path, startPath) < 0 {
+				return true
+			}
 			data := node.Data()
-			if len(data) > 0 {
-				// note: even though we access path.Data directly here is ok
-				// cause every key in the mpt is []byte, i.e. it consists of even number of nibbles
+			// note: even though we access path.Data directly here is ok
+			// cause every key in the mpt is []byte, i.e. it consists of even number of nibbles
+			if len(data) > 0 && bytes.Compare(path.Data, start) >= 0 {
 				if !yield(path.Data, data) {
-					return
+					return false
 				}
 			}
 			switch node := node.(type) {
 			case *BranchNode:
 				for i, br := range node.Branches {
-					if len(br) > 0 {
-						iter(br, path.Combine(newPath([]byte{byte(i)}, true)))
+					if len(br) > 0 && !iter(br, path.Combine(newPath([]byte{byte(i)}, true))) {
+						return false
 					}
 				}
-				return
 			case *ExtensionNode:
-				iter(node.NextRef, path)
+				return iter(node.NextRef, path)
 			}
+			return true
 		}
 		if m.root.IsValid() {
 			iter(m.root, newPath(nil, false))
 		}
 	}
 }
+
+// comparePrefix compares the nibbles of the paths up to the length of the shorter one.
+func comparePrefix(path, other *Path) int {
+	for i := range min(path.Size(), other.Size()) {
+		if a, b := path.At(i), other.At(i); a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
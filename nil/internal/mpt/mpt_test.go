@@ -123,6 +123,35 @@ func TestIterate(t *testing.T) {
 	require.Len(t, keys, i)
 }
 
+func TestIterateFrom(t *testing.T) {
+	t.Parallel()
+
+	trie := mpt.NewInMemMPT()
+	keys := [][]byte{[]byte("do"), []byte("dog"), []byte("doge"), []byte("horse")}
+	for _, key := range keys {
+		require.NoError(t, trie.Set(key, key))
+	}
+
+	collect := func(start []byte, limit int) [][]byte {
+		var res [][]byte
+		for k, v := range trie.IterateFrom(start) {
+			require.Equal(t, k, v)
+			if len(res) == limit {
+				break
+			}
+			res = append(res, k)
+		}
+		return res
+	}
+
+	require.Equal(t, keys, collect(nil, len(keys)))
+	require.Equal(t, keys[1:], collect([]byte("dog"), len(keys)))
+	require.Equal(t, keys[2:], collect([]byte("doga"), len(keys)))
+	require.Equal(t, keys[3:], collect([]byte("e"), len(keys)))
+	require.Empty(t, collect([]byte("z"), len(keys)))
+	require.Equal(t, keys[1:3], collect([]byte("dog"), 2))
+}
+
 func TestInsertGetLots(t *testing.T) {
 	t.Parallel()
 
@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network/internal"
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
@@ -24,7 +26,8 @@ type BasicManager struct {
 	pubSub *PubSub
 	dht    *DHT
 
-	meter telemetry.Meter
+	meter   telemetry.Meter
+	metrics *internal.MetricsReporter
 
 	logger logging.Logger
 }
@@ -58,6 +61,7 @@ func newManagerFromHost(
 	ctx context.Context,
 	conf *Config,
 	h host.Host,
+	metrics *internal.MetricsReporter,
 	database db.DB,
 	logger logging.Logger,
 ) (*BasicManager, error) {
@@ -83,6 +87,7 @@ func newManagerFromHost(
 		pubSub:          ps,
 		dht:             dht,
 		meter:           telemetry.NewMeter("github.com/NilFoundation/nil/nil/internal/network"),
+		metrics:         metrics,
 		logger:          logger,
 	}, nil
 }
@@ -108,19 +113,19 @@ func NewManager(ctx context.Context, conf *Config, database db.DB) (*BasicManage
 		conf.PrivateKey = privateKey
 	}
 
-	h, logger, err := newHost(ctx, conf)
+	h, metrics, logger, err := newHost(ctx, conf)
 	if err != nil {
 		return nil, err
 	}
-	return newManagerFromHost(ctx, conf, h, database, logger)
+	return newManagerFromHost(ctx, conf, h, metrics, database, logger)
 }
 
 func NewClientManager(ctx context.Context, conf *Config, database db.DB) (*BasicManager, error) {
-	h, logger, err := newClient(ctx, conf)
+	h, metrics, logger, err := newClient(ctx, conf)
 	if err != nil {
 		return nil, err
 	}
-	return newManagerFromHost(ctx, conf, h, database, logger)
+	return newManagerFromHost(ctx, conf, h, metrics, database, logger)
 }
 
 func (m *BasicManager) ID() peer.ID {
@@ -177,6 +182,119 @@ func (m *BasicManager) Connect(ctx context.Context, addr AddrInfo) (PeerID, erro
 	return addr.ID, nil
 }
 
+func (m *BasicManager) Disconnect(peer PeerID) (int, error) {
+	m.logger.Debug().Msgf("Disconnecting from %s", peer)
+
+	conns := len(m.host.Network().ConnsToPeer(peer))
+	if err := m.host.Network().ClosePeer(peer); err != nil {
+		return 0, err
+	}
+	return conns, nil
+}
+
+func (m *BasicManager) AddPeer(ctx context.Context, addr AddrInfo) (int, error) {
+	m.logger.Debug().Msgf("Adding peer %s", addr)
+
+	m.host.Peerstore().AddAddrs(addr.ID, addr.Addrs, peerstore.PermanentAddrTTL)
+	if err := m.host.Connect(ctx, peer.AddrInfo(addr)); err != nil {
+		return 0, err
+	}
+	if m.dht != nil {
+		if _, err := m.dht.RoutingTable().TryAddPeer(addr.ID, true, false); err != nil {
+			// The peer is connected anyway, e.g., the routing table rejects the peers not serving the DHT.
+			m.logger.Warn().Err(err).Msgf("Failed to add %s to the DHT routing table", addr.ID)
+		}
+	}
+	return len(m.host.Network().ConnsToPeer(addr.ID)), nil
+}
+
+func (m *BasicManager) RemovePeer(peer PeerID) (int, error) {
+	m.logger.Debug().Msgf("Removing peer %s", peer)
+
+	conns, err := m.Disconnect(peer)
+	if err != nil {
+		return 0, err
+	}
+	// The peer is forgotten once it is disconnected, since the peerstore keeps the data of the connected peers.
+	if m.dht != nil {
+		m.dht.RoutingTable().RemovePeer(peer)
+	}
+	m.host.Peerstore().ClearAddrs(peer)
+	m.host.Peerstore().RemovePeer(peer)
+	return conns, nil
+}
+
+func (m *BasicManager) ProtectPeer(peer PeerID, tag string, protect bool) bool {
+	if protect {
+		m.host.ConnManager().Protect(peer, tag)
+		return true
+	}
+	return m.host.ConnManager().Unprotect(peer, tag)
+}
+
+func (m *BasicManager) SetPeerTag(peer PeerID, tag string, value int) int {
+	var previous int
+	if info := m.host.ConnManager().GetTagInfo(peer); info != nil {
+		previous = info.Tags[tag]
+	}
+	if value == 0 {
+		m.host.ConnManager().UntagPeer(peer, tag)
+	} else {
+		m.host.ConnManager().TagPeer(peer, tag, value)
+	}
+	return previous
+}
+
+func (m *BasicManager) ConnectedPeers() []PeerID {
+	return m.host.Network().Peers()
+}
+
+func (m *BasicManager) GetPeerInfo(peer PeerID) (PeerInfo, error) {
+	conns := m.host.Network().ConnsToPeer(peer)
+	if len(conns) == 0 {
+		return PeerInfo{}, fmt.Errorf("%w: %s", ErrPeerNotConnected, peer)
+	}
+
+	info := PeerInfo{
+		Id:          peer,
+		Connections: uint64(len(conns)),
+		Streams:     make(map[ProtocolID]uint64),
+		Bandwidth:   newBandwidthStats(m.metrics.GetBandwidthForPeer(peer)),
+	}
+	for _, addr := range m.host.Peerstore().Addrs(peer) {
+		info.Addrs = append(info.Addrs, addr.String())
+	}
+	// The version is unknown until the peer is identified.
+	info.ProtocolVersion, _ = m.GetPeerProtocolVersion(peer)
+	for _, conn := range conns {
+		for _, stream := range conn.GetStreams() {
+			info.Streams[m.withoutNetworkPrefix(stream.Protocol())]++
+		}
+	}
+	return info, nil
+}
+
+func (m *BasicManager) GetBandwidthByProtocol() map[ProtocolID]BandwidthStats {
+	byProtocol := m.metrics.GetBandwidthByProtocol()
+	result := make(map[ProtocolID]BandwidthStats, len(byProtocol))
+	for protocol, stats := range byProtocol {
+		result[m.withoutNetworkPrefix(protocol)] = newBandwidthStats(stats)
+	}
+	return result
+}
+
+// withoutNetworkPrefix returns the protocol the way it is passed to the manager,
+// the protocols of libp2p itself are returned as they are.
+func (m *BasicManager) withoutNetworkPrefix(protocol ProtocolID) ProtocolID {
+	if m.prefix == "" {
+		return protocol
+	}
+	if trimmed, ok := strings.CutPrefix(string(protocol), m.prefix); ok {
+		return ProtocolID(trimmed)
+	}
+	return protocol
+}
+
 func (m *BasicManager) Close() {
 	if m.dht != nil {
 		if err := m.dht.Close(); err != nil {
@@ -14,6 +14,8 @@ type reputationChangeReason string
 
 const (
 	ReputationChangeInvalidBlockSignature = reputationChangeReason("invalid block signature")
+	// ReputationChangeRawApiQuotaExceeded is reported for the peers using the raw API of the node above their quotas.
+	ReputationChangeRawApiQuotaExceeded = reputationChangeReason("raw API quota exceeded")
 )
 
 type ReputationChangeSettings = map[reputationChangeReason]Reputation
@@ -21,6 +23,7 @@ type ReputationChangeSettings = map[reputationChangeReason]Reputation
 func DefaultReputationChangeSettings() ReputationChangeSettings {
 	return ReputationChangeSettings{
 		ReputationChangeInvalidBlockSignature: -100,
+		ReputationChangeRawApiQuotaExceeded:   -50,
 	}
 }
 
@@ -13,4 +13,7 @@ var (
 	ErrPublicKeyMismatch = errors.New("public key does not match the private key")
 	// ErrIdentityMismatch is returned when the identity does not match the public key.
 	ErrIdentityMismatch = errors.New("identity does not match the public key")
+
+	// ErrPeerNotConnected is returned when the node has no open connections to the peer.
+	ErrPeerNotConnected = errors.New("peer is not connected")
 )
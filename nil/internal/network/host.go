@@ -23,10 +23,12 @@ type Host = host.Host
 
 var defaultGracePeriod = connmgr.WithGracePeriod(time.Minute)
 
-func getCommonOptions(ctx context.Context, conf *Config) ([]libp2p.Option, logging.Logger, error) {
+func getCommonOptions(
+	ctx context.Context, conf *Config,
+) ([]libp2p.Option, *internal.MetricsReporter, logging.Logger, error) {
 	pid, err := peer.IDFromPublicKey(conf.PrivateKey.GetPublic())
 	if err != nil {
-		return nil, logging.Nop(), err
+		return nil, nil, logging.Nop(), err
 	}
 
 	logger := internal.Logger.With().
@@ -44,12 +46,12 @@ func getCommonOptions(ctx context.Context, conf *Config) ([]libp2p.Option, loggi
 		400, // hi
 		defaultGracePeriod)
 	if err != nil {
-		return nil, logger, err
+		return nil, nil, logger, err
 	}
 
 	metrics, err := internal.NewMetricsReporter(ctx, pid)
 	if err != nil {
-		return nil, logger, err
+		return nil, nil, logger, err
 	}
 
 	return []libp2p.Option{
@@ -58,19 +60,19 @@ func getCommonOptions(ctx context.Context, conf *Config) ([]libp2p.Option, loggi
 		libp2p.ConnectionManager(cm),
 		libp2p.Identity(conf.PrivateKey),
 		libp2p.BandwidthReporter(metrics),
-	}, logger, nil
+	}, metrics, logger, nil
 }
 
 // newHost creates a new libp2p host. It must be closed after use.
-func newHost(ctx context.Context, conf *Config) (Host, logging.Logger, error) {
+func newHost(ctx context.Context, conf *Config) (Host, *internal.MetricsReporter, logging.Logger, error) {
 	addr := conf.IPV4Address
 	if addr == "" {
 		addr = "0.0.0.0"
 	}
 
-	options, logger, err := getCommonOptions(ctx, conf)
+	options, metrics, logger, err := getCommonOptions(ctx, conf)
 	if err != nil {
-		return nil, logging.Nop(), err
+		return nil, nil, logging.Nop(), err
 	}
 
 	if conf.TcpPort != 0 {
@@ -119,13 +121,13 @@ func newHost(ctx context.Context, conf *Config) (Host, logging.Logger, error) {
 
 	host, err := libp2p.New(options...)
 	if err != nil {
-		return nil, logging.Nop(), err
+		return nil, nil, logging.Nop(), err
 	}
-	return host, logger, nil
+	return host, metrics, logger, nil
 }
 
 // newClient creates a new libp2p host that doesn't listen to any port. It must be closed after use.
-func newClient(ctx context.Context, conf *Config) (Host, logging.Logger, error) {
+func newClient(ctx context.Context, conf *Config) (Host, *internal.MetricsReporter, logging.Logger, error) {
 	var privateKey libp2pcrypto.PrivKey
 	if conf != nil && conf.PrivateKey != nil {
 		privateKey = conf.PrivateKey
@@ -134,18 +136,18 @@ func newClient(ctx context.Context, conf *Config) (Host, logging.Logger, error)
 		var err error
 		privateKey, err = GeneratePrivateKey()
 		if err != nil {
-			return nil, logging.Nop(), err
+			return nil, nil, logging.Nop(), err
 		}
 	}
 
-	options, logger, err := getCommonOptions(ctx, &Config{PrivateKey: privateKey})
+	options, metrics, logger, err := getCommonOptions(ctx, &Config{PrivateKey: privateKey})
 	if err != nil {
-		return nil, logging.Nop(), err
+		return nil, nil, logging.Nop(), err
 	}
 	options = append(options, libp2p.NoListenAddrs)
 	host, err := libp2p.New(options...)
 	if err != nil {
-		return nil, logging.Nop(), err
+		return nil, nil, logging.Nop(), err
 	}
-	return host, logger, nil
+	return host, metrics, logger, nil
 }
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"context"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/internal/telemetry"
 	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
@@ -10,9 +11,20 @@ import (
 	"github.com/libp2p/go-libp2p/core/protocol"
 )
 
+const (
+	// bandwidthTrimInterval is the interval of dropping the traffic of the idle peers and protocols.
+	bandwidthTrimInterval = 10 * time.Minute
+	// bandwidthIdleTime is the time without traffic after which the peer or the protocol is idle.
+	bandwidthIdleTime = time.Hour
+)
+
 var _ metrics.Reporter = (*MetricsReporter)(nil)
 
+// MetricsReporter exports the traffic of the host to the telemetry and keeps its totals and rates
+// for the introspection of the node.
 type MetricsReporter struct {
+	*metrics.BandwidthCounter
+
 	ctx context.Context
 	id  peer.ID
 
@@ -31,23 +43,45 @@ func NewMetricsReporter(ctx context.Context, id peer.ID) (*MetricsReporter, erro
 	if err != nil {
 		return nil, err
 	}
-	return &MetricsReporter{
-		ctx:      ctx,
-		id:       id,
-		sentSize: sentSize,
-		recvSize: recvSize,
-	}, nil
+	reporter := &MetricsReporter{
+		BandwidthCounter: metrics.NewBandwidthCounter(),
+		ctx:              ctx,
+		id:               id,
+		sentSize:         sentSize,
+		recvSize:         recvSize,
+	}
+	go reporter.trimIdle(ctx)
+	return reporter, nil
 }
 
-func (s *MetricsReporter) LogSentMessage(int64) {
-	// handled by LogSentMessageStream
+// trimIdle drops the traffic of the idle peers and protocols until ctx is done, so that the counter
+// doesn't keep the traffic of every peer the node has ever been connected to.
+func (s *MetricsReporter) trimIdle(ctx context.Context) {
+	ticker := time.NewTicker(bandwidthTrimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.TrimIdle(now.Add(-bandwidthIdleTime))
+		}
+	}
 }
 
-func (s *MetricsReporter) LogRecvMessage(int64) {
-	// handled by LogRecvMessageStream
+func (s *MetricsReporter) LogSentMessage(size int64) {
+	// exported by LogSentMessageStream
+	s.BandwidthCounter.LogSentMessage(size)
+}
+
+func (s *MetricsReporter) LogRecvMessage(size int64) {
+	// exported by LogRecvMessageStream
+	s.BandwidthCounter.LogRecvMessage(size)
 }
 
 func (s *MetricsReporter) LogSentMessageStream(size int64, protocol protocol.ID, peer peer.ID) {
+	s.BandwidthCounter.LogSentMessageStream(size, protocol, peer)
 	s.sentSize.Add(s.ctx, size, telattr.With(
 		telattr.P2PIdentity(s.id),
 		telattr.PeerId(peer),
@@ -56,29 +90,10 @@ func (s *MetricsReporter) LogSentMessageStream(size int64, protocol protocol.ID,
 }
 
 func (s *MetricsReporter) LogRecvMessageStream(size int64, protocol protocol.ID, peer peer.ID) {
+	s.BandwidthCounter.LogRecvMessageStream(size, protocol, peer)
 	s.recvSize.Add(s.ctx, size, telattr.With(
 		telattr.P2PIdentity(s.id),
 		telattr.PeerId(peer),
 		telattr.ProtocolId(protocol),
 	))
 }
-
-func (s *MetricsReporter) GetBandwidthForPeer(peer.ID) metrics.Stats {
-	panic("not implemented")
-}
-
-func (s *MetricsReporter) GetBandwidthForProtocol(protocol.ID) metrics.Stats {
-	panic("not implemented")
-}
-
-func (s *MetricsReporter) GetBandwidthTotals() metrics.Stats {
-	panic("not implemented")
-}
-
-func (s *MetricsReporter) GetBandwidthByPeer() map[peer.ID]metrics.Stats {
-	panic("not implemented")
-}
-
-func (s *MetricsReporter) GetBandwidthByProtocol() map[protocol.ID]metrics.Stats {
-	panic("not implemented")
-}
@@ -12,12 +12,43 @@ type Manager interface {
 	AllKnownPeers() []PeerID
 	GetPeersForProtocol(pid ProtocolID) []PeerID
 	Connect(ctx context.Context, addr AddrInfo) (PeerID, error)
+	// Disconnect closes the connections to the peer and returns their number.
+	Disconnect(peer PeerID) (int, error)
+	// AddPeer remembers the addresses of the peer permanently, adds it to the routing table of the DHT
+	// and connects to it. It returns the number of the open connections to the peer.
+	AddPeer(ctx context.Context, addr AddrInfo) (int, error)
+	// RemovePeer forgets the addresses of the peer, removes it from the routing table of the DHT
+	// and closes the connections to it. It returns the number of the closed connections.
+	RemovePeer(peer PeerID) (int, error)
+	// ProtectPeer protects the connections to the peer from being trimmed under the tag or removes the protection.
+	// It returns whether the peer stays protected under any tag.
+	ProtectPeer(peer PeerID, tag string, protect bool) bool
+	// SetPeerTag sets the value of the tag the connections are trimmed by, the peers with the lowest values first.
+	// Zero removes the tag. It returns the previous value.
+	SetPeerTag(peer PeerID, tag string, value int) int
+	// ConnectedPeers returns the peers the node has open connections to.
+	ConnectedPeers() []PeerID
+	// GetPeerInfo returns the connections of the node to the peer, ErrPeerNotConnected if there are none.
+	GetPeerInfo(peer PeerID) (PeerInfo, error)
+	// GetBandwidthByProtocol returns the traffic of the node by protocol since it started.
+	GetBandwidthByProtocol() map[ProtocolID]BandwidthStats
 	Close()
 
 	NewStream(ctx context.Context, peerId PeerID, protocolId ProtocolID) (Stream, error)
 	SetStreamHandler(ctx context.Context, protocolId ProtocolID, handler StreamHandler)
 	SetRequestHandler(ctx context.Context, protocolId ProtocolID, handler RequestHandler)
+	// RemoveStreamHandler removes the handler set by SetStreamHandler or SetRequestHandler.
+	// The streams being handled are not affected.
+	RemoveStreamHandler(protocolId ProtocolID)
 	SendRequestAndGetResponse(ctx context.Context, peerId PeerID, protocolId ProtocolID, request []byte) ([]byte, error)
 
 	getHost() Host
 }
+
+// NoHost is embedded by the implementations of Manager outside the package that are not backed by a libp2p host,
+// e.g., the in-process ones of the tests. The functions of the package requiring the host treat them as having none.
+type NoHost struct{}
+
+func (NoHost) getHost() Host {
+	return nil
+}
@@ -111,6 +111,70 @@ func (s *ManagerSuite) TestReqResp() {
 	})
 }
 
+func (s *ManagerSuite) TestPeerInfo() {
+	m1 := s.newManager()
+	defer m1.Close()
+	m2 := s.newManager()
+	defer m2.Close()
+
+	_, id2 := ConnectManagers(s.T(), m1, m2)
+	const protocol = "test-info"
+	m2.SetRequestHandler(s.context, protocol, func(context.Context, []byte) ([]byte, error) {
+		return []byte("world"), nil
+	})
+	_, err := m1.SendRequestAndGetResponse(s.context, id2, protocol, []byte("hello"))
+	s.Require().NoError(err)
+
+	s.Require().Contains(m1.ConnectedPeers(), id2)
+	info, err := m1.GetPeerInfo(id2)
+	s.Require().NoError(err)
+	s.Require().Equal(id2, info.Id)
+	s.Require().Positive(info.Connections)
+	s.Require().NotEmpty(info.Addrs)
+
+	// The totals of the traffic are updated in the background.
+	s.Require().Eventually(func() bool {
+		info, err := m1.GetPeerInfo(id2)
+		s.Require().NoError(err)
+		stats := m1.GetBandwidthByProtocol()[protocol]
+		return info.Bandwidth.TotalOut > 0 && stats.TotalOut > 0 && stats.TotalIn > 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	_, err = m1.GetPeerInfo(m1.ID())
+	s.Require().ErrorIs(err, ErrPeerNotConnected)
+}
+
+func (s *ManagerSuite) TestPeerControl() {
+	m1 := s.newManager()
+	defer m1.Close()
+	m2 := s.newManager()
+	defer m2.Close()
+
+	id2 := m2.ID()
+	s.Run("AddPeer", func() {
+		conns, err := m1.AddPeer(s.context, CalcAddress(m2))
+		s.Require().NoError(err)
+		s.Require().Positive(conns)
+		s.Require().Contains(m1.ConnectedPeers(), id2)
+		WaitForPeer(s.T(), m2, m1.ID())
+	})
+
+	s.Run("ProtectPeer", func() {
+		s.Require().True(m1.ProtectPeer(id2, "test", true))
+		s.Require().True(m1.host.ConnManager().IsProtected(id2, "test"))
+		s.Require().False(m1.ProtectPeer(id2, "test", false))
+		s.Require().False(m1.host.ConnManager().IsProtected(id2, "test"))
+	})
+
+	s.Run("RemovePeer", func() {
+		conns, err := m1.RemovePeer(id2)
+		s.Require().NoError(err)
+		s.Require().Positive(conns)
+		s.Require().NotContains(m1.ConnectedPeers(), id2)
+		s.Require().Empty(m1.host.Peerstore().Addrs(id2))
+	})
+}
+
 type ConnectionManagerCheckParams struct {
 	halfDecayTimeSeconds int
 	forgetAfterTime      time.Duration
@@ -0,0 +1,37 @@
+package network
+
+import (
+	"github.com/libp2p/go-libp2p/core/metrics"
+)
+
+// BandwidthStats is the traffic of the node in bytes, the rates are per second.
+type BandwidthStats struct {
+	TotalIn  uint64
+	TotalOut uint64
+	RateIn   float64
+	RateOut  float64
+}
+
+func newBandwidthStats(stats metrics.Stats) BandwidthStats {
+	return BandwidthStats{
+		TotalIn:  uint64(stats.TotalIn),
+		TotalOut: uint64(stats.TotalOut),
+		RateIn:   stats.RateIn,
+		RateOut:  stats.RateOut,
+	}
+}
+
+// PeerInfo describes the connections of the node to a peer.
+type PeerInfo struct {
+	Id PeerID
+	// Addrs are the known addresses of the peer.
+	Addrs []string
+	// ProtocolVersion is empty if the peer is not identified yet.
+	ProtocolVersion string
+	Connections     uint64
+	// Streams is the number of the open streams to the peer by protocol.
+	Streams map[ProtocolID]uint64
+	// Bandwidth is the traffic between the node and the peer since the node started. It is dropped
+	// once the peer is idle for an hour.
+	Bandwidth BandwidthStats
+}
@@ -3,5 +3,9 @@ package network
 import cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
 
 func TryGetPeerReputationTracker(manager Manager) cm.PeerReputationTracker {
-	return cm.TryGetPeerReputationTracker(manager.getHost())
+	host := manager.getHost()
+	if host == nil {
+		return nil
+	}
+	return cm.TryGetPeerReputationTracker(host)
 }
@@ -34,8 +34,11 @@ type SubscriptionCounters struct {
 }
 
 type PubSubMessage struct {
-	Data         []byte
+	Data []byte
+	// ReceivedFrom is the peer the message was delivered by, it is not the author for the relayed messages.
 	ReceivedFrom PeerID
+	// From is the peer that published the message.
+	From PeerID
 }
 
 type Subscription struct {
@@ -226,7 +229,7 @@ func (s *Subscription) Start(ctx context.Context, skipSelfMessages bool) <-chan
 			s.receivedSize.Add(ctx, int64(len(msg.Data)), attrs)
 			s.logger.Trace().Msg("Received message")
 
-			msgCh <- PubSubMessage{msg.Data, msg.ReceivedFrom}
+			msgCh <- PubSubMessage{Data: msg.Data, ReceivedFrom: msg.ReceivedFrom, From: msg.GetFrom()}
 		}
 
 		close(msgCh)
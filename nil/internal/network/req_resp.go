@@ -83,6 +83,15 @@ func (m *BasicManager) SetStreamHandler(ctx context.Context, protocolId Protocol
 	})
 }
 
+func (m *BasicManager) RemoveStreamHandler(protocolId ProtocolID) {
+	protocolId = ProtocolID(m.withNetworkPrefix(string(protocolId)))
+	m.logger.Debug().
+		Str(logging.FieldProtocolID, string(protocolId)).
+		Msg("Removing stream handler")
+
+	m.host.RemoveStreamHandler(protocolId)
+}
+
 func (m *BasicManager) SendRequestAndGetResponse(
 	ctx context.Context,
 	peerId PeerID,
@@ -109,12 +118,26 @@ func (m *BasicManager) SendRequestAndGetResponse(
 	return io.ReadAll(stream)
 }
 
+type requestPeerKey struct{}
+
+// RequestPeer returns the peer that sent the request being handled by a RequestHandler.
+func RequestPeer(ctx context.Context) (PeerID, bool) {
+	peerId, ok := ctx.Value(requestPeerKey{}).(PeerID)
+	return peerId, ok
+}
+
+// WithRequestPeer sets the peer returned by RequestPeer, e.g., by the managers not backed by libp2p hosts.
+func WithRequestPeer(ctx context.Context, peerId PeerID) context.Context {
+	return context.WithValue(ctx, requestPeerKey{}, peerId)
+}
+
 func (m *BasicManager) SetRequestHandler(ctx context.Context, protocolId ProtocolID, handler RequestHandler) {
 	logger := m.logger.With().Str(logging.FieldProtocolID, m.withNetworkPrefix(string(protocolId))).Logger()
 
 	m.SetStreamHandler(ctx, protocolId, func(stream Stream) {
 		ctx, cancel := context.WithTimeout(ctx, responseTimeout)
 		defer cancel()
+		ctx = WithRequestPeer(ctx, stream.Conn().RemotePeer())
 
 		logger.Trace().Msgf("Handling request %s...", stream.ID())
 
@@ -7,12 +7,15 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/telemetry/internal"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
 	Config = internal.Config
 
 	Meter = metric.Meter
+
+	Tracer = trace.Tracer
 )
 
 func NewDefaultConfig() *Config {
@@ -38,6 +41,11 @@ func NewMeter(name string) Meter {
 	return otel.Meter(name)
 }
 
+// NewTracer returns the tracer of the global provider, the spans are dropped unless the provider is set.
+func NewTracer(name string) Tracer {
+	return otel.Tracer(name)
+}
+
 func Int64Gauge(meter metric.Meter, name string) metric.Int64Gauge {
 	res, err := meter.Int64Gauge(name)
 	check.PanicIfErr(err)
@@ -55,3 +63,9 @@ func Int64UpDownCounter(meter metric.Meter, name string) metric.Int64UpDownCount
 	check.PanicIfErr(err)
 	return res
 }
+
+func Int64Histogram(meter metric.Meter, name string) metric.Int64Histogram {
+	res, err := meter.Int64Histogram(name)
+	check.PanicIfErr(err)
+	return res
+}
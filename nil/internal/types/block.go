@@ -19,6 +19,15 @@ func (bn BlockNumber) Uint64() uint64 {
 	return uint64(bn)
 }
 
+// AddSaturating returns the block number n blocks after bn, InvalidBlockNumber if it overflows.
+// It is used to compute the ends of the requested ranges of blocks.
+func (bn BlockNumber) AddSaturating(n uint64) BlockNumber {
+	if n > uint64(InvalidBlockNumber-bn) {
+		return InvalidBlockNumber
+	}
+	return bn + BlockNumber(n)
+}
+
 func (bn BlockNumber) String() string { return strconv.FormatUint(bn.Uint64(), 10) }
 func (bn BlockNumber) Bytes() []byte  { return []byte(bn.String()) }
 func (bn BlockNumber) Type() string   { return "BlockNumber" }
@@ -17,6 +17,15 @@ func TestInvalidBlockNumber(t *testing.T) {
 	}
 }
 
+func TestBlockNumberAddSaturating(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, BlockNumber(5), BlockNumber(2).AddSaturating(3))
+	require.Equal(t, InvalidBlockNumber, (InvalidBlockNumber - 1).AddSaturating(1))
+	require.Equal(t, InvalidBlockNumber, (InvalidBlockNumber - 1).AddSaturating(2))
+	require.Equal(t, InvalidBlockNumber, BlockNumber(1).AddSaturating(uint64(InvalidBlockNumber)))
+}
+
 func TestBlock_SignAndVerifySignature(t *testing.T) {
 	t.Parallel()
 
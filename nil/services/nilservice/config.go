@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"slices"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/internal/collate"
@@ -20,6 +21,7 @@ import (
 	"github.com/NilFoundation/nil/nil/services/cometa"
 	"github.com/NilFoundation/nil/nil/services/indexer"
 	"github.com/NilFoundation/nil/nil/services/rollup"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 )
 
 type RunMode int
@@ -45,6 +47,86 @@ type Config struct {
 	RPCPort        int                   `yaml:"rpcPort,omitempty"`
 	BootstrapPeers network.AddrInfoSlice `yaml:"bootstrapPeers,omitempty"`
 	EnableDevApi   bool                  `yaml:"enableDevApi,omitempty"`
+	EnableDebugApi bool                  `yaml:"enableDebugApi,omitempty"`
+	// SnapshotBySyncApi bootstraps the state of the shards at the latest block of the peers by the raw sync API
+	// instead of fetching the whole DB from a bootstrap peer
+	SnapshotBySyncApi bool `yaml:"snapshotBySyncApi,omitempty"`
+	// EnableWebsocket serves the websocket connections on the RPC endpoint, e.g., for eth_subscribe
+	EnableWebsocket bool `yaml:"enableWebsocket,omitempty"`
+	// WebsocketSubscriptionLimit limits the subscriptions of a websocket connection, the default is used if zero
+	WebsocketSubscriptionLimit int `yaml:"websocketSubscriptionLimit,omitempty"`
+	// EthCompatRPCPort serves the eth_* methods with the signatures of Ethereum for the Ethereum tooling,
+	// disabled if zero
+	EthCompatRPCPort int `yaml:"ethCompatRpcPort,omitempty"`
+	// EthCompatDefaultShard is the shard of the Ethereum-compatible methods taking neither an address nor a hash,
+	// e.g., eth_blockNumber
+	EthCompatDefaultShard types.ShardId `yaml:"ethCompatDefaultShard,omitempty"`
+	// RestPort serves the read-only REST gateway with its OpenAPI specification, disabled if zero
+	RestPort int `yaml:"restPort,omitempty"`
+	// EnableGraphQL serves the GraphQL queries resolving the links between the entities across shards
+	// at /graphql of the REST gateway
+	EnableGraphQL bool `yaml:"enableGraphQL,omitempty"`
+	// EnableSyncApi serves the snapshots of the state of the shards to the other nodes
+	EnableSyncApi bool `yaml:"enableSyncApi,omitempty"`
+	// EnableTxpoolApi serves the content of the transaction pools of the active shards to the other nodes
+	EnableTxpoolApi bool `yaml:"enableTxpoolApi,omitempty"`
+	// EnableClusterApi serves the queries about all the shards of the network to the other nodes
+	EnableClusterApi bool `yaml:"enableClusterApi,omitempty"`
+	// EnableProofApi serves the zk proofs of the main shard blocks provided by StateProofSource to the other nodes,
+	// the proofs are reported as not found without the source
+	EnableProofApi bool `yaml:"enableProofApi,omitempty"`
+	// StateRetentionBlocks limits the state served by the raw API of a non-archive node to the latest blocks,
+	// the state of all the blocks is served if zero
+	StateRetentionBlocks uint64 `yaml:"stateRetentionBlocks,omitempty"`
+	// RejectInvalidTransactions makes the raw API reject the sent transactions that fail the checks
+	// of the block generator, otherwise they are added to the pool and fail in a block
+	RejectInvalidTransactions bool `yaml:"rejectInvalidTransactions,omitempty"`
+	// RawFaucetApi serves the faucet of the devnet to the other nodes with the limits of the amounts,
+	// it is only served together with the dev API
+	RawFaucetApi *rawapi.FaucetApiConfig `yaml:"rawFaucetApi,omitempty"`
+	// RawDevnetApi serves the controls of the chains of the single-node devnet to the authenticated operators,
+	// it is only served together with the dev API without the consensus
+	RawDevnetApi *rawapi.DevnetApiConfig `yaml:"rawDevnetApi,omitempty"`
+	// RawApiRateLimits limits the raw API requests served to the other nodes by protocol ID or method name
+	RawApiRateLimits rawapi.RateLimits `yaml:"rawApiRateLimits,omitempty"`
+	// RawApiRequestSizeLimits limits the size of the raw API requests in bytes, e.g., "SendTransaction: 131072"
+	RawApiRequestSizeLimits rawapi.RequestSizeLimits `yaml:"rawApiRequestSizeLimits,omitempty"`
+	// RawApiTimeouts limits the time the raw API requests of the methods are handled, e.g., "Call: 3s"
+	RawApiTimeouts rawapi.MethodTimeouts `yaml:"rawApiTimeouts,omitempty"`
+	// RawApiWorkerPool serves the raw API requests on a bounded pool of workers by the priorities of the methods,
+	// so that, e.g., the traces can't starve the block headers. The requests are served by the network if nil
+	RawApiWorkerPool *rawapi.WorkerPoolConfig `yaml:"rawApiWorkerPool,omitempty"`
+	// RawApiLoadShedding rejects the low-priority raw API requests of the methods whose latency or number
+	// of the requests being handled crosses the thresholds, nothing is shed if nil
+	RawApiLoadShedding *rawapi.LoadSheddingConfig `yaml:"rawApiLoadShedding,omitempty"`
+	// RawApiPeerAcl restricts the raw API methods to the listed peers by protocol ID or method name,
+	// e.g., "SendTransaction" to the relays. It can be replaced at runtime with the admin API
+	RawApiPeerAcl rawapi.PeerAcl `yaml:"rawApiPeerAcl,omitempty"`
+	// RawApiPeerQuota accounts the raw API usage by peer and bans the peers exceeding the quota for a while,
+	// the usage is exposed by the admin API. The usage is not accounted if nil
+	RawApiPeerQuota *rawapi.PeerQuotaConfig `yaml:"rawApiPeerQuota,omitempty"`
+	// RawApiRequestLog configures the sampling of the logged raw API requests and the watchdog of the slow ones
+	RawApiRequestLog rawapi.RequestLogConfig `yaml:"rawApiRequestLog,omitempty"`
+	// RawApiDrainTimeout is the time the raw API requests being handled are waited for on shutdown
+	RawApiDrainTimeout time.Duration `yaml:"rawApiDrainTimeout,omitempty"`
+	// RawApiCompressionThreshold is the minimal size of the compressed raw API responses, negative disables compression
+	RawApiCompressionThreshold int `yaml:"rawApiCompressionThreshold,omitempty"`
+	// RawApiResponseCache caches the raw API responses of the immutable data, e.g., of the blocks by hash,
+	// both by the served handlers and by the network clients of an RPC node, disabled if the size is zero
+	RawApiResponseCache rawapi.ResponseCacheConfig `yaml:"rawApiResponseCache,omitempty"`
+	// RawApiDisableDeduplication handles the identical concurrent raw API requests of the read-only APIs separately
+	RawApiDisableDeduplication bool `yaml:"rawApiDisableDeduplication,omitempty"`
+	// RawApiCachingClient makes an RPC node coalesce the raw API requests to the read-only APIs of the shards
+	// and cache the responses of the immutable data and of the latest blocks locally, disabled if nil
+	RawApiCachingClient *rawapi.CachingClientConfig `yaml:"rawApiCachingClient,omitempty"`
+	// RawApiJsonCodec also serves the raw API methods in JSON by the protocol IDs with the "/json" suffix for debugging
+	RawApiJsonCodec bool `yaml:"rawApiJsonCodec,omitempty"`
+	// RawApiRecordPath is the file the handled raw API requests are recorded to for replaying, none if empty
+	RawApiRecordPath string `yaml:"rawApiRecordPath,omitempty"`
+	// RawApiRecordMaxSize is the size of the record file the recording stops at, 1 GiB if 0
+	RawApiRecordMaxSize int64 `yaml:"rawApiRecordMaxSize,omitempty"`
+	// RawApiGrpc serves the raw API methods over gRPC to the clients outside of the p2p network, disabled if nil
+	RawApiGrpc *rawapi.GrpcGatewayConfig `yaml:"rawApiGrpc,omitempty"`
 
 	// Profiling
 	PprofPort int `yaml:"pprofPort,omitempty"`
@@ -52,6 +134,13 @@ type Config struct {
 	// Admin
 	AdminSocketPath string `yaml:"adminSocket,omitempty"`
 	AllowDbDrop     bool   `yaml:"allowDbDrop,omitempty"`
+	// RawAdminApi serves the admin API to the authenticated operators over the raw API
+	RawAdminApi *rawapi.AdminApiConfig `yaml:"rawAdminApi,omitempty"`
+	// RawDbApi serves the raw reads of the database to the authenticated operators over the raw API
+	RawDbApi *rawapi.DbApiConfig `yaml:"rawDbApi,omitempty"`
+	// RawConsensusApi serves the state of the consensus of the shards to the authenticated operators
+	// over the raw API, e.g., to debug the stuck rounds
+	RawConsensusApi *rawapi.ConsensusApiConfig `yaml:"rawConsensusApi,omitempty"`
 
 	// RPC events log
 	LogClientRpcEvents bool `yaml:"logClientRpcEvents,omitempty"`
@@ -86,6 +175,8 @@ type Config struct {
 	RpcNode   *RpcNodeConfig             `yaml:"rpcNode,omitempty"`
 
 	L1Fetcher rollup.L1BlockFetcher `yaml:"-"`
+	// StateProofSource provides the proofs served by the proof API, e.g., by the prover running along with the node
+	StateProofSource rawapi.StateProofSource `yaml:"-"`
 
 	FeeCalculator         execution.FeeCalculator                                                   `yaml:"-"`
 	NetworkManagerFactory func(ctx context.Context, cfg *Config, db db.DB) (network.Manager, error) `yaml:"-"`
@@ -94,6 +185,8 @@ type Config struct {
 const (
 	DefaultNShards   types.ShardId = 5
 	DefaultPprofPort uint32        = 6060
+
+	DefaultRawApiDrainTimeout = 5 * time.Second
 )
 
 func NewDefaultConfig() *Config {
@@ -115,6 +208,8 @@ func NewDefaultConfig() *Config {
 		Replay:    NewDefaultReplayConfig(),
 		RpcNode:   NewDefaultRpcNodeConfig(),
 		PprofPort: int(DefaultPprofPort),
+
+		RawApiDrainTimeout: DefaultRawApiDrainTimeout,
 	}
 }
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"sync"
@@ -29,9 +30,12 @@ import (
 	"github.com/NilFoundation/nil/nil/services/indexer/driver"
 	"github.com/NilFoundation/nil/nil/services/rollup"
 	"github.com/NilFoundation/nil/nil/services/rpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/graphql"
 	"github.com/NilFoundation/nil/nil/services/rpc/httpcfg"
 	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rest"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
 	dht "github.com/libp2p/go-libp2p-kad-dht"
@@ -57,12 +61,14 @@ func startRpcServer(
 	}
 
 	httpConfig := &httpcfg.HttpCfg{
-		HttpURL:         addr,
-		HttpCompression: true,
-		TraceRequests:   true,
-		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
-		HttpCORSDomain:  []string{"*"},
-		KeepHeaders:     []string{"Client-Version", "Client-Type", "X-UID"},
+		HttpURL:                    addr,
+		HttpCompression:            true,
+		WebsocketEnabled:           cfg.EnableWebsocket,
+		WebsocketSubscriptionLimit: cfg.WebsocketSubscriptionLimit,
+		TraceRequests:              true,
+		HTTPTimeouts:               httpcfg.DefaultHTTPTimeouts,
+		HttpCORSDomain:             []string{"*"},
+		KeepHeaders:                []string{"Client-Version", "Client-Type", "X-UID"},
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
@@ -173,6 +179,50 @@ func startRpcServer(
 	return rpc.StartRpcServer(ctx, httpConfig, apiList, logger, nil)
 }
 
+// startEthCompatRpcServer serves the Ethereum-compatible eth_* methods on a separate port,
+// since they have the same names as the methods of the main RPC server.
+func startEthCompatRpcServer(ctx context.Context, cfg *Config, rawApi rawapi.NodeApi, db db.ReadOnlyDB) error {
+	logger := logging.NewLogger("eth-compat-RPC").With().
+		Int(logging.FieldRpcPort, cfg.EthCompatRPCPort).
+		Logger()
+
+	httpConfig := &httpcfg.HttpCfg{
+		HttpURL:         fmt.Sprintf("tcp://127.0.0.1:%d", cfg.EthCompatRPCPort),
+		HttpCompression: true,
+		TraceRequests:   true,
+		HTTPTimeouts:    httpcfg.DefaultHTTPTimeouts,
+		HttpCORSDomain:  []string{"*"},
+	}
+
+	// The logs are read with the raw API, so the blocks are not polled for the filters.
+	var ethImpl *jsonrpc.EthCompatAPIImpl
+	if cfg.RunMode == NormalRunMode || cfg.RunMode == RpcRunMode {
+		impl := jsonrpc.NewEthAPI(ctx, rawApi, db, false, cfg.LogClientRpcEvents)
+		defer impl.Shutdown()
+		ethImpl = jsonrpc.NewEthCompatAPI(impl.APIImplRo, impl, cfg.EthCompatDefaultShard)
+	} else {
+		impl := jsonrpc.NewEthAPIRo(ctx, rawApi, db, false, cfg.LogClientRpcEvents)
+		defer impl.Shutdown()
+		ethImpl = jsonrpc.NewEthCompatAPI(impl, nil, cfg.EthCompatDefaultShard)
+	}
+
+	apiList := []transport.API{
+		{
+			Namespace: "eth",
+			Public:    true,
+			Service:   jsonrpc.EthCompatAPI(ethImpl),
+			Version:   "1.0",
+		},
+		{
+			Namespace: "web3",
+			Public:    true,
+			Service:   jsonrpc.Web3API(jsonrpc.NewWeb3API(rawApi)),
+			Version:   "1.0",
+		},
+	}
+	return rpc.StartRpcServer(ctx, httpConfig, apiList, logger, nil)
+}
+
 func startAdminServer(ctx context.Context, cfg *Config) error {
 	return admin.StartAdminServer(ctx,
 		&admin.ServerConfig{
@@ -184,42 +234,126 @@ func startAdminServer(ctx context.Context, cfg *Config) error {
 
 const defaultCollatorTickPeriodMs = 2000
 
+// shardPeerDirectoryRefreshInterval is the interval the RPC nodes query the peers for the shards they serve,
+// the changes announced by the peers are applied at once.
+const shardPeerDirectoryRefreshInterval = time.Minute
+
 // used to access started service from outside of `Run` call
 type ServiceInterop struct {
 	TxnPools map[types.ShardId]txnpool.Pool
 }
 
 func getRawApi(
+	ctx context.Context,
 	cfg *Config,
 	networkManager network.Manager,
 	database db.DB,
 	txnPools map[types.ShardId]txnpool.Pool,
+	syncers []*collate.Syncer,
+	consensuses map[types.ShardId]rawapi.ShardConsensus,
+	accessControl *rawapi.AccessControl,
+	peerQuotas *rawapi.PeerQuotas,
+	peerDirectory *rawapi.ShardPeerDirectory,
 ) rawapi.NodeApi {
 	nodeApiBuilder := rawapi.NodeApiBuilder(database, networkManager)
+	for i, syncer := range syncers {
+		nodeApiBuilder.
+			WithSyncProgress(types.ShardId(i), syncer).
+			WithNewBlocks(types.ShardId(i), syncer.Validator())
+	}
 
 	switch cfg.RunMode {
 	case RpcRunMode:
+		nodeApiBuilder.
+			WithShardPeerDirectory(peerDirectory).
+			WithResponseCache(cfg.RawApiResponseCache)
 		for shardId := range types.ShardId(cfg.NShards) {
+			if cfg.RawApiCachingClient != nil {
+				nodeApiBuilder.WithCachingNetworkShardApiClientRo(ctx, shardId, *cfg.RawApiCachingClient)
+			} else {
+				nodeApiBuilder.WithNetworkShardApiClientRo(shardId)
+			}
 			nodeApiBuilder.
-				WithNetworkShardApiClientRo(shardId).
 				WithNetworkShardApiClientRw(shardId).
 				WithNetworkShardApiClientDev(shardId)
+			if cfg.EnableDebugApi {
+				nodeApiBuilder.WithNetworkShardApiClientDebug(shardId)
+			}
+			if cfg.EnableTxpoolApi {
+				nodeApiBuilder.WithNetworkShardApiClientTxpool(shardId)
+			}
 		}
 
 	case ArchiveRunMode:
 		for shardId := range types.ShardId(cfg.NShards) {
 			nodeApiBuilder.WithLocalShardApiRo(shardId)
+			if cfg.EnableSyncApi {
+				nodeApiBuilder.WithLocalShardApiSync(shardId)
+			}
+		}
+		if cfg.EnableClusterApi {
+			nodeApiBuilder.WithLocalClusterApi()
+		}
+		if cfg.EnableProofApi {
+			nodeApiBuilder.WithLocalProofApi(cfg.StateProofSource)
+		}
+		if cfg.RawAdminApi != nil {
+			nodeApiBuilder.WithLocalAdminApi(*cfg.RawAdminApi, accessControl, peerQuotas)
+		}
+		if cfg.RawDbApi != nil {
+			nodeApiBuilder.WithLocalDbApi(*cfg.RawDbApi)
 		}
 
 	case NormalRunMode:
+		// Only the archive nodes are expected to serve the state of all the blocks.
+		nodeApiBuilder.WithPrunedState(cfg.StateRetentionBlocks)
+		if cfg.RejectInvalidTransactions {
+			nodeApiBuilder.WithTransactionPreValidation()
+		}
 		for shardId := range types.ShardId(cfg.NShards) {
 			nodeApiBuilder.WithLocalShardApiRo(shardId)
 			if cfg.IsShardActive(shardId) {
 				nodeApiBuilder.WithLocalShardApiRw(shardId, txnPools[shardId])
+				if cfg.EnableTxpoolApi {
+					nodeApiBuilder.WithLocalShardApiTxpool(shardId, txnPools[shardId])
+				}
 			}
 			if cfg.EnableDevApi {
 				nodeApiBuilder.WithLocalShardApiDev(shardId)
 			}
+			if cfg.EnableDebugApi {
+				nodeApiBuilder.WithLocalShardApiDebug(shardId)
+			}
+			if cfg.EnableSyncApi {
+				nodeApiBuilder.WithLocalShardApiSync(shardId)
+			}
+		}
+		if cfg.EnableClusterApi {
+			nodeApiBuilder.WithLocalClusterApi()
+		}
+		if cfg.EnableProofApi {
+			nodeApiBuilder.WithLocalProofApi(cfg.StateProofSource)
+		}
+		if cfg.EnableDevApi && cfg.RawFaucetApi != nil {
+			nodeApiBuilder.WithLocalFaucetApi(*cfg.RawFaucetApi)
+		}
+		if cfg.EnableDevApi && cfg.DisableConsensus && cfg.RawDevnetApi != nil {
+			controls := make(map[types.ShardId]rawapi.ShardChainControl)
+			for i, syncer := range syncers {
+				if shardId := types.ShardId(i); cfg.IsShardActive(shardId) {
+					controls[shardId] = syncer.Validator()
+				}
+			}
+			nodeApiBuilder.WithLocalDevnetApi(*cfg.RawDevnetApi, controls)
+		}
+		if cfg.RawAdminApi != nil {
+			nodeApiBuilder.WithLocalAdminApi(*cfg.RawAdminApi, accessControl, peerQuotas)
+		}
+		if cfg.RawDbApi != nil {
+			nodeApiBuilder.WithLocalDbApi(*cfg.RawDbApi)
+		}
+		if cfg.RawConsensusApi != nil && !cfg.DisableConsensus {
+			nodeApiBuilder.WithLocalConsensusApi(*cfg.RawConsensusApi, consensuses)
 		}
 
 	case BlockReplayRunMode:
@@ -265,6 +399,22 @@ func getSyncerConfig(name string, cfg *Config, shardId types.ShardId) *collate.S
 	}
 }
 
+// fetchSnapshotBySyncApi bootstraps the state of the shards from the sync API of the peers.
+// The latest block of the main shard is trusted to the peers, the blocks of the other shards
+// are verified against it.
+func fetchSnapshotBySyncApi(ctx context.Context, cfg *Config, nm network.Manager, database db.DB) error {
+	nodeApiBuilder := rawapi.NodeApiBuilder(database, nm)
+	for i := range cfg.NShards {
+		shardId := types.ShardId(i)
+		nodeApiBuilder.
+			WithNetworkShardApiClientRo(shardId).
+			WithNetworkShardApiClientSync(shardId)
+	}
+	return rawapi.FetchSnapshot(
+		ctx, nodeApiBuilder.BuildAndReset(), database,
+		rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+}
+
 type syncersResult struct {
 	funcs   []concurrent.Task
 	syncers []*collate.Syncer
@@ -294,6 +444,11 @@ func createSyncers(
 	for i := range cfg.NShards {
 		shardId := types.ShardId(i)
 		syncerConfig := getSyncerConfig(name, cfg, shardId)
+		if shardId.IsMainShard() && cfg.SnapshotBySyncApi && nm != nil {
+			syncerConfig.FetchSnapshot = func(ctx context.Context) error {
+				return fetchSnapshotBySyncApi(ctx, cfg, nm, database)
+			}
+		}
 		syncer, err := collate.NewSyncer(syncerConfig, validators[i], database, nm)
 		if err != nil {
 			return nil, err
@@ -349,6 +504,10 @@ type Node struct {
 	funcs          []concurrent.Task
 	logger         logging.Logger
 	ctx            context.Context
+
+	// rawApi is set if the raw API is served to the other nodes, it is drained on close.
+	rawApi             rawapi.NodeApi
+	rawApiDrainTimeout time.Duration
 }
 
 func (i *Node) Run() error {
@@ -370,6 +529,14 @@ func (i *Node) Run() error {
 }
 
 func (i *Node) Close(ctx context.Context) {
+	if i.rawApi != nil {
+		// The requests being handled get the time to complete before the network is closed.
+		drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), i.rawApiDrainTimeout)
+		if remaining := i.rawApi.UnsetP2pRequestHandlers(drainCtx); remaining != 0 {
+			i.logger.Warn().Msgf("Closing with %d raw API requests still being handled", remaining)
+		}
+		cancel()
+	}
 	if i.NetworkManager != nil {
 		i.NetworkManager.Close()
 	}
@@ -383,32 +550,38 @@ func runNormalOrCollatorsOnly(
 	database db.DB,
 	networkManager network.Manager,
 	logger logging.Logger,
-) ([]concurrent.Task, map[types.ShardId]txnpool.Pool, error) {
+) (
+	[]concurrent.Task,
+	map[types.ShardId]txnpool.Pool,
+	[]*collate.Syncer,
+	map[types.ShardId]rawapi.ShardConsensus,
+	error,
+) {
 	if err := cfg.LoadValidatorKeys(); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	if !cfg.SplitShards && len(cfg.ZeroState.GetValidators()) == 0 {
 		if err := initDefaultValidator(cfg); err != nil {
-			return nil, nil, err
+			return nil, nil, nil, nil, err
 		}
 	}
 
 	validators, err := createValidators(ctx, cfg, database, networkManager)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	syncersResult, err := createSyncers("sync", cfg, validators, networkManager, database, logger)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 	funcs = append(funcs, syncersResult.funcs...)
 
-	shardFuncs, err := createShards(cfg, validators, syncersResult, database, networkManager, logger)
+	shardFuncs, consensuses, err := createShards(cfg, validators, syncersResult, database, networkManager, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create collators")
-		return nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	txPools := make(map[types.ShardId]txnpool.Pool)
@@ -421,7 +594,7 @@ func runNormalOrCollatorsOnly(
 	}
 
 	funcs = append(funcs, shardFuncs...)
-	return funcs, txPools, nil
+	return funcs, txPools, syncersResult.syncers, consensuses, nil
 }
 
 func CreateNode(
@@ -485,10 +658,13 @@ func CreateNode(
 	}
 
 	var txnPools map[types.ShardId]txnpool.Pool
+	var syncers []*collate.Syncer
 	var syncersResult *syncersResult
+	var consensuses map[types.ShardId]rawapi.ShardConsensus
 	switch cfg.RunMode {
 	case NormalRunMode, CollatorsOnlyRunMode:
-		funcs, txnPools, err = runNormalOrCollatorsOnly(ctx, funcs, cfg, database, networkManager, logger)
+		funcs, txnPools, syncers, consensuses, err = runNormalOrCollatorsOnly(
+			ctx, funcs, cfg, database, networkManager, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -506,6 +682,7 @@ func CreateNode(
 			return nil, err
 		}
 		funcs = append(funcs, syncersResult.funcs...)
+		syncers = syncersResult.syncers
 	case BlockReplayRunMode:
 		replayer := collate.NewReplayScheduler(database, collate.ReplayParams{
 			BlockGeneratorParams: cfg.BlockGeneratorParams(cfg.Replay.ShardId),
@@ -556,13 +733,86 @@ func CreateNode(
 			return nil
 		}))
 
-	rawApi := getRawApi(cfg, networkManager, database, txnPools)
+	// The access control is kept even if the ACL is empty, so that the admin API can restrict the methods later.
+	var accessControl *rawapi.AccessControl
+	if len(cfg.RawApiPeerAcl) != 0 || cfg.RawAdminApi != nil {
+		accessControl = rawapi.NewAccessControl(cfg.RawApiPeerAcl)
+	}
+	var peerQuotas *rawapi.PeerQuotas
+	if cfg.RawApiPeerQuota != nil {
+		peerQuotas = rawapi.NewPeerQuotas(*cfg.RawApiPeerQuota, networkManager)
+	}
+	// The RPC nodes route the requests to the peers serving the shards.
+	var peerDirectory *rawapi.ShardPeerDirectory
+	if cfg.RunMode == RpcRunMode {
+		peerDirectory = rawapi.NewShardPeerDirectory(networkManager, cfg.NShards)
+		funcs = append(funcs, concurrent.MakeTask("shard-peer-directory", func(ctx context.Context) error {
+			peerDirectory.Run(ctx, shardPeerDirectoryRefreshInterval)
+			return nil
+		}))
+	}
+	rawApi := getRawApi(
+		ctx, cfg, networkManager, database, txnPools, syncers, consensuses, accessControl, peerQuotas, peerDirectory)
 	funcs = addRpcServerWorkerIfEnabled(funcs, cfg, rawApi, syncersResult, database, logger)
 
+	var servedRawApi rawapi.NodeApi
 	if cfg.RunMode != CollatorsOnlyRunMode && cfg.RunMode != RpcRunMode {
-		if err := rawApi.SetP2pRequestHandlers(ctx, networkManager, logger); err != nil {
+		handlersConfig := rawapi.RequestHandlersConfig{
+			CompressionThreshold: cfg.RawApiCompressionThreshold,
+			ResponseCache:        cfg.RawApiResponseCache,
+			DisableDeduplication: cfg.RawApiDisableDeduplication,
+			Logging:              cfg.RawApiRequestLog,
+			EnableJsonCodec:      cfg.RawApiJsonCodec,
+			RequestSizeLimits:    cfg.RawApiRequestSizeLimits,
+		}
+		if accessControl != nil {
+			handlersConfig.Interceptors = append(handlersConfig.Interceptors, accessControl.Interceptor())
+		}
+		// The banned peers are rejected before they count towards the limits of the methods.
+		if peerQuotas != nil {
+			handlersConfig.Interceptors = append(handlersConfig.Interceptors, peerQuotas.Interceptor())
+		}
+		if len(cfg.RawApiRateLimits) != 0 {
+			handlersConfig.Interceptors = append(
+				handlersConfig.Interceptors, rawapi.NewRateLimitInterceptor(cfg.RawApiRateLimits))
+		}
+		if len(cfg.RawApiTimeouts) != 0 {
+			handlersConfig.Interceptors = append(
+				handlersConfig.Interceptors, rawapi.NewTimeoutInterceptor(cfg.RawApiTimeouts))
+		}
+		// The requests waiting for the workers are counted by the shedding.
+		if cfg.RawApiLoadShedding != nil {
+			handlersConfig.Interceptors = append(
+				handlersConfig.Interceptors, rawapi.NewLoadSheddingInterceptor(*cfg.RawApiLoadShedding))
+		}
+		// The requests wait for the workers within their timeouts.
+		if cfg.RawApiWorkerPool != nil {
+			pool := rawapi.NewWorkerPool(*cfg.RawApiWorkerPool)
+			handlersConfig.Interceptors = append(handlersConfig.Interceptors, pool.Interceptor())
+			funcs = append(funcs, concurrent.MakeTask("rawapi-worker-pool", pool.Run))
+		}
+		if cfg.RawApiRecordPath != "" {
+			recorder, err := rawapi.NewRequestFileRecorder(cfg.RawApiRecordPath, cfg.RawApiRecordMaxSize)
+			if err != nil {
+				return nil, err
+			}
+			// The recorder is the last one, so that only the requests reaching the API are recorded.
+			handlersConfig.Interceptors = append(handlersConfig.Interceptors, rawapi.NewRecordingInterceptor(recorder))
+			funcs = append(funcs, concurrent.MakeTask("rawapi-recorder", recorder.Run))
+		}
+		handlersManager := networkManager
+		if cfg.RawApiGrpc != nil && networkManager != nil {
+			// The gateway serves the handlers set on it over gRPC as well.
+			gateway := rawapi.NewGrpcGateway(networkManager)
+			handlersManager = gateway
+			funcs = append(funcs, concurrent.MakeTask("rawapi-grpc", func(ctx context.Context) error {
+				return gateway.Serve(ctx, *cfg.RawApiGrpc, logging.NewLogger("rawapi-grpc"))
+			}))
+		}
+		if err := rawApi.SetP2pRequestHandlers(ctx, handlersManager, handlersConfig, logger); err != nil {
 			return nil, err
 		}
+		servedRawApi = rawApi
 
 		funcs = append(funcs, workers...)
 
@@ -572,10 +822,12 @@ func CreateNode(
 	}
 
 	return &Node{
-		NetworkManager: networkManager,
-		funcs:          funcs,
-		logger:         logger,
-		ctx:            ctx,
+		NetworkManager:     networkManager,
+		funcs:              funcs,
+		logger:             logger,
+		ctx:                ctx,
+		rawApi:             servedRawApi,
+		rawApiDrainTimeout: cfg.RawApiDrainTimeout,
 	}, nil
 }
 
@@ -587,7 +839,59 @@ func addRpcServerWorkerIfEnabled(
 	database db.DB,
 	logger logging.Logger,
 ) []concurrent.Task {
-	if (cfg.RPCPort == 0 && cfg.HttpUrl == "") || rawApi == nil {
+	if rawApi == nil {
+		return tasks
+	}
+
+	if cfg.EthCompatRPCPort != 0 {
+		tasks = append(tasks, concurrent.MakeTask(
+			"eth-compat-rpc-api",
+			func(ctx context.Context) error {
+				if syncersResult != nil {
+					if err := syncersResult.Wait(); err != nil {
+						return err
+					}
+				}
+				if err := startEthCompatRpcServer(ctx, cfg, rawApi, database); err != nil {
+					logger.Error().Err(err).Msg("Ethereum-compatible RPC server goroutine failed")
+					return err
+				}
+				return nil
+			}))
+	}
+
+	if cfg.RestPort != 0 {
+		tasks = append(tasks, concurrent.MakeTask(
+			"rest-api",
+			func(ctx context.Context) error {
+				if syncersResult != nil {
+					if err := syncersResult.Wait(); err != nil {
+						return err
+					}
+				}
+				api := jsonrpc.NewEthAPIRo(ctx, rawApi, database, false, cfg.LogClientRpcEvents)
+				defer api.Shutdown()
+				restLogger := logging.NewLogger("REST").With().Int(logging.FieldRpcPort, cfg.RestPort).Logger()
+				handler := rest.NewHandler(api, restLogger)
+				if cfg.EnableGraphQL {
+					graphqlHandler, err := graphql.NewHandler(api)
+					if err != nil {
+						return err
+					}
+					mux := http.NewServeMux()
+					mux.Handle("/", handler)
+					mux.Handle(graphql.Path, graphqlHandler)
+					handler = mux
+				}
+				if err := rest.StartServer(ctx, fmt.Sprintf("127.0.0.1:%d", cfg.RestPort), handler, restLogger); err != nil {
+					logger.Error().Err(err).Msg("REST gateway goroutine failed")
+					return err
+				}
+				return nil
+			}))
+	}
+
+	if cfg.RPCPort == 0 && cfg.HttpUrl == "" {
 		return tasks
 	}
 
@@ -720,12 +1024,13 @@ func createShards(
 	database db.DB,
 	networkManager network.Manager,
 	logger logging.Logger,
-) ([]concurrent.Task, error) {
+) ([]concurrent.Task, map[types.ShardId]rawapi.ShardConsensus, error) {
 	funcs := make([]concurrent.Task, 0, cfg.NShards)
+	consensuses := make(map[types.ShardId]rawapi.ShardConsensus)
 
 	validatorsNum := len(cfg.ZeroState.GetValidators())
 	if validatorsNum != int(cfg.NShards)-1 {
-		return nil, fmt.Errorf("number of shards mismatch in the config, expected %d, got %d",
+		return nil, nil, fmt.Errorf("number of shards mismatch in the config, expected %d, got %d",
 			cfg.NShards-1, validatorsNum)
 	}
 
@@ -735,7 +1040,7 @@ func createShards(
 		if cfg.IsShardActive(shardId) {
 			pKey, err := cfg.ValidatorKeysManager.GetKey()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			consensus, err := ibft.NewConsensus(&ibft.ConsensusParams{
@@ -746,9 +1051,10 @@ func createShards(
 				PrivateKey: pKey,
 			})
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			collator := collate.NewScheduler(validators[i], database, consensus, networkManager)
+			consensuses[shardId] = consensus
 
 			funcs = append(funcs, concurrent.MakeTask(
 				fmt.Sprintf("[%d] collator", i),
@@ -769,10 +1075,10 @@ func createShards(
 					return nil
 				}))
 		} else if networkManager == nil {
-			return nil, errors.New("trying to start syncer without network configuration")
+			return nil, nil, errors.New("trying to start syncer without network configuration")
 		}
 	}
-	return funcs, nil
+	return funcs, consensuses, nil
 }
 
 func createCollateParams(shard types.ShardId, cfg *Config, collatorTickPeriod time.Duration) *collate.Params {
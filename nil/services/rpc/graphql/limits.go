@@ -0,0 +1,93 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+)
+
+var errTooManyApiCalls = errors.New("query makes too many API calls")
+
+type callBudgetKey struct{}
+
+// withCallBudget limits the API calls made by the resolvers of a query, see chargeCall.
+func withCallBudget(ctx context.Context, calls int64) context.Context {
+	budget := new(atomic.Int64)
+	budget.Store(calls)
+	return context.WithValue(ctx, callBudgetKey{}, budget)
+}
+
+// chargeCall takes an API call from the budget of the query. The fields resolved by the API fail
+// once the budget is exhausted, so that a query can't fan out to the shards without bounds.
+func chargeCall(ctx context.Context) error {
+	budget, ok := ctx.Value(callBudgetKey{}).(*atomic.Int64)
+	if ok && budget.Add(-1) < 0 {
+		return errTooManyApiCalls
+	}
+	return nil
+}
+
+// countAliases counts the aliases of the query, i.e., the names followed by a colon outside
+// of the arguments, the strings and the comments.
+func countAliases(query string) int {
+	var aliases, parens int
+	var name bool
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '#':
+			newline := strings.IndexByte(query[i:], '\n')
+			if newline < 0 {
+				return aliases
+			}
+			i += newline
+			name = false
+		case c == '"':
+			end := skipString(query, i)
+			if end < 0 {
+				return aliases
+			}
+			i = end
+			name = false
+		case c == '(':
+			parens++
+			name = false
+		case c == ')':
+			parens--
+			name = false
+		case c == ':':
+			if name && parens == 0 {
+				aliases++
+			}
+			name = false
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+			name = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+		default:
+			name = false
+		}
+	}
+	return aliases
+}
+
+// skipString returns the index of the closing quote of the string or the block string starting at i,
+// or -1 if it is not closed.
+func skipString(query string, i int) int {
+	if strings.HasPrefix(query[i:], `"""`) {
+		end := strings.Index(query[i+3:], `"""`)
+		if end < 0 {
+			return -1
+		}
+		return i + 3 + end + 2
+	}
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			j++
+		case '"':
+			return j
+		}
+	}
+	return -1
+}
@@ -0,0 +1,413 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+// Long is the 64-bit integer scalar of the schema.
+type Long int64
+
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+func (l *Long) UnmarshalGraphQL(input any) error {
+	switch input := input.(type) {
+	case int32:
+		*l = Long(input)
+	case int64:
+		*l = Long(input)
+	case float64:
+		*l = Long(input)
+	case string:
+		value, err := strconv.ParseInt(input, 10, 64)
+		if err != nil {
+			var hexValue uint64
+			if hexValue, err = hexutil.DecodeUint64(input); err != nil {
+				return fmt.Errorf("invalid Long %q: %w", input, err)
+			}
+			value = int64(hexValue)
+		}
+		*l = Long(value)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return nil
+}
+
+// orNil drops the not found errors, the missing entities are null in the result.
+func orNil[T any](result *T, err error) (*T, error) {
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, nil
+	}
+	return result, err
+}
+
+func parseHash(value string) (common.Hash, error) {
+	var hash common.Hash
+	if err := hash.Set(value); err != nil {
+		return hash, fmt.Errorf("invalid hash %q: %w", value, err)
+	}
+	return hash, nil
+}
+
+// Resolver resolves the queries of the schema with the API.
+type Resolver struct {
+	api jsonrpc.EthAPIRo
+}
+
+func (r *Resolver) Shards(ctx context.Context) ([]int32, error) {
+	if err := chargeCall(ctx); err != nil {
+		return nil, err
+	}
+	shardIds, err := r.api.GetShardIdList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]int32, len(shardIds))
+	for i, shardId := range shardIds {
+		result[i] = int32(shardId)
+	}
+	return result, nil
+}
+
+type blockArgs struct {
+	ShardId int32
+	Number  *Long
+	Hash    *string
+}
+
+func (r *Resolver) Block(ctx context.Context, args blockArgs) (*Block, error) {
+	if args.Hash != nil {
+		hash, err := parseHash(*args.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if types.ShardIdFromHash(hash) != types.ShardId(args.ShardId) {
+			return nil, fmt.Errorf("block %s is not in shard %d", hash, args.ShardId)
+		}
+		return r.blockByHash(ctx, hash)
+	}
+
+	number := transport.LatestBlockNumber
+	if args.Number != nil {
+		number = transport.BlockNumber(*args.Number)
+	}
+	if err := chargeCall(ctx); err != nil {
+		return nil, err
+	}
+	return r.newBlock(orNil(r.api.GetBlockByNumber(ctx, types.ShardId(args.ShardId), number, false)))
+}
+
+type hashArgs struct {
+	Hash string
+}
+
+func (r *Resolver) Transaction(ctx context.Context, args hashArgs) (*Transaction, error) {
+	hash, err := parseHash(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return r.transaction(ctx, hash)
+}
+
+func (r *Resolver) Receipt(ctx context.Context, args hashArgs) (*Receipt, error) {
+	hash, err := parseHash(args.Hash)
+	if err != nil {
+		return nil, err
+	}
+	return r.receipt(ctx, hash)
+}
+
+// blockByHash fetches the block without the transactions, they are fetched if selected, see Block.Transactions.
+func (r *Resolver) blockByHash(ctx context.Context, hash common.Hash) (*Block, error) {
+	if err := chargeCall(ctx); err != nil {
+		return nil, err
+	}
+	return r.newBlock(orNil(r.api.GetBlockByHash(ctx, hash, false)))
+}
+
+func (r *Resolver) newBlock(block *jsonrpc.RPCBlock, err error) (*Block, error) {
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return &Block{r: r, block: block}, nil
+}
+
+func (r *Resolver) transaction(ctx context.Context, hash common.Hash) (*Transaction, error) {
+	if err := chargeCall(ctx); err != nil {
+		return nil, err
+	}
+	txn, err := orNil(r.api.GetInTransactionByHash(ctx, hash))
+	if txn == nil || err != nil {
+		return nil, err
+	}
+	return &Transaction{r: r, txn: txn}, nil
+}
+
+func (r *Resolver) receipt(ctx context.Context, hash common.Hash) (*Receipt, error) {
+	if err := chargeCall(ctx); err != nil {
+		return nil, err
+	}
+	receipt, err := orNil(r.api.GetInTransactionReceipt(ctx, hash))
+	if receipt == nil || err != nil {
+		return nil, err
+	}
+	return &Receipt{r: r, receipt: receipt}, nil
+}
+
+type Block struct {
+	r     *Resolver
+	block *jsonrpc.RPCBlock
+}
+
+func (b *Block) ShardId() int32 {
+	return int32(b.block.ShardId)
+}
+
+func (b *Block) Number() Long {
+	return Long(b.block.Number)
+}
+
+func (b *Block) Hash() string {
+	return b.block.Hash.Hex()
+}
+
+func (b *Block) ParentHash() string {
+	return b.block.ParentHash.Hex()
+}
+
+func (b *Block) MainShardHash() string {
+	return b.block.MainShardHash.Hex()
+}
+
+func (b *Block) GasUsed() Long {
+	return Long(b.block.GasUsed)
+}
+
+// Transactions fetches the block again with the transactions, which are not fetched unless selected.
+func (b *Block) Transactions(ctx context.Context) ([]*Transaction, error) {
+	if len(b.block.TransactionHashes) == 0 {
+		return []*Transaction{}, nil
+	}
+	if err := chargeCall(ctx); err != nil {
+		return nil, err
+	}
+	block, err := b.r.api.GetBlockByHash(ctx, b.block.Hash, true)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Transaction, len(block.Transactions))
+	for i, txn := range block.Transactions {
+		result[i] = &Transaction{r: b.r, txn: txn}
+	}
+	return result, nil
+}
+
+// ChildBlocks fetches the child blocks from their shards concurrently.
+func (b *Block) ChildBlocks(ctx context.Context) ([]*Block, error) {
+	blocks := make([]*Block, len(b.block.ChildBlocks))
+	errs := make([]error, len(b.block.ChildBlocks))
+	var wg sync.WaitGroup
+	for i, hash := range b.block.ChildBlocks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			blocks[i], errs[i] = b.r.blockByHash(ctx, hash)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	result := make([]*Block, 0, len(blocks))
+	for _, block := range blocks {
+		if block != nil {
+			result = append(result, block)
+		}
+	}
+	return result, nil
+}
+
+type Transaction struct {
+	r   *Resolver
+	txn *jsonrpc.RPCInTransaction
+}
+
+func (t *Transaction) Hash() string {
+	return t.txn.Hash.Hex()
+}
+
+func (t *Transaction) ShardId() int32 {
+	return int32(types.ShardIdFromHash(t.txn.Hash))
+}
+
+func (t *Transaction) From() string {
+	return t.txn.From.Hex()
+}
+
+func (t *Transaction) To() string {
+	return t.txn.To.Hex()
+}
+
+func (t *Transaction) Value() string {
+	return t.txn.Value.String()
+}
+
+func (t *Transaction) Data() string {
+	return t.txn.Data.String()
+}
+
+func (t *Transaction) Seqno() Long {
+	return Long(t.txn.Seqno)
+}
+
+func (t *Transaction) Success() bool {
+	return t.txn.Success
+}
+
+func (t *Transaction) Index() Long {
+	return Long(t.txn.Index)
+}
+
+func (t *Transaction) GasUsed() Long {
+	return Long(t.txn.GasUsed)
+}
+
+func (t *Transaction) BlockHash() string {
+	return t.txn.BlockHash.Hex()
+}
+
+func (t *Transaction) BlockNumber() Long {
+	return Long(t.txn.BlockNumber)
+}
+
+func (t *Transaction) Block(ctx context.Context) (*Block, error) {
+	return t.r.blockByHash(ctx, t.txn.BlockHash)
+}
+
+func (t *Transaction) Receipt(ctx context.Context) (*Receipt, error) {
+	return t.r.receipt(ctx, t.txn.Hash)
+}
+
+type Receipt struct {
+	r       *Resolver
+	receipt *jsonrpc.RPCReceipt
+}
+
+func (r *Receipt) TransactionHash() string {
+	return r.receipt.TxnHash.Hex()
+}
+
+func (r *Receipt) ShardId() int32 {
+	return int32(r.receipt.ShardId)
+}
+
+func (r *Receipt) Success() bool {
+	return r.receipt.Success
+}
+
+func (r *Receipt) Status() string {
+	return r.receipt.Status
+}
+
+func (r *Receipt) ErrorMessage() *string {
+	if r.receipt.ErrorMessage == "" {
+		return nil
+	}
+	return &r.receipt.ErrorMessage
+}
+
+func (r *Receipt) GasUsed() Long {
+	return Long(r.receipt.GasUsed)
+}
+
+func (r *Receipt) BlockHash() string {
+	return r.receipt.BlockHash.Hex()
+}
+
+func (r *Receipt) BlockNumber() Long {
+	return Long(r.receipt.BlockNumber)
+}
+
+func (r *Receipt) Logs() []*Log {
+	result := make([]*Log, len(r.receipt.Logs))
+	for i, log := range r.receipt.Logs {
+		result[i] = &Log{log: log}
+	}
+	return result
+}
+
+// OutTransactions reuses the receipts of the out transactions returned along with the receipt,
+// the rest are fetched on demand.
+func (r *Receipt) OutTransactions() []*OutTransaction {
+	result := make([]*OutTransaction, len(r.receipt.OutTransactions))
+	for i, hash := range r.receipt.OutTransactions {
+		result[i] = &OutTransaction{r: r.r, hash: hash}
+		if i < len(r.receipt.OutReceipts) && r.receipt.OutReceipts[i] != nil {
+			result[i].receipt = &Receipt{r: r.r, receipt: r.receipt.OutReceipts[i]}
+		}
+	}
+	return result
+}
+
+func (r *Receipt) Transaction(ctx context.Context) (*Transaction, error) {
+	return r.r.transaction(ctx, r.receipt.TxnHash)
+}
+
+type OutTransaction struct {
+	r       *Resolver
+	hash    common.Hash
+	receipt *Receipt
+}
+
+func (t *OutTransaction) Hash() string {
+	return t.hash.Hex()
+}
+
+func (t *OutTransaction) ShardId() int32 {
+	return int32(types.ShardIdFromHash(t.hash))
+}
+
+func (t *OutTransaction) Transaction(ctx context.Context) (*Transaction, error) {
+	return t.r.transaction(ctx, t.hash)
+}
+
+func (t *OutTransaction) Receipt(ctx context.Context) (*Receipt, error) {
+	if t.receipt != nil {
+		return t.receipt, nil
+	}
+	return t.r.receipt(ctx, t.hash)
+}
+
+type Log struct {
+	log *jsonrpc.RPCLog
+}
+
+func (l *Log) Address() string {
+	return l.log.Address.Hex()
+}
+
+func (l *Log) Topics() []string {
+	result := make([]string, len(l.log.Topics))
+	for i, topic := range l.log.Topics {
+		result[i] = topic.Hex()
+	}
+	return result
+}
+
+func (l *Log) Data() string {
+	return l.log.Data.String()
+}
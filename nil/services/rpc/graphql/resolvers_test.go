@@ -0,0 +1,173 @@
+package graphql
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	mainHash  = common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001")
+	childHash = common.HexToHash("0x0001000000000000000000000000000000000000000000000000000000000002")
+	txnHash   = common.HexToHash("0x0001000000000000000000000000000000000000000000000000000000000003")
+	outHash   = common.HexToHash("0x0002000000000000000000000000000000000000000000000000000000000004")
+)
+
+// testApi serves a main shard block referring to a block of shard 1 with a transaction
+// that sent a transaction to shard 2, which is not processed yet.
+type testApi struct {
+	jsonrpc.EthAPIRo
+
+	// fullBlocks counts the blocks fetched with the transactions.
+	fullBlocks *atomic.Int32
+}
+
+func (testApi) GetBlockByNumber(
+	_ context.Context, shardId types.ShardId, number transport.BlockNumber, _ bool,
+) (*jsonrpc.RPCBlock, error) {
+	if shardId != types.MainShardId || number != transport.LatestBlockNumber {
+		return nil, db.ErrKeyNotFound
+	}
+	return &jsonrpc.RPCBlock{Number: 5, Hash: mainHash, ChildBlocks: []common.Hash{childHash}}, nil
+}
+
+func (api testApi) GetBlockByHash(_ context.Context, hash common.Hash, fullTx bool) (*jsonrpc.RPCBlock, error) {
+	switch hash {
+	case mainHash:
+		return &jsonrpc.RPCBlock{Number: 5, Hash: mainHash, ChildBlocks: []common.Hash{childHash}}, nil
+	case childHash:
+		block := &jsonrpc.RPCBlock{
+			Number:        3,
+			Hash:          childHash,
+			ShardId:       1,
+			MainShardHash: mainHash,
+		}
+		if !fullTx {
+			block.TransactionHashes = []common.Hash{txnHash}
+			return block, nil
+		}
+		api.fullBlocks.Add(1)
+		block.Transactions = []*jsonrpc.RPCInTransaction{{
+			Transaction: jsonrpc.Transaction{Hash: txnHash, Value: types.NewValueFromUint64(10)},
+			BlockHash:   childHash,
+			BlockNumber: 3,
+		}}
+		return block, nil
+	}
+	return nil, db.ErrKeyNotFound
+}
+
+func (testApi) GetInTransactionByHash(_ context.Context, hash common.Hash) (*jsonrpc.RPCInTransaction, error) {
+	if hash != txnHash {
+		return nil, db.ErrKeyNotFound
+	}
+	return &jsonrpc.RPCInTransaction{
+		Transaction: jsonrpc.Transaction{Hash: txnHash, Value: types.NewValueFromUint64(10)},
+		BlockHash:   childHash,
+		BlockNumber: 3,
+	}, nil
+}
+
+func (testApi) GetInTransactionReceipt(_ context.Context, hash common.Hash) (*jsonrpc.RPCReceipt, error) {
+	if hash != txnHash {
+		return nil, db.ErrKeyNotFound
+	}
+	return &jsonrpc.RPCReceipt{
+		Success:         true,
+		Status:          "Success",
+		TxnHash:         txnHash,
+		ShardId:         1,
+		BlockHash:       childHash,
+		BlockNumber:     3,
+		OutTransactions: []common.Hash{outHash},
+	}, nil
+}
+
+func exec(t *testing.T, api testApi, query string) string {
+	t.Helper()
+
+	schema, err := newSchema(api)
+	require.NoError(t, err)
+	response := schema.Exec(withCallBudget(t.Context(), maxApiCalls), query, "", nil)
+	require.Empty(t, response.Errors)
+	return string(response.Data)
+}
+
+func newTestApi() testApi {
+	return testApi{fullBlocks: new(atomic.Int32)}
+}
+
+func TestResolvers(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ChildBlocks", func(t *testing.T) {
+		data := exec(t, newTestApi(), `{
+			block(shardId: 0) {
+				number
+				childBlocks { shardId number transactions { hash value } }
+			}
+		}`)
+		require.JSONEq(t, `{"block": {"number": 5, "childBlocks": [{
+			"shardId": 1, "number": 3, "transactions": [{"hash": "`+txnHash.Hex()+`", "value": "10"}]
+		}]}}`, data)
+	})
+
+	t.Run("CrossShard", func(t *testing.T) {
+		data := exec(t, newTestApi(), `{
+			transaction(hash: "`+txnHash.Hex()+`") {
+				shardId
+				block { number }
+				receipt {
+					success
+					errorMessage
+					outTransactions { shardId transaction { hash } receipt { status } }
+				}
+			}
+		}`)
+		require.JSONEq(t, `{"transaction": {
+			"shardId": 1,
+			"block": {"number": 3},
+			"receipt": {
+				"success": true,
+				"errorMessage": null,
+				"outTransactions": [{"shardId": 2, "transaction": null, "receipt": null}]
+			}
+		}}`, data)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		data := exec(t, newTestApi(), `{ block(shardId: 3, number: "0x1") { hash } }`)
+		require.JSONEq(t, `{"block": null}`, data)
+	})
+
+	t.Run("TransactionsSelected", func(t *testing.T) {
+		// The transactions are only fetched for the blocks they are selected for.
+		api := newTestApi()
+		exec(t, api, `{ block(shardId: 0) { childBlocks { number } } }`)
+		require.Zero(t, api.fullBlocks.Load())
+
+		exec(t, api, `{ block(shardId: 0) { childBlocks { transactions { hash } } } }`)
+		require.Equal(t, int32(1), api.fullBlocks.Load())
+	})
+
+	t.Run("TooManyApiCalls", func(t *testing.T) {
+		schema, err := newSchema(newTestApi())
+		require.NoError(t, err)
+
+		// The child blocks are fetched by another call, which exceeds the budget.
+		query := `{ block(shardId: 0) { childBlocks { number } } }`
+		response := schema.Exec(withCallBudget(t.Context(), 1), query, "", nil)
+		require.Len(t, response.Errors, 1)
+		require.Contains(t, response.Errors[0].Message, errTooManyApiCalls.Error())
+
+		response = schema.Exec(withCallBudget(t.Context(), 2), query, "", nil)
+		require.Empty(t, response.Errors)
+	})
+}
@@ -0,0 +1,80 @@
+package graphql
+
+// schema describes the entities of the shards and their links. The links across shards
+// (child blocks of the main shard, the transactions spawned by receipts) are resolved
+// by the server with the API of the target shard.
+const schema = `
+	# Long is a 64-bit integer, it can be passed as a decimal or a hex string too.
+	scalar Long
+
+	schema {
+		query: Query
+	}
+
+	type Query {
+		# The IDs of all shards.
+		shards: [Int!]!
+		# The block of the shard with the given hash or number, the latest one if none is given.
+		block(shardId: Int!, number: Long, hash: String): Block
+		transaction(hash: String!): Transaction
+		receipt(hash: String!): Receipt
+	}
+
+	type Block {
+		shardId: Int!
+		number: Long!
+		hash: String!
+		parentHash: String!
+		mainShardHash: String!
+		gasUsed: Long!
+		transactions: [Transaction!]!
+		# The blocks of the other shards referred to by the main shard block.
+		childBlocks: [Block!]!
+	}
+
+	type Transaction {
+		hash: String!
+		shardId: Int!
+		from: String!
+		to: String!
+		value: String!
+		data: String!
+		seqno: Long!
+		success: Boolean!
+		index: Long!
+		gasUsed: Long!
+		blockHash: String!
+		blockNumber: Long!
+		block: Block
+		receipt: Receipt
+	}
+
+	type Receipt {
+		transactionHash: String!
+		shardId: Int!
+		success: Boolean!
+		status: String!
+		errorMessage: String
+		gasUsed: Long!
+		blockHash: String!
+		blockNumber: Long!
+		logs: [Log!]!
+		# The cross-shard transactions spawned by the transaction.
+		outTransactions: [OutTransaction!]!
+		transaction: Transaction
+	}
+
+	# OutTransaction is a transaction sent to another shard, it's null there until processed.
+	type OutTransaction {
+		hash: String!
+		shardId: Int!
+		transaction: Transaction
+		receipt: Receipt
+	}
+
+	type Log {
+		address: String!
+		topics: [String!]!
+		data: String!
+	}
+`
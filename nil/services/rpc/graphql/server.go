@@ -0,0 +1,79 @@
+package graphql
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/graph-gophers/graphql-go"
+)
+
+const (
+	// Path is the path of the GraphQL endpoint.
+	Path = "/graphql"
+
+	// maxDepth limits the nesting of the queries as every level can fan out to the shards.
+	maxDepth = 12
+	// maxParallelism limits the number of the fields resolved concurrently for a query.
+	maxParallelism = 16
+	// maxRequestSize limits the size of the body of the requests.
+	maxRequestSize = 64 * 1024
+	// maxAliases limits the aliases of a query, since every alias resolves its field once more.
+	maxAliases = 32
+	// maxApiCalls limits the calls of the API made for a query, i.e., the cost of its fan-out to the shards.
+	maxApiCalls = 256
+)
+
+func newSchema(api jsonrpc.EthAPIRo) (*graphql.Schema, error) {
+	return graphql.ParseSchema(
+		schema,
+		&Resolver{api: api},
+		graphql.MaxDepth(maxDepth),
+		graphql.MaxParallelism(maxParallelism))
+}
+
+type queryRequest struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+type handler struct {
+	schema *graphql.Schema
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestSize)
+	var request queryRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		status := http.StatusBadRequest
+		if maxBytesErr := new(http.MaxBytesError); errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if aliases := countAliases(request.Query); aliases > maxAliases {
+		http.Error(w, fmt.Sprintf("query has %d aliases, at most %d are allowed", aliases, maxAliases),
+			http.StatusBadRequest)
+		return
+	}
+
+	ctx := withCallBudget(r.Context(), maxApiCalls)
+	response := h.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(response)
+}
+
+// NewHandler returns the handler serving the GraphQL queries over the API at Path.
+func NewHandler(api jsonrpc.EthAPIRo) (http.Handler, error) {
+	schema, err := newSchema(api)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(http.MethodPost+" "+Path, &handler{schema: schema})
+	return mux, nil
+}
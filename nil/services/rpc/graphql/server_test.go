@@ -0,0 +1,70 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountAliases(t *testing.T) {
+	t.Parallel()
+
+	require.Zero(t, countAliases(`query Q($hash: String!) { transaction(hash: $hash) { hash } }`))
+	require.Equal(t, 2, countAliases(`{ a: block(shardId: 0) { hash } b : block(shardId: 1) { hash } }`))
+	// The colons of the strings and the comments are not aliases.
+	require.Zero(t, countAliases("{ # a: b\n block(shardId: 0) { hash } }"))
+	require.Zero(t, countAliases(`{ transaction(hash: "a: b") { hash } }`))
+	require.Equal(t, 1, countAliases(`{ a: transaction(hash: """x: "y" """) { hash } }`))
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	handler, err := NewHandler(newTestApi())
+	require.NoError(t, err)
+	post := func(t *testing.T, body string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, Path, strings.NewReader(body)))
+		return recorder
+	}
+	query := func(t *testing.T, query string) *httptest.ResponseRecorder {
+		t.Helper()
+
+		body, err := json.Marshal(queryRequest{Query: query})
+		require.NoError(t, err)
+		return post(t, string(body))
+	}
+
+	t.Run("Query", func(t *testing.T) {
+		t.Parallel()
+
+		response := query(t, `{ block(shardId: 0) { number } }`)
+		require.Equal(t, http.StatusOK, response.Code)
+		require.JSONEq(t, `{"data": {"block": {"number": 5}}}`, response.Body.String())
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		t.Parallel()
+
+		response := post(t, `{"query": "`+strings.Repeat(" ", maxRequestSize)+`"}`)
+		require.Equal(t, http.StatusRequestEntityTooLarge, response.Code)
+	})
+
+	t.Run("TooManyAliases", func(t *testing.T) {
+		t.Parallel()
+
+		var fields strings.Builder
+		for i := range maxAliases + 1 {
+			fmt.Fprintf(&fields, "a%d: shards ", i)
+		}
+		response := query(t, "{ "+fields.String()+"}")
+		require.Equal(t, http.StatusBadRequest, response.Code)
+	})
+}
@@ -40,6 +40,10 @@ type HttpCfg struct {
 	HttpURL         string
 	HttpCORSDomain  []string
 	HttpCompression bool
+	// WebsocketEnabled serves the websocket connections on the same endpoint, they support subscriptions
+	WebsocketEnabled bool
+	// WebsocketSubscriptionLimit limits the subscriptions of a websocket connection, the default is used if zero
+	WebsocketSubscriptionLimit int
 
 	TraceRequests      bool // Print requests to logs at INFO level
 	DebugSingleRequest bool // Print single-request-related debugging info to logs at INFO level
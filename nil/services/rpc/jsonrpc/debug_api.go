@@ -56,9 +56,11 @@ func (api *DebugAPIImpl) GetBlockByNumber(
 			blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
 		case transport.EarliestBlockNumber:
 			blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.EarliestBlock)
-		case transport.LatestExecutedBlockNumber:
 		case transport.FinalizedBlockNumber:
+			blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.FinalizedBlock)
 		case transport.SafeBlockNumber:
+			blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.SafeBlock)
+		case transport.LatestExecutedBlockNumber:
 		case transport.PendingBlockNumber:
 		default:
 			return nil, fmt.Errorf("not supported special block number %s", number)
@@ -24,6 +24,10 @@ package jsonrpc
 // @component PollFilterId id string "The ID of the filter that should be polled."
 // @component UninstallFilterId id string "The ID of the filter that should be uninstalled."
 // @component FilterId id string "The ID of the filter."
+// @component SubscriptionKind kind string "The kind of the subscription: newHeads, logs or newPendingTransactions."
+// @component SubscriptionParams params object "The filter query for logs or the fullTx flag for pending transactions."
+// @component SubscriptionId subscriptionId string "The ID of the subscription sent along with its notifications."
+// @component UnsubscribeId id string "The ID of the subscription that should be canceled."
 // @component FilterChanges filterChanges array "The array of logs, block headers or pending transactions that have occurred since the last poll of the filter."
 // @component FilterLogs filterLogs array "The array of logs that have been recorded since the last poll of the filter."
 // @component ShardIds shardIds array "The array of shard IDs."
@@ -2,6 +2,7 @@ package jsonrpc
 
 import (
 	"context"
+	"maps"
 
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
@@ -29,7 +30,20 @@ func (api *APIImplRo) GetTokens(
 	address types.Address,
 	blockNrOrHash transport.BlockNumberOrHash,
 ) (map[types.TokenId]types.Value, error) {
-	return api.rawapi.GetTokens(ctx, address, toBlockReference(blockNrOrHash))
+	// The pages are limited, so the tokens are read page by page.
+	var request rawapitypes.TokensRequest
+	tokens := make(map[types.TokenId]types.Value)
+	for {
+		page, err := api.rawapi.GetTokens(ctx, address, toBlockReference(blockNrOrHash), request)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(tokens, page.Tokens)
+		if page.Next == nil {
+			return tokens, nil
+		}
+		request.Start = page.Next
+	}
 }
 
 // GetTransactionCount implements eth_getTransactionCount.
@@ -2,6 +2,7 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
@@ -257,6 +258,33 @@ type EthAPIRo interface {
 	*/
 	GetFilterLogs(_ context.Context, id string) ([]*RPCLog, error)
 
+	/*
+		@name Subscribe
+		@summary Subscribes to the events of the shard.
+		@description Implements eth_subscribe. Available over websocket connections only.
+		@tags [Filters]
+		@param shardId BlockShardId
+		@param kind SubscriptionKind
+		@param params SubscriptionParams
+		@returns subscriptionId SubscriptionId
+	*/
+	Subscribe(
+		ctx context.Context,
+		shardId types.ShardId,
+		kind string,
+		params *json.RawMessage,
+	) (transport.SubscriptionID, error)
+
+	/*
+		@name Unsubscribe
+		@summary Cancels the subscription with the given id.
+		@description Implements eth_unsubscribe. Available over websocket connections only.
+		@tags [Filters]
+		@param id UnsubscribeId
+		@returns isDeleted IsDeleted
+	*/
+	Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error)
+
 	/*
 		@name GetShardsIdList
 		@summary Retrieves a list of IDs of all shards.
@@ -283,6 +311,7 @@ type EthAPIRo interface {
 		@param args CallArgs
 		@param mainBlockNrOrHash BlockNumberOrHash
 		@param overrides StateOverrides
+		@param blockOverrides BlockOverrides
 		@returns callRes CallRes
 	*/
 	Call(
@@ -290,6 +319,7 @@ type EthAPIRo interface {
 		args CallArgs,
 		mainBlockNrOrHash transport.BlockNumberOrHash,
 		overrides *StateOverrides,
+		blockOverrides *BlockOverrides,
 	) (*CallRes, error)
 
 	/*
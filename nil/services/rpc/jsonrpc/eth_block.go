@@ -26,6 +26,13 @@ func sszToRPCBlock(shardId types.ShardId, raw *types.RawBlockWithExtractedData,
 	return NewRPCBlock(shardId, block, fullTx)
 }
 
+// isSupportedBlockNumber checks that the block number is either regular or resolved by the raw API.
+func isSupportedBlockNumber(number transport.BlockNumber) bool {
+	return number >= transport.LatestBlockNumber ||
+		number == transport.SafeBlockNumber ||
+		number == transport.FinalizedBlockNumber
+}
+
 // GetBlockByNumber implements eth_getBlockByNumber. Returns information about a block given the block's number.
 func (api *APIImplRo) GetBlockByNumber(
 	ctx context.Context,
@@ -33,7 +40,7 @@ func (api *APIImplRo) GetBlockByNumber(
 	number transport.BlockNumber,
 	fullTx bool,
 ) (*RPCBlock, error) {
-	if number < transport.LatestBlockNumber {
+	if !isSupportedBlockNumber(number) {
 		return nil, errNotImplemented
 	}
 
@@ -59,7 +66,7 @@ func (api *APIImplRo) GetBlockByHash(ctx context.Context, hash common.Hash, full
 func (api *APIImplRo) GetBlockTransactionCountByNumber(
 	ctx context.Context, shardId types.ShardId, number transport.BlockNumber,
 ) (hexutil.Uint, error) {
-	if number < transport.LatestBlockNumber {
+	if !isSupportedBlockNumber(number) {
 		return 0, errNotImplemented
 	}
 	res, err := api.rawapi.GetBlockTransactionCount(ctx, shardId, blockNrToBlockReference(number))
@@ -56,15 +56,17 @@ func (suite *SuiteEthBlock) TestGetBlockByNumber() {
 	_, err := suite.api.GetBlockByNumber(suite.ctx, shardId, transport.LatestExecutedBlockNumber, false)
 	suite.Require().EqualError(err, "not implemented")
 
-	_, err = suite.api.GetBlockByNumber(suite.ctx, shardId, transport.FinalizedBlockNumber, false)
-	suite.Require().EqualError(err, "not implemented")
-
-	_, err = suite.api.GetBlockByNumber(suite.ctx, shardId, transport.SafeBlockNumber, false)
-	suite.Require().EqualError(err, "not implemented")
-
 	_, err = suite.api.GetBlockByNumber(suite.ctx, shardId, transport.PendingBlockNumber, false)
 	suite.Require().EqualError(err, "not implemented")
 
+	// The blocks of the main shard are final once committed.
+	for _, number := range []transport.BlockNumber{transport.FinalizedBlockNumber, transport.SafeBlockNumber} {
+		data, err := suite.api.GetBlockByNumber(suite.ctx, shardId, number, false)
+		suite.Require().NoError(err)
+		suite.Require().NotNil(data)
+		suite.Equal(suite.lastBlockHash, data.Hash)
+	}
+
 	data, err := suite.api.GetBlockByNumber(suite.ctx, shardId, transport.LatestBlockNumber, false)
 	suite.Require().NoError(err)
 	suite.Require().NotNil(data)
@@ -5,8 +5,6 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/NilFoundation/nil/nil/common/check"
-	"github.com/NilFoundation/nil/nil/internal/params"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/transport"
@@ -20,12 +18,13 @@ func (api *APIImplRo) Call(
 	args CallArgs,
 	mainBlockNrOrHash transport.BlockNumberOrHash,
 	overrides *StateOverrides,
+	blockOverrides *BlockOverrides,
 ) (*CallRes, error) {
 	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
 	if args.Fee.FeeCredit.IsZero() {
 		args.Fee = types.NewFeePackFromGas(1_000_000_000_000_000_000)
 	}
-	res, err := api.rawapi.Call(ctx, args, blockRef, overrides)
+	res, err := api.rawapi.Call(ctx, args, blockRef, overrides, blockOverrides)
 	if err != nil {
 		return nil, err
 	}
@@ -38,11 +37,6 @@ func refineResult(input types.Value) types.Value {
 	return input.Mul64(12).Div64(10)
 }
 
-// SstoreSentryGasEIP2200 is a requirement to execute SSTORE opcode.
-// But actually we can spend less amount of gas.
-// Let's try to specify reasonable upper bound for fee estimation.
-const SstoreSentryGas = types.Gas(params.SstoreSentryGasEIP2200)
-
 func refineOutTxnResult(txns []*rpctypes.OutTransaction) types.Value {
 	result := types.NewZeroValue()
 	if len(txns) == 0 {
@@ -52,7 +46,7 @@ func refineOutTxnResult(txns []*rpctypes.OutTransaction) types.Value {
 	for _, txn := range txns {
 		result = result.
 			Add(txn.CoinsUsed).
-			Add(SstoreSentryGas.ToValue(txn.BaseFee)).
+			Add(rpctypes.SstoreSentryGas.ToValue(txn.BaseFee)).
 			Add(refineOutTxnResult(txn.OutTransactions))
 	}
 	return result
@@ -64,11 +58,6 @@ func (api *APIImplRo) EstimateFee(
 	args CallArgs,
 	mainBlockNrOrHash transport.BlockNumberOrHash,
 ) (*EstimateFeeRes, error) {
-	balanceCap, err := types.NewValueFromDecimal("1000000000000000000000000") // 1 MEther
-	check.PanicIfErr(err)
-	feeCreditCap, err := types.NewValueFromDecimal("500000000000000000000000") // 0.5 MEther
-	check.PanicIfErr(err)
-
 	blockRef := rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(toBlockReference(mainBlockNrOrHash))
 	execute := func(balance, feeCredit types.Value) (*rpctypes.CallResWithGasPrice, error) {
 		args.Fee = types.NewFeePackFromFeeCredit(feeCredit)
@@ -80,7 +69,7 @@ func (api *APIImplRo) EstimateFee(
 		}
 
 		// Root transaction considered here as external since we anyway override contract balance.
-		res, err := api.rawapi.Call(ctx, args, blockRef, stateOverrides)
+		res, err := api.rawapi.Call(ctx, args, blockRef, stateOverrides, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to estimate call fee: %s", err.Error())
 		}
@@ -92,20 +81,18 @@ func (api *APIImplRo) EstimateFee(
 	}
 
 	// Check that it's possible to run transaction with Max balance and feeCredit
-	res, err := execute(balanceCap, feeCreditCap)
+	res, err := execute(rpctypes.FeeEstimationBalanceCap(), rpctypes.FeeEstimationFeeCreditCap())
 	if err != nil {
 		return nil, err
 	}
 
 	result := res.CoinsUsed.
 		Add(args.Value).
-		Add(SstoreSentryGas.ToValue(res.BaseFee)).
+		Add(rpctypes.SstoreSentryGas.ToValue(res.BaseFee)).
 		Add(refineOutTxnResult(res.OutTransactions))
 
 	if !args.Flags.GetBit(types.TransactionFlagInternal) {
-		// Heuristic price for external transaction verification for the smart account.
-		const externalVerificationGas = types.Gas(10_000)
-		result = result.Add(externalVerificationGas.ToValue(res.BaseFee))
+		result = result.Add(rpctypes.ExternalVerificationGas.ToValue(res.BaseFee))
 	}
 	maxBaseFee := res.BaseFee
 	for _, txn := range res.OutTransactions {
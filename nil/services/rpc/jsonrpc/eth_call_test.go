@@ -104,26 +104,26 @@ func (s *SuiteEthCall) TestSmcCall() {
 		To:    to,
 		Fee:   types.NewFeePackFromGas(10_000),
 	}
-	res, err := s.api.Call(ctx, args, latestBlockId, nil)
+	res, err := s.api.Call(ctx, args, latestBlockId, nil, nil)
 	s.Require().NoError(err)
 	s.EqualValues(0x2a, s.unpackGetValue(res.Data))
 
 	// Call with block number
 	num := transport.BlockNumber(0)
-	res, err = s.api.Call(ctx, args, transport.BlockNumberOrHash{BlockNumber: &num}, nil)
+	res, err = s.api.Call(ctx, args, transport.BlockNumberOrHash{BlockNumber: &num}, nil, nil)
 	s.Require().NoError(err)
 	s.EqualValues(0x2a, s.unpackGetValue(res.Data))
 
 	// Out of gas
 	args.Fee = types.NewFeePackFromGas(1)
-	res, err = s.api.Call(ctx, args, transport.BlockNumberOrHash{BlockNumber: &num}, nil)
+	res, err = s.api.Call(ctx, args, transport.BlockNumberOrHash{BlockNumber: &num}, nil, nil)
 	s.Require().NoError(err)
 	s.Require().Contains(res.Error, vm.ErrOutOfGas.Error())
 
 	// Unknown "to"
 	args.Fee = types.NewFeePackFromGas(10_000)
 	args.To = types.GenerateRandomAddress(types.BaseShardId)
-	res, err = s.api.Call(ctx, args, transport.BlockNumberOrHash{BlockNumber: &num}, nil)
+	res, err = s.api.Call(ctx, args, transport.BlockNumberOrHash{BlockNumber: &num}, nil, nil)
 	s.Require().NoError(err)
 	s.Require().Empty(res.Error)
 	s.Require().Empty(res.Data)
@@ -147,12 +147,12 @@ func (s *SuiteEthCall) TestChainCall() {
 		To:   to,
 		Fee:  types.NewFeePackFromGas(10_000),
 	}
-	res, err := s.api.Call(ctx, args, latestBlockId, nil)
+	res, err := s.api.Call(ctx, args, latestBlockId, nil, nil)
 	s.Require().NoError(err)
 	s.EqualValues(0x2a, s.unpackGetValue(res.Data))
 
 	args.Data = &setData
-	res, err = s.api.Call(ctx, args, latestBlockId, nil)
+	res, err = s.api.Call(ctx, args, latestBlockId, nil, nil)
 	s.Require().NoError(err)
 	s.Empty(res.Data)
 	s.Len(res.StateOverrides, 1)
@@ -160,11 +160,42 @@ func (s *SuiteEthCall) TestChainCall() {
 	s.Nil(res.StateOverrides[s.simple].Balance)
 
 	args.Data = &getData
-	res, err = s.api.Call(ctx, args, latestBlockId, &res.StateOverrides)
+	res, err = s.api.Call(ctx, args, latestBlockId, &res.StateOverrides, nil)
 	s.Require().NoError(err)
 	s.EqualValues(0x7b, s.unpackGetValue(res.Data))
 }
 
+func (s *SuiteEthCall) TestBlockOverrides() {
+	ctx := s.T().Context()
+
+	// The code returns the number and the timestamp of the block.
+	code := hexutil.Bytes{
+		byte(vm.NUMBER), byte(vm.PUSH1), 0, byte(vm.MSTORE),
+		byte(vm.TIMESTAMP), byte(vm.PUSH1), 0x20, byte(vm.MSTORE),
+		byte(vm.PUSH1), 0x40, byte(vm.PUSH1), 0, byte(vm.RETURN),
+	}
+	to := types.GenerateRandomAddress(types.BaseShardId)
+	overrides := &StateOverrides{to: {Code: &code}}
+	args := CallArgs{
+		To:  to,
+		Fee: types.NewFeePackFromGas(10_000),
+	}
+
+	res, err := s.api.Call(ctx, args, latestBlockId, overrides, nil)
+	s.Require().NoError(err)
+	s.Require().Empty(res.Error)
+	s.Require().Len(res.Data, 64)
+	s.Zero(new(big.Int).SetBytes(res.Data[:32]).Uint64())
+
+	number := types.BlockNumber(7)
+	timestamp := uint64(1_000)
+	res, err = s.api.Call(ctx, args, latestBlockId, overrides, &BlockOverrides{Number: &number, Timestamp: &timestamp})
+	s.Require().NoError(err)
+	s.Require().Empty(res.Error)
+	s.EqualValues(7, new(big.Int).SetBytes(res.Data[:32]).Uint64())
+	s.EqualValues(1_000, new(big.Int).SetBytes(res.Data[32:]).Uint64())
+}
+
 func TestSuiteEthCall(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,566 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+// ethDynamicFeeTxType is the type of the EIP-1559 transactions,
+// the fees of the nil transactions are specified the same way.
+const ethDynamicFeeTxType = 2
+
+// ethEmptyUncleHash is the hash of the empty list of uncles, nil blocks have no uncles.
+var ethEmptyUncleHash = common.HexToHash("0x1dcc4de8dec75d7aab85b567b6ccd41ad312451b948a7413f0a142fd40d49347")
+
+var (
+	errEthContractCreationCall = errors.New(
+		"calls without the recipient are not supported, contracts are deployed via smart accounts")
+	errEthReadOnly = errors.New("transactions are not accepted by the read-only node")
+)
+
+// EthCompatAPI serves the eth_* methods with the signatures of Ethereum, so that the Ethereum tooling
+// (ethers.js, foundry cast, etc.) can read the state of the shards. It is served on a separate endpoint,
+// since its methods clash with the ones of EthAPI taking the shard IDs.
+//
+// The shard of a request is resolved from the address or the hash, the methods without either use the default shard.
+// The transactions sent with eth_sendRawTransaction are nil external transactions, the Ethereum ones are not accepted.
+type EthCompatAPI interface {
+	ChainId(ctx context.Context) (hexutil.Uint64, error)
+	BlockNumber(ctx context.Context) (hexutil.Uint64, error)
+	GasPrice(ctx context.Context) (*hexutil.Big, error)
+
+	GetBalance(
+		ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash) (*hexutil.Big, error)
+	GetCode(
+		ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash) (hexutil.Bytes, error)
+	GetTransactionCount(
+		ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash) (hexutil.Uint64, error)
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		key common.Hash,
+		blockNrOrHash transport.BlockNumberOrHash,
+	) (common.Hash, error)
+
+	GetBlockByNumber(ctx context.Context, number transport.BlockNumber, fullTx bool) (*EthBlock, error)
+	GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (*EthBlock, error)
+	GetTransactionByHash(ctx context.Context, hash common.Hash) (*EthTransaction, error)
+	GetTransactionReceipt(ctx context.Context, hash common.Hash) (*EthReceipt, error)
+	GetLogs(ctx context.Context, query EthLogQuery) ([]*EthLog, error)
+
+	Call(ctx context.Context, args EthCallArgs, blockNrOrHash transport.BlockNumberOrHash) (hexutil.Bytes, error)
+	EstimateGas(
+		ctx context.Context, args EthCallArgs, blockNrOrHash *transport.BlockNumberOrHash) (hexutil.Uint64, error)
+	SendRawTransaction(ctx context.Context, encoded hexutil.Bytes) (common.Hash, error)
+}
+
+// EthBlock is a block in the format of Ethereum. The fields nil blocks don't have are zero.
+type EthBlock struct {
+	Number           hexutil.Uint64 `json:"number"`
+	Hash             common.Hash    `json:"hash"`
+	ParentHash       common.Hash    `json:"parentHash"`
+	Nonce            hexutil.Bytes  `json:"nonce"`
+	Sha3Uncles       common.Hash    `json:"sha3Uncles"`
+	LogsBloom        hexutil.Bytes  `json:"logsBloom"`
+	TransactionsRoot common.Hash    `json:"transactionsRoot"`
+	StateRoot        common.Hash    `json:"stateRoot"`
+	ReceiptsRoot     common.Hash    `json:"receiptsRoot"`
+	Miner            types.Address  `json:"miner"`
+	Difficulty       hexutil.Uint64 `json:"difficulty"`
+	ExtraData        hexutil.Bytes  `json:"extraData"`
+	Size             hexutil.Uint64 `json:"size"`
+	GasLimit         hexutil.Uint64 `json:"gasLimit"`
+	GasUsed          hexutil.Uint64 `json:"gasUsed"`
+	// Timestamp is zero, since nil blocks don't keep the time they are produced at.
+	Timestamp     hexutil.Uint64 `json:"timestamp"`
+	BaseFeePerGas *hexutil.Big   `json:"baseFeePerGas"`
+	// Transactions are either the hashes or the EthTransaction objects.
+	Transactions []any         `json:"transactions"`
+	Uncles       []common.Hash `json:"uncles"`
+}
+
+// EthTransaction is a transaction included in a block in the format of Ethereum.
+type EthTransaction struct {
+	BlockHash            common.Hash    `json:"blockHash"`
+	BlockNumber          hexutil.Uint64 `json:"blockNumber"`
+	TransactionIndex     hexutil.Uint64 `json:"transactionIndex"`
+	Hash                 common.Hash    `json:"hash"`
+	Type                 hexutil.Uint64 `json:"type"`
+	ChainId              hexutil.Uint64 `json:"chainId"`
+	Nonce                hexutil.Uint64 `json:"nonce"`
+	From                 types.Address  `json:"from"`
+	To                   types.Address  `json:"to"`
+	Value                *hexutil.Big   `json:"value"`
+	Gas                  hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big   `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"`
+	Input                hexutil.Bytes  `json:"input"`
+}
+
+// EthReceipt is a receipt in the format of Ethereum. The outgoing transactions and their receipts are not included.
+type EthReceipt struct {
+	TransactionHash   common.Hash    `json:"transactionHash"`
+	TransactionIndex  hexutil.Uint64 `json:"transactionIndex"`
+	BlockHash         common.Hash    `json:"blockHash"`
+	BlockNumber       hexutil.Uint64 `json:"blockNumber"`
+	From              types.Address  `json:"from"`
+	To                types.Address  `json:"to"`
+	CumulativeGasUsed hexutil.Uint64 `json:"cumulativeGasUsed"`
+	GasUsed           hexutil.Uint64 `json:"gasUsed"`
+	EffectiveGasPrice *hexutil.Big   `json:"effectiveGasPrice"`
+	ContractAddress   *types.Address `json:"contractAddress"`
+	Logs              []*EthLog      `json:"logs"`
+	LogsBloom         hexutil.Bytes  `json:"logsBloom"`
+	Status            hexutil.Uint64 `json:"status"`
+	Type              hexutil.Uint64 `json:"type"`
+}
+
+// EthLog is a log in the format of Ethereum.
+type EthLog struct {
+	Address          types.Address  `json:"address"`
+	Topics           []common.Hash  `json:"topics"`
+	Data             hexutil.Bytes  `json:"data"`
+	BlockNumber      hexutil.Uint64 `json:"blockNumber"`
+	BlockHash        common.Hash    `json:"blockHash"`
+	TransactionHash  common.Hash    `json:"transactionHash"`
+	TransactionIndex hexutil.Uint64 `json:"transactionIndex"`
+	LogIndex         hexutil.Uint64 `json:"logIndex"`
+	Removed          bool           `json:"removed"`
+}
+
+// EthCallArgs are the arguments of eth_call and eth_estimateGas.
+type EthCallArgs struct {
+	From *types.Address  `json:"from"`
+	To   *types.Address  `json:"to"`
+	Gas  *hexutil.Uint64 `json:"gas"`
+	// GasPrice is accepted for compatibility, the calls are executed at the base fee of the shard.
+	GasPrice *hexutil.Big   `json:"gasPrice"`
+	Value    *hexutil.Big   `json:"value"`
+	Data     *hexutil.Bytes `json:"data"`
+	Input    *hexutil.Bytes `json:"input"`
+}
+
+// EthLogQuery is the filter of eth_getLogs. The blocks of the range are resolved in the shard of the addresses,
+// which must be in the same shard, or in the default shard if there are none. The block hash selects its own shard.
+type EthLogQuery struct {
+	filters.FilterQuery
+
+	fromBlock *transport.BlockNumber
+	toBlock   *transport.BlockNumber
+}
+
+func (q *EthLogQuery) UnmarshalJSON(data []byte) error {
+	if err := q.FilterQuery.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	// FilterQuery keeps the numbers only, so the named blocks are read separately.
+	var blocks struct {
+		FromBlock *transport.BlockNumber `json:"fromBlock"`
+		ToBlock   *transport.BlockNumber `json:"toBlock"`
+	}
+	if err := json.Unmarshal(data, &blocks); err != nil {
+		return err
+	}
+	q.fromBlock, q.toBlock = blocks.FromBlock, blocks.ToBlock
+	return nil
+}
+
+type EthCompatAPIImpl struct {
+	eth *APIImplRo
+	// sender sends the transactions, it is nil on the read-only nodes.
+	sender       *APIImpl
+	defaultShard types.ShardId
+}
+
+var _ EthCompatAPI = (*EthCompatAPIImpl)(nil)
+
+// NewEthCompatAPI creates the Ethereum-compatible API over the API of the node,
+// the transactions are rejected if sender is nil.
+func NewEthCompatAPI(eth *APIImplRo, sender *APIImpl, defaultShard types.ShardId) *EthCompatAPIImpl {
+	return &EthCompatAPIImpl{eth: eth, sender: sender, defaultShard: defaultShard}
+}
+
+// ChainId implements eth_chainId.
+func (api *EthCompatAPIImpl) ChainId(ctx context.Context) (hexutil.Uint64, error) {
+	return api.eth.ChainId(ctx)
+}
+
+// BlockNumber implements eth_blockNumber. Returns the number of the latest block of the default shard.
+func (api *EthCompatAPIImpl) BlockNumber(ctx context.Context) (hexutil.Uint64, error) {
+	number, err := api.latestBlockNumber(ctx, api.defaultShard)
+	return hexutil.Uint64(number), err
+}
+
+// GasPrice implements eth_gasPrice. Returns the gas price of the default shard.
+func (api *EthCompatAPIImpl) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	price, err := api.eth.GasPrice(ctx, api.defaultShard)
+	if err != nil {
+		return nil, err
+	}
+	return hexutil.NewBig(price.ToBig()), nil
+}
+
+// GetBalance implements eth_getBalance.
+func (api *EthCompatAPIImpl) GetBalance(
+	ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash,
+) (*hexutil.Big, error) {
+	return api.eth.GetBalance(ctx, address, blockNrOrHash)
+}
+
+// GetCode implements eth_getCode.
+func (api *EthCompatAPIImpl) GetCode(
+	ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash,
+) (hexutil.Bytes, error) {
+	return api.eth.GetCode(ctx, address, blockNrOrHash)
+}
+
+// GetTransactionCount implements eth_getTransactionCount. Returns the seqno of the account.
+func (api *EthCompatAPIImpl) GetTransactionCount(
+	ctx context.Context, address types.Address, blockNrOrHash transport.BlockNumberOrHash,
+) (hexutil.Uint64, error) {
+	return api.eth.GetTransactionCount(ctx, address, blockNrOrHash)
+}
+
+// GetStorageAt implements eth_getStorageAt.
+func (api *EthCompatAPIImpl) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockNrOrHash transport.BlockNumberOrHash,
+) (common.Hash, error) {
+	value, err := api.eth.rawapi.GetStorageAt(ctx, address, key, toBlockReference(blockNrOrHash))
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	bytes := value.Bytes32()
+	return common.BytesToHash(bytes[:]), nil
+}
+
+// GetBlockByNumber implements eth_getBlockByNumber for the blocks of the default shard.
+func (api *EthCompatAPIImpl) GetBlockByNumber(
+	ctx context.Context, number transport.BlockNumber, fullTx bool,
+) (*EthBlock, error) {
+	if !isSupportedBlockNumber(number) {
+		return nil, errNotImplemented
+	}
+	raw, err := api.eth.rawapi.GetFullBlockData(ctx, api.defaultShard, blockNrToBlockReference(number))
+	if err != nil {
+		return nil, err
+	}
+	return newEthBlock(api.defaultShard, raw, fullTx)
+}
+
+// GetBlockByHash implements eth_getBlockByHash for the blocks of any shard.
+func (api *EthCompatAPIImpl) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (*EthBlock, error) {
+	shardId := types.ShardIdFromHash(hash)
+	raw, err := api.eth.rawapi.GetFullBlockData(ctx, shardId, rawapitypes.BlockHashAsBlockReference(hash))
+	if err != nil {
+		return nil, err
+	}
+	return newEthBlock(shardId, raw, fullTx)
+}
+
+// GetTransactionByHash implements eth_getTransactionByHash for the transactions included in the blocks.
+func (api *EthCompatAPIImpl) GetTransactionByHash(ctx context.Context, hash common.Hash) (*EthTransaction, error) {
+	txn, err := api.eth.GetInTransactionByHash(ctx, hash)
+	if err != nil || txn == nil {
+		return nil, err
+	}
+	return newEthTransaction(txn), nil
+}
+
+// GetTransactionReceipt implements eth_getTransactionReceipt.
+func (api *EthCompatAPIImpl) GetTransactionReceipt(ctx context.Context, hash common.Hash) (*EthReceipt, error) {
+	receipt, err := api.eth.GetInTransactionReceipt(ctx, hash)
+	if err != nil || receipt == nil {
+		return nil, err
+	}
+	txn, err := api.eth.GetInTransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return newEthReceipt(receipt, txn), nil
+}
+
+// GetLogs implements eth_getLogs.
+func (api *EthCompatAPIImpl) GetLogs(ctx context.Context, query EthLogQuery) ([]*EthLog, error) {
+	shardId, err := api.logsShard(query.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := rawapitypes.LogFilter{Addresses: query.Addresses, Topics: query.Topics}
+	if query.BlockHash != nil {
+		// The logs of the block are read in the shard of its hash.
+		blockShardId := types.ShardIdFromHash(*query.BlockHash)
+		if len(query.Addresses) != 0 && shardId != blockShardId {
+			return nil, fmt.Errorf("%w: addresses of shard %d are filtered in block of shard %d",
+				rawapitypes.ErrShardMismatch, shardId, blockShardId)
+		}
+		shardId = blockShardId
+
+		block, err := api.eth.GetBlockByHash(ctx, *query.BlockHash, false)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			return nil, fmt.Errorf("block %s not found", query.BlockHash)
+		}
+		filter.FromBlock, filter.ToBlock = block.Number, &block.Number
+	} else {
+		if filter.FromBlock, err = api.resolveBlockNumber(ctx, shardId, query.fromBlock); err != nil {
+			return nil, err
+		}
+		if query.toBlock != nil {
+			toBlock, err := api.resolveBlockNumber(ctx, shardId, query.toBlock)
+			if err != nil {
+				return nil, err
+			}
+			filter.ToBlock = &toBlock
+		}
+	}
+
+	infos, err := api.eth.rawapi.GetLogs(ctx, shardId, filter)
+	if err != nil {
+		return nil, err
+	}
+	logs := make([]*EthLog, len(infos))
+	for i, info := range infos {
+		logs[i] = newEthLog(info.Log, info.BlockId, info.BlockHash, info.TransactionHash,
+			info.TransactionIndex, info.LogIndex)
+	}
+	return logs, nil
+}
+
+// logsShard returns the shard of the addresses of the log filter.
+func (api *EthCompatAPIImpl) logsShard(addresses []types.Address) (types.ShardId, error) {
+	if len(addresses) == 0 {
+		return api.defaultShard, nil
+	}
+	shardId := addresses[0].ShardId()
+	for _, address := range addresses[1:] {
+		if address.ShardId() != shardId {
+			return 0, fmt.Errorf("%w: addresses of shards %d and %d are filtered at once",
+				rawapitypes.ErrShardMismatch, shardId, address.ShardId())
+		}
+	}
+	return shardId, nil
+}
+
+// resolveBlockNumber returns the number of the block, the named blocks and the missing one are the latest block.
+func (api *EthCompatAPIImpl) resolveBlockNumber(
+	ctx context.Context, shardId types.ShardId, number *transport.BlockNumber,
+) (types.BlockNumber, error) {
+	if number != nil && *number >= 0 {
+		return types.BlockNumber(*number), nil
+	}
+	return api.latestBlockNumber(ctx, shardId)
+}
+
+func (api *EthCompatAPIImpl) latestBlockNumber(ctx context.Context, shardId types.ShardId) (types.BlockNumber, error) {
+	block, err := api.eth.GetBlockByNumber(ctx, shardId, transport.LatestBlockNumber, false)
+	if err != nil {
+		return 0, err
+	}
+	return block.Number, nil
+}
+
+// Call implements eth_call. Returns the data returned by the call, or an error if the call fails.
+func (api *EthCompatAPIImpl) Call(
+	ctx context.Context, args EthCallArgs, blockNrOrHash transport.BlockNumberOrHash,
+) (hexutil.Bytes, error) {
+	callArgs, err := args.toCallArgs()
+	if err != nil {
+		return nil, err
+	}
+	res, err := api.eth.Call(ctx, callArgs, blockNrOrHash, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("execution reverted: %s", res.Error)
+	}
+	return res.Data, nil
+}
+
+// EstimateGas implements eth_estimateGas. The estimated fee credit is converted to gas at the maximal base fee.
+func (api *EthCompatAPIImpl) EstimateGas(
+	ctx context.Context, args EthCallArgs, blockNrOrHash *transport.BlockNumberOrHash,
+) (hexutil.Uint64, error) {
+	callArgs, err := args.toCallArgs()
+	if err != nil {
+		return 0, err
+	}
+	block := transport.BlockNumberOrHash(transport.LatestBlock)
+	if blockNrOrHash != nil {
+		block = *blockNrOrHash
+	}
+	res, err := api.eth.EstimateFee(ctx, callArgs, block)
+	if err != nil {
+		return 0, err
+	}
+	if res.MaxBasFee.IsZero() {
+		return 0, errors.New("base fee of the shard is zero")
+	}
+	return hexutil.Uint64(res.FeeCredit.ToGas(res.MaxBasFee)), nil
+}
+
+// SendRawTransaction implements eth_sendRawTransaction for the nil external transactions.
+func (api *EthCompatAPIImpl) SendRawTransaction(ctx context.Context, encoded hexutil.Bytes) (common.Hash, error) {
+	if api.sender == nil {
+		return common.EmptyHash, errEthReadOnly
+	}
+	return api.sender.SendRawTransaction(ctx, encoded)
+}
+
+func (args EthCallArgs) toCallArgs() (CallArgs, error) {
+	if args.To == nil {
+		return CallArgs{}, errEthContractCreationCall
+	}
+	// Input is the newer name of data, it takes precedence if both are set.
+	callArgs := CallArgs{From: args.From, To: *args.To, Data: args.Input}
+	if callArgs.Data == nil {
+		callArgs.Data = args.Data
+	}
+	if args.Gas != nil {
+		callArgs.Fee = types.NewFeePackFromGas(types.Gas(*args.Gas))
+	}
+	if args.Value != nil {
+		value, overflow := types.NewValueFromBig(args.Value.ToInt())
+		if overflow {
+			return CallArgs{}, fmt.Errorf("value %s overflows", args.Value)
+		}
+		callArgs.Value = value
+	}
+	return callArgs, nil
+}
+
+func newEthBlock(shardId types.ShardId, raw *types.RawBlockWithExtractedData, fullTx bool) (*EthBlock, error) {
+	data, err := raw.DecodeSSZ()
+	if err != nil {
+		return nil, err
+	}
+	block := data.Block
+	if block == nil {
+		return nil, nil
+	}
+	hash := block.Hash(shardId)
+
+	transactions := make([]any, len(data.InTransactions))
+	for i, txn := range data.InTransactions {
+		if !fullTx {
+			transactions[i] = txn.Hash()
+			continue
+		}
+		rpcTxn, err := NewRPCInTransaction(txn, data.Receipts[i], types.TransactionIndex(i), hash, block.Id)
+		if err != nil {
+			return nil, err
+		}
+		transactions[i] = newEthTransaction(rpcTxn)
+	}
+
+	return &EthBlock{
+		Number:           hexutil.Uint64(block.Id),
+		Hash:             hash,
+		ParentHash:       block.PrevBlock,
+		Nonce:            make(hexutil.Bytes, 8),
+		Sha3Uncles:       ethEmptyUncleHash,
+		LogsBloom:        block.LogsBloom.Bytes(),
+		TransactionsRoot: block.InTransactionsRoot,
+		StateRoot:        block.SmartContractsRoot,
+		ReceiptsRoot:     block.ReceiptsRoot,
+		ExtraData:        hexutil.Bytes{},
+		GasLimit:         hexutil.Uint64(types.DefaultMaxGasInBlock),
+		GasUsed:          hexutil.Uint64(block.GasUsed),
+		BaseFeePerGas:    hexutil.NewBig(block.BaseFee.ToBig()),
+		Transactions:     transactions,
+		Uncles:           []common.Hash{},
+	}, nil
+}
+
+func newEthTransaction(txn *RPCInTransaction) *EthTransaction {
+	var gas hexutil.Uint64
+	if !txn.MaxFeePerGas.IsZero() {
+		gas = hexutil.Uint64(txn.FeeCredit.ToGas(txn.MaxFeePerGas))
+	}
+	return &EthTransaction{
+		BlockHash:            txn.BlockHash,
+		BlockNumber:          hexutil.Uint64(txn.BlockNumber),
+		TransactionIndex:     txn.Index,
+		Hash:                 txn.Hash,
+		Type:                 ethDynamicFeeTxType,
+		ChainId:              hexutil.Uint64(txn.ChainID),
+		Nonce:                txn.Seqno,
+		From:                 txn.From,
+		To:                   txn.To,
+		Value:                hexutil.NewBig(txn.Value.ToBig()),
+		Gas:                  gas,
+		GasPrice:             hexutil.NewBig(txn.MaxFeePerGas.ToBig()),
+		MaxFeePerGas:         hexutil.NewBig(txn.MaxFeePerGas.ToBig()),
+		MaxPriorityFeePerGas: hexutil.NewBig(txn.MaxPriorityFeePerGas.ToBig()),
+		Input:                txn.Data,
+	}
+}
+
+// newEthReceipt converts the receipt, the transaction may be nil if it's not found.
+func newEthReceipt(receipt *RPCReceipt, txn *RPCInTransaction) *EthReceipt {
+	logs := make([]*EthLog, len(receipt.Logs))
+	for i, log := range receipt.Logs {
+		logs[i] = newEthLog(log.Log, receipt.BlockNumber, receipt.BlockHash, receipt.TxnHash,
+			receipt.TxnIndex, uint64(i))
+	}
+
+	result := &EthReceipt{
+		TransactionHash:  receipt.TxnHash,
+		TransactionIndex: hexutil.Uint64(receipt.TxnIndex),
+		BlockHash:        receipt.BlockHash,
+		BlockNumber:      hexutil.Uint64(receipt.BlockNumber),
+		// The transactions of a block are executed independently, so the gas is not accumulated.
+		CumulativeGasUsed: hexutil.Uint64(receipt.GasUsed),
+		GasUsed:           hexutil.Uint64(receipt.GasUsed),
+		EffectiveGasPrice: hexutil.NewBig(receipt.GasPrice.ToBig()),
+		Logs:              logs,
+		LogsBloom:         receipt.Bloom,
+		Type:              ethDynamicFeeTxType,
+	}
+	if receipt.Success {
+		result.Status = 1
+	}
+	if receipt.ContractAddress != types.EmptyAddress {
+		result.ContractAddress = &receipt.ContractAddress
+	}
+	if txn != nil {
+		result.From, result.To = txn.From, txn.To
+	}
+	return result
+}
+
+func newEthLog(
+	log *types.Log,
+	blockId types.BlockNumber,
+	blockHash common.Hash,
+	txnHash common.Hash,
+	txnIndex types.TransactionIndex,
+	logIndex uint64,
+) *EthLog {
+	return &EthLog{
+		Address:          log.Address,
+		Topics:           log.Topics,
+		Data:             log.Data,
+		BlockNumber:      hexutil.Uint64(blockId),
+		BlockHash:        blockHash,
+		TransactionHash:  txnHash,
+		TransactionIndex: hexutil.Uint64(txnIndex),
+		LogIndex:         hexutil.Uint64(logIndex),
+	}
+}
@@ -0,0 +1,151 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type SuiteEthCompat struct {
+	suite.Suite
+
+	ctx           context.Context
+	db            db.DB
+	api           *EthCompatAPIImpl
+	zeroStateHash common.Hash
+	lastBlockHash common.Hash
+}
+
+func (suite *SuiteEthCompat) SetupSuite() {
+	suite.ctx = context.Background()
+
+	var err error
+	suite.db, err = db.NewBadgerDbInMemory()
+	suite.Require().NoError(err)
+
+	suite.zeroStateHash = execution.GenerateZeroState(suite.T(), types.MainShardId, suite.db).Hash(types.MainShardId)
+	suite.lastBlockHash = suite.zeroStateHash
+	txn := types.NewEmptyTransaction()
+	txn.Data = types.Code("data")
+	suite.lastBlockHash = execution.GenerateBlockFromTransactionsWithoutExecution(suite.T(),
+		types.MainShardId, 1, suite.lastBlockHash, suite.db, txn)
+
+	eth := NewTestEthAPI(suite.ctx, suite.T(), suite.db, 1)
+	suite.api = NewEthCompatAPI(eth.APIImplRo, eth, types.MainShardId)
+}
+
+func (suite *SuiteEthCompat) TearDownSuite() {
+	suite.db.Close()
+}
+
+func (suite *SuiteEthCompat) TestBlockNumber() {
+	number, err := suite.api.BlockNumber(suite.ctx)
+	suite.Require().NoError(err)
+	suite.Equal(hexutil.Uint64(1), number)
+}
+
+func (suite *SuiteEthCompat) TestGetBlock() {
+	block, err := suite.api.GetBlockByNumber(suite.ctx, transport.LatestBlockNumber, false)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(block)
+	suite.Equal(hexutil.Uint64(1), block.Number)
+	suite.Equal(suite.lastBlockHash, block.Hash)
+	suite.Equal(ethEmptyUncleHash, block.Sha3Uncles)
+	suite.Require().Len(block.Transactions, 1)
+	suite.IsType(common.Hash{}, block.Transactions[0])
+
+	block, err = suite.api.GetBlockByHash(suite.ctx, suite.lastBlockHash, true)
+	suite.Require().NoError(err)
+	suite.Require().NotNil(block)
+	suite.Require().Len(block.Transactions, 1)
+	txn, ok := block.Transactions[0].(*EthTransaction)
+	suite.Require().True(ok)
+	suite.Equal(hexutil.Bytes("data"), txn.Input)
+	suite.Equal(hexutil.Uint64(ethDynamicFeeTxType), txn.Type)
+
+	_, err = suite.api.GetBlockByNumber(suite.ctx, transport.PendingBlockNumber, false)
+	suite.Require().ErrorIs(err, errNotImplemented)
+}
+
+func (suite *SuiteEthCompat) TestCallArgs() {
+	_, err := EthCallArgs{}.toCallArgs()
+	suite.Require().ErrorIs(err, errEthContractCreationCall)
+
+	to := types.ShardAndHexToAddress(types.MainShardId, "0x11")
+	data := hexutil.Bytes("data")
+	input := hexutil.Bytes("input")
+	gas := hexutil.Uint64(1000)
+	args, err := EthCallArgs{
+		To:    &to,
+		Gas:   &gas,
+		Value: hexutil.NewBigFromInt64(10),
+		Data:  &data,
+		Input: &input,
+	}.toCallArgs()
+	suite.Require().NoError(err)
+	suite.Equal(to, args.To)
+	suite.Equal(&input, args.Data)
+	suite.Equal(types.NewFeePackFromGas(1000), args.Fee)
+	suite.Equal(types.NewValueFromUint64(10), args.Value)
+}
+
+func (suite *SuiteEthCompat) TestLogsShard() {
+	shardId, err := suite.api.logsShard(nil)
+	suite.Require().NoError(err)
+	suite.Equal(types.MainShardId, shardId)
+
+	shardId, err = suite.api.logsShard([]types.Address{types.ShardAndHexToAddress(1, "0x11")})
+	suite.Require().NoError(err)
+	suite.Equal(types.ShardId(1), shardId)
+
+	_, err = suite.api.logsShard([]types.Address{
+		types.ShardAndHexToAddress(1, "0x11"),
+		types.ShardAndHexToAddress(2, "0x11"),
+	})
+	suite.Require().ErrorIs(err, rawapitypes.ErrShardMismatch)
+}
+
+func (suite *SuiteEthCompat) TestGetLogsByBlockHash() {
+	// The block is looked up in the shard of its hash rather than in the default shard, which is not served.
+	api := NewEthCompatAPI(suite.api.eth, suite.api.sender, 1)
+	query := EthLogQuery{}
+	query.BlockHash = &suite.zeroStateHash
+	_, err := api.GetLogs(suite.ctx, query)
+	suite.Require().NoError(err)
+
+	query.Addresses = []types.Address{types.ShardAndHexToAddress(1, "0x11")}
+	_, err = api.GetLogs(suite.ctx, query)
+	suite.Require().ErrorIs(err, rawapitypes.ErrShardMismatch)
+}
+
+func TestSuiteEthCompat(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(SuiteEthCompat))
+}
+
+func TestEthLogQueryUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	var query EthLogQuery
+	require.NoError(t, json.Unmarshal([]byte(`{"fromBlock":"0x2","toBlock":"latest"}`), &query))
+	require.NotNil(t, query.fromBlock)
+	require.Equal(t, transport.BlockNumber(2), *query.fromBlock)
+	require.NotNil(t, query.toBlock)
+	require.Equal(t, transport.LatestBlockNumber, *query.toBlock)
+
+	query = EthLogQuery{}
+	require.NoError(t, json.Unmarshal([]byte(`{}`), &query))
+	require.Nil(t, query.fromBlock)
+	require.Nil(t, query.toBlock)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -263,6 +264,14 @@ func (s *SuiteEthFilters) TestBlocks() {
 	s.Require().NoError(err)
 }
 
+func (s *SuiteEthFilters) TestSubscribeWithoutNotifications() {
+	_, err := s.api.Subscribe(s.ctx, s.shardId, subscriptionNewHeads, nil)
+	s.Require().ErrorIs(err, transport.ErrNotificationsUnsupported)
+
+	_, err = s.api.Unsubscribe(s.ctx, transport.NewSubscriptionID())
+	s.Require().ErrorIs(err, transport.ErrNotificationsUnsupported)
+}
+
 func TestEthFilters(t *testing.T) {
 	t.Parallel()
 
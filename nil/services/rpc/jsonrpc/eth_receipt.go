@@ -8,7 +8,7 @@ import (
 )
 
 func (api *APIImplRo) GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*RPCReceipt, error) {
-	info, err := api.rawapi.GetInTransactionReceipt(ctx, types.ShardIdFromHash(hash), hash)
+	info, err := api.rawapi.GetReceipt(ctx, types.ShardIdFromHash(hash), hash)
 	if err != nil {
 		return nil, err
 	}
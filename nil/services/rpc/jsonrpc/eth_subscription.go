@@ -0,0 +1,148 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/filters"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+const (
+	subscriptionNewHeads               = "newHeads"
+	subscriptionLogs                   = "logs"
+	subscriptionNewPendingTransactions = "newPendingTransactions"
+
+	subscriptionNotificationMethod = "eth_subscription"
+)
+
+var errUnknownSubscription = errors.New("unknown subscription")
+
+// Subscribe implements eth_subscribe. Creates a subscription to the new blocks, logs or pending transactions
+// of the shard. The events are sent as eth_subscription notifications over the connection.
+func (api *APIImplRo) Subscribe(
+	ctx context.Context,
+	shardId types.ShardId,
+	kind string,
+	params *json.RawMessage,
+) (transport.SubscriptionID, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return "", transport.ErrNotificationsUnsupported
+	}
+
+	// The subscription outlives the request, it's canceled once the notifications are over.
+	subCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	run, err := api.openSubscription(subCtx, shardId, kind, params)
+	if err != nil {
+		cancel()
+		return "", err
+	}
+	id, err := notifier.Subscribe(subscriptionNotificationMethod, func(ctx context.Context, notify func(any) error) error {
+		defer cancel()
+		return run(ctx, notify)
+	})
+	if err != nil {
+		cancel()
+		return "", err
+	}
+	return id, nil
+}
+
+// Unsubscribe implements eth_unsubscribe. Cancels the subscription created over the same connection.
+func (api *APIImplRo) Unsubscribe(ctx context.Context, id transport.SubscriptionID) (bool, error) {
+	notifier, ok := transport.NotifierFromContext(ctx)
+	if !ok {
+		return false, transport.ErrNotificationsUnsupported
+	}
+	return notifier.Unsubscribe(id), nil
+}
+
+type subscriptionRunner = func(ctx context.Context, notify func(any) error) error
+
+func (api *APIImplRo) openSubscription(
+	ctx context.Context,
+	shardId types.ShardId,
+	kind string,
+	params *json.RawMessage,
+) (subscriptionRunner, error) {
+	switch kind {
+	case subscriptionNewHeads:
+		heads, err := api.rawapi.SubscribeNewHeads(ctx, shardId)
+		if err != nil {
+			return nil, err
+		}
+		return forwardNotifications(heads, func(data sszx.SSZEncodedData) (any, error) {
+			block := &types.Block{}
+			if err := block.UnmarshalSSZ(data); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal block: %w", err)
+			}
+			return block, nil
+		}), nil
+	case subscriptionLogs:
+		var query filters.FilterQuery
+		if params != nil {
+			if err := json.Unmarshal(*params, &query); err != nil {
+				return nil, err
+			}
+		}
+		filter := rawapitypes.LogFilter{Addresses: query.Addresses, Topics: query.Topics}
+		logs, err := api.rawapi.SubscribeLogs(ctx, shardId, filter)
+		if err != nil {
+			return nil, err
+		}
+		return forwardNotifications(logs, func(info *rawapitypes.LogInfo) (any, error) {
+			return NewRPCLog(info.Log, info.BlockId), nil
+		}), nil
+	case subscriptionNewPendingTransactions:
+		var fullTx bool
+		if params != nil {
+			if err := json.Unmarshal(*params, &fullTx); err != nil {
+				return nil, err
+			}
+		}
+		txns, err := api.rawapi.SubscribePendingTransactions(ctx, shardId, fullTx)
+		if err != nil {
+			return nil, err
+		}
+		return forwardNotifications(txns, func(pending *rawapitypes.PendingTransaction) (any, error) {
+			if pending.TransactionSSZ == nil {
+				return pending.Hash, nil
+			}
+			txn := &types.Transaction{}
+			if err := txn.UnmarshalSSZ(pending.TransactionSSZ); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal transaction: %w", err)
+			}
+			return NewTransaction(txn), nil
+		}), nil
+	}
+	return nil, fmt.Errorf("%w: %s", errUnknownSubscription, kind)
+}
+
+// forwardNotifications sends the converted events until the channel is closed or ctx is done.
+func forwardNotifications[T any](events <-chan T, convert func(T) (any, error)) subscriptionRunner {
+	return func(ctx context.Context, notify func(any) error) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case event, ok := <-events:
+				if !ok {
+					return nil
+				}
+				result, err := convert(event)
+				if err != nil {
+					return err
+				}
+				if err := notify(result); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
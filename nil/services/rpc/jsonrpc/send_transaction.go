@@ -20,14 +20,21 @@ func (api *APIImpl) SendRawTransaction(ctx context.Context, encoded hexutil.Byte
 	}
 
 	shardId := extTxn.To.ShardId()
-	reason, err := api.rawapi.SendTransaction(ctx, shardId, encoded)
+	// eth_sendRawTransaction keeps replacing the pending transaction with the same seqno on a sufficient fee bump.
+	result, err := api.rawapi.SendTransaction(ctx, shardId, encoded, txnpool.ReplaceIfFeeBumped, "")
 	if err != nil {
 		return common.EmptyHash, err
 	}
 
-	if reason != txnpool.NotSet {
-		log.Err(ErrTransactionDiscarded).Msgf("%s", reason)
-		return common.EmptyHash, fmt.Errorf("%w: %s", ErrTransactionDiscarded, reason)
+	// The transaction that fails the checks is only discarded if the node rejects the invalid transactions,
+	// otherwise it fails in a block, as before the checks.
+	if result.DiscardReason != txnpool.NotSet && !result.Verdict.Passed() {
+		log.Err(ErrTransactionDiscarded).Msgf("%s: %s", result.DiscardReason, result.Verdict.Error)
+		return common.EmptyHash, fmt.Errorf("%w: %s: %w", ErrTransactionDiscarded, result.DiscardReason, result.Verdict.Error)
+	}
+	if result.DiscardReason != txnpool.NotSet {
+		log.Err(ErrTransactionDiscarded).Msgf("%s", result.DiscardReason)
+		return common.EmptyHash, fmt.Errorf("%w: %s", ErrTransactionDiscarded, result.DiscardReason)
 	}
 
 	return extTxn.Hash(), nil
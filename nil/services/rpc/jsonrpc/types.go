@@ -16,6 +16,7 @@ type (
 	Contract       = rpctypes.Contract
 	CallArgs       = rpctypes.CallArgs
 	StateOverrides = rpctypes.StateOverrides
+	BlockOverrides = rpctypes.BlockOverrides
 )
 
 // @component RPCInTransaction rpcInTransaction object "The transaction whose information is requested."
@@ -3,12 +3,18 @@ package jsonrpc
 import (
 	"context"
 
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
 )
 
+// defaultHealthMaxBlockLag is the number of blocks a shard may be behind the network while the node is healthy.
+const defaultHealthMaxBlockLag = 10
+
 // Web3API provides interfaces for the web3_ RPC commands
 type Web3API interface {
 	ClientVersion(_ context.Context) (string, error)
+	Health(ctx context.Context, maxBlockLag *hexutil.Uint64) (*NodeHealth, error)
 }
 
 type Web3APIImpl struct {
@@ -23,7 +29,51 @@ func NewWeb3API(rawApi rawapi.NodeApi) *Web3APIImpl {
 	}
 }
 
+// ShardHealth is the synchronization of a shard followed by the node.
+type ShardHealth struct {
+	ShardId           types.ShardId     `json:"shardId"`
+	Healthy           bool              `json:"healthy"`
+	CurrentBlock      types.BlockNumber `json:"currentBlock"`
+	HighestKnownBlock types.BlockNumber `json:"highestKnownBlock"`
+	Phase             string            `json:"phase,omitempty"`
+	Peers             hexutil.Uint64    `json:"peers"`
+	// Error is set if the status of the shard can't be obtained.
+	Error string `json:"error,omitempty"`
+}
+
+// NodeHealth is healthy if all the shards followed by the node are.
+type NodeHealth struct {
+	Healthy bool           `json:"healthy"`
+	Shards  []*ShardHealth `json:"shards"`
+}
+
 // ClientVersion implements web3_clientVersion. Returns the current client version.
 func (api *Web3APIImpl) ClientVersion(ctx context.Context) (string, error) {
 	return api.rawApi.ClientVersion(ctx)
 }
+
+// Health implements web3_health. Returns whether the node is in sync with the network, i.e., no shard is being
+// synchronized or is more than maxBlockLag blocks behind the highest block received from the peers.
+func (api *Web3APIImpl) Health(ctx context.Context, maxBlockLag *hexutil.Uint64) (*NodeHealth, error) {
+	lag := uint64(defaultHealthMaxBlockLag)
+	if maxBlockLag != nil {
+		lag = uint64(*maxBlockLag)
+	}
+	health, err := api.rawApi.Health(ctx, lag)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &NodeHealth{Healthy: health.Healthy, Shards: make([]*ShardHealth, len(health.Shards))}
+	for i, shard := range health.Shards {
+		shardHealth := &ShardHealth{ShardId: shard.ShardId, Healthy: shard.Healthy, Error: shard.Error}
+		if shard.Status != nil {
+			shardHealth.CurrentBlock = shard.Status.CurrentBlock
+			shardHealth.HighestKnownBlock = shard.Status.HighestKnownBlock
+			shardHealth.Phase = shard.Status.Phase.String()
+			shardHealth.Peers = hexutil.Uint64(shard.Status.Peers)
+		}
+		result.Shards[i] = shardHealth
+	}
+	return result, nil
+}
@@ -0,0 +1,71 @@
+package jsonrpc
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+type testSyncProgress struct {
+	progress rawapitypes.SyncProgress
+}
+
+func (p *testSyncProgress) SyncProgress() rawapitypes.SyncProgress {
+	return p.progress
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	defer database.Close()
+
+	// The shard has no blocks yet, while the peers have announced the block 20.
+	progress := &testSyncProgress{progress: rawapitypes.SyncProgress{
+		Phase:             rawapitypes.SyncPhaseFollowing,
+		HighestKnownBlock: 20,
+		Peers:             2,
+	}}
+	nodeApi := rawapi.NodeApiBuilder(database, nil).
+		WithSyncProgress(types.MainShardId, progress).
+		WithLocalShardApiRo(types.MainShardId).
+		BuildAndReset()
+	api := NewWeb3API(nodeApi)
+
+	t.Run("Lagging", func(t *testing.T) {
+		health, err := api.Health(t.Context(), nil)
+		require.NoError(t, err)
+		require.False(t, health.Healthy)
+		require.Equal(t, []*ShardHealth{{
+			ShardId:           types.MainShardId,
+			Healthy:           false,
+			CurrentBlock:      0,
+			HighestKnownBlock: 20,
+			Phase:             "following",
+			Peers:             2,
+		}}, health.Shards)
+	})
+
+	t.Run("WithinLag", func(t *testing.T) {
+		maxBlockLag := hexutil.Uint64(20)
+		health, err := api.Health(t.Context(), &maxBlockLag)
+		require.NoError(t, err)
+		require.True(t, health.Healthy)
+		require.Len(t, health.Shards, 1)
+		require.True(t, health.Shards[0].Healthy)
+	})
+
+	t.Run("Replaying", func(t *testing.T) {
+		progress.progress = rawapitypes.SyncProgress{Phase: rawapitypes.SyncPhaseReplaying}
+		health, err := api.Health(t.Context(), nil)
+		require.NoError(t, err)
+		require.False(t, health.Healthy)
+		require.Equal(t, "replaying", health.Shards[0].Phase)
+	})
+}
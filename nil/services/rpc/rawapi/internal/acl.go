@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type PeerAcl = rawapitypes.PeerAcl
+
+// AccessControl restricts the raw API methods to the peers of its ACL. The ACL can be replaced at runtime,
+// e.g., by the admin API, the handlers intercepted by AccessControl.Interceptor use the current one.
+type AccessControl struct {
+	acl atomic.Pointer[PeerAcl]
+}
+
+func NewAccessControl(acl PeerAcl) *AccessControl {
+	ac := &AccessControl{}
+	ac.Set(acl)
+	return ac
+}
+
+// Set replaces the ACL, the requests being handled are not affected.
+func (ac *AccessControl) Set(acl PeerAcl) {
+	acl = maps.Clone(acl)
+	ac.acl.Store(&acl)
+}
+
+// Get returns a copy of the current ACL.
+func (ac *AccessControl) Get() PeerAcl {
+	return maps.Clone(*ac.acl.Load())
+}
+
+// Interceptor creates an interceptor rejecting the requests of the peers not allowed by the ACL
+// with rawapitypes.ErrUnauthorized. The requests of unknown peers are rejected as well
+// if the method is restricted. The service protocols are not intercepted. The streams and the subscriptions
+// are checked once they are opened, the replaced ACL doesn't end the open ones.
+func (ac *AccessControl) Interceptor() RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		if isServiceProtocol(protocol) {
+			return next
+		}
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			if !ac.allowed(ctx, protocol) {
+				return nil, rawapitypes.ErrUnauthorized
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+func (ac *AccessControl) allowed(ctx context.Context, protocol network.ProtocolID) bool {
+	peers, ok := findProtocolEntry(*ac.acl.Load(), protocol)
+	if !ok {
+		return true
+	}
+	peerId, ok := network.RequestPeer(ctx)
+	return ok && slices.Contains(peers, peerId)
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"context"
+	"crypto/subtle"
+	"path"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// RequestAuth configures the authentication of the callers of the protected raw API methods.
+// A request is accepted if it carries the shared secret or is sent by one of the allowed peers.
+type RequestAuth struct {
+	// SharedSecret is compared with the token the caller has set with WithAuthToken.
+	SharedSecret string `yaml:"sharedSecret,omitempty"`
+	// AllowedPeers are the peers whose requests are accepted without the secret.
+	AllowedPeers []network.PeerID `yaml:"allowedPeers,omitempty"`
+}
+
+// Enabled reports whether some caller can be authenticated at all.
+func (a RequestAuth) Enabled() bool {
+	return a.SharedSecret != "" || len(a.AllowedPeers) != 0
+}
+
+type (
+	// authTokenKey holds the token sent by the client.
+	authTokenKey struct{}
+	// requestAuthTokenKey holds the token received by the server. It is kept apart from the token
+	// sent by the client, so the calls made while handling a request don't pass the token of the caller on.
+	requestAuthTokenKey struct{}
+)
+
+// WithAuthToken makes the raw API requests sent with the context carry the token,
+// which authenticates the caller to the methods protected with the shared secret.
+func WithAuthToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, authTokenKey{}, token)
+}
+
+func withRequestAuthToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, requestAuthTokenKey{}, token)
+}
+
+// isServiceProtocol reports whether the protocol is not a method of the API but serves the calls of the methods.
+// Such protocols are not restricted: the version handshake discloses nothing, and the fetching of the chunks
+// requires the handle of a response the caller was authorized for.
+func isServiceProtocol(protocol network.ProtocolID) bool {
+	switch path.Base(string(protocol)) {
+	case getApiVersionMethodName, fetchChunkMethodName:
+		return true
+	}
+	return false
+}
+
+// NewAuthInterceptor creates an interceptor rejecting the requests of the callers not authenticated by auth
+// with rawapitypes.ErrUnauthorized. The service protocols are not intercepted.
+func NewAuthInterceptor(auth RequestAuth) RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		if isServiceProtocol(protocol) {
+			return next
+		}
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			if !auth.authenticate(ctx) {
+				return nil, rawapitypes.ErrUnauthorized
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+func (a RequestAuth) authenticate(ctx context.Context) bool {
+	if a.SharedSecret != "" {
+		token, _ := ctx.Value(requestAuthTokenKey{}).(string)
+		if subtle.ConstantTimeCompare([]byte(token), []byte(a.SharedSecret)) == 1 {
+			return true
+		}
+	}
+	if peerId, ok := network.RequestPeer(ctx); ok {
+		return slices.Contains(a.AllowedPeers, peerId)
+	}
+	return false
+}
@@ -0,0 +1,254 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	batchMethodName = "Batch"
+
+	maxBatchSize = 64
+)
+
+var errBatchTooLarge = fmt.Errorf("batch must contain at most %d requests", maxBatchSize)
+
+// makeBatchRequestHandler creates a handler that unpacks a list of sub-requests and dispatches each of them
+// to the request handler of the corresponding method. Responses are returned in the order of the requests.
+// Streaming methods can't be a part of a batch.
+func makeBatchRequestHandler(handlers map[string]network.RequestHandler) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		var batchRequest pb.BatchRequest
+		if err := proto.Unmarshal(request, &batchRequest); err != nil {
+			return packBatchError(fmt.Errorf("failed to unpack Protobuf request: %w", err))
+		}
+		if len(batchRequest.GetRequests()) > maxBatchSize {
+			return packBatchError(errBatchTooLarge)
+		}
+
+		responses := make([]*pb.BatchSubResponse, len(batchRequest.GetRequests()))
+		for i, subRequest := range batchRequest.GetRequests() {
+			responses[i] = handleBatchSubRequest(ctx, handlers, subRequest)
+		}
+		return proto.Marshal(&pb.BatchResponse{
+			Result: &pb.BatchResponse_Data{Data: &pb.BatchResponses{Responses: responses}},
+		})
+	}
+}
+
+func handleBatchSubRequest(
+	ctx context.Context,
+	handlers map[string]network.RequestHandler,
+	subRequest *pb.BatchSubRequest,
+) *pb.BatchSubResponse {
+	handler, ok := handlers[subRequest.GetMethod()]
+	if !ok {
+		return packBatchSubError(fmt.Errorf("method %s not found", subRequest.GetMethod()))
+	}
+	response, err := handler(ctx, subRequest.GetPayload())
+	if err != nil {
+		return packBatchSubError(err)
+	}
+	return &pb.BatchSubResponse{Result: &pb.BatchSubResponse_Payload{Payload: response}}
+}
+
+func packBatchSubError(err error) *pb.BatchSubResponse {
+	return &pb.BatchSubResponse{Result: &pb.BatchSubResponse_Error{Error: new(pb.Error).PackProtoMessage(err)}}
+}
+
+func packBatchError(err error) ([]byte, error) {
+	return proto.Marshal(&pb.BatchResponse{Result: &pb.BatchResponse_Error{Error: new(pb.Error).PackProtoMessage(err)}})
+}
+
+// batchCall is a single API method call to be sent as a part of a batch request.
+type batchCall struct {
+	codec *methodCodec
+	args  []any
+}
+
+// batchCallResult contains the unpacked result of a batchCall.
+type batchCallResult struct {
+	Result any
+	Err    error
+}
+
+func packBatchRequest(calls []batchCall) ([]byte, error) {
+	if len(calls) > maxBatchSize {
+		return nil, errBatchTooLarge
+	}
+
+	batchRequest := &pb.BatchRequest{Requests: make([]*pb.BatchSubRequest, len(calls))}
+	for i, call := range calls {
+		if call.codec.kind != singleResponse {
+			return nil, fmt.Errorf("method %s can't be batched", call.codec.methodName)
+		}
+		payload, err := call.codec.packRequest(call.args...)
+		if err != nil {
+			return nil, err
+		}
+		batchRequest.Requests[i] = &pb.BatchSubRequest{Method: call.codec.methodName, Payload: payload}
+	}
+	return proto.Marshal(batchRequest)
+}
+
+func unpackBatchResponse(calls []batchCall, response []byte) ([]batchCallResult, error) {
+	var batchResponse pb.BatchResponse
+	if err := proto.Unmarshal(response, &batchResponse); err != nil {
+		return nil, fmt.Errorf("failed to unpack Protobuf response: %w", err)
+	}
+
+	switch batchResponse.GetResult().(type) {
+	case *pb.BatchResponse_Error:
+		return nil, batchResponse.GetError().UnpackProtoMessage()
+	case *pb.BatchResponse_Data:
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+
+	subResponses := batchResponse.GetData().GetResponses()
+	if len(subResponses) != len(calls) {
+		return nil, fmt.Errorf("expected %d responses in batch, got %d", len(calls), len(subResponses))
+	}
+
+	results := make([]batchCallResult, len(calls))
+	for i, subResponse := range subResponses {
+		if subResponse.GetError() != nil {
+			results[i].Err = subResponse.GetError().UnpackProtoMessage()
+			continue
+		}
+		results[i].Result, results[i].Err = calls[i].codec.unpackResponse(subResponse.GetPayload())
+	}
+	return results, nil
+}
+
+// doNetworkShardApiBatchRequest sends several calls of the same API to a shard in a single network round trip.
+// The returned error is only set if the whole batch failed, errors of separate calls are returned in the results.
+func doNetworkShardApiBatchRequest(
+	ctx context.Context,
+	networkManager network.Manager,
+	selectPeer PeerSelector,
+	shardId types.ShardId,
+	apiName string,
+	calls []batchCall,
+) ([]batchCallResult, error) {
+	serverPeerId, protocol, err := discoverVersionedProtocol(
+		ctx, networkManager, selectPeer, shardId, apiName, batchMethodName)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBody, err := packBatchRequest(calls)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := sendEnvelopedRequest(ctx, networkManager, serverPeerId, protocol, requestBody)
+	if err != nil {
+		forgetApiVersion(serverPeerId, shardId, apiName)
+		return nil, err
+	}
+	return unpackBatchResponse(calls, response)
+}
+
+// BatchResult is the result of a call added to a batch, it is set once the batch is sent.
+type BatchResult[ResultType any] struct {
+	Result ResultType
+	Err    error
+}
+
+// ShardApiRoBatch collects the calls of the read-only API of a shard and sends them to a peer serving it
+// in a single network round trip, e.g., to read the balance, the code and the seqno of an account at once.
+// It is not safe for concurrent use.
+type ShardApiRoBatch struct {
+	networkManager network.Manager
+	selectPeer     PeerSelector
+	shardId        types.ShardId
+	codec          apiCodec
+
+	calls []batchCall
+	// setResults set the results of the calls of the same index once the batch is sent.
+	setResults []func(batchCallResult)
+}
+
+// NewNetworkShardApiRoBatch creates a batch of the calls of the read-only API of the shard served
+// by the other nodes. The peer the batch is sent to is chosen by peerSelector, the first of the peers
+// serving the shard is used if it is nil.
+func NewNetworkShardApiRoBatch(
+	networkManager network.Manager, shardId types.ShardId, peerSelector PeerSelector,
+) *ShardApiRoBatch {
+	if peerSelector == nil {
+		peerSelector = selectFirstPeer
+	}
+	codec, err := newApiCodec(reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo]())
+	check.PanicIfErr(err)
+	return &ShardApiRoBatch{
+		networkManager: networkManager,
+		selectPeer:     peerSelector,
+		shardId:        shardId,
+		codec:          codec,
+	}
+}
+
+func addBatchCall[ResultType any](batch *ShardApiRoBatch, methodName string, args ...any) *BatchResult[ResultType] {
+	codec, ok := batch.codec[methodName]
+	check.PanicIfNotf(ok, "Codec for method %s not found", methodName)
+
+	result := new(BatchResult[ResultType])
+	batch.calls = append(batch.calls, batchCall{codec: codec, args: args})
+	batch.setResults = append(batch.setResults, func(callResult batchCallResult) {
+		if callResult.Err != nil {
+			result.Err = callResult.Err
+			return
+		}
+		var ok bool
+		result.Result, ok = callResult.Result.(ResultType)
+		check.PanicIfNotf(ok, "unexpected response type: %T", callResult.Result)
+	})
+	return result
+}
+
+func (b *ShardApiRoBatch) GetBalance(
+	address types.Address, blockReference rawapitypes.BlockReference,
+) *BatchResult[types.Value] {
+	return addBatchCall[types.Value](b, "GetBalance", address, blockReference)
+}
+
+func (b *ShardApiRoBatch) GetCode(
+	address types.Address, blockReference rawapitypes.BlockReference,
+) *BatchResult[types.Code] {
+	return addBatchCall[types.Code](b, "GetCode", address, blockReference)
+}
+
+func (b *ShardApiRoBatch) GetAccountMeta(
+	address types.Address, blockReference rawapitypes.BlockReference,
+) *BatchResult[*rawapitypes.AccountMeta] {
+	return addBatchCall[*rawapitypes.AccountMeta](b, "GetAccountMeta", address, blockReference)
+}
+
+// Send sends the calls added since the last Send and sets their results. The returned error is only set
+// if the whole batch failed, the results of the calls are left unset then.
+func (b *ShardApiRoBatch) Send(ctx context.Context) error {
+	calls, setResults := b.calls, b.setResults
+	b.calls, b.setResults = nil, nil
+	if len(calls) == 0 {
+		return nil
+	}
+
+	results, err := doNetworkShardApiBatchRequest(ctx, b.networkManager, b.selectPeer, b.shardId, apiNameRo, calls)
+	if err != nil {
+		return err
+	}
+	for i, result := range results {
+		setResults[i](result)
+	}
+	return nil
+}
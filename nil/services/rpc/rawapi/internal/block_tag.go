@@ -0,0 +1,125 @@
+package internal
+
+import (
+	"context"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type (
+	// ifBlockChangedKey holds the block hash the requests sent by the client are conditional on.
+	ifBlockChangedKey struct{}
+	// responseBlockKey holds the *ResponseBlock the client receives the block hashes of the responses to.
+	responseBlockKey struct{}
+	// resolvedBlockKey holds the *resolvedBlock of the request handled by the server. It is kept apart from
+	// the keys of the client, so the calls made while handling a request are not conditional.
+	resolvedBlockKey struct{}
+)
+
+// WithIfBlockChanged makes the raw API requests sent with the context conditional on the block, e.g.,
+// the one of the previous response, see WithResponseBlock. A request fails with rawapitypes.ErrNotModified
+// instead of getting the data if it still reads the data at the block only. So the clients polling
+// the latest block get short replies until the next block is committed.
+func WithIfBlockChanged(ctx context.Context, blockHash common.Hash) context.Context {
+	return context.WithValue(ctx, ifBlockChangedKey{}, blockHash)
+}
+
+// ResponseBlock receives the hash of the block the data of a response is read at.
+type ResponseBlock struct {
+	mu   sync.Mutex
+	hash common.Hash
+}
+
+// WithResponseBlock makes the raw API responses received with the context report their block hashes to the block.
+func WithResponseBlock(ctx context.Context, block *ResponseBlock) context.Context {
+	return context.WithValue(ctx, responseBlockKey{}, block)
+}
+
+// Hash returns the block hash of the latest response. It is empty if the response doesn't read a single block,
+// e.g., a batch one, or if it is served from the cache of the client.
+func (b *ResponseBlock) Hash() common.Hash {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.hash
+}
+
+func (b *ResponseBlock) set(hash common.Hash) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.hash = hash
+}
+
+// resolvedBlock is the block the data of the response is read at, which is sent in the response envelope.
+// The requests reading several blocks, e.g., the ones resolving the finalized block through the main shard,
+// are not tagged, so the tag always identifies the data of the response.
+type resolvedBlock struct {
+	// ifChanged is the block the request is conditional on, empty if it is not.
+	ifChanged common.Hash
+
+	mu        sync.Mutex
+	hash      common.Hash
+	ambiguous bool
+}
+
+func withResolvedBlock(ctx context.Context, ifChanged common.Hash) (context.Context, *resolvedBlock) {
+	resolved := &resolvedBlock{ifChanged: ifChanged}
+	return context.WithValue(ctx, resolvedBlockKey{}, resolved), resolved
+}
+
+func getResolvedBlock(ctx context.Context) *resolvedBlock {
+	resolved, _ := ctx.Value(resolvedBlockKey{}).(*resolvedBlock)
+	return resolved
+}
+
+// recordResolvedBlock is called once a block reference of the request is resolved.
+func recordResolvedBlock(ctx context.Context, blockHash common.Hash) {
+	if resolved := getResolvedBlock(ctx); resolved != nil {
+		resolved.record(blockHash, false)
+	}
+}
+
+func (b *resolvedBlock) record(hash common.Hash, ambiguous bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case ambiguous:
+		b.ambiguous = true
+	case b.hash.Empty():
+		b.hash = hash
+	case b.hash != hash:
+		b.ambiguous = true
+	}
+}
+
+// blockHash returns the hash of the single block the request reads, empty if there is none.
+func (b *resolvedBlock) blockHash() common.Hash {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.ambiguous {
+		return common.EmptyHash
+	}
+	return b.hash
+}
+
+// notModified checks whether the request is conditional on the single block it has read. It is checked
+// once the request is handled, since the handler may read other blocks after the first one.
+func (b *resolvedBlock) notModified() bool {
+	return !b.ifChanged.Empty() && b.blockHash() == b.ifChanged
+}
+
+// merge records the blocks read by the call whose response is shared by the request, see requestDeduplicator.
+func (b *resolvedBlock) merge(other *resolvedBlock) {
+	other.mu.Lock()
+	hash, ambiguous := other.hash, other.ambiguous
+	other.mu.Unlock()
+
+	if !hash.Empty() || ambiguous {
+		b.record(hash, ambiguous)
+	}
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxPooledBufferSize limits the buffers returned to the pool, so that a single large response
+// doesn't keep its memory after it's written.
+const maxPooledBufferSize = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any {
+		return new([]byte)
+	},
+}
+
+// getBuffer returns an empty scratch buffer, it must be returned with putBuffer once its content is consumed.
+func getBuffer() *[]byte {
+	buf, ok := bufferPool.Get().(*[]byte)
+	check.PanicIfNot(ok)
+	return buf
+}
+
+func putBuffer(buf *[]byte) {
+	if cap(*buf) > maxPooledBufferSize {
+		return
+	}
+	*buf = (*buf)[:0]
+	bufferPool.Put(buf)
+}
+
+// marshalAppend appends the encoding of the message to the buffer. Unlike proto.Marshal,
+// it doesn't allocate if the buffer has enough capacity.
+func marshalAppend(buf []byte, message proto.Message) ([]byte, error) {
+	return proto.MarshalOptions{}.MarshalAppend(buf, message)
+}
+
+// messagePool reuses the Protobuf messages of a type. A message is reset when it's put back,
+// so the values unpacked from it must not refer to the message itself, only to its nested messages and data,
+// which is the case for all the conversion methods of the requests and responses.
+type messagePool struct {
+	pool sync.Pool
+}
+
+func newMessagePool(messageType reflect.Type) *messagePool {
+	return &messagePool{
+		pool: sync.Pool{
+			New: func() any {
+				return reflect.New(messageType).Interface()
+			},
+		},
+	}
+}
+
+func (p *messagePool) get() proto.Message {
+	message, ok := p.pool.Get().(proto.Message)
+	check.PanicIfNot(ok)
+	return message
+}
+
+func (p *messagePool) put(message proto.Message) {
+	proto.Reset(message)
+	p.pool.Put(message)
+}
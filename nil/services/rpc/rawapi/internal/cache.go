@@ -0,0 +1,236 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ResponseCacheConfig configures the cache of the responses that never change for the same request.
+type ResponseCacheConfig struct {
+	// Size is the maximal number of the cached responses, the cache is disabled if it is zero.
+	Size int `yaml:"size,omitempty"`
+	// Ttl is the time a response is kept for, the responses are only evicted by the newer ones if it is zero.
+	Ttl time.Duration `yaml:"ttl,omitempty"`
+
+	// immutable replaces immutableResponses, e.g., to cache the methods of the test APIs.
+	immutable map[string]immutableResponseCheck
+}
+
+// immutableResponseCheck decides whether the result of the method stays the same for the same arguments.
+type immutableResponseCheck func(args []any, result any) bool
+
+// immutableResponses are the checks of the methods whose responses are cached by default.
+// Only the responses of the methods listed here are cached.
+var immutableResponses = map[string]immutableResponseCheck{
+	"GetBlockHeader":             isBlockReferencedByHash(0),
+	"GetFullBlockData":           isBlockReferencedByHash(0),
+	"GetBlockTransactionCount":   isBlockReferencedByHash(0),
+	"GetBlockFinalitySignatures": isBlockReferencedByHash(0),
+	"GetCode":                    isBlockReferencedByHash(1),
+	"GetReceipt": func(_ []any, result any) bool {
+		receipt, ok := result.(*rawapitypes.ReceiptInfo)
+		return ok && isReceiptFinalized(receipt)
+	},
+}
+
+// isBlockReferencedByHash returns the check of the block reference argument, the data of a block
+// referenced by hash can't change.
+func isBlockReferencedByHash(argIndex int) immutableResponseCheck {
+	return func(args []any, _ any) bool {
+		blockReference, ok := args[argIndex].(rawapitypes.BlockReference)
+		return ok && blockReference.Type() == rawapitypes.HashBlockReference
+	}
+}
+
+// isReceiptFinalized checks that the receipt and the receipts of all the transactions it produced
+// are included in the main chain, so they won't change anymore.
+func isReceiptFinalized(receipt *rawapitypes.ReceiptInfo) bool {
+	if receipt == nil || !receipt.IncludedInMain || receipt.Temporary || len(receipt.ReceiptSSZ) == 0 {
+		return false
+	}
+	if len(receipt.OutReceipts) != len(receipt.OutTransactions) {
+		return false
+	}
+	for _, outReceipt := range receipt.OutReceipts {
+		if !isReceiptFinalized(outReceipt) {
+			return false
+		}
+	}
+	return true
+}
+
+type responseCacheMetrics struct {
+	hits   telemetry.Counter
+	misses telemetry.Counter
+}
+
+var getResponseCacheMetrics = sync.OnceValue(func() *responseCacheMetrics {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/rpc/rawapi")
+	return &responseCacheMetrics{
+		hits:   telemetry.Int64Counter(meter, "response_cache_hits"),
+		misses: telemetry.Int64Counter(meter, "response_cache_misses"),
+	}
+})
+
+// responseCache keeps the packed responses of the immutable data by the packed requests.
+// The same cache is used by the servers and by the clients, which are told apart in the metrics.
+type responseCache struct {
+	responses *expirable.LRU[string, []byte]
+	immutable map[string]immutableResponseCheck
+	metrics   *responseCacheMetrics
+	option    metric.MeasurementOption
+}
+
+// newResponseCache returns nil if the cache is disabled.
+func newResponseCache(cfg ResponseCacheConfig, shardId types.ShardId, side string) *responseCache {
+	if cfg.Size <= 0 {
+		return nil
+	}
+	immutable := cfg.immutable
+	if immutable == nil {
+		immutable = immutableResponses
+	}
+	return &responseCache{
+		responses: expirable.NewLRU[string, []byte](cfg.Size, nil, cfg.Ttl),
+		immutable: immutable,
+		metrics:   getResponseCacheMetrics(),
+		option:    telattr.With(telattr.ShardId(shardId), telattr.Type(side)),
+	}
+}
+
+// committedBlocksSource is implemented by the local APIs of the shards, see NewBlocksSource.
+type committedBlocksSource interface {
+	// subscribeCommittedBlocks returns nil if the node doesn't commit the blocks of the shard.
+	subscribeCommittedBlocks(ctx context.Context) <-chan types.BlockNumber
+}
+
+// purgeOnResets drops the cached responses once the chain of the shard is reset, see NewBlocksSource,
+// until ctx is done, e.g., the receipts of the dropped blocks are not finalized anymore.
+func (c *responseCache) purgeOnResets(ctx context.Context, blocks <-chan types.BlockNumber) {
+	var last types.BlockNumber
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case blockId := <-blocks:
+			if blockId < last {
+				c.responses.Purge()
+			}
+			last = blockId
+		}
+	}
+}
+
+// getOrLoad returns the cached response or the loaded one, which is cached if it is successful and immutable.
+// The arguments of the method are only unpacked when the loaded response has to be checked.
+func (c *responseCache) getOrLoad(
+	ctx context.Context,
+	codec *methodCodec,
+	request []byte,
+	unpackArgs func() ([]any, error),
+	load func() ([]byte, error),
+) ([]byte, error) {
+	if c == nil {
+		return load()
+	}
+	isImmutable, ok := c.immutable[codec.methodName]
+	if !ok {
+		return load()
+	}
+
+	key := responseKey(ctx, codec.methodName, request)
+	methodOption := metric.WithAttributes(telattr.RpcMethod(codec.methodName))
+	if response, ok := c.responses.Get(key); ok {
+		c.metrics.hits.Add(ctx, 1, c.option, methodOption)
+		return response, nil
+	}
+	c.metrics.misses.Add(ctx, 1, c.option, methodOption)
+
+	response, err := load()
+	if err != nil {
+		return nil, err
+	}
+	result, err := codec.unpackResponse(response)
+	if err != nil {
+		// The error responses are not cached, the data may appear later.
+		return response, nil
+	}
+	args, err := unpackArgs()
+	if err == nil && isImmutable(args, result) {
+		c.responses.Add(key, response)
+	}
+	return response, nil
+}
+
+func (c *responseCache) wrapRequestHandler(codec *methodCodec, handler network.RequestHandler) network.RequestHandler {
+	if c == nil {
+		return handler
+	}
+	if _, ok := c.immutable[codec.methodName]; !ok {
+		return handler
+	}
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		return c.getOrLoad(ctx, codec, request,
+			func() ([]any, error) {
+				values, err := codec.unpackRequest(request)
+				if err != nil {
+					return nil, err
+				}
+				args := make([]any, len(values))
+				for i, value := range values {
+					args[i] = value.Interface()
+				}
+				return args, nil
+			},
+			func() ([]byte, error) {
+				return handler(ctx, request)
+			})
+	}
+}
+
+// shardApiRequestPerformerCached serves the requests of the immutable data from the cache
+// before sending them with the wrapped performer.
+type shardApiRequestPerformerCached struct {
+	shardApiRequestPerformer
+
+	cache *responseCache
+}
+
+var _ shardApiRequestPerformer = (*shardApiRequestPerformerCached)(nil)
+
+func withResponseCache(performer shardApiRequestPerformer, cfg ResponseCacheConfig) shardApiRequestPerformer {
+	cache := newResponseCache(cfg, performer.shardId(), "client")
+	if cache == nil {
+		return performer
+	}
+	return &shardApiRequestPerformerCached{shardApiRequestPerformer: performer, cache: cache}
+}
+
+func (api *shardApiRequestPerformerCached) doApiRequest(
+	ctx context.Context, codec *methodCodec, args ...any,
+) ([]byte, error) {
+	if _, ok := api.cache.immutable[codec.methodName]; !ok {
+		return api.shardApiRequestPerformer.doApiRequest(ctx, codec, args...)
+	}
+
+	request, err := codec.packRequest(args...)
+	if err != nil {
+		return nil, err
+	}
+	return api.cache.getOrLoad(ctx, codec, request,
+		func() ([]any, error) {
+			return args, nil
+		},
+		func() ([]byte, error) {
+			return api.shardApiRequestPerformer.doApiRequest(ctx, codec, args...)
+		})
+}
@@ -0,0 +1,212 @@
+package internal
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const listSupportedMethodsMethodName = "ListSupportedMethods"
+
+// SupportedMethod describes a method of an API of a shard served by a node, see ListSupportedMethods.
+type SupportedMethod struct {
+	// Api is the name of the API of the method, e.g., "rawapi_ro".
+	Api    string
+	Method string
+	// ProtocolIds are the IDs of all the protocols serving the method.
+	ProtocolIds []network.ProtocolID
+	// Versions are the API versions the method is served in, the newest one first. They are empty for the methods
+	// served only by the IDs without a version, e.g., for the handshake.
+	Versions []string
+	// ReadOnly is set if the API of the method only reads the data.
+	ReadOnly bool
+	// MaxRequestSize is the maximal size of a request in bytes, zero if it is not limited.
+	MaxRequestSize int
+	// Stream is set if the method streams the results or is a subscription, so it is served over a stream.
+	Stream bool
+	// CurrentMethod is the current name of the method if Method is a deprecated one, see deprecatedMethodNames.
+	CurrentMethod string
+	// Json is set if the method is also served in the JSON form, see RequestHandlersConfig.EnableJsonCodec.
+	Json bool
+}
+
+// isReadOnlyApi reports whether the methods of the API only read the data, see servedApis.
+func isReadOnlyApi(apiName string) bool {
+	for _, served := range servedApis {
+		if served.name == apiName {
+			return served.readOnly
+		}
+	}
+	return false
+}
+
+// parseMethodProtocolId splits the ID of the protocol of a method relative to the prefix of its API.
+// The version is empty for the ID without a version segment.
+func parseMethodProtocolId(protocol string) (methodName string, version string, json bool) {
+	protocol, json = strings.CutSuffix(protocol, jsonProtocolSuffix)
+	version, methodName, ok := strings.Cut(protocol, "/")
+	if !ok {
+		return protocol, "", json
+	}
+	return methodName, version, json
+}
+
+// describeSupportedMethods lists the methods of the API by the IDs of the protocols serving them.
+// The protocols without a version add no version, since the legacy one is also served by its versioned IDs.
+func describeSupportedMethods(
+	requestProtocols []network.ProtocolID,
+	streamProtocols []network.ProtocolID,
+	shardId types.ShardId,
+	apiName string,
+	cfg RequestHandlersConfig,
+) *pb.SupportedMethods {
+	readOnly := isReadOnlyApi(apiName)
+	currentMethodNames := make(map[string]string)
+	for methodName, deprecatedNames := range deprecatedMethodNames {
+		for _, deprecatedName := range deprecatedNames {
+			currentMethodNames[deprecatedName] = methodName
+		}
+	}
+
+	prefix := string(makeProtocolId(shardId, apiName, ""))
+	methods := make(map[string]*pb.SupportedMethod)
+	add := func(protocol network.ProtocolID, stream bool) {
+		methodName, version, json := parseMethodProtocolId(strings.TrimPrefix(string(protocol), prefix))
+		method, ok := methods[methodName]
+		if !ok {
+			currentName := currentMethodNames[methodName]
+			// The interceptors see the requests by the deprecated names as the ones of the current name.
+			limit, _ := findProtocolEntry(
+				cfg.RequestSizeLimits, makeProtocolId(shardId, apiName, cmp.Or(currentName, methodName)))
+			method = &pb.SupportedMethod{
+				Method:         methodName,
+				ReadOnly:       readOnly,
+				MaxRequestSize: uint64(max(limit, 0)),
+				CurrentMethod:  currentName,
+			}
+			methods[methodName] = method
+		}
+		method.ProtocolIds = append(method.ProtocolIds, string(protocol))
+		method.Stream = method.Stream || stream
+		switch {
+		case json:
+			method.Json = true
+		case version != "" && !slices.Contains(method.Versions, version):
+			method.Versions = append(method.Versions, version)
+		}
+	}
+	for _, protocol := range requestProtocols {
+		add(protocol, false)
+	}
+	for _, protocol := range streamProtocols {
+		add(protocol, true)
+	}
+
+	supported := &pb.SupportedMethods{Methods: make([]*pb.SupportedMethod, 0, len(methods))}
+	for _, methodName := range slices.Sorted(maps.Keys(methods)) {
+		method := methods[methodName]
+		slices.Sort(method.ProtocolIds)
+		slices.SortFunc(method.Versions, func(a, b string) int {
+			return cmp.Compare(slices.Index(supportedApiVersions, a), slices.Index(supportedApiVersions, b))
+		})
+		supported.Methods = append(supported.Methods, method)
+	}
+	return supported
+}
+
+func makeListSupportedMethodsRequestHandler(methods *pb.SupportedMethods) network.RequestHandler {
+	response, err := proto.Marshal(
+		&pb.ListSupportedMethodsResponse{Result: &pb.ListSupportedMethodsResponse_Data{Data: methods}})
+	check.PanicIfErr(err)
+	return func(context.Context, []byte) ([]byte, error) {
+		return response, nil
+	}
+}
+
+func packListSupportedMethodsError(err error) []byte {
+	response, packErr := proto.Marshal(&pb.ListSupportedMethodsResponse{
+		Result: &pb.ListSupportedMethodsResponse_Error{Error: new(pb.Error).PackProtoMessage(err)},
+	})
+	check.PanicIfErr(packErr)
+	return response
+}
+
+// listApiMethods returns the methods of the API of the shard served by the peer.
+func listApiMethods(
+	ctx context.Context,
+	networkManager network.Manager,
+	peerId network.PeerID,
+	shardId types.ShardId,
+	apiName string,
+) ([]SupportedMethod, error) {
+	request, err := proto.Marshal(&pb.ListSupportedMethodsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	response, err := networkManager.SendRequestAndGetResponse(
+		ctx, peerId, makeProtocolId(shardId, apiName, listSupportedMethodsMethodName), request)
+	if err != nil {
+		return nil, err
+	}
+
+	var methodsResponse pb.ListSupportedMethodsResponse
+	if err := proto.Unmarshal(response, &methodsResponse); err != nil {
+		return nil, fmt.Errorf("failed to unpack Protobuf response: %w", err)
+	}
+	switch methodsResponse.GetResult().(type) {
+	case *pb.ListSupportedMethodsResponse_Error:
+		return nil, methodsResponse.GetError().UnpackProtoMessage()
+	case *pb.ListSupportedMethodsResponse_Data:
+		methods := make([]SupportedMethod, 0, len(methodsResponse.GetData().GetMethods()))
+		for _, method := range methodsResponse.GetData().GetMethods() {
+			protocolIds := make([]network.ProtocolID, len(method.GetProtocolIds()))
+			for i, protocol := range method.GetProtocolIds() {
+				protocolIds[i] = network.ProtocolID(protocol)
+			}
+			methods = append(methods, SupportedMethod{
+				Api:            apiName,
+				Method:         method.GetMethod(),
+				ProtocolIds:    protocolIds,
+				Versions:       method.GetVersions(),
+				ReadOnly:       method.GetReadOnly(),
+				MaxRequestSize: int(method.GetMaxRequestSize()),
+				Stream:         method.GetStream(),
+				CurrentMethod:  method.GetCurrentMethod(),
+				Json:           method.GetJson(),
+			})
+		}
+		return methods, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// ListSupportedMethods returns the methods of all the APIs of the shard served by the peer, so that the clients
+// can tell what the peer supports instead of probing the methods and interpreting the failures. The APIs of the peer
+// are only listed if it serves ListSupportedMethods for them, the nodes not supporting the listing return nothing.
+func ListSupportedMethods(
+	ctx context.Context, networkManager network.Manager, peerId network.PeerID, shardId types.ShardId,
+) ([]SupportedMethod, error) {
+	var methods []SupportedMethod
+	for _, served := range servedApis {
+		protocol := makeProtocolId(shardId, served.name, listSupportedMethodsMethodName)
+		if !slices.Contains(networkManager.GetPeersForProtocol(protocol), peerId) {
+			continue
+		}
+		apiMethods, err := listApiMethods(ctx, networkManager, peerId, shardId, served.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list methods of %s: %w", served.name, err)
+		}
+		methods = append(methods, apiMethods...)
+	}
+	return methods, nil
+}
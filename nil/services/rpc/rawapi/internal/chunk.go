@@ -0,0 +1,230 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/google/uuid"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	fetchChunkMethodName = "FetchChunk"
+
+	// defaultChunkSize is the maximal size of a response sent at once. The larger responses are stored
+	// by the server and fetched by chunks, so a dropped stream doesn't require to repeat the whole request.
+	defaultChunkSize = 1024 * 1024
+
+	// The stored responses are expected to be fetched right away, so only a few of them are kept for a short time.
+	// The store is shared by the APIs of all the shards of the node, the responses not fitting it are sent at once.
+	maxStoredResponses     = 64
+	maxStoredResponsesSize = 64 * 1024 * 1024
+	storedResponseTtl      = time.Minute
+
+	// maxChunkFetchAttempts is the number of times a chunk is requested before the fetching fails,
+	// the delay before the next attempt starts with chunkFetchBackoff and doubles after every one.
+	maxChunkFetchAttempts = 3
+	chunkFetchBackoff     = 100 * time.Millisecond
+)
+
+var (
+	errChunkedResponseNotFound = fmt.Errorf("chunked response %w", rawapitypes.ErrNotFound)
+	errChunkOutOfRange         = rawapitypes.NewInvalidArgumentError(errors.New("chunk offset is out of range"))
+	errEmptyChunk              = errors.New("empty chunk of response")
+)
+
+func chunkSize(size int) int {
+	if size == 0 {
+		return defaultChunkSize
+	}
+	return size
+}
+
+type storedResponse struct {
+	data      []byte
+	expiresAt time.Time
+	// peerId is the peer the response was sent to, only it can fetch the chunks.
+	peerId network.PeerID
+}
+
+// chunkStore keeps the large responses until they are fetched by chunks.
+// The expired responses are dropped lazily, when they are requested or evicted by the new ones.
+type chunkStore struct {
+	// mu guards the total size of the responses, the cache is safe for concurrent use by itself.
+	mu        sync.Mutex
+	size      int
+	responses *lru.Cache[string, *storedResponse]
+}
+
+func newChunkStore() *chunkStore {
+	s := &chunkStore{}
+	var err error
+	s.responses, err = lru.NewWithEvict(maxStoredResponses, func(_ string, response *storedResponse) {
+		s.size -= len(response.data)
+	})
+	check.PanicIfErr(err)
+	return s
+}
+
+// put stores the response to the peer and returns its handle.
+// The response is not stored if it is larger than the store.
+func (s *chunkStore) put(peerId network.PeerID, data []byte) (string, bool) {
+	if len(data) > maxStoredResponsesSize {
+		return "", false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for s.size+len(data) > maxStoredResponsesSize && s.responses.Len() > 0 {
+		s.responses.RemoveOldest()
+	}
+	handle := uuid.NewString()
+	s.responses.Add(handle, &storedResponse{data: data, expiresAt: time.Now().Add(storedResponseTtl), peerId: peerId})
+	s.size += len(data)
+	return handle, true
+}
+
+// get returns the response stored for the peer, the responses to the other peers are not found.
+func (s *chunkStore) get(peerId network.PeerID, handle string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response, ok := s.responses.Get(handle)
+	if !ok || response.peerId != peerId {
+		return nil, false
+	}
+	if time.Now().After(response.expiresAt) {
+		s.responses.Remove(handle)
+		return nil, false
+	}
+	return response.data, true
+}
+
+// makeFetchChunkRequestHandler creates a handler sending the part of a stored response.
+// The chunks are limited by the chunk size regardless of the length requested by the client.
+func makeFetchChunkRequestHandler(chunks *chunkStore, maxChunkSize int) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		var chunkRequest pb.FetchChunkRequest
+		if err := proto.Unmarshal(request, &chunkRequest); err != nil {
+			return nil, rawapitypes.NewInvalidArgumentError(
+				fmt.Errorf("failed to unpack Protobuf request: %w", err))
+		}
+
+		peerId, _ := network.RequestPeer(ctx)
+		data, ok := chunks.get(peerId, chunkRequest.GetHandle())
+		if !ok {
+			return nil, errChunkedResponseNotFound
+		}
+		offset := chunkRequest.GetOffset()
+		if offset >= uint64(len(data)) {
+			return nil, errChunkOutOfRange
+		}
+		length := uint64(maxChunkSize)
+		if chunkRequest.GetLength() > 0 {
+			length = min(length, chunkRequest.GetLength())
+		}
+		end := min(offset+length, uint64(len(data)))
+		return proto.Marshal(&pb.FetchChunkResponse{Result: &pb.FetchChunkResponse_Data{Data: data[offset:end]}})
+	}
+}
+
+func packFetchChunkError(err error) []byte {
+	response, packErr := proto.Marshal(
+		&pb.FetchChunkResponse{Result: &pb.FetchChunkResponse_Error{Error: new(pb.Error).PackProtoMessage(err)}})
+	check.PanicIfErr(packErr)
+	return response
+}
+
+// fetchChunkedResponse fetches the response stored by the server. A chunk that failed to arrive
+// (e.g., because the stream was dropped) is requested again from the same offset after a backoff,
+// the errors returned by the server are not retried.
+func fetchChunkedResponse(
+	ctx context.Context,
+	networkManager network.Manager,
+	peerId network.PeerID,
+	protocol network.ProtocolID,
+	chunked *pb.ChunkedResponse,
+) ([]byte, error) {
+	if chunked.GetTotalSize() > maxDecompressedSize {
+		return nil, errDecompressedSizeExceeded
+	}
+	fetchProtocol := network.ProtocolID(path.Join(path.Dir(string(protocol)), fetchChunkMethodName))
+
+	response := make([]byte, 0, chunked.GetTotalSize())
+	for uint64(len(response)) < chunked.GetTotalSize() {
+		offset := uint64(len(response))
+		chunk, err := fetchChunk(ctx, networkManager, peerId, fetchProtocol, &pb.FetchChunkRequest{
+			Handle: chunked.GetHandle(),
+			Offset: offset,
+			Length: chunked.GetTotalSize() - offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch chunk at offset %d: %w", offset, err)
+		}
+		if len(chunk) == 0 {
+			return nil, errEmptyChunk
+		}
+		if offset+uint64(len(chunk)) > chunked.GetTotalSize() {
+			return nil, fmt.Errorf("chunked response exceeds its size %d", chunked.GetTotalSize())
+		}
+		response = append(response, chunk...)
+	}
+	return response, nil
+}
+
+func fetchChunk(
+	ctx context.Context,
+	networkManager network.Manager,
+	peerId network.PeerID,
+	protocol network.ProtocolID,
+	request *pb.FetchChunkRequest,
+) ([]byte, error) {
+	requestBody, err := proto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := chunkFetchBackoff
+	for attempt := 1; ; attempt++ {
+		var response []byte
+		response, err = networkManager.SendRequestAndGetResponse(ctx, peerId, protocol, requestBody)
+		if err == nil {
+			return unpackFetchChunkResponse(response)
+		}
+		if attempt == maxChunkFetchAttempts || ctx.Err() != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(backoff):
+			backoff *= 2
+		}
+	}
+}
+
+func unpackFetchChunkResponse(response []byte) ([]byte, error) {
+	var chunkResponse pb.FetchChunkResponse
+	if err := proto.Unmarshal(response, &chunkResponse); err != nil {
+		return nil, fmt.Errorf("failed to unpack Protobuf response: %w", err)
+	}
+
+	switch chunkResponse.GetResult().(type) {
+	case *pb.FetchChunkResponse_Error:
+		return nil, chunkResponse.GetError().UnpackProtoMessage()
+	case *pb.FetchChunkResponse_Data:
+		return chunkResponse.GetData(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestChunkStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Peer", func(t *testing.T) {
+		t.Parallel()
+
+		chunks := newChunkStore()
+		handle, ok := chunks.put("peer-a", []byte{1, 2, 3})
+		require.True(t, ok)
+
+		data, ok := chunks.get("peer-a", handle)
+		require.True(t, ok)
+		require.Equal(t, []byte{1, 2, 3}, data)
+
+		// The handle is not enough to fetch the response sent to another peer.
+		_, ok = chunks.get("peer-b", handle)
+		require.False(t, ok)
+		_, ok = chunks.get("", handle)
+		require.False(t, ok)
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		t.Parallel()
+
+		chunks := newChunkStore()
+		_, ok := chunks.put("peer-a", make([]byte, maxStoredResponsesSize+1))
+		require.False(t, ok)
+
+		// The oldest responses are evicted to fit the new ones.
+		data := make([]byte, maxStoredResponsesSize/2+1)
+		first, ok := chunks.put("peer-a", data)
+		require.True(t, ok)
+		second, ok := chunks.put("peer-b", data)
+		require.True(t, ok)
+
+		_, ok = chunks.get("peer-a", first)
+		require.False(t, ok)
+		_, ok = chunks.get("peer-b", second)
+		require.True(t, ok)
+		require.LessOrEqual(t, chunks.size, maxStoredResponsesSize)
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		t.Parallel()
+
+		chunks := newChunkStore()
+		handle, ok := chunks.put("peer-a", []byte{1, 2, 3})
+		require.True(t, ok)
+		handler := makeFetchChunkRequestHandler(chunks, 2)
+		request, err := proto.Marshal(&pb.FetchChunkRequest{Handle: handle})
+		require.NoError(t, err)
+
+		response, err := handler(network.WithRequestPeer(t.Context(), "peer-a"), request)
+		require.NoError(t, err)
+		chunk, err := unpackFetchChunkResponse(response)
+		require.NoError(t, err)
+		require.Equal(t, []byte{1, 2}, chunk)
+
+		_, err = handler(network.WithRequestPeer(t.Context(), "peer-b"), request)
+		require.ErrorIs(t, err, errChunkedResponseNotFound)
+	})
+}
@@ -9,13 +9,17 @@ import (
 	"github.com/NilFoundation/nil/nil/common/check"
 )
 
-type doApiRequestFunction func(ctx context.Context, codec *methodCodec, args ...any) ([]byte, error)
+type (
+	doApiRequestFunction      func(ctx context.Context, codec *methodCodec, args ...any) ([]byte, error)
+	doApiSubscriptionFunction func(ctx context.Context, codec *methodCodec, args ...any) (<-chan []byte, error)
+)
 
 type shardApiRequestPerformer interface {
 	shardApiBase
 
 	apiCodec() apiCodec
 	doApiRequest(ctx context.Context, codec *methodCodec, args ...any) ([]byte, error)
+	doApiSubscription(ctx context.Context, codec *methodCodec, args ...any) (<-chan []byte, error)
 }
 
 func sendRequestAndGetResponseWithCallerMethodName[ResponseType any](
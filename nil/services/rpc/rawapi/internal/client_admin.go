@@ -0,0 +1,106 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type shardApiClientAdmin struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiAdmin = (*shardApiClientAdmin)(nil)
+
+func constructShardApiClientAdmin(performer shardApiRequestPerformer) *shardApiClientAdmin {
+	return &shardApiClientAdmin{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+// NewNetworkAdminApiClient creates a client of the admin API of the node with the given peer ID.
+// The requests are authenticated by the peer ID of the network manager or by the token set with WithAuthToken.
+func NewNetworkAdminApiClient(networkManager network.Manager, peerId network.PeerID) AdminApi {
+	client, err := newShardApiClientNetwork[shardApiClientAdmin, shardApiAdmin, NetworkTransportProtocolAdmin](
+		constructShardApiClientAdmin, types.MainShardId, apiNameAdmin, networkManager, selectPeer(peerId))
+	check.PanicIfErr(err)
+	return client
+}
+
+// selectPeer makes the requests go to the given peer only, e.g., to manage that very node.
+func selectPeer(peerId network.PeerID) PeerSelector {
+	return func(peers []network.PeerID) (network.PeerID, error) {
+		if !slices.Contains(peers, peerId) {
+			return "", fmt.Errorf("peer %s doesn't serve the API", peerId)
+		}
+		return peerId, nil
+	}
+}
+
+func (api *shardApiClientAdmin) SetLogLevel(ctx context.Context, level string) (string, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[string](ctx, api, "SetLogLevel", level)
+}
+
+func (api *shardApiClientAdmin) DisconnectPeer(ctx context.Context, peerId network.PeerID) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "DisconnectPeer", peerId)
+}
+
+func (api *shardApiClientAdmin) AddPeer(ctx context.Context, addr network.AddrInfo) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "AddPeer", addr)
+}
+
+func (api *shardApiClientAdmin) RemovePeer(ctx context.Context, peerId network.PeerID) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "RemovePeer", peerId)
+}
+
+func (api *shardApiClientAdmin) ProtectPeer(
+	ctx context.Context, peerId network.PeerID, tag string, protect bool,
+) (bool, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[bool](ctx, api, "ProtectPeer", peerId, tag, protect)
+}
+
+func (api *shardApiClientAdmin) SetPeerTag(
+	ctx context.Context, peerId network.PeerID, tag string, value int64,
+) (int64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[int64](ctx, api, "SetPeerTag", peerId, tag, value)
+}
+
+func (api *shardApiClientAdmin) FlushCaches(ctx context.Context) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "FlushCaches")
+}
+
+func (api *shardApiClientAdmin) TriggerSnapshot(ctx context.Context) (string, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[string](ctx, api, "TriggerSnapshot")
+}
+
+func (api *shardApiClientAdmin) SetPeerAcl(ctx context.Context, acl rawapitypes.PeerAcl) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "SetPeerAcl", acl)
+}
+
+func (api *shardApiClientAdmin) GetPeerAcl(ctx context.Context) (rawapitypes.PeerAcl, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[rawapitypes.PeerAcl](ctx, api, "GetPeerAcl")
+}
+
+func (api *shardApiClientAdmin) GetPeerUsage(ctx context.Context) ([]rawapitypes.PeerUsage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]rawapitypes.PeerUsage](ctx, api, "GetPeerUsage")
+}
+
+func (api *shardApiClientAdmin) GetPeers(ctx context.Context) ([]network.PeerInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]network.PeerInfo](ctx, api, "GetPeers")
+}
+
+func (api *shardApiClientAdmin) GetPeerInfo(ctx context.Context, peerId network.PeerID) (*network.PeerInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*network.PeerInfo](ctx, api, "GetPeerInfo", peerId)
+}
+
+func (api *shardApiClientAdmin) GetBandwidthByProtocol(
+	ctx context.Context,
+) (map[network.ProtocolID]network.BandwidthStats, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[map[network.ProtocolID]network.BandwidthStats](
+		ctx, api, "GetBandwidthByProtocol")
+}
@@ -0,0 +1,249 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/sync/singleflight"
+)
+
+// newHeadsResubscribeDelay is the time the latest responses aren't cached for after the subscription
+// to the new heads has failed, before it is retried.
+const newHeadsResubscribeDelay = time.Second
+
+// CachingClientConfig configures the client of the read-only API caching the responses locally.
+type CachingClientConfig struct {
+	// PeerSelector chooses the peer a request is sent to, the first of the peers serving the shard is used if it is nil.
+	PeerSelector PeerSelector `yaml:"-"`
+	// ResponseCache configures the cache of the immutable responses, e.g., of the blocks referenced by hash.
+	ResponseCache ResponseCacheConfig `yaml:"responseCache,omitempty"`
+	// LatestCacheSize is the maximal number of the cached responses of the requests referencing the latest block.
+	// They are dropped on every new head of the shard. The cache is disabled if it is zero.
+	LatestCacheSize int `yaml:"latestCacheSize,omitempty"`
+	// DisableCoalescing makes the identical concurrent requests be sent separately.
+	DisableCoalescing bool `yaml:"disableCoalescing,omitempty"`
+}
+
+// DefaultCachingClientConfig returns the configuration suitable for the services embedding the client.
+func DefaultCachingClientConfig() CachingClientConfig {
+	return CachingClientConfig{
+		ResponseCache:   ResponseCacheConfig{Size: 4096, Ttl: time.Hour},
+		LatestCacheSize: 1024,
+	}
+}
+
+// NewCachingShardApiClient creates a client of the read-only API of the shard served by the other nodes,
+// which sends the identical concurrent requests once and caches the responses. The immutable responses
+// are kept until evicted, while the ones of the latest block are dropped on the new heads of the shard
+// the client subscribes to. The subscription is ended when ctx is done.
+func NewCachingShardApiClient(
+	ctx context.Context, networkManager network.Manager, shardId types.ShardId, config CachingClientConfig,
+) ShardApiRo {
+	return newCachingShardApiClient(ctx, networkManager, shardId, config)
+}
+
+func newCachingShardApiClient(
+	ctx context.Context, networkManager network.Manager, shardId types.ShardId, config CachingClientConfig,
+) *shardApiClientRo {
+	if config.PeerSelector == nil {
+		config.PeerSelector = selectFirstPeer
+	}
+	client, err := newShardApiClientNetwork[shardApiClientRo, shardApiRo, NetworkTransportProtocolRo](
+		func(performer shardApiRequestPerformer) *shardApiClientRo {
+			if !config.DisableCoalescing {
+				performer = withRequestCoalescing(performer)
+			}
+			performer = withResponseCache(performer, config.ResponseCache)
+			return constructShardApiClientRo(withLatestResponseCache(ctx, performer, config.LatestCacheSize))
+		},
+		shardId, apiNameRo, networkManager, config.PeerSelector)
+	check.PanicIfErr(err)
+	return client
+}
+
+// shardApiRequestPerformerCoalesced sends the identical concurrent requests with the wrapped performer once.
+type shardApiRequestPerformerCoalesced struct {
+	shardApiRequestPerformer
+
+	calls singleflight.Group
+}
+
+var _ shardApiRequestPerformer = (*shardApiRequestPerformerCoalesced)(nil)
+
+func withRequestCoalescing(performer shardApiRequestPerformer) shardApiRequestPerformer {
+	return &shardApiRequestPerformerCoalesced{shardApiRequestPerformer: performer}
+}
+
+// doApiRequest makes the requests equal to the one being sent wait for its response. As on the server,
+// the request is not cancelled when the first caller goes away, but its deadline is kept.
+func (api *shardApiRequestPerformerCoalesced) doApiRequest(
+	ctx context.Context, codec *methodCodec, args ...any,
+) ([]byte, error) {
+	request, err := codec.packRequest(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The conditional requests get different responses, so they are only coalesced with the ones
+	// conditional on the same block.
+	key := codec.methodName + "/" + string(request)
+	if blockHash, ok := ctx.Value(ifBlockChangedKey{}).(common.Hash); ok {
+		key = blockHash.Hex() + "/" + key
+	}
+	results := api.calls.DoChan(key, func() (any, error) {
+		callCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithDeadline(callCtx, deadline)
+			defer cancel()
+		}
+		return api.shardApiRequestPerformer.doApiRequest(callCtx, codec, args...)
+	})
+
+	select {
+	case result := <-results:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		response, _ := result.Val.([]byte)
+		return response, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// latestResponse is a cached response of a request referencing the latest block.
+type latestResponse struct {
+	response []byte
+	// blockHash is the block the response is read at, empty if it is not known, see ResponseBlock.
+	blockHash common.Hash
+}
+
+// shardApiRequestPerformerLatestCached serves the requests referencing the latest block from the cache
+// until the next head of the shard. While the heads aren't received, the cached responses are only served
+// once the requests conditional on their blocks are not modified, see WithIfBlockChanged.
+type shardApiRequestPerformerLatestCached struct {
+	shardApiRequestPerformer
+
+	responses *lru.Cache[string, latestResponse]
+	// generation is increased on every new head, so that the responses loaded before it aren't cached.
+	generation atomic.Uint64
+	// subscribed is false while the heads aren't received, the responses aren't cached then.
+	subscribed atomic.Bool
+	// mu orders the caching of the responses after the checks of the generation with the purges.
+	mu sync.Mutex
+}
+
+var _ shardApiRequestPerformer = (*shardApiRequestPerformerLatestCached)(nil)
+
+func withLatestResponseCache(
+	ctx context.Context, performer shardApiRequestPerformer, size int,
+) shardApiRequestPerformer {
+	if size <= 0 {
+		return performer
+	}
+	api := &shardApiRequestPerformerLatestCached{
+		shardApiRequestPerformer: performer,
+		responses:                mustCreate(lru.New[string, latestResponse](size)),
+	}
+	go api.watchNewHeads(ctx)
+	return api
+}
+
+// isLatestBlockReferenced checks whether any argument of the request references the latest block.
+func isLatestBlockReferenced(args []any) bool {
+	for _, arg := range args {
+		blockReference, ok := arg.(rawapitypes.BlockReference)
+		if ok && blockReference.Type() == rawapitypes.NamedBlockIdentifierReference &&
+			blockReference.NamedBlockIdentifier() == rawapitypes.LatestBlock {
+			return true
+		}
+	}
+	return false
+}
+
+func (api *shardApiRequestPerformerLatestCached) doApiRequest(
+	ctx context.Context, codec *methodCodec, args ...any,
+) ([]byte, error) {
+	if codec.kind != singleResponse || !isLatestBlockReferenced(args) {
+		return api.shardApiRequestPerformer.doApiRequest(ctx, codec, args...)
+	}
+
+	request, err := codec.packRequest(args...)
+	if err != nil {
+		return nil, err
+	}
+	key := codec.methodName + "/" + string(request)
+	cached, ok := api.responses.Get(key)
+	if ok && api.subscribed.Load() {
+		return cached.response, nil
+	}
+	revalidated := ok && !cached.blockHash.Empty()
+	if revalidated {
+		ctx = WithIfBlockChanged(ctx, cached.blockHash)
+	}
+
+	generation := api.generation.Load()
+	var block ResponseBlock
+	response, err := api.shardApiRequestPerformer.doApiRequest(WithResponseBlock(ctx, &block), codec, args...)
+	if err != nil {
+		return nil, err
+	}
+	// The error responses are not cached, the data may appear with the next block.
+	if _, err := codec.unpackResponse(response); err != nil {
+		if revalidated && errors.Is(err, rawapitypes.ErrNotModified) {
+			return cached.response, nil
+		}
+		return response, nil
+	}
+	api.mu.Lock()
+	if api.generation.Load() == generation {
+		api.responses.Add(key, latestResponse{response: response, blockHash: block.Hash()})
+	}
+	api.mu.Unlock()
+	return response, nil
+}
+
+// invalidate drops the cached responses, the ones being loaded won't be cached either.
+func (api *shardApiRequestPerformerLatestCached) invalidate() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.generation.Add(1)
+	api.responses.Purge()
+}
+
+// watchNewHeads invalidates the cache on the new heads of the shard until ctx is done.
+// The subscription is renewed if the server ends it.
+func (api *shardApiRequestPerformerLatestCached) watchNewHeads(ctx context.Context) {
+	codec, ok := api.apiCodec()["SubscribeNewHeads"]
+	check.PanicIfNotf(ok, "Codec for method SubscribeNewHeads not found")
+
+	for ctx.Err() == nil {
+		heads, err := api.shardApiRequestPerformer.doApiSubscription(ctx, codec)
+		if err == nil {
+			// The head may have changed before the subscription.
+			api.invalidate()
+			api.subscribed.Store(true)
+			for range heads {
+				api.invalidate()
+			}
+			api.subscribed.Store(false)
+			api.invalidate()
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(newHeadsResubscribeDelay):
+		}
+	}
+}
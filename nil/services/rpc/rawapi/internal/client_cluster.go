@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type shardApiClientCluster struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiCluster = (*shardApiClientCluster)(nil)
+
+func constructShardApiClientCluster(performer shardApiRequestPerformer) *shardApiClientCluster {
+	return &shardApiClientCluster{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+// NewNetworkClusterApiClient creates a client of the cluster API served by the peers of the network manager.
+func NewNetworkClusterApiClient(networkManager network.Manager) ClusterApi {
+	client, err := newShardApiClientNetwork[shardApiClientCluster, shardApiCluster, NetworkTransportProtocolCluster](
+		constructShardApiClientCluster, types.MainShardId, apiNameCluster, networkManager, selectFirstPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientCluster) GetFamilyTransactionCount(
+	ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.FamilyTransactionCount, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.FamilyTransactionCount](
+		ctx, api, "GetFamilyTransactionCount", address, blockReference)
+}
+
+func (api *shardApiClientCluster) GetLatestBlocks(ctx context.Context) ([]*rawapitypes.ShardBlockHead, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.ShardBlockHead](
+		ctx, api, "GetLatestBlocks")
+}
+
+func (api *shardApiClientCluster) GetGasPriceStats(ctx context.Context) (*rawapitypes.GasPriceStats, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.GasPriceStats](ctx, api, "GetGasPriceStats")
+}
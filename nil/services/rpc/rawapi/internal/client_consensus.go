@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type shardApiClientConsensus struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiConsensus = (*shardApiClientConsensus)(nil)
+
+func constructShardApiClientConsensus(performer shardApiRequestPerformer) *shardApiClientConsensus {
+	return &shardApiClientConsensus{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+// NewNetworkConsensusApiClient creates a client of the consensus API of the node with the given peer ID.
+// The requests are authenticated the same way as the ones of the admin API.
+func NewNetworkConsensusApiClient(networkManager network.Manager, peerId network.PeerID) ConsensusApi {
+	client, err := newShardApiClientNetwork[
+		shardApiClientConsensus, shardApiConsensus, NetworkTransportProtocolConsensus,
+	](constructShardApiClientConsensus, types.MainShardId, apiNameConsensus, networkManager, selectPeer(peerId))
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientConsensus) GetConsensusState(
+	ctx context.Context, shardId types.ShardId,
+) (*rawapitypes.ConsensusState, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ConsensusState](
+		ctx, api, "GetConsensusState", shardId)
+}
+
+func (api *shardApiClientConsensus) GetRoundTimeouts(
+	ctx context.Context, shardId types.ShardId,
+) ([]rawapitypes.RoundTimeout, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]rawapitypes.RoundTimeout](
+		ctx, api, "GetRoundTimeouts", shardId)
+}
@@ -0,0 +1,40 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+type shardApiClientDb struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiDb = (*shardApiClientDb)(nil)
+
+func constructShardApiClientDb(performer shardApiRequestPerformer) *shardApiClientDb {
+	return &shardApiClientDb{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+// NewNetworkDbApiClient creates a client of the database API of the node with the given peer ID.
+// The requests are authenticated the same way as the ones of the admin API.
+func NewNetworkDbApiClient(networkManager network.Manager, peerId network.PeerID) DbApi {
+	client, err := newShardApiClientNetwork[shardApiClientDb, shardApiDb, NetworkTransportProtocolDb](
+		constructShardApiClientDb, types.MainShardId, apiNameDb, networkManager, selectPeer(peerId))
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientDb) GetRaw(ctx context.Context, table string, key []byte) ([]byte, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]byte](ctx, api, "GetRaw", table, key)
+}
+
+func (api *shardApiClientDb) ListKeys(
+	ctx context.Context, table string, prefix []byte, after []byte, limit uint64,
+) ([][]byte, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[][]byte](ctx, api, "ListKeys", table, prefix, after, limit)
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+type shardApiClientDebug struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiDebug = (*shardApiClientDebug)(nil)
+
+func constructShardApiClientDebug(performer shardApiRequestPerformer) *shardApiClientDebug {
+	return &shardApiClientDebug{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+func newShardApiClientNetworkDebug(
+	shardId types.ShardId, networkManager network.Manager, selectPeer PeerSelector,
+) *shardApiClientDebug {
+	client, err := newShardApiClientNetwork[shardApiClientDebug, shardApiDebug, NetworkTransportProtocolDebug](
+		constructShardApiClientDebug, shardId, apiNameDebug, networkManager, selectPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientDebug) TraceTransaction(
+	ctx context.Context, hash common.Hash,
+) (*rawapitypes.ExecutionTrace, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ExecutionTrace](
+		ctx, api, "TraceTransaction", hash)
+}
+
+func (api *shardApiClientDebug) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.ExecutionTrace, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ExecutionTrace](
+		ctx, api, "TraceCall", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+}
+
+func (api *shardApiClientDebug) TraceTransactionStateDiff(
+	ctx context.Context, hash common.Hash,
+) (*rawapitypes.StateDiff, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.StateDiff](
+		ctx, api, "TraceTransactionStateDiff", hash)
+}
+
+func (api *shardApiClientDebug) TraceCallStateDiff(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.StateDiff, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.StateDiff](
+		ctx, api, "TraceCallStateDiff", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+}
+
+func (api *shardApiClientDebug) ReplayTransaction(
+	ctx context.Context, hash common.Hash, options rawapitypes.ReplayOptions,
+) (*rawapitypes.ReplayResult, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ReplayResult](
+		ctx, api, "ReplayTransaction", hash, options)
+}
+
+func (api *shardApiClientDebug) GetPendingResponsesFor(
+	ctx context.Context,
+	address types.Address,
+	start types.TransactionIndex,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.PendingResponses, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.PendingResponses](
+		ctx, api, "GetPendingResponsesFor", address, start, limit, blockReference)
+}
+
+func (api *shardApiClientDebug) GetAwaitingContext(
+	ctx context.Context, hash common.Hash,
+) (*rawapitypes.AwaitingContext, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.AwaitingContext](
+		ctx, api, "GetAwaitingContext", hash)
+}
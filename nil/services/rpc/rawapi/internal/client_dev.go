@@ -20,9 +20,11 @@ func constructShardApiClientDev(performer shardApiRequestPerformer) *shardApiCli
 	}
 }
 
-func newShardApiClientNetworkDev(shardId types.ShardId, networkManager network.Manager) *shardApiClientDev {
+func newShardApiClientNetworkDev(
+	shardId types.ShardId, networkManager network.Manager, selectPeer PeerSelector,
+) *shardApiClientDev {
 	client, err := newShardApiClientNetwork[shardApiClientDev, shardApiDev, NetworkTransportProtocolDev](
-		constructShardApiClientDev, shardId, apiNameDev, networkManager)
+		constructShardApiClientDev, shardId, apiNameDev, networkManager, selectPeer)
 	check.PanicIfErr(err)
 	return client
 }
@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+type shardApiClientDevnet struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiDevnet = (*shardApiClientDevnet)(nil)
+
+func constructShardApiClientDevnet(performer shardApiRequestPerformer) *shardApiClientDevnet {
+	return &shardApiClientDevnet{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+// NewNetworkDevnetApiClient creates a client of the devnet API served by the peers of the network manager.
+func NewNetworkDevnetApiClient(networkManager network.Manager) DevnetApi {
+	client, err := newShardApiClientNetwork[shardApiClientDevnet, shardApiDevnet, NetworkTransportProtocolDevnet](
+		constructShardApiClientDevnet, types.MainShardId, apiNameDevnet, networkManager, selectFirstPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientDevnet) MineBlock(ctx context.Context, count uint64) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "MineBlock", count)
+}
+
+func (api *shardApiClientDevnet) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "SetNextBlockTimestamp", timestamp)
+}
+
+func (api *shardApiClientDevnet) SnapshotState(ctx context.Context) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "SnapshotState")
+}
+
+func (api *shardApiClientDevnet) RevertToSnapshot(ctx context.Context, id uint64) (bool, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[bool](ctx, api, "RevertToSnapshot", id)
+}
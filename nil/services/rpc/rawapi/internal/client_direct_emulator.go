@@ -85,6 +85,32 @@ func (api *shardApiRequestPerformerDirectEmulator) doApiRequest(
 	return codec.packResponse(apiCallResults...)
 }
 
+func (api *shardApiRequestPerformerDirectEmulator) doApiSubscription(
+	ctx context.Context, codec *methodCodec, args ...any,
+) (<-chan []byte, error) {
+	apiValue := reflect.ValueOf(api.shardApi)
+	apiMethod := apiValue.MethodByName(codec.methodName)
+	check.PanicIfNot(!apiMethod.IsZero())
+
+	requestBody, err := codec.packRequest(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	unpackedArguments, err := codec.unpackRequest(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	apiArguments := []reflect.Value{reflect.ValueOf(ctx)}
+	apiArguments = append(apiArguments, unpackedArguments...)
+	results, err := splitError(apiMethod.Call(apiArguments))
+	if err != nil {
+		return nil, err
+	}
+	return packSubscriptionEvents(ctx, codec, results[0]), nil
+}
+
 func (api *shardApiRequestPerformerDirectEmulator) setNodeApi(nodeApi NodeApi) {
 	api.shardApi.setNodeApi(nodeApi)
 }
@@ -92,10 +118,19 @@ func (api *shardApiRequestPerformerDirectEmulator) setNodeApi(nodeApi NodeApi) {
 func (api *shardApiRequestPerformerDirectEmulator) setAsP2pRequestHandlersIfAllowed(
 	ctx context.Context,
 	networkManager network.Manager,
+	cfg RequestHandlersConfig,
 	logger logging.Logger,
 ) error {
 	return setRawApiRequestHandlers(
-		ctx, api.transportType, api.apiType, api.derived, api.shardId(), api.apiName, networkManager, logger)
+		ctx,
+		api.transportType,
+		api.apiType,
+		api.derived,
+		api.shardId(),
+		api.apiName,
+		networkManager,
+		cfg,
+		logger)
 }
 
 func (api *shardApiRequestPerformerDirectEmulator) apiCodec() apiCodec {
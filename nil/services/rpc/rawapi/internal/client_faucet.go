@@ -0,0 +1,41 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type shardApiClientFaucet struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiFaucet = (*shardApiClientFaucet)(nil)
+
+func constructShardApiClientFaucet(performer shardApiRequestPerformer) *shardApiClientFaucet {
+	return &shardApiClientFaucet{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+// NewNetworkFaucetApiClient creates a client of the faucet API served by the peers of the network manager.
+func NewNetworkFaucetApiClient(networkManager network.Manager) FaucetApi {
+	client, err := newShardApiClientNetwork[shardApiClientFaucet, shardApiFaucet, NetworkTransportProtocolFaucet](
+		constructShardApiClientFaucet, types.MainShardId, apiNameFaucet, networkManager, selectFirstPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientFaucet) RequestFunds(
+	ctx context.Context, address types.Address, amount types.Value,
+) (*rawapitypes.SendTransactionResult, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.SendTransactionResult](
+		ctx, api, "RequestFunds", address, amount)
+}
+
+func (api *shardApiClientFaucet) GetFaucetLimits(ctx context.Context) (*rawapitypes.FaucetLimits, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.FaucetLimits](ctx, api, "GetFaucetLimits")
+}
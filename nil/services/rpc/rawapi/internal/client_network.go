@@ -10,10 +10,19 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/types"
 )
 
+// PeerSelector chooses the peer a request is sent to among the peers serving the API of the shard.
+// The list of the peers is never empty.
+type PeerSelector func(peers []network.PeerID) (network.PeerID, error)
+
+func selectFirstPeer(peers []network.PeerID) (network.PeerID, error) {
+	return peers[0], nil
+}
+
 type shardApiRequestPerformerNetwork struct {
 	shard          types.ShardId
 	apiName        string
 	networkManager network.Manager
+	selectPeer     PeerSelector
 	codec          apiCodec
 }
 
@@ -22,7 +31,15 @@ var _ shardApiRequestPerformer = (*shardApiRequestPerformerNetwork)(nil)
 func (api *shardApiRequestPerformerNetwork) doApiRequest(
 	ctx context.Context, codec *methodCodec, args ...any,
 ) ([]byte, error) {
-	return doNetworkShardApiRequest(ctx, api.networkManager, api.shard, api.apiName, codec, args...)
+	return doNetworkShardApiRequest(
+		ctx, api.networkManager, api.selectPeer, api.shard, api.apiName, codec, args...)
+}
+
+func (api *shardApiRequestPerformerNetwork) doApiSubscription(
+	ctx context.Context, codec *methodCodec, args ...any,
+) (<-chan []byte, error) {
+	return doNetworkShardApiSubscription(
+		ctx, api.networkManager, api.selectPeer, api.shard, api.apiName, codec, args...)
 }
 
 func (api *shardApiRequestPerformerNetwork) shardId() types.ShardId {
@@ -34,6 +51,7 @@ func (api *shardApiRequestPerformerNetwork) setNodeApi(_ NodeApi) {}
 func (api *shardApiRequestPerformerNetwork) setAsP2pRequestHandlersIfAllowed(
 	_ context.Context,
 	_ network.Manager,
+	_ RequestHandlersConfig,
 	_ logging.Logger,
 ) error {
 	return nil
@@ -53,6 +71,7 @@ func newShardApiClientNetwork[
 	shardId types.ShardId,
 	apiName string,
 	networkManager network.Manager,
+	selectPeer PeerSelector,
 ) (*ClientType, error) {
 	codec, err := newApiCodec(reflect.TypeFor[ShardApiType](), reflect.TypeFor[TransportType]())
 	if err != nil {
@@ -63,6 +82,7 @@ func newShardApiClientNetwork[
 		shard:          shardId,
 		apiName:        apiName,
 		networkManager: networkManager,
+		selectPeer:     selectPeer,
 		codec:          codec,
 	}), nil
 }
@@ -70,13 +90,14 @@ func newShardApiClientNetwork[
 func doNetworkShardApiRequest(
 	ctx context.Context,
 	networkManager network.Manager,
+	selectPeer PeerSelector,
 	shardId types.ShardId,
 	apiName string,
 	codec *methodCodec,
 	args ...any,
 ) ([]byte, error) {
-	protocol := network.ProtocolID(fmt.Sprintf("/shard/%d/%s/%s", shardId, apiName, codec.methodName))
-	serverPeerId, err := discoverAppropriatePeer(networkManager, shardId, protocol)
+	serverPeerId, protocol, err := discoverVersionedProtocol(
+		ctx, networkManager, selectPeer, shardId, apiName, codec.methodName)
 	if err != nil {
 		return nil, err
 	}
@@ -86,11 +107,17 @@ func doNetworkShardApiRequest(
 		return nil, err
 	}
 
-	return networkManager.SendRequestAndGetResponse(ctx, serverPeerId, protocol, requestBody)
+	response, err := sendEnvelopedRequest(ctx, networkManager, serverPeerId, protocol, requestBody)
+	if err != nil {
+		forgetApiVersion(serverPeerId, shardId, apiName)
+		return nil, err
+	}
+	return response, nil
 }
 
 func discoverAppropriatePeer(
 	networkManager network.Manager,
+	selectPeer PeerSelector,
 	shardId types.ShardId,
 	protocol network.ProtocolID,
 ) (network.PeerID, error) {
@@ -98,5 +125,5 @@ func discoverAppropriatePeer(
 	if len(peersWithSpecifiedShard) == 0 {
 		return "", fmt.Errorf("no peers with shard %d found", shardId)
 	}
-	return peersWithSpecifiedShard[0], nil
+	return selectPeer(peersWithSpecifiedShard)
 }
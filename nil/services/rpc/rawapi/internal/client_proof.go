@@ -0,0 +1,45 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/google/uuid"
+)
+
+type shardApiClientProof struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiProof = (*shardApiClientProof)(nil)
+
+func constructShardApiClientProof(performer shardApiRequestPerformer) *shardApiClientProof {
+	return &shardApiClientProof{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+// NewNetworkProofApiClient creates a client of the proof API served by the peers of the network manager.
+func NewNetworkProofApiClient(networkManager network.Manager) ProofApi {
+	client, err := newShardApiClientNetwork[shardApiClientProof, shardApiProof, NetworkTransportProtocolProof](
+		constructShardApiClientProof, types.MainShardId, apiNameProof, networkManager, selectFirstPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientProof) GetStateProofForBlock(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StateProof, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.StateProof](
+		ctx, api, "GetStateProofForBlock", blockReference)
+}
+
+func (api *shardApiClientProof) GetProofTaskStatus(
+	ctx context.Context, id uuid.UUID,
+) (*rawapitypes.ProofTaskInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ProofTaskInfo](
+		ctx, api, "GetProofTaskStatus", id)
+}
@@ -24,9 +24,58 @@ func constructShardApiClientRo(performer shardApiRequestPerformer) *shardApiClie
 	}
 }
 
-func newShardApiClientNetworkRo(shardId types.ShardId, networkManager network.Manager) *shardApiClientRo {
+func newShardApiClientNetworkRo(
+	shardId types.ShardId, networkManager network.Manager, cacheConfig ResponseCacheConfig, selectPeer PeerSelector,
+) *shardApiClientRo {
 	client, err := newShardApiClientNetwork[shardApiClientRo, shardApiRo, NetworkTransportProtocolRo](
-		constructShardApiClientRo, shardId, apiNameRo, networkManager)
+		func(performer shardApiRequestPerformer) *shardApiClientRo {
+			return constructShardApiClientRo(withResponseCache(performer, cacheConfig))
+		},
+		shardId, apiNameRo, networkManager, selectPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func newShardApiClientNetworkRoWithPeerSelector(
+	shardId types.ShardId, networkManager network.Manager, selectPeer PeerSelector,
+) *shardApiClientRo {
+	client, err := newShardApiClientNetwork[shardApiClientRo, shardApiRo, NetworkTransportProtocolRo](
+		constructShardApiClientRo, shardId, apiNameRo, networkManager, selectPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+// shardApiClientNetwork is the client of both APIs of a shard, each of them is sent over its own protocols.
+type shardApiClientNetwork struct {
+	*shardApiClientRo
+	*shardApiClientRw
+}
+
+var _ ShardApi = shardApiClientNetwork{}
+
+// NewNetworkShardApiClient creates a client of the read-only and the read-write APIs of the shard served
+// by the other nodes. The requests are packed the same way the handlers set by the node unpack them. The peer
+// serving a request is chosen by peerSelector, the first of the peers serving the shard is used if it is nil.
+func NewNetworkShardApiClient(
+	networkManager network.Manager, shardId types.ShardId, peerSelector PeerSelector,
+) ShardApi {
+	if peerSelector == nil {
+		peerSelector = selectFirstPeer
+	}
+	return shardApiClientNetwork{
+		shardApiClientRo: newShardApiClientNetworkRoWithPeerSelector(shardId, networkManager, peerSelector),
+		shardApiClientRw: newShardApiClientNetworkRw(shardId, networkManager, peerSelector),
+	}
+}
+
+// NewMultiPeerShardApiClient creates a client of the read-only API of the shard sending each request
+// to the best of the candidate peers. The peers are ranked by their error rates and latencies,
+// a request is retried on the next peer if it fails and hedged if the peer is slow to respond.
+func NewMultiPeerShardApiClient(
+	networkManager network.Manager, shardId types.ShardId, config MultiPeerConfig,
+) ShardApiRo {
+	client, err := newShardApiClientMultiPeer[shardApiClientRo, shardApiRo, NetworkTransportProtocolRo](
+		constructShardApiClientRo, shardId, apiNameRo, networkManager, config)
 	check.PanicIfErr(err)
 	return client
 }
@@ -59,6 +108,49 @@ func (api *shardApiClientRo) GetBlockTransactionCount(
 		ctx, api, "GetBlockTransactionCount", blockReference)
 }
 
+func (api *shardApiClientRo) GetBlockRange(
+	ctx context.Context, from types.BlockNumber, count uint64, fullBlocks bool,
+) ([]*types.RawBlockWithExtractedData, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*types.RawBlockWithExtractedData](
+		ctx, api, "GetBlockRange", from, count, fullBlocks)
+}
+
+func (api *shardApiClientRo) GetShardStats(
+	ctx context.Context, from types.BlockNumber, count uint64,
+) (*rawapitypes.ShardStats, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ShardStats](
+		ctx, api, "GetShardStats", from, count)
+}
+
+func (api *shardApiClientRo) GetBlockFinalitySignatures(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.FinalitySignatures, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.FinalitySignatures](
+		ctx, api, "GetBlockFinalitySignatures", blockReference)
+}
+
+func (api *shardApiClientRo) GetValidators(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.ValidatorSet, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ValidatorSet](
+		ctx, api, "GetValidators", blockReference)
+}
+
+func (api *shardApiClientRo) GetValidatorInfo(
+	ctx context.Context, publicKey []byte,
+) (*rawapitypes.ValidatorInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ValidatorInfo](
+		ctx, api, "GetValidatorInfo", publicKey)
+}
+
+func (api *shardApiClientRo) GetEpochInfo(ctx context.Context) (*rawapitypes.EpochInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.EpochInfo](ctx, api, "GetEpochInfo")
+}
+
+func (api *shardApiClientRo) SubscribeNewHeads(ctx context.Context) (<-chan sszx.SSZEncodedData, error) {
+	return subscribeWithCallerMethodName[sszx.SSZEncodedData](ctx, api, "SubscribeNewHeads")
+}
+
 func (api *shardApiClientRo) GetBalance(
 	ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
 ) (types.Value, error) {
@@ -66,6 +158,13 @@ func (api *shardApiClientRo) GetBalance(
 		ctx, api, "GetBalance", address, blockReference)
 }
 
+func (api *shardApiClientRo) GetBalances(
+	ctx context.Context, addresses []types.Address, blockReference rawapitypes.BlockReference,
+) ([]types.Value, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]types.Value](
+		ctx, api, "GetBalances", addresses, blockReference)
+}
+
 func (api *shardApiClientRo) GetCode(
 	ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
 ) (types.Code, error) {
@@ -73,11 +172,53 @@ func (api *shardApiClientRo) GetCode(
 		ctx, api, "GetCode", address, blockReference)
 }
 
+func (api *shardApiClientRo) GetStorageAt(
+	ctx context.Context, address types.Address, key common.Hash, blockReference rawapitypes.BlockReference,
+) (types.Uint256, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[types.Uint256](
+		ctx, api, "GetStorageAt", address, key, blockReference)
+}
+
+func (api *shardApiClientRo) GetStorageRange(
+	ctx context.Context,
+	address types.Address,
+	start common.Hash,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StorageRange, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.StorageRange](
+		ctx, api, "GetStorageRange", address, start, limit, blockReference)
+}
+
 func (api *shardApiClientRo) GetTokens(
-	ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
-) (map[types.TokenId]types.Value, error) {
-	return sendRequestAndGetResponseWithCallerMethodName[map[types.TokenId]types.Value](
-		ctx, api, "GetTokens", address, blockReference)
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+	request rawapitypes.TokensRequest,
+) (*rawapitypes.TokensPage, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.TokensPage](
+		ctx, api, "GetTokens", address, blockReference, request)
+}
+
+func (api *shardApiClientRo) GetTokenBalance(
+	ctx context.Context, address types.Address, tokenId types.TokenId, blockReference rawapitypes.BlockReference,
+) (types.Value, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[types.Value](
+		ctx, api, "GetTokenBalance", address, tokenId, blockReference)
+}
+
+func (api *shardApiClientRo) GetTokenInfo(
+	ctx context.Context, tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.TokenInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.TokenInfo](
+		ctx, api, "GetTokenInfo", tokenId, mainBlockReference)
+}
+
+func (api *shardApiClientRo) ListTokensCreatedBy(
+	ctx context.Context, address types.Address, mainBlockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.TokenInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.TokenInfo](
+		ctx, api, "ListTokensCreatedBy", address, mainBlockReference)
 }
 
 func (api *shardApiClientRo) GetContract(
@@ -87,14 +228,69 @@ func (api *shardApiClientRo) GetContract(
 		ctx, api, "GetContract", address, blockReference)
 }
 
+func (api *shardApiClientRo) GetAccountMeta(
+	ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountMeta, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.AccountMeta](
+		ctx, api, "GetAccountMeta", address, blockReference)
+}
+
+func (api *shardApiClientRo) GetProof(
+	ctx context.Context, address types.Address, storageKeys []common.Hash, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.ContractProof, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ContractProof](
+		ctx, api, "GetProof", address, storageKeys, blockReference)
+}
+
 func (api *shardApiClientRo) Call(
 	ctx context.Context,
 	args rpctypes.CallArgs,
 	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
 ) (*rpctypes.CallResWithGasPrice, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[*rpctypes.CallResWithGasPrice](
-		ctx, api, "Call", args, mainBlockReferenceOrHashWithChildren, overrides)
+		ctx, api, "Call", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+}
+
+func (api *shardApiClientRo) EstimateFee(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.FeeEstimation, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.FeeEstimation](
+		ctx, api, "EstimateFee", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+}
+
+func (api *shardApiClientRo) CreateAccessList(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.AccessListResult, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.AccessListResult](
+		ctx, api, "CreateAccessList", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+}
+
+func (api *shardApiClientRo) SimulateBundle(
+	ctx context.Context,
+	calls []rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) ([]*rawapitypes.BundleCallResult, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.BundleCallResult](
+		ctx, api, "SimulateBundle", calls, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+}
+
+func (api *shardApiClientRo) PrepareDeploy(
+	ctx context.Context, request rawapitypes.DeployRequest,
+) (*rawapitypes.DeployPreparation, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.DeployPreparation](
+		ctx, api, "PrepareDeploy", request)
 }
 
 func (api *shardApiClientRo) GetInTransaction(
@@ -104,11 +300,55 @@ func (api *shardApiClientRo) GetInTransaction(
 		ctx, api, "GetInTransaction", request)
 }
 
-func (api *shardApiClientRo) GetInTransactionReceipt(
+func (api *shardApiClientRo) GetInTransactionByIndex(
+	ctx context.Context, blockReference rawapitypes.BlockReference, index types.TransactionIndex,
+) (*rawapitypes.TransactionInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.TransactionInfo](
+		ctx, api, "GetInTransactionByIndex", blockReference, index)
+}
+
+func (api *shardApiClientRo) GetReceipt(
 	ctx context.Context, hash common.Hash,
 ) (*rawapitypes.ReceiptInfo, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ReceiptInfo](
-		ctx, api, "GetInTransactionReceipt", hash)
+		ctx, api, "GetReceipt", hash)
+}
+
+func (api *shardApiClientRo) GetBounceInfo(ctx context.Context, hash common.Hash) (*rawapitypes.BounceInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.BounceInfo](ctx, api, "GetBounceInfo", hash)
+}
+
+func (api *shardApiClientRo) GetBlockReceipts(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.ReceiptInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.ReceiptInfo](
+		ctx, api, "GetBlockReceipts", blockReference)
+}
+
+func (api *shardApiClientRo) GetOutTransaction(
+	ctx context.Context, request rawapitypes.TransactionRequest,
+) (*rawapitypes.TransactionInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.TransactionInfo](
+		ctx, api, "GetOutTransaction", request)
+}
+
+func (api *shardApiClientRo) GetOutTransactions(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.TransactionInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.TransactionInfo](
+		ctx, api, "GetOutTransactions", blockReference)
+}
+
+func (api *shardApiClientRo) GetLogs(
+	ctx context.Context, filter rawapitypes.LogFilter,
+) ([]*rawapitypes.LogInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.LogInfo](ctx, api, "GetLogs", filter)
+}
+
+func (api *shardApiClientRo) SubscribeLogs(
+	ctx context.Context, filter rawapitypes.LogFilter,
+) (<-chan *rawapitypes.LogInfo, error) {
+	return subscribeWithCallerMethodName[*rawapitypes.LogInfo](ctx, api, "SubscribeLogs", filter)
 }
 
 func (api *shardApiClientRo) GasPrice(ctx context.Context) (types.Value, error) {
@@ -123,6 +363,25 @@ func (api *shardApiClientRo) GetNumShards(ctx context.Context) (uint64, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "GetNumShards")
 }
 
+func (api *shardApiClientRo) ResolveShard(ctx context.Context, address types.Address) (types.ShardId, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[types.ShardId](ctx, api, "ResolveShard", address)
+}
+
+func (api *shardApiClientRo) ResolveShards(
+	ctx context.Context, addresses []types.Address,
+) ([]*rawapitypes.ShardResolution, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.ShardResolution](
+		ctx, api, "ResolveShards", addresses)
+}
+
+func (api *shardApiClientRo) GetChainConfig(ctx context.Context) (*rawapitypes.ChainConfig, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.ChainConfig](ctx, api, "GetChainConfig")
+}
+
+func (api *shardApiClientRo) GetSyncStatus(ctx context.Context) (*rawapitypes.SyncStatus, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.SyncStatus](ctx, api, "GetSyncStatus")
+}
+
 func (api *shardApiClientRo) ClientVersion(ctx context.Context) (string, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[string](ctx, api, "ClientVersion")
 }
@@ -3,6 +3,7 @@ package internal
 import (
 	"context"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
@@ -22,9 +23,11 @@ func constructShardApiClientRw(performer shardApiRequestPerformer) *shardApiClie
 	}
 }
 
-func newShardApiClientNetworkRw(shardId types.ShardId, networkManager network.Manager) *shardApiClientRw {
+func newShardApiClientNetworkRw(
+	shardId types.ShardId, networkManager network.Manager, selectPeer PeerSelector,
+) *shardApiClientRw {
 	client, err := newShardApiClientNetwork[shardApiClientRw, shardApiRw, NetworkTransportProtocolRw](
-		constructShardApiClientRw, shardId, apiNameRw, networkManager)
+		constructShardApiClientRw, shardId, apiNameRw, networkManager, selectPeer)
 	check.PanicIfErr(err)
 	return client
 }
@@ -36,9 +39,25 @@ func newShardApiClientDirectEmulatorRw(shardApi shardApiRw) *shardApiClientRw {
 	return client
 }
 
-func (api *shardApiClientRw) SendTransaction(ctx context.Context, transaction []byte) (txnpool.DiscardReason, error) {
-	return sendRequestAndGetResponseWithCallerMethodName[txnpool.DiscardReason](
-		ctx, api, "SendTransaction", transaction)
+func (api *shardApiClientRw) SendTransaction(
+	ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string,
+) (*rawapitypes.SendTransactionResult, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.SendTransactionResult](
+		ctx, api, "SendTransaction", transaction, replacement, idempotencyKey)
+}
+
+func (api *shardApiClientRw) SendTransactionBundle(
+	ctx context.Context, transactions [][]byte, sameBlock bool,
+) ([]*rawapitypes.SendTransactionResult, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*rawapitypes.SendTransactionResult](
+		ctx, api, "SendTransactionBundle", transactions, sameBlock)
+}
+
+func (api *shardApiClientRw) SendTransactionAndWatch(
+	ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string,
+) (<-chan *rawapitypes.TransactionEvent, error) {
+	return subscribeWithCallerMethodName[*rawapitypes.TransactionEvent](
+		ctx, api, "SendTransactionAndWatch", transaction, replacement, idempotencyKey)
 }
 
 func (api *shardApiClientRw) GetTransactionCount(
@@ -48,6 +67,27 @@ func (api *shardApiClientRw) GetTransactionCount(
 		ctx, api, "GetTransactionCount", address, blockReference)
 }
 
+func (api *shardApiClientRw) GetNextValidSeqno(
+	ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
+) (uint64, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[uint64](
+		ctx, api, "GetNextValidSeqno", address, blockReference)
+}
+
+func (api *shardApiClientRw) GetTransactionStatus(
+	ctx context.Context, hash common.Hash,
+) (*rawapitypes.TransactionStatusInfo, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.TransactionStatusInfo](
+		ctx, api, "GetTransactionStatus", hash)
+}
+
+func (api *shardApiClientRw) ResendTransaction(
+	ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy,
+) (txnpool.DiscardReason, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[txnpool.DiscardReason](
+		ctx, api, "ResendTransaction", transaction, replacement)
+}
+
 func (api *shardApiClientRw) GetTxpoolStatus(ctx context.Context) (uint64, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[uint64](ctx, api, "GetTxpoolStatus")
 }
@@ -55,3 +95,10 @@ func (api *shardApiClientRw) GetTxpoolStatus(ctx context.Context) (uint64, error
 func (api *shardApiClientRw) GetTxpoolContent(ctx context.Context) ([]*types.Transaction, error) {
 	return sendRequestAndGetResponseWithCallerMethodName[[]*types.Transaction](ctx, api, "GetTxpoolContent")
 }
+
+func (api *shardApiClientRw) SubscribePendingTransactions(
+	ctx context.Context, fullTransactions bool,
+) (<-chan *rawapitypes.PendingTransaction, error) {
+	return subscribeWithCallerMethodName[*rawapitypes.PendingTransaction](
+		ctx, api, "SubscribePendingTransactions", fullTransactions)
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type shardApiClientSync struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiSync = (*shardApiClientSync)(nil)
+
+func constructShardApiClientSync(performer shardApiRequestPerformer) *shardApiClientSync {
+	return &shardApiClientSync{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+func newShardApiClientNetworkSync(
+	shardId types.ShardId, networkManager network.Manager, selectPeer PeerSelector,
+) *shardApiClientSync {
+	client, err := newShardApiClientNetwork[shardApiClientSync, shardApiSync, NetworkTransportProtocolSync](
+		constructShardApiClientSync, shardId, apiNameSync, networkManager, selectPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientSync) GetSnapshotManifest(
+	ctx context.Context, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.SnapshotManifest, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.SnapshotManifest](
+		ctx, api, "GetSnapshotManifest", blockReference)
+}
+
+func (api *shardApiClientSync) GetSnapshotChunk(
+	ctx context.Context, request rawapitypes.SnapshotChunkRequest,
+) (*rawapitypes.SnapshotChunk, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.SnapshotChunk](
+		ctx, api, "GetSnapshotChunk", request)
+}
+
+func (api *shardApiClientSync) GetBlocksSince(
+	ctx context.Context, sequence types.BlockNumber, limit uint64,
+) ([]*types.RawBlockWithExtractedData, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[[]*types.RawBlockWithExtractedData](
+		ctx, api, "GetBlocksSince", sequence, limit)
+}
@@ -2,16 +2,21 @@ package internal
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"reflect"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/sszx"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/protobuf/proto"
 )
@@ -27,6 +32,8 @@ type generatedApiClient struct {
 	doApiRequest   doApiRequestFunction
 }
 
+// ApiClientTestSuite serves the requests of the client the way the legacy nodes do, which neither support
+// the handshake nor wrap the requests and the responses.
 type ApiClientTestSuite struct {
 	RawApiTestSuite
 
@@ -45,7 +52,8 @@ func newGeneratedApiClient(networkManager network.Manager, serverPeerId network.
 		networkManager: networkManager,
 		serverPeerId:   serverPeerId,
 		doApiRequest: func(ctx context.Context, codec *methodCodec, args ...any) ([]byte, error) {
-			return doNetworkShardApiRequest(ctx, networkManager, types.BaseShardId, "testapi", codec, args...)
+			return doNetworkShardApiRequest(
+				ctx, networkManager, selectFirstPeer, types.BaseShardId, "testapi", codec, args...)
 		},
 	}, nil
 }
@@ -102,8 +110,7 @@ func (s *ApiClientTestSuite) TestValidResponse() {
 				},
 			}
 			index++
-			resp, err := proto.Marshal(response)
-			return resp, err
+			return proto.Marshal(response)
 		})
 	s.waitForRequestHandler("/shard/1/testapi/TestMethod")
 
@@ -150,8 +157,294 @@ func (s *ApiClientTestSuite) TestErrorResponse() {
 	s.Require().ErrorContains(err, "Test error")
 }
 
+func (s *ApiClientTestSuite) TestNetworkShardApiClient() {
+	s.serverNetworkManager.SetRequestHandler(
+		s.ctx,
+		"/shard/1/rawapi_ro/GetNumShards",
+		func(ctx context.Context, request []byte) ([]byte, error) {
+			return proto.Marshal(&pb.Uint64Response{Result: &pb.Uint64Response_Count{Count: 4}})
+		})
+	s.waitForRequestHandler("/shard/1/rawapi_ro/GetNumShards")
+
+	var selectedFrom []network.PeerID
+	client := NewNetworkShardApiClient(
+		s.clientNetworkManager,
+		types.BaseShardId,
+		func(peers []network.PeerID) (network.PeerID, error) {
+			selectedFrom = peers
+			return peers[0], nil
+		})
+
+	numShards, err := client.GetNumShards(s.ctx)
+	s.Require().NoError(err)
+	s.Require().EqualValues(4, numShards)
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, selectedFrom)
+
+	// The read-write API is sent over its own protocols.
+	s.serverNetworkManager.SetRequestHandler(
+		s.ctx,
+		"/shard/1/rawapi_rw/GetTxpoolStatus",
+		func(ctx context.Context, request []byte) ([]byte, error) {
+			return proto.Marshal(&pb.Uint64Response{Result: &pb.Uint64Response_Count{Count: 7}})
+		})
+	s.waitForRequestHandler("/shard/1/rawapi_rw/GetTxpoolStatus")
+
+	selectedFrom = nil
+	poolSize, err := client.GetTxpoolStatus(s.ctx)
+	s.Require().NoError(err)
+	s.Require().EqualValues(7, poolSize)
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, selectedFrom)
+}
+
 func TestClient(t *testing.T) {
 	t.Parallel()
 
 	suite.Run(t, new(ApiClientTestSuite))
 }
+
+func TestRaceShardApiPeers(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	peers := []network.PeerID{"slow", "fast", "backup"}
+	errFailed := errors.New("failed")
+
+	t.Run("Hedge", func(t *testing.T) {
+		t.Parallel()
+
+		response, err := raceShardApiPeers(ctx, peers,
+			func(network.PeerID) time.Duration { return 10 * time.Millisecond },
+			func(ctx context.Context, peerId network.PeerID) ([]byte, error) {
+				if peerId == "slow" {
+					<-ctx.Done()
+					return nil, ctx.Err()
+				}
+				return []byte(peerId), nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, []byte("fast"), response)
+	})
+
+	t.Run("Failover", func(t *testing.T) {
+		t.Parallel()
+
+		var tried []network.PeerID
+		response, err := raceShardApiPeers(ctx, peers,
+			func(network.PeerID) time.Duration { return -1 },
+			func(_ context.Context, peerId network.PeerID) ([]byte, error) {
+				tried = append(tried, peerId)
+				if peerId != "backup" {
+					return nil, errFailed
+				}
+				return []byte(peerId), nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, []byte("backup"), response)
+		require.Equal(t, peers, tried)
+	})
+
+	t.Run("AllFailed", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := raceShardApiPeers(ctx, peers,
+			func(network.PeerID) time.Duration { return -1 },
+			func(context.Context, network.PeerID) ([]byte, error) {
+				return nil, errFailed
+			})
+		require.ErrorIs(t, err, errFailed)
+		require.ErrorContains(t, err, "peer slow")
+		require.ErrorContains(t, err, "peer backup")
+	})
+}
+
+func TestRetryableResponseError(t *testing.T) {
+	t.Parallel()
+
+	apiCodec, err := newApiCodec(reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo]())
+	require.NoError(t, err)
+	codec := apiCodec["GetBlockHeader"]
+
+	// The errors of the peer are failed over, the errors of the request are returned by every peer alike.
+	for _, err := range []error{
+		rawapitypes.NewOverloadedError(time.Second),
+		rawapitypes.NewStatePrunedError(1, 10),
+		rawapitypes.NewInternalError("incident"),
+	} {
+		retryableErr := retryableResponseError(codec, codec.packError(err))
+		require.Error(t, retryableErr)
+		require.Equal(t, rawapitypes.ErrorCodeOf(err), rawapitypes.ErrorCodeOf(retryableErr))
+	}
+	for _, err := range []error{
+		rawapitypes.NewInvalidArgumentError(errors.New("bad request")),
+		rawapitypes.NewExecutionRevertedError("reverted", nil),
+		fmt.Errorf("block %w", rawapitypes.ErrNotFound),
+	} {
+		require.NoError(t, retryableResponseError(codec, codec.packError(err)))
+	}
+}
+
+func TestPeerScores(t *testing.T) {
+	t.Parallel()
+
+	scores := newPeerScores()
+	for range minLatencySamples {
+		scores.record("fast", time.Millisecond, nil)
+		scores.record("slow", 100*time.Millisecond, nil)
+		scores.record("failing", time.Millisecond, errors.New("failed"))
+	}
+
+	require.Equal(t,
+		[]network.PeerID{"unknown", "fast", "slow", "failing"},
+		scores.rank([]network.PeerID{"failing", "slow", "fast", "unknown"}))
+	require.Equal(t, time.Millisecond, scores.hedgeDelay("fast"))
+	require.Equal(t, defaultHedgeDelay, scores.hedgeDelay("failing"))
+	require.Equal(t, defaultHedgeDelay, scores.hedgeDelay("unknown"))
+}
+
+// cachingTestPerformer answers GetBlockTransactionCount with the number of the requests it has received
+// and sends the heads pushed by the test to the subscribers. The responses are read at the block if it is set,
+// the requests conditional on it are not modified.
+type cachingTestPerformer struct {
+	shardApiRequestPerformerNetwork
+
+	calls        atomic.Uint64
+	release      chan struct{}
+	heads        chan []byte
+	subscribeErr error
+	block        common.Hash
+}
+
+func (p *cachingTestPerformer) doApiRequest(ctx context.Context, codec *methodCodec, _ ...any) ([]byte, error) {
+	count := p.calls.Add(1)
+	<-p.release
+	if block, ok := ctx.Value(responseBlockKey{}).(*ResponseBlock); ok {
+		block.set(p.block)
+	}
+	if ifChanged, ok := ctx.Value(ifBlockChangedKey{}).(common.Hash); ok && ifChanged == p.block {
+		return codec.packError(rawapitypes.NewNotModifiedError(p.block)), nil
+	}
+	return codec.packResponse(reflect.ValueOf(count), reflect.Zero(reflect.TypeFor[error]()))
+}
+
+func (p *cachingTestPerformer) doApiSubscription(context.Context, *methodCodec, ...any) (<-chan []byte, error) {
+	if p.subscribeErr != nil {
+		return nil, p.subscribeErr
+	}
+	return p.heads, nil
+}
+
+func newCachingTestPerformer(t *testing.T) *cachingTestPerformer {
+	t.Helper()
+
+	codec, err := newApiCodec(reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo]())
+	require.NoError(t, err)
+	return &cachingTestPerformer{
+		shardApiRequestPerformerNetwork: shardApiRequestPerformerNetwork{shard: types.BaseShardId, codec: codec},
+		release:                         make(chan struct{}),
+		heads:                           make(chan []byte),
+	}
+}
+
+func TestCachingClient(t *testing.T) {
+	t.Parallel()
+
+	getCount := func(t *testing.T, client *shardApiClientRo, blockReference rawapitypes.BlockReference) uint64 {
+		t.Helper()
+
+		// The context of the test is not used, since the bubble of the coalescing test doesn't wait on it.
+		count, err := client.GetBlockTransactionCount(context.Background(), blockReference)
+		require.NoError(t, err)
+		return count
+	}
+
+	t.Run("Coalescing", func(t *testing.T) {
+		t.Parallel()
+
+		synctestRun(func() {
+			performer := newCachingTestPerformer(t)
+			client := constructShardApiClientRo(withRequestCoalescing(performer))
+
+			const requests = 5
+			counts := make(chan uint64, requests)
+			for range requests {
+				go func() {
+					counts <- getCount(t, client, rawapitypes.BlockNumberAsBlockReference(1))
+				}()
+			}
+			// All the requests wait for the first one before it is answered.
+			synctestWait()
+			close(performer.release)
+
+			for range requests {
+				require.EqualValues(t, 1, <-counts)
+			}
+			require.EqualValues(t, 1, performer.calls.Load())
+		})
+	})
+
+	t.Run("Immutable", func(t *testing.T) {
+		t.Parallel()
+
+		performer := newCachingTestPerformer(t)
+		close(performer.release)
+		client := constructShardApiClientRo(withResponseCache(performer, ResponseCacheConfig{Size: 16}))
+
+		byHash := rawapitypes.BlockHashAsBlockReference(common.HexToHash("0x1234"))
+		cached := getCount(t, client, byHash)
+		require.Equal(t, cached, getCount(t, client, byHash))
+
+		// The blocks referenced by number may be replaced, their responses aren't cached.
+		number := getCount(t, client, rawapitypes.BlockNumberAsBlockReference(1))
+		require.Greater(t, getCount(t, client, rawapitypes.BlockNumberAsBlockReference(1)), number)
+		require.EqualValues(t, 3, performer.calls.Load())
+	})
+
+	t.Run("Latest", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		performer := newCachingTestPerformer(t)
+		close(performer.release)
+		client := constructShardApiClientRo(withLatestResponseCache(ctx, withRequestCoalescing(performer), 16))
+
+		latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+		var cached uint64
+		require.Eventually(t, func() bool {
+			cached = getCount(t, client, latest)
+			return getCount(t, client, latest) == cached
+		}, time.Second, 10*time.Millisecond)
+
+		// The requests referencing the other blocks aren't cached.
+		number := getCount(t, client, rawapitypes.BlockNumberAsBlockReference(1))
+		require.Greater(t, getCount(t, client, rawapitypes.BlockNumberAsBlockReference(1)), number)
+
+		performer.heads <- nil
+		require.Eventually(t, func() bool {
+			return getCount(t, client, latest) != cached
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Revalidated", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		performer := newCachingTestPerformer(t)
+		close(performer.release)
+		performer.subscribeErr = errors.New("heads are not served")
+		performer.block = common.HexToHash("0x1234")
+		client := constructShardApiClientRo(withLatestResponseCache(ctx, withRequestCoalescing(performer), 16))
+
+		// The heads aren't received, so the cached response is served once the latest block is not changed.
+		latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+		cached := getCount(t, client, latest)
+		require.Equal(t, cached, getCount(t, client, latest))
+		require.EqualValues(t, 2, performer.calls.Load())
+
+		performer.block = common.HexToHash("0x5678")
+		require.Greater(t, getCount(t, client, latest), cached)
+	})
+}
@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+type shardApiClientTxpool struct {
+	shardApiRequestPerformer
+}
+
+var _ shardApiTxpool = (*shardApiClientTxpool)(nil)
+
+func constructShardApiClientTxpool(performer shardApiRequestPerformer) *shardApiClientTxpool {
+	return &shardApiClientTxpool{
+		shardApiRequestPerformer: performer,
+	}
+}
+
+func newShardApiClientNetworkTxpool(
+	shardId types.ShardId, networkManager network.Manager, selectPeer PeerSelector,
+) *shardApiClientTxpool {
+	client, err := newShardApiClientNetwork[shardApiClientTxpool, shardApiTxpool, NetworkTransportProtocolTxpool](
+		constructShardApiClientTxpool, shardId, apiNameTxpool, networkManager, selectPeer)
+	check.PanicIfErr(err)
+	return client
+}
+
+func (api *shardApiClientTxpool) GetPoolContent(ctx context.Context) (*rawapitypes.PoolContent, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.PoolContent](ctx, api, "GetPoolContent")
+}
+
+func (api *shardApiClientTxpool) GetPoolStatus(ctx context.Context) (*rawapitypes.PoolStatus, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.PoolStatus](ctx, api, "GetPoolStatus")
+}
+
+func (api *shardApiClientTxpool) GetPoolTransactionsByAccount(
+	ctx context.Context, address types.Address,
+) (*rawapitypes.PoolContent, error) {
+	return sendRequestAndGetResponseWithCallerMethodName[*rawapitypes.PoolContent](
+		ctx, api, "GetPoolTransactionsByAccount", address)
+}
@@ -11,6 +11,20 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+// responseKind describes how the result of an API method is transferred.
+type responseKind uint8
+
+const (
+	// singleResponse is a result packed into a single Protobuf response.
+	singleResponse responseKind = iota
+	// streamingResponse is used for methods which return a slice and whose Protobuf response type describes
+	// a single element of it. Each element is packed into a separate response frame.
+	streamingResponse
+	// subscriptionResponse is used for methods which return a receive-only channel and whose Protobuf response
+	// type describes a single element of it. Elements are sent as separate frames while the subscription is active.
+	subscriptionResponse
+)
+
 type methodCodec struct {
 	methodName           string
 	apiMethodResultType  reflect.Type
@@ -20,6 +34,18 @@ type methodCodec struct {
 	requestUnpackMethod  reflect.Method
 	responsePackMethod   reflect.Method
 	responseUnpackMethod reflect.Method
+
+	// requests and responses reuse the Protobuf messages, requests is nil if the method has no request.
+	requests  *messagePool
+	responses *messagePool
+
+	kind responseKind
+	// passthrough encodes the successful result of the method directly, see passthroughResponses.
+	passthrough func(result any) ([]byte, error)
+	// ssz encodes the result of the method in SSZ if it is listed in sszResponses, the SSZ responses are
+	// only sent by the copy of the codec returned by sszEncoding.
+	ssz       *sszResponseCodec
+	encodeSSZ bool
 }
 
 func (c *methodCodec) packRequest(apiArgs ...any) ([]byte, error) {
@@ -27,8 +53,10 @@ func (c *methodCodec) packRequest(apiArgs ...any) ([]byte, error) {
 		return nil, nil
 	}
 
-	pbRequestValuePtr := reflect.New(c.pbRequestType)
-	args := []reflect.Value{pbRequestValuePtr}
+	pbRequest := c.requests.get()
+	defer c.requests.put(pbRequest)
+	args := make([]reflect.Value, 0, len(apiArgs)+1)
+	args = append(args, reflect.ValueOf(pbRequest))
 	for _, arg := range apiArgs {
 		args = append(args, reflect.ValueOf(arg))
 	}
@@ -36,10 +64,7 @@ func (c *methodCodec) packRequest(apiArgs ...any) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	transaction, ok := pbRequestValuePtr.Interface().(proto.Message)
-	// Should never happen, so we don't pack error to response.
-	check.PanicIfNotf(ok, "failed to create proto transaction %s", c.pbRequestType)
-	request, err := proto.Marshal(transaction)
+	request, err := proto.Marshal(pbRequest)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack Protobuf request: %w", err)
 	}
@@ -51,28 +76,91 @@ func (c *methodCodec) unpackRequest(request []byte) ([]reflect.Value, error) {
 		return nil, nil
 	}
 
-	pbRequestValuePtr := reflect.New(c.pbRequestType)
-	transaction, ok := pbRequestValuePtr.Interface().(proto.Message)
-	// Should never happen, so we don't pack error to response.
-	check.PanicIfNotf(ok, "failed to create proto transaction %s", c.pbRequestType)
-	err := proto.Unmarshal(request, transaction)
+	pbRequest := c.requests.get()
+	defer c.requests.put(pbRequest)
+	if err := unmarshalRequest(request, pbRequest); err != nil {
+		return nil, err
+	}
+	arguments, err := callMethodWithLastOutputError(
+		c.requestUnpackMethod.Func, []reflect.Value{reflect.ValueOf(pbRequest)})
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack Protobuf request: %w", err)
+		return nil, wrapRequestUnpackError(err)
 	}
-	return callMethodWithLastOutputError(c.requestUnpackMethod.Func, []reflect.Value{pbRequestValuePtr})
+	if err := validateRequestValues(c.methodName, arguments); err != nil {
+		return nil, err
+	}
+	return arguments, nil
 }
 
 func (c *methodCodec) packResponse(apiCallResults ...reflect.Value) ([]byte, error) {
-	pbResponseValuePtr := reflect.New(c.pbResponseType)
-	if _, err := callMethodWithLastOutputError(
-		c.responsePackMethod.Func,
-		append([]reflect.Value{pbResponseValuePtr}, apiCallResults...)); err != nil {
-		return c.packError(err), nil
+	check.PanicIfNotf(c.kind != subscriptionResponse, "method %s is a subscription", c.methodName)
+
+	if c.kind == streamingResponse {
+		var response []byte
+		err := c.packResponseFrames(func(frame []byte) error {
+			response = appendFrame(response, frame)
+			return nil
+		}, apiCallResults...)
+		return response, err
+	}
+	if c.passthrough != nil {
+		if results, err := splitError(apiCallResults); err == nil {
+			check.PanicIfNot(len(results) == 1)
+			return c.passthrough(results[0].Interface())
+		}
+	}
+	return c.packSingleResponse(apiCallResults...)
+}
+
+// packResponseFrames packs the elements of the slice returned by a streaming API method one by one
+// and passes the resulting frames to write. An error returned by the API method is packed into a single frame.
+// The frames are packed into a reused buffer, so write must not retain them.
+func (c *methodCodec) packResponseFrames(write func(frame []byte) error, apiCallResults ...reflect.Value) error {
+	check.PanicIfNotf(c.kind == streamingResponse, "method %s is not a streaming one", c.methodName)
+
+	results, err := splitError(apiCallResults)
+	if err != nil {
+		return write(c.packError(err))
+	}
+	check.PanicIfNot(len(results) == 1)
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	items := results[0]
+	noError := reflect.Zero(reflect.TypeFor[error]())
+	for i := range items.Len() {
+		frame, err := c.appendSingleResponse((*buf)[:0], items.Index(i), noError)
+		if err != nil {
+			return err
+		}
+		*buf = frame
+		if err := write(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *methodCodec) packSingleResponse(apiCallResults ...reflect.Value) ([]byte, error) {
+	return c.appendSingleResponse(nil, apiCallResults...)
+}
+
+// appendSingleResponse appends the packed response to the buffer.
+func (c *methodCodec) appendSingleResponse(buf []byte, apiCallResults ...reflect.Value) ([]byte, error) {
+	if c.encodeSSZ {
+		if response, ok := c.appendSSZResponse(buf, apiCallResults); ok {
+			return response, nil
+		}
 	}
-	transaction, ok := pbResponseValuePtr.Interface().(proto.Message)
-	// Should never happen, so we don't pack error to response.
-	check.PanicIfNotf(ok, "failed to create proto transaction %s", c.pbResponseType)
-	response, err := proto.Marshal(transaction)
+	pbResponse := c.responses.get()
+	defer c.responses.put(pbResponse)
+	args := make([]reflect.Value, 0, len(apiCallResults)+1)
+	args = append(args, reflect.ValueOf(pbResponse))
+	if _, err := callMethodWithLastOutputError(c.responsePackMethod.Func, append(args, apiCallResults...)); err != nil {
+		return append(buf, c.packError(err)...), nil
+	}
+	response, err := marshalAppend(buf, pbResponse)
 	if err != nil {
 		return nil, fmt.Errorf("failed to pack Protobuf response: %w", err)
 	}
@@ -80,28 +168,61 @@ func (c *methodCodec) packResponse(apiCallResults ...reflect.Value) ([]byte, err
 }
 
 func (c *methodCodec) packError(err error) []byte {
-	pbResponseValuePtr := reflect.New(c.pbResponseType)
+	pbResponse := c.responses.get()
+	defer c.responses.put(pbResponse)
 	_, err = callMethodWithLastOutputError(
 		c.responsePackMethod.Func,
-		[]reflect.Value{pbResponseValuePtr, reflect.New(c.apiMethodResultType).Elem(), reflect.ValueOf(err)})
+		[]reflect.Value{reflect.ValueOf(pbResponse), reflect.New(c.packedResultType()).Elem(), reflect.ValueOf(err)})
 	check.PanicIfErr(err)
 
-	transaction, ok := pbResponseValuePtr.Interface().(proto.Message)
-	check.PanicIfNotf(ok, "failed to create proto transaction %s", c.pbResponseType)
-	response, err := proto.Marshal(transaction)
+	response, err := proto.Marshal(pbResponse)
 	check.PanicIfErr(err)
 	return response
 }
 
+// packedResultType returns the type accepted by the PackProtoMessage method of the response.
+func (c *methodCodec) packedResultType() reflect.Type {
+	if c.kind != singleResponse {
+		return c.apiMethodResultType.Elem()
+	}
+	return c.apiMethodResultType
+}
+
 func (c *methodCodec) unpackResponse(response []byte) (any, error) {
-	pbResponseValuePtr := reflect.New(c.pbResponseType)
-	transaction, ok := pbResponseValuePtr.Interface().(proto.Message)
-	check.PanicIfNotf(ok, "failed to create proto transaction %s", c.pbResponseType)
-	err := proto.Unmarshal(response, transaction)
+	if c.kind != streamingResponse {
+		return c.unpackSingleResponse(response)
+	}
+
+	frames, err := splitFrames(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack response frames: %w", err)
+	}
+	items := reflect.MakeSlice(c.apiMethodResultType, 0, len(frames))
+	for _, frame := range frames {
+		item, err := c.unpackSingleResponse(frame)
+		if err != nil {
+			return nil, err
+		}
+		items = reflect.Append(items, reflect.ValueOf(item))
+	}
+	return items.Interface(), nil
+}
+
+func (c *methodCodec) unpackSingleResponse(response []byte) (any, error) {
+	if c.ssz != nil && len(response) != 0 && response[0] == sszResponseMarker {
+		result, err := c.ssz.decode(response[1:])
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack SSZ response: %w", err)
+		}
+		return result, nil
+	}
+	pbResponse := c.responses.get()
+	defer c.responses.put(pbResponse)
+	err := proto.Unmarshal(response, pbResponse)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unpack Protobuf response: %w", err)
 	}
-	resp, err := callMethodWithLastOutputError(c.responseUnpackMethod.Func, []reflect.Value{pbResponseValuePtr})
+	resp, err := callMethodWithLastOutputError(c.responseUnpackMethod.Func, []reflect.Value{reflect.ValueOf(pbResponse)})
 	if err != nil {
 		return nil, err
 	}
@@ -136,47 +257,103 @@ type apiCodec map[string]*methodCodec
 //   - The UnpackProtoMessage method of the response type returns the same type as the corresponding API method
 //     and error
 //
-// If any of the conditions are not met, an error is returned.
+// As an exception, an API method may return a slice while the response conversion methods operate on its
+// element type. Such a method is considered streaming: its result is sent as a sequence of response frames.
+// Similarly, a method returning a receive-only channel of the element type is considered a subscription.
+//
+// If any of the conditions are not met, an error listing all the mismatched methods is returned.
 func newApiCodec(api, transport reflect.Type) (apiCodec, error) {
+	return newApiCodecWithSSZResponses(api, transport, sszResponses)
+}
+
+// newApiCodecWithSSZResponses creates the codec encoding the results of the listed methods in SSZ,
+// see sszResponses.
+func newApiCodecWithSSZResponses(
+	api, transport reflect.Type, sszCodecs map[string]*sszResponseCodec,
+) (apiCodec, error) {
 	apiCodec := make(apiCodec)
+	parityErr := &apiParityError{api: api, transport: transport, mismatched: make(map[string]error)}
 	for apiMethod := range common.Filter(iterMethods(api), isExportedMethod) {
 		if err := checkApiMethodSignature(apiMethod); err != nil {
-			return nil, err
+			parityErr.mismatched[apiMethod.Name] = err
+			continue
 		}
 
 		transportMethod, ok := transport.MethodByName(apiMethod.Name)
 		if !ok {
-			return nil, fmt.Errorf("method %s not found in %s", apiMethod.Name, transport)
+			parityErr.missing = append(parityErr.missing, apiMethod.Name)
+			continue
 		}
-		pbRequestType, pbResponseType, err := checkTransportMethodSignatureAndExtractPbTypes(transport, transportMethod)
+		methodCodec, err := newMethodCodec(apiMethod, transport, transportMethod, sszCodecs[apiMethod.Name])
 		if err != nil {
-			return nil, err
+			parityErr.mismatched[apiMethod.Name] = err
+			continue
 		}
-		requestPackMethod, requestUnpackMethod, err := //
-			obtainAndValidateRequestConversionMethods(apiMethod, pbRequestType)
-		if err != nil {
-			return nil, err
-		}
-		responsePackMethod, responseUnpackMethod, err := //
-			obtainAndValidateResponseConversionMethods(apiMethod, pbResponseType)
-		if err != nil {
-			return nil, err
+		apiCodec[apiMethod.Name] = methodCodec
+	}
+	for transportMethod := range iterMethods(transport) {
+		if _, ok := api.MethodByName(transportMethod.Name); !ok {
+			parityErr.extra = append(parityErr.extra, transportMethod.Name)
 		}
+	}
 
-		apiCodec[apiMethod.Name] = &methodCodec{
-			methodName:           apiMethod.Name,
-			apiMethodResultType:  apiMethod.Type.Out(0),
-			pbRequestType:        pbRequestType,
-			pbResponseType:       pbResponseType,
-			requestPackMethod:    requestPackMethod,
-			requestUnpackMethod:  requestUnpackMethod,
-			responsePackMethod:   responsePackMethod,
-			responseUnpackMethod: responseUnpackMethod,
-		}
+	if !parityErr.empty() {
+		return nil, parityErr
 	}
 	return apiCodec, nil
 }
 
+func newMethodCodec(
+	apiMethod reflect.Method,
+	transport reflect.Type,
+	transportMethod reflect.Method,
+	sszCodec *sszResponseCodec,
+) (*methodCodec, error) {
+	pbRequestType, pbResponseType, err := checkTransportMethodSignatureAndExtractPbTypes(transport, transportMethod)
+	if err != nil {
+		return nil, err
+	}
+	requestPackMethod, requestUnpackMethod, err := //
+		obtainAndValidateRequestConversionMethods(apiMethod, pbRequestType)
+	if err != nil {
+		return nil, err
+	}
+	responsePackMethod, responseUnpackMethod, kind, err := //
+		obtainAndValidateResponseConversionMethods(apiMethod, pbResponseType)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests *messagePool
+	if pbRequestType != nil {
+		requests = newMessagePool(pbRequestType)
+	}
+	var passthrough func(result any) ([]byte, error)
+	if kind == singleResponse {
+		passthrough = passthroughResponses[apiMethod.Name]
+	}
+	var ssz *sszResponseCodec
+	if kind != streamingResponse {
+		ssz = sszCodec
+	}
+
+	return &methodCodec{
+		methodName:           apiMethod.Name,
+		apiMethodResultType:  apiMethod.Type.Out(0),
+		pbRequestType:        pbRequestType,
+		pbResponseType:       pbResponseType,
+		requestPackMethod:    requestPackMethod,
+		requestUnpackMethod:  requestUnpackMethod,
+		responsePackMethod:   responsePackMethod,
+		responseUnpackMethod: responseUnpackMethod,
+		requests:             requests,
+		responses:            newMessagePool(pbResponseType),
+		kind:                 kind,
+		passthrough:          passthrough,
+		ssz:                  ssz,
+	}, nil
+}
+
 func iterMethods(t reflect.Type) iter.Seq[reflect.Method] {
 	type Yield = func(p reflect.Method) bool
 	return func(yield Yield) {
@@ -315,19 +492,19 @@ func obtainAndValidateRequestConversionMethods(
 func obtainAndValidateResponseConversionMethods(
 	apiMethod reflect.Method,
 	pbResponseType reflect.Type,
-) (reflect.Method, reflect.Method, error) {
+) (reflect.Method, reflect.Method, responseKind, error) {
 	const packMethodName = "PackProtoMessage"
 	const unpackMethodName = "UnpackProtoMessage"
 
 	packProtoMessage, ok := reflect.PointerTo(pbResponseType).MethodByName(packMethodName)
 	if !ok {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"method %s not found in %s", packMethodName, pbResponseType)
 	}
 
 	unpackProtoMessage, ok := reflect.PointerTo(pbResponseType).MethodByName(unpackMethodName)
 	if !ok {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"method %s not found in %s", unpackMethodName, pbResponseType)
 	}
 
@@ -336,42 +513,57 @@ func obtainAndValidateResponseConversionMethods(
 	unpackProtoMessageType := unpackProtoMessage.Type
 
 	if packProtoMessageType.NumIn()-1 != 2 {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"%s must accept exactly 2 arguments, but accepted %d",
 			packMethodName, packProtoMessageType.NumIn()-1)
 	}
 	if !isErrorType(packProtoMessageType.In(2)) {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf("last argument of %s must be error", packMethodName)
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
+			"last argument of %s must be error", packMethodName)
 	}
 
 	if unpackProtoMessageType.NumIn() != 1 {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"%s must accept exactly 1 argument, but accepted %d",
 			unpackMethodName, unpackProtoMessageType.NumIn())
 	}
 	if unpackProtoMessageType.NumOut() != 2 {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"%s must return exactly 2 values, but returned %d",
 			unpackMethodName, unpackProtoMessageType.NumOut())
 	}
 	if !isErrorType(unpackProtoMessageType.Out(1)) {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"last output argument of %s must be error", unpackMethodName)
 	}
 
-	if apiMethodType.Out(0) != packProtoMessageType.In(1) {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+	resultType := apiMethodType.Out(0)
+	kind := singleResponse
+	if resultType != packProtoMessageType.In(1) {
+		switch {
+		case resultType.Kind() == reflect.Slice && resultType.Elem() == packProtoMessageType.In(1):
+			kind = streamingResponse
+			resultType = resultType.Elem()
+		case resultType.Kind() == reflect.Chan && resultType.ChanDir() == reflect.RecvDir &&
+			resultType.Elem() == packProtoMessageType.In(1):
+			kind = subscriptionResponse
+			resultType = resultType.Elem()
+		}
+	}
+
+	if resultType != packProtoMessageType.In(1) {
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"API method outputs %s type, but %s expects %s",
 			apiMethodType.Out(0), packMethodName, packProtoMessageType.In(1))
 	}
 
-	if apiMethodType.Out(0) != unpackProtoMessageType.Out(0) {
-		return reflect.Method{}, reflect.Method{}, fmt.Errorf(
+	if resultType != unpackProtoMessageType.Out(0) {
+		return reflect.Method{}, reflect.Method{}, singleResponse, fmt.Errorf(
 			"API method outputs %s type, but %s expects %s",
 			apiMethodType.Out(0), unpackMethodName, unpackProtoMessageType.Out(0))
 	}
 
-	return packProtoMessage, unpackProtoMessage, nil
+	return packProtoMessage, unpackProtoMessage, kind, nil
 }
 
 func isErrorType(t reflect.Type) bool {
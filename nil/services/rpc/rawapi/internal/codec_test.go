@@ -2,12 +2,17 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"testing"
 
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
 	"github.com/stretchr/testify/require"
 )
 
@@ -88,6 +93,39 @@ func TestApisCompatibility(t *testing.T) {
 	}
 }
 
+type mismatchedNetworkTransportProtocol interface {
+	TestMethod(pb.BlockRequest) pb.RawBlockResponse
+	OtherMethod(pb.BlockRequest) pb.RawBlockResponse
+	ExtraMethod(pb.BlockRequest) pb.RawBlockResponse
+}
+
+type mismatchedApi interface {
+	TestMethod(ctx context.Context, blockReference rawapitypes.BlockReference) (int, error)
+	OtherMethod(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error)
+	MissingMethod(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error)
+}
+
+func TestApiParity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ServedApis", func(t *testing.T) {
+		t.Parallel()
+
+		require.NoError(t, validateServedApis())
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newApiCodec(reflect.TypeFor[mismatchedApi](), reflect.TypeFor[mismatchedNetworkTransportProtocol]())
+		require.EqualError(t, err,
+			"internal.mismatchedApi does not match internal.mismatchedNetworkTransportProtocol:\n"+
+				"\t+ ExtraMethod: method ExtraMethod not found in internal.mismatchedApi\n"+
+				"\t- MissingMethod: method MissingMethod not found in internal.mismatchedNetworkTransportProtocol\n"+
+				"\t~ TestMethod: API method outputs int type, but PackProtoMessage expects []uint8")
+	})
+}
+
 type noArgsNetworkTransportProtocol interface {
 	TestMethod() pb.RawBlockResponse
 }
@@ -127,3 +165,336 @@ func TestApisNoArgs(t *testing.T) {
 		})
 	}
 }
+
+type streamingApi interface {
+	TestMethod(ctx context.Context, blockReference rawapitypes.BlockReference) ([]sszx.SSZEncodedData, error)
+}
+
+func TestStreamingMethodCodec(t *testing.T) {
+	t.Parallel()
+
+	codec, err := newApiCodec(
+		reflect.TypeFor[streamingApi](), reflect.TypeFor[compatibleNetworkTransportProtocol]())
+	require.NoError(t, err)
+
+	methodCodec := codec["TestMethod"]
+	require.Equal(t, streamingResponse, methodCodec.kind)
+
+	t.Run("Data", func(t *testing.T) {
+		t.Parallel()
+
+		blocks := []sszx.SSZEncodedData{{1, 2, 3}, {4}, {5, 6}}
+		response, err := methodCodec.packResponse(reflect.ValueOf(blocks), reflect.Zero(reflect.TypeFor[error]()))
+		require.NoError(t, err)
+
+		frames, err := splitFrames(response)
+		require.NoError(t, err)
+		require.Len(t, frames, len(blocks))
+
+		unpacked, err := unpackResponse[[]sszx.SSZEncodedData](methodCodec, response)
+		require.NoError(t, err)
+		require.Equal(t, blocks, unpacked)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		response, err := methodCodec.packResponse(
+			reflect.Zero(reflect.TypeFor[[]sszx.SSZEncodedData]()), reflect.ValueOf(errors.New("test error")))
+		require.NoError(t, err)
+
+		_, err = unpackResponse[[]sszx.SSZEncodedData](methodCodec, response)
+		require.ErrorContains(t, err, "test error")
+	})
+}
+
+func TestSyncApiBlocksSinceIsStreaming(t *testing.T) {
+	t.Parallel()
+
+	codec, err := newApiCodec(reflect.TypeFor[shardApiSync](), reflect.TypeFor[NetworkTransportProtocolSync]())
+	require.NoError(t, err)
+	require.Equal(t, streamingResponse, codec["GetBlocksSince"].kind)
+	require.Equal(t, singleResponse, codec["GetSnapshotChunk"].kind)
+}
+
+func TestFullBlockDataPassthrough(t *testing.T) {
+	t.Parallel()
+
+	codec, err := newApiCodec(reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo]())
+	require.NoError(t, err)
+	methodCodec := codec["GetFullBlockData"]
+	require.NotNil(t, methodCodec.passthrough)
+
+	block := &types.RawBlockWithExtractedData{
+		Block:          []byte{1, 2, 3},
+		InTransactions: []sszx.SSZEncodedData{{4}, {5, 6}},
+		ChildBlocks:    []common.Hash{common.HexToHash("0x01")},
+		Config:         map[string][]byte{"gasPrice": {7}},
+	}
+	response, err := methodCodec.packResponse(reflect.ValueOf(block), reflect.Zero(reflect.TypeFor[error]()))
+	require.NoError(t, err)
+	unpacked, err := unpackResponse[*types.RawBlockWithExtractedData](methodCodec, response)
+	require.NoError(t, err)
+	require.Equal(t, block.Block, unpacked.Block)
+	require.Equal(t, block.InTransactions, unpacked.InTransactions)
+	require.Equal(t, block.ChildBlocks, unpacked.ChildBlocks)
+	require.Equal(t, block.Config, unpacked.Config)
+
+	response, err = methodCodec.packResponse(
+		reflect.Zero(reflect.TypeFor[*types.RawBlockWithExtractedData]()), reflect.ValueOf(errors.New("test error")))
+	require.NoError(t, err)
+	_, err = unpackResponse[*types.RawBlockWithExtractedData](methodCodec, response)
+	require.ErrorContains(t, err, "test error")
+}
+
+func TestSSZResponses(t *testing.T) {
+	t.Parallel()
+
+	codec, err := newApiCodec(reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo]())
+	require.NoError(t, err)
+	noError := reflect.Zero(reflect.TypeFor[error]())
+
+	t.Run("BlockHeader", func(t *testing.T) {
+		t.Parallel()
+
+		methodCodec := codec["GetBlockHeader"]
+		sszCodec := methodCodec.sszEncoding()
+		require.NotNil(t, sszCodec)
+
+		header := sszx.SSZEncodedData{1, 2, 3}
+		response, err := sszCodec.packResponse(reflect.ValueOf(header), noError)
+		require.NoError(t, err)
+		require.Equal(t, append([]byte{sszResponseMarker}, header...), response)
+		// The client unpacks both encodings with the same codec.
+		unpacked, err := unpackResponse[sszx.SSZEncodedData](methodCodec, response)
+		require.NoError(t, err)
+		require.Equal(t, header, unpacked)
+
+		response, err = methodCodec.packResponse(reflect.ValueOf(header), noError)
+		require.NoError(t, err)
+		require.NotEqual(t, sszResponseMarker, response[0])
+		unpacked, err = unpackResponse[sszx.SSZEncodedData](methodCodec, response)
+		require.NoError(t, err)
+		require.Equal(t, header, unpacked)
+
+		// The errors and the missing data are sent in Protobuf.
+		response, err = sszCodec.packResponse(
+			reflect.Zero(reflect.TypeFor[sszx.SSZEncodedData]()), reflect.ValueOf(errors.New("test error")))
+		require.NoError(t, err)
+		_, err = unpackResponse[sszx.SSZEncodedData](methodCodec, response)
+		require.ErrorContains(t, err, "test error")
+
+		response, err = sszCodec.packResponse(reflect.Zero(reflect.TypeFor[sszx.SSZEncodedData]()), noError)
+		require.NoError(t, err)
+		_, err = unpackResponse[sszx.SSZEncodedData](methodCodec, response)
+		require.ErrorContains(t, err, "block should not be nil")
+	})
+
+	t.Run("Transactions", func(t *testing.T) {
+		t.Parallel()
+
+		methodCodec := codec["GetTxpoolContent"]
+		transactions := []*types.Transaction{
+			{From: types.ShardAndHexToAddress(types.BaseShardId, "0x01"), Value: types.NewValueFromUint64(100)},
+			{
+				TransactionDigest: types.TransactionDigest{Seqno: 7},
+				From:              types.ShardAndHexToAddress(types.BaseShardId, "0x02"),
+			},
+		}
+		for _, txns := range [][]*types.Transaction{transactions, {}} {
+			response, err := methodCodec.sszEncoding().packResponse(reflect.ValueOf(txns), noError)
+			require.NoError(t, err)
+			require.Equal(t, sszResponseMarker, response[0])
+
+			unpacked, err := unpackResponse[[]*types.Transaction](methodCodec, response)
+			require.NoError(t, err)
+			require.Len(t, unpacked, len(txns))
+			for i, txn := range txns {
+				require.Equal(t, txn.Hash(), unpacked[i].Hash())
+			}
+		}
+	})
+
+	t.Run("FullBlock", func(t *testing.T) {
+		t.Parallel()
+
+		methodCodec := codec["GetFullBlockData"]
+		block := &types.RawBlockWithExtractedData{
+			Block:           sszx.SSZEncodedData{1, 2, 3},
+			InTransactions:  []sszx.SSZEncodedData{{4}, {5, 6}},
+			InTxCounts:      []sszx.SSZEncodedData{{7}},
+			OutTransactions: []sszx.SSZEncodedData{{8}},
+			OutTxCounts:     []sszx.SSZEncodedData{{9}},
+			Receipts:        []sszx.SSZEncodedData{{10}, {11}},
+			Errors:          map[common.Hash]string{common.HexToHash("0x02"): "failed", common.HexToHash("0x01"): ""},
+			ChildBlocks:     []common.Hash{common.HexToHash("0x03"), common.HexToHash("0x04")},
+			DbTimestamp:     12,
+			Config:          map[string][]byte{"gasPrice": {13}, "validators": {14, 15}},
+		}
+		response, err := methodCodec.sszEncoding().packResponse(reflect.ValueOf(block), noError)
+		require.NoError(t, err)
+		require.Equal(t, sszResponseMarker, response[0])
+
+		unpacked, err := unpackResponse[*types.RawBlockWithExtractedData](methodCodec, response)
+		require.NoError(t, err)
+		require.Equal(t, block, unpacked)
+
+		// The block without the optional data is encoded as well.
+		block = &types.RawBlockWithExtractedData{Block: sszx.SSZEncodedData{1}}
+		response, err = methodCodec.sszEncoding().packResponse(reflect.ValueOf(block), noError)
+		require.NoError(t, err)
+		unpacked, err = unpackResponse[*types.RawBlockWithExtractedData](methodCodec, response)
+		require.NoError(t, err)
+		require.Equal(t, block, unpacked)
+
+		_, err = sszFullBlockResponse.decode(appendSSZList(nil, []sszx.SSZEncodedData{{1}}))
+		require.ErrorIs(t, err, errInvalidSSZList)
+	})
+
+	t.Run("InTransaction", func(t *testing.T) {
+		t.Parallel()
+
+		methodCodec := codec["GetInTransaction"]
+		info := &rawapitypes.TransactionInfo{
+			TransactionSSZ: []byte{1, 2},
+			ReceiptSSZ:     []byte{3},
+			Index:          4,
+			BlockHash:      common.HexToHash("0x05"),
+			BlockId:        6,
+		}
+		response, err := methodCodec.sszEncoding().packResponse(reflect.ValueOf(info), noError)
+		require.NoError(t, err)
+		require.Equal(t, sszResponseMarker, response[0])
+
+		unpacked, err := unpackResponse[*rawapitypes.TransactionInfo](methodCodec, response)
+		require.NoError(t, err)
+		require.Equal(t, info, unpacked)
+
+		_, err = sszTransactionInfoResponse.decode(appendSSZList(nil, []sszx.SSZEncodedData{{1}, {2}, {3}, {4}, {5}}))
+		require.ErrorIs(t, err, errInvalidSSZList)
+	})
+
+	t.Run("NotEncoded", func(t *testing.T) {
+		t.Parallel()
+
+		require.Nil(t, codec["GetBalance"].sszEncoding())
+		require.Nil(t, codec["GetReceipt"].sszEncoding())
+	})
+
+	t.Run("InvalidList", func(t *testing.T) {
+		t.Parallel()
+
+		items, err := splitSSZList(appendSSZList(nil, []sszx.SSZEncodedData{{1}, {}, {2, 3}}))
+		require.NoError(t, err)
+		require.Equal(t, []sszx.SSZEncodedData{{1}, {}, {2, 3}}, items)
+
+		for _, data := range [][]byte{{1}, {0, 0, 0, 0}, {3, 0, 0, 0, 1}, {8, 0, 0, 0, 9, 0, 0, 0}} {
+			_, err := splitSSZList(data)
+			require.ErrorIs(t, err, errInvalidSSZList)
+		}
+	})
+}
+
+func TestRequestValidation(t *testing.T) {
+	t.Parallel()
+
+	codec, err := newApiCodec(reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo]())
+	require.NoError(t, err)
+	getLogsCodec := codec["GetLogs"]
+
+	request, err := getLogsCodec.packRequest(rawapitypes.LogFilter{Topics: make([][]common.Hash, 2)})
+	require.NoError(t, err)
+	_, err = getLogsCodec.unpackRequest(request)
+	require.NoError(t, err)
+
+	request, err = getLogsCodec.packRequest(rawapitypes.LogFilter{Topics: make([][]common.Hash, maxLogFilterTopics+1)})
+	require.NoError(t, err)
+	_, err = getLogsCodec.unpackRequest(request)
+	require.Equal(t, rawapitypes.InvalidArgumentErrorCode, rawapitypes.ErrorCodeOf(err))
+
+	toBlock := types.BlockNumber(maxLogsBlockRange)
+	request, err = getLogsCodec.packRequest(rawapitypes.LogFilter{ToBlock: &toBlock})
+	require.NoError(t, err)
+	_, err = getLogsCodec.unpackRequest(request)
+	require.ErrorIs(t, err, errLogsBlockRangeTooWide)
+
+	getBalancesCodec := codec["GetBalances"]
+	request, err = getBalancesCodec.packRequest(
+		make([]types.Address, maxMultiAccountRequestSize+1),
+		rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+	require.NoError(t, err)
+	_, err = getBalancesCodec.unpackRequest(request)
+	require.Equal(t, rawapitypes.InvalidArgumentErrorCode, rawapitypes.ErrorCodeOf(err))
+}
+
+// benchmarkMethodCodec measures the round trip of the request and the response of the method
+// through the codec under concurrent load.
+func benchmarkMethodCodec(b *testing.B, methodName string, args []any, result any) {
+	b.Helper()
+
+	codec, err := newApiCodec(reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo]())
+	require.NoError(b, err)
+	methodCodec := codec[methodName]
+	noError := reflect.Zero(reflect.TypeFor[error]())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(p *testing.PB) {
+		for p.Next() {
+			request, err := methodCodec.packRequest(args...)
+			if err != nil {
+				b.Error(err)
+				return
+			}
+			if _, err := methodCodec.unpackRequest(request); err != nil {
+				b.Error(err)
+				return
+			}
+			response, err := methodCodec.packResponse(reflect.ValueOf(result), noError)
+			if err != nil {
+				b.Error(err)
+				return
+			}
+			if _, err := methodCodec.unpackResponse(response); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+func BenchmarkCallCodec(b *testing.B) {
+	data := hexutil.Bytes(make([]byte, 256))
+	args := []any{
+		rpctypes.CallArgs{
+			To:    types.GenerateRandomAddress(types.BaseShardId),
+			Value: types.NewValueFromUint64(1),
+			Data:  &data,
+		},
+		rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(
+			rawapitypes.BlockNumberAsBlockReference(types.BlockNumber(1))),
+		(*rpctypes.StateOverrides)(nil),
+		(*rpctypes.BlockOverrides)(nil),
+	}
+	result := &rpctypes.CallResWithGasPrice{
+		Data:      make([]byte, 256),
+		CoinsUsed: types.NewValueFromUint64(21000),
+		BaseFee:   types.NewValueFromUint64(10),
+	}
+	benchmarkMethodCodec(b, "Call", args, result)
+}
+
+func BenchmarkGetFullBlockDataCodec(b *testing.B) {
+	args := []any{rawapitypes.BlockNumberAsBlockReference(types.BlockNumber(1))}
+	result := &types.RawBlockWithExtractedData{
+		Block:       make([]byte, 1024),
+		ChildBlocks: []common.Hash{common.HexToHash("0x01")},
+		DbTimestamp: 1,
+	}
+	for range 100 {
+		result.InTransactions = append(result.InTransactions, make([]byte, 256))
+		result.OutTransactions = append(result.OutTransactions, make([]byte, 256))
+		result.Receipts = append(result.Receipts, make([]byte, 128))
+	}
+	benchmarkMethodCodec(b, "GetFullBlockData", args, result)
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// defaultCompressionThreshold is the minimal size of a compressed response. Smaller responses are sent as is,
+	// since their transfer takes less time than the compression.
+	defaultCompressionThreshold = 16 * 1024
+
+	// maxDecompressedSize protects the client from the responses that decompress into too much data.
+	maxDecompressedSize = 256 * 1024 * 1024
+
+	// clientCompression is the compression accepted by the network client.
+	clientCompression = pb.Compression_ZstdCompression
+)
+
+var errDecompressedSizeExceeded = fmt.Errorf("decompressed response exceeds %d bytes", maxDecompressedSize)
+
+// The encoder and the decoder are safe for concurrent use by EncodeAll and DecodeAll.
+var (
+	zstdEncoder = mustCreate(zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1)))
+	zstdDecoder = mustCreate(zstd.NewReader(
+		nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(maxDecompressedSize)))
+)
+
+func mustCreate[T any](v T, err error) T {
+	check.PanicIfErr(err)
+	return v
+}
+
+func compressionThreshold(threshold int) int {
+	if threshold == 0 {
+		return defaultCompressionThreshold
+	}
+	return threshold
+}
+
+// packResponseEnvelope wraps the response for the client accepting the compression.
+// The response is compressed only if it is not smaller than the threshold.
+func packResponseEnvelope(response []byte, compression pb.Compression, threshold int) *pb.ResponseEnvelope {
+	envelope := &pb.ResponseEnvelope{Payload: response}
+	if threshold >= 0 && len(response) >= compressionThreshold(threshold) {
+		switch compression {
+		case pb.Compression_ZstdCompression:
+			envelope.Payload = zstdEncoder.EncodeAll(response, nil)
+		case pb.Compression_SnappyCompression:
+			envelope.Payload = snappy.Encode(nil, response)
+		case pb.Compression_NoCompression:
+		default:
+			// The compression isn't supported, so the response is sent uncompressed.
+			compression = pb.Compression_NoCompression
+		}
+		envelope.Compression = compression
+	}
+	return envelope
+}
+
+// decompressResponse returns the decompressed payload of the envelope.
+func decompressResponse(envelope *pb.ResponseEnvelope) ([]byte, error) {
+	switch envelope.GetCompression() {
+	case pb.Compression_NoCompression:
+		return envelope.GetPayload(), nil
+	case pb.Compression_ZstdCompression:
+		payload, err := zstdDecoder.DecodeAll(envelope.GetPayload(), nil)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) {
+			return nil, errDecompressedSizeExceeded
+		}
+		return payload, err
+	case pb.Compression_SnappyCompression:
+		size, err := snappy.DecodedLen(envelope.GetPayload())
+		if err != nil {
+			return nil, err
+		}
+		if size > maxDecompressedSize {
+			return nil, errDecompressedSizeExceeded
+		}
+		return snappy.Decode(nil, envelope.GetPayload())
+	}
+	return nil, fmt.Errorf("unsupported compression %s", envelope.GetCompression())
+}
@@ -0,0 +1,15 @@
+package internal
+
+import (
+	"syscall"
+	"time"
+)
+
+// threadCpuTime returns the CPU time consumed by the OS thread of the caller.
+func threadCpuTime() (time.Duration, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_THREAD, &usage); err != nil {
+		return 0, false
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), true
+}
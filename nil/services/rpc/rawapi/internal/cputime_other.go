@@ -0,0 +1,10 @@
+//go:build !linux
+
+package internal
+
+import "time"
+
+// threadCpuTime is not supported on this platform, the call time is not accounted.
+func threadCpuTime() (time.Duration, bool) {
+	return 0, false
+}
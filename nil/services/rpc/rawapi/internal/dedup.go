@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+)
+
+// deduplicatedApis are the APIs whose methods only read the data, so the identical requests handled
+// at the same time get the response of a single call.
+var deduplicatedApis = map[string]struct{}{
+	apiNameRo:      {},
+	apiNameCluster: {},
+}
+
+// getDeduplicatedRequestsCounter counts the requests that got the response of a call shared with other requests.
+var getDeduplicatedRequestsCounter = sync.OnceValue(func() telemetry.Counter {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/rpc/rawapi")
+	return telemetry.Int64Counter(meter, "deduplicated_requests")
+})
+
+// requestDeduplicator collapses the identical concurrent requests of the methods of an API into one call.
+type requestDeduplicator struct {
+	calls   singleflight.Group
+	counter telemetry.Counter
+	option  metric.MeasurementOption
+
+	// waiting is the number of the requests waiting for the responses of the calls, the callers included.
+	waiting atomic.Int64
+}
+
+// newRequestDeduplicator returns nil if the requests of the API are not deduplicated.
+func newRequestDeduplicator(apiName string, shardId types.ShardId, disabled bool) *requestDeduplicator {
+	if _, ok := deduplicatedApis[apiName]; !ok || disabled {
+		return nil
+	}
+	return &requestDeduplicator{
+		counter: getDeduplicatedRequestsCounter(),
+		option:  telattr.With(telattr.ShardId(shardId)),
+	}
+}
+
+// dedupedResponse is the response of a call shared by the requests along with the blocks the call read.
+type dedupedResponse struct {
+	response []byte
+	resolved *resolvedBlock
+}
+
+// wrapRequestHandler makes the requests equal to the one being handled wait for its response. The call is not
+// cancelled when the first caller goes away, since the others may still wait for it, but its deadline is kept.
+// The requests conditional on different blocks are not equal, and the response of the call is tagged by its blocks
+// for every request sharing it.
+func (d *requestDeduplicator) wrapRequestHandler(
+	codec *methodCodec, handler network.RequestHandler,
+) network.RequestHandler {
+	if d == nil {
+		return handler
+	}
+	methodOption := metric.WithAttributes(telattr.RpcMethod(codec.methodName))
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		key := responseKey(ctx, codec.methodName, request)
+		resolved := getResolvedBlock(ctx)
+		if resolved != nil && !resolved.ifChanged.Empty() {
+			key = resolved.ifChanged.Hex() + "/" + key
+		}
+		results := d.calls.DoChan(key, func() (any, error) {
+			callCtx := context.WithoutCancel(ctx)
+			if deadline, ok := ctx.Deadline(); ok {
+				var cancel context.CancelFunc
+				callCtx, cancel = context.WithDeadline(callCtx, deadline)
+				defer cancel()
+			}
+			var callResolved *resolvedBlock
+			if resolved != nil {
+				callCtx, callResolved = withResolvedBlock(callCtx, resolved.ifChanged)
+			}
+			response, err := handler(callCtx, request)
+			return dedupedResponse{response: response, resolved: callResolved}, err
+		})
+		// The request is already waiting for the call when DoChan returns.
+		d.waiting.Add(1)
+		defer d.waiting.Add(-1)
+
+		select {
+		case result := <-results:
+			if result.Shared {
+				d.counter.Add(ctx, 1, d.option, methodOption)
+			}
+			if result.Err != nil {
+				return nil, result.Err
+			}
+			deduped, _ := result.Val.(dedupedResponse)
+			if resolved != nil && deduped.resolved != nil {
+				resolved.merge(deduped.resolved)
+			}
+			return deduped.response, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestDeduplicator(t *testing.T) {
+	t.Parallel()
+
+	// Only the read-only APIs are deduplicated, unless it's disabled.
+	require.Nil(t, newRequestDeduplicator(apiNameRw, types.BaseShardId, false))
+	require.Nil(t, newRequestDeduplicator(apiNameRo, types.BaseShardId, true))
+
+	deduplicator := newRequestDeduplicator(apiNameRo, types.BaseShardId, false)
+	require.NotNil(t, deduplicator)
+	counter := new(testCounter)
+	deduplicator.counter = counter
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	handler := deduplicator.wrapRequestHandler(
+		&methodCodec{methodName: "GetBlockHeader"},
+		func(_ context.Context, request []byte) ([]byte, error) {
+			calls.Add(1)
+			<-release
+			return request, nil
+		})
+
+	type response struct {
+		payload []byte
+		err     error
+	}
+	responses := make(chan response)
+	send := func(request string) {
+		go func() {
+			payload, err := handler(t.Context(), []byte(request))
+			responses <- response{payload, err}
+		}()
+	}
+	// waitFor waits until the number of the requests waiting for the calls is reached.
+	waitFor := func(waiting int64) {
+		require.Eventually(t, func() bool {
+			return deduplicator.waiting.Load() == waiting
+		}, time.Second, 10*time.Millisecond)
+	}
+
+	// All the identical requests wait for the call made by the first of them, a different request makes its own.
+	const requests = 5
+	for range requests {
+		send("latest")
+	}
+	waitFor(requests)
+	send("finalized")
+	waitFor(requests + 1)
+
+	close(release)
+	received := make(map[string]int)
+	for range requests + 1 {
+		response := <-responses
+		require.NoError(t, response.err)
+		received[string(response.payload)]++
+	}
+	require.Equal(t, map[string]int{"latest": requests, "finalized": 1}, received)
+	require.EqualValues(t, 2, calls.Load())
+	// Each of the requests sharing a call is counted.
+	require.EqualValues(t, requests, counter.added.Load())
+	require.Zero(t, deduplicator.waiting.Load())
+}
@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const currentMethodAttribute = "currentMethod"
+
+// deprecatedMethodNames are the earlier names of the renamed methods by their current names,
+// e.g., "GetReceipt": {"GetInTransactionReceipt"}. The methods are served by the protocol IDs of both names,
+// so that the deployed clients keep working after the rename, and the requests by the earlier names
+// are counted to tell when the names can be dropped.
+var deprecatedMethodNames = map[string][]string{
+	"GetReceipt": {"GetInTransactionReceipt"},
+}
+
+// getDeprecatedRequestsCounter counts the requests of the methods by their deprecated names.
+var getDeprecatedRequestsCounter = sync.OnceValue(func() telemetry.Counter {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/rpc/rawapi")
+	return telemetry.Int64Counter(meter, "deprecated_requests")
+})
+
+// deprecationNotice counts the requests by a deprecated name of a method and warns about the first one.
+type deprecationNotice struct {
+	counter telemetry.Counter
+	option  metric.MeasurementOption
+	warn    func()
+}
+
+func newDeprecationNotice(
+	shardId types.ShardId, deprecatedName string, methodName string, logger logging.Logger,
+) *deprecationNotice {
+	return &deprecationNotice{
+		counter: getDeprecatedRequestsCounter(),
+		option: telattr.With(
+			telattr.ShardId(shardId),
+			telattr.RpcMethod(deprecatedName),
+			attribute.String(currentMethodAttribute, methodName)),
+		warn: sync.OnceFunc(func() {
+			logger.Warn().
+				Str("deprecatedMethod", deprecatedName).
+				Str(currentMethodAttribute, methodName).
+				Msg("Method is requested by its deprecated name")
+		}),
+	}
+}
+
+func (n *deprecationNotice) record(ctx context.Context) {
+	n.counter.Add(ctx, 1, n.option)
+	n.warn()
+}
+
+func withDeprecationNotice(handler network.RequestHandler, notice *deprecationNotice) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		notice.record(ctx)
+		return handler(ctx, request)
+	}
+}
+
+func withStreamDeprecationNotice(handler network.StreamHandler, notice *deprecationNotice) network.StreamHandler {
+	return func(stream network.Stream) {
+		notice.record(context.Background())
+		handler(stream)
+	}
+}
+
+// registerDeprecatedNames also serves the handlers of the renamed methods by the protocol IDs
+// of their deprecated names in all the versions. The requests by the deprecated names are handled,
+// intercepted and measured as the ones of the current name, plus they are counted by the notice.
+func registerDeprecatedNames[T any](
+	handlers map[network.ProtocolID]T,
+	withNotice func(T, *deprecationNotice) T,
+	shardId types.ShardId,
+	logger logging.Logger,
+) {
+	for _, protocol := range slices.Collect(maps.Keys(handlers)) {
+		methodName := path.Base(string(protocol))
+		for _, deprecatedName := range deprecatedMethodNames[methodName] {
+			deprecatedProtocol := network.ProtocolID(path.Join(path.Dir(string(protocol)), deprecatedName))
+			_, exists := handlers[deprecatedProtocol]
+			check.PanicIfNotf(!exists, "deprecated name %s of method %s is served", deprecatedName, methodName)
+
+			notice := newDeprecationNotice(shardId, deprecatedName, methodName,
+				logger.With().Str(logging.FieldProtocolID, string(deprecatedProtocol)).Logger())
+			handlers[deprecatedProtocol] = withNotice(handlers[protocol], notice)
+		}
+	}
+}
+
+// validateDeprecatedNames checks that the deprecated names don't shadow the methods of the APIs.
+func validateDeprecatedNames(apiMethodNames map[string]struct{}) error {
+	for methodName, deprecatedNames := range deprecatedMethodNames {
+		for _, deprecatedName := range deprecatedNames {
+			if _, ok := apiMethodNames[deprecatedName]; ok {
+				return fmt.Errorf("deprecated name %s of method %s is a served method", deprecatedName, methodName)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type testCounter struct {
+	noop.Int64Counter
+
+	added atomic.Int64
+}
+
+func (c *testCounter) Add(_ context.Context, incr int64, _ ...metric.AddOption) {
+	c.added.Add(incr)
+}
+
+func TestDeprecationNotice(t *testing.T) {
+	t.Parallel()
+
+	var logs bytes.Buffer
+	logger := logging.NewFromZerolog(zerolog.New(&logs).Level(zerolog.WarnLevel))
+	counter := new(testCounter)
+
+	var served int
+	handlers := map[network.ProtocolID]network.RequestHandler{
+		makeProtocolId(1, apiNameRo, "GetReceipt"): func(context.Context, []byte) ([]byte, error) {
+			served++
+			return nil, nil
+		},
+	}
+	withNotice := func(handler network.RequestHandler, notice *deprecationNotice) network.RequestHandler {
+		notice.counter = counter
+		return withDeprecationNotice(handler, notice)
+	}
+	registerDeprecatedNames(handlers, withNotice, 1, logger)
+
+	// The method is served by its deprecated name, the requests by which are counted and warned about once.
+	handler, ok := handlers[makeProtocolId(1, apiNameRo, "GetInTransactionReceipt")]
+	require.True(t, ok)
+	for range 3 {
+		_, err := handler(t.Context(), nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, served)
+	require.EqualValues(t, 3, counter.added.Load())
+	require.Equal(t, 1, bytes.Count(logs.Bytes(), []byte("deprecated name")))
+	require.Contains(t, logs.String(), `"deprecatedMethod":"GetInTransactionReceipt"`)
+	require.Contains(t, logs.String(), `"currentMethod":"GetReceipt"`)
+
+	// The current name is not counted.
+	_, err := handlers[makeProtocolId(1, apiNameRo, "GetReceipt")](t.Context(), nil)
+	require.NoError(t, err)
+	require.Equal(t, 4, served)
+	require.EqualValues(t, 3, counter.added.Load())
+}
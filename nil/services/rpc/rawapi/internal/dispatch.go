@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// methodDispatcher serves a request of a single-response API method without the reflection.
+// It returns the packed response, the errors are packed by the caller with the codec of the method.
+// The errors of the API packed by the dispatcher itself are recorded with recordRequestError.
+type methodDispatcher func(ctx context.Context, request []byte) ([]byte, error)
+
+// dispatcherFactories create the dispatchers of the methods of an API implementation by the type of the API.
+// They are registered by the code generated with dispatchgen, the APIs without them are served by the reflection.
+var dispatcherFactories = make(map[reflect.Type]func(api any) map[string]methodDispatcher)
+
+// registerDispatchers is called by the generated code on initialization.
+func registerDispatchers[Api any](newDispatchers func(api Api) map[string]methodDispatcher) {
+	dispatcherFactories[reflect.TypeFor[Api]()] = func(api any) map[string]methodDispatcher {
+		return newDispatchers(api.(Api))
+	}
+}
+
+func newDispatchers(apiType reflect.Type, api any) map[string]methodDispatcher {
+	factory, ok := dispatcherFactories[apiType]
+	if !ok {
+		return nil
+	}
+	return factory(api)
+}
+
+func makeDispatchedRequestHandler(
+	dispatch methodDispatcher,
+	codec *methodCodec,
+	logger logging.Logger,
+) network.RequestHandler {
+	return func(ctx context.Context, request []byte) (response []byte, err error) {
+		defer func() {
+			if err != nil {
+				recordRequestError(ctx, err)
+				response, err = codec.packError(err), nil
+			}
+		}()
+		defer recoverApiPanic(logger, &err)
+		return dispatch(ctx, request)
+	}
+}
+
+func unmarshalRequest(request []byte, pbRequest proto.Message) error {
+	if err := proto.Unmarshal(request, pbRequest); err != nil {
+		return rawapitypes.NewInvalidArgumentError(fmt.Errorf("failed to unpack Protobuf request: %w", err))
+	}
+	return nil
+}
+
+// wrapRequestUnpackError classifies the errors of the request conversion as invalid arguments,
+// unless they already have a code (e.g., an invalid block reference).
+func wrapRequestUnpackError(err error) error {
+	var apiErr *rawapitypes.Error
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	return rawapitypes.NewInvalidArgumentError(err)
+}
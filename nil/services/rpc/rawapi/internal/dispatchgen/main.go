@@ -0,0 +1,528 @@
+// dispatchgen emits the typed request handlers of the raw API methods, so that the requests are served
+// without the reflection. For every pair of an API interface and its NetworkTransportProtocol* interface given
+// as "api:transport", it generates the dispatchers of the methods with a single response. The handlers of the
+// streaming methods and subscriptions remain reflection-based.
+//
+// A mismatch between an API interface and the conversion methods of its Protobuf types is reported by the tool,
+// and the drift of the types the tool can't check is detected when the generated code is compiled.
+//
+// With -mock, it emits the mocks of the API interfaces to another package instead, see generateMocks.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	packMethodName   = "PackProtoMessage"
+	unpackMethodName = "UnpackProtoMessage"
+)
+
+// The names used by the generated code, the arguments with the same names are renamed.
+var reservedNames = map[string]bool{
+	"api": true, "ctx": true, "request": true, "pbRequest": true, "pbResponse": true,
+	"result": true, "err": true, "packErr": true, "pb": true, "proto": true, "context": true, "reflect": true,
+}
+
+var builtinTypes = map[string]bool{
+	"bool": true, "byte": true, "error": true, "string": true, "rune": true, "any": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true,
+}
+
+// sourceFunc is a function type together with the imports of the file it is declared in,
+// which are needed to compare the types declared in different packages.
+type sourceFunc struct {
+	name    string
+	fn      *ast.FuncType
+	pkg     string
+	imports map[string]string
+}
+
+type sourceInterface struct {
+	methods []sourceFunc
+	// embedded are the names of the interfaces of the same package embedded into the interface.
+	embedded []string
+}
+
+type dispatchedMethod struct {
+	name         string
+	args         []string
+	requestType  string
+	responseType string
+}
+
+type dispatchedApi struct {
+	name    string
+	methods []dispatchedMethod
+}
+
+func main() {
+	out := flag.String("out", "dispatch_generated.go", "output file")
+	pbDir := flag.String("pb", "../pb", "directory of the Protobuf types and their conversion methods")
+	mock := flag.Bool("mock", false, "generate the mocks of the API interfaces instead of the dispatchers")
+	var mockCfg mockConfig
+	flag.StringVar(&mockCfg.srcDir, "src", "../internal", "directory of the API interfaces for -mock")
+	flag.StringVar(&mockCfg.srcImport, "import", "", "import path of the package of the API interfaces for -mock")
+	flag.StringVar(&mockCfg.pkgName, "pkg", "", "package of the generated mocks for -mock")
+	flag.StringVar(&mockCfg.buildTag, "tag", "", "build constraint of the generated mocks for -mock")
+	flag.Parse()
+
+	var err error
+	if *mock {
+		err = runMock(*out, mockCfg, flag.Args())
+	} else {
+		err = run(*out, *pbDir, flag.Args())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dispatchgen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(out string, pbDir string, pairs []string) error {
+	if len(pairs) == 0 {
+		return errors.New("no API interfaces are given")
+	}
+
+	pkgName, interfaces, _, err := parseDir(".", out)
+	if err != nil {
+		return err
+	}
+	_, _, conversions, err := parseDir(pbDir, "")
+	if err != nil {
+		return err
+	}
+
+	apis := make([]dispatchedApi, 0, len(pairs))
+	for _, pair := range pairs {
+		apiName, transportName, ok := strings.Cut(pair, ":")
+		if !ok {
+			return fmt.Errorf("invalid pair %q, expected api:transport", pair)
+		}
+		api, err := makeDispatchedApi(interfaces, conversions, apiName, transportName)
+		if err != nil {
+			return err
+		}
+		apis = append(apis, api)
+	}
+
+	source, err := format.Source(generate(pkgName, apis))
+	if err != nil {
+		return fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return os.WriteFile(out, source, 0o644)
+}
+
+// parseDir collects the interfaces and the methods of the types declared in the non-test files of the package.
+func parseDir(
+	dir string, skip string,
+) (string, map[string]sourceInterface, map[string]map[string]sourceFunc, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var pkgName string
+	interfaces := make(map[string]sourceInterface)
+	methods := make(map[string]map[string]sourceFunc)
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || filepath.Base(file) == filepath.Base(skip) {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		pkgName = f.Name.Name
+		imports := fileImports(f)
+
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					if iface, ok := typeSpec.Type.(*ast.InterfaceType); ok {
+						interfaces[typeSpec.Name.Name] = collectInterface(iface, pkgName, imports)
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv == nil || len(decl.Recv.List) != 1 {
+					continue
+				}
+				receiver := decl.Recv.List[0].Type
+				if star, ok := receiver.(*ast.StarExpr); ok {
+					receiver = star.X
+				}
+				ident, ok := receiver.(*ast.Ident)
+				if !ok {
+					continue
+				}
+				if methods[ident.Name] == nil {
+					methods[ident.Name] = make(map[string]sourceFunc)
+				}
+				methods[ident.Name][decl.Name.Name] = sourceFunc{
+					name: decl.Name.Name, fn: decl.Type, pkg: pkgName, imports: imports,
+				}
+			}
+		}
+	}
+	return pkgName, interfaces, methods, nil
+}
+
+func fileImports(f *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, spec := range f.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := filepath.Base(path)
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+	return imports
+}
+
+func collectInterface(iface *ast.InterfaceType, pkg string, imports map[string]string) sourceInterface {
+	var result sourceInterface
+	for _, field := range iface.Methods.List {
+		if ident, ok := field.Type.(*ast.Ident); ok && len(field.Names) == 0 {
+			result.embedded = append(result.embedded, ident.Name)
+			continue
+		}
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 || !field.Names[0].IsExported() {
+			continue
+		}
+		result.methods = append(result.methods, sourceFunc{
+			name: field.Names[0].Name, fn: fn, pkg: pkg, imports: imports,
+		})
+	}
+	return result
+}
+
+func makeDispatchedApi(
+	interfaces map[string]sourceInterface,
+	conversions map[string]map[string]sourceFunc,
+	apiName string,
+	transportName string,
+) (dispatchedApi, error) {
+	apiMethods, err := interfaceMethods(interfaces, apiName)
+	if err != nil {
+		return dispatchedApi{}, err
+	}
+	transport, err := interfaceMethods(interfaces, transportName)
+	if err != nil {
+		return dispatchedApi{}, err
+	}
+	transportMethods := make(map[string]sourceFunc, len(transport))
+	for _, method := range transport {
+		transportMethods[method.name] = method
+	}
+
+	result := dispatchedApi{name: apiName}
+	for _, apiMethod := range apiMethods {
+		transportMethod, ok := transportMethods[apiMethod.name]
+		if !ok {
+			return dispatchedApi{}, fmt.Errorf("method %s not found in %s", apiMethod.name, transportName)
+		}
+		method, single, err := makeDispatchedMethod(apiMethod, transportMethod, conversions)
+		if err != nil {
+			return dispatchedApi{}, fmt.Errorf("%s.%s: %w", apiName, apiMethod.name, err)
+		}
+		if single {
+			result.methods = append(result.methods, method)
+		}
+	}
+	return result, nil
+}
+
+// interfaceMethods returns the exported methods of the interface, including the ones of the embedded interfaces.
+// The interfaces embedded from the other packages are not resolved, they are not expected to declare API methods.
+func interfaceMethods(interfaces map[string]sourceInterface, name string) ([]sourceFunc, error) {
+	iface, ok := interfaces[name]
+	if !ok {
+		return nil, fmt.Errorf("interface %s not found", name)
+	}
+	methods := iface.methods
+	for _, embedded := range iface.embedded {
+		embeddedMethods, err := interfaceMethods(interfaces, embedded)
+		if err != nil {
+			return nil, err
+		}
+		methods = append(slices.Clip(methods), embeddedMethods...)
+	}
+	return methods, nil
+}
+
+// makeDispatchedMethod checks the method against its Protobuf types. It reports whether the method has
+// a single response, the streaming methods and subscriptions are not dispatched by the generated code.
+func makeDispatchedMethod(
+	apiMethod sourceFunc,
+	transportMethod sourceFunc,
+	conversions map[string]map[string]sourceFunc,
+) (dispatchedMethod, bool, error) {
+	method := dispatchedMethod{name: apiMethod.name}
+
+	params := fieldTypes(apiMethod.fn.Params)
+	if len(params) == 0 {
+		return method, false, errors.New("API method must accept the context")
+	}
+	for i, name := range fieldNames(apiMethod.fn.Params)[1:] {
+		if name == "" || name == "_" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		if reservedNames[name] {
+			name += "Arg"
+		}
+		method.args = append(method.args, name)
+	}
+	results := fieldTypes(apiMethod.fn.Results)
+	if len(results) != 2 {
+		return method, false, errors.New("API method must return a result and an error")
+	}
+
+	transportParams := fieldTypes(transportMethod.fn.Params)
+	transportResults := fieldTypes(transportMethod.fn.Results)
+	if len(transportParams) > 1 || len(transportResults) != 1 {
+		return method, false, errors.New("transport method must accept at most 1 request and return 1 response")
+	}
+
+	if len(transportParams) == 1 {
+		requestType, err := pbTypeName(transportParams[0])
+		if err != nil {
+			return method, false, err
+		}
+		unpack, ok := conversions[requestType][unpackMethodName]
+		if !ok {
+			return method, false, fmt.Errorf("method %s not found in pb.%s", unpackMethodName, requestType)
+		}
+		if n := len(fieldTypes(unpack.fn.Results)) - 1; n != len(method.args) {
+			return method, false, fmt.Errorf(
+				"API method requires %d arguments, but pb.%s.%s returns %d",
+				len(method.args), requestType, unpackMethodName, n)
+		}
+		method.requestType = requestType
+	} else if len(method.args) != 0 {
+		return method, false, errors.New("API method requires arguments, but transport method has no request")
+	}
+
+	responseType, err := pbTypeName(transportResults[0])
+	if err != nil {
+		return method, false, err
+	}
+	pack, ok := conversions[responseType][packMethodName]
+	if !ok {
+		return method, false, fmt.Errorf("method %s not found in pb.%s", packMethodName, responseType)
+	}
+	packParams := fieldTypes(pack.fn.Params)
+	if len(packParams) != 2 {
+		return method, false, fmt.Errorf("pb.%s.%s must accept a result and an error", responseType, packMethodName)
+	}
+	method.responseType = responseType
+
+	resultType := qualifiedType(results[0], apiMethod.pkg, apiMethod.imports)
+	packedType := qualifiedType(packParams[0], pack.pkg, pack.imports)
+	return method, resultType == packedType, nil
+}
+
+// fieldTypes returns the type of every parameter, i.e. a type of a group of parameters is repeated.
+func fieldTypes(fields *ast.FieldList) []ast.Expr {
+	if fields == nil {
+		return nil
+	}
+	var types []ast.Expr
+	for _, field := range fields.List {
+		for range max(len(field.Names), 1) {
+			types = append(types, field.Type)
+		}
+	}
+	return types
+}
+
+func fieldNames(fields *ast.FieldList) []string {
+	var names []string
+	for _, field := range fields.List {
+		if len(field.Names) == 0 {
+			names = append(names, "")
+		}
+		for _, name := range field.Names {
+			names = append(names, name.Name)
+		}
+	}
+	return names
+}
+
+func pbTypeName(expr ast.Expr) (string, error) {
+	selector, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return "", fmt.Errorf("unexpected Protobuf type %s", exprString(expr))
+	}
+	if pkg, ok := selector.X.(*ast.Ident); !ok || pkg.Name != "pb" {
+		return "", fmt.Errorf("unexpected Protobuf type %s", exprString(expr))
+	}
+	return selector.Sel.Name, nil
+}
+
+// qualifiedType renders the type with the full import paths of the packages, so that the types written
+// in the files with different imports can be compared.
+func qualifiedType(expr ast.Expr, pkg string, imports map[string]string) string {
+	var b strings.Builder
+	writeQualifiedType(&b, expr, pkg, imports)
+	return b.String()
+}
+
+func writeQualifiedType(b *strings.Builder, expr ast.Expr, pkg string, imports map[string]string) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if !builtinTypes[expr.Name] {
+			b.WriteString(pkg + ".")
+		}
+		b.WriteString(expr.Name)
+	case *ast.SelectorExpr:
+		if x, ok := expr.X.(*ast.Ident); ok {
+			if path, ok := imports[x.Name]; ok {
+				b.WriteString(path + "." + expr.Sel.Name)
+				return
+			}
+		}
+		b.WriteString(exprString(expr))
+	case *ast.StarExpr:
+		b.WriteString("*")
+		writeQualifiedType(b, expr.X, pkg, imports)
+	case *ast.ArrayType:
+		b.WriteString("[")
+		if expr.Len != nil {
+			b.WriteString(exprString(expr.Len))
+		}
+		b.WriteString("]")
+		writeQualifiedType(b, expr.Elt, pkg, imports)
+	case *ast.MapType:
+		b.WriteString("map[")
+		writeQualifiedType(b, expr.Key, pkg, imports)
+		b.WriteString("]")
+		writeQualifiedType(b, expr.Value, pkg, imports)
+	case *ast.ChanType:
+		switch expr.Dir {
+		case ast.RECV:
+			b.WriteString("<-chan ")
+		case ast.SEND:
+			b.WriteString("chan<- ")
+		default:
+			b.WriteString("chan ")
+		}
+		writeQualifiedType(b, expr.Value, pkg, imports)
+	default:
+		b.WriteString(exprString(expr))
+	}
+}
+
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	if err := format.Node(&buf, token.NewFileSet(), expr); err != nil {
+		return fmt.Sprintf("%T", expr)
+	}
+	return buf.String()
+}
+
+func generate(pkgName string, apis []dispatchedApi) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by dispatchgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	fmt.Fprintf(&b, "import (\n\t\"context\"\n\t\"reflect\"\n\n")
+	fmt.Fprintf(&b, "\t\"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb\"\n")
+	fmt.Fprintf(&b, "\t\"google.golang.org/protobuf/proto\"\n)\n\n")
+
+	// The messages are reused by the dispatchers of all the APIs, see messagePool.
+	var messageTypes []string
+	for _, api := range apis {
+		for _, method := range api.methods {
+			if method.requestType != "" {
+				messageTypes = append(messageTypes, method.requestType)
+			}
+			messageTypes = append(messageTypes, method.responseType)
+		}
+	}
+	slices.Sort(messageTypes)
+	fmt.Fprintf(&b, "var (\n")
+	for _, messageType := range slices.Compact(messageTypes) {
+		fmt.Fprintf(&b, "\t%s = newMessagePool(reflect.TypeFor[pb.%s]())\n", messagePoolName(messageType), messageType)
+	}
+	fmt.Fprintf(&b, ")\n\n")
+
+	fmt.Fprintf(&b, "func init() {\n")
+	for _, api := range apis {
+		fmt.Fprintf(&b, "\tregisterDispatchers(func(api %s) map[string]methodDispatcher {\n", api.name)
+		fmt.Fprintf(&b, "\t\treturn map[string]methodDispatcher{\n")
+		for _, method := range api.methods {
+			generateMethod(&b, method)
+		}
+		fmt.Fprintf(&b, "\t\t}\n\t})\n")
+	}
+	fmt.Fprintf(&b, "}\n")
+	return b.Bytes()
+}
+
+func generateMethod(b *bytes.Buffer, method dispatchedMethod) {
+	requestName := "_"
+	if method.requestType != "" {
+		requestName = "request"
+	}
+	fmt.Fprintf(b, "%q: func(ctx context.Context, %s []byte) ([]byte, error) {\n", method.name, requestName)
+
+	// The phases are traced as in makeRequestHandler.
+	if method.requestType != "" {
+		fmt.Fprintf(b, "pbRequest := %s.get().(*pb.%s)\n", messagePoolName(method.requestType), method.requestType)
+		fmt.Fprintf(b, "defer %s.put(pbRequest)\n", messagePoolName(method.requestType))
+		fmt.Fprintf(b, "_, unpackSpan := startRequestPhase(ctx, requestPhaseUnpack)\n")
+		fmt.Fprintf(b, "if err := unmarshalRequest(request, pbRequest); err != nil {\n"+
+			"endSpan(unpackSpan, err)\nreturn nil, err\n}\n")
+		unpacked := append(append([]string{}, method.args...), "err")
+		fmt.Fprintf(b, "%s := pbRequest.%s()\n", strings.Join(unpacked, ", "), unpackMethodName)
+		fmt.Fprintf(b, "if err != nil {\nerr = wrapRequestUnpackError(err)\n} else {\n")
+		fmt.Fprintf(b, "err = validateRequest(%q, %s)\n}\n", method.name, strings.Join(method.args, ", "))
+		fmt.Fprintf(b, "endSpan(unpackSpan, err)\n")
+		fmt.Fprintf(b, "if err != nil {\nreturn nil, err\n}\n")
+	}
+
+	callArgs := append([]string{"dispatchCtx"}, method.args...)
+	fmt.Fprintf(b, "dispatchCtx, dispatchSpan := startRequestPhase(ctx, requestPhaseDispatch)\n")
+	fmt.Fprintf(b, "result, err := api.%s(%s)\n", method.name, strings.Join(callArgs, ", "))
+	fmt.Fprintf(b, "endSpan(dispatchSpan, err)\n")
+	fmt.Fprintf(b, "recordRequestError(ctx, err)\n")
+	fmt.Fprintf(b, "_, packSpan := startRequestPhase(ctx, requestPhasePack)\n")
+	fmt.Fprintf(b, "defer packSpan.End()\n")
+	// The successful results of the methods listed in passthroughResponses are encoded as by the codec.
+	fmt.Fprintf(b, "if passthrough := passthroughResponses[%q]; passthrough != nil && err == nil {\n"+
+		"return passthrough(result)\n}\n", method.name)
+	fmt.Fprintf(b, "pbResponse := %s.get().(*pb.%s)\n", messagePoolName(method.responseType), method.responseType)
+	fmt.Fprintf(b, "defer %s.put(pbResponse)\n", messagePoolName(method.responseType))
+	fmt.Fprintf(b, "if packErr := pbResponse.%s(result, err); packErr != nil {\nreturn nil, packErr\n}\n",
+		packMethodName)
+	fmt.Fprintf(b, "return proto.Marshal(pbResponse)\n},\n")
+}
+
+// messagePoolName is the name of the package-level pool of the messages of the Protobuf type.
+func messagePoolName(messageType string) string {
+	return "pb" + messageType + "Pool"
+}
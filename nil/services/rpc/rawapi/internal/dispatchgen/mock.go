@@ -0,0 +1,335 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"os"
+	"slices"
+	"strings"
+)
+
+// The names used by the generated mocks, the arguments with the same names are renamed.
+var mockReservedNames = map[string]bool{"m": true, "f": true}
+
+type mockConfig struct {
+	srcDir    string
+	srcImport string
+	pkgName   string
+	buildTag  string
+}
+
+type mockParam struct {
+	name     string
+	typ      string
+	variadic bool
+}
+
+type mockedMethod struct {
+	name    string
+	params  []mockParam
+	results []string
+	// ctx reports whether the first parameter is the context, which is not recorded.
+	ctx bool
+}
+
+type mockedApi struct {
+	name    string
+	methods []mockedMethod
+}
+
+// typeRenderer renders the types of the API interfaces in the package of the mocks
+// and collects the imports they need.
+type typeRenderer struct {
+	srcPkg  string
+	imports map[string]string
+}
+
+// runMock generates the mocks of the API interfaces given as "api" or "api:transport". The mock of an API
+// with a transport interface is keyed off it, i.e. every mocked method must be served by the transport.
+func runMock(out string, cfg mockConfig, pairs []string) error {
+	if len(pairs) == 0 {
+		return errors.New("no API interfaces are given")
+	}
+	if cfg.srcImport == "" || cfg.pkgName == "" {
+		return errors.New("-import and -pkg are required for -mock")
+	}
+
+	srcPkg, interfaces, _, err := parseDir(cfg.srcDir, out)
+	if err != nil {
+		return err
+	}
+	renderer := &typeRenderer{srcPkg: srcPkg, imports: map[string]string{srcPkg: cfg.srcImport}}
+
+	apis := make([]mockedApi, 0, len(pairs))
+	for _, pair := range pairs {
+		apiName, transportName, _ := strings.Cut(pair, ":")
+		api, err := makeMockedApi(interfaces, renderer, apiName, transportName)
+		if err != nil {
+			return err
+		}
+		apis = append(apis, api)
+	}
+
+	source, err := format.Source(generateMocks(cfg, renderer, apis))
+	if err != nil {
+		return fmt.Errorf("failed to format the generated code: %w", err)
+	}
+	return os.WriteFile(out, source, 0o644)
+}
+
+func makeMockedApi(
+	interfaces map[string]sourceInterface,
+	renderer *typeRenderer,
+	apiName string,
+	transportName string,
+) (mockedApi, error) {
+	apiMethods, err := interfaceMethods(interfaces, apiName)
+	if err != nil {
+		return mockedApi{}, err
+	}
+	if transportName != "" {
+		transport, err := interfaceMethods(interfaces, transportName)
+		if err != nil {
+			return mockedApi{}, err
+		}
+		for _, method := range apiMethods {
+			if !slices.ContainsFunc(transport, func(m sourceFunc) bool { return m.name == method.name }) {
+				return mockedApi{}, fmt.Errorf("method %s not found in %s", method.name, transportName)
+			}
+		}
+	}
+
+	result := mockedApi{name: apiName}
+	for _, apiMethod := range apiMethods {
+		method, err := makeMockedMethod(apiMethod, renderer)
+		if err != nil {
+			return mockedApi{}, fmt.Errorf("%s.%s: %w", apiName, apiMethod.name, err)
+		}
+		result.methods = append(result.methods, method)
+	}
+	return result, nil
+}
+
+func makeMockedMethod(apiMethod sourceFunc, renderer *typeRenderer) (mockedMethod, error) {
+	method := mockedMethod{name: apiMethod.name}
+
+	names := fieldNames(apiMethod.fn.Params)
+	for i, expr := range fieldTypes(apiMethod.fn.Params) {
+		param := mockParam{name: names[i]}
+		if param.name == "" || param.name == "_" {
+			param.name = fmt.Sprintf("arg%d", i)
+		}
+		if mockReservedNames[param.name] {
+			param.name += "Arg"
+		}
+		if ellipsis, ok := expr.(*ast.Ellipsis); ok {
+			param.variadic = true
+			expr = ellipsis.Elt
+		}
+		typ, err := renderer.render(expr, apiMethod)
+		if err != nil {
+			return method, err
+		}
+		param.typ = typ
+		method.params = append(method.params, param)
+	}
+	method.ctx = len(method.params) > 0 && method.params[0].typ == "context.Context"
+
+	for _, expr := range fieldTypes(apiMethod.fn.Results) {
+		typ, err := renderer.render(expr, apiMethod)
+		if err != nil {
+			return method, err
+		}
+		method.results = append(method.results, typ)
+	}
+	return method, nil
+}
+
+// render writes the type as it is referenced from the package of the mocks.
+func (t *typeRenderer) render(expr ast.Expr, method sourceFunc) (string, error) {
+	var b strings.Builder
+	if err := t.write(&b, expr, method); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func (t *typeRenderer) write(b *strings.Builder, expr ast.Expr, method sourceFunc) error {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if builtinTypes[expr.Name] {
+			b.WriteString(expr.Name)
+			return nil
+		}
+		if !expr.IsExported() {
+			return fmt.Errorf("unexported type %s can't be mocked", expr.Name)
+		}
+		b.WriteString(t.srcPkg + "." + expr.Name)
+	case *ast.SelectorExpr:
+		x, ok := expr.X.(*ast.Ident)
+		if !ok {
+			return fmt.Errorf("unexpected type %s", exprString(expr))
+		}
+		path, ok := method.imports[x.Name]
+		if !ok {
+			return fmt.Errorf("import of %s not found", x.Name)
+		}
+		if known, ok := t.imports[x.Name]; ok && known != path {
+			return fmt.Errorf("package name %s is used for both %s and %s", x.Name, known, path)
+		}
+		t.imports[x.Name] = path
+		b.WriteString(x.Name + "." + expr.Sel.Name)
+	case *ast.StarExpr:
+		b.WriteString("*")
+		return t.write(b, expr.X, method)
+	case *ast.ArrayType:
+		b.WriteString("[")
+		if expr.Len != nil {
+			b.WriteString(exprString(expr.Len))
+		}
+		b.WriteString("]")
+		return t.write(b, expr.Elt, method)
+	case *ast.MapType:
+		b.WriteString("map[")
+		if err := t.write(b, expr.Key, method); err != nil {
+			return err
+		}
+		b.WriteString("]")
+		return t.write(b, expr.Value, method)
+	case *ast.ChanType:
+		switch expr.Dir {
+		case ast.RECV:
+			b.WriteString("<-chan ")
+		case ast.SEND:
+			b.WriteString("chan<- ")
+		default:
+			b.WriteString("chan ")
+		}
+		return t.write(b, expr.Value, method)
+	case *ast.InterfaceType:
+		if expr.Methods != nil && len(expr.Methods.List) > 0 {
+			return fmt.Errorf("unexpected type %s", exprString(expr))
+		}
+		b.WriteString("any")
+	default:
+		return fmt.Errorf("unexpected type %s", exprString(expr))
+	}
+	return nil
+}
+
+// generateMocks emits a mock per API interface. The mock records every call with the Recorder it embeds
+// and calls the function set for the method, e.g., GetBalanceFunc for GetBalance. The methods without
+// the functions return the zero values and an error wrapping ErrNotMocked if they return an error.
+// The Recorder and ErrNotMocked are declared in the package of the mocks.
+func generateMocks(cfg mockConfig, renderer *typeRenderer, apis []mockedApi) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by dispatchgen. DO NOT EDIT.\n\n")
+	if cfg.buildTag != "" {
+		fmt.Fprintf(&b, "//go:build %s\n\n", cfg.buildTag)
+	}
+	fmt.Fprintf(&b, "package %s\n\n", cfg.pkgName)
+
+	names := make([]string, 0, len(renderer.imports))
+	for name := range renderer.imports {
+		names = append(names, name)
+	}
+	// The standard packages go first, their paths have no domain.
+	isStd := func(name string) bool { return !strings.Contains(strings.Split(renderer.imports[name], "/")[0], ".") }
+	slices.SortFunc(names, func(a, b string) int {
+		if isStd(a) != isStd(b) {
+			if isStd(a) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(renderer.imports[a], renderer.imports[b])
+	})
+	fmt.Fprintf(&b, "import (\n")
+	for i, name := range names {
+		path := renderer.imports[name]
+		if i > 0 && isStd(names[i-1]) && !isStd(name) {
+			fmt.Fprintf(&b, "\n")
+		}
+		if name == path[strings.LastIndex(path, "/")+1:] {
+			fmt.Fprintf(&b, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(&b, "\t%s %q\n", name, path)
+		}
+	}
+	fmt.Fprintf(&b, ")\n")
+
+	for _, api := range apis {
+		mockName := api.name + "Mock"
+		fmt.Fprintf(&b, "\n// %s is a mock of %s.%s.\n", mockName, renderer.srcPkg, api.name)
+		fmt.Fprintf(&b, "type %s struct {\nRecorder\n\n", mockName)
+		for _, method := range api.methods {
+			fmt.Fprintf(&b, "%sFunc %s\n", method.name, method.signature("func"))
+		}
+		fmt.Fprintf(&b, "}\n\n")
+		fmt.Fprintf(&b, "var _ %s.%s = (*%s)(nil)\n", renderer.srcPkg, api.name, mockName)
+		for _, method := range api.methods {
+			generateMockedMethod(&b, api.name, mockName, method)
+		}
+	}
+	return b.Bytes()
+}
+
+// signature renders the parameters and the results of the method after the prefix.
+func (method mockedMethod) signature(prefix string) string {
+	params := make([]string, 0, len(method.params))
+	for _, param := range method.params {
+		if param.variadic {
+			params = append(params, param.name+" ..."+param.typ)
+		} else {
+			params = append(params, param.name+" "+param.typ)
+		}
+	}
+	signature := prefix + "(" + strings.Join(params, ", ") + ")"
+	switch len(method.results) {
+	case 0:
+		return signature
+	case 1:
+		return signature + " " + method.results[0]
+	default:
+		return signature + " (" + strings.Join(method.results, ", ") + ")"
+	}
+}
+
+func generateMockedMethod(b *bytes.Buffer, apiName string, mockName string, method mockedMethod) {
+	fmt.Fprintf(b, "\nfunc (m *%s) %s {\n", mockName, method.signature(method.name))
+
+	recorded := []string{fmt.Sprintf("%q", method.name)}
+	callArgs := make([]string, 0, len(method.params))
+	for i, param := range method.params {
+		callArgs = append(callArgs, param.name)
+		if param.variadic {
+			callArgs[i] += "..."
+		}
+		if i > 0 || !method.ctx {
+			recorded = append(recorded, param.name)
+		}
+	}
+	fmt.Fprintf(b, "m.Record(%s)\n", strings.Join(recorded, ", "))
+
+	call := fmt.Sprintf("f(%s)", strings.Join(callArgs, ", "))
+	fmt.Fprintf(b, "if f := m.%sFunc; f != nil {\n", method.name)
+	if len(method.results) == 0 {
+		fmt.Fprintf(b, "%s\nreturn\n}\n}\n", call)
+		return
+	}
+	fmt.Fprintf(b, "return %s\n}\n", call)
+
+	zeros := make([]string, 0, len(method.results))
+	for i, result := range method.results {
+		if i == len(method.results)-1 && result == "error" {
+			zeros = append(zeros, fmt.Sprintf("newNotMockedError(%q, %q)", apiName, method.name))
+			continue
+		}
+		fmt.Fprintf(b, "var r%d %s\n", i, result)
+		zeros = append(zeros, fmt.Sprintf("r%d", i))
+	}
+	fmt.Fprintf(b, "return %s\n}\n", strings.Join(zeros, ", "))
+}
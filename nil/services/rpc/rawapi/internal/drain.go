@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var errRequestHandlersUnset = errors.New("request handlers are unset")
+
+// inFlightRequests counts the requests being handled, the new requests are rejected once it is drained.
+type inFlightRequests struct {
+	mu       sync.Mutex
+	count    int
+	draining bool
+	// idle is closed when the last request being handled is done during the drain.
+	idle chan struct{}
+}
+
+func (r *inFlightRequests) enter() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.draining {
+		return false
+	}
+	r.count++
+	return true
+}
+
+func (r *inFlightRequests) leave() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.count--
+	if r.count == 0 && r.idle != nil {
+		close(r.idle)
+		r.idle = nil
+	}
+}
+
+// drain rejects the new requests and waits for the ones being handled until the context is done.
+// It returns the number of the requests still being handled.
+func (r *inFlightRequests) drain(ctx context.Context) int {
+	r.mu.Lock()
+	r.draining = true
+	if r.count == 0 {
+		r.mu.Unlock()
+		return 0
+	}
+	if r.idle == nil {
+		r.idle = make(chan struct{})
+	}
+	idle := r.idle
+	r.mu.Unlock()
+
+	select {
+	case <-idle:
+		return 0
+	case <-ctx.Done():
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.count
+	}
+}
@@ -0,0 +1,24 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightRequestsDrain(t *testing.T) {
+	t.Parallel()
+
+	var requests inFlightRequests
+	require.True(t, requests.enter())
+
+	// The drain is over once the request being handled is done, whether it's done before the drain starts or not.
+	drained := make(chan int)
+	go func() {
+		drained <- requests.drain(t.Context())
+	}()
+	requests.leave()
+	require.Zero(t, <-drained)
+
+	require.False(t, requests.enter())
+}
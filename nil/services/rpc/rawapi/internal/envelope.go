@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// sendEnvelopedRequest wraps the request and unwraps the response of a raw API method.
+// The requests by the protocol IDs without a version are sent unwrapped, since the legacy nodes
+// addressed by them don't unwrap the requests.
+func sendEnvelopedRequest(
+	ctx context.Context,
+	networkManager network.Manager,
+	peerId network.PeerID,
+	protocol network.ProtocolID,
+	payload []byte,
+) (_ []byte, err error) {
+	ctx, span := startClientSpan(ctx, protocol)
+	defer func() { endSpan(span, err) }()
+
+	if !isVersionedProtocol(protocol) {
+		return networkManager.SendRequestAndGetResponse(ctx, peerId, protocol, payload)
+	}
+	request, err := packRequestEnvelope(ctx, protocol, payload)
+	if err != nil {
+		return nil, err
+	}
+	response, err := networkManager.SendRequestAndGetResponse(ctx, peerId, protocol, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope pb.ResponseEnvelope
+	if err := proto.Unmarshal(response, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unpack response envelope: %w", err)
+	}
+	// The responses of the hedged requests cancelled in favor of the other peers are not reported.
+	if block, ok := ctx.Value(responseBlockKey{}).(*ResponseBlock); ok && ctx.Err() == nil {
+		blockHash, err := envelope.GetBlockHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		block.set(blockHash)
+	}
+	if envelope.GetChunked() != nil {
+		envelope.Payload, err = fetchChunkedResponse(ctx, networkManager, peerId, protocol, envelope.GetChunked())
+		if err != nil {
+			return nil, err
+		}
+	}
+	return decompressResponse(&envelope)
+}
+
+// packRequestEnvelope wraps the request together with the time left until the deadline of the caller.
+// The remaining time is sent instead of the deadline itself, so the clocks of the nodes don't have to be in sync.
+// The auth token of the context is sent along, see WithAuthToken, as well as the trace context
+// and the block the request is conditional on, see WithIfBlockChanged.
+// The request is signed for the protocol if the context has a signing key, see WithRequestSigningKey.
+func packRequestEnvelope(ctx context.Context, protocol network.ProtocolID, payload []byte) ([]byte, error) {
+	return packRequestEnvelopeAccepting(ctx, protocol, payload, clientCompression, true)
+}
+
+// packRequestEnvelopeAccepting is packRequestEnvelope accepting the given response encoding,
+// the response is not wrapped if neither compression nor chunks are accepted.
+func packRequestEnvelopeAccepting(
+	ctx context.Context, protocol network.ProtocolID, payload []byte, compression pb.Compression, acceptChunked bool,
+) ([]byte, error) {
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout <= 0 {
+			return nil, context.DeadlineExceeded
+		}
+	}
+	envelope := new(pb.RequestEnvelope).PackProtoMessage(payload, timeout, compression, acceptChunked)
+	envelope.AuthToken, _ = ctx.Value(authTokenKey{}).(string)
+	envelope.TraceContext = injectTraceContext(ctx)
+	if blockHash, ok := ctx.Value(ifBlockChangedKey{}).(common.Hash); ok {
+		envelope.IfBlockChanged = new(pb.Hash)
+		if err := envelope.IfBlockChanged.PackProtoMessage(blockHash); err != nil {
+			return nil, err
+		}
+	}
+	if err := signRequestEnvelope(ctx, protocol, envelope); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(envelope)
+}
+
+// acceptUnenvelopedRequests serves the requests of the legacy nodes, which send them unwrapped
+// by the protocol IDs without a version. They are handled as the envelopes without options,
+// so their responses are not wrapped either.
+func acceptUnenvelopedRequests(handler network.RequestHandler) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		var envelope pb.RequestEnvelope
+		if err := proto.Unmarshal(request, &envelope); err == nil && envelope.GetEnveloped() {
+			return handler(ctx, request)
+		}
+		wrapped, err := proto.Marshal(
+			new(pb.RequestEnvelope).PackProtoMessage(request, 0, pb.Compression_NoCompression, false))
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, wrapped)
+	}
+}
+
+// makeEnvelopeRequestHandler unwraps the request and limits its handling by the timeout of the caller,
+// so that the work the caller is no longer waiting for is aborted. The errors of the handler are packed
+// into the response, which is wrapped if the caller accepts compression or chunked responses.
+// The responses larger than the chunk size are kept in the store to be fetched by chunks by the peer
+// the response is sent to. The signer of a request signed for the protocol is passed to the handler,
+// see GetRequestSigner.
+// The response is tagged by the block the handler reads the data at, see recordResolvedBlock, except
+// for the batches, whose calls read different blocks. The conditional requests are always wrapped,
+// so their responses are tagged as well.
+func makeEnvelopeRequestHandler(
+	handler network.RequestHandler,
+	protocol network.ProtocolID,
+	packError func(error) []byte,
+	cfg RequestHandlersConfig,
+	chunks *chunkStore,
+	signed *signedRequests,
+) network.RequestHandler {
+	methodName := path.Base(string(protocol))
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		var envelope pb.RequestEnvelope
+		if err := proto.Unmarshal(request, &envelope); err != nil {
+			return packError(fmt.Errorf("failed to unpack request envelope: %w", err)), nil
+		}
+
+		payload, timeout, compression, acceptChunked := envelope.UnpackProtoMessage()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		ctx = extractTraceContext(ctx, envelope.GetTraceContext())
+		var resolved *resolvedBlock
+		if methodName != batchMethodName {
+			ifChanged, err := envelope.GetIfBlockChanged().UnpackProtoMessage()
+			if err != nil {
+				return packError(err), nil
+			}
+			ctx, resolved = withResolvedBlock(ctx, ifChanged)
+		}
+		if token := envelope.GetAuthToken(); token != "" {
+			ctx = withRequestAuthToken(ctx, token)
+		}
+		if signature := envelope.GetSignature(); signature != nil {
+			signer, err := signed.verify(protocol, payload, signature, time.Now())
+			if err != nil {
+				return packError(err), nil
+			}
+			ctx = context.WithValue(ctx, requestSignerKey{}, signer)
+		}
+
+		response, err := handler(ctx, payload)
+		if err == nil && resolved != nil && resolved.notModified() {
+			err = rawapitypes.NewNotModifiedError(resolved.ifChanged)
+		}
+		if err != nil {
+			response = packError(err)
+		}
+		if compression == pb.Compression_NoCompression && !acceptChunked && envelope.GetIfBlockChanged() == nil {
+			return response, nil
+		}
+
+		responseEnvelope := packResponseEnvelope(response, compression, cfg.CompressionThreshold)
+		if resolved != nil {
+			if blockHash := resolved.blockHash(); !blockHash.Empty() {
+				responseEnvelope.BlockHash = new(pb.Hash)
+				if err := responseEnvelope.BlockHash.PackProtoMessage(blockHash); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if size := chunkSize(cfg.ChunkSize); acceptChunked && size > 0 && len(responseEnvelope.GetPayload()) > size {
+			peerId, _ := network.RequestPeer(ctx)
+			if handle, ok := chunks.put(peerId, responseEnvelope.GetPayload()); ok {
+				responseEnvelope.Chunked = &pb.ChunkedResponse{
+					Handle:    handle,
+					TotalSize: uint64(len(responseEnvelope.GetPayload())),
+				}
+				responseEnvelope.Payload = nil
+			}
+		}
+		return proto.Marshal(responseEnvelope)
+	}
+}
@@ -0,0 +1,166 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnknownMethod = errors.New("unknown method")
+
+// servedMethodCodecs are the codecs of the methods of the served APIs by "<api name>/<method name>".
+var servedMethodCodecs = sync.OnceValue(func() map[string]*methodCodec {
+	codecs := make(map[string]*methodCodec)
+	for _, served := range servedApis {
+		codec, err := newApiCodec(served.api, served.transport)
+		check.PanicIfErr(err)
+		for name, methodCodec := range codec {
+			codecs[served.name+"/"+name] = methodCodec
+		}
+	}
+	return codecs
+})
+
+// servedMethods returns the methods accepted by unpackServedRequest and packServedResponse in the ascending order.
+func servedMethods() []string {
+	return slices.Sorted(maps.Keys(servedMethodCodecs()))
+}
+
+func servedMethodCodec(method string) (*methodCodec, error) {
+	codec, ok := servedMethodCodecs()[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownMethod, method)
+	}
+	return codec, nil
+}
+
+// unpackServedRequest unpacks the request of the method the same way as the request handlers do before
+// the API is called. The method is named as "<api name>/<method name>", e.g., "rawapi_ro/GetBlockHeader".
+func unpackServedRequest(method string, request []byte) ([]any, error) {
+	codec, err := servedMethodCodec(method)
+	if err != nil {
+		return nil, err
+	}
+	values, err := codec.unpackRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value.Interface()
+	}
+	return args, nil
+}
+
+// packServedResponse packs the result of the method the same way as the request handlers do, the result is
+// the zero one if it is nil. The responses of the subscriptions are sent as events and can't be packed.
+func packServedResponse(method string, result any, err error) ([]byte, error) {
+	codec, codecErr := servedMethodCodec(method)
+	if codecErr != nil {
+		return nil, codecErr
+	}
+	if codec.kind == subscriptionResponse {
+		return nil, fmt.Errorf("method %s is a subscription", method)
+	}
+
+	resultValue := reflect.Zero(codec.apiMethodResultType)
+	if result != nil {
+		resultValue = reflect.ValueOf(result)
+		if !resultValue.Type().AssignableTo(codec.apiMethodResultType) {
+			return nil, fmt.Errorf("method %s returns %s, not %T", method, codec.apiMethodResultType, result)
+		}
+	}
+	errValue := reflect.Zero(reflect.TypeFor[error]())
+	if err != nil {
+		errValue = reflect.ValueOf(&err).Elem()
+	}
+	return codec.packResponse(resultValue, errValue)
+}
+
+// addServedMethodSeeds adds the seeds of every served method to the corpus of the fuzz test.
+func addServedMethodSeeds(f *testing.F, methods []string) {
+	f.Helper()
+
+	for i := range methods {
+		f.Add(uint(i), []byte{})
+		// A nested message in the first field, as most of the requests start with one.
+		f.Add(uint(i), []byte{0x0a, 0x02, 0x08, 0x01})
+	}
+}
+
+func TestPackServedResponse(t *testing.T) {
+	t.Parallel()
+
+	require.Contains(t, servedMethods(), "rawapi_ro/GetBlockTransactionCount")
+
+	response, err := packServedResponse("rawapi_ro/GetBlockTransactionCount", uint64(5), nil)
+	require.NoError(t, err)
+	codec, err := servedMethodCodec("rawapi_ro/GetBlockTransactionCount")
+	require.NoError(t, err)
+	count, err := unpackResponse[uint64](codec, response)
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), count)
+
+	_, err = packServedResponse("rawapi_ro/GetBlockTransactionCount", "5", nil)
+	require.Error(t, err)
+	_, err = packServedResponse("rawapi_ro/UnknownMethod", nil, nil)
+	require.ErrorIs(t, err, errUnknownMethod)
+}
+
+// FuzzUnpackRequest feeds the decoding of the requests of every served method with arbitrary bytes.
+// The malformed requests must be rejected with the errors the clients can tell apart, never with a panic.
+func FuzzUnpackRequest(f *testing.F) {
+	methods := servedMethods()
+	addServedMethodSeeds(f, methods)
+
+	f.Fuzz(func(t *testing.T, index uint, request []byte) {
+		method := methods[index%uint(len(methods))]
+		_, err := unpackServedRequest(method, request)
+		if err == nil {
+			return
+		}
+		var apiErr *rawapitypes.Error
+		require.ErrorAs(t, err, &apiErr)
+
+		codec, codecErr := servedMethodCodec(method)
+		require.NoError(t, codecErr)
+		if codec.kind == subscriptionResponse {
+			return
+		}
+		response, packErr := packServedResponse(method, nil, err)
+		require.NoError(t, packErr)
+		_, err = codec.unpackResponse(response)
+		require.Equal(t, apiErr.Code, rawapitypes.ErrorCodeOf(err))
+	})
+}
+
+// FuzzPackRequest packs the arguments of every request the servers accept the same way as the clients do.
+// The packed request must be decoded to the same arguments, so that the clients can send whatever is served.
+func FuzzPackRequest(f *testing.F) {
+	methods := servedMethods()
+	addServedMethodSeeds(f, methods)
+
+	f.Fuzz(func(t *testing.T, index uint, request []byte) {
+		method := methods[index%uint(len(methods))]
+		args, err := unpackServedRequest(method, request)
+		if err != nil {
+			return
+		}
+
+		codec, err := servedMethodCodec(method)
+		require.NoError(t, err)
+		packed, err := codec.packRequest(args...)
+		require.NoError(t, err)
+		unpacked, err := unpackServedRequest(method, packed)
+		require.NoError(t, err)
+		require.Equal(t, args, unpacked)
+	})
+}
@@ -0,0 +1,3 @@
+package internal
+
+//go:generate go run ./dispatchgen -out dispatch_generated.go shardApiRo:NetworkTransportProtocolRo shardApiRw:NetworkTransportProtocolRw shardApiDev:NetworkTransportProtocolDev shardApiDebug:NetworkTransportProtocolDebug shardApiTxpool:NetworkTransportProtocolTxpool shardApiSync:NetworkTransportProtocolSync shardApiProof:NetworkTransportProtocolProof shardApiAdmin:NetworkTransportProtocolAdmin shardApiDb:NetworkTransportProtocolDb shardApiCluster:NetworkTransportProtocolCluster
@@ -0,0 +1,385 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	// grpcShardIdHeader is the metadata key of the shard the method is called in, it is required.
+	grpcShardIdHeader = "shard-id"
+	// grpcAuthTokenHeader is the metadata key of the auth token, see WithAuthToken.
+	grpcAuthTokenHeader = "auth-token"
+
+	grpcPackage     = "rawapi"
+	grpcGatewayFile = "rawapi/gateway.proto"
+
+	// grpcPeerPrefix is the prefix of the peers of the clients of the gateway. The peer IDs of libp2p are multihashes,
+	// which never start with it, so the clients can't be taken for the peers, e.g., by the ACL.
+	grpcPeerPrefix = "grpc/"
+)
+
+// GrpcGatewayConfig configures the gRPC server serving the raw API methods to the clients outside of the p2p network.
+type GrpcGatewayConfig struct {
+	// Endpoint is the address the server listens on, e.g., "127.0.0.1:8531".
+	Endpoint string `yaml:"endpoint"`
+	// CertFile and KeyFile are the TLS certificate and key of the server, it is served without TLS if they are empty.
+	// The server is only served without TLS on a loopback endpoint, unless AllowInsecure is set.
+	CertFile      string `yaml:"certFile,omitempty"`
+	KeyFile       string `yaml:"keyFile,omitempty"`
+	AllowInsecure bool   `yaml:"allowInsecure,omitempty"`
+	// Reflection serves the gRPC reflection, so that the services can be listed and called by the tools, e.g., grpcurl.
+	Reflection bool `yaml:"reflection,omitempty"`
+}
+
+// GrpcGateway serves the methods of the raw API set on the network manager it wraps over gRPC as well.
+// Each served API is a service of the "rawapi" package named after the API, e.g., rawapi.ShardApiRo,
+// whose methods take and return the Protobuf messages of the NetworkTransportProtocol* interfaces,
+// so the clients can be generated from the .proto files. The shard is passed in the "shard-id" metadata.
+//
+// The requests are handled by the same handlers as the ones of the peers, i.e. they are intercepted,
+// limited and logged the same way. Each connection of a client is the peer of its requests, so the clients
+// are rate limited and have the quotas the same way as the peers, but the methods restricted to the peers
+// by the ACL are rejected. The streaming methods and the subscriptions are not served.
+type GrpcGateway struct {
+	network.Manager
+
+	mu       sync.RWMutex
+	handlers map[network.ProtocolID]network.RequestHandler
+}
+
+// NewGrpcGateway wraps the network manager, the request handlers of the raw API are to be set on the gateway.
+func NewGrpcGateway(manager network.Manager) *GrpcGateway {
+	return &GrpcGateway{
+		Manager:  manager,
+		handlers: make(map[network.ProtocolID]network.RequestHandler),
+	}
+}
+
+func (g *GrpcGateway) SetRequestHandler(
+	ctx context.Context, protocolId network.ProtocolID, handler network.RequestHandler,
+) {
+	g.mu.Lock()
+	g.handlers[protocolId] = handler
+	g.mu.Unlock()
+
+	g.Manager.SetRequestHandler(ctx, protocolId, handler)
+}
+
+func (g *GrpcGateway) RemoveStreamHandler(protocolId network.ProtocolID) {
+	g.mu.Lock()
+	delete(g.handlers, protocolId)
+	g.mu.Unlock()
+
+	g.Manager.RemoveStreamHandler(protocolId)
+}
+
+// Serve serves the gRPC server until the context is done.
+func (g *GrpcGateway) Serve(ctx context.Context, cfg GrpcGatewayConfig, logger logging.Logger) error {
+	listener, err := net.Listen("tcp", cfg.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.Endpoint, err)
+	}
+	return g.serve(ctx, listener, cfg, logger)
+}
+
+func (g *GrpcGateway) serve(
+	ctx context.Context, listener net.Listener, cfg GrpcGatewayConfig, logger logging.Logger,
+) error {
+	services, files, err := grpcGatewayServices()
+	if err != nil {
+		return err
+	}
+
+	options := []grpc.ServerOption{grpc.ForceServerCodec(grpcRawCodec{})}
+	if cfg.CertFile == "" && cfg.KeyFile == "" && !cfg.AllowInsecure && !isLoopbackAddr(listener.Addr()) {
+		listener.Close()
+		return fmt.Errorf("TLS is required to serve on %s, unless allowInsecure is set", listener.Addr())
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		options = append(options, grpc.Creds(creds))
+	}
+	server := grpc.NewServer(options...)
+	for _, service := range services {
+		server.RegisterService(g.serviceDesc(service), g)
+	}
+	if cfg.Reflection {
+		reflectionpb.RegisterServerReflectionServer(server, reflection.NewServerV1(reflection.ServerOptions{
+			Services:           server,
+			DescriptorResolver: grpcGatewayResolver{files: files},
+		}))
+	}
+
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			server.GracefulStop()
+		case <-stopped:
+		}
+	}()
+
+	logger.Info().Str("endpoint", listener.Addr().String()).Msg("Serving raw API over gRPC")
+	if err := server.Serve(listener); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+func (g *GrpcGateway) serviceDesc(service grpcService) *grpc.ServiceDesc {
+	desc := &grpc.ServiceDesc{
+		ServiceName: grpcPackage + "." + service.name,
+		HandlerType: (*any)(nil),
+		Metadata:    grpcGatewayFile,
+	}
+	for _, methodName := range service.methods {
+		desc.Methods = append(desc.Methods, grpc.MethodDesc{
+			MethodName: methodName,
+			Handler:    g.makeMethodHandler(service.apiName, desc.ServiceName, methodName),
+		})
+	}
+	return desc
+}
+
+func (g *GrpcGateway) makeMethodHandler(apiName string, serviceName string, methodName string) grpc.MethodHandler {
+	info := &grpc.UnaryServerInfo{Server: g, FullMethod: "/" + serviceName + "/" + methodName}
+	return func(_ any, ctx context.Context, decode func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		request := new(grpcRawMessage)
+		if err := decode(request); err != nil {
+			return nil, err
+		}
+		handle := func(ctx context.Context, request any) (any, error) {
+			message, _ := request.(*grpcRawMessage)
+			response, err := g.handle(ctx, apiName, methodName, *message)
+			if err != nil {
+				return nil, err
+			}
+			return &response, nil
+		}
+		if interceptor == nil {
+			return handle(ctx, request)
+		}
+		return interceptor(ctx, request, info, handle)
+	}
+}
+
+// handle calls the handler of the method set for the shard, the errors of the API are returned in the response.
+func (g *GrpcGateway) handle(
+	ctx context.Context, apiName string, methodName string, request []byte,
+) (grpcRawMessage, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	shardId, err := grpcShardId(md)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The clients of the gateway expect the Protobuf messages, so the version sending SSZ isn't used.
+	protocol := makeVersionedProtocolId(shardId, apiName, legacyApiVersion, methodName)
+	g.mu.RLock()
+	handler, ok := g.handlers[protocol]
+	g.mu.RUnlock()
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "method %s is not served in shard %d", methodName, shardId)
+	}
+
+	peerId, err := grpcRequestPeer(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	ctx = network.WithRequestPeer(ctx, peerId)
+	if tokens := md.Get(grpcAuthTokenHeader); len(tokens) != 0 {
+		ctx = WithAuthToken(ctx, tokens[0])
+	}
+	envelope, err := packRequestEnvelopeAccepting(ctx, protocol, request, pb.Compression_NoCompression, false)
+	if err != nil {
+		return nil, status.FromContextError(err).Err()
+	}
+	response, err := handler(ctx, envelope)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+	return response, nil
+}
+
+func grpcShardId(md metadata.MD) (types.ShardId, error) {
+	values := md.Get(grpcShardIdHeader)
+	if len(values) == 0 {
+		return 0, fmt.Errorf("%s metadata is required", grpcShardIdHeader)
+	}
+	shardId, err := strconv.ParseUint(values[0], 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s metadata: %w", grpcShardIdHeader, err)
+	}
+	return types.ShardId(shardId), nil
+}
+
+// grpcRequestPeer returns the peer of the connection the request is received by.
+func grpcRequestPeer(ctx context.Context) (network.PeerID, error) {
+	client, ok := peer.FromContext(ctx)
+	if !ok || client.Addr == nil {
+		return "", errors.New("no client connection")
+	}
+	return network.PeerID(grpcPeerPrefix + client.Addr.String()), nil
+}
+
+func isLoopbackAddr(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	return ok && tcpAddr.IP.IsLoopback()
+}
+
+// grpcService is a served API, its methods are the ones of its transport interface with a single response.
+type grpcService struct {
+	apiName string
+	name    string
+	methods []string
+}
+
+// grpcGatewayServices returns the services of the gateway together with the registry of the file describing them,
+// which refers to the messages of the pb package.
+var grpcGatewayServices = sync.OnceValues(func() ([]grpcService, *protoregistry.Files, error) {
+	file := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String(grpcGatewayFile),
+		Package: proto.String(grpcPackage),
+		Syntax:  proto.String("proto3"),
+	}
+	dependencies := make(map[string]struct{})
+	messageName := func(message proto.Message) *string {
+		descriptor := message.ProtoReflect().Descriptor()
+		dependencies[descriptor.ParentFile().Path()] = struct{}{}
+		return proto.String("." + string(descriptor.FullName()))
+	}
+
+	services := make([]grpcService, 0, len(servedApis))
+	for _, served := range servedApis {
+		codec, err := newApiCodec(served.api, served.transport)
+		if err != nil {
+			return nil, nil, err
+		}
+		service := grpcService{
+			apiName: served.name,
+			name:    strings.ToUpper(served.api.Name()[:1]) + served.api.Name()[1:],
+		}
+		serviceProto := &descriptorpb.ServiceDescriptorProto{Name: proto.String(service.name)}
+		for _, methodName := range slices.Sorted(maps.Keys(codec)) {
+			methodCodec := codec[methodName]
+			if methodCodec.kind != singleResponse {
+				continue
+			}
+			var request proto.Message = &emptypb.Empty{}
+			if methodCodec.pbRequestType != nil {
+				request = newProtoMessage(methodCodec.pbRequestType)
+			}
+			serviceProto.Method = append(serviceProto.Method, &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String(methodName),
+				InputType:  messageName(request),
+				OutputType: messageName(newProtoMessage(methodCodec.pbResponseType)),
+			})
+			service.methods = append(service.methods, methodName)
+		}
+		file.Service = append(file.Service, serviceProto)
+		services = append(services, service)
+	}
+	file.Dependency = slices.Sorted(maps.Keys(dependencies))
+
+	descriptor, err := protodesc.NewFile(file, protoregistry.GlobalFiles)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to describe gRPC services: %w", err)
+	}
+	files := new(protoregistry.Files)
+	if err := files.RegisterFile(descriptor); err != nil {
+		return nil, nil, err
+	}
+	return services, files, nil
+})
+
+func newProtoMessage(t reflect.Type) proto.Message {
+	message, ok := reflect.New(t).Interface().(proto.Message)
+	// Should never happen, the types are checked by the codec.
+	check.PanicIfNotf(ok, "%s is not a Protobuf message", t)
+	return message
+}
+
+// grpcGatewayResolver resolves the descriptors of the gateway, the rest are resolved by the global registry.
+type grpcGatewayResolver struct {
+	files *protoregistry.Files
+}
+
+func (r grpcGatewayResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if file, err := r.files.FindFileByPath(path); err == nil {
+		return file, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r grpcGatewayResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if descriptor, err := r.files.FindDescriptorByName(name); err == nil {
+		return descriptor, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// grpcRawMessage is a raw API message, it is passed through as it is, since the handlers unpack and pack it.
+type grpcRawMessage []byte
+
+// grpcRawCodec passes the raw API messages through, the other messages, e.g., the ones of the reflection,
+// are marshaled with Protobuf. It is named "proto", since that's what the messages are for the clients.
+type grpcRawCodec struct{}
+
+func (grpcRawCodec) Marshal(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case *grpcRawMessage:
+		return *v, nil
+	case proto.Message:
+		return proto.Marshal(v)
+	}
+	return nil, fmt.Errorf("unexpected message type %T", v)
+}
+
+func (grpcRawCodec) Unmarshal(data []byte, v any) error {
+	switch v := v.(type) {
+	case *grpcRawMessage:
+		// The buffer may be reused by gRPC once the message is received.
+		*v = slices.Clone(data)
+		return nil
+	case proto.Message:
+		return proto.Unmarshal(data, v)
+	}
+	return fmt.Errorf("unexpected message type %T", v)
+}
+
+func (grpcRawCodec) Name() string {
+	return "proto"
+}
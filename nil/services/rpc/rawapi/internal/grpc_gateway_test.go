@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestGrpcGateway(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	initialTcpPort.CompareAndSwap(0, 9010)
+	manager := network.NewTestManagers(ctx, t, int(initialTcpPort.Add(2)), 1)[0]
+	gateway := NewGrpcGateway(manager)
+
+	var authToken string
+	var requestPeer network.PeerID
+	handler := func(ctx context.Context, request []byte) ([]byte, error) {
+		authToken, _ = ctx.Value(requestAuthTokenKey{}).(string)
+		requestPeer, _ = network.RequestPeer(ctx)
+		return proto.Marshal(&pb.StringResponse{Result: &pb.StringResponse_Value{Value: "gateway/v1"}})
+	}
+	packError := func(err error) []byte {
+		response, packErr := proto.Marshal(&pb.StringResponse{
+			Result: &pb.StringResponse_Error{Error: new(pb.Error).PackProtoMessage(err)},
+		})
+		require.NoError(t, packErr)
+		return response
+	}
+	gateway.SetRequestHandler(
+		ctx,
+		makeVersionedProtocolId(1, apiNameRo, legacyApiVersion, "ClientVersion"),
+		makeEnvelopeRequestHandler(
+			handler,
+			makeProtocolId(1, apiNameRo, "ClientVersion"),
+			packError,
+			RequestHandlersConfig{},
+			newChunkStore(),
+			newSignedRequests()))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- gateway.serve(ctx, listener, GrpcGatewayConfig{Reflection: true}, logging.NewLogger("Test"))
+	}()
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	const method = "/rawapi.ShardApiRo/ClientVersion"
+
+	t.Run("Call", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(ctx, grpcShardIdHeader, "1", grpcAuthTokenHeader, "token")
+		var response pb.StringResponse
+		require.NoError(t, conn.Invoke(ctx, method, &emptypb.Empty{}, &response))
+		require.Equal(t, "gateway/v1", response.GetValue())
+		require.Equal(t, "token", authToken)
+	})
+
+	t.Run("Peer", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(ctx, grpcShardIdHeader, "1")
+		require.NoError(t, conn.Invoke(ctx, method, &emptypb.Empty{}, new(pb.StringResponse)))
+		require.True(t, strings.HasPrefix(string(requestPeer), grpcPeerPrefix+"127.0.0.1:"))
+
+		// Each connection is a peer of its own.
+		otherConn, err := grpc.NewClient(
+			listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		require.NoError(t, err)
+		defer otherConn.Close()
+
+		firstPeer := requestPeer
+		require.NoError(t, otherConn.Invoke(ctx, method, &emptypb.Empty{}, new(pb.StringResponse)))
+		require.NotEqual(t, firstPeer, requestPeer)
+	})
+
+	t.Run("NoShard", func(t *testing.T) {
+		err := conn.Invoke(ctx, method, &emptypb.Empty{}, new(pb.StringResponse))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("NotServedShard", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(ctx, grpcShardIdHeader, "2")
+		err := conn.Invoke(ctx, method, &emptypb.Empty{}, new(pb.StringResponse))
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+
+	t.Run("Reflection", func(t *testing.T) {
+		stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		require.NoError(t, err)
+		defer func() { require.NoError(t, stream.CloseSend()) }()
+
+		require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		}))
+		response, err := stream.Recv()
+		require.NoError(t, err)
+		services := make([]string, 0)
+		for _, service := range response.GetListServicesResponse().GetService() {
+			services = append(services, service.GetName())
+		}
+		require.Contains(t, services, "rawapi.ShardApiRo")
+		require.Contains(t, services, "rawapi.ShardApiDb")
+
+		require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{
+				FileContainingSymbol: "rawapi.ShardApiRo.GetBlockHeader",
+			},
+		}))
+		response, err = stream.Recv()
+		require.NoError(t, err)
+		require.NotEmpty(t, response.GetFileDescriptorResponse().GetFileDescriptorProto())
+	})
+
+	cancel()
+	require.NoError(t, <-served)
+}
+
+func TestGrpcGatewayInsecure(t *testing.T) {
+	t.Parallel()
+
+	gateway := NewGrpcGateway(nil)
+	logger := logging.NewLogger("Test")
+
+	listener, err := net.Listen("tcp", "0.0.0.0:0")
+	require.NoError(t, err)
+	require.ErrorContains(t, gateway.serve(t.Context(), listener, GrpcGatewayConfig{}, logger), "TLS is required")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	listener, err = net.Listen("tcp", "0.0.0.0:0")
+	require.NoError(t, err)
+	served := make(chan error, 1)
+	go func() {
+		served <- gateway.serve(ctx, listener, GrpcGatewayConfig{AllowInsecure: true}, logger)
+	}()
+	cancel()
+	require.NoError(t, <-served)
+}
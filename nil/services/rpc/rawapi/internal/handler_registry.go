@@ -0,0 +1,223 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// apiHandlers are the handlers of the methods of an API by protocol ID.
+type apiHandlers struct {
+	requests map[network.ProtocolID]network.RequestHandler
+	streams  map[network.ProtocolID]network.StreamHandler
+}
+
+func (h *apiHandlers) has(protocol network.ProtocolID) bool {
+	if h == nil {
+		return false
+	}
+	_, isRequest := h.requests[protocol]
+	_, isStream := h.streams[protocol]
+	return isRequest || isStream
+}
+
+// servedApi serves the API by the handlers it currently points to, so that they can be swapped at once.
+type servedApi struct {
+	handlers atomic.Pointer[apiHandlers]
+	// round is the round of the shard the API was set in.
+	round int
+}
+
+// shardHandlers are the handlers of the APIs of a shard by API name.
+type shardHandlers struct {
+	apis     map[string]*servedApi
+	inFlight *inFlightRequests
+	// round is increased by handlerRegistry.beginRound, the APIs not set since then are removed by endRound.
+	round int
+}
+
+// handlerRegistry keeps the handlers set by setRawApiRequestHandlers for each shard, so that they can be
+// swapped and unset, and counts the requests they are handling, so that they can be drained.
+// The network handlers are set once per protocol and call the current handlers of the API, so the handlers
+// of an API set again are swapped atomically without removing the protocols from the network.
+// The requests of the streaming methods and the subscriptions are not counted, since a subscription
+// may last forever, their streams are only unset.
+type handlerRegistry struct {
+	mu     sync.Mutex
+	shards map[types.ShardId]*shardHandlers
+}
+
+func newHandlerRegistry() *handlerRegistry {
+	return &handlerRegistry{
+		shards: make(map[types.ShardId]*shardHandlers),
+	}
+}
+
+func (r *handlerRegistry) getOrAddShard(shardId types.ShardId) *shardHandlers {
+	shard, ok := r.shards[shardId]
+	if !ok {
+		shard = &shardHandlers{
+			apis:     make(map[string]*servedApi),
+			inFlight: &inFlightRequests{},
+		}
+		r.shards[shardId] = shard
+	}
+	return shard
+}
+
+// set serves the handlers of the API of the shard, replacing the ones it was served by.
+// The protocols the API no longer has are removed from the network.
+func (r *handlerRegistry) set(
+	ctx context.Context,
+	manager network.Manager,
+	shardId types.ShardId,
+	apiName string,
+	handlers *apiHandlers,
+) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shard := r.getOrAddShard(shardId)
+	api, ok := shard.apis[apiName]
+	if !ok {
+		api = &servedApi{}
+		shard.apis[apiName] = api
+	}
+	api.round = shard.round
+	previous := api.handlers.Swap(handlers)
+
+	for protocol := range handlers.requests {
+		if !previous.has(protocol) {
+			manager.SetRequestHandler(ctx, protocol, makeServedRequestHandler(api, protocol, shard.inFlight))
+		}
+	}
+	for protocol := range handlers.streams {
+		if !previous.has(protocol) {
+			manager.SetStreamHandler(ctx, protocol, makeServedStreamHandler(api, protocol))
+		}
+	}
+	if previous != nil {
+		removeMissingProtocols(manager, previous, handlers)
+	}
+}
+
+// beginRound starts setting the APIs of the shard anew, see endRound.
+func (r *handlerRegistry) beginRound(shardId types.ShardId) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.getOrAddShard(shardId).round++
+}
+
+// endRound removes the APIs of the shard that were not set since beginRound.
+func (r *handlerRegistry) endRound(manager network.Manager, shardId types.ShardId) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shard, ok := r.shards[shardId]
+	if !ok {
+		return
+	}
+	for apiName, api := range shard.apis {
+		if api.round != shard.round {
+			removeMissingProtocols(manager, api.handlers.Swap(&apiHandlers{}), nil)
+			delete(shard.apis, apiName)
+		}
+	}
+}
+
+// remove stops serving the API of the shard, the requests being handled by it are not waited for.
+func (r *handlerRegistry) remove(manager network.Manager, shardId types.ShardId, apiName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shard, ok := r.shards[shardId]
+	if !ok {
+		return
+	}
+	if api, ok := shard.apis[apiName]; ok {
+		removeMissingProtocols(manager, api.handlers.Swap(&apiHandlers{}), nil)
+		delete(shard.apis, apiName)
+	}
+}
+
+func removeMissingProtocols(manager network.Manager, previous *apiHandlers, current *apiHandlers) {
+	for protocol := range previous.requests {
+		if !current.has(protocol) {
+			manager.RemoveStreamHandler(protocol)
+		}
+	}
+	for protocol := range previous.streams {
+		if !current.has(protocol) {
+			manager.RemoveStreamHandler(protocol)
+		}
+	}
+}
+
+// makeServedRequestHandler counts the requests of the handler, the requests received after the drain
+// has started are rejected, e.g., the ones whose streams were opened before the handler was unset.
+func makeServedRequestHandler(
+	api *servedApi,
+	protocol network.ProtocolID,
+	inFlight *inFlightRequests,
+) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		handler, ok := api.handlers.Load().requests[protocol]
+		if !ok || !inFlight.enter() {
+			return nil, errRequestHandlersUnset
+		}
+		defer inFlight.leave()
+		return handler(ctx, request)
+	}
+}
+
+func makeServedStreamHandler(api *servedApi, protocol network.ProtocolID) network.StreamHandler {
+	return func(stream network.Stream) {
+		handler, ok := api.handlers.Load().streams[protocol]
+		if !ok {
+			_ = stream.Reset()
+			return
+		}
+		handler(stream)
+	}
+}
+
+// unset removes the handlers of the shards, so that the new requests are not accepted,
+// and drains the requests being handled until the context is done.
+// It returns the number of the requests still being handled.
+func (r *handlerRegistry) unset(ctx context.Context, manager network.Manager, shardIds []types.ShardId) int {
+	r.mu.Lock()
+	inFlight := make([]*inFlightRequests, 0, len(shardIds))
+	for _, shardId := range shardIds {
+		shard, ok := r.shards[shardId]
+		if !ok {
+			continue
+		}
+		delete(r.shards, shardId)
+		for _, api := range shard.apis {
+			removeMissingProtocols(manager, api.handlers.Swap(&apiHandlers{}), nil)
+		}
+		inFlight = append(inFlight, shard.inFlight)
+	}
+	r.mu.Unlock()
+
+	var remaining int
+	for _, requests := range inFlight {
+		remaining += requests.drain(ctx)
+	}
+	return remaining
+}
+
+func (r *handlerRegistry) shardIds() []types.ShardId {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	shardIds := make([]types.ShardId, 0, len(r.shards))
+	for shardId := range r.shards {
+		shardIds = append(shardIds, shardId)
+	}
+	return shardIds
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// jsonProtocolSuffix selects the JSON encoding of the requests and the responses of a method,
+// e.g., "/shard/1/rawapi_ro/GetBlockHeader/json".
+const jsonProtocolSuffix = "/json"
+
+func makeJsonProtocolId(protocol network.ProtocolID) network.ProtocolID {
+	return protocol + jsonProtocolSuffix
+}
+
+// makeJsonRequestHandler serves the method to the requests in the canonical JSON form of its Protobuf messages,
+// so that it can be called with a hand-written request while debugging. The request is passed to the handler
+// of the binary protocol in an envelope without options, so it is dispatched, intercepted and answered the same
+// way, and the response, including the error envelope, is converted back.
+func makeJsonRequestHandler(handler network.RequestHandler, codec *methodCodec) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		payload, err := jsonToProtobuf(request, codec.pbRequestType)
+		if err != nil {
+			return protobufToJson(codec.packError(rawapitypes.NewInvalidArgumentError(err)), codec.pbResponseType)
+		}
+		envelope, err := proto.Marshal(
+			new(pb.RequestEnvelope).PackProtoMessage(payload, 0, pb.Compression_NoCompression, false))
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := handler(ctx, envelope)
+		if err != nil {
+			return nil, err
+		}
+		return protobufToJson(response, codec.pbResponseType)
+	}
+}
+
+// jsonToProtobuf converts the JSON form of the message of the type to the binary one.
+// The methods without arguments accept an empty request.
+func jsonToProtobuf(request []byte, messageType reflect.Type) ([]byte, error) {
+	if messageType == nil {
+		return nil, nil
+	}
+	message, _ := reflect.New(messageType).Interface().(proto.Message)
+	if len(request) != 0 {
+		if err := protojson.Unmarshal(request, message); err != nil {
+			return nil, fmt.Errorf("failed to unpack JSON request: %w", err)
+		}
+	}
+	return proto.Marshal(message)
+}
+
+func protobufToJson(response []byte, messageType reflect.Type) ([]byte, error) {
+	message, _ := reflect.New(messageType).Interface().(proto.Message)
+	if err := proto.Unmarshal(response, message); err != nil {
+		return nil, fmt.Errorf("failed to unpack Protobuf response: %w", err)
+	}
+	return protojson.MarshalOptions{Multiline: true}.Marshal(message)
+}
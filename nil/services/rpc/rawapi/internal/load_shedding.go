@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	defaultSheddingRetryAfter = time.Second
+	// debugSheddingLoad is the share of the thresholds above which the debug requests are shed,
+	// so that they are shed before the requests of the users.
+	debugSheddingLoad = 0.5
+	// sheddingLatencyAlpha is the weight of the latest request in the moving average of the latency.
+	sheddingLatencyAlpha = 0.2
+)
+
+// SheddingThresholds are the loads of a raw API method above which its requests are shed.
+// Zero values disable the corresponding threshold.
+type SheddingThresholds struct {
+	// MaxLatency is the moving average of the time the requests are handled for.
+	MaxLatency time.Duration `yaml:"maxLatency,omitempty"`
+	// MaxInFlight is the number of the requests being handled, including the ones waiting for a worker
+	// if the requests are served on the worker pool after the shedding.
+	MaxInFlight int `yaml:"maxInFlight,omitempty"`
+}
+
+// LoadSheddingConfig configures the shedding of the low-priority requests of the overloaded methods.
+type LoadSheddingConfig struct {
+	// Thresholds map a protocol ID (e.g., "/shard/1/rawapi_ro/Call") or a method name (e.g., "Call")
+	// to the thresholds of the method. The thresholds of the protocol ID take precedence.
+	Thresholds map[string]SheddingThresholds `yaml:"thresholds"`
+	// Priorities override the default priorities of the methods by protocol ID or method name.
+	Priorities map[string]RequestPriority `yaml:"priorities,omitempty"`
+	// RetryAfter is suggested to the clients of the shed requests, 1s by default. The latency of a method
+	// isn't considered anymore if none of its requests has been handled during this time.
+	RetryAfter time.Duration `yaml:"retryAfter,omitempty"`
+}
+
+// NewLoadSheddingInterceptor creates an interceptor rejecting the requests of the methods whose load
+// crosses their thresholds with rawapitypes.ErrOverloaded and the time to retry after. The requests of
+// CriticalRequestPriority are never shed, while the debug ones are shed at the half of the thresholds already.
+func NewLoadSheddingInterceptor(cfg LoadSheddingConfig) RequestInterceptor {
+	retryAfter := cfg.RetryAfter
+	if retryAfter <= 0 {
+		retryAfter = defaultSheddingRetryAfter
+	}
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		thresholds, ok := findProtocolEntry(cfg.Thresholds, protocol)
+		if !ok || (thresholds.MaxLatency <= 0 && thresholds.MaxInFlight <= 0) {
+			return next
+		}
+		priority := requestPriorityOf(cfg.Priorities, protocol)
+		if priority == CriticalRequestPriority {
+			return next
+		}
+		maxLoad := 1.0
+		if priority == DebugRequestPriority {
+			maxLoad = debugSheddingLoad
+		}
+
+		load := &methodLoad{thresholds: thresholds, window: retryAfter}
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			if load.exceeds(maxLoad) {
+				return nil, rawapitypes.NewOverloadedError(retryAfter)
+			}
+			load.inFlight.Add(1)
+			start := time.Now()
+			defer func() {
+				load.inFlight.Add(-1)
+				load.addLatency(time.Since(start))
+			}()
+			return next(ctx, request)
+		}
+	}
+}
+
+// methodLoad tracks the load of a method.
+type methodLoad struct {
+	thresholds SheddingThresholds
+	// window is the time the latency is considered for after the last handled request. Otherwise,
+	// a method whose requests are all shed would never recover.
+	window time.Duration
+
+	inFlight atomic.Int64
+
+	mu         sync.Mutex
+	latency    time.Duration
+	lastSample time.Time
+}
+
+// exceeds reports whether the load is above the given share of the thresholds.
+func (l *methodLoad) exceeds(share float64) bool {
+	if l.thresholds.MaxInFlight > 0 && float64(l.inFlight.Load()) >= share*float64(l.thresholds.MaxInFlight) {
+		return true
+	}
+	if l.thresholds.MaxLatency <= 0 {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if time.Since(l.lastSample) > l.window {
+		return false
+	}
+	return float64(l.latency) > share*float64(l.thresholds.MaxLatency)
+}
+
+func (l *methodLoad) addLatency(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if time.Since(l.lastSample) > l.window {
+		l.latency = latency
+	} else {
+		l.latency = time.Duration(sheddingLatencyAlpha*float64(latency) + (1-sheddingLatencyAlpha)*float64(l.latency))
+	}
+	l.lastSample = time.Now()
+}
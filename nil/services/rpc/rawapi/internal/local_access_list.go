@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+// accessSet contains the storage keys accessed by a call grouped by account.
+type accessSet map[types.Address]map[common.Hash]struct{}
+
+func (s accessSet) keys(address types.Address) map[common.Hash]struct{} {
+	keys, ok := s[address]
+	if !ok {
+		keys = make(map[common.Hash]struct{})
+		s[address] = keys
+	}
+	return keys
+}
+
+// addExecutionState adds the accounts loaded by the execution together with the storage slots read or written.
+func (s accessSet) addExecutionState(es *execution.ExecutionState) {
+	for address, account := range es.Accounts {
+		keys := s.keys(address)
+		if account == nil {
+			continue
+		}
+		for key := range account.State {
+			keys[key] = struct{}{}
+		}
+	}
+}
+
+func (s accessSet) addAccessList(accessList []rawapitypes.AccessTuple) {
+	for _, tuple := range accessList {
+		keys := s.keys(tuple.Address)
+		for _, key := range tuple.StorageKeys {
+			keys[key] = struct{}{}
+		}
+	}
+}
+
+func (s accessSet) accessList() []rawapitypes.AccessTuple {
+	accessList := make([]rawapitypes.AccessTuple, 0, len(s))
+	for address, keys := range s {
+		tuple := rawapitypes.AccessTuple{Address: address, StorageKeys: make([]common.Hash, 0, len(keys))}
+		for key := range keys {
+			tuple.StorageKeys = append(tuple.StorageKeys, key)
+		}
+		slices.SortFunc(tuple.StorageKeys, func(a, b common.Hash) int {
+			return bytes.Compare(a.Bytes(), b.Bytes())
+		})
+		accessList = append(accessList, tuple)
+	}
+	slices.SortFunc(accessList, func(a, b rawapitypes.AccessTuple) int {
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes())
+	})
+	return accessList
+}
+
+// CreateAccessList executes the call and collects the accounts and the storage keys it touches.
+// The outgoing transactions are executed on their shards the same way as by Call, so the list includes
+// the cross-shard touches.
+func (api *localShardApiRo) CreateAccessList(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.AccessListResult, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.executeCall(ctx, tx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := make(accessSet)
+	accesses.addExecutionState(call.es)
+	if call.result.Failed() {
+		return &rawapitypes.AccessListResult{
+			AccessList: accesses.accessList(),
+			Error:      call.result.GetError().Error(),
+		}, nil
+	}
+
+	stateOverrides, err := call.stateChange(tx, overrides)
+	if err != nil {
+		return nil, err
+	}
+
+	blockRef := rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(
+		call.mainBlockHash, call.childBlocks)
+	for _, outTxn := range call.es.OutTransactions[call.txnHash] {
+		raw, err := outTxn.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := api.nodeApi.CreateAccessList(
+			ctx, rpctypes.CallArgs{Transaction: (*hexutil.Bytes)(&raw)}, blockRef, &stateOverrides, nil)
+		if err != nil {
+			return nil, err
+		}
+		accesses.addAccessList(res.AccessList)
+		if res.Error != "" {
+			return &rawapitypes.AccessListResult{AccessList: accesses.accessList(), Error: res.Error}, nil
+		}
+	}
+	return &rawapitypes.AccessListResult{AccessList: accesses.accessList()}, nil
+}
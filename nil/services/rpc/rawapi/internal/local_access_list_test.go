@@ -0,0 +1,87 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessSet(t *testing.T) {
+	t.Parallel()
+
+	first := types.ShardAndHexToAddress(types.BaseShardId, "0x01")
+	second := types.ShardAndHexToAddress(types.MainShardId, "0x02")
+	key := common.HexToHash("0x01")
+	otherKey := common.HexToHash("0x02")
+
+	// The lists of the calls on the other shards are merged, the keys are listed once and sorted.
+	accesses := make(accessSet)
+	accesses.addAccessList([]rawapitypes.AccessTuple{{Address: second, StorageKeys: []common.Hash{otherKey, key}}})
+	accesses.addAccessList([]rawapitypes.AccessTuple{
+		{Address: second, StorageKeys: []common.Hash{key}},
+		{Address: first},
+	})
+	require.Equal(t, []rawapitypes.AccessTuple{
+		{Address: first, StorageKeys: []common.Hash{}},
+		{Address: second, StorageKeys: []common.Hash{key, otherKey}},
+	}, accesses.accessList())
+}
+
+func TestCreateAccessList(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.MainShardId
+	hash := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	api := NodeApiBuilder(database, nil).
+		WithLocalShardApiRo(shardId).
+		BuildAndReset()
+	blockReference := rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(hash, nil)
+
+	// createAccessList calls the code at the address and returns the tuple of the address.
+	address := types.ShardAndHexToAddress(shardId, "0x1234")
+	createAccessList := func(t *testing.T, code hexutil.Bytes) (rawapitypes.AccessTuple, string) {
+		t.Helper()
+
+		overrides := rpctypes.StateOverrides{address: {Code: &code}}
+		args := rpctypes.CallArgs{
+			To:  address,
+			Fee: types.NewFeePackFromFeeCredit(rpctypes.FeeEstimationFeeCreditCap()),
+		}
+		res, err := api.CreateAccessList(t.Context(), args, blockReference, &overrides, nil)
+		require.NoError(t, err)
+		for _, tuple := range res.AccessList {
+			if tuple.Address == address {
+				return tuple, res.Error
+			}
+		}
+		require.FailNow(t, "address is not in the access list")
+		return rawapitypes.AccessTuple{}, ""
+	}
+
+	// The slots read and written are listed.
+	// PUSH1 5, SLOAD, POP, PUSH1 1, PUSH1 1, SSTORE, STOP
+	tuple, callErr := createAccessList(t, hexutil.Bytes{
+		byte(vm.PUSH1), 5, byte(vm.SLOAD), byte(vm.POP),
+		byte(vm.PUSH1), 1, byte(vm.PUSH1), 1, byte(vm.SSTORE), byte(vm.STOP),
+	})
+	require.Empty(t, callErr)
+	require.Equal(t, []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x05")}, tuple.StorageKeys)
+
+	// The slots accessed before the call fails are listed along with the error.
+	// PUSH1 7, SLOAD, INVALID
+	tuple, callErr = createAccessList(t, hexutil.Bytes{byte(vm.PUSH1), 7, byte(vm.SLOAD), byte(vm.INVALID)})
+	require.NotEmpty(t, callErr)
+	require.Equal(t, []common.Hash{common.HexToHash("0x07")}, tuple.StorageKeys)
+}
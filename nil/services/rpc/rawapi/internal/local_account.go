@@ -1,19 +1,24 @@
 package internal
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/contracts"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/mpt"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
-var errBlockNotFound = errors.New("block not found")
+var errBlockNotFound = fmt.Errorf("block %w", rawapitypes.ErrNotFound)
 
 func (api *localShardApiRo) GetBalance(
 	ctx context.Context,
@@ -22,7 +27,12 @@ func (api *localShardApiRo) GetBalance(
 ) (types.Value, error) {
 	shardId := address.ShardId()
 	if shardId != api.shardId() {
-		return types.Value{}, fmt.Errorf("address is not in the shard %d", api.shard)
+		return types.Value{}, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return types.Value{}, err
 	}
 
 	tx, err := api.db.CreateRoTx(ctx)
@@ -31,7 +41,7 @@ func (api *localShardApiRo) GetBalance(
 	}
 	defer tx.Rollback()
 
-	acc, err := api.getSmartContract(tx, address, blockReference)
+	acc, err := api.getSmartContract(ctx, tx, address, blockReference)
 	if err != nil {
 		if errors.Is(err, db.ErrKeyNotFound) {
 			return types.Value{}, nil
@@ -41,6 +51,54 @@ func (api *localShardApiRo) GetBalance(
 	return acc.Balance, nil
 }
 
+// GetBalances reads the accounts in the same block, which is resolved once for all of them.
+func (api *localShardApiRo) GetBalances(
+	ctx context.Context,
+	addresses []types.Address,
+	blockReference rawapitypes.BlockReference,
+) ([]types.Value, error) {
+	for _, address := range addresses {
+		if address.ShardId() != api.shardId() {
+			return nil, fmt.Errorf("%w: address %s is not in the shard %d",
+				rawapitypes.ErrShardMismatch, address, api.shard)
+		}
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open tx to find accounts: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, err := api.getBlockHeaderByReference(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make([]types.Value, len(addresses))
+	for i, address := range addresses {
+		contractRaw, _, err := api.getRawSmartContractInBlock(tx, address, block)
+		if err != nil {
+			if errors.Is(err, db.ErrKeyNotFound) {
+				balances[i] = types.NewZeroValue()
+				continue
+			}
+			return nil, err
+		}
+		contract := new(types.SmartContract)
+		if err := contract.UnmarshalSSZ(contractRaw); err != nil {
+			return nil, err
+		}
+		balances[i] = contract.Balance
+	}
+	return balances, nil
+}
+
 func (api *localShardApiRo) GetCode(
 	ctx context.Context,
 	address types.Address,
@@ -48,7 +106,12 @@ func (api *localShardApiRo) GetCode(
 ) (types.Code, error) {
 	shardId := address.ShardId()
 	if shardId != api.shardId() {
-		return types.Code{}, fmt.Errorf("address is not in the shard %d", api.shard)
+		return types.Code{}, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return types.Code{}, err
 	}
 
 	tx, err := api.db.CreateRoTx(ctx)
@@ -57,7 +120,7 @@ func (api *localShardApiRo) GetCode(
 	}
 	defer tx.Rollback()
 
-	acc, err := api.getSmartContract(tx, address, blockReference)
+	acc, err := api.getSmartContract(ctx, tx, address, blockReference)
 	if err != nil {
 		if errors.Is(err, db.ErrKeyNotFound) {
 			return nil, nil
@@ -75,14 +138,115 @@ func (api *localShardApiRo) GetCode(
 	return code, nil
 }
 
+func (api *localShardApiRo) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (types.Uint256, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return types.Uint256{}, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return types.Uint256{}, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return types.Uint256{}, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	acc, err := api.getSmartContract(ctx, tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return types.Uint256{}, nil
+		}
+		return types.Uint256{}, err
+	}
+
+	storageReader := execution.NewDbStorageTrieReader(tx, shardId)
+	storageReader.SetRootHash(acc.StorageRoot)
+	value, err := storageReader.Fetch(key)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return types.Uint256{}, nil
+		}
+		return types.Uint256{}, err
+	}
+	return *value, nil
+}
+
+// GetStorageRange iterates the storage trie of the contract, its keys are stored unhashed in their order.
+func (api *localShardApiRo) GetStorageRange(
+	ctx context.Context,
+	address types.Address,
+	start common.Hash,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StorageRange, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return nil, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+	if limit == 0 {
+		limit = maxStorageRangeSize
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	acc, err := api.getSmartContract(ctx, tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return &rawapitypes.StorageRange{}, nil
+		}
+		return nil, err
+	}
+
+	storageReader := execution.NewDbStorageTrieReader(tx, shardId)
+	storageReader.SetRootHash(acc.StorageRoot)
+	storageRange := &rawapitypes.StorageRange{}
+	for key, raw := range storageReader.IterateFrom(start.Bytes()) {
+		entryKey := common.BytesToHash(key)
+		if uint64(len(storageRange.Entries)) == limit {
+			storageRange.Next = &entryKey
+			break
+		}
+		var value types.Uint256
+		if err := value.UnmarshalSSZ(raw); err != nil {
+			return nil, err
+		}
+		storageRange.Entries = append(storageRange.Entries, rawapitypes.StorageEntry{Key: entryKey, Value: value})
+	}
+	return storageRange, nil
+}
+
 func (api *localShardApiRo) GetTokens(
 	ctx context.Context,
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
-) (map[types.TokenId]types.Value, error) {
+	request rawapitypes.TokensRequest,
+) (*rawapitypes.TokensPage, error) {
 	shardId := address.ShardId()
 	if shardId != api.shardId() {
-		return nil, fmt.Errorf("address is not in the shard %d", api.shard)
+		return nil, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
 	}
 
 	tx, err := api.db.CreateRoTx(ctx)
@@ -91,26 +255,121 @@ func (api *localShardApiRo) GetTokens(
 	}
 	defer tx.Rollback()
 
-	acc, err := api.getSmartContract(tx, address, blockReference)
+	acc, err := api.getSmartContract(ctx, tx, address, blockReference)
 	if err != nil {
 		if errors.Is(err, db.ErrKeyNotFound) {
-			return nil, nil
+			return &rawapitypes.TokensPage{}, nil
 		}
 		return nil, err
 	}
 
 	tokenReader := execution.NewDbTokenTrieReader(tx, shardId)
 	tokenReader.SetRootHash(acc.TokenRoot)
-	entries, err := tokenReader.Entries()
+	return readTokensPage(tokenReader, request)
+}
+
+func compareTokenIds(a, b types.TokenId) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// readTokensPage reads the tokens selected by the request in the order of their IDs. The listed tokens
+// are fetched one by one, the rest of the tokens are iterated starting from the requested one.
+func readTokensPage(
+	tokenReader *execution.TokenTrieReader,
+	request rawapitypes.TokensRequest,
+) (*rawapitypes.TokensPage, error) {
+	limit := request.Limit
+	if limit == 0 {
+		limit = maxTokensPageSize
+	}
+	page := &rawapitypes.TokensPage{Tokens: make(map[types.TokenId]types.Value)}
+	// add returns false once the page is full, the token becomes the start of the next page.
+	add := func(tokenId types.TokenId, value types.Value) bool {
+		if uint64(len(page.Tokens)) == limit {
+			page.Next = &tokenId
+			return false
+		}
+		page.Tokens[tokenId] = value
+		return true
+	}
+
+	if len(request.TokenIds) != 0 {
+		tokenIds := slices.Compact(slices.SortedFunc(slices.Values(request.TokenIds), compareTokenIds))
+		for _, tokenId := range tokenIds {
+			if request.Start != nil && compareTokenIds(tokenId, *request.Start) < 0 {
+				continue
+			}
+			value, err := tokenReader.Fetch(tokenId)
+			if errors.Is(err, db.ErrKeyNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !add(tokenId, *value) {
+				break
+			}
+		}
+		return page, nil
+	}
+
+	var start []byte
+	if request.Start != nil {
+		start = request.Start[:]
+	}
+	for key, raw := range tokenReader.IterateFrom(start) {
+		var value types.Value
+		if err := value.UnmarshalSSZ(raw); err != nil {
+			return nil, err
+		}
+		if !add(types.TokenId(types.BytesToAddress(key)), value) {
+			break
+		}
+	}
+	return page, nil
+}
+
+// GetTokenBalance fetches the balance of the token without reading the rest of the tokens of the account.
+func (api *localShardApiRo) GetTokenBalance(
+	ctx context.Context,
+	address types.Address,
+	tokenId types.TokenId,
+	blockReference rawapitypes.BlockReference,
+) (types.Value, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return types.Value{}, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
 	if err != nil {
-		return nil, err
+		return types.Value{}, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return types.Value{}, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	acc, err := api.getSmartContract(ctx, tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return types.Value{}, nil
+		}
+		return types.Value{}, err
 	}
 
-	return common.SliceToMap(
-		entries,
-		func(_ int, kv execution.Entry[types.TokenId, *types.Value]) (types.TokenId, types.Value) {
-			return kv.Key, *kv.Val
-		}), nil
+	tokenReader := execution.NewDbTokenTrieReader(tx, shardId)
+	tokenReader.SetRootHash(acc.TokenRoot)
+	value, err := tokenReader.Fetch(tokenId)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return types.Value{}, nil
+		}
+		return types.Value{}, err
+	}
+	return *value, nil
 }
 
 func (api *localShardApiRo) GetContract(
@@ -118,13 +377,18 @@ func (api *localShardApiRo) GetContract(
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
 ) (*rawapitypes.SmartContract, error) {
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := api.db.CreateRoTx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
 	}
 	defer tx.Rollback()
 
-	contractRaw, proofBuilder, err := api.getRawSmartContract(tx, address, blockReference)
+	contractRaw, proofBuilder, err := api.getRawSmartContract(ctx, tx, address, blockReference)
 	if err != nil && proofBuilder == nil {
 		return nil, err
 	}
@@ -145,12 +409,81 @@ func (api *localShardApiRo) GetContract(
 		return &rawapitypes.SmartContract{ProofEncoded: encodedProof}, nil
 	}
 
+	contract, err := api.readSmartContract(tx, contractRaw)
+	if err != nil {
+		return nil, err
+	}
+	contract.ProofEncoded = encodedProof
+	return contract, nil
+}
+
+// dispatchesMethods reports whether the dispatcher of the code pushes the selectors of all the methods of the ABI.
+// Solidity pushes the selector without its leading zero bytes.
+func dispatchesMethods(code types.Code, contractAbi *abi.ABI) bool {
+	for _, method := range contractAbi.Methods {
+		selector := bytes.TrimLeft(method.ID, "\x00")
+		push := append([]byte{byte(vm.PUSH0) + byte(len(selector))}, selector...)
+		if !bytes.Contains(code, push) {
+			return false
+		}
+	}
+	return true
+}
+
+func (api *localShardApiRo) GetAccountMeta(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountMeta, error) {
+	shardId := address.ShardId()
+	if shardId != api.shardId() {
+		return nil, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open tx to find account: %w", err)
+	}
+	defer tx.Rollback()
+
+	acc, err := api.getSmartContract(ctx, tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return &rawapitypes.AccountMeta{}, nil
+		}
+		return nil, err
+	}
+
+	code, err := db.ReadCode(tx, shardId, acc.CodeHash)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, err
+	}
+	smartAccountAbi, err := contracts.GetAbi(contracts.NameSmartAccount)
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.AccountMeta{
+		Exists:         true,
+		IsSmartAccount: len(code) != 0 && dispatchesMethods(code, smartAccountAbi),
+		CodeHash:       acc.CodeHash,
+		StorageRoot:    acc.StorageRoot,
+		ExtSeqno:       acc.ExtSeqno,
+	}, nil
+}
+
+// readSmartContract reads the code and the tries of the contract stored in the contract trie.
+func (api *localShardApiRo) readSmartContract(tx db.RoTx, contractRaw []byte) (*rawapitypes.SmartContract, error) {
 	contract := new(types.SmartContract)
 	if err := contract.UnmarshalSSZ(contractRaw); err != nil {
 		return nil, err
 	}
 
-	code, err := db.ReadCode(tx, address.ShardId(), contract.CodeHash)
+	code, err := db.ReadCode(tx, api.shardId(), contract.CodeHash)
 	if err != nil {
 		if !errors.Is(err, db.ErrKeyNotFound) {
 			return nil, err
@@ -158,21 +491,21 @@ func (api *localShardApiRo) GetContract(
 		code = nil
 	}
 
-	storageReader := execution.NewDbStorageTrieReader(tx, address.ShardId())
+	storageReader := execution.NewDbStorageTrieReader(tx, api.shardId())
 	storageReader.SetRootHash(contract.StorageRoot)
 	storageEntries, err := storageReader.Entries()
 	if err != nil {
 		return nil, err
 	}
 
-	tokenReader := execution.NewDbTokenTrieReader(tx, address.ShardId())
+	tokenReader := execution.NewDbTokenTrieReader(tx, api.shardId())
 	tokenReader.SetRootHash(contract.TokenRoot)
 	tokenEntries, err := tokenReader.Entries()
 	if err != nil {
 		return nil, err
 	}
 
-	asyncContextReader := execution.NewDbAsyncContextTrieReader(tx, address.ShardId())
+	asyncContextReader := execution.NewDbAsyncContextTrieReader(tx, api.shardId())
 	asyncContextReader.SetRootHash(contract.AsyncContextRoot)
 	asyncContextEntries, err := asyncContextReader.Entries()
 	if err != nil {
@@ -182,7 +515,6 @@ func (api *localShardApiRo) GetContract(
 	return &rawapitypes.SmartContract{
 		ContractSSZ:  contractRaw,
 		Code:         code,
-		ProofEncoded: encodedProof,
 		Storage:      execution.ConvertTrieEntriesToMap(storageEntries),
 		Tokens:       execution.ConvertTrieEntriesToMap(tokenEntries),
 		AsyncContext: execution.ConvertTrieEntriesToMap(asyncContextEntries),
@@ -197,20 +529,134 @@ func makeProofBuilder(root *mpt.Reader, key []byte) proofBuilder {
 	}
 }
 
+// maxProofStorageKeys limits the number of storage slots proved by a single GetProof request.
+const maxProofStorageKeys = 256
+
+func (api *localShardApiRo) GetProof(
+	ctx context.Context,
+	address types.Address,
+	storageKeys []common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.ContractProof, error) {
+	if address.ShardId() != api.shardId() {
+		return nil, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+	if len(storageKeys) > maxProofStorageKeys {
+		return nil, fmt.Errorf("at most %d storage keys can be proved at once", maxProofStorageKeys)
+	}
+
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, err := api.getBlockHeaderByReference(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	contractRaw, proofBuilder, err := api.getRawSmartContractInBlock(tx, address, block)
+	if err != nil && proofBuilder == nil {
+		return nil, err
+	}
+
+	proof, err := proofBuilder(mpt.ReadMPTOperation)
+	if err != nil {
+		return nil, err
+	}
+	encodedProof, err := proof.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &rawapitypes.ContractProof{
+		StateRoot:    block.SmartContractsRoot,
+		ContractSSZ:  contractRaw,
+		ProofEncoded: encodedProof,
+	}
+	// Storage of a non-existent contract is empty, the proof of the contract absence is sufficient.
+	if contractRaw == nil {
+		return result, nil
+	}
+
+	contract := new(types.SmartContract)
+	if err := contract.UnmarshalSSZ(contractRaw); err != nil {
+		return nil, err
+	}
+
+	storageReader := execution.NewDbStorageTrieReader(tx, address.ShardId())
+	storageReader.SetRootHash(contract.StorageRoot)
+	result.StorageProofs = make([]rawapitypes.StorageProof, len(storageKeys))
+	for i, key := range storageKeys {
+		var value types.Uint256
+		if v, err := storageReader.Fetch(key); err == nil {
+			value = *v
+		} else if !errors.Is(err, db.ErrKeyNotFound) {
+			return nil, err
+		}
+
+		proof, err := mpt.BuildProof(storageReader.Reader, key.Bytes(), mpt.ReadMPTOperation)
+		if err != nil {
+			return nil, err
+		}
+		encodedProof, err := proof.Encode()
+		if err != nil {
+			return nil, err
+		}
+
+		result.StorageProofs[i] = rawapitypes.StorageProof{
+			Key:          key,
+			Value:        value,
+			ProofEncoded: encodedProof,
+		}
+	}
+	return result, nil
+}
+
+func (api *localShardApiRo) getBlockHeaderByReference(
+	ctx context.Context,
+	tx db.RoTx,
+	blockReference rawapitypes.BlockReference,
+) (*types.Block, error) {
+	rawBlock, err := api.getBlockByReference(ctx, tx, blockReference, false)
+	if err != nil {
+		return nil, err
+	}
+	if rawBlock == nil {
+		return nil, errBlockNotFound
+	}
+	block := new(types.Block)
+	if err := block.UnmarshalSSZ(rawBlock.Block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
 func (api *localShardApiRo) getRawSmartContract(
+	ctx context.Context,
 	tx db.RoTx,
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
 ) ([]byte, proofBuilder, error) {
-	rawBlock, err := api.getBlockByReference(tx, blockReference, false)
+	block, err := api.getBlockHeaderByReference(ctx, tx, blockReference)
 	if err != nil {
 		return nil, nil, err
 	}
-	if rawBlock == nil {
-		return nil, nil, errBlockNotFound
-	}
-	var block types.Block
-	if err := block.UnmarshalSSZ(rawBlock.Block); err != nil {
+	return api.getRawSmartContractInBlock(tx, address, block)
+}
+
+func (api *localShardApiRo) getRawSmartContractInBlock(
+	tx db.RoTx,
+	address types.Address,
+	block *types.Block,
+) ([]byte, proofBuilder, error) {
+	if err := api.checkStateRetained(tx, block); err != nil {
 		return nil, nil, err
 	}
 
@@ -229,12 +675,32 @@ func (api *localShardApiRo) getRawSmartContract(
 	return contractRaw, makeProofBuilder(root, addressBytes), nil
 }
 
+// checkStateRetained fails with rawapitypes.ErrStatePruned if the state of the block is not served by the node.
+func (api *localShardApiRo) checkStateRetained(tx db.RoTx, block *types.Block) error {
+	if api.retainedStateBlocks == 0 {
+		return nil
+	}
+	lastBlock, _, err := db.ReadLastBlock(tx, api.shardId())
+	if err != nil {
+		return err
+	}
+	var earliest types.BlockNumber
+	if uint64(lastBlock.Id) >= api.retainedStateBlocks {
+		earliest = lastBlock.Id - types.BlockNumber(api.retainedStateBlocks) + 1
+	}
+	if block.Id < earliest {
+		return rawapitypes.NewStatePrunedError(block.Id, earliest)
+	}
+	return nil
+}
+
 func (api *localShardApiRo) getSmartContract(
+	ctx context.Context,
 	tx db.RoTx,
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
 ) (*types.SmartContract, error) {
-	contractRaw, _, err := api.getRawSmartContract(tx, address, blockReference)
+	contractRaw, _, err := api.getRawSmartContract(ctx, tx, address, blockReference)
 	if err != nil {
 		return nil, err
 	}
@@ -265,13 +731,18 @@ func (api *localShardApiRw) GetTransactionCount(
 		blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
 	}
 
+	blockReference, err := api.roApi.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return 0, err
+	}
+
 	tx, err := api.roApi.db.CreateRoTx(ctx)
 	if err != nil {
 		return 0, fmt.Errorf("cannot open tx to find account: %w", err)
 	}
 	defer tx.Rollback()
 
-	acc, err := api.roApi.getSmartContract(tx, address, blockReference)
+	acc, err := api.roApi.getSmartContract(ctx, tx, address, blockReference)
 	if err != nil {
 		if errors.Is(err, db.ErrKeyNotFound) {
 			return 0, nil
@@ -280,3 +751,25 @@ func (api *localShardApiRw) GetTransactionCount(
 	}
 	return uint64(acc.ExtSeqno), nil
 }
+
+// GetNextValidSeqno returns the seqno the next external transaction to the account should be sent with. Unlike
+// the transaction count of the pending block, it points to the first gap in the seqnos of the pooled transactions.
+func (api *localShardApiRw) GetNextValidSeqno(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (uint64, error) {
+	if blockReference.Type() == rawapitypes.NamedBlockIdentifierReference &&
+		blockReference.NamedBlockIdentifier() == rawapitypes.PendingBlock {
+		blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	}
+
+	seqno, err := api.GetTransactionCount(ctx, address, blockReference)
+	if err != nil {
+		return 0, err
+	}
+	if api.txnpool == nil {
+		return seqno, nil
+	}
+	return uint64(api.txnpool.NextSeqno(address, types.Seqno(seqno))), nil
+}
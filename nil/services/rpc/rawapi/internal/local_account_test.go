@@ -0,0 +1,285 @@
+package internal
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatchesMethods(t *testing.T) {
+	t.Parallel()
+
+	contractAbi, err := abi.JSON(strings.NewReader(`[
+		{"type": "function", "name": "verifyExternal", "inputs": [{"type": "uint256"}, {"type": "bytes"}]},
+		{"type": "function", "name": "asyncCall", "inputs": []}
+	]`))
+	require.NoError(t, err)
+
+	dispatch := func(name string) types.Code {
+		return append(types.Code{byte(vm.PUSH4)}, contractAbi.Methods[name].ID...)
+	}
+	code := append(append(types.Code{byte(vm.PUSH1), 0}, dispatch("verifyExternal")...), dispatch("asyncCall")...)
+	require.True(t, dispatchesMethods(code, &contractAbi))
+
+	// The code dispatching a part of the methods doesn't implement the ABI.
+	require.False(t, dispatchesMethods(dispatch("verifyExternal"), &contractAbi))
+	// The selector is to be pushed, not just contained in the code.
+	code = append(dispatch("verifyExternal"), dispatch("asyncCall")[1:]...)
+	require.False(t, dispatchesMethods(code, &contractAbi))
+}
+
+func TestReadTokensPage(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(tx.Rollback)
+
+	// The account holds more tokens than a page can contain, the IDs are ordered by their indices.
+	tokenIds := make([]types.TokenId, maxTokensPageSize+2)
+	values := make([]*types.Value, len(tokenIds))
+	for i := range tokenIds {
+		tokenIds[i] = types.TokenId(types.ShardAndHexToAddress(types.BaseShardId, fmt.Sprintf("0x%x", i+1)))
+		value := types.NewValueFromUint64(uint64(i + 1))
+		values[i] = &value
+	}
+	trie := execution.NewDbTokenTrie(tx, types.BaseShardId)
+	require.NoError(t, trie.UpdateBatch(tokenIds, values))
+
+	// The page is limited even if the limit is not set, the next page starts where it ends.
+	page, err := readTokensPage(trie.BaseMPTReader, rawapitypes.TokensRequest{})
+	require.NoError(t, err)
+	require.Len(t, page.Tokens, maxTokensPageSize)
+	require.Equal(t, &tokenIds[maxTokensPageSize], page.Next)
+
+	page, err = readTokensPage(trie.BaseMPTReader, rawapitypes.TokensRequest{Start: page.Next})
+	require.NoError(t, err)
+	require.Equal(t, map[types.TokenId]types.Value{
+		tokenIds[maxTokensPageSize]:   *values[maxTokensPageSize],
+		tokenIds[maxTokensPageSize+1]: *values[maxTokensPageSize+1],
+	}, page.Tokens)
+	require.Nil(t, page.Next)
+
+	// The listed tokens are paged by the limit as well, the ones not held are omitted.
+	page, err = readTokensPage(trie.BaseMPTReader, rawapitypes.TokensRequest{
+		Limit:    1,
+		TokenIds: []types.TokenId{tokenIds[2], types.TokenId(types.EmptyAddress), tokenIds[0]},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[types.TokenId]types.Value{tokenIds[0]: *values[0]}, page.Tokens)
+	require.Equal(t, &tokenIds[2], page.Next)
+}
+
+func TestGetStorageAt(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	execution.GenerateZeroState(t, types.BaseShardId, database)
+	api := newLocalShardApiRo(types.BaseShardId, database)
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+
+	// The constructor of the smart account stores its public key, each slot reads as the range of the storage.
+	storageRange, err := api.GetStorageRange(t.Context(), types.MainSmartAccountAddress, common.EmptyHash, 0, latest)
+	require.NoError(t, err)
+	require.NotEmpty(t, storageRange.Entries)
+	for _, entry := range storageRange.Entries {
+		value, err := api.GetStorageAt(t.Context(), types.MainSmartAccountAddress, entry.Key, latest)
+		require.NoError(t, err)
+		require.Equal(t, entry.Value, value)
+	}
+
+	// The slots never written and the storage of the accounts that don't exist are zero.
+	value, err := api.GetStorageAt(t.Context(), types.MainSmartAccountAddress, common.HexToHash("0xdead"), latest)
+	require.NoError(t, err)
+	require.True(t, value.IsZero())
+
+	address := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+	value, err = api.GetStorageAt(t.Context(), address, storageRange.Entries[0].Key, latest)
+	require.NoError(t, err)
+	require.True(t, value.IsZero())
+
+	_, err = api.GetStorageAt(t.Context(), types.ShardAndHexToAddress(types.MainShardId, "0x1234"),
+		storageRange.Entries[0].Key, latest)
+	require.ErrorIs(t, err, rawapitypes.ErrShardMismatch)
+}
+
+func TestGetProof(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	zeroBlock := execution.GenerateZeroState(t, shardId, database)
+	api := newLocalShardApiRo(shardId, database)
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+
+	// verifyRead checks that the proof is the proof of reading the value of the key in the trie of the root.
+	verifyRead := func(t *testing.T, encoded []byte, key []byte, value []byte, root common.Hash) {
+		t.Helper()
+
+		proof, err := mpt.DecodeProof(encoded)
+		require.NoError(t, err)
+		ok, err := proof.VerifyRead(key, value, root)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	storageRange, err := api.GetStorageRange(t.Context(), types.MainSmartAccountAddress, common.EmptyHash, 1, latest)
+	require.NoError(t, err)
+	require.Len(t, storageRange.Entries, 1)
+	stored := storageRange.Entries[0]
+	missing := common.HexToHash("0xdead")
+
+	// The contract is proved against the state root of the block, and its slots against its storage root.
+	proof, err := api.GetProof(t.Context(), types.MainSmartAccountAddress, []common.Hash{stored.Key, missing}, latest)
+	require.NoError(t, err)
+	require.Equal(t, zeroBlock.SmartContractsRoot, proof.StateRoot)
+	verifyRead(t, proof.ProofEncoded, types.MainSmartAccountAddress.Hash().Bytes(), proof.ContractSSZ, proof.StateRoot)
+
+	var contract types.SmartContract
+	require.NoError(t, contract.UnmarshalSSZ(proof.ContractSSZ))
+	require.Len(t, proof.StorageProofs, 2)
+	require.Equal(t, stored.Key, proof.StorageProofs[0].Key)
+	require.Equal(t, stored.Value, proof.StorageProofs[0].Value)
+	value, err := stored.Value.MarshalSSZ()
+	require.NoError(t, err)
+	verifyRead(t, proof.StorageProofs[0].ProofEncoded, stored.Key.Bytes(), value, contract.StorageRoot)
+
+	// The slots never written are proved absent.
+	require.True(t, proof.StorageProofs[1].Value.IsZero())
+	verifyRead(t, proof.StorageProofs[1].ProofEncoded, missing.Bytes(), nil, contract.StorageRoot)
+
+	// The absence of the contract is proved instead of its storage.
+	address := types.ShardAndHexToAddress(shardId, "0x1234")
+	proof, err = api.GetProof(t.Context(), address, []common.Hash{stored.Key}, latest)
+	require.NoError(t, err)
+	require.Nil(t, proof.ContractSSZ)
+	require.Empty(t, proof.StorageProofs)
+	verifyRead(t, proof.ProofEncoded, address.Hash().Bytes(), nil, proof.StateRoot)
+
+	_, err = api.GetProof(t.Context(), address, make([]common.Hash, maxProofStorageKeys+1), latest)
+	require.Error(t, err)
+}
+
+func TestGetBalances(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	execution.GenerateZeroState(t, types.BaseShardId, database)
+	api := NodeApiBuilder(database, nil).
+		WithLocalShardApiRo(types.MainShardId).
+		WithLocalShardApiRo(types.BaseShardId).
+		BuildAndReset()
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+
+	// The balances of the accounts of the shards are returned in the order of the addresses.
+	absent := types.ShardAndHexToAddress(types.MainShardId, "0x1234")
+	addresses := []types.Address{
+		types.MainSmartAccountAddress, types.GovernanceAddress, absent, types.FaucetAddress, types.GovernanceAddress,
+	}
+	balances, err := api.GetBalances(t.Context(), addresses, latest)
+	require.NoError(t, err)
+	require.Len(t, balances, len(addresses))
+	for i, address := range addresses {
+		balance, err := api.GetBalance(t.Context(), address, latest)
+		require.NoError(t, err)
+		require.Zero(t, balance.Cmp(balances[i]))
+	}
+	require.False(t, balances[0].IsZero())
+	require.False(t, balances[1].IsZero())
+	require.True(t, balances[2].IsZero())
+
+	// The request fails as a whole if a shard is not served.
+	_, err = api.GetBalances(t.Context(),
+		[]types.Address{types.MainSmartAccountAddress, types.ShardAndHexToAddress(types.ShardId(5), "0x1234")}, latest)
+	require.Error(t, err)
+}
+
+func TestGetStorageRange(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	// The contract stores the slots 1 to 5.
+	shardId := types.MainShardId
+	address := types.ShardAndHexToAddress(shardId, "0x1234")
+	keys := make([]common.Hash, 5)
+	values := make(map[common.Hash]types.Uint256)
+	for i := range keys {
+		keys[i] = common.BigToHash(big.NewInt(int64(i + 1)))
+		values[keys[i]] = *types.NewUint256(uint64(10 * (i + 1)))
+	}
+	zeroBlock := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	writeBlock(t, database, shardId, zeroBlock, func(es *execution.ExecutionState) {
+		for key, value := range values {
+			require.NoError(t, es.SetState(address, key, common.Hash(value.Bytes32())))
+		}
+	})
+	api := newLocalShardApiRo(shardId, database)
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+
+	// readKeys returns the keys of the range and its next key.
+	readKeys := func(t *testing.T, start common.Hash, limit uint64) ([]common.Hash, *common.Hash) {
+		t.Helper()
+
+		storageRange, err := api.GetStorageRange(t.Context(), address, start, limit, latest)
+		require.NoError(t, err)
+		rangeKeys := make([]common.Hash, len(storageRange.Entries))
+		for i, entry := range storageRange.Entries {
+			rangeKeys[i] = entry.Key
+			require.Equal(t, values[entry.Key], entry.Value)
+		}
+		return rangeKeys, storageRange.Next
+	}
+
+	// The ranges follow each other in the order of the keys.
+	rangeKeys, next := readKeys(t, keys[0], 2)
+	require.Equal(t, keys[:2], rangeKeys)
+	require.Equal(t, &keys[2], next)
+	rangeKeys, next = readKeys(t, *next, 2)
+	require.Equal(t, keys[2:4], rangeKeys)
+	require.Equal(t, &keys[4], next)
+	rangeKeys, next = readKeys(t, *next, 2)
+	require.Equal(t, keys[4:], rangeKeys)
+	require.Nil(t, next)
+
+	// The range without the limit is capped by the node only.
+	rangeKeys, next = readKeys(t, keys[0], 0)
+	require.Equal(t, keys, rangeKeys)
+	require.Nil(t, next)
+
+	// The storage of the account that doesn't exist is empty.
+	storageRange, err := api.GetStorageRange(t.Context(), types.ShardAndHexToAddress(shardId, "0x5678"),
+		common.EmptyHash, 0, latest)
+	require.NoError(t, err)
+	require.Empty(t, storageRange.Entries)
+	require.Nil(t, storageRange.Next)
+}
@@ -0,0 +1,303 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync/atomic"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/rs/zerolog"
+)
+
+var (
+	errSnapshotsDisabled  = errors.New("snapshot directory is not configured")
+	errSnapshotInProgress = errors.New("snapshot is already in progress")
+	errAclDisabled        = errors.New("access control is not enabled")
+	errQuotasDisabled     = errors.New("peer quotas are not enabled")
+	errNetworkDisabled    = errors.New("network is not configured")
+)
+
+// AdminApiConfig configures the admin API of the node.
+type AdminApiConfig struct {
+	// Auth authenticates the operators, the API is not served if no one can be authenticated.
+	Auth RequestAuth `yaml:"auth,omitempty"`
+	// SnapshotDir is the directory the snapshots of the database are written to, they are disabled if it is empty.
+	SnapshotDir string `yaml:"snapshotDir,omitempty"`
+}
+
+type localShardApiAdmin struct {
+	shard          types.ShardId
+	db             db.ReadOnlyDB
+	networkManager network.Manager
+	cfg            AdminApiConfig
+	// accessControl is nil if the raw API of the node is not restricted by an ACL.
+	accessControl *AccessControl
+	// quotas is nil if the usage of the raw API by peer is not accounted.
+	quotas *PeerQuotas
+	// flushCaches drops the caches of the local APIs of the node.
+	flushCaches func() uint64
+
+	snapshotting atomic.Bool
+	logger       logging.Logger
+}
+
+var _ shardApiAdmin = (*localShardApiAdmin)(nil)
+
+func newLocalShardApiAdmin(
+	shardId types.ShardId,
+	db db.ReadOnlyDB,
+	networkManager network.Manager,
+	cfg AdminApiConfig,
+	accessControl *AccessControl,
+	quotas *PeerQuotas,
+	flushCaches func() uint64,
+) *localShardApiAdmin {
+	return &localShardApiAdmin{
+		shard:          shardId,
+		db:             db,
+		networkManager: networkManager,
+		cfg:            cfg,
+		accessControl:  accessControl,
+		quotas:         quotas,
+		flushCaches:    flushCaches,
+		logger:         logging.NewLogger("admin_api"),
+	}
+}
+
+func (api *localShardApiAdmin) shardId() types.ShardId {
+	return api.shard
+}
+
+func (api *localShardApiAdmin) setNodeApi(_ NodeApi) {}
+
+// setAsP2pRequestHandlersIfAllowed serves the API only if the operators can be authenticated.
+// The authentication precedes the rest of the interceptors, so the rejected requests don't count towards the limits.
+func (api *localShardApiAdmin) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	if !api.cfg.Auth.Enabled() {
+		logger.Warn().Msg("Admin API is not served since neither a shared secret nor allowed peers are configured")
+		return nil
+	}
+	cfg.Interceptors = append([]RequestInterceptor{NewAuthInterceptor(api.cfg.Auth)}, cfg.Interceptors...)
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolAdmin](),
+		reflect.TypeFor[shardApiAdmin](),
+		api,
+		api.shard,
+		apiNameAdmin,
+		networkManager,
+		cfg,
+		logger)
+}
+
+// SetLogLevel sets the global log level of the node and returns the previous one.
+func (api *localShardApiAdmin) SetLogLevel(_ context.Context, level string) (string, error) {
+	previous := zerolog.GlobalLevel().String()
+	if err := logging.TrySetupGlobalLevel(level); err != nil {
+		return "", rawapitypes.NewInvalidArgumentError(err)
+	}
+	api.logger.Info().Msgf("Log level is changed from %s to %s", previous, level)
+	return previous, nil
+}
+
+// DisconnectPeer closes the connections to the peer and returns their number.
+// The peer is free to reconnect, it is not banned.
+func (api *localShardApiAdmin) DisconnectPeer(_ context.Context, peerId network.PeerID) (uint64, error) {
+	if api.networkManager == nil {
+		return 0, errNetworkDisabled
+	}
+	conns, err := api.networkManager.Disconnect(peerId)
+	if err != nil {
+		return 0, err
+	}
+	api.logger.Info().Msgf("Disconnected from %s, %d connections closed", peerId, conns)
+	return uint64(conns), nil
+}
+
+// AddPeer makes the node connect to the peer and keep its addresses, e.g., when a shard has no serving peers.
+// The peer is not remembered across restarts, the static config is for that.
+func (api *localShardApiAdmin) AddPeer(ctx context.Context, addr network.AddrInfo) (uint64, error) {
+	if api.networkManager == nil {
+		return 0, errNetworkDisabled
+	}
+	conns, err := api.networkManager.AddPeer(ctx, addr)
+	if err != nil {
+		return 0, err
+	}
+	api.logger.Info().Msgf("Added peer %s, %d connections open", addr.ID, conns)
+	return uint64(conns), nil
+}
+
+// RemovePeer makes the node disconnect from the peer and forget it.
+// The peer may still be discovered again or reconnect, it is not banned.
+func (api *localShardApiAdmin) RemovePeer(_ context.Context, peerId network.PeerID) (uint64, error) {
+	if api.networkManager == nil {
+		return 0, errNetworkDisabled
+	}
+	conns, err := api.networkManager.RemovePeer(peerId)
+	if err != nil {
+		return 0, err
+	}
+	api.logger.Info().Msgf("Removed peer %s, %d connections closed", peerId, conns)
+	return uint64(conns), nil
+}
+
+func (api *localShardApiAdmin) ProtectPeer(
+	_ context.Context, peerId network.PeerID, tag string, protect bool,
+) (bool, error) {
+	if api.networkManager == nil {
+		return false, errNetworkDisabled
+	}
+	protected := api.networkManager.ProtectPeer(peerId, tag, protect)
+	api.logger.Info().Msgf("Protection %s of peer %s is set to %t", tag, peerId, protect)
+	return protected, nil
+}
+
+func (api *localShardApiAdmin) SetPeerTag(
+	_ context.Context, peerId network.PeerID, tag string, value int64,
+) (int64, error) {
+	if api.networkManager == nil {
+		return 0, errNetworkDisabled
+	}
+	previous := api.networkManager.SetPeerTag(peerId, tag, int(value))
+	api.logger.Info().Msgf("Tag %s of peer %s is changed from %d to %d", tag, peerId, previous, value)
+	return int64(previous), nil
+}
+
+// FlushCaches drops the cached blocks, transactions and receipts of the local APIs
+// and returns the number of the dropped entries.
+func (api *localShardApiAdmin) FlushCaches(_ context.Context) (uint64, error) {
+	flushed := api.flushCaches()
+	api.logger.Info().Msgf("Caches are flushed, %d entries dropped", flushed)
+	return flushed, nil
+}
+
+// TriggerSnapshot starts writing the backup of the database to the snapshot directory and returns
+// the path of the file. The file appears once the backup is complete, only one backup is written at a time.
+func (api *localShardApiAdmin) TriggerSnapshot(ctx context.Context) (string, error) {
+	if api.cfg.SnapshotDir == "" {
+		return "", errSnapshotsDisabled
+	}
+	if !api.snapshotting.CompareAndSwap(false, true) {
+		return "", errSnapshotInProgress
+	}
+
+	name := fmt.Sprintf("snapshot-%s.backup", time.Now().UTC().Format("20060102T150405Z"))
+	path := filepath.Join(api.cfg.SnapshotDir, name)
+	// The backup outlives the request.
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer api.snapshotting.Store(false)
+
+		api.logger.Info().Msgf("Writing snapshot to %s...", path)
+		if err := api.writeSnapshot(ctx, path); err != nil {
+			api.logger.Error().Err(err).Msgf("Failed to write snapshot to %s", path)
+			return
+		}
+		api.logger.Info().Msgf("Snapshot is written to %s", path)
+	}()
+	return path, nil
+}
+
+// SetPeerAcl replaces the ACL of the raw API of the node. The new ACL applies to the requests received after it,
+// and it doesn't survive a restart of the node.
+func (api *localShardApiAdmin) SetPeerAcl(_ context.Context, acl rawapitypes.PeerAcl) (uint64, error) {
+	if api.accessControl == nil {
+		return 0, errAclDisabled
+	}
+	api.accessControl.Set(acl)
+	api.logger.Info().Msgf("Peer ACL is replaced, %d methods are restricted", len(acl))
+	return uint64(len(acl)), nil
+}
+
+func (api *localShardApiAdmin) GetPeerAcl(_ context.Context) (rawapitypes.PeerAcl, error) {
+	if api.accessControl == nil {
+		return nil, errAclDisabled
+	}
+	return api.accessControl.Get(), nil
+}
+
+func (api *localShardApiAdmin) GetPeerUsage(_ context.Context) ([]rawapitypes.PeerUsage, error) {
+	if api.quotas == nil {
+		return nil, errQuotasDisabled
+	}
+	return api.quotas.Usage(), nil
+}
+
+// GetPeers returns the peers the node is connected to. The peers which disconnect meanwhile are skipped.
+func (api *localShardApiAdmin) GetPeers(_ context.Context) ([]network.PeerInfo, error) {
+	if api.networkManager == nil {
+		return nil, errNetworkDisabled
+	}
+	peerIds := api.networkManager.ConnectedPeers()
+	peers := make([]network.PeerInfo, 0, len(peerIds))
+	for _, peerId := range peerIds {
+		info, err := api.networkManager.GetPeerInfo(peerId)
+		if errors.Is(err, network.ErrPeerNotConnected) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		peers = append(peers, info)
+	}
+	return peers, nil
+}
+
+func (api *localShardApiAdmin) GetPeerInfo(_ context.Context, peerId network.PeerID) (*network.PeerInfo, error) {
+	if api.networkManager == nil {
+		return nil, errNetworkDisabled
+	}
+	info, err := api.networkManager.GetPeerInfo(peerId)
+	if errors.Is(err, network.ErrPeerNotConnected) {
+		return nil, rawapitypes.NewError(rawapitypes.NotFoundErrorCode, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (api *localShardApiAdmin) GetBandwidthByProtocol(
+	_ context.Context,
+) (map[network.ProtocolID]network.BandwidthStats, error) {
+	if api.networkManager == nil {
+		return nil, errNetworkDisabled
+	}
+	return api.networkManager.GetBandwidthByProtocol(), nil
+}
+
+func (api *localShardApiAdmin) writeSnapshot(ctx context.Context, path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmpPath := path + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpPath)
+
+	err = api.db.Stream(ctx, func([]byte) bool { return true }, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
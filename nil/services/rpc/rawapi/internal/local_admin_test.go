@@ -0,0 +1,113 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalShardApiAdmin(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	newApi := func(cfg AdminApiConfig, accessControl *AccessControl) *localShardApiAdmin {
+		return newLocalShardApiAdmin(types.MainShardId, database, nil, cfg, accessControl, nil,
+			func() uint64 { return 3 })
+	}
+
+	t.Run("SetLogLevel", func(t *testing.T) {
+		t.Parallel()
+
+		previous := zerolog.GlobalLevel()
+		t.Cleanup(func() { zerolog.SetGlobalLevel(previous) })
+
+		api := newApi(AdminApiConfig{}, nil)
+		level, err := api.SetLogLevel(t.Context(), "trace")
+		require.NoError(t, err)
+		require.Equal(t, previous.String(), level)
+		require.Equal(t, zerolog.TraceLevel, zerolog.GlobalLevel())
+
+		// The level is kept if the new one is unknown.
+		_, err = api.SetLogLevel(t.Context(), "verbose")
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+		require.Equal(t, zerolog.TraceLevel, zerolog.GlobalLevel())
+	})
+
+	t.Run("FlushCaches", func(t *testing.T) {
+		t.Parallel()
+
+		flushed, err := newApi(AdminApiConfig{}, nil).FlushCaches(t.Context())
+		require.NoError(t, err)
+		require.EqualValues(t, 3, flushed)
+	})
+
+	t.Run("PeerAcl", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newApi(AdminApiConfig{}, nil).GetPeerAcl(t.Context())
+		require.ErrorIs(t, err, errAclDisabled)
+
+		// The ACL replaced is the one the node checks the requests against.
+		accessControl := NewAccessControl(nil)
+		api := newApi(AdminApiConfig{}, accessControl)
+		acl := rawapitypes.PeerAcl{"GetBlockHeader": []network.PeerID{"peer"}}
+		restricted, err := api.SetPeerAcl(t.Context(), acl)
+		require.NoError(t, err)
+		require.EqualValues(t, 1, restricted)
+		require.Equal(t, acl, accessControl.Get())
+
+		got, err := api.GetPeerAcl(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, acl, got)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Parallel()
+
+		// The node without the network or the quotas reports it instead of failing.
+		api := newApi(AdminApiConfig{}, nil)
+		_, err := api.DisconnectPeer(t.Context(), "peer")
+		require.ErrorIs(t, err, errNetworkDisabled)
+		_, err = api.GetPeers(t.Context())
+		require.ErrorIs(t, err, errNetworkDisabled)
+		_, err = api.GetPeerUsage(t.Context())
+		require.ErrorIs(t, err, errQuotasDisabled)
+		_, err = api.TriggerSnapshot(t.Context())
+		require.ErrorIs(t, err, errSnapshotsDisabled)
+	})
+
+	t.Run("TriggerSnapshot", func(t *testing.T) {
+		t.Parallel()
+
+		dir := filepath.Join(t.TempDir(), "snapshots")
+		api := newApi(AdminApiConfig{SnapshotDir: dir}, nil)
+		// Only one backup is written at a time.
+		api.snapshotting.Store(true)
+		_, err := api.TriggerSnapshot(t.Context())
+		require.ErrorIs(t, err, errSnapshotInProgress)
+		api.snapshotting.Store(false)
+
+		path, err := api.TriggerSnapshot(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, dir, filepath.Dir(path))
+
+		// The file appears once the backup is written, and the next one can be started then.
+		require.Eventually(t, func() bool {
+			_, err := os.Stat(path)
+			return err == nil && !api.snapshotting.Load()
+		}, 5*time.Second, 10*time.Millisecond)
+		_, err = os.Stat(path + ".tmp")
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
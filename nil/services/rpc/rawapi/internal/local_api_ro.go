@@ -9,18 +9,41 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
+// SyncProgressSource reports the progress of the synchronization of a shard with the network, e.g., its syncer.
+type SyncProgressSource interface {
+	SyncProgress() rawapitypes.SyncProgress
+}
+
+// NewBlocksSource notifies about the blocks committed to a shard by the node, e.g., its validator.
+// See collate.Validator.SubscribeBlocks.
+type NewBlocksSource interface {
+	SubscribeBlocks(ctx context.Context) <-chan types.BlockNumber
+}
+
 type localShardApiRo struct {
 	db       db.ReadOnlyDB
 	accessor *execution.StateAccessor
 	shard    types.ShardId
+	// retainedStateBlocks is the number of the latest blocks whose state is served, all of them if it is zero.
+	retainedStateBlocks uint64
+	// protocolVersion is the version of the network protocol of the node, it is empty if there is no network.
+	protocolVersion string
+	// syncProgress is nil if the shard is not synchronized with the network.
+	syncProgress SyncProgressSource
+	// newBlocks is nil if the node doesn't commit the blocks of the shard, the subscriptions are not served then.
+	newBlocks NewBlocksSource
 
 	nodeApi NodeApi
 	logger  logging.Logger
 }
 
-var _ shardApiRo = (*localShardApiRo)(nil)
+var (
+	_ shardApiRo            = (*localShardApiRo)(nil)
+	_ committedBlocksSource = (*localShardApiRo)(nil)
+)
 
 func newLocalShardApiRo(shardId types.ShardId, db db.ReadOnlyDB) *localShardApiRo {
 	stateAccessor := execution.NewStateAccessor()
@@ -39,6 +62,7 @@ func (api *localShardApiRo) shardId() types.ShardId {
 func (api *localShardApiRo) setAsP2pRequestHandlersIfAllowed(
 	ctx context.Context,
 	networkManager network.Manager,
+	cfg RequestHandlersConfig,
 	logger logging.Logger,
 ) error {
 	return setRawApiRequestHandlers(
@@ -49,6 +73,7 @@ func (api *localShardApiRo) setAsP2pRequestHandlersIfAllowed(
 		api.shard,
 		apiNameRo,
 		networkManager,
+		cfg,
 		logger)
 }
 
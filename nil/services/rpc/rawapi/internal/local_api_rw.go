@@ -13,6 +13,10 @@ import (
 type localShardApiRw struct {
 	roApi   *localShardApiRo
 	txnpool txnpool.Pool
+	sent    *sentTransactions
+	// rejectInvalid discards the transactions that fail the checks of the block generator instead of adding them
+	// to the pool, where they are included in a block as failed.
+	rejectInvalid bool
 }
 
 var _ shardApiRw = (*localShardApiRw)(nil)
@@ -21,6 +25,7 @@ func newLocalShardApiRw(roApi *localShardApiRo, txnpool txnpool.Pool) *localShar
 	return &localShardApiRw{
 		roApi:   roApi,
 		txnpool: txnpool,
+		sent:    newSentTransactions(),
 	}
 }
 
@@ -31,6 +36,7 @@ func (api *localShardApiRw) shardId() types.ShardId {
 func (api *localShardApiRw) setAsP2pRequestHandlersIfAllowed(
 	ctx context.Context,
 	networkManager network.Manager,
+	cfg RequestHandlersConfig,
 	logger logging.Logger,
 ) error {
 	return setRawApiRequestHandlers(
@@ -41,6 +47,7 @@ func (api *localShardApiRw) setAsP2pRequestHandlersIfAllowed(
 		api.roApi.shardId(),
 		apiNameRw,
 		networkManager,
+		cfg,
 		logger)
 }
 
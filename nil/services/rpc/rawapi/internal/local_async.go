@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// GetPendingResponsesFor returns a page of the async requests of the contract whose responses it hasn't processed
+// yet. The contract keeps the context of a request until the response is processed. The page starts at the request
+// with the start ID, or at the one following it in the async context trie.
+func (api *localShardApiDebug) GetPendingResponsesFor(
+	ctx context.Context,
+	address types.Address,
+	start types.TransactionIndex,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.PendingResponses, error) {
+	if address.ShardId() != api.shardId() {
+		return nil, fmt.Errorf("%w: address is not in the shard %d", rawapitypes.ErrShardMismatch, api.shardId())
+	}
+	if limit == 0 {
+		limit = maxPendingResponsesPageSize
+	}
+
+	blockReference, err := api.roApi.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	contract, err := api.roApi.getSmartContract(ctx, tx, address, blockReference)
+	if err != nil {
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return &rawapitypes.PendingResponses{Responses: []*rawapitypes.PendingResponse{}}, nil
+		}
+		return nil, err
+	}
+
+	asyncContextReader := execution.NewDbAsyncContextTrieReader(tx, api.shardId())
+	asyncContextReader.SetRootHash(contract.AsyncContextRoot)
+	return readPendingResponsesPage(asyncContextReader, start, limit)
+}
+
+// readPendingResponsesPage reads at most limit async contexts starting at the start ID in the order of the trie.
+func readPendingResponsesPage(
+	reader *execution.AsyncContextTrieReader, start types.TransactionIndex, limit uint64,
+) (*rawapitypes.PendingResponses, error) {
+	page := &rawapitypes.PendingResponses{Responses: []*rawapitypes.PendingResponse{}}
+	for key, raw := range reader.IterateFrom(start.Bytes()) {
+		requestId := types.BytesToTransactionIndex(key)
+		if uint64(len(page.Responses)) == limit {
+			page.Next = &requestId
+			break
+		}
+		var asyncContext types.AsyncContext
+		if err := asyncContext.UnmarshalSSZ(raw); err != nil {
+			return nil, err
+		}
+		page.Responses = append(page.Responses, &rawapitypes.PendingResponse{
+			RequestId:             requestId,
+			ResponseProcessingGas: asyncContext.ResponseProcessingGas,
+		})
+	}
+	return page, nil
+}
+
+// GetAwaitingContext returns the async requests whose responses are delivered after the transaction
+// received or sent by the shard. Whether the requests are still pending is checked at the latest blocks
+// of the shards of their callers, so a stuck workflow shows the first response that is not delivered.
+func (api *localShardApiDebug) GetAwaitingContext(
+	ctx context.Context,
+	hash common.Hash,
+) (*rawapitypes.AwaitingContext, error) {
+	txn, received, err := api.getTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	awaiting := &rawapitypes.AwaitingContext{
+		Flags:    txn.Flags,
+		From:     txn.From,
+		To:       txn.To,
+		Received: received,
+		Requests: make([]rawapitypes.AwaitedRequest, 0, len(txn.RequestChain)+1),
+	}
+	if !txn.IsRequestOrResponse() {
+		return awaiting, nil
+	}
+
+	// The response is delivered to the sender of the request. The chain is unwound from its end,
+	// see execution.ExecutionState.SendResponseTransaction.
+	caller := txn.From
+	if txn.IsResponse() {
+		caller = txn.To
+	}
+	awaiting.Requests = append(awaiting.Requests, rawapitypes.AwaitedRequest{Caller: caller, RequestId: txn.RequestId})
+	for _, request := range slices.Backward(txn.RequestChain) {
+		awaiting.Requests = append(awaiting.Requests, rawapitypes.AwaitedRequest{
+			Caller:    request.Caller,
+			RequestId: request.Id,
+		})
+	}
+
+	// The page of a single response starting at the request holds it only if the request is pending.
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	for i := range awaiting.Requests {
+		request := &awaiting.Requests[i]
+		requestId := types.TransactionIndex(request.RequestId)
+		page, err := api.roApi.nodeApi.GetPendingResponsesFor(ctx, request.Caller, requestId, 1, latest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get pending responses of %s: %w", request.Caller, err)
+		}
+		request.Pending = len(page.Responses) != 0 && page.Responses[0].RequestId == requestId
+	}
+	return awaiting, nil
+}
+
+// getTransaction looks the transaction up among the incoming transactions of the shard and then among
+// the outgoing ones. It reports whether the transaction is incoming.
+func (api *localShardApiDebug) getTransaction(
+	ctx context.Context, hash common.Hash,
+) (*types.Transaction, bool, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	accessor := api.roApi.accessor.Access(tx, api.shardId())
+	inData, err := accessor.GetInTransaction().ByHash(hash)
+	if err == nil {
+		return inData.Transaction(), true, nil
+	}
+	if !errors.Is(err, db.ErrKeyNotFound) {
+		return nil, false, err
+	}
+
+	outData, err := accessor.GetOutTransaction().ByHash(hash)
+	if err != nil {
+		return nil, false, err
+	}
+	return outData.Transaction(), false, nil
+}
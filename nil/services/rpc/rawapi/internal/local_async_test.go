@@ -0,0 +1,67 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadPendingResponsesPage(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(tx.Rollback)
+
+	// The contract awaits more responses than a page can contain.
+	requestIds := make([]types.TransactionIndex, maxPendingResponsesPageSize+1)
+	contexts := make([]*types.AsyncContext, len(requestIds))
+	for i := range requestIds {
+		requestIds[i] = types.TransactionIndex(i + 1)
+		contexts[i] = &types.AsyncContext{ResponseProcessingGas: types.Gas(1000 * (i + 1))}
+	}
+	trie := execution.NewDbAsyncContextTrie(tx, types.BaseShardId)
+	require.NoError(t, trie.UpdateBatch(requestIds, contexts))
+
+	// The next page starts where the page ends.
+	page, err := readPendingResponsesPage(trie.BaseMPTReader, 0, maxPendingResponsesPageSize)
+	require.NoError(t, err)
+	require.Len(t, page.Responses, maxPendingResponsesPageSize)
+	require.NotNil(t, page.Next)
+
+	read := make(map[types.TransactionIndex]types.Gas)
+	for _, response := range page.Responses {
+		read[response.RequestId] = response.ResponseProcessingGas
+	}
+	page, err = readPendingResponsesPage(trie.BaseMPTReader, *page.Next, maxPendingResponsesPageSize)
+	require.NoError(t, err)
+	require.Len(t, page.Responses, 1)
+	require.Nil(t, page.Next)
+	read[page.Responses[0].RequestId] = page.Responses[0].ResponseProcessingGas
+
+	// The pages hold all the requests exactly once.
+	require.Len(t, read, len(requestIds))
+	for i, requestId := range requestIds {
+		require.Equal(t, contexts[i].ResponseProcessingGas, read[requestId])
+	}
+
+	// The page starts at the request if it's pending, which GetAwaitingContext relies on.
+	page, err = readPendingResponsesPage(trie.BaseMPTReader, requestIds[5], 1)
+	require.NoError(t, err)
+	require.Equal(t, []*rawapitypes.PendingResponse{
+		{RequestId: requestIds[5], ResponseProcessingGas: contexts[5].ResponseProcessingGas},
+	}, page.Responses)
+
+	require.NoError(t, trie.Delete(requestIds[5]))
+	page, err = readPendingResponsesPage(trie.BaseMPTReader, requestIds[5], 1)
+	require.NoError(t, err)
+	require.NotEqual(t, requestIds[5], page.Responses[0].RequestId)
+}
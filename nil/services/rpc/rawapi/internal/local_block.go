@@ -3,11 +3,14 @@ package internal
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math/bits"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
@@ -17,13 +20,18 @@ func (api *localShardApiRo) GetBlockHeader(
 	ctx context.Context,
 	blockReference rawapitypes.BlockReference,
 ) (sszx.SSZEncodedData, error) {
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := api.db.CreateRoTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	block, err := api.getBlockByReference(tx, blockReference, false)
+	block, err := api.getBlockByReference(ctx, tx, blockReference, false)
 	if err != nil {
 		return nil, err
 	}
@@ -34,38 +42,169 @@ func (api *localShardApiRo) GetFullBlockData(
 	ctx context.Context,
 	blockReference rawapitypes.BlockReference,
 ) (*types.RawBlockWithExtractedData, error) {
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
 	tx, err := api.db.CreateRoTx(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer tx.Rollback()
 
-	return api.getBlockByReference(tx, blockReference, true)
+	return api.getBlockByReference(ctx, tx, blockReference, true)
 }
 
 func (api *localShardApiRo) GetBlockTransactionCount(
 	ctx context.Context,
 	blockReference rawapitypes.BlockReference,
 ) (uint64, error) {
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return 0, err
+	}
+
 	tx, err := api.db.CreateRoTx(ctx)
 	if err != nil {
 		return 0, err
 	}
 	defer tx.Rollback()
 
-	res, err := api.getBlockByReference(tx, blockReference, true)
+	res, err := api.getBlockByReference(ctx, tx, blockReference, true)
 	if err != nil {
 		return 0, err
 	}
 	return uint64(len(res.InTransactions)), nil
 }
 
+// maxBlockRangeSize limits the number of blocks returned by a single GetBlockRange request.
+// Clients are expected to request the rest of the range with subsequent calls.
+const maxBlockRangeSize = 100
+
+func (api *localShardApiRo) GetBlockRange(
+	ctx context.Context,
+	from types.BlockNumber,
+	count uint64,
+	fullBlocks bool,
+) ([]*types.RawBlockWithExtractedData, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	count = min(count, maxBlockRangeSize)
+	blocks := make([]*types.RawBlockWithExtractedData, 0, count)
+	for number := from; number < from.AddSaturating(count); number++ {
+		hash, err := db.ReadBlockHashByNumber(tx, api.shardId(), number)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			// The range is truncated by the last known block.
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		block, err := api.getBlockByHash(tx, hash, fullBlocks)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// GetBlockFinalitySignatures returns the aggregate signature of the block along with the validators it is
+// checked against. The validators of a block are defined by the config of the previous one.
+func (api *localShardApiRo) GetBlockFinalitySignatures(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.FinalitySignatures, error) {
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+	block, err := db.ReadBlock(tx, api.shardId(), blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	validators, err := api.getBlockValidators(tx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &rawapitypes.FinalitySignatures{
+		BlockHash:     blockHash,
+		BlockNumber:   block.Id,
+		Round:         block.Round,
+		ProposerIndex: block.ProposerIndex,
+		PublicKeys:    make([][]byte, len(validators)),
+		Quorum:        ibftQuorum(uint64(len(validators))),
+	}
+	for i := range validators {
+		result.PublicKeys[i] = validators[i].PublicKey[:]
+	}
+	if block.Signature != nil {
+		result.Signature = block.Signature.Sig
+		result.SignersMask = block.Signature.Mask
+		for _, b := range block.Signature.Mask {
+			result.Signers += uint64(bits.OnesCount8(b))
+		}
+	}
+	return result, nil
+}
+
+// getBlockValidators returns the validators of the block, which are defined by the config of the previous one.
+func (api *localShardApiRo) getBlockValidators(tx db.RoTx, block *types.Block) ([]config.ValidatorInfo, error) {
+	configBlock := block
+	if block.Id > 0 {
+		var err error
+		configBlock, err = db.ReadBlock(tx, api.shardId(), block.PrevBlock)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read block %s: %w", block.PrevBlock, err)
+		}
+	}
+	return api.getNextBlockValidators(tx, configBlock)
+}
+
+// getNextBlockValidators returns the validators of the blocks following the block, defined by its config.
+func (api *localShardApiRo) getNextBlockValidators(tx db.RoTx, block *types.Block) ([]config.ValidatorInfo, error) {
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, block, api.shardId())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+	return config.GetShardValidators(configAccessor, api.shardId())
+}
+
+// ibftQuorum returns the number of the validators required by IBFT to commit a block,
+// which is more than two thirds of them.
+func ibftQuorum(validators uint64) uint64 {
+	return 2*validators/3 + 1
+}
+
 func (api *localShardApiRo) getBlockByReference(
+	ctx context.Context,
 	tx db.RoTx,
 	blockReference rawapitypes.BlockReference,
 	withTransactions bool,
 ) (*types.RawBlockWithExtractedData, error) {
-	blockHash, err := api.getBlockHashByReference(tx, blockReference)
+	blockHash, err := api.getBlockHashByReference(ctx, tx, blockReference)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +212,22 @@ func (api *localShardApiRo) getBlockByReference(
 	return api.getBlockByHash(tx, blockHash, withTransactions)
 }
 
+// getBlockHashByReference resolves the block the request reads the data at, the request is tagged by the block,
+// see recordResolvedBlock.
 func (api *localShardApiRo) getBlockHashByReference(
+	ctx context.Context,
+	tx db.RoTx,
+	blockReference rawapitypes.BlockReference,
+) (common.Hash, error) {
+	blockHash, err := api.resolveBlockReference(tx, blockReference)
+	if err != nil {
+		return common.EmptyHash, err
+	}
+	recordResolvedBlock(ctx, blockHash)
+	return blockHash, nil
+}
+
+func (api *localShardApiRo) resolveBlockReference(
 	tx db.RoTx,
 	blockReference rawapitypes.BlockReference,
 ) (common.Hash, error) {
@@ -84,14 +238,49 @@ func (api *localShardApiRo) getBlockHashByReference(
 		switch blockReference.NamedBlockIdentifier() {
 		case rawapitypes.EarliestBlock:
 			return db.ReadBlockHashByNumber(tx, api.shardId(), 0)
-		case rawapitypes.LatestBlock, rawapitypes.PendingBlock:
+		case rawapitypes.LatestBlock, rawapitypes.PendingBlock, rawapitypes.SafeBlock:
+			// The blocks can't be reverted once they are committed by the validators of the shard.
 			return db.ReadLastBlockHash(tx, api.shardId())
+		case rawapitypes.FinalizedBlock:
+			if api.shardId().IsMainShard() {
+				return db.ReadLastBlockHash(tx, api.shardId())
+			}
+			// The finalized block of the other shards is replaced by its hash before the transaction is opened.
+			return common.EmptyHash, fmt.Errorf("%w: finalized block is not resolved by the latest main block",
+				rawapitypes.ErrInvalidBlockReference)
 		}
-		return common.EmptyHash, errors.New("unknown named block identifier")
+		return common.EmptyHash, fmt.Errorf("%w: unknown named block identifier", rawapitypes.ErrInvalidBlockReference)
 	case rawapitypes.HashBlockReference:
 		return blockReference.Hash(), nil
 	}
-	return common.EmptyHash, errors.New("unknown block reference type")
+	return common.EmptyHash, fmt.Errorf("%w: unknown block reference type", rawapitypes.ErrInvalidBlockReference)
+}
+
+// resolveFinalizedBlock replaces the reference to the finalized block by the reference to the last block of the shard
+// included in the latest main block. The main block may be read from another node, so the reference is resolved
+// before the transaction reading the shard is opened.
+func (api *localShardApiRo) resolveFinalizedBlock(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (rawapitypes.BlockReference, error) {
+	if api.shardId().IsMainShard() ||
+		blockReference.Type() != rawapitypes.NamedBlockIdentifierReference ||
+		blockReference.NamedBlockIdentifier() != rawapitypes.FinalizedBlock {
+		return blockReference, nil
+	}
+
+	rawMainBlock, err := api.nodeApi.GetFullBlockData(
+		ctx,
+		types.MainShardId,
+		rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+	if err != nil {
+		return blockReference, fmt.Errorf("failed to get latest main block: %w", err)
+	}
+	if len(rawMainBlock.ChildBlocks) < int(api.shardId()) {
+		return blockReference, fmt.Errorf(
+			"%w: main shard includes only %d blocks", rawapitypes.ErrShardNotFound, len(rawMainBlock.ChildBlocks))
+	}
+	return rawapitypes.BlockHashAsBlockReference(rawMainBlock.ChildBlocks[api.shardId()-1]), nil
 }
 
 func (api *localShardApiRo) getBlockByHash(
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlockRange(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	hash := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	for number := range types.BlockNumber(2) {
+		hash = execution.GenerateBlockFromTransactions(t, shardId, number+1, hash, database, nil)
+	}
+	api := NodeApiBuilder(database, nil).WithLocalShardApiRo(shardId).BuildAndReset()
+
+	// blockRange returns the numbers of the blocks of the range.
+	blockRange := func(t *testing.T, from types.BlockNumber, count uint64) []types.BlockNumber {
+		t.Helper()
+
+		blocks, err := api.GetBlockRange(t.Context(), shardId, from, count, false)
+		require.NoError(t, err)
+		numbers := make([]types.BlockNumber, len(blocks))
+		for i, raw := range blocks {
+			var block types.Block
+			require.NoError(t, block.UnmarshalSSZ(raw.Block))
+			numbers[i] = block.Id
+		}
+		return numbers
+	}
+
+	require.Equal(t, []types.BlockNumber{0, 1}, blockRange(t, 0, 2))
+	// The range is truncated by the last block, the end of the range doesn't overflow.
+	require.Equal(t, []types.BlockNumber{1, 2}, blockRange(t, 1, math.MaxUint64))
+	require.Empty(t, blockRange(t, 3, 10))
+}
+
+// mainBlockNodeApi serves the latest main block including the given blocks of the other shards.
+type mainBlockNodeApi struct {
+	NodeApi
+
+	childBlocks []common.Hash
+}
+
+func (api mainBlockNodeApi) GetFullBlockData(
+	_ context.Context, shardId types.ShardId, _ rawapitypes.BlockReference,
+) (*types.RawBlockWithExtractedData, error) {
+	if shardId != types.MainShardId {
+		return nil, makeShardNotFoundError("GetFullBlockData", shardId)
+	}
+	return &types.RawBlockWithExtractedData{ChildBlocks: api.childBlocks}, nil
+}
+
+func TestFinalizedBlock(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	finalized := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	execution.GenerateBlockFromTransactions(t, shardId, 1, finalized, database, nil)
+
+	api := newLocalShardApiRo(shardId, database)
+	api.setNodeApi(mainBlockNodeApi{childBlocks: []common.Hash{finalized}})
+
+	finalizedReference := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.FinalizedBlock)
+
+	// The finalized block is the last block of the shard included in the main block, not the latest one.
+	raw, err := api.GetBlockHeader(t.Context(), finalizedReference)
+	require.NoError(t, err)
+	var block types.Block
+	require.NoError(t, block.UnmarshalSSZ(raw))
+	require.Equal(t, types.BlockNumber(0), block.Id)
+
+	// The reference is replaced by the hash before the transaction is opened.
+	reference, err := api.resolveFinalizedBlock(t.Context(), finalizedReference)
+	require.NoError(t, err)
+	require.Equal(t, rawapitypes.BlockHashAsBlockReference(finalized), reference)
+}
+
+func TestGetBlockFinalitySignatures(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	// The main shard is validated by the validators of all the shards, the ones of several shards are listed once.
+	newValidator := func(key byte) config.ValidatorInfo {
+		return config.ValidatorInfo{PublicKey: config.Pubkey{key}}
+	}
+	zeroStateConfig, err := execution.CreateDefaultZeroStateConfig(execution.MainPublicKey)
+	require.NoError(t, err)
+	zeroStateConfig.ConfigParams = execution.ConfigParams{
+		Validators: config.ParamValidators{Validators: []config.ListValidators{
+			{List: []config.ValidatorInfo{newValidator(1), newValidator(2)}},
+			{List: []config.ValidatorInfo{newValidator(2), newValidator(3)}},
+		}},
+		GasPrice: config.ParamGasPrice{
+			Shards: []types.Uint256{*types.NewUint256(10), *types.NewUint256(10), *types.NewUint256(10)},
+		},
+	}
+	g, err := execution.NewBlockGenerator(t.Context(),
+		execution.NewBlockGeneratorParams(types.MainShardId, 3), database, nil)
+	require.NoError(t, err)
+	zeroState, err := g.GenerateZeroState(zeroStateConfig)
+	g.Rollback()
+	require.NoError(t, err)
+	genesis := zeroState.Hash(types.MainShardId)
+	hash := execution.GenerateBlockFromTransactions(t, types.MainShardId, 1, genesis, database, nil)
+
+	// The signature is not a part of the hash of the block, it's set after the block is committed.
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	block, err := db.ReadBlock(tx, types.MainShardId, hash)
+	require.NoError(t, err)
+	block.Signature = &types.BlsAggregateSignature{Sig: []byte{0xaa, 0xbb}, Mask: []byte{0b101}}
+	require.NoError(t, db.WriteBlock(tx, types.MainShardId, hash, block))
+	require.NoError(t, tx.Commit())
+
+	api := newLocalShardApiRo(types.MainShardId, database)
+	signatures, err := api.GetBlockFinalitySignatures(t.Context(), rawapitypes.BlockHashAsBlockReference(hash))
+	require.NoError(t, err)
+	require.Equal(t, hash, signatures.BlockHash)
+	require.Equal(t, types.BlockNumber(1), signatures.BlockNumber)
+	require.Equal(t, []byte{0xaa, 0xbb}, signatures.Signature)
+	require.Equal(t, []byte{0b101}, signatures.SignersMask)
+	require.Len(t, signatures.PublicKeys, 3)
+	for i, key := range signatures.PublicKeys {
+		require.Equal(t, newValidator(byte(i + 1)).PublicKey[:], key)
+	}
+	require.EqualValues(t, 2, signatures.Signers)
+	require.EqualValues(t, 3, signatures.Quorum)
+
+	// The genesis block isn't signed, its validators are defined by its own config.
+	signatures, err = api.GetBlockFinalitySignatures(t.Context(), rawapitypes.BlockNumberAsBlockReference(0))
+	require.NoError(t, err)
+	require.Equal(t, genesis, signatures.BlockHash)
+	require.Empty(t, signatures.Signature)
+	require.Zero(t, signatures.Signers)
+	require.Len(t, signatures.PublicKeys, 3)
+}
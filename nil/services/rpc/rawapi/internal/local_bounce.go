@@ -0,0 +1,63 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// GetBounceInfo returns the bounce and the refund transactions sent by the shard after the execution of
+// the transaction included in its block.
+func (api *localShardApiRo) GetBounceInfo(ctx context.Context, hash common.Hash) (*rawapitypes.BounceInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	accessor := api.accessor.Access(tx, api.shardId())
+	data, err := accessor.GetInTransaction().WithReceipt().ByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	txn := data.Transaction()
+	receipt := data.Receipt()
+
+	info := &rawapitypes.BounceInfo{
+		Success:            receipt.Success,
+		BounceTo:           txn.BounceTo,
+		BouncedValue:       types.NewZeroValue(),
+		RefundTo:           txn.RefundTo,
+		Refunded:           types.NewZeroValue(),
+		RefundTransactions: make([]common.Hash, 0),
+	}
+	if !receipt.Success {
+		info.ErrorMessage, err = db.ReadError(tx, hash)
+		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			return nil, err
+		}
+	}
+
+	for i := receipt.OutTxnIndex; i < receipt.OutTxnIndex+receipt.OutTxnNum; i++ {
+		outData, err := accessor.GetOutTransaction().ByIndex(types.TransactionIndex(i), data.Block())
+		if err != nil {
+			return nil, err
+		}
+		outTxn := outData.Transaction()
+		switch {
+		case outTxn.IsBounce():
+			info.Bounced = true
+			info.BouncedValue = outTxn.Value
+			info.BounceTransaction = outTxn.Hash()
+		case outTxn.IsRefund():
+			info.Refunded = info.Refunded.Add(outTxn.Value)
+			info.RefundTransactions = append(info.RefundTransactions, outTxn.Hash())
+		}
+	}
+	return info, nil
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBounceInfo(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	bounceTo := types.ShardAndHexToAddress(types.MainShardId, "0x01")
+	refundTo := types.ShardAndHexToAddress(shardId, "0x02")
+	newTransaction := func(data string, flags ...int) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.Flags = types.NewTransactionFlags(append(flags, types.TransactionFlagInternal)...)
+		txn.To = types.ShardAndHexToAddress(shardId, "0x1234")
+		txn.BounceTo = bounceTo
+		txn.RefundTo = refundTo
+		txn.Data = types.Code(data)
+		return txn
+	}
+	newReturned := func(to types.Address, value uint64, flag int) *types.Transaction {
+		txn := newTransaction("returned", flag)
+		txn.To = to
+		txn.Value = types.NewValueFromUint64(value)
+		return txn
+	}
+
+	// The failed transaction bounces its value and refunds the fee credit, the successful one only refunds it.
+	failed := newTransaction("failed")
+	succeeded := newTransaction("succeeded")
+	bounce := newReturned(bounceTo, 100, types.TransactionFlagBounce)
+	refunds := []*types.Transaction{
+		newReturned(refundTo, 10, types.TransactionFlagRefund),
+		newReturned(refundTo, 5, types.TransactionFlagRefund),
+		newReturned(refundTo, 7, types.TransactionFlagRefund),
+	}
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	zeroBlock := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	writeBlock(t, database, shardId, zeroBlock, func(es *execution.ExecutionState) {
+		send := func(parent common.Hash, txns ...*types.Transaction) {
+			for _, txn := range txns {
+				outTxn := &types.OutboundTransaction{Transaction: txn, TxnHash: txn.Hash()}
+				es.OutTransactions[parent] = append(es.OutTransactions[parent], outTxn)
+			}
+		}
+
+		hash := es.AddInTransaction(failed)
+		es.AddReceipt(execution.NewExecutionResult().SetError(types.NewError(types.ErrorExecution)))
+		send(hash, bounce, refunds[0], refunds[1])
+
+		hash = es.AddInTransaction(succeeded)
+		es.AddReceipt(execution.NewExecutionResult())
+		send(hash, refunds[2])
+	})
+
+	api := newLocalShardApiRo(shardId, database)
+
+	info, err := api.GetBounceInfo(t.Context(), failed.Hash())
+	require.NoError(t, err)
+	require.False(t, info.Success)
+	require.NotEmpty(t, info.ErrorMessage)
+	require.True(t, info.Bounced)
+	require.Equal(t, bounceTo, info.BounceTo)
+	require.Equal(t, types.NewValueFromUint64(100), info.BouncedValue)
+	require.Equal(t, bounce.Hash(), info.BounceTransaction)
+	require.Equal(t, refundTo, info.RefundTo)
+	require.Equal(t, types.NewValueFromUint64(15), info.Refunded)
+	require.Equal(t, []common.Hash{refunds[0].Hash(), refunds[1].Hash()}, info.RefundTransactions)
+
+	// The out transactions of the other transactions of the block are not attributed to the transaction.
+	info, err = api.GetBounceInfo(t.Context(), succeeded.Hash())
+	require.NoError(t, err)
+	require.True(t, info.Success)
+	require.Empty(t, info.ErrorMessage)
+	require.False(t, info.Bounced)
+	require.True(t, info.BouncedValue.IsZero())
+	require.Equal(t, types.NewValueFromUint64(7), info.Refunded)
+	require.Equal(t, []common.Hash{refunds[2].Hash()}, info.RefundTransactions)
+
+	// The transactions sent by the shard are not executed by it.
+	_, err = api.GetBounceInfo(t.Context(), bounce.Hash())
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+}
@@ -0,0 +1,80 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+// SimulateBundle executes the calls in the same execution state, so each call sees the changes of the previous ones.
+// A failed call is reported in its result and the rest of the bundle is executed anyway, as is a call repeating
+// the previous one, which can't be told from it in the same state. The transactions sent by the calls are returned
+// without being executed.
+func (api *localShardApiRo) SimulateBundle(
+	ctx context.Context,
+	calls []rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) ([]*rawapitypes.BundleCallResult, error) {
+	txns := make([]*types.Transaction, len(calls))
+	for i, args := range calls {
+		txn, err := args.ToTransaction()
+		if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		if shardId := txn.To.ShardId(); shardId != api.shardId() {
+			return nil, fmt.Errorf("%w: destination shard %d of call %d is not equal to the instance shard %d",
+				rawapitypes.ErrShardMismatch, shardId, i, api.shard)
+		}
+		txns[i] = txn
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.newCallExecution(ctx, tx, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*rawapitypes.BundleCallResult, len(calls))
+	for i, args := range calls {
+		if err := call.handle(ctx, args, txns[i], nil); errors.Is(err, errDuplicateCall) {
+			results[i] = &rawapitypes.BundleCallResult{Error: err.Error()}
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("call %d: %w", i, err)
+		}
+		res := call.result
+		result := &rawapitypes.BundleCallResult{
+			Data:      res.ReturnData,
+			CoinsUsed: res.CoinsUsed(),
+			GasUsed:   res.GasUsed,
+			Logs:      call.es.Logs[call.txnHash],
+		}
+		if res.Failed() {
+			result.Error = res.GetError().Error()
+		}
+		for _, outTxn := range call.es.OutTransactions[call.txnHash] {
+			raw, err := outTxn.MarshalSSZ()
+			if err != nil {
+				return nil, err
+			}
+			result.OutTransactions = append(result.OutTransactions, raw)
+		}
+		// The state keeps the logs by the hash of the call, the calls repeating it later start with no logs.
+		delete(call.es.Logs, call.txnHash)
+		delete(call.es.DebugLogs, call.txnHash)
+		delete(call.es.OutTransactions, call.txnHash)
+		results[i] = result
+	}
+	return results, nil
+}
@@ -0,0 +1,39 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulateBundle(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.MainShardId
+	hash := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	api := NodeApiBuilder(database, nil).WithLocalShardApiRo(shardId).BuildAndReset()
+
+	first := rpctypes.CallArgs{To: types.ShardAndHexToAddress(shardId, "0x1234")}
+	second := rpctypes.CallArgs{To: types.ShardAndHexToAddress(shardId, "0x5678")}
+	results, err := api.SimulateBundle(t.Context(),
+		[]rpctypes.CallArgs{first, first, second, first},
+		rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(hash, nil), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	// The call repeating the previous one fails alone, the same call is executed again after another one.
+	require.Contains(t, results[1].Error, "repeats the previous one")
+	for _, i := range []int{0, 2, 3} {
+		require.NotContains(t, results[i].Error, "repeats the previous one")
+	}
+	require.Equal(t, results[0], results[3])
+}
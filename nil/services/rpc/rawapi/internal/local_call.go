@@ -11,6 +11,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/config"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
@@ -137,7 +138,8 @@ func (api *localShardApiRo) handleOutTransactions(
 			ctx,
 			args,
 			rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(mainBlockHash, childBlocks),
-			overrides)
+			overrides,
+			nil)
 		if err != nil {
 			return nil, err
 		}
@@ -163,17 +165,29 @@ func (api *localShardApiRo) handleOutTransactions(
 	return outTransactions, nil
 }
 
-func (api *localShardApiRo) Call(
-	ctx context.Context, args rpctypes.CallArgs,
+var errDuplicateCall = rawapitypes.NewInvalidArgumentError(errors.New("call repeats the previous one"))
+
+// callExecution is the state of a block with the calls executed on top of it, the result is of the last call.
+type callExecution struct {
+	es            *execution.ExecutionState
+	block         *types.Block
+	txnHash       common.Hash
+	result        *execution.ExecutionResult
+	mainBlockHash common.Hash
+	childBlocks   []common.Hash
+}
+
+// executeCall executes the call on the state of the requested block. The block overrides apply to the block of
+// the destination shard only. The hooks, if not nil, trace the execution.
+func (api *localShardApiRo) executeCall(
+	ctx context.Context,
+	tx db.RoTx,
+	args rpctypes.CallArgs,
 	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 	overrides *rpctypes.StateOverrides,
-) (*rpctypes.CallResWithGasPrice, error) {
-	tx, err := api.db.CreateRoTx(ctx)
-	if err != nil {
-		return nil, err
-	}
-	defer tx.Rollback()
-
+	blockOverrides *rpctypes.BlockOverrides,
+	hooks *tracing.Hooks,
+) (*callExecution, error) {
 	txn, err := args.ToTransaction()
 	if err != nil {
 		return nil, err
@@ -181,9 +195,30 @@ func (api *localShardApiRo) Call(
 
 	shardId := txn.To.ShardId()
 	if shardId != api.shardId() {
-		return nil, fmt.Errorf("destination shard %d is not equal to the instance shard %d", shardId, api.shard)
+		return nil, fmt.Errorf("%w: destination shard %d is not equal to the instance shard %d",
+			rawapitypes.ErrShardMismatch, shardId, api.shard)
 	}
 
+	call, err := api.newCallExecution(ctx, tx, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if err := call.handle(ctx, args, txn, hooks); err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+// newCallExecution prepares the state of the requested block of the shard for the calls.
+func (api *localShardApiRo) newCallExecution(
+	ctx context.Context,
+	tx db.RoTx,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*callExecution, error) {
+	shardId := api.shardId()
+
 	var mainBlockHash common.Hash
 	var childBlocks []common.Hash
 	if mainBlockReferenceOrHashWithChildren.IsReference() {
@@ -240,10 +275,29 @@ func (api *localShardApiRo) Call(
 			return nil, err
 		}
 	}
+	if blockOverrides != nil {
+		blockOverrides.Override(es)
+	}
+
+	return &callExecution{
+		es:            es,
+		block:         block,
+		mainBlockHash: mainBlockHash,
+		childBlocks:   childBlocks,
+	}, nil
+}
 
+// handle executes the transaction of the call on top of the state left by the previous calls, if any.
+func (c *callExecution) handle(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	txn *types.Transaction,
+	hooks *tracing.Hooks,
+) error {
+	es := c.es
 	if txn.IsDeploy() {
 		if err := execution.ValidateDeployTransaction(txn); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
@@ -257,58 +311,102 @@ func (api *localShardApiRo) Call(
 	case txn.IsInternal():
 		payer = execution.NewTransactionPayer(txn, es)
 	default:
-		var toAs *execution.AccountState
-		if toAs, err = es.GetAccount(txn.To); err != nil {
-			return nil, err
+		toAs, err := es.GetAccount(txn.To)
+		if err != nil {
+			return err
 		} else if toAs == nil {
-			return nil, rpctypes.ErrToAccNotFound
+			return rpctypes.ErrToAccNotFound
 		}
 		payer = execution.NewAccountPayer(toAs, txn)
 	}
 
 	txn.TxId = es.InTxCounts[txn.From.ShardId()]
-	txnHash := es.AddInTransaction(txn)
-	res := es.HandleTransaction(ctx, txn, payer)
+	hash := txn.Hash()
+	if hash == es.InTransactionHash {
+		return errDuplicateCall
+	}
+	es.AddInTransactionWithHash(txn, hash)
+	c.txnHash = hash
+	es.EvmTracingHooks = hooks
+	c.result = es.HandleTransaction(ctx, txn, payer)
+	return nil
+}
+
+// stateChange returns the state modified by the call in the form of overrides for the outgoing transactions.
+func (c *callExecution) stateChange(
+	tx db.RoTx, overrides *rpctypes.StateOverrides,
+) (rpctypes.StateOverrides, error) {
+	esOld, err := execution.NewExecutionState(tx, c.es.ShardId, execution.StateParams{
+		Block:          c.block,
+		ConfigAccessor: config.GetStubAccessor(),
+		Mode:           execution.ModeReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return calculateStateChange(c.es, esOld, overrides)
+}
+
+func (api *localShardApiRo) Call(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	result, _, err := api.call(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	return result, err
+}
+
+// call is Call also returning the error of the failed execution, so that the error can be told by its code.
+func (api *localShardApiRo) call(
+	ctx context.Context, args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (_ *rpctypes.CallResWithGasPrice, execErr error, _ error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.executeCall(ctx, tx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	es, res := call.es, call.result
 
 	result := &rpctypes.CallResWithGasPrice{
 		Data:      res.ReturnData,
 		CoinsUsed: res.CoinsUsed(),
-		Logs:      es.Logs[txnHash],
-		DebugLogs: es.DebugLogs[txnHash],
+		Logs:      es.Logs[call.txnHash],
+		DebugLogs: es.DebugLogs[call.txnHash],
 	}
 
 	if res.Failed() {
 		result.Error = res.GetError().Error()
-		return result, nil
+		return result, res.GetError(), nil
 	}
 
-	esOld, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
-		Block:          block,
-		ConfigAccessor: config.GetStubAccessor(),
-		Mode:           execution.ModeReadOnly,
-	})
-	if err != nil {
-		return nil, err
-	}
-	stateOverrides, err := calculateStateChange(es, esOld, overrides)
+	stateOverrides, err := call.stateChange(tx, overrides)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	execOutTransactions := es.OutTransactions[txnHash]
+	execOutTransactions := es.OutTransactions[call.txnHash]
 	outTransactions, err := api.handleOutTransactions(
 		ctx,
 		execOutTransactions,
-		mainBlockHash,
-		childBlocks,
+		call.mainBlockHash,
+		call.childBlocks,
 		&stateOverrides,
 	)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	result.OutTransactions = outTransactions
 	result.StateOverrides = stateOverrides
 	result.BaseFee = es.BaseFee
-	return result, nil
+	return result, nil, nil
 }
@@ -0,0 +1,198 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"golang.org/x/sync/errgroup"
+)
+
+var errClusterHashReference = rawapitypes.NewInvalidArgumentError(
+	errors.New("block hash can't be resolved in every shard"))
+
+// localShardApiCluster queries the shards with the API of the node, so the shards it doesn't serve itself
+// are queried over the network.
+type localShardApiCluster struct {
+	nodeApi NodeApi
+}
+
+var _ shardApiCluster = (*localShardApiCluster)(nil)
+
+func newLocalShardApiCluster() *localShardApiCluster {
+	return &localShardApiCluster{}
+}
+
+func (api *localShardApiCluster) shardId() types.ShardId {
+	return types.MainShardId
+}
+
+func (api *localShardApiCluster) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolCluster](),
+		reflect.TypeFor[shardApiCluster](),
+		api,
+		types.MainShardId,
+		apiNameCluster,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiCluster) setNodeApi(nodeApi NodeApi) {
+	api.nodeApi = nodeApi
+}
+
+// forEachShard calls f for all the shards of the network concurrently, the results are sorted by shard ID.
+// It fails if any of the calls fails. The request is charged to the rate limits as one per shard.
+func forEachShard[T any](
+	ctx context.Context, nodeApi NodeApi, f func(ctx context.Context, shardId types.ShardId) (T, error),
+) ([]T, error) {
+	shardIds, err := nodeApi.GetShardIdList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The list of the main shard contains the other shards only.
+	shardIds = append([]types.ShardId{types.MainShardId}, shardIds...)
+	slices.Sort(shardIds)
+	shardIds = slices.Compact(shardIds)
+	if err := chargeRequests(ctx, len(shardIds)-1); err != nil {
+		return nil, err
+	}
+
+	results := make([]T, len(shardIds))
+	group, ctx := errgroup.WithContext(ctx)
+	for i, shardId := range shardIds {
+		group.Go(func() error {
+			var err error
+			results[i], err = f(ctx, shardId)
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (api *localShardApiCluster) GetFamilyTransactionCount(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.FamilyTransactionCount, error) {
+	if blockReference.Type() == rawapitypes.HashBlockReference {
+		return nil, errClusterHashReference
+	}
+
+	counts, err := forEachShard(ctx, api.nodeApi, func(
+		ctx context.Context, shardId types.ShardId,
+	) (rawapitypes.ShardTransactionCount, error) {
+		shardAddress := address
+		copy(shardAddress[:types.ShardIdSize], shardId.Bytes())
+		count, err := api.getTransactionCount(ctx, shardAddress, blockReference)
+		return rawapitypes.ShardTransactionCount{ShardId: shardId, Address: shardAddress, Count: count}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &rawapitypes.FamilyTransactionCount{Shards: counts}
+	for _, count := range counts {
+		result.Total += count.Count
+	}
+	return result, nil
+}
+
+// getTransactionCount reads the seqno of the account with the read-only API, so the shards the node doesn't
+// validate are counted too. The pooled transactions are counted for the pending block only, and only
+// for the shards the node has the read-write API of; the latest block is counted for the others.
+func (api *localShardApiCluster) getTransactionCount(
+	ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
+) (uint64, error) {
+	if blockReference.Type() == rawapitypes.NamedBlockIdentifierReference &&
+		blockReference.NamedBlockIdentifier() == rawapitypes.PendingBlock {
+		count, err := api.nodeApi.GetTransactionCount(ctx, address, blockReference)
+		if !errors.Is(err, rawapitypes.ErrShardNotFound) {
+			return count, err
+		}
+		blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	}
+	meta, err := api.nodeApi.GetAccountMeta(ctx, address, blockReference)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(meta.ExtSeqno), nil
+}
+
+func (api *localShardApiCluster) GetLatestBlocks(ctx context.Context) ([]*rawapitypes.ShardBlockHead, error) {
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	return forEachShard(ctx, api.nodeApi, func(
+		ctx context.Context, shardId types.ShardId,
+	) (*rawapitypes.ShardBlockHead, error) {
+		header, err := api.nodeApi.GetBlockHeader(ctx, shardId, latest)
+		if err != nil {
+			return nil, err
+		}
+		var block types.Block
+		if err := block.UnmarshalSSZ(header); err != nil {
+			return nil, fmt.Errorf("failed to decode the latest block of shard %d: %w", shardId, err)
+		}
+		return &rawapitypes.ShardBlockHead{
+			ShardId:     shardId,
+			BlockNumber: block.Id,
+			BlockHash:   block.Hash(shardId),
+		}, nil
+	})
+}
+
+func (api *localShardApiCluster) GetGasPriceStats(ctx context.Context) (*rawapitypes.GasPriceStats, error) {
+	prices, err := forEachShard(ctx, api.nodeApi, func(
+		ctx context.Context, shardId types.ShardId,
+	) (rawapitypes.ShardGasPrice, error) {
+		price, err := api.nodeApi.GasPrice(ctx, shardId)
+		return rawapitypes.ShardGasPrice{ShardId: shardId, GasPrice: price}, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newGasPriceStats(prices), nil
+}
+
+// newGasPriceStats computes the statistics of the gas prices, the median of an even number of them
+// is the mean of the two middle ones.
+func newGasPriceStats(prices []rawapitypes.ShardGasPrice) *rawapitypes.GasPriceStats {
+	stats := &rawapitypes.GasPriceStats{Shards: prices}
+	if len(prices) == 0 {
+		return stats
+	}
+
+	sorted := make([]types.Value, len(prices))
+	sum := types.NewZeroValue()
+	for i, price := range prices {
+		sorted[i] = price.GasPrice
+		sum = sum.Add(price.GasPrice)
+	}
+	slices.SortFunc(sorted, types.Value.Cmp)
+
+	n := len(sorted)
+	stats.Min = sorted[0]
+	stats.Max = sorted[n-1]
+	stats.Mean = sum.Div64(uint64(n))
+	stats.Median = sorted[n/2]
+	if n%2 == 0 {
+		stats.Median = sorted[n/2-1].Add(sorted[n/2]).Div64(2)
+	}
+	return stats
+}
@@ -0,0 +1,99 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+// clusterNodeApi serves the shards 0-2 with the seqno of an account equal to its shard,
+// the node has the read-write API of the main shard only, whose pool holds one more transaction.
+type clusterNodeApi struct {
+	NodeApi
+}
+
+func (clusterNodeApi) GetShardIdList(context.Context) ([]types.ShardId, error) {
+	return []types.ShardId{2, 1}, nil
+}
+
+func (clusterNodeApi) GetBlockHeader(
+	_ context.Context, shardId types.ShardId, _ rawapitypes.BlockReference,
+) (sszx.SSZEncodedData, error) {
+	block := types.Block{BlockData: types.BlockData{Id: types.BlockNumber(10 + shardId)}}
+	return block.MarshalSSZ()
+}
+
+func (clusterNodeApi) GetAccountMeta(
+	_ context.Context, address types.Address, _ rawapitypes.BlockReference,
+) (*rawapitypes.AccountMeta, error) {
+	return &rawapitypes.AccountMeta{Exists: true, ExtSeqno: types.Seqno(address.ShardId())}, nil
+}
+
+func (clusterNodeApi) GetTransactionCount(
+	_ context.Context, address types.Address, _ rawapitypes.BlockReference,
+) (uint64, error) {
+	if address.ShardId() != types.MainShardId {
+		return 0, makeShardNotFoundError("GetTransactionCount", address.ShardId())
+	}
+	return 1, nil
+}
+
+func newTestClusterApi() *localShardApiCluster {
+	api := newLocalShardApiCluster()
+	api.setNodeApi(clusterNodeApi{})
+	return api
+}
+
+func TestClusterLatestBlocks(t *testing.T) {
+	t.Parallel()
+
+	heads, err := newTestClusterApi().GetLatestBlocks(t.Context())
+	require.NoError(t, err)
+	require.Len(t, heads, 3)
+	for i, head := range heads {
+		require.Equal(t, types.ShardId(i), head.ShardId)
+		require.Equal(t, types.BlockNumber(10+i), head.BlockNumber)
+	}
+}
+
+func TestClusterFamilyTransactionCount(t *testing.T) {
+	t.Parallel()
+
+	api := newTestClusterApi()
+	address := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+
+	count, err := api.GetFamilyTransactionCount(
+		t.Context(), address, rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0+1+2), count.Total)
+
+	// The pool is counted for the shards with the read-write API, the latest block for the others.
+	count, err = api.GetFamilyTransactionCount(
+		t.Context(), address, rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.PendingBlock))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1+1+2), count.Total)
+}
+
+func TestClusterRateLimits(t *testing.T) {
+	t.Parallel()
+
+	api := newTestClusterApi()
+	// The request is charged for every shard it is fanned out to.
+	handler := NewRateLimitInterceptor(RateLimits{"GetLatestBlocks": {PeerRequestsPerSecond: 0.001, PeerBurst: 5}})(
+		t.Context(), makeProtocolId(types.MainShardId, apiNameCluster, "GetLatestBlocks"),
+		func(ctx context.Context, _ []byte) ([]byte, error) {
+			_, err := api.GetLatestBlocks(ctx)
+			return nil, err
+		})
+	ctx := network.WithRequestPeer(t.Context(), "peer-a")
+
+	_, err := handler(ctx, nil)
+	require.NoError(t, err)
+	_, err = handler(ctx, nil)
+	require.ErrorIs(t, err, rawapitypes.ErrRateLimited)
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// ConsensusApiConfig configures the consensus API of the node.
+type ConsensusApiConfig struct {
+	// Auth authenticates the operators, the API is not served if no one can be authenticated.
+	Auth RequestAuth `yaml:"auth,omitempty"`
+}
+
+// ShardConsensus is the consensus a shard commits its blocks with.
+type ShardConsensus interface {
+	ConsensusState(ctx context.Context) (rawapitypes.ConsensusState, error)
+	RoundTimeouts() []rawapitypes.RoundTimeout
+}
+
+type localShardApiConsensus struct {
+	cfg         ConsensusApiConfig
+	consensuses map[types.ShardId]ShardConsensus
+}
+
+var _ shardApiConsensus = (*localShardApiConsensus)(nil)
+
+func newLocalShardApiConsensus(
+	cfg ConsensusApiConfig, consensuses map[types.ShardId]ShardConsensus,
+) *localShardApiConsensus {
+	return &localShardApiConsensus{
+		cfg:         cfg,
+		consensuses: consensuses,
+	}
+}
+
+func (api *localShardApiConsensus) shardId() types.ShardId {
+	return types.MainShardId
+}
+
+func (api *localShardApiConsensus) setNodeApi(_ NodeApi) {}
+
+// setAsP2pRequestHandlersIfAllowed serves the API only if the operators can be authenticated,
+// the same way as the admin API does.
+func (api *localShardApiConsensus) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	if !api.cfg.Auth.Enabled() {
+		logger.Warn().Msg("Consensus API is not served since neither a shared secret nor allowed peers are configured")
+		return nil
+	}
+	cfg.Interceptors = append([]RequestInterceptor{NewAuthInterceptor(api.cfg.Auth)}, cfg.Interceptors...)
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolConsensus](),
+		reflect.TypeFor[shardApiConsensus](),
+		api,
+		types.MainShardId,
+		apiNameConsensus,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiConsensus) getConsensus(shardId types.ShardId) (ShardConsensus, error) {
+	consensus, ok := api.consensuses[shardId]
+	if !ok {
+		return nil, fmt.Errorf("%w: shard %d is not validated by the node", rawapitypes.ErrNotFound, shardId)
+	}
+	return consensus, nil
+}
+
+func (api *localShardApiConsensus) GetConsensusState(
+	ctx context.Context, shardId types.ShardId,
+) (*rawapitypes.ConsensusState, error) {
+	consensus, err := api.getConsensus(shardId)
+	if err != nil {
+		return nil, err
+	}
+	state, err := consensus.ConsensusState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (api *localShardApiConsensus) GetRoundTimeouts(
+	_ context.Context, shardId types.ShardId,
+) ([]rawapitypes.RoundTimeout, error) {
+	consensus, err := api.getConsensus(shardId)
+	if err != nil {
+		return nil, err
+	}
+	return consensus.RoundTimeouts(), nil
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	defaultDbListKeys = 100
+	maxDbListKeys     = 1000
+)
+
+var (
+	errDbKeyNotFound = fmt.Errorf("key %w", rawapitypes.ErrNotFound)
+	errDbEmptyTable  = rawapitypes.NewInvalidArgumentError(errors.New("table name is empty"))
+)
+
+// DbApiConfig configures the database API of the node.
+type DbApiConfig struct {
+	// Auth authenticates the operators, the API is not served if no one can be authenticated.
+	Auth RequestAuth `yaml:"auth,omitempty"`
+}
+
+type localShardApiDb struct {
+	shard types.ShardId
+	db    db.ReadOnlyDB
+	cfg   DbApiConfig
+}
+
+var _ shardApiDb = (*localShardApiDb)(nil)
+
+func newLocalShardApiDb(shardId types.ShardId, db db.ReadOnlyDB, cfg DbApiConfig) *localShardApiDb {
+	return &localShardApiDb{
+		shard: shardId,
+		db:    db,
+		cfg:   cfg,
+	}
+}
+
+func (api *localShardApiDb) shardId() types.ShardId {
+	return api.shard
+}
+
+func (api *localShardApiDb) setNodeApi(_ NodeApi) {}
+
+// setAsP2pRequestHandlersIfAllowed serves the API only if the operators can be authenticated,
+// the same way as the admin API does.
+func (api *localShardApiDb) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	if !api.cfg.Auth.Enabled() {
+		logger.Warn().Msg("DB API is not served since neither a shared secret nor allowed peers are configured")
+		return nil
+	}
+	cfg.Interceptors = append([]RequestInterceptor{NewAuthInterceptor(api.cfg.Auth)}, cfg.Interceptors...)
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolDb](),
+		reflect.TypeFor[shardApiDb](),
+		api,
+		api.shard,
+		apiNameDb,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiDb) GetRaw(ctx context.Context, table string, key []byte) ([]byte, error) {
+	if table == "" {
+		return nil, errDbEmptyTable
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	value, err := tx.Get(db.TableName(table), key)
+	if errors.Is(err, db.ErrKeyNotFound) {
+		return nil, errDbKeyNotFound
+	}
+	return value, err
+}
+
+// ListKeys returns the keys of the table starting with the prefix and following after,
+// the default number of them if limit is zero.
+func (api *localShardApiDb) ListKeys(
+	ctx context.Context,
+	table string,
+	prefix []byte,
+	after []byte,
+	limit uint64,
+) ([][]byte, error) {
+	if table == "" {
+		return nil, errDbEmptyTable
+	}
+	if limit == 0 {
+		limit = defaultDbListKeys
+	}
+	limit = min(limit, maxDbListKeys)
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	from := prefix
+	if bytes.Compare(after, from) > 0 {
+		from = after
+	}
+	iter, err := tx.Range(db.TableName(table), from, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	keys := make([][]byte, 0)
+	for uint64(len(keys)) < limit && iter.HasNext() {
+		key, _, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.HasPrefix(key, prefix) {
+			break
+		}
+		if len(after) != 0 && bytes.Compare(key, after) <= 0 {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
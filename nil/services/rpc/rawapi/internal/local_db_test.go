@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListKeys(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	const table = "test"
+	// The keys of the prefix 1 outnumber the limit of a page, the ones of the prefixes 0 and 2 surround them.
+	key := func(prefix byte, i uint16) []byte {
+		return binary.BigEndian.AppendUint16([]byte{prefix}, i)
+	}
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	require.NoError(t, tx.Put(table, key(0, 0), []byte{1}))
+	for i := range uint16(maxDbListKeys + 5) {
+		require.NoError(t, tx.Put(table, key(1, i), []byte{1}))
+	}
+	require.NoError(t, tx.Put(table, key(2, 0), []byte{1}))
+	require.NoError(t, tx.Commit())
+
+	api := newLocalShardApiDb(types.MainShardId, database, DbApiConfig{})
+
+	t.Run("Limit", func(t *testing.T) {
+		t.Parallel()
+
+		keys, err := api.ListKeys(t.Context(), table, []byte{1}, nil, 0)
+		require.NoError(t, err)
+		require.Len(t, keys, defaultDbListKeys)
+		require.Equal(t, key(1, 0), keys[0])
+
+		keys, err = api.ListKeys(t.Context(), table, []byte{1}, nil, 2*maxDbListKeys)
+		require.NoError(t, err)
+		require.Len(t, keys, maxDbListKeys)
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		t.Parallel()
+
+		// All the keys of the prefix are listed page by page, each one starting after the last key of the previous.
+		var listed [][]byte
+		var after []byte
+		for {
+			keys, err := api.ListKeys(t.Context(), table, []byte{1}, after, maxDbListKeys)
+			require.NoError(t, err)
+			if len(keys) == 0 {
+				break
+			}
+			listed = append(listed, keys...)
+			after = keys[len(keys)-1]
+		}
+		require.Len(t, listed, maxDbListKeys+5)
+		for i, k := range listed {
+			require.Equal(t, key(1, uint16(i)), k)
+		}
+	})
+
+	t.Run("AfterOutsidePrefix", func(t *testing.T) {
+		t.Parallel()
+
+		// The cursor preceding the prefix is the same as none, the one following it leaves nothing to list.
+		keys, err := api.ListKeys(t.Context(), table, []byte{1}, key(0, 0), 1)
+		require.NoError(t, err)
+		require.Equal(t, [][]byte{key(1, 0)}, keys)
+
+		keys, err = api.ListKeys(t.Context(), table, []byte{1}, key(2, 0), 1)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+
+	t.Run("EmptyTable", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := api.ListKeys(t.Context(), "", nil, nil, 1)
+		require.ErrorIs(t, err, errDbEmptyTable)
+	})
+}
@@ -0,0 +1,295 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+var errGenesisTransactionTrace = errors.New("transactions of the genesis block can't be traced")
+
+type localShardApiDebug struct {
+	roApi *localShardApiRo
+}
+
+var _ shardApiDebug = (*localShardApiDebug)(nil)
+
+func newLocalShardApiDebug(roApi *localShardApiRo) *localShardApiDebug {
+	return &localShardApiDebug{
+		roApi: roApi,
+	}
+}
+
+func (api *localShardApiDebug) shardId() types.ShardId {
+	return api.roApi.shardId()
+}
+
+func (api *localShardApiDebug) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolDebug](),
+		reflect.TypeFor[shardApiDebug](),
+		api,
+		api.roApi.shardId(),
+		apiNameDebug,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiDebug) setNodeApi(nodeApi NodeApi) {
+	api.roApi.nodeApi = nodeApi
+}
+
+// TraceTransaction re-executes the transaction included in a block of the shard and returns its trace.
+func (api *localShardApiDebug) TraceTransaction(
+	ctx context.Context,
+	hash common.Hash,
+) (*rawapitypes.ExecutionTrace, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tracer := newExecutionTracer()
+	_, res, err := api.replayTransaction(ctx, tx, hash, tracer.hooks())
+	if err != nil {
+		return nil, err
+	}
+	return tracer.trace(res), nil
+}
+
+// TraceCall executes the call the same way as Call and returns its trace.
+func (api *localShardApiDebug) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.ExecutionTrace, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tracer := newExecutionTracer()
+	call, err := api.roApi.executeCall(
+		ctx, tx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides, tracer.hooks())
+	if err != nil {
+		return nil, err
+	}
+	return tracer.trace(call.result), nil
+}
+
+// ReplayTransaction re-executes the transaction included in a block of the shard on top of the state preceding it
+// and compares the resulting receipt with the stored one.
+func (api *localShardApiDebug) ReplayTransaction(
+	ctx context.Context,
+	hash common.Hash,
+	options rawapitypes.ReplayOptions,
+) (*rawapitypes.ReplayResult, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	tracer := newExecutionTracer()
+	hooks := tracer.hooks()
+	if options.DisableSteps {
+		hooks.OnOpcode = nil
+	}
+	if options.DisableCallTree {
+		hooks.OnEnter, hooks.OnExit = nil, nil
+	}
+	es, res, err := api.replayTransaction(ctx, tx, hash, hooks)
+	if err != nil {
+		return nil, err
+	}
+	replayed := es.Receipts[len(es.Receipts)-1]
+
+	block, index, err := api.roApi.getBlockAndInTransactionIndexByTransactionHash(tx, api.shardId(), hash)
+	if err != nil {
+		return nil, err
+	}
+	receiptsReader := execution.NewDbReceiptTrieReader(tx, api.shardId())
+	receiptsReader.SetRootHash(block.ReceiptsRoot)
+	stored, err := receiptsReader.Fetch(index.TransactionIndex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read receipt of transaction %s: %w", hash, err)
+	}
+
+	return &rawapitypes.ReplayResult{
+		ReturnData: res.ReturnData,
+		Logs:       replayed.Logs,
+		Trace:      tracer.trace(res),
+		Mismatches: receiptMismatches(replayed, stored),
+	}, nil
+}
+
+// receiptMismatches returns the fields the receipts differ in. The outgoing transactions are numbered
+// when the block is finalized, so their indexes are not compared.
+func receiptMismatches(replayed, stored *types.Receipt) []string {
+	mismatches := make([]string, 0)
+	if replayed.Success != stored.Success {
+		mismatches = append(mismatches, "success")
+	}
+	if replayed.Status != stored.Status {
+		mismatches = append(mismatches, "status")
+	}
+	if replayed.GasUsed != stored.GasUsed {
+		mismatches = append(mismatches, "gasUsed")
+	}
+	if !replayed.Forwarded.Eq(stored.Forwarded) {
+		mismatches = append(mismatches, "forwarded")
+	}
+	if replayed.FailedPc != stored.FailedPc {
+		mismatches = append(mismatches, "failedPc")
+	}
+	if !slices.EqualFunc(replayed.Logs, stored.Logs, logsEqual) {
+		mismatches = append(mismatches, "logs")
+	}
+	return mismatches
+}
+
+func logsEqual(a, b *types.Log) bool {
+	return a.Address == b.Address && slices.Equal(a.Topics, b.Topics) && bytes.Equal(a.Data, b.Data)
+}
+
+// replayBlockUntil executes the transactions of the block preceding the requested one on top of the state
+// of the previous block. It returns the resulting state and the requested transaction, which is not executed yet.
+func (api *localShardApiDebug) replayBlockUntil(
+	ctx context.Context,
+	tx db.RoTx,
+	hash common.Hash,
+) (*execution.ExecutionState, *types.Transaction, error) {
+	shardId := api.shardId()
+	block, index, err := api.roApi.getBlockAndInTransactionIndexByTransactionHash(tx, shardId, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if block.Id == 0 {
+		return nil, nil, errGenesisTransactionTrace
+	}
+
+	prevBlock, err := db.ReadBlock(tx, shardId, block.PrevBlock)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read block %s: %w", block.PrevBlock, err)
+	}
+
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, prevBlock, shardId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		Block:          prevBlock,
+		ConfigAccessor: configAccessor,
+		Mode:           execution.ModeReadOnly,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	es.BaseFee = block.BaseFee
+	es.MainShardHash = block.MainShardHash
+	es.PatchLevel = block.PatchLevel
+	es.RollbackCounter = block.RollbackCounter
+
+	txnsReader := execution.NewDbTransactionTrieReader(tx, shardId)
+	txnsReader.SetRootHash(block.InTransactionsRoot)
+	entries, err := txnsReader.Entries()
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+
+	for _, entry := range entries {
+		if entry.Key == index.TransactionIndex {
+			return es, entry.Val, nil
+		}
+		if res := replayInTransaction(ctx, es, entry.Val, nil); res.FatalError != nil {
+			return nil, nil, res.FatalError
+		}
+	}
+	return nil, nil, fmt.Errorf("transaction %s is not found in block %s", hash, index.BlockHash)
+}
+
+// replayTransaction executes the transaction included in a block of the shard with the hooks.
+// Only the execution of the transaction is traced, the validation of an external transaction is not.
+func (api *localShardApiDebug) replayTransaction(
+	ctx context.Context,
+	tx db.RoTx,
+	hash common.Hash,
+	hooks *tracing.Hooks,
+) (*execution.ExecutionState, *execution.ExecutionResult, error) {
+	es, txn, err := api.replayBlockUntil(ctx, tx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	res := replayInTransaction(ctx, es, txn, hooks)
+	if res.FatalError != nil {
+		return nil, nil, res.FatalError
+	}
+	return es, res, nil
+}
+
+// replayInTransaction executes the transaction the same way as the block generator does.
+func replayInTransaction(
+	ctx context.Context,
+	es *execution.ExecutionState,
+	txn *types.Transaction,
+	hooks *tracing.Hooks,
+) *execution.ExecutionResult {
+	es.AddInTransaction(txn)
+
+	var res *execution.ExecutionResult
+	if txn.IsInternal() {
+		if err := es.AcceptInternalTransaction(txn); err != nil {
+			res = execution.NewExecutionResult().SetError(types.KeepOrWrapError(types.ErrorValidation, err))
+		} else {
+			es.EvmTracingHooks = hooks
+			res = es.HandleTransaction(ctx, txn, execution.NewTransactionPayer(txn, es))
+		}
+	} else {
+		verifyResult := execution.ValidateExternalTransaction(es, txn)
+		if verifyResult.Failed() {
+			res = verifyResult
+		} else {
+			acc, err := es.GetAccount(txn.To)
+			if err != nil {
+				return execution.NewExecutionResult().SetFatal(err)
+			}
+			es.EvmTracingHooks = hooks
+			res = es.HandleTransaction(ctx, txn, execution.NewAccountPayer(acc, txn))
+			res.AddUsed(verifyResult.GasUsed)
+		}
+	}
+	es.EvmTracingHooks = nil
+
+	if res.FatalError == nil {
+		es.AddReceipt(res)
+	}
+	return res
+}
@@ -0,0 +1,58 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptMismatches(t *testing.T) {
+	t.Parallel()
+
+	address := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+	// newReceipt returns the receipt the changes are compared against.
+	newReceipt := func() *types.Receipt {
+		return &types.Receipt{
+			Success:   true,
+			GasUsed:   21_000,
+			Forwarded: types.NewValueFromUint64(100),
+			Logs: []*types.Log{{
+				Address: address,
+				Topics:  []common.Hash{common.HexToHash("0x01")},
+				Data:    []byte{1, 2},
+			}},
+			OutTxnIndex: 1,
+			OutTxnNum:   2,
+		}
+	}
+
+	for _, test := range []struct {
+		name       string
+		change     func(receipt *types.Receipt)
+		mismatches []string
+	}{
+		{"Equal", func(*types.Receipt) {}, []string{}},
+		// The outgoing transactions are numbered by the block.
+		{"OutTxnIndex", func(r *types.Receipt) { r.OutTxnIndex = 5 }, []string{}},
+		{"Failed", func(r *types.Receipt) {
+			r.Success = false
+			r.Status = types.ErrorExecution
+			r.FailedPc = 10
+		}, []string{"success", "status", "failedPc"}},
+		{"GasUsed", func(r *types.Receipt) { r.GasUsed++ }, []string{"gasUsed"}},
+		{"Forwarded", func(r *types.Receipt) { r.Forwarded = types.NewValueFromUint64(101) }, []string{"forwarded"}},
+		{"LogData", func(r *types.Receipt) { r.Logs[0].Data = []byte{1, 3} }, []string{"logs"}},
+		{"LogTopics", func(r *types.Receipt) { r.Logs[0].Topics = nil }, []string{"logs"}},
+		{"NoLogs", func(r *types.Receipt) { r.Logs = nil }, []string{"logs"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+
+			replayed := newReceipt()
+			test.change(replayed)
+			require.Equal(t, test.mismatches, receiptMismatches(replayed, newReceipt()))
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+// PrepareDeploy computes the address of the contract and simulates its external deployment at the latest block,
+// so that a wallet can fund the address and build the deploy transaction with the estimated fee.
+func (api *localShardApiRo) PrepareDeploy(
+	ctx context.Context, request rawapitypes.DeployRequest,
+) (*rawapitypes.DeployPreparation, error) {
+	if request.ShardId != api.shardId() {
+		return nil, fmt.Errorf("%w: deployment is not to the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+	if request.ShardId.IsMainShard() {
+		return nil, rawapitypes.NewInvalidArgumentError(errors.New("contracts can't be deployed to the main shard"))
+	}
+	if len(request.Code) == 0 {
+		return nil, rawapitypes.NewInvalidArgumentError(errors.New("code is empty"))
+	}
+
+	payload := types.BuildDeployPayload(request.Code, request.Salt)
+	preparation := &rawapitypes.DeployPreparation{
+		Address: types.CreateAddress(request.ShardId, payload),
+		Payload: payload.Bytes(),
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The latest block is resolved once, so that the account is read and the deployment is simulated
+	// at the same block even if a new one is committed meanwhile.
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	call, err := api.newCallExecution(
+		ctx, tx, rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(latest), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	block := rawapitypes.BlockHashAsBlockReference(call.block.Hash(api.shardId()))
+	mainBlock := rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(
+		call.mainBlockHash, call.childBlocks)
+
+	meta, err := api.GetAccountMeta(ctx, preparation.Address, block)
+	if err != nil {
+		return nil, err
+	}
+	// The same check as the one of the execution, see execution.ExecutionState.ContractExists.
+	preparation.Deployed = meta.Exists && (meta.CodeHash != common.EmptyHash || meta.StorageRoot != common.EmptyHash)
+	if preparation.Balance, err = api.GetBalance(ctx, preparation.Address, block); err != nil {
+		return nil, err
+	}
+	if preparation.Deployed {
+		return preparation, nil
+	}
+
+	data := hexutil.Bytes(preparation.Payload)
+	args := rpctypes.CallArgs{
+		Flags: types.NewTransactionFlags(types.TransactionFlagDeploy),
+		To:    preparation.Address,
+		Data:  &data,
+		Seqno: meta.ExtSeqno,
+	}
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	// The deployment is executed before the estimation to tell the failures of the constructor from the others.
+	if err := call.handle(ctx, args, txn, nil); err != nil {
+		return nil, err
+	}
+	if res := call.result; res.Failed() {
+		execErr := res.GetError()
+		reverted := types.GetErrorCode(execErr) == types.ErrorExecutionReverted
+		if request.ValidateConstructor {
+			if reverted {
+				return nil, rawapitypes.NewExecutionRevertedError(execErr.Error(), res.ReturnData)
+			}
+			return nil, rawapitypes.NewInvalidArgumentError(fmt.Errorf("deployment fails: %w", execErr))
+		}
+		preparation.SimulationError = execErr.Error()
+		if reverted {
+			preparation.RevertData = res.ReturnData
+		}
+		return preparation, nil
+	}
+
+	if preparation.Fee, err = api.EstimateFee(ctx, args, mainBlock, nil, nil); err != nil {
+		return nil, err
+	}
+	return preparation, nil
+}
@@ -29,6 +29,7 @@ func (api *localShardApiDev) setNodeApi(_ NodeApi) {}
 func (api *localShardApiDev) setAsP2pRequestHandlersIfAllowed(
 	ctx context.Context,
 	networkManager network.Manager,
+	cfg RequestHandlersConfig,
 	logger logging.Logger,
 ) error {
 	return setRawApiRequestHandlers(
@@ -39,6 +40,7 @@ func (api *localShardApiDev) setAsP2pRequestHandlersIfAllowed(
 		api.shard,
 		apiNameRo,
 		networkManager,
+		cfg,
 		logger)
 }
 
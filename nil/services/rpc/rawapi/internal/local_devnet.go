@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	cerrors "github.com/NilFoundation/nil/nil/internal/collate/errors"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// maxMinedBlocks limits the number of blocks mined in every shard by a single request.
+const maxMinedBlocks = 1000
+
+// ShardChainControl controls the collation of the blocks of a shard of a single-node devnet, e.g., its validator.
+type ShardChainControl interface {
+	CollateBlock(ctx context.Context) (types.BlockNumber, error)
+	NextBlockTime(ctx context.Context) (uint64, error)
+	// SetNextBlockTime sets the time of the next block instead if the time is before it and returns the time set.
+	SetNextBlockTime(ctx context.Context, time uint64) (uint64, error)
+	SnapshotChain(ctx context.Context) (rawapitypes.ChainSnapshot, error)
+	ResetChain(ctx context.Context, snapshot rawapitypes.ChainSnapshot) error
+}
+
+// DevnetApiConfig configures the devnet API of the node.
+type DevnetApiConfig struct {
+	// Auth authenticates the operators, the API is not served if no one can be authenticated.
+	Auth RequestAuth `yaml:"auth,omitempty"`
+}
+
+type chainSnapshot struct {
+	id     uint64
+	shards map[types.ShardId]rawapitypes.ChainSnapshot
+}
+
+// localShardApiDevnet controls the chains of the shards of the node. The snapshots are kept in memory,
+// so they are lost on restart.
+type localShardApiDevnet struct {
+	cfg      DevnetApiConfig
+	controls map[types.ShardId]ShardChainControl
+	// shardIds are ordered by collation, the main shard last, so that its blocks include the blocks of the others.
+	shardIds []types.ShardId
+
+	// mu serializes the requests, so that the snapshots are consistent with the blocks mined.
+	mu             sync.Mutex
+	snapshots      []chainSnapshot
+	nextSnapshotId uint64
+}
+
+var _ shardApiDevnet = (*localShardApiDevnet)(nil)
+
+func newLocalShardApiDevnet(cfg DevnetApiConfig, controls map[types.ShardId]ShardChainControl) *localShardApiDevnet {
+	_, ok := controls[types.MainShardId]
+	check.PanicIfNotf(ok, "main shard is not controlled")
+
+	// The main shard is the first one by ID.
+	shardIds := slices.Sorted(maps.Keys(controls))
+	shardIds = append(shardIds[1:], types.MainShardId)
+	return &localShardApiDevnet{
+		cfg:      cfg,
+		controls: controls,
+		shardIds: shardIds,
+		// The IDs start from one, so that a zero ID is never valid.
+		nextSnapshotId: 1,
+	}
+}
+
+func (api *localShardApiDevnet) shardId() types.ShardId {
+	return types.MainShardId
+}
+
+// setAsP2pRequestHandlersIfAllowed serves the API only if the operators can be authenticated,
+// the same way as the admin API does, since it rewrites the chains.
+func (api *localShardApiDevnet) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	if !api.cfg.Auth.Enabled() {
+		logger.Warn().Msg("Devnet API is not served since neither a shared secret nor allowed peers are configured")
+		return nil
+	}
+	cfg.Interceptors = append([]RequestInterceptor{NewAuthInterceptor(api.cfg.Auth)}, cfg.Interceptors...)
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolDevnet](),
+		reflect.TypeFor[shardApiDevnet](),
+		api,
+		types.MainShardId,
+		apiNameDevnet,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiDevnet) setNodeApi(_ NodeApi) {}
+
+func (api *localShardApiDevnet) MineBlock(ctx context.Context, count uint64) (uint64, error) {
+	if count == 0 || count > maxMinedBlocks {
+		return 0, rawapitypes.NewInvalidArgumentError(
+			fmt.Errorf("count %d is out of range [1, %d]", count, maxMinedBlocks))
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	var mainBlockId types.BlockNumber
+	for range count {
+		for _, shardId := range api.shardIds {
+			blockId, err := api.controls[shardId].CollateBlock(ctx)
+			if err != nil {
+				return 0, fmt.Errorf("failed to collate block of shard %s: %w", shardId, err)
+			}
+			if shardId.IsMainShard() {
+				mainBlockId = blockId
+			}
+		}
+	}
+	return uint64(mainBlockId), nil
+}
+
+// SetNextBlockTimestamp checks the timestamp against the next blocks of all the shards before setting it,
+// so it is set either in all the shards or in none. The schedulers may collate a block in between,
+// the time of the next block is set in the shard then, and the latest time set is returned.
+func (api *localShardApiDevnet) SetNextBlockTimestamp(ctx context.Context, timestamp uint64) (uint64, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	for _, shardId := range api.shardIds {
+		next, err := api.controls[shardId].NextBlockTime(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get time of next block of shard %s: %w", shardId, err)
+		}
+		if timestamp < next {
+			return 0, rawapitypes.NewInvalidArgumentError(fmt.Errorf(
+				"shard %s: %w: %d is before %d", shardId, cerrors.ErrTimeBeforeNextBlock, timestamp, next))
+		}
+	}
+
+	var latest uint64
+	for _, shardId := range api.shardIds {
+		time, err := api.controls[shardId].SetNextBlockTime(ctx, timestamp)
+		if err != nil {
+			return 0, fmt.Errorf("failed to set time of next block of shard %s: %w", shardId, err)
+		}
+		latest = max(latest, time)
+	}
+	return latest, nil
+}
+
+// SnapshotState takes the snapshots of the main shard first. The blocks collated by the schedulers in between
+// are only included in the snapshots of the other shards, so the main shard never refers to the dropped blocks.
+func (api *localShardApiDevnet) SnapshotState(ctx context.Context) (uint64, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	snapshot := chainSnapshot{
+		id:     api.nextSnapshotId,
+		shards: make(map[types.ShardId]rawapitypes.ChainSnapshot, len(api.shardIds)),
+	}
+	for _, shardId := range slices.Backward(api.shardIds) {
+		shardSnapshot, err := api.controls[shardId].SnapshotChain(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("failed to take snapshot of shard %s: %w", shardId, err)
+		}
+		snapshot.shards[shardId] = shardSnapshot
+	}
+
+	api.snapshots = append(api.snapshots, snapshot)
+	api.nextSnapshotId++
+	return snapshot.id, nil
+}
+
+func (api *localShardApiDevnet) RevertToSnapshot(ctx context.Context, id uint64) (bool, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	index := slices.IndexFunc(api.snapshots, func(snapshot chainSnapshot) bool {
+		return snapshot.id == id
+	})
+	if index < 0 {
+		return false, nil
+	}
+
+	for _, shardId := range api.shardIds {
+		if err := api.controls[shardId].ResetChain(ctx, api.snapshots[index].shards[shardId]); err != nil {
+			return false, fmt.Errorf("failed to reset chain of shard %s: %w", shardId, err)
+		}
+	}
+	api.snapshots = api.snapshots[:index]
+	return true, nil
+}
@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/jonboulle/clockwork"
+)
+
+const (
+	// defaultFaucetWindow is the period the quota of the requesters applies to if the window is not configured.
+	defaultFaucetWindow = 24 * time.Hour
+
+	// faucetFeeCredit is the fee credit of the transactions of the faucet, enough for a single transfer.
+	faucetFeeCredit = 100_000
+)
+
+var (
+	// defaultFaucetMaxAmount and defaultFaucetRequesterQuota are the limits used if they are not configured,
+	// so that a faucet is never drained by a single requester.
+	defaultFaucetMaxAmount      = types.NewValueFromUint64(1_000_000_000_000_000_000)
+	defaultFaucetRequesterQuota = defaultFaucetMaxAmount.Mul64(10)
+
+	errFaucetEmptyAddress = rawapitypes.NewInvalidArgumentError(errors.New("address is empty"))
+	errFaucetZeroAmount   = rawapitypes.NewInvalidArgumentError(errors.New("amount is zero"))
+)
+
+// FaucetApiConfig limits the funds the faucet API of the node sends.
+type FaucetApiConfig struct {
+	// MaxAmount limits the amount of a single request, the default one is used if it is zero.
+	MaxAmount types.Value `yaml:"maxAmount,omitempty"`
+	// RequesterQuota limits the total amount sent to the requests of a requester within the window,
+	// the default one is used if it is zero. The requester is the signer of the request, see GetRequestSigner,
+	// or the peer sending it, so that the quota doesn't depend on the addresses the funds are sent to.
+	RequesterQuota types.Value `yaml:"requesterQuota,omitempty"`
+	// Window is the period the quota of the requesters applies to, the default one is used if it is zero.
+	Window time.Duration `yaml:"window,omitempty"`
+}
+
+// localShardApiFaucet sends the transactions of the faucet to the pool of its shard with the API of the node.
+type localShardApiFaucet struct {
+	nodeApi NodeApi
+	cfg     FaucetApiConfig
+	clock   clockwork.Clock
+
+	// mu guards the quota and the seqno, it is never held while calling the node API.
+	mu sync.Mutex
+	// granted are the amounts sent or being sent to the requesters since the window started.
+	granted     map[string]types.Value
+	windowStart time.Time
+	// nextSeqno is the seqno of the next transaction of the faucet, so that the concurrent requests get
+	// consecutive seqnos before the previous transactions reach the pool. It is zero if it is not known.
+	nextSeqno types.Seqno
+}
+
+var _ shardApiFaucet = (*localShardApiFaucet)(nil)
+
+func newLocalShardApiFaucet(cfg FaucetApiConfig) *localShardApiFaucet {
+	if cfg.MaxAmount.IsZero() {
+		cfg.MaxAmount = defaultFaucetMaxAmount
+	}
+	if cfg.RequesterQuota.IsZero() {
+		cfg.RequesterQuota = defaultFaucetRequesterQuota
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = defaultFaucetWindow
+	}
+	return &localShardApiFaucet{
+		cfg:     cfg,
+		clock:   clockwork.NewRealClock(),
+		granted: make(map[string]types.Value),
+	}
+}
+
+func (api *localShardApiFaucet) shardId() types.ShardId {
+	return types.MainShardId
+}
+
+func (api *localShardApiFaucet) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolFaucet](),
+		reflect.TypeFor[shardApiFaucet](),
+		api,
+		types.MainShardId,
+		apiNameFaucet,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiFaucet) setNodeApi(nodeApi NodeApi) {
+	api.nodeApi = nodeApi
+}
+
+func (api *localShardApiFaucet) RequestFunds(
+	ctx context.Context, address types.Address, amount types.Value,
+) (*rawapitypes.SendTransactionResult, error) {
+	if address.IsEmpty() {
+		return nil, errFaucetEmptyAddress
+	}
+	if amount.IsZero() {
+		return nil, errFaucetZeroAmount
+	}
+	if amount.Cmp(api.cfg.MaxAmount) > 0 {
+		return nil, rawapitypes.NewInvalidArgumentError(
+			fmt.Errorf("amount %s exceeds the limit %s", amount, api.cfg.MaxAmount))
+	}
+	if _, err := api.nodeApi.ResolveShard(ctx, address); err != nil {
+		return nil, err
+	}
+
+	requester := requesterKey(ctx)
+	windowStart, err := api.reserve(requester, amount)
+	if err != nil {
+		return nil, err
+	}
+	result, err := api.sendFunds(ctx, address, amount)
+	if err != nil || !result.Verdict.Passed() || result.DiscardReason != txnpool.NotSet {
+		api.release(requester, amount, windowStart)
+	}
+	return result, err
+}
+
+// reserve counts the amount towards the quota of the requester before it is sent, so that the concurrent
+// requests can't exceed the quota. It returns the start of the window the amount is counted in.
+func (api *localShardApiFaucet) reserve(requester string, amount types.Value) (time.Time, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	now := api.clock.Now()
+	if now.Sub(api.windowStart) >= api.cfg.Window {
+		clear(api.granted)
+		api.windowStart = now
+	}
+	granted, ok := api.granted[requester]
+	if !ok {
+		granted = types.NewZeroValue()
+	}
+	total, overflow := granted.AddOverflow(amount)
+	if overflow || total.Cmp(api.cfg.RequesterQuota) > 0 {
+		return time.Time{}, rawapitypes.NewError(rawapitypes.RateLimitedErrorCode, fmt.Errorf(
+			"%w: %s is already sent to the requester out of the quota %s until %s", rawapitypes.ErrRateLimited,
+			granted, api.cfg.RequesterQuota, api.windowStart.Add(api.cfg.Window).Format(time.RFC3339)))
+	}
+	api.granted[requester] = total
+	return api.windowStart, nil
+}
+
+// release returns the amount that is not sent to the quota of the requester, unless the window is over.
+func (api *localShardApiFaucet) release(requester string, amount types.Value, windowStart time.Time) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	if !api.windowStart.Equal(windowStart) {
+		return
+	}
+	if granted, ok := api.granted[requester]; ok && granted.Cmp(amount) >= 0 {
+		api.granted[requester] = granted.Sub(amount)
+	}
+}
+
+// takeSeqno returns the seqno of the next transaction of the faucet given the next valid seqno the node knows.
+func (api *localShardApiFaucet) takeSeqno(validSeqno types.Seqno) types.Seqno {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	seqno := max(validSeqno, api.nextSeqno)
+	api.nextSeqno = seqno + 1
+	return seqno
+}
+
+// forgetSeqno makes the next transactions use the seqno the node knows, since the transaction with the seqno
+// taken may never reach the pool.
+func (api *localShardApiFaucet) forgetSeqno() {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	api.nextSeqno = 0
+}
+
+// sendFunds sends the withdrawal from the faucet with the next seqno of the faucet.
+func (api *localShardApiFaucet) sendFunds(
+	ctx context.Context, address types.Address, amount types.Value,
+) (*rawapitypes.SendTransactionResult, error) {
+	pending := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.PendingBlock)
+	validSeqno, err := api.nodeApi.GetNextValidSeqno(ctx, types.FaucetAddress, pending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seqno of the faucet: %w", err)
+	}
+
+	callData, err := contracts.NewCallData(contracts.NameFaucet, "withdrawTo", address, amount.ToBig())
+	if err != nil {
+		return nil, err
+	}
+	transaction := &types.ExternalTransaction{
+		To:           types.FaucetAddress,
+		Data:         callData,
+		Seqno:        api.takeSeqno(types.Seqno(validSeqno)),
+		Kind:         types.ExecutionTransactionKind,
+		FeeCredit:    types.GasToValue(faucetFeeCredit),
+		MaxFeePerGas: types.MaxFeePerGasDefault,
+	}
+	data, err := transaction.MarshalSSZ()
+	if err != nil {
+		api.forgetSeqno()
+		return nil, err
+	}
+	result, err := api.nodeApi.SendTransaction(ctx, types.FaucetAddress.ShardId(), data, txnpool.NoReplacement, "")
+	// The seqno of a pooled transaction is taken even if it fails in the block.
+	if err != nil || result.DiscardReason != txnpool.NotSet {
+		api.forgetSeqno()
+	}
+	return result, err
+}
+
+func (api *localShardApiFaucet) GetFaucetLimits(_ context.Context) (*rawapitypes.FaucetLimits, error) {
+	return &rawapitypes.FaucetLimits{
+		Faucet:         types.FaucetAddress,
+		MaxAmount:      api.cfg.MaxAmount,
+		RequesterQuota: api.cfg.RequesterQuota,
+		Window:         api.cfg.Window,
+	}, nil
+}
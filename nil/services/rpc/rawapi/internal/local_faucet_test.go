@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/require"
+)
+
+// faucetNodeApi is the pool of the faucet shard, the methods not used by the faucet are not implemented.
+type faucetNodeApi struct {
+	NodeApi
+
+	mu sync.Mutex
+	// validSeqno is the next valid seqno of the faucet, it is advanced by the admitted transactions
+	// unless the pool is lagging.
+	validSeqno uint64
+	lagging    bool
+	discard    txnpool.DiscardReason
+	sent       []*types.ExternalTransaction
+}
+
+func (api *faucetNodeApi) ResolveShard(_ context.Context, address types.Address) (types.ShardId, error) {
+	return address.ShardId(), nil
+}
+
+func (api *faucetNodeApi) GetNextValidSeqno(
+	context.Context, types.Address, rawapitypes.BlockReference,
+) (uint64, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	return api.validSeqno, nil
+}
+
+func (api *faucetNodeApi) SendTransaction(
+	_ context.Context, shardId types.ShardId, transaction []byte, _ txnpool.ReplacementPolicy, _ string,
+) (*rawapitypes.SendTransactionResult, error) {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	txn := new(types.ExternalTransaction)
+	if err := txn.UnmarshalSSZ(transaction); err != nil {
+		return nil, err
+	}
+	api.sent = append(api.sent, txn)
+	if api.discard == txnpool.NotSet && !api.lagging {
+		api.validSeqno = uint64(txn.Seqno) + 1
+	}
+	return &rawapitypes.SendTransactionResult{Hash: txn.Hash(), ShardId: shardId, DiscardReason: api.discard}, nil
+}
+
+func (api *faucetNodeApi) sentSeqnos() []types.Seqno {
+	api.mu.Lock()
+	defer api.mu.Unlock()
+
+	seqnos := make([]types.Seqno, len(api.sent))
+	for i, txn := range api.sent {
+		seqnos[i] = txn.Seqno
+	}
+	return seqnos
+}
+
+func newTestFaucet(t *testing.T, cfg FaucetApiConfig) (*localShardApiFaucet, *faucetNodeApi, *clockwork.FakeClock) {
+	t.Helper()
+
+	nodeApi := &faucetNodeApi{}
+	clock := clockwork.NewFakeClock()
+	faucet := newLocalShardApiFaucet(cfg)
+	faucet.clock = clock
+	faucet.setNodeApi(nodeApi)
+	return faucet, nodeApi, clock
+}
+
+func TestFaucetLimits(t *testing.T) {
+	t.Parallel()
+
+	faucet, _, _ := newTestFaucet(t, FaucetApiConfig{})
+	limits, err := faucet.GetFaucetLimits(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, types.FaucetAddress, limits.Faucet)
+	require.Equal(t, defaultFaucetMaxAmount, limits.MaxAmount)
+	require.Equal(t, defaultFaucetRequesterQuota, limits.RequesterQuota)
+	require.Equal(t, defaultFaucetWindow, limits.Window)
+}
+
+func TestFaucetRequestFunds(t *testing.T) {
+	t.Parallel()
+
+	cfg := FaucetApiConfig{
+		MaxAmount:      types.NewValueFromUint64(100),
+		RequesterQuota: types.NewValueFromUint64(150),
+	}
+	address := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+	otherAddress := types.ShardAndHexToAddress(types.BaseShardId, "0x5678")
+	peerCtx := func(peerId network.PeerID) context.Context {
+		return network.WithRequestPeer(t.Context(), peerId)
+	}
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		t.Parallel()
+
+		faucet, _, _ := newTestFaucet(t, cfg)
+		_, err := faucet.RequestFunds(t.Context(), types.EmptyAddress, types.NewValueFromUint64(1))
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+		_, err = faucet.RequestFunds(t.Context(), address, types.NewZeroValue())
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+		_, err = faucet.RequestFunds(t.Context(), address, types.NewValueFromUint64(101))
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+	})
+
+	t.Run("QuotaByPeer", func(t *testing.T) {
+		t.Parallel()
+
+		faucet, nodeApi, _ := newTestFaucet(t, cfg)
+		ctx := peerCtx("peer-a")
+		_, err := faucet.RequestFunds(ctx, address, types.NewValueFromUint64(100))
+		require.NoError(t, err)
+		// The quota is counted for the peer, not for the address the funds are sent to.
+		_, err = faucet.RequestFunds(ctx, otherAddress, types.NewValueFromUint64(60))
+		require.ErrorIs(t, err, rawapitypes.ErrRateLimited)
+		_, err = faucet.RequestFunds(ctx, otherAddress, types.NewValueFromUint64(50))
+		require.NoError(t, err)
+
+		_, err = faucet.RequestFunds(peerCtx("peer-b"), address, types.NewValueFromUint64(100))
+		require.NoError(t, err)
+		require.Equal(t, []types.Seqno{0, 1, 2}, nodeApi.sentSeqnos())
+	})
+
+	t.Run("QuotaBySigner", func(t *testing.T) {
+		t.Parallel()
+
+		faucet, _, _ := newTestFaucet(t, cfg)
+		signer := RequestSigner{Address: address}
+		// The signer is the requester regardless of the peers relaying its requests.
+		for _, peerId := range []network.PeerID{"peer-a", "peer-b"} {
+			ctx := context.WithValue(peerCtx(peerId), requestSignerKey{}, signer)
+			_, err := faucet.RequestFunds(ctx, address, types.NewValueFromUint64(100))
+			if peerId == "peer-a" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorIs(t, err, rawapitypes.ErrRateLimited)
+			}
+		}
+		_, err := faucet.RequestFunds(peerCtx("peer-a"), address, types.NewValueFromUint64(100))
+		require.NoError(t, err)
+	})
+
+	t.Run("Window", func(t *testing.T) {
+		t.Parallel()
+
+		faucet, _, clock := newTestFaucet(t, cfg)
+		ctx := peerCtx("peer-a")
+		_, err := faucet.RequestFunds(ctx, address, types.NewValueFromUint64(100))
+		require.NoError(t, err)
+		_, err = faucet.RequestFunds(ctx, address, types.NewValueFromUint64(100))
+		require.ErrorIs(t, err, rawapitypes.ErrRateLimited)
+
+		clock.Advance(defaultFaucetWindow - 1)
+		_, err = faucet.RequestFunds(ctx, address, types.NewValueFromUint64(100))
+		require.ErrorIs(t, err, rawapitypes.ErrRateLimited)
+
+		clock.Advance(1)
+		_, err = faucet.RequestFunds(ctx, address, types.NewValueFromUint64(100))
+		require.NoError(t, err)
+	})
+
+	t.Run("Discarded", func(t *testing.T) {
+		t.Parallel()
+
+		faucet, nodeApi, _ := newTestFaucet(t, cfg)
+		nodeApi.lagging = true
+		ctx := peerCtx("peer-a")
+		_, err := faucet.RequestFunds(ctx, address, types.NewValueFromUint64(100))
+		require.NoError(t, err)
+
+		// The discarded amount is not counted, and the seqno of the node is used again.
+		nodeApi.discard = txnpool.SeqnoTooLow
+		result, err := faucet.RequestFunds(ctx, address, types.NewValueFromUint64(50))
+		require.NoError(t, err)
+		require.Equal(t, txnpool.SeqnoTooLow, result.DiscardReason)
+
+		nodeApi.discard = txnpool.NotSet
+		_, err = faucet.RequestFunds(ctx, address, types.NewValueFromUint64(50))
+		require.NoError(t, err)
+		// The seqnos are consecutive while the pool is lagging, until a transaction is discarded.
+		require.Equal(t, []types.Seqno{0, 1, 0}, nodeApi.sentSeqnos())
+	})
+}
@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+const (
+	// maxFeeEstimationIterations limits the number of calls made by the binary search of the execution fee.
+	maxFeeEstimationIterations = 24
+
+	// feeEstimationErrorRatio is the denominator of the acceptable relative error of the execution fee:
+	// the search stops once the interval is narrower than 1/feeEstimationErrorRatio of its upper bound.
+	feeEstimationErrorRatio = 100
+
+	// forwardingFeeMarginPercent is added to the fees of the outgoing transactions, since these are not searched for
+	// and are executed with the state of the estimation.
+	forwardingFeeMarginPercent = 20
+)
+
+// EstimateFee finds the fee credit required for the call. The execution fee on the destination shard is found by
+// binary search, the fees of the outgoing transactions are taken from their execution with the maximal fee credit.
+// The balance of the destination account is overridden, so the estimation doesn't depend on it.
+func (api *localShardApiRo) EstimateFee(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.FeeEstimation, error) {
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	estimationOverrides := make(rpctypes.StateOverrides)
+	if overrides != nil {
+		for addr, contract := range *overrides {
+			estimationOverrides[addr] = contract
+		}
+	}
+	contract := estimationOverrides[txn.To]
+	balanceCap := rpctypes.FeeEstimationBalanceCap()
+	contract.Balance = &balanceCap
+	estimationOverrides[txn.To] = contract
+
+	execute := func(feeCredit types.Value) (*rpctypes.CallResWithGasPrice, error) {
+		args.Fee = types.NewFeePackFromFeeCredit(feeCredit)
+		return api.Call(ctx, args, mainBlockReferenceOrHashWithChildren, &estimationOverrides, blockOverrides)
+	}
+
+	args.Fee = types.NewFeePackFromFeeCredit(rpctypes.FeeEstimationFeeCreditCap())
+	res, execErr, err := api.call(ctx, args, mainBlockReferenceOrHashWithChildren, &estimationOverrides, blockOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if execErr != nil {
+		if types.GetErrorCode(execErr) == types.ErrorExecutionReverted {
+			return nil, rawapitypes.NewExecutionRevertedError(res.Error, res.Data)
+		}
+		return nil, errors.New(res.Error)
+	}
+
+	executionFee, err := searchExecutionFee(res.CoinsUsed, rpctypes.SstoreSentryGas.ToValue(res.BaseFee), execute)
+	if err != nil {
+		return nil, err
+	}
+
+	estimation := &rawapitypes.FeeEstimation{
+		ExecutionFee:    executionFee,
+		ForwardingFee:   outTransactionsFee(res.OutTransactions).Mul64(100 + forwardingFeeMarginPercent).Div64(100),
+		VerificationFee: types.NewZeroValue(),
+		BaseFee:         res.BaseFee,
+		MaxBaseFee:      maxOutTransactionsBaseFee(res.BaseFee, res.OutTransactions),
+	}
+	if !txn.IsInternal() {
+		estimation.VerificationFee = rpctypes.ExternalVerificationGas.ToValue(res.BaseFee)
+	}
+	estimation.FeeCredit = estimation.ExecutionFee.
+		Add(estimation.ForwardingFee).
+		Add(estimation.VerificationFee).
+		Add(txn.Value)
+	return estimation, nil
+}
+
+// searchExecutionFee finds the minimal fee credit the call succeeds with. The call is known to succeed with
+// the fee credit cap and can't succeed with less than coinsUsed.
+func searchExecutionFee(
+	coinsUsed, sentryFee types.Value,
+	execute func(feeCredit types.Value) (*rpctypes.CallResWithGasPrice, error),
+) (types.Value, error) {
+	succeeds := func(feeCredit types.Value) (bool, error) {
+		res, err := execute(feeCredit)
+		if err != nil {
+			return false, err
+		}
+		return res.Error == "", nil
+	}
+
+	if ok, err := succeeds(coinsUsed); err != nil {
+		return types.Value{}, err
+	} else if ok {
+		return coinsUsed, nil
+	}
+
+	lo, hi := coinsUsed, rpctypes.FeeEstimationFeeCreditCap()
+	// Most transactions only need the gas withheld by the 63/64 rule and the SSTORE sentry on top of the used gas,
+	// so try it first to narrow the range quickly.
+	if optimistic := coinsUsed.Add(sentryFee).Mul64(64).Div64(63); optimistic.Cmp(hi) < 0 {
+		ok, err := succeeds(optimistic)
+		if err != nil {
+			return types.Value{}, err
+		}
+		if ok {
+			hi = optimistic
+		} else {
+			lo = optimistic
+		}
+	}
+
+	for range maxFeeEstimationIterations {
+		if hi.Sub(lo).Cmp(hi.Div64(feeEstimationErrorRatio)) <= 0 {
+			break
+		}
+		mid := lo.Add(hi).Div64(2)
+		ok, err := succeeds(mid)
+		if err != nil {
+			return types.Value{}, err
+		}
+		if ok {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return hi, nil
+}
+
+// outTransactionsFee returns the fee of the outgoing transactions including the ones they send in turn.
+func outTransactionsFee(txns []*rpctypes.OutTransaction) types.Value {
+	fee := types.NewZeroValue()
+	for _, txn := range txns {
+		fee = fee.
+			Add(txn.CoinsUsed).
+			Add(rpctypes.SstoreSentryGas.ToValue(txn.BaseFee)).
+			Add(outTransactionsFee(txn.OutTransactions))
+	}
+	return fee
+}
+
+func maxOutTransactionsBaseFee(baseFee types.Value, txns []*rpctypes.OutTransaction) types.Value {
+	for _, txn := range txns {
+		if txn.BaseFee.Cmp(baseFee) > 0 {
+			baseFee = txn.BaseFee
+		}
+		baseFee = maxOutTransactionsBaseFee(baseFee, txn.OutTransactions)
+	}
+	return baseFee
+}
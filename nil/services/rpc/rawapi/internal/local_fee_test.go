@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchExecutionFee(t *testing.T) {
+	t.Parallel()
+
+	// executeRequiring succeeds with the fee credit not lower than the required one and counts the calls.
+	executeRequiring := func(required types.Value, calls *int) func(types.Value) (*rpctypes.CallResWithGasPrice, error) {
+		return func(feeCredit types.Value) (*rpctypes.CallResWithGasPrice, error) {
+			*calls++
+			if feeCredit.Cmp(required) < 0 {
+				return &rpctypes.CallResWithGasPrice{Error: "out of gas"}, nil
+			}
+			return &rpctypes.CallResWithGasPrice{}, nil
+		}
+	}
+
+	t.Run("CoinsUsed", func(t *testing.T) {
+		t.Parallel()
+
+		coinsUsed := types.NewValueFromUint64(1000)
+		var calls int
+		fee, err := searchExecutionFee(coinsUsed, types.NewValueFromUint64(10), executeRequiring(coinsUsed, &calls))
+		require.NoError(t, err)
+		require.Equal(t, coinsUsed, fee)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("Optimistic", func(t *testing.T) {
+		t.Parallel()
+
+		// The fee credit required is covered by the gas withheld by the 63/64 rule and the SSTORE sentry.
+		required := types.NewValueFromUint64(1_010_000)
+		var calls int
+		fee, err := searchExecutionFee(
+			types.NewValueFromUint64(1_000_000), types.NewValueFromUint64(10_000), executeRequiring(required, &calls))
+		require.NoError(t, err)
+		requireFeeWithinError(t, required, fee)
+		require.LessOrEqual(t, calls, 2+maxFeeEstimationIterations)
+	})
+
+	t.Run("Search", func(t *testing.T) {
+		t.Parallel()
+
+		// The fee credit required is far above the optimistic guess, so it's found by the binary search.
+		required := rpctypes.FeeEstimationFeeCreditCap().Div64(3)
+		var calls int
+		fee, err := searchExecutionFee(
+			types.NewValueFromUint64(1000), types.NewValueFromUint64(10), executeRequiring(required, &calls))
+		require.NoError(t, err)
+		requireFeeWithinError(t, required, fee)
+		require.LessOrEqual(t, calls, 2+maxFeeEstimationIterations)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		errExecution := errors.New("execution failed")
+		_, err := searchExecutionFee(
+			types.NewValueFromUint64(1000), types.NewValueFromUint64(10),
+			func(types.Value) (*rpctypes.CallResWithGasPrice, error) {
+				return nil, errExecution
+			})
+		require.ErrorIs(t, err, errExecution)
+	})
+}
+
+// requireFeeWithinError checks that the fee is enough and exceeds the required one by the acceptable error at most.
+func requireFeeWithinError(t *testing.T, required, fee types.Value) {
+	t.Helper()
+
+	require.GreaterOrEqual(t, fee.Cmp(required), 0)
+	require.LessOrEqual(t, fee.Sub(required).Cmp(fee.Div64(feeEstimationErrorRatio)), 0)
+}
+
+func TestOutTransactionsFee(t *testing.T) {
+	t.Parallel()
+
+	baseFee := types.NewValueFromUint64(2)
+	txns := []*rpctypes.OutTransaction{
+		{
+			CoinsUsed: types.NewValueFromUint64(100),
+			BaseFee:   baseFee,
+			OutTransactions: []*rpctypes.OutTransaction{
+				{CoinsUsed: types.NewValueFromUint64(10), BaseFee: types.NewValueFromUint64(5)},
+			},
+		},
+		{CoinsUsed: types.NewValueFromUint64(1), BaseFee: baseFee},
+	}
+
+	// Every transaction, including the ones sent by the outgoing ones, is charged the SSTORE sentry on top.
+	sentries := rpctypes.SstoreSentryGas.ToValue(baseFee).Mul64(2).
+		Add(rpctypes.SstoreSentryGas.ToValue(types.NewValueFromUint64(5)))
+	require.Equal(t, types.NewValueFromUint64(111).Add(sentries), outTransactionsFee(txns))
+	require.Equal(t, types.NewValueFromUint64(5), maxOutTransactionsBaseFee(types.NewValueFromUint64(1), txns))
+	require.Equal(t, types.NewValueFromUint64(7), maxOutTransactionsBaseFee(types.NewValueFromUint64(7), txns))
+}
+
+func TestFeeEstimationCaps(t *testing.T) {
+	t.Parallel()
+
+	// The state overrides take the address of the cap, which mustn't change the cap of the other estimations.
+	balanceCap := rpctypes.FeeEstimationBalanceCap()
+	*balanceCap.Uint256 = *types.NewUint256(1)
+	require.Equal(t, mustDecimalValue(t, "1000000000000000000000000"), rpctypes.FeeEstimationBalanceCap())
+	require.Positive(t, rpctypes.FeeEstimationBalanceCap().Cmp(rpctypes.FeeEstimationFeeCreditCap()))
+}
+
+func mustDecimalValue(t *testing.T, str string) types.Value {
+	t.Helper()
+
+	v, err := types.NewValueFromDecimal(str)
+	require.NoError(t, err)
+	return v
+}
@@ -0,0 +1,194 @@
+package internal
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	// maxLogsBlockRange limits the number of blocks scanned by a single GetLogs request.
+	maxLogsBlockRange = 1024
+
+	// maxLogsInResponse limits the number of logs returned by a single GetLogs request.
+	maxLogsInResponse = 10000
+)
+
+var (
+	errLogsBlockRangeTooWide = fmt.Errorf("block range must contain at most %d blocks", maxLogsBlockRange)
+	errTooManyLogs           = fmt.Errorf("query returned more than %d logs, narrow the filter", maxLogsInResponse)
+)
+
+// GetLogs returns the logs matching the filter in the given block range.
+// The bloom filter of a block serves as the log index, so the receipts are only read for blocks that may contain
+// matching logs.
+func (api *localShardApiRo) GetLogs(
+	ctx context.Context,
+	filter rawapitypes.LogFilter,
+) ([]*rawapitypes.LogInfo, error) {
+	var finalizedReference rawapitypes.BlockReference
+	if filter.Finalized {
+		var err error
+		finalizedReference, err = api.resolveFinalizedBlock(
+			ctx, rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.FinalizedBlock))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var toBlock types.BlockNumber
+	if filter.ToBlock != nil {
+		toBlock = *filter.ToBlock
+	} else {
+		lastBlock, _, err := db.ReadLastBlock(tx, api.shardId())
+		if err != nil {
+			return nil, err
+		}
+		toBlock = lastBlock.Id
+	}
+	if toBlock < filter.FromBlock {
+		return nil, errors.New("invalid block range: toBlock is less than fromBlock")
+	}
+	if filter.Finalized {
+		finalized, err := api.readBlockId(tx, finalizedReference)
+		if err != nil {
+			return nil, err
+		}
+		if finalized < filter.FromBlock {
+			// None of the blocks of the range is finalized yet.
+			return make([]*rawapitypes.LogInfo, 0), nil
+		}
+		toBlock = min(toBlock, finalized)
+	}
+	if toBlock-filter.FromBlock >= maxLogsBlockRange {
+		return nil, errLogsBlockRangeTooWide
+	}
+
+	logs := make([]*rawapitypes.LogInfo, 0)
+	for number := filter.FromBlock; number <= toBlock; number++ {
+		block, err := db.ReadBlockByNumber(tx, api.shardId(), number)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			// The range is truncated by the last known block.
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		blockLogs, err := api.getBlockLogs(tx, block, filter)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, blockLogs...)
+		if len(logs) > maxLogsInResponse {
+			return nil, errTooManyLogs
+		}
+	}
+	return logs, nil
+}
+
+// readBlockId returns the number of the referenced block, the finalized block is resolved by resolveFinalizedBlock.
+func (api *localShardApiRo) readBlockId(
+	tx db.RoTx,
+	blockReference rawapitypes.BlockReference,
+) (types.BlockNumber, error) {
+	hash, err := api.resolveBlockReference(tx, blockReference)
+	if err != nil {
+		return 0, err
+	}
+	block, err := db.ReadBlock(tx, api.shardId(), hash)
+	if err != nil {
+		return 0, err
+	}
+	return block.Id, nil
+}
+
+// getBlockLogs returns the logs of the block matching the filter. The block range of the filter is not checked.
+func (api *localShardApiRo) getBlockLogs(
+	tx db.RoTx,
+	block *types.Block,
+	filter rawapitypes.LogFilter,
+) ([]*rawapitypes.LogInfo, error) {
+	if !bloomMayMatchLogFilter(block.LogsBloom, filter) {
+		return nil, nil
+	}
+
+	reader := execution.NewDbReceiptTrieReader(tx, api.shardId())
+	reader.SetRootHash(block.ReceiptsRoot)
+	receipts, err := reader.Entries()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(receipts, func(a, b execution.Entry[types.TransactionIndex, *types.Receipt]) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	var logs []*rawapitypes.LogInfo
+	blockHash := block.Hash(api.shardId())
+	var logIndex uint64
+	for _, receipt := range receipts {
+		for _, log := range receipt.Val.Logs {
+			if logMatchesFilter(log, filter) {
+				logs = append(logs, &rawapitypes.LogInfo{
+					Log:              log,
+					BlockId:          block.Id,
+					BlockHash:        blockHash,
+					TransactionHash:  receipt.Val.TxnHash,
+					TransactionIndex: receipt.Key,
+					LogIndex:         logIndex,
+				})
+			}
+			logIndex++
+		}
+	}
+	return logs, nil
+}
+
+// bloomMayMatchLogFilter returns false if the block with the given bloom definitely contains no matching logs.
+func bloomMayMatchLogFilter(bloom types.Bloom, filter rawapitypes.LogFilter) bool {
+	if len(filter.Addresses) > 0 &&
+		!slices.ContainsFunc(filter.Addresses, func(address types.Address) bool {
+			return bloom.Test(address.Bytes())
+		}) {
+		return false
+	}
+	for _, alternatives := range filter.Topics {
+		if len(alternatives) == 0 {
+			continue
+		}
+		if !slices.ContainsFunc(alternatives, func(topic common.Hash) bool {
+			return bloom.Test(topic.Bytes())
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+func logMatchesFilter(log *types.Log, filter rawapitypes.LogFilter) bool {
+	if len(filter.Addresses) > 0 && !slices.Contains(filter.Addresses, log.Address) {
+		return false
+	}
+	if len(filter.Topics) > len(log.Topics) {
+		return false
+	}
+	for i, alternatives := range filter.Topics {
+		if len(alternatives) > 0 && !slices.Contains(alternatives, log.Topics[i]) {
+			return false
+		}
+	}
+	return true
+}
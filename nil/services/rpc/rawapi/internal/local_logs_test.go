@@ -0,0 +1,114 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLogsBlock writes the block of the shard with a receipt holding the logs and makes it the last one.
+func writeLogsBlock(
+	t *testing.T,
+	database db.DB,
+	shardId types.ShardId,
+	number types.BlockNumber,
+	prevBlock common.Hash,
+	logs ...*types.Log,
+) common.Hash {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	receipts := types.Receipts{{Success: true, Logs: logs, TxnHash: common.BytesToHash([]byte{byte(number)})}}
+	receiptsTrie := execution.NewDbReceiptTrie(tx, shardId)
+	require.NoError(t, receiptsTrie.Update(0, receipts[0]))
+
+	block := &types.Block{
+		BlockData: types.BlockData{Id: number, PrevBlock: prevBlock, ReceiptsRoot: receiptsTrie.RootHash()},
+		LogsBloom: types.CreateBloom(receipts),
+	}
+	hash := block.Hash(shardId)
+	require.NoError(t, db.WriteBlock(tx, shardId, hash, block))
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, &execution.BlockGenerationResult{
+		BlockHash: hash,
+		Block:     block,
+	}, execution.ModeVerify))
+	require.NoError(t, tx.Commit())
+	return hash
+}
+
+func TestGetLogs(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	first := types.ShardAndHexToAddress(shardId, "0x1234")
+	second := types.ShardAndHexToAddress(shardId, "0x5678")
+	topic := common.HexToHash("0x01")
+
+	hashes := make([]common.Hash, 3)
+	hashes[0] = writeLogsBlock(t, database, shardId, 0, common.EmptyHash,
+		&types.Log{Address: first, Topics: []common.Hash{topic}})
+	hashes[1] = writeLogsBlock(t, database, shardId, 1, hashes[0],
+		&types.Log{Address: second}, &types.Log{Address: first, Topics: []common.Hash{topic}})
+	hashes[2] = writeLogsBlock(t, database, shardId, 2, hashes[1],
+		&types.Log{Address: first, Topics: []common.Hash{common.HexToHash("0x02")}})
+
+	api := newLocalShardApiRo(shardId, database)
+	// The block 1 is the last one included in the main block.
+	api.setNodeApi(mainBlockNodeApi{childBlocks: []common.Hash{hashes[1]}})
+
+	// getLogs returns the numbers of the blocks and the indices of the logs matching the filter.
+	getLogs := func(t *testing.T, filter rawapitypes.LogFilter) [][2]uint64 {
+		t.Helper()
+
+		logs, err := api.GetLogs(t.Context(), filter)
+		require.NoError(t, err)
+		positions := make([][2]uint64, len(logs))
+		for i, info := range logs {
+			require.Equal(t, hashes[info.BlockId], info.BlockHash)
+			positions[i] = [2]uint64{uint64(info.BlockId), info.LogIndex}
+		}
+		return positions
+	}
+
+	t.Run("Filters", func(t *testing.T) {
+		t.Parallel()
+
+		require.Equal(t, [][2]uint64{{0, 0}, {1, 0}, {1, 1}, {2, 0}}, getLogs(t, rawapitypes.LogFilter{}))
+		require.Equal(t, [][2]uint64{{1, 0}}, getLogs(t, rawapitypes.LogFilter{Addresses: []types.Address{second}}))
+		require.Equal(t, [][2]uint64{{0, 0}, {1, 1}}, getLogs(t, rawapitypes.LogFilter{
+			Addresses: []types.Address{first, second},
+			Topics:    [][]common.Hash{{topic}},
+		}))
+
+		toBlock := types.BlockNumber(1)
+		require.Equal(t, [][2]uint64{{1, 0}, {1, 1}}, getLogs(t, rawapitypes.LogFilter{FromBlock: 1, ToBlock: &toBlock}))
+	})
+
+	t.Run("Finalized", func(t *testing.T) {
+		t.Parallel()
+
+		// The range is cut by the finalized block whether it ends with the latest block or a later one.
+		require.Equal(t, [][2]uint64{{1, 0}, {1, 1}}, getLogs(t, rawapitypes.LogFilter{FromBlock: 1, Finalized: true}))
+		toBlock := types.BlockNumber(10)
+		require.Equal(t, [][2]uint64{{1, 0}, {1, 1}}, getLogs(t, rawapitypes.LogFilter{
+			FromBlock: 1,
+			ToBlock:   &toBlock,
+			Finalized: true,
+		}))
+
+		// None of the blocks of the range is finalized.
+		require.Empty(t, getLogs(t, rawapitypes.LogFilter{FromBlock: 2, Finalized: true}))
+	})
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/google/uuid"
+)
+
+// StateProofSource provides the proofs of the main shard blocks generated by the prover.
+type StateProofSource interface {
+	// GetStateProof returns the proof of the block, rawapitypes.ErrNotFound if it is not generated yet.
+	GetStateProof(ctx context.Context, blockHash common.Hash) (*rawapitypes.StateProof, error)
+	// GetProofTask returns the state of the task, rawapitypes.ErrNotFound if the task is unknown.
+	GetProofTask(ctx context.Context, id uuid.UUID) (*rawapitypes.ProofTaskInfo, error)
+}
+
+// errProofSourceNotAvailable is returned by the proof API of the node without a source of the proofs, so that
+// the clients see that the proofs are not served by the node rather than that the API is unknown.
+var errProofSourceNotAvailable = fmt.Errorf("%w: node has no source of proofs", rawapitypes.ErrNotFound)
+
+type localShardApiProof struct {
+	roApi  *localShardApiRo
+	source StateProofSource
+}
+
+var _ shardApiProof = (*localShardApiProof)(nil)
+
+func newLocalShardApiProof(roApi *localShardApiRo, source StateProofSource) *localShardApiProof {
+	return &localShardApiProof{
+		roApi:  roApi,
+		source: source,
+	}
+}
+
+func (api *localShardApiProof) shardId() types.ShardId {
+	return api.roApi.shardId()
+}
+
+func (api *localShardApiProof) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolProof](),
+		reflect.TypeFor[shardApiProof](),
+		api,
+		api.roApi.shardId(),
+		apiNameProof,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiProof) setNodeApi(nodeApi NodeApi) {
+	api.roApi.nodeApi = nodeApi
+}
+
+// GetStateProofForBlock resolves the reference to the main shard block and returns its proof.
+func (api *localShardApiProof) GetStateProofForBlock(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StateProof, error) {
+	if api.source == nil {
+		return nil, errProofSourceNotAvailable
+	}
+
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.roApi.getBlockHashByReference(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+	return api.source.GetStateProof(ctx, blockHash)
+}
+
+func (api *localShardApiProof) GetProofTaskStatus(
+	ctx context.Context,
+	id uuid.UUID,
+) (*rawapitypes.ProofTaskInfo, error) {
+	if api.source == nil {
+		return nil, errProofSourceNotAvailable
+	}
+	return api.source.GetProofTask(ctx, id)
+}
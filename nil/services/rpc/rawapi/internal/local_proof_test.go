@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// testProofSource serves the proofs of the blocks and the tasks it has.
+type testProofSource struct {
+	proofs map[common.Hash]*rawapitypes.StateProof
+	tasks  map[uuid.UUID]*rawapitypes.ProofTaskInfo
+}
+
+func (s testProofSource) GetStateProof(_ context.Context, blockHash common.Hash) (*rawapitypes.StateProof, error) {
+	if proof, ok := s.proofs[blockHash]; ok {
+		return proof, nil
+	}
+	return nil, rawapitypes.ErrNotFound
+}
+
+func (s testProofSource) GetProofTask(_ context.Context, id uuid.UUID) (*rawapitypes.ProofTaskInfo, error) {
+	if task, ok := s.tasks[id]; ok {
+		return task, nil
+	}
+	return nil, rawapitypes.ErrNotFound
+}
+
+func TestLocalShardApiProof(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	genesis := execution.GenerateZeroState(t, types.MainShardId, database).Hash(types.MainShardId)
+	latest := execution.GenerateBlockFromTransactions(t, types.MainShardId, 1, genesis, database, nil)
+
+	taskId := uuid.New()
+	proof := &rawapitypes.StateProof{BlockHash: latest, BlockNumber: 1, TaskId: taskId, Proof: []byte{1, 2, 3}}
+	task := &rawapitypes.ProofTaskInfo{Id: taskId, Status: rawapitypes.ProofTaskStatusCompleted, BlockHash: latest}
+	source := testProofSource{
+		proofs: map[common.Hash]*rawapitypes.StateProof{latest: proof},
+		tasks:  map[uuid.UUID]*rawapitypes.ProofTaskInfo{taskId: task},
+	}
+	api := newLocalShardApiProof(newLocalShardApiRo(types.MainShardId, database), source)
+
+	// The references are resolved to the hash of the block, which the proofs are kept by.
+	for _, reference := range []rawapitypes.BlockReference{
+		rawapitypes.BlockHashAsBlockReference(latest),
+		rawapitypes.BlockNumberAsBlockReference(1),
+		rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock),
+	} {
+		got, err := api.GetStateProofForBlock(t.Context(), reference)
+		require.NoError(t, err)
+		require.Equal(t, proof, got)
+	}
+
+	// The proof of the block is not generated yet.
+	_, err = api.GetStateProofForBlock(t.Context(), rawapitypes.BlockHashAsBlockReference(genesis))
+	require.ErrorIs(t, err, rawapitypes.ErrNotFound)
+
+	got, err := api.GetProofTaskStatus(t.Context(), taskId)
+	require.NoError(t, err)
+	require.Equal(t, task, got)
+	_, err = api.GetProofTaskStatus(t.Context(), uuid.New())
+	require.ErrorIs(t, err, rawapitypes.ErrNotFound)
+
+	// The API of the node without a source reports that the proofs are not available.
+	api = newLocalShardApiProof(newLocalShardApiRo(types.MainShardId, database), nil)
+	_, err = api.GetStateProofForBlock(t.Context(), rawapitypes.BlockHashAsBlockReference(latest))
+	require.ErrorIs(t, err, rawapitypes.ErrNotFound)
+	_, err = api.GetProofTaskStatus(t.Context(), taskId)
+	require.ErrorIs(t, err, rawapitypes.ErrNotFound)
+}
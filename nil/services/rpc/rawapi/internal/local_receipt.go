@@ -1,9 +1,11 @@
 package internal
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 
 	fastssz "github.com/NilFoundation/fastssz"
 	"github.com/NilFoundation/nil/nil/common"
@@ -15,7 +17,7 @@ import (
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
-func (api *localShardApiRo) GetInTransactionReceipt(
+func (api *localShardApiRo) GetReceipt(
 	ctx context.Context,
 	hash common.Hash,
 ) (*rawapitypes.ReceiptInfo, error) {
@@ -51,39 +53,11 @@ func (api *localShardApiRo) GetInTransactionReceipt(
 			return nil, err
 		}
 
-		if priorityFee, ok := execution.GetEffectivePriorityFee(block.BaseFee, transaction); ok {
-			gasPrice = block.BaseFee.Add(priorityFee)
-		} else if receipt.Status != types.ErrorBaseFeeTooHigh {
-			api.logger.Error().
-				Stringer(logging.FieldTransactionHash, hash).
-				Msgf("Calculation of EffectivePriorityFee failed with wrong status: %s", receipt.Status)
-		}
-
-		// Check if the transaction is included in the main chain
-		rawMainBlock, err := api.nodeApi.GetFullBlockData(
-			ctx,
-			types.MainShardId,
-			rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
-		if err == nil {
-			mainBlockData, err := rawMainBlock.DecodeSSZ()
-			if err != nil {
-				return nil, err
-			}
+		gasPrice = api.effectiveGasPrice(hash, block, transaction, receipt)
 
-			if api.shardId().IsMainShard() {
-				includedInMain = mainBlockData.Id >= block.Id
-			} else {
-				if len(rawMainBlock.ChildBlocks) < int(api.shardId()) {
-					return nil, fmt.Errorf(
-						"%w: main shard includes only %d blocks",
-						makeShardNotFoundError(methodNameChecked("GetInTransactionReceipt"), api.shardId()),
-						len(rawMainBlock.ChildBlocks))
-				}
-				blockHash := rawMainBlock.ChildBlocks[api.shardId()-1]
-				if last, err := api.accessor.Access(tx, api.shardId()).GetBlock().ByHash(blockHash); err == nil {
-					includedInMain = last.Block().Id >= block.Id
-				}
-			}
+		includedInMain, err = api.isIncludedInMain(ctx, tx, block, methodNameChecked("GetReceipt"))
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		gasPrice = types.DefaultGasPrice
@@ -121,7 +95,7 @@ func (api *localShardApiRo) GetInTransactionReceipt(
 				return nil, err
 			}
 			txnHash := res.Transaction().Hash()
-			r, err := api.nodeApi.GetInTransactionReceipt(ctx, res.Transaction().To.ShardId(), txnHash)
+			r, err := api.nodeApi.GetReceipt(ctx, res.Transaction().To.ShardId(), txnHash)
 			if err != nil {
 				return nil, err
 			}
@@ -165,6 +139,158 @@ func (api *localShardApiRo) GetInTransactionReceipt(
 	}, nil
 }
 
+// GetBlockReceipts returns the receipts of all transactions of the block in the order of the transactions.
+// Unlike GetReceipt, it doesn't collect the receipts of the outgoing transactions,
+// only their hashes are returned.
+func (api *localShardApiRo) GetBlockReceipts(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.ReceiptInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, err := api.fetchBlockByRef(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	includedInMain, err := api.isIncludedInMain(ctx, tx, block, methodNameChecked("GetBlockReceipts"))
+	if err != nil {
+		return nil, err
+	}
+
+	transactions, err := readBlockTransactions(tx, api.shardId(), block.InTransactionsRoot)
+	if err != nil {
+		return nil, err
+	}
+	outTransactions, err := readBlockTransactions(tx, api.shardId(), block.OutTransactionsRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	receiptsReader := execution.NewDbReceiptTrieReader(tx, api.shardId())
+	receiptsReader.SetRootHash(block.ReceiptsRoot)
+	receipts, err := receiptsReader.Entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(receipts) != len(transactions) {
+		return nil, fmt.Errorf("block has %d transactions and %d receipts", len(transactions), len(receipts))
+	}
+	slices.SortFunc(receipts, func(a, b execution.Entry[types.TransactionIndex, *types.Receipt]) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	blockHash := block.Hash(api.shardId())
+	infos := make([]*rawapitypes.ReceiptInfo, len(receipts))
+	for i, entry := range receipts {
+		receipt := entry.Val
+		transaction := transactions[i]
+		hash := transaction.Hash()
+
+		errMsg, err := db.ReadError(tx, hash)
+		if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+			return nil, err
+		}
+
+		outEnd := receipt.OutTxnIndex + receipt.OutTxnNum
+		if int(outEnd) > len(outTransactions) {
+			return nil, fmt.Errorf("receipt of transaction %s refers to missing outgoing transactions", hash)
+		}
+		var outHashes []common.Hash
+		for _, outTxn := range outTransactions[receipt.OutTxnIndex:outEnd] {
+			outHashes = append(outHashes, outTxn.Hash())
+		}
+
+		receiptSSZ, err := receipt.MarshalSSZ()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal receipt: %w", err)
+		}
+
+		infos[i] = &rawapitypes.ReceiptInfo{
+			ReceiptSSZ:      receiptSSZ,
+			Flags:           transaction.Flags,
+			Index:           entry.Key,
+			BlockHash:       blockHash,
+			BlockId:         block.Id,
+			IncludedInMain:  includedInMain,
+			OutTransactions: outHashes,
+			ErrorMessage:    errMsg,
+			GasPrice:        api.effectiveGasPrice(hash, block, transaction, receipt),
+		}
+	}
+	return infos, nil
+}
+
+// readBlockTransactions reads the transactions of the trie in the order of their indexes.
+func readBlockTransactions(tx db.RoTx, shardId types.ShardId, root common.Hash) ([]*types.Transaction, error) {
+	reader := execution.NewDbTransactionTrieReader(tx, shardId)
+	reader.SetRootHash(root)
+	entries, err := reader.Entries()
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(entries, func(a, b execution.Entry[types.TransactionIndex, *types.Transaction]) int {
+		return cmp.Compare(a.Key, b.Key)
+	})
+
+	transactions := make([]*types.Transaction, len(entries))
+	for i, entry := range entries {
+		transactions[i] = entry.Val
+	}
+	return transactions, nil
+}
+
+func (api *localShardApiRo) effectiveGasPrice(
+	hash common.Hash, block *types.Block, transaction *types.Transaction, receipt *types.Receipt,
+) types.Value {
+	var gasPrice types.Value
+	if priorityFee, ok := execution.GetEffectivePriorityFee(block.BaseFee, transaction); ok {
+		gasPrice = block.BaseFee.Add(priorityFee)
+	} else if receipt.Status != types.ErrorBaseFeeTooHigh {
+		api.logger.Error().
+			Stringer(logging.FieldTransactionHash, hash).
+			Msgf("Calculation of EffectivePriorityFee failed with wrong status: %s", receipt.Status)
+	}
+	return gasPrice
+}
+
+// isIncludedInMain checks if the block is included in the main chain.
+// The block is considered not included if the latest main block is not available.
+func (api *localShardApiRo) isIncludedInMain(
+	ctx context.Context, tx db.RoTx, block *types.Block, methodName string,
+) (bool, error) {
+	rawMainBlock, err := api.nodeApi.GetFullBlockData(
+		ctx,
+		types.MainShardId,
+		rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+	if err != nil {
+		return false, nil
+	}
+	mainBlockData, err := rawMainBlock.DecodeSSZ()
+	if err != nil {
+		return false, err
+	}
+
+	if api.shardId().IsMainShard() {
+		return mainBlockData.Id >= block.Id, nil
+	}
+	if len(rawMainBlock.ChildBlocks) < int(api.shardId()) {
+		return false, fmt.Errorf(
+			"%w: main shard includes only %d blocks",
+			makeShardNotFoundError(methodName, api.shardId()),
+			len(rawMainBlock.ChildBlocks))
+	}
+	blockHash := rawMainBlock.ChildBlocks[api.shardId()-1]
+	if last, err := api.accessor.Access(tx, api.shardId()).GetBlock().ByHash(blockHash); err == nil {
+		return last.Block().Id >= block.Id, nil
+	}
+	return false, nil
+}
+
 func (api *localShardApiRo) getBlockAndInTransactionIndexByTransactionHash(
 	tx db.RoTx,
 	shardId types.ShardId,
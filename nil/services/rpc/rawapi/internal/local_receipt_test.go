@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBlockReceipts(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	newTransaction := func(data string) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.Data = types.Code(data)
+		return txn
+	}
+	txns := []*types.Transaction{newTransaction("first"), newTransaction("second"), newTransaction("third")}
+
+	// The first block is included in the latest main block, the second one is not yet.
+	mainBlock := execution.GenerateZeroState(t, types.MainShardId, database).Hash(types.MainShardId)
+	zeroBlock := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	included := execution.GenerateBlockFromTransactionsWithoutExecution(t, shardId, 1, zeroBlock, database, txns...)
+	execution.GenerateBlockFromTransactionsWithoutExecution(t, shardId, 2, included, database,
+		newTransaction("latest"))
+	execution.GenerateBlockFromTransactions(t, types.MainShardId, 1, mainBlock, database,
+		map[types.ShardId]common.Hash{shardId: included})
+	api := NodeApiBuilder(database, nil).
+		WithLocalShardApiRo(types.MainShardId).
+		WithLocalShardApiRo(shardId).
+		BuildAndReset()
+
+	// The receipts are the ones of the transactions of the block in their order.
+	infos, err := api.GetBlockReceipts(t.Context(), shardId, rawapitypes.BlockHashAsBlockReference(included))
+	require.NoError(t, err)
+	require.Len(t, infos, len(txns))
+	for i, info := range infos {
+		require.Equal(t, types.TransactionIndex(i), info.Index)
+		require.Equal(t, included, info.BlockHash)
+		require.Equal(t, types.BlockNumber(1), info.BlockId)
+		require.True(t, info.IncludedInMain)
+		require.Empty(t, info.OutTransactions)
+
+		var receipt types.Receipt
+		require.NoError(t, receipt.UnmarshalSSZ(info.ReceiptSSZ))
+		require.Equal(t, txns[i].Hash(), receipt.TxnHash)
+		require.True(t, receipt.Success)
+	}
+
+	infos, err = api.GetBlockReceipts(t.Context(), shardId,
+		rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+	require.NoError(t, err)
+	require.Len(t, infos, 1)
+	require.Equal(t, types.BlockNumber(2), infos[0].BlockId)
+	require.False(t, infos[0].IncludedInMain)
+
+	// The zero state receives no transactions.
+	infos, err = api.GetBlockReceipts(t.Context(), shardId, rawapitypes.BlockNumberAsBlockReference(0))
+	require.NoError(t, err)
+	require.Empty(t, infos)
+}
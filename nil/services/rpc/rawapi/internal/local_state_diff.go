@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+// calculateStateDiff compares the balances and the storage slots of the accounts loaded by the execution
+// with their values in the state before the execution. The accounts deleted by the execution are marked so.
+func calculateStateDiff(post, pre *execution.ExecutionState) ([]rawapitypes.AccountDiff, error) {
+	diffs := make([]rawapitypes.AccountDiff, 0)
+	for addr, account := range post.Accounts {
+		preAccount, err := pre.GetAccount(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted := account == nil
+		if !deleted {
+			if deleted, err = post.HasSelfDestructed(addr); err != nil {
+				return nil, err
+			}
+		}
+		if deleted && preAccount == nil {
+			// The account is created and deleted by the same execution.
+			continue
+		}
+
+		diff := rawapitypes.AccountDiff{Address: addr, Deleted: deleted}
+
+		preBalance := types.NewZeroValue()
+		if preAccount != nil {
+			preBalance = preAccount.Balance
+		}
+		postBalance := types.NewZeroValue()
+		if !deleted {
+			postBalance = account.Balance
+		}
+		if !postBalance.Eq(preBalance) {
+			diff.Balance = &rawapitypes.BalanceDiff{Pre: preBalance, Post: postBalance}
+		}
+
+		// The storage of the deleted account is dropped as a whole, its slots are not listed.
+		if !deleted {
+			for key, value := range account.State {
+				var preValue common.Hash
+				if preAccount != nil {
+					if preValue, err = preAccount.GetState(key); err != nil {
+						return nil, err
+					}
+				}
+				if value != preValue {
+					diff.Storage = append(diff.Storage, rawapitypes.StorageDiff{Key: key, Pre: preValue, Post: value})
+				}
+			}
+		}
+
+		if !diff.Deleted && diff.Balance == nil && len(diff.Storage) == 0 {
+			continue
+		}
+		slices.SortFunc(diff.Storage, func(a, b rawapitypes.StorageDiff) int {
+			return bytes.Compare(a.Key.Bytes(), b.Key.Bytes())
+		})
+		diffs = append(diffs, diff)
+	}
+
+	slices.SortFunc(diffs, func(a, b rawapitypes.AccountDiff) int {
+		return bytes.Compare(a.Address.Bytes(), b.Address.Bytes())
+	})
+	return diffs, nil
+}
+
+// TraceTransactionStateDiff re-executes the transaction included in a block of the shard and returns the state
+// it modified. The preceding transactions of the block are executed twice, so that the state before
+// the transaction is kept for the comparison.
+func (api *localShardApiDebug) TraceTransactionStateDiff(
+	ctx context.Context,
+	hash common.Hash,
+) (*rawapitypes.StateDiff, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	pre, _, err := api.replayBlockUntil(ctx, tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	post, res, err := api.replayTransaction(ctx, tx, hash, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return makeStateDiff(post, pre, res)
+}
+
+// TraceCallStateDiff executes the call the same way as Call and returns the state it modified
+// on the destination shard. The overrides are applied to the state before the call.
+func (api *localShardApiDebug) TraceCallStateDiff(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.StateDiff, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	call, err := api.roApi.executeCall(ctx, tx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pre, err := execution.NewExecutionState(tx, call.es.ShardId, execution.StateParams{
+		Block:          call.block,
+		ConfigAccessor: config.GetStubAccessor(),
+		Mode:           execution.ModeReadOnly,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if overrides != nil {
+		if err := overrides.Override(pre); err != nil {
+			return nil, err
+		}
+	}
+
+	return makeStateDiff(call.es, pre, call.result)
+}
+
+func makeStateDiff(
+	post, pre *execution.ExecutionState, res *execution.ExecutionResult,
+) (*rawapitypes.StateDiff, error) {
+	accounts, err := calculateStateDiff(post, pre)
+	if err != nil {
+		return nil, err
+	}
+	stateDiff := &rawapitypes.StateDiff{Accounts: accounts}
+	if res.Failed() {
+		stateDiff.Error = res.GetError().Error()
+	}
+	return stateDiff, nil
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateStateDiff(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	shardId := types.BaseShardId
+	newState := func() *execution.ExecutionState {
+		es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+			ConfigAccessor: config.GetStubAccessor(),
+		})
+		require.NoError(t, err)
+		return es
+	}
+	pre, post := newState(), newState()
+
+	modified := types.ShardAndHexToAddress(shardId, "0x01")
+	created := types.ShardAndHexToAddress(shardId, "0x02")
+	deleted := types.ShardAndHexToAddress(shardId, "0x03")
+	destroyed := types.ShardAndHexToAddress(shardId, "0x04")
+	key := common.HexToHash("0x01")
+
+	for _, es := range []*execution.ExecutionState{pre, post} {
+		for _, addr := range []types.Address{modified, deleted, destroyed} {
+			require.NoError(t, es.CreateAccount(addr))
+			require.NoError(t, es.SetBalance(addr, types.NewValueFromUint64(5)))
+		}
+	}
+	require.NoError(t, post.SetBalance(modified, types.NewValueFromUint64(7)))
+	require.NoError(t, post.SetState(modified, key, common.HexToHash("0x2a")))
+
+	// The account created and deleted by the same execution is not reported.
+	require.NoError(t, post.CreateAccount(created))
+	require.NoError(t, post.CreateContract(created))
+	require.NoError(t, post.SetState(created, key, common.HexToHash("0x2a")))
+	require.NoError(t, post.Selfdestruct6780(created))
+
+	// The existing account becoming the contract destroyed by the execution is reported deleted,
+	// as is the account dropped from the state.
+	require.NoError(t, post.CreateContract(destroyed))
+	require.NoError(t, post.SetState(destroyed, key, common.HexToHash("0x2a")))
+	require.NoError(t, post.Selfdestruct6780(destroyed))
+	post.Accounts[deleted] = nil
+
+	diffs, err := calculateStateDiff(post, pre)
+	require.NoError(t, err)
+	removed := &rawapitypes.BalanceDiff{Pre: types.NewValueFromUint64(5), Post: types.NewZeroValue()}
+	require.Equal(t, []rawapitypes.AccountDiff{
+		{
+			Address: modified,
+			Balance: &rawapitypes.BalanceDiff{Pre: types.NewValueFromUint64(5), Post: types.NewValueFromUint64(7)},
+			Storage: []rawapitypes.StorageDiff{{Key: key, Post: common.HexToHash("0x2a")}},
+		},
+		{Address: deleted, Deleted: true, Balance: removed},
+		{Address: destroyed, Deleted: true, Balance: removed},
+	}, diffs)
+}
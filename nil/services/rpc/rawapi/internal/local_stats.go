@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// maxStatsBlockRange limits the number of blocks a single GetShardStats request is computed over.
+const maxStatsBlockRange = 1024
+
+// GetShardStats computes the statistics of the count blocks starting with from. The range is truncated
+// by the last block of the shard, so the statistics of the latest blocks are requested with a large count.
+func (api *localShardApiRo) GetShardStats(
+	ctx context.Context,
+	from types.BlockNumber,
+	count uint64,
+) (*rawapitypes.ShardStats, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stats := &rawapitypes.ShardStats{FromBlock: from}
+	count = min(count, maxStatsBlockRange)
+	for number := from; number < from.AddSaturating(count); number++ {
+		hash, err := db.ReadBlockHashByNumber(tx, api.shardId(), number)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		block, err := db.ReadBlock(tx, api.shardId(), hash)
+		if err != nil {
+			return nil, err
+		}
+		if err := api.addBlockStats(tx, stats, block); err != nil {
+			return nil, err
+		}
+	}
+
+	if stats.BlockCount != 0 {
+		stats.ToBlock = from + types.BlockNumber(stats.BlockCount-1)
+		stats.AverageGasUsed = stats.GasUsed / types.Gas(stats.BlockCount)
+	}
+	return stats, nil
+}
+
+func (api *localShardApiRo) addBlockStats(tx db.RoTx, stats *rawapitypes.ShardStats, block *types.Block) error {
+	inReader := execution.NewDbTransactionTrieReader(tx, api.shardId())
+	inReader.SetRootHash(block.InTransactionsRoot)
+	inTransactions, err := inReader.Values()
+	if err != nil {
+		return err
+	}
+
+	outReader := execution.NewDbTransactionTrieReader(tx, api.shardId())
+	outReader.SetRootHash(block.OutTransactionsRoot)
+	outTransactions, err := outReader.Values()
+	if err != nil {
+		return err
+	}
+
+	stats.BlockCount++
+	if len(inTransactions) == 0 {
+		stats.EmptyBlockCount++
+	}
+	stats.GasUsed += block.GasUsed
+	stats.MaxGasUsed = max(stats.MaxGasUsed, block.GasUsed)
+	stats.InTransactionCount += uint64(len(inTransactions))
+	stats.OutTransactionCount += uint64(len(outTransactions))
+	for _, txn := range inTransactions {
+		if txn.IsInternal() && txn.From.ShardId() != api.shardId() {
+			stats.CrossShardInCount++
+		}
+	}
+	for _, txn := range outTransactions {
+		if txn.To.ShardId() != api.shardId() {
+			stats.CrossShardOutCount++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package internal
+
+import (
+	"context"
+	"errors"
+
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+var errNewBlocksNotWatched = errors.New("the node doesn't commit the blocks of the shard, they can't be watched")
+
+func (api *localShardApiRo) SubscribeNewHeads(ctx context.Context) (<-chan sszx.SSZEncodedData, error) {
+	heads := make(chan sszx.SSZEncodedData, subscriptionBufferSize)
+	err := api.watchNewBlocks(ctx, func(_ db.RoTx, block *types.Block, _ bool) bool {
+		header, err := block.MarshalSSZ()
+		if err != nil {
+			api.logger.Error().Err(err).Msg("Failed to marshal block header")
+			return false
+		}
+		select {
+		case heads <- header:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() {
+		close(heads)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return heads, nil
+}
+
+// SubscribeLogs sends the logs matching the filter from the blocks added to the shard after the call.
+// Blocks before FromBlock are skipped, the subscription is over once the block after ToBlock is added.
+// The logs of the blocks the chain is reset to are not sent again.
+// If the filter is limited to the finalized blocks, the logs of a block are sent once it's finalized instead.
+func (api *localShardApiRo) SubscribeLogs(
+	ctx context.Context,
+	filter rawapitypes.LogFilter,
+) (<-chan *rawapitypes.LogInfo, error) {
+	if filter.Finalized {
+		return api.subscribeFinalizedLogs(ctx, filter)
+	}
+
+	logs := make(chan *rawapitypes.LogInfo, subscriptionBufferSize)
+	err := api.watchNewBlocks(ctx, func(tx db.RoTx, block *types.Block, reset bool) bool {
+		if reset || block.Id < filter.FromBlock {
+			return true
+		}
+		if filter.ToBlock != nil && block.Id > *filter.ToBlock {
+			return false
+		}
+
+		blockLogs, err := api.getBlockLogs(tx, block, filter)
+		if err != nil {
+			api.logger.Error().Err(err).Msg("Failed to read block logs")
+			return false
+		}
+		for _, info := range blockLogs {
+			select {
+			case logs <- info:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}, func() {
+		close(logs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// subscribeFinalizedLogs sends the logs matching the filter from the blocks finalized after the call.
+// The finality is checked once a new block is added to the shard, the shards add the blocks regularly
+// even if they are empty.
+func (api *localShardApiRo) subscribeFinalizedLogs(
+	ctx context.Context,
+	filter rawapitypes.LogFilter,
+) (<-chan *rawapitypes.LogInfo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	newBlocks, err := api.subscribeNewBlocks(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	finalized, err := api.readFinalizedBlockId(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	logs := make(chan *rawapitypes.LogInfo, subscriptionBufferSize)
+	go func() {
+		defer close(logs)
+		defer cancel()
+
+		next := max(finalized+1, filter.FromBlock)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-newBlocks:
+			}
+
+			last, err := api.readFinalizedBlockId(ctx)
+			if err != nil {
+				api.logger.Warn().Err(err).Msg("Failed to read the finalized block")
+				continue
+			}
+
+			var proceed bool
+			next, proceed = api.sendBlockRangeLogs(ctx, next, last, filter, logs)
+			if !proceed {
+				return
+			}
+		}
+	}()
+	return logs, nil
+}
+
+// readFinalizedBlockId returns the number of the finalized block of the shard, see FinalizedBlock.
+func (api *localShardApiRo) readFinalizedBlockId(ctx context.Context) (types.BlockNumber, error) {
+	blockReference, err := api.resolveFinalizedBlock(
+		ctx, rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.FinalizedBlock))
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	return api.readBlockId(tx, blockReference)
+}
+
+// sendBlockRangeLogs sends the logs matching the filter from the blocks starting from next up to the last one
+// and returns the number of the first block whose logs are not sent yet. It's false once the subscription is over.
+func (api *localShardApiRo) sendBlockRangeLogs(
+	ctx context.Context,
+	next types.BlockNumber,
+	last types.BlockNumber,
+	filter rawapitypes.LogFilter,
+	logs chan<- *rawapitypes.LogInfo,
+) (types.BlockNumber, bool) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		api.logger.Warn().Err(err).Msg("Failed to create transaction")
+		return next, true
+	}
+	defer tx.Rollback()
+
+	for ; next <= last; next++ {
+		if filter.ToBlock != nil && next > *filter.ToBlock {
+			return next, false
+		}
+		block, err := db.ReadBlockByNumber(tx, api.shardId(), next)
+		if err != nil {
+			api.logger.Warn().Err(err).Msg("Failed to read block")
+			return next, true
+		}
+		blockLogs, err := api.getBlockLogs(tx, block, filter)
+		if err != nil {
+			api.logger.Error().Err(err).Msg("Failed to read block logs")
+			return next, false
+		}
+		for _, info := range blockLogs {
+			select {
+			case logs <- info:
+			case <-ctx.Done():
+				return next, false
+			}
+		}
+	}
+	return next, true
+}
+
+// watchNewBlocks calls onBlock for every block added to the shard after the call, in order, until ctx is done
+// or onBlock returns false. onStop is called once the watching is over. Once the chain is reset,
+// see NewBlocksSource, onBlock is called again for the block it is reset to with reset set,
+// and then for the blocks replacing the dropped ones.
+func (api *localShardApiRo) watchNewBlocks(
+	ctx context.Context,
+	onBlock func(tx db.RoTx, block *types.Block, reset bool) bool,
+	onStop func(),
+) error {
+	ctx, cancel := context.WithCancel(ctx)
+	// The blocks are subscribed to before the last one is read, so that none of them is missed.
+	newBlocks, err := api.subscribeNewBlocks(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	lastBlock, _, err := db.ReadLastBlock(tx, api.shardId())
+	tx.Rollback()
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	go func() {
+		defer onStop()
+		defer cancel()
+
+		next := lastBlock.Id + 1
+		for {
+			reset := false
+			select {
+			case <-ctx.Done():
+				return
+			case blockId := <-newBlocks:
+				if blockId < next-1 {
+					next = blockId
+					reset = true
+				}
+			}
+
+			var proceed bool
+			next, proceed = api.processNewBlocks(ctx, next, reset, onBlock)
+			if !proceed {
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// subscribeNewBlocks returns a channel receiving the numbers of the blocks committed to the shard by the node
+// until ctx is done, see NewBlocksSource. The blocks can't be watched if the node doesn't commit them.
+func (api *localShardApiRo) subscribeNewBlocks(ctx context.Context) (<-chan types.BlockNumber, error) {
+	if api.newBlocks == nil {
+		return nil, errNewBlocksNotWatched
+	}
+	return api.newBlocks.SubscribeBlocks(ctx), nil
+}
+
+func (api *localShardApiRo) subscribeCommittedBlocks(ctx context.Context) <-chan types.BlockNumber {
+	if api.newBlocks == nil {
+		return nil
+	}
+	return api.newBlocks.SubscribeBlocks(ctx)
+}
+
+// processNewBlocks passes the blocks starting from next to onBlock and returns the number of the first block
+// that is not processed yet. If reset is set, next is the block the chain is reset to.
+func (api *localShardApiRo) processNewBlocks(
+	ctx context.Context,
+	next types.BlockNumber,
+	reset bool,
+	onBlock func(tx db.RoTx, block *types.Block, reset bool) bool,
+) (types.BlockNumber, bool) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		api.logger.Warn().Err(err).Msg("Failed to create transaction")
+		return next, true
+	}
+	defer tx.Rollback()
+
+	for {
+		block, err := db.ReadBlockByNumber(tx, api.shardId(), next)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			return next, true
+		}
+		if err != nil {
+			api.logger.Warn().Err(err).Msg("Failed to read block")
+			return next, true
+		}
+		if !onBlock(tx, block, reset) {
+			return next, false
+		}
+		reset = false
+		next++
+	}
+}
@@ -0,0 +1,133 @@
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlocksSource passes the numbers sent to it to the only subscriber. Since it's unbuffered,
+// a send returns once the subscriber is done with the previous block.
+type testBlocksSource chan types.BlockNumber
+
+func (s testBlocksSource) SubscribeBlocks(context.Context) <-chan types.BlockNumber {
+	return s
+}
+
+// finalizingNodeApi serves the latest main block including the block of the shard it's set to.
+type finalizingNodeApi struct {
+	NodeApi
+
+	finalized atomic.Pointer[common.Hash]
+}
+
+func (api *finalizingNodeApi) GetFullBlockData(
+	_ context.Context, shardId types.ShardId, _ rawapitypes.BlockReference,
+) (*types.RawBlockWithExtractedData, error) {
+	if shardId != types.MainShardId {
+		return nil, makeShardNotFoundError("GetFullBlockData", shardId)
+	}
+	return &types.RawBlockWithExtractedData{ChildBlocks: []common.Hash{*api.finalized.Load()}}, nil
+}
+
+func TestSubscribeLogs(t *testing.T) {
+	t.Parallel()
+
+	shardId := types.BaseShardId
+	address := types.ShardAndHexToAddress(shardId, "0x1234")
+
+	// newSubscribedApi returns the API of the shard with the zero block, the blocks of which are sent by the source.
+	newSubscribedApi := func(t *testing.T) (*localShardApiRo, db.DB, testBlocksSource, common.Hash) {
+		t.Helper()
+
+		database, err := db.NewBadgerDbInMemory()
+		require.NoError(t, err)
+		t.Cleanup(database.Close)
+
+		zeroBlock := writeLogsBlock(t, database, shardId, 0, common.EmptyHash, &types.Log{Address: address})
+		source := make(testBlocksSource)
+		api := newLocalShardApiRo(shardId, database)
+		api.newBlocks = source
+		return api, database, source, zeroBlock
+	}
+
+	t.Run("Latest", func(t *testing.T) {
+		t.Parallel()
+
+		api, database, source, zeroBlock := newSubscribedApi(t)
+		logs, err := api.SubscribeLogs(t.Context(), rawapitypes.LogFilter{Addresses: []types.Address{address}})
+		require.NoError(t, err)
+
+		// The logs of the blocks added after the call are sent as soon as the blocks are.
+		writeLogsBlock(t, database, shardId, 1, zeroBlock, &types.Log{Address: address})
+		source <- 1
+		info := <-logs
+		require.Equal(t, types.BlockNumber(1), info.BlockId)
+	})
+
+	t.Run("Finalized", func(t *testing.T) {
+		t.Parallel()
+
+		api, database, source, zeroBlock := newSubscribedApi(t)
+		nodeApi := new(finalizingNodeApi)
+		nodeApi.finalized.Store(&zeroBlock)
+		api.setNodeApi(nodeApi)
+
+		toBlock := types.BlockNumber(1)
+		logs, err := api.SubscribeLogs(t.Context(), rawapitypes.LogFilter{ToBlock: &toBlock, Finalized: true})
+		require.NoError(t, err)
+
+		first := writeLogsBlock(t, database, shardId, 1, zeroBlock, &types.Log{Address: address})
+		second := writeLogsBlock(t, database, shardId, 2, first, &types.Log{Address: address})
+
+		// The logs of the blocks are not sent until they are finalized.
+		source <- 2
+		source <- 2
+		require.Empty(t, logs)
+
+		nodeApi.finalized.Store(&first)
+		source <- 2
+		info := <-logs
+		require.Equal(t, types.BlockNumber(1), info.BlockId)
+		require.Equal(t, first, info.BlockHash)
+
+		// The subscription is over once the block after the range is finalized.
+		nodeApi.finalized.Store(&second)
+		source <- 2
+		_, ok := <-logs
+		require.False(t, ok)
+	})
+}
+
+func TestSubscribeNewHeads(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	zeroBlock := writeLogsBlock(t, database, shardId, 0, common.EmptyHash)
+	api := newLocalShardApiRo(shardId, database)
+
+	// The shard is not polled if the node doesn't commit its blocks.
+	_, err = api.SubscribeNewHeads(t.Context())
+	require.ErrorIs(t, err, errNewBlocksNotWatched)
+
+	source := make(testBlocksSource)
+	api.newBlocks = source
+	heads, err := api.SubscribeNewHeads(t.Context())
+	require.NoError(t, err)
+
+	writeLogsBlock(t, database, shardId, 1, zeroBlock)
+	source <- 1
+	var head types.Block
+	require.NoError(t, head.UnmarshalSSZ(<-heads))
+	require.Equal(t, types.BlockNumber(1), head.Id)
+}
@@ -0,0 +1,201 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	defaultSnapshotChunkContracts = 256
+	maxSnapshotChunkContracts     = 4096
+
+	defaultSyncBlocks = 100
+	maxSyncBlocks     = 1000
+)
+
+type localShardApiSync struct {
+	roApi *localShardApiRo
+}
+
+var _ shardApiSync = (*localShardApiSync)(nil)
+
+func newLocalShardApiSync(roApi *localShardApiRo) *localShardApiSync {
+	return &localShardApiSync{
+		roApi: roApi,
+	}
+}
+
+func (api *localShardApiSync) shardId() types.ShardId {
+	return api.roApi.shardId()
+}
+
+func (api *localShardApiSync) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolSync](),
+		reflect.TypeFor[shardApiSync](),
+		api,
+		api.roApi.shardId(),
+		apiNameSync,
+		networkManager,
+		cfg,
+		logger)
+}
+
+func (api *localShardApiSync) setNodeApi(nodeApi NodeApi) {
+	api.roApi.nodeApi = nodeApi
+}
+
+// GetSnapshotManifest returns the block the snapshot of the state is taken at together with the data
+// of the block that is not stored in the contract trie. The contracts are fetched with GetSnapshotChunk.
+func (api *localShardApiSync) GetSnapshotManifest(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.SnapshotManifest, error) {
+	blockReference, err := api.roApi.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.roApi.getBlockHashByReference(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+	block, err := api.readSnapshotBlock(tx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := api.roApi.accessor.RawAccess(tx, api.shardId()).
+		GetBlock().
+		WithChildBlocks().
+		WithConfig().
+		ByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	blockSSZ, err := block.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.SnapshotManifest{
+		BlockSSZ:    blockSSZ,
+		BlockHash:   blockHash,
+		ChildBlocks: data.ChildBlocks(),
+		Config:      data.Config(),
+	}, nil
+}
+
+// GetSnapshotChunk returns the contracts of the block with the keys starting from the requested one.
+func (api *localShardApiSync) GetSnapshotChunk(
+	ctx context.Context,
+	request rawapitypes.SnapshotChunkRequest,
+) (*rawapitypes.SnapshotChunk, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, err := api.readSnapshotBlock(tx, request.BlockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := int(min(request.Limit, maxSnapshotChunkContracts))
+	if limit == 0 {
+		limit = defaultSnapshotChunkContracts
+	}
+
+	root := mpt.NewDbReader(tx, api.shardId(), db.ContractTrieTable)
+	root.SetRootHash(block.SmartContractsRoot)
+	chunk := &rawapitypes.SnapshotChunk{Contracts: make([]*rawapitypes.SmartContract, 0, limit)}
+	for key, contractRaw := range root.IterateFrom(request.Start.Bytes()) {
+		if len(chunk.Contracts) == limit {
+			next := common.BytesToHash(key)
+			chunk.Next = &next
+			break
+		}
+		contract, err := api.roApi.readSmartContract(tx, contractRaw)
+		if err != nil {
+			return nil, err
+		}
+		chunk.Contracts = append(chunk.Contracts, contract)
+	}
+	return chunk, nil
+}
+
+// GetBlocksSince streams the consecutive full blocks starting with the block with the given number,
+// so that a follower can keep up with the shard after bootstrapping it. The blocks carry
+// the hashes of the child blocks, i.e., the main shard blocks reference the blocks of the other shards.
+// The result is truncated by the last known block, an empty result means that the follower is up to date.
+func (api *localShardApiSync) GetBlocksSince(
+	ctx context.Context,
+	sequence types.BlockNumber,
+	limit uint64,
+) ([]*types.RawBlockWithExtractedData, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	limit = min(limit, maxSyncBlocks)
+	if limit == 0 {
+		limit = defaultSyncBlocks
+	}
+
+	blocks := make([]*types.RawBlockWithExtractedData, 0, limit)
+	for number := sequence; number < sequence.AddSaturating(limit); number++ {
+		hash, err := db.ReadBlockHashByNumber(tx, api.shardId(), number)
+		if errors.Is(err, db.ErrKeyNotFound) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		block, err := api.roApi.getBlockByHash(tx, hash, true)
+		if err != nil {
+			return nil, err
+		}
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// readSnapshotBlock reads the block whose state is kept by the node.
+func (api *localShardApiSync) readSnapshotBlock(tx db.RoTx, blockHash common.Hash) (*types.Block, error) {
+	block, err := db.ReadBlock(tx, api.shardId(), blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.roApi.checkStateRetained(tx, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
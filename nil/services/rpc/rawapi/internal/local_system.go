@@ -9,6 +9,7 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 )
 
 func (api *localShardApiRo) GasPrice(ctx context.Context) (types.Value, error) {
@@ -57,3 +58,135 @@ func (api *localShardApiRo) GetNumShards(ctx context.Context) (uint64, error) {
 	}
 	return uint64(len(shards) + 1), nil
 }
+
+// resolveShard returns the shard of the address, the shard ID is encoded in its first bytes.
+func resolveShard(address types.Address, numShards uint64) (types.ShardId, error) {
+	if address.IsEmpty() {
+		return 0, rawapitypes.NewInvalidArgumentError(errors.New("address is empty"))
+	}
+	shardId := address.ShardId()
+	if uint64(shardId) >= numShards {
+		return 0, rawapitypes.NewInvalidArgumentError(
+			fmt.Errorf("shard %d of address %s is not in the network of %d shards", shardId, address, numShards))
+	}
+	return shardId, nil
+}
+
+func (api *localShardApiRo) ResolveShard(ctx context.Context, address types.Address) (types.ShardId, error) {
+	numShards, err := api.GetNumShards(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return resolveShard(address, numShards)
+}
+
+func (api *localShardApiRo) ResolveShards(
+	ctx context.Context, addresses []types.Address,
+) ([]*rawapitypes.ShardResolution, error) {
+	numShards, err := api.GetNumShards(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolutions := make([]*rawapitypes.ShardResolution, len(addresses))
+	for i, address := range addresses {
+		resolutions[i] = &rawapitypes.ShardResolution{Address: address}
+		resolutions[i].ShardId, resolutions[i].Error = resolveShard(address, numShards)
+	}
+	return resolutions, nil
+}
+
+// GetChainConfig reads the parameters of the shards from the config of the latest block of the main shard.
+func (api *localShardApiRo) GetChainConfig(ctx context.Context) (*rawapitypes.ChainConfig, error) {
+	if api.shardId() != types.MainShardId {
+		return nil, errors.New("GetChainConfig is only supported for the main shard")
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	genesisHash, err := db.ReadBlockHashByNumber(tx, types.MainShardId, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read genesis block: %w", err)
+	}
+	block, blockHash, err := db.ReadLastBlock(tx, types.MainShardId)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.NewConfigReader(tx, &blockHash)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open config accessor: %w", err)
+	}
+	gasPrice, err := config.GetParamGasPrice(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get gas price: %w", err)
+	}
+	validators, err := config.GetParamValidators(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get validators: %w", err)
+	}
+
+	// The gas prices are set for all the shards, including the main one.
+	shards := make([]rawapitypes.ShardConfig, len(gasPrice.Shards))
+	for i := range gasPrice.Shards {
+		shard := rawapitypes.ShardConfig{
+			Id:       types.ShardId(i),
+			GasPrice: types.Value{Uint256: &gasPrice.Shards[i]},
+		}
+		if shard.Id.IsMainShard() {
+			shard.Validators = countDistinctValidators(validators.Validators)
+		} else if i-1 < len(validators.Validators) {
+			shard.Validators = uint64(len(validators.Validators[i-1].List))
+		}
+		shards[i] = shard
+	}
+
+	return &rawapitypes.ChainConfig{
+		ChainId:         types.DefaultChainId,
+		ProtocolVersion: api.protocolVersion,
+		GenesisHash:     genesisHash,
+		PatchLevel:      block.PatchLevel,
+		Shards:          shards,
+	}, nil
+}
+
+// countDistinctValidators counts the validators of all the shards, the ones validating several shards are counted once.
+func countDistinctValidators(lists []config.ListValidators) uint64 {
+	keys := make(map[config.Pubkey]struct{})
+	for _, list := range lists {
+		for _, validator := range list.List {
+			keys[validator.PublicKey] = struct{}{}
+		}
+	}
+	return uint64(len(keys))
+}
+
+// GetSyncStatus compares the last block of the shard with the highest one received from the peers.
+func (api *localShardApiRo) GetSyncStatus(ctx context.Context) (*rawapitypes.SyncStatus, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status := &rawapitypes.SyncStatus{}
+	block, _, err := db.ReadLastBlock(tx, api.shardId())
+	switch {
+	case err == nil:
+		status.CurrentBlock = block.Id
+	case !errors.Is(err, db.ErrKeyNotFound):
+		return nil, err
+	}
+
+	if api.syncProgress != nil {
+		progress := api.syncProgress.SyncProgress()
+		status.Phase = progress.Phase
+		status.HighestKnownBlock = progress.HighestKnownBlock
+		status.Peers = progress.Peers
+	}
+	status.HighestKnownBlock = max(status.HighestKnownBlock, status.CurrentBlock)
+	return status, nil
+}
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetChainConfig(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	// The config of the latest main block is read, the genesis hash is the one of the zero state.
+	genesis := execution.GenerateZeroState(t, types.MainShardId, database).Hash(types.MainShardId)
+	latest := execution.GenerateBlockFromTransactions(t, types.MainShardId, 1, genesis, database, nil)
+	require.NotEqual(t, genesis, latest)
+
+	api := newLocalShardApiRo(types.MainShardId, database)
+	api.protocolVersion = "/nil/1.0"
+	chainConfig, err := api.GetChainConfig(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, types.DefaultChainId, chainConfig.ChainId)
+	require.Equal(t, "/nil/1.0", chainConfig.ProtocolVersion)
+	require.Equal(t, genesis, chainConfig.GenesisHash)
+
+	// The test zero state sets the gas prices of three shards and no validators.
+	require.Len(t, chainConfig.Shards, 3)
+	for i, shard := range chainConfig.Shards {
+		require.Equal(t, types.ShardId(i), shard.Id)
+		require.Equal(t, types.NewValueFromUint64(10), shard.GasPrice)
+		require.Zero(t, shard.Validators)
+	}
+
+	// The config is only stored in the main shard.
+	_, err = newLocalShardApiRo(types.BaseShardId, database).GetChainConfig(t.Context())
+	require.Error(t, err)
+}
+
+func TestCountDistinctValidators(t *testing.T) {
+	t.Parallel()
+
+	newValidator := func(key byte) config.ValidatorInfo {
+		return config.ValidatorInfo{PublicKey: config.Pubkey{key}}
+	}
+
+	require.Zero(t, countDistinctValidators(nil))
+
+	// The validators of several shards are counted once.
+	require.EqualValues(t, 3, countDistinctValidators([]config.ListValidators{
+		{List: []config.ValidatorInfo{newValidator(1), newValidator(2)}},
+		{List: []config.ValidatorInfo{newValidator(2), newValidator(3)}},
+		{},
+	}))
+}
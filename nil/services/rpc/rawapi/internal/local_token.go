@@ -0,0 +1,163 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/abi"
+	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+)
+
+// tokenGetterGas is the gas limit of each call of the getters of the token contracts, which only read the storage.
+const tokenGetterGas = 1_000_000
+
+var errTokenNotFound = fmt.Errorf("token %w", rawapitypes.ErrNotFound)
+
+// GetTokenInfo calls the getters of NilTokenBase of the contract minting the token, whose address is the ID
+// of the token. The token is not found if the contract doesn't implement them.
+func (api *localShardApiRo) GetTokenInfo(
+	ctx context.Context,
+	tokenId types.TokenId,
+	mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.TokenInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	return api.readTokenInfo(ctx, tx, tokenId, mainBlockReference)
+}
+
+// ListTokensCreatedBy returns the token of the account if it has been minted or named. A token can only be
+// minted by the contract whose address is its ID, so an account creates at most one token.
+func (api *localShardApiRo) ListTokensCreatedBy(
+	ctx context.Context,
+	address types.Address,
+	mainBlockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.TokenInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	info, err := api.readTokenInfo(ctx, tx, types.TokenId(address), mainBlockReference)
+	if errors.Is(err, errTokenNotFound) {
+		return []*rawapitypes.TokenInfo{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Each smart account inherits NilTokenBase, so only the tokens in use are reported.
+	if info.TotalSupply.IsZero() && info.Name == "" {
+		return []*rawapitypes.TokenInfo{}, nil
+	}
+	return []*rawapitypes.TokenInfo{info}, nil
+}
+
+// readTokenInfo calls the getters in the same execution state, so the block is resolved and loaded once.
+func (api *localShardApiRo) readTokenInfo(
+	ctx context.Context,
+	tx db.RoTx,
+	tokenId types.TokenId,
+	mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.TokenInfo, error) {
+	owner := types.Address(tokenId)
+	if owner.ShardId() != api.shardId() {
+		return nil, fmt.Errorf("%w: token is not in the shard %d", rawapitypes.ErrShardMismatch, api.shard)
+	}
+
+	tokenAbi, err := contracts.GetAbi(contracts.NameNilTokenBase)
+	if err != nil {
+		return nil, err
+	}
+	call, err := api.newCallExecution(
+		ctx, tx, rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(mainBlockReference), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	totalSupply, err := callTokenGetter[*big.Int](ctx, call, tokenAbi, owner, "getTokenTotalSupply")
+	if err != nil {
+		return nil, err
+	}
+	name, err := callTokenGetter[string](ctx, call, tokenAbi, owner, "getTokenName")
+	if err != nil {
+		return nil, err
+	}
+
+	value, overflow := types.NewValueFromBig(totalSupply)
+	if overflow {
+		return nil, fmt.Errorf("total supply %s of token %s overflows", totalSupply, tokenId)
+	}
+	return &rawapitypes.TokenInfo{
+		Id:          tokenId,
+		Name:        name,
+		Symbol:      types.GetTokenName(tokenId),
+		Decimals:    tokenDecimals(tokenId),
+		Owner:       owner,
+		TotalSupply: value,
+	}, nil
+}
+
+// tokenDecimals returns the decimals the well-known tokens are denominated in, as their counterparts outside
+// of the chain are. The rest of the tokens are indivisible.
+func tokenDecimals(tokenId types.TokenId) uint8 {
+	switch types.Address(tokenId) {
+	case types.FaucetAddress, types.EthFaucetAddress:
+		return 18
+	case types.UsdtFaucetAddress, types.UsdcFaucetAddress:
+		return 6
+	case types.BtcFaucetAddress:
+		return 8
+	}
+	return 0
+}
+
+// callTokenGetter calls the getter of the token contract without arguments and returns its single result.
+func callTokenGetter[T any](
+	ctx context.Context,
+	call *callExecution,
+	tokenAbi *abi.ABI,
+	address types.Address,
+	method string,
+) (T, error) {
+	var result T
+	data, err := tokenAbi.Pack(method)
+	if err != nil {
+		return result, err
+	}
+	args := rpctypes.CallArgs{
+		To:   address,
+		Data: (*hexutil.Bytes)(&data),
+		Fee:  types.NewFeePackFromGas(tokenGetterGas),
+	}
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return result, err
+	}
+	if err := call.handle(ctx, args, txn, nil); err != nil {
+		return result, err
+	}
+	if call.result.Failed() {
+		return result, errTokenNotFound
+	}
+
+	// The contracts without the getter, e.g., the accounts without code, return no data.
+	values, err := tokenAbi.Unpack(method, call.result.ReturnData)
+	if err != nil || len(values) != 1 {
+		return result, errTokenNotFound
+	}
+	result, ok := values[0].(T)
+	if !ok {
+		return result, errTokenNotFound
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTokenInfo(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	// The faucets of the tokens are deployed to the base shard included in the latest main block.
+	mainBlock := execution.GenerateZeroState(t, types.MainShardId, database).Hash(types.MainShardId)
+	baseBlock := execution.GenerateZeroState(t, types.BaseShardId, database).Hash(types.BaseShardId)
+	execution.GenerateBlockFromTransactions(t, types.MainShardId, 1, mainBlock, database,
+		map[types.ShardId]common.Hash{types.BaseShardId: baseBlock})
+	api := NodeApiBuilder(database, nil).
+		WithLocalShardApiRo(types.MainShardId).
+		WithLocalShardApiRo(types.BaseShardId).
+		BuildAndReset()
+
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+
+	t.Run("WellKnown", func(t *testing.T) {
+		t.Parallel()
+
+		info, err := api.GetTokenInfo(t.Context(), types.TokenId(types.UsdtFaucetAddress), latest)
+		require.NoError(t, err)
+		require.Equal(t, "USDT", info.Symbol)
+		require.EqualValues(t, 6, info.Decimals)
+		require.Equal(t, types.UsdtFaucetAddress, info.Owner)
+		require.True(t, info.TotalSupply.IsZero())
+
+		info, err = api.GetTokenInfo(t.Context(), types.TokenId(types.EthFaucetAddress), latest)
+		require.NoError(t, err)
+		require.Equal(t, "ETH", info.Symbol)
+		require.EqualValues(t, 18, info.Decimals)
+	})
+
+	t.Run("Unused", func(t *testing.T) {
+		t.Parallel()
+
+		// The smart account implements the token of its own, which is reported only once it's minted or named.
+		info, err := api.GetTokenInfo(t.Context(), types.TokenId(types.MainSmartAccountAddress), latest)
+		require.NoError(t, err)
+		require.Empty(t, info.Symbol)
+		require.Zero(t, info.Decimals)
+
+		tokens, err := api.ListTokensCreatedBy(t.Context(), types.MainSmartAccountAddress, latest)
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		// The account without code doesn't implement the getters.
+		address := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+		_, err := api.GetTokenInfo(t.Context(), types.TokenId(address), latest)
+		require.ErrorIs(t, err, rawapitypes.ErrNotFound)
+
+		tokens, err := api.ListTokensCreatedBy(t.Context(), address, latest)
+		require.NoError(t, err)
+		require.Empty(t, tokens)
+	})
+}
@@ -74,8 +74,10 @@ func (api *localShardApiRo) getInTransactionByBlockHashAndIndex(
 	}, nil
 }
 
-func (api *localShardApiRo) fetchBlockByRef(tx db.RoTx, blockRef rawapitypes.BlockReference) (*types.Block, error) {
-	hash, err := api.getBlockHashByReference(tx, blockRef)
+func (api *localShardApiRo) fetchBlockByRef(
+	ctx context.Context, tx db.RoTx, blockRef rawapitypes.BlockReference,
+) (*types.Block, error) {
+	hash, err := api.getBlockHashByReference(ctx, tx, blockRef)
 	if err != nil {
 		return nil, err
 	}
@@ -88,9 +90,9 @@ func (api *localShardApiRo) fetchBlockByRef(tx db.RoTx, blockRef rawapitypes.Blo
 }
 
 func (api *localShardApiRo) getInTransactionByBlockRefAndIndex(
-	tx db.RoTx, blockRef rawapitypes.BlockReference, index types.TransactionIndex,
+	ctx context.Context, tx db.RoTx, blockRef rawapitypes.BlockReference, index types.TransactionIndex,
 ) (*rawapitypes.TransactionInfo, error) {
-	block, err := api.fetchBlockByRef(tx, blockRef)
+	block, err := api.fetchBlockByRef(ctx, tx, blockRef)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +103,14 @@ func (api *localShardApiRo) GetInTransaction(
 	ctx context.Context,
 	request rawapitypes.TransactionRequest,
 ) (*rawapitypes.TransactionInfo, error) {
+	var blockRef rawapitypes.BlockReference
+	if request.ByHash == nil {
+		var err error
+		if blockRef, err = api.resolveFinalizedBlock(ctx, request.ByBlockRefAndIndex.BlockRef); err != nil {
+			return nil, err
+		}
+	}
+
 	tx, err := api.db.CreateRoTx(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create transaction: %w", err)
@@ -110,6 +120,123 @@ func (api *localShardApiRo) GetInTransaction(
 	if request.ByHash != nil {
 		return api.getTransactionByHash(tx, request.ByHash.Hash)
 	}
-	return api.getInTransactionByBlockRefAndIndex(
-		tx, request.ByBlockRefAndIndex.BlockRef, request.ByBlockRefAndIndex.Index)
+	return api.getInTransactionByBlockRefAndIndex(ctx, tx, blockRef, request.ByBlockRefAndIndex.Index)
+}
+
+// GetInTransactionByIndex returns the incoming transaction of the block at the index,
+// which is expected to be below the GetBlockTransactionCount result.
+func (api *localShardApiRo) GetInTransactionByIndex(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+	index types.TransactionIndex,
+) (*rawapitypes.TransactionInfo, error) {
+	return api.GetInTransaction(ctx, rawapitypes.TransactionRequest{
+		ByBlockRefAndIndex: &rawapitypes.TransactionRequestByBlockRefAndIndex{BlockRef: blockReference, Index: index},
+	})
+}
+
+func (api *localShardApiRo) getOutTransactionByHash(
+	tx db.RoTx, hash common.Hash,
+) (*rawapitypes.TransactionInfo, error) {
+	data, err := api.accessor.Access(tx, api.shardId()).GetOutTransaction().ByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionSSZ, err := data.Transaction().MarshalSSZ()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction: %w", err)
+	}
+
+	block := data.Block()
+	return &rawapitypes.TransactionInfo{
+		TransactionSSZ: transactionSSZ,
+		Index:          data.Index(),
+		BlockHash:      block.Hash(api.shardId()),
+		BlockId:        block.Id,
+	}, nil
+}
+
+func (api *localShardApiRo) getOutTransactionByBlockRefAndIndex(
+	ctx context.Context, tx db.RoTx, blockRef rawapitypes.BlockReference, index types.TransactionIndex,
+) (*rawapitypes.TransactionInfo, error) {
+	block, err := api.fetchBlockByRef(ctx, tx, blockRef)
+	if err != nil {
+		return nil, err
+	}
+
+	rawTxn, err := getRawBlockEntity(
+		tx, api.shardId(), db.TransactionTrieTable, block.OutTransactionsRoot, index.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	return &rawapitypes.TransactionInfo{
+		TransactionSSZ: rawTxn,
+		Index:          index,
+		BlockHash:      block.Hash(api.shardId()),
+		BlockId:        block.Id,
+	}, nil
+}
+
+// GetOutTransaction returns a transaction sent from the shard. Outgoing transactions have no receipts in the
+// source shard, the receipt can be obtained from the shard of the destination address.
+func (api *localShardApiRo) GetOutTransaction(
+	ctx context.Context,
+	request rawapitypes.TransactionRequest,
+) (*rawapitypes.TransactionInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if request.ByHash != nil {
+		return api.getOutTransactionByHash(tx, request.ByHash.Hash)
+	}
+	return api.getOutTransactionByBlockRefAndIndex(
+		ctx, tx, request.ByBlockRefAndIndex.BlockRef, request.ByBlockRefAndIndex.Index)
+}
+
+func (api *localShardApiRo) GetOutTransactions(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.TransactionInfo, error) {
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := api.accessor.RawAccess(tx, api.shardId()).GetBlock().WithOutTransactions().ByHash(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var block types.Block
+	if err := block.UnmarshalSSZ(data.Block()); err != nil {
+		return nil, err
+	}
+
+	outTransactions := data.OutTransactions()
+	result := make([]*rawapitypes.TransactionInfo, len(outTransactions))
+	for i, transactionSSZ := range outTransactions {
+		result[i] = &rawapitypes.TransactionInfo{
+			TransactionSSZ: transactionSSZ,
+			Index:          types.TransactionIndex(i),
+			BlockHash:      blockHash,
+			BlockId:        block.Id,
+		}
+	}
+	return result, nil
 }
@@ -0,0 +1,162 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetInTransactionByIndex(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	newTransaction := func(data string) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.Data = types.Code(data)
+		return txn
+	}
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	zeroBlock := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	finalized := execution.GenerateBlockFromTransactionsWithoutExecution(t, shardId, 1, zeroBlock, database,
+		newTransaction("first"), newTransaction("second"))
+	execution.GenerateBlockFromTransactionsWithoutExecution(t, shardId, 2, finalized, database,
+		newTransaction("latest"))
+
+	api := newLocalShardApiRo(shardId, database)
+	api.setNodeApi(mainBlockNodeApi{childBlocks: []common.Hash{finalized}})
+
+	// getData returns the data of the transaction of the block at the index.
+	getData := func(t *testing.T, reference rawapitypes.BlockReference, index types.TransactionIndex) string {
+		t.Helper()
+
+		info, err := api.GetInTransactionByIndex(t.Context(), reference, index)
+		require.NoError(t, err)
+		require.Equal(t, index, info.Index)
+		var txn types.Transaction
+		require.NoError(t, txn.UnmarshalSSZ(info.TransactionSSZ))
+		return string(txn.Data)
+	}
+
+	// The transactions are the same the block and index request to GetInTransaction returns.
+	require.Equal(t, "first", getData(t, rawapitypes.BlockHashAsBlockReference(finalized), 0))
+	require.Equal(t, "second", getData(t, rawapitypes.BlockNumberAsBlockReference(1), 1))
+	require.Equal(t, "latest", getData(t, rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock), 0))
+
+	// The finalized block is resolved by the main shard as well.
+	require.Equal(t, "second",
+		getData(t, rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.FinalizedBlock), 1))
+
+	// The index is checked against the count of the transactions of the block.
+	_, err = api.GetInTransactionByIndex(t.Context(), rawapitypes.BlockNumberAsBlockReference(2), 1)
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+}
+
+// writeBlock writes the block following prevBlock with the changes made to the state of the shard.
+func writeBlock(
+	t *testing.T, database db.DB, shardId types.ShardId, prevBlock common.Hash,
+	change func(es *execution.ExecutionState),
+) common.Hash {
+	t.Helper()
+
+	tx, err := database.CreateRwTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	block, err := db.ReadBlock(tx, shardId, prevBlock)
+	require.NoError(t, err)
+	es, err := execution.NewExecutionState(tx, shardId, execution.StateParams{
+		Block:          block,
+		ConfigAccessor: config.GetStubAccessor(),
+	})
+	require.NoError(t, err)
+	es.BaseFee = types.DefaultGasPrice
+	change(es)
+
+	blockRes, err := es.Commit(block.Id+1, nil)
+	require.NoError(t, err)
+	require.NoError(t, execution.PostprocessBlock(tx, shardId, blockRes, execution.ModeVerify))
+	require.NoError(t, tx.Commit())
+	return blockRes.BlockHash
+}
+
+func TestGetOutTransactions(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.BaseShardId
+	newTransaction := func(data string) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.To = types.ShardAndHexToAddress(types.MainShardId, "0x1234")
+		txn.Data = types.Code(data)
+		return txn
+	}
+	sent := []*types.Transaction{newTransaction("first"), newTransaction("second")}
+	execution.GenerateZeroState(t, types.MainShardId, database)
+	zeroBlock := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	// The block sends the transactions to the other shard.
+	blockHash := writeBlock(t, database, shardId, zeroBlock, func(es *execution.ExecutionState) {
+		for _, txn := range sent {
+			es.AppendForwardTransaction(txn)
+		}
+	})
+
+	api := newLocalShardApiRo(shardId, database)
+
+	// requireSent checks that the transaction is the one sent by the block at the index.
+	requireSent := func(t *testing.T, index types.TransactionIndex, info *rawapitypes.TransactionInfo) {
+		t.Helper()
+
+		require.Equal(t, index, info.Index)
+		require.Equal(t, blockHash, info.BlockHash)
+		require.Equal(t, types.BlockNumber(1), info.BlockId)
+		var txn types.Transaction
+		require.NoError(t, txn.UnmarshalSSZ(info.TransactionSSZ))
+		require.Equal(t, sent[index].Hash(), txn.Hash())
+	}
+
+	infos, err := api.GetOutTransactions(t.Context(), rawapitypes.BlockNumberAsBlockReference(1))
+	require.NoError(t, err)
+	require.Len(t, infos, len(sent))
+	for i, info := range infos {
+		requireSent(t, types.TransactionIndex(i), info)
+	}
+
+	// The transactions are found by the hash and by the index in the block as well.
+	info, err := api.GetOutTransaction(t.Context(), rawapitypes.TransactionRequest{
+		ByHash: &rawapitypes.TransactionRequestByHash{Hash: sent[1].Hash()},
+	})
+	require.NoError(t, err)
+	requireSent(t, 1, info)
+
+	info, err = api.GetOutTransaction(t.Context(), rawapitypes.TransactionRequest{
+		ByBlockRefAndIndex: &rawapitypes.TransactionRequestByBlockRefAndIndex{
+			BlockRef: rawapitypes.BlockHashAsBlockReference(blockHash),
+			Index:    0,
+		},
+	})
+	require.NoError(t, err)
+	requireSent(t, 0, info)
+
+	// The zero state sends nothing, and the transactions sent are not received by the shard.
+	infos, err = api.GetOutTransactions(t.Context(), rawapitypes.BlockHashAsBlockReference(zeroBlock))
+	require.NoError(t, err)
+	require.Empty(t, infos)
+
+	_, err = api.GetInTransaction(t.Context(), rawapitypes.TransactionRequest{
+		ByHash: &rawapitypes.TransactionRequestByHash{Hash: sent[0].Hash()},
+	})
+	require.ErrorIs(t, err, db.ErrKeyNotFound)
+}
@@ -0,0 +1,169 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+// maxTransactionWatchDuration limits the time a transaction is watched for. The transactions that are neither
+// finalized nor dropped by then, e.g., the ones underpriced for long, are looked up by GetTransactionStatus.
+const maxTransactionWatchDuration = 10 * time.Minute
+
+// SendTransactionAndWatch sends the transaction the same way as SendTransaction, including the handling
+// of the idempotency key, and notifies the sender about its lifecycle. The submitted event comes first,
+// then the included and finalized ones follow, or the dropped one if the transaction is not admitted
+// to the pool or is evicted from it. The channel is closed after the last event, or without it
+// once the transaction has been watched for maxTransactionWatchDuration.
+func (api *localShardApiRw) SendTransactionAndWatch(
+	ctx context.Context, encoded []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string,
+) (<-chan *rawapitypes.TransactionEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, maxTransactionWatchDuration)
+	// The blocks are subscribed to before the transaction is sent, so that the block including it is not missed.
+	newBlocks, err := api.roApi.subscribeNewBlocks(ctx)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	result, err := api.SendTransaction(ctx, encoded, replacement, idempotencyKey)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	events := make(chan *rawapitypes.TransactionEvent, subscriptionBufferSize)
+	events <- &rawapitypes.TransactionEvent{Type: rawapitypes.TransactionEventSubmitted, Result: result}
+
+	if result.DiscardReason != txnpool.NotSet {
+		event := &rawapitypes.TransactionEvent{
+			Type:         rawapitypes.TransactionEventDropped,
+			ErrorMessage: result.DiscardReason.String(),
+		}
+		if !result.Verdict.Passed() {
+			event.ErrorMessage = result.Verdict.Error.Error()
+		}
+		events <- event
+		close(events)
+		cancel()
+		return events, nil
+	}
+
+	go func() {
+		defer close(events)
+		defer cancel()
+		api.watchTransaction(ctx, result.Hash, newBlocks, events)
+	}()
+	return events, nil
+}
+
+// watchTransaction sends the events of the transaction admitted to the pool until it's finalized or dropped.
+// The transaction is checked once a new block is added to the shard, the shards add the blocks regularly
+// even if they are empty.
+func (api *localShardApiRw) watchTransaction(
+	ctx context.Context,
+	hash common.Hash,
+	newBlocks <-chan types.BlockNumber,
+	events chan<- *rawapitypes.TransactionEvent,
+) {
+	send := func(event *rawapitypes.TransactionEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	included := false
+	missing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-newBlocks:
+		}
+
+		event, finalized, err := api.checkIncluded(ctx, hash)
+		if err != nil {
+			api.roApi.logger.Warn().Err(err).Msg("Failed to check the watched transaction")
+			continue
+		}
+		if event != nil {
+			if !included {
+				included = true
+				if !send(event) {
+					return
+				}
+			}
+			if finalized {
+				send(&rawapitypes.TransactionEvent{
+					Type:        rawapitypes.TransactionEventFinalized,
+					BlockNumber: event.BlockNumber,
+					BlockHash:   event.BlockHash,
+				})
+				return
+			}
+			continue
+		}
+
+		if included {
+			continue
+		}
+		if inPool, _ := api.txnpool.IsQueued(hash); inPool {
+			missing = false
+			continue
+		}
+		// The transaction is removed from the pool after the block with it is written,
+		// so it's only considered dropped if it's still not in a block on the next check.
+		if !missing {
+			missing = true
+			continue
+		}
+
+		event = &rawapitypes.TransactionEvent{Type: rawapitypes.TransactionEventDropped}
+		if receiptWithError, ok := execution.FailureReceiptCache.Get(hash); ok {
+			event.ErrorMessage = receiptWithError.Error.Error()
+		}
+		send(event)
+		return
+	}
+}
+
+// checkIncluded returns the included event if the transaction is in a block of the shard
+// and whether the block is finalized.
+func (api *localShardApiRw) checkIncluded(
+	ctx context.Context, hash common.Hash,
+) (*rawapitypes.TransactionEvent, bool, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	block, receipt, err := api.getIncludedTransaction(tx, hash)
+	if err != nil || block == nil {
+		return nil, false, err
+	}
+
+	event := &rawapitypes.TransactionEvent{
+		Type:        rawapitypes.TransactionEventIncluded,
+		BlockNumber: block.Id,
+		BlockHash:   block.Hash(api.shardId()),
+	}
+	if !receipt.Success {
+		if event.ErrorMessage, err = readTransactionError(tx, hash); err != nil {
+			return nil, false, err
+		}
+	}
+
+	finalized, err := api.roApi.isIncludedInMain(ctx, tx, block, "SendTransactionAndWatch")
+	if err != nil {
+		return nil, false, err
+	}
+	return event, finalized, nil
+}
@@ -4,14 +4,366 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"golang.org/x/sync/singleflight"
 )
 
-func (api *localShardApiRw) SendTransaction(ctx context.Context, encoded []byte) (txnpool.DiscardReason, error) {
+const (
+	// idempotencyKeyTtl is the time the result of sending a transaction is returned for the retries
+	// with the same idempotency key.
+	idempotencyKeyTtl = 10 * time.Minute
+
+	maxIdempotencyKeys = 10_000
+
+	// maxIdempotencyKeyLength limits the length of an idempotency key, so that the stored keys are bounded.
+	maxIdempotencyKeyLength = 128
+)
+
+var errIdempotencyKeyReused = rawapitypes.NewInvalidArgumentError(
+	errors.New("idempotency key is already used for another transaction"))
+
+// sentTransactions keeps the results of sending the transactions by the idempotency keys of the clients.
+// The keys are scoped by the requesters, so a requester can't get or occupy the results of another one.
+type sentTransactions struct {
+	results  *expirable.LRU[string, *rawapitypes.SendTransactionResult]
+	inFlight singleflight.Group
+}
+
+// send sends the transaction once for the concurrent calls with the same key, and stores the result if
+// the transaction is added to the pool. The discarded transactions aren't stored, so they can be fixed and retried.
+// The sending isn't canceled with the context of the first call, since the other calls wait for it.
+func (s *sentTransactions) send(
+	ctx context.Context,
+	idempotencyKey string,
+	hash common.Hash,
+	send func(ctx context.Context) (*rawapitypes.SendTransactionResult, error),
+) (*rawapitypes.SendTransactionResult, error) {
+	key := requesterKey(ctx) + "/" + idempotencyKey
+	sendCtx := context.WithoutCancel(ctx)
+	results := s.inFlight.DoChan(key, func() (any, error) {
+		if result, ok := s.results.Get(key); ok {
+			return result, nil
+		}
+		result, err := send(sendCtx)
+		if err != nil {
+			return nil, err
+		}
+		if result.DiscardReason == txnpool.NotSet {
+			s.results.Add(key, result)
+		}
+		return result, nil
+	})
+
+	var res singleflight.Result
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res = <-results:
+	}
+	if res.Err != nil {
+		return nil, res.Err
+	}
+	sent, ok := res.Val.(*rawapitypes.SendTransactionResult)
+	check.PanicIfNot(ok)
+	if sent.Hash != hash {
+		return nil, errIdempotencyKeyReused
+	}
+	return sent, nil
+}
+
+func newSentTransactions() *sentTransactions {
+	return &sentTransactions{
+		results: expirable.NewLRU[string, *rawapitypes.SendTransactionResult](
+			maxIdempotencyKeys, nil, idempotencyKeyTtl),
+	}
+}
+
+// SendTransaction checks the transaction against the latest state of the shard and adds it to the pool.
+// The verdict of the checks is reported in the result. The transaction that fails them is still added to the pool
+// and fails in a block, unless the API rejects the invalid transactions, see WithTransactionPreValidation.
+// A pending transaction with the same seqno is replaced if the fee is bumped enough,
+// unless the policy is NoReplacement.
+// If the idempotency key is set, the result is stored and returned for the retries of the requester with
+// the same key, the concurrent retries wait for the first one. Only the transactions added to the pool are stored,
+// so the failed and the rejected calls can be retried.
+func (api *localShardApiRw) SendTransaction(
+	ctx context.Context, encoded []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string,
+) (*rawapitypes.SendTransactionResult, error) {
+	if api.txnpool == nil {
+		return nil, errTxnPoolNotAvailable
+	}
+
+	var extTxn types.ExternalTransaction
+	if err := extTxn.UnmarshalSSZ(encoded); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	if idempotencyKey == "" {
+		return api.sendTransaction(ctx, &extTxn, replacement)
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return nil, rawapitypes.NewInvalidArgumentError(
+			fmt.Errorf("idempotency key must be at most %d bytes", maxIdempotencyKeyLength))
+	}
+	return api.sent.send(ctx, idempotencyKey, extTxn.Hash(),
+		func(ctx context.Context) (*rawapitypes.SendTransactionResult, error) {
+			return api.sendTransaction(ctx, &extTxn, replacement)
+		})
+}
+
+func (api *localShardApiRw) sendTransaction(
+	ctx context.Context, extTxn *types.ExternalTransaction, replacement txnpool.ReplacementPolicy,
+) (*rawapitypes.SendTransactionResult, error) {
+	txn := extTxn.ToTransaction()
+
+	result := &rawapitypes.SendTransactionResult{
+		Hash:    extTxn.Hash(),
+		ShardId: extTxn.To.ShardId(),
+	}
+	verdict, err := api.validateTransaction(ctx, txn, types.NewZeroValue())
+	if err != nil {
+		return nil, err
+	}
+	result.Verdict = verdict
+	if !verdict.Passed() && api.rejectInvalid {
+		result.DiscardReason = verdict.DiscardReason()
+		return result, nil
+	}
+
+	if replacement == txnpool.NoReplacement {
+		result.DiscardReason, err = api.txnpool.AddWithoutReplacement(ctx, txn)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	reasons, err := api.txnpool.Add(ctx, txn)
+	if err != nil {
+		return nil, err
+	}
+	result.DiscardReason = reasons[0]
+	return result, nil
+}
+
+// validateTransaction does the checks of the block generator, see execution.ValidateExternalTransaction,
+// against the latest block of the shard.
+// Unlike the block generator, it accepts a seqno ahead of the account, since such a transaction waits
+// in the pool for the preceding ones, and doesn't check the fee against the base fee, which may go down.
+// The balance must also cover the spent value, e.g., by the preceding transactions of the same bundle.
+func (api *localShardApiRw) validateTransaction(
+	ctx context.Context, txn *types.Transaction, spent types.Value,
+) (rawapitypes.TransactionVerdict, error) {
+	fail := func(check rawapitypes.TransactionCheck, err types.ExecError) (rawapitypes.TransactionVerdict, error) {
+		return rawapitypes.TransactionVerdict{FailedCheck: check, Error: err}, nil
+	}
+
+	if verdict := api.validateTransactionFields(txn); !verdict.Passed() {
+		return verdict, nil
+	}
+
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return rawapitypes.TransactionVerdict{}, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, _, err := db.ReadLastBlock(tx, api.shardId())
+	if err != nil {
+		return rawapitypes.TransactionVerdict{}, fmt.Errorf("failed to read the latest block: %w", err)
+	}
+	configAccessor, err := config.NewConfigAccessorFromBlockWithTx(tx, block, api.shardId())
+	if err != nil {
+		return rawapitypes.TransactionVerdict{}, fmt.Errorf("failed to create config accessor: %w", err)
+	}
+	es, err := execution.NewExecutionState(tx, api.shardId(), execution.StateParams{
+		Block:          block,
+		ConfigAccessor: configAccessor,
+		Mode:           execution.ModeReadOnly,
+	})
+	if err != nil {
+		return rawapitypes.TransactionVerdict{}, err
+	}
+	es.BaseFee = block.BaseFee
+
+	account, err := es.GetAccount(txn.To)
+	if err != nil {
+		return rawapitypes.TransactionVerdict{}, err
+	}
+
+	res := execution.ValidateExternalTransaction(es, txn)
+	if res.FatalError != nil {
+		return rawapitypes.TransactionVerdict{}, res.FatalError
+	}
+	if res.Failed() {
+		if res.Error.Code() != types.ErrorSeqnoGap || account.ExtSeqno > txn.Seqno {
+			return fail(transactionCheckOf(res.Error), res.Error)
+		}
+		// The seqno is ahead of the account, so the signature is checked the same way as it will be.
+		res = es.CallVerifyExternal(txn, account)
+		if res.FatalError != nil {
+			return rawapitypes.TransactionVerdict{}, res.FatalError
+		}
+		if res.Failed() {
+			return fail(rawapitypes.TransactionCheckSignature, res.Error)
+		}
+	}
+	// The block generator doesn't check the seqnos of the deployments, but the pool does.
+	if account.ExtSeqno > txn.Seqno {
+		return fail(rawapitypes.TransactionCheckSeqno, types.NewVerboseError(types.ErrorSeqnoGap,
+			fmt.Sprintf("account %v > transaction %v", account.ExtSeqno, txn.Seqno)))
+	}
+
+	cost, overflow := txn.Value.AddOverflow(txn.FeeCredit)
+	if !overflow {
+		cost, overflow = cost.AddOverflow(spent)
+	}
+	if overflow || account.Balance.Cmp(cost) < 0 {
+		message := fmt.Sprintf("balance %s < value %s + fee credit %s", account.Balance, txn.Value, txn.FeeCredit)
+		if !spent.IsZero() {
+			message += fmt.Sprintf(" + spent %s", spent)
+		}
+		return fail(rawapitypes.TransactionCheckBalance, types.NewVerboseError(types.ErrorInsufficientFunds, message))
+	}
+	return rawapitypes.TransactionVerdict{}, nil
+}
+
+// transactionCheckOf returns the check failed with the error of the validation of the block generator.
+func transactionCheckOf(err types.ExecError) rawapitypes.TransactionCheck {
+	switch err.Code() {
+	case types.ErrorInvalidChainId:
+		return rawapitypes.TransactionCheckChainId
+	case types.ErrorInvalidPayload, types.ErrorDeployToMainShard, types.ErrorIncorrectDeploymentAddress:
+		return rawapitypes.TransactionCheckDeployPayload
+	case types.ErrorNoAccount, types.ErrorDestinationContractDoesNotExist,
+		types.ErrorContractAlreadyExists, types.ErrorContractDoesNotExist:
+		return rawapitypes.TransactionCheckAccount
+	case types.ErrorSeqnoGap:
+		return rawapitypes.TransactionCheckSeqno
+	case types.ErrorMaxFeePerGasIsZero, types.ErrorRefundTransactionIsNotAllowedInExternalTransactions:
+		return rawapitypes.TransactionCheckFields
+	}
+	return rawapitypes.TransactionCheckSignature
+}
+
+// validateTransactionFields does the checks that don't depend on the state of the shard.
+func (api *localShardApiRw) validateTransactionFields(txn *types.Transaction) rawapitypes.TransactionVerdict {
+	fail := func(check rawapitypes.TransactionCheck, err types.ExecError) rawapitypes.TransactionVerdict {
+		return rawapitypes.TransactionVerdict{FailedCheck: check, Error: err}
+	}
+
+	if shardId := txn.To.ShardId(); shardId != api.shardId() {
+		return fail(rawapitypes.TransactionCheckShard, types.NewVerboseError(types.ErrorCrossShardTransaction,
+			fmt.Sprintf("transaction to shard %d is sent to shard %d", shardId, api.shardId())))
+	}
+	if txn.ChainId != types.DefaultChainId {
+		return fail(rawapitypes.TransactionCheckChainId, types.NewError(types.ErrorInvalidChainId))
+	}
+	if txn.IsDeploy() {
+		if err := execution.ValidateDeployTransaction(txn); err != nil {
+			return fail(rawapitypes.TransactionCheckDeployPayload, err)
+		}
+	}
+	return rawapitypes.TransactionVerdict{}
+}
+
+// SendTransactionBundle checks the transactions the same way as SendTransaction and adds either all of them
+// to the pool or none of them. If some of them fail the checks and the API rejects the invalid transactions,
+// the rest are discarded as BundleRejected.
+// The transactions to the contracts deployed by the preceding ones of the bundle are only checked
+// by their fields, since the state of the shard doesn't have the contracts yet. The balance of an account
+// must cover all the transactions of the bundle paid by it.
+func (api *localShardApiRw) SendTransactionBundle(
+	ctx context.Context, encoded [][]byte, sameBlock bool,
+) ([]*rawapitypes.SendTransactionResult, error) {
+	if api.txnpool == nil {
+		return nil, errTxnPoolNotAvailable
+	}
+	if len(encoded) == 0 {
+		return nil, rawapitypes.NewInvalidArgumentError(errors.New("bundle is empty"))
+	}
+	if len(encoded) > txnpool.MaxBundleSize {
+		return nil, rawapitypes.NewInvalidArgumentError(
+			fmt.Errorf("bundle must contain at most %d transactions", txnpool.MaxBundleSize))
+	}
+
+	txns := make([]*types.Transaction, len(encoded))
+	results := make([]*rawapitypes.SendTransactionResult, len(encoded))
+	deployed := make(map[types.Address]struct{})
+	spent := make(map[types.Address]types.Value)
+	rejected := false
+	for i, data := range encoded {
+		var extTxn types.ExternalTransaction
+		if err := extTxn.UnmarshalSSZ(data); err != nil {
+			return nil, fmt.Errorf("failed to decode transaction %d: %w", i, err)
+		}
+		txns[i] = extTxn.ToTransaction()
+		results[i] = &rawapitypes.SendTransactionResult{
+			Hash:    extTxn.Hash(),
+			ShardId: extTxn.To.ShardId(),
+		}
+
+		var verdict rawapitypes.TransactionVerdict
+		if _, ok := deployed[txns[i].To]; ok {
+			verdict = api.validateTransactionFields(txns[i])
+		} else {
+			var err error
+			if verdict, err = api.validateTransaction(ctx, txns[i], spent[txns[i].To]); err != nil {
+				return nil, err
+			}
+			// The sum doesn't overflow once the balance covers it.
+			if verdict.Passed() {
+				spent[txns[i].To] = spent[txns[i].To].Add(txns[i].Value).Add(txns[i].FeeCredit)
+			}
+		}
+		if txns[i].IsDeploy() {
+			deployed[txns[i].To] = struct{}{}
+		}
+
+		results[i].Verdict = verdict
+		if !verdict.Passed() && api.rejectInvalid {
+			results[i].DiscardReason = verdict.DiscardReason()
+			rejected = true
+		}
+	}
+
+	if rejected {
+		for _, result := range results {
+			if result.Verdict.Passed() {
+				result.DiscardReason = txnpool.BundleRejected
+			}
+		}
+		return results, nil
+	}
+
+	reasons, err := api.txnpool.AddBundle(ctx, txns, sameBlock)
+	if err != nil {
+		if errors.Is(err, txnpool.ErrInvalidBundle) {
+			return nil, rawapitypes.NewInvalidArgumentError(err)
+		}
+		return nil, err
+	}
+	for i, reason := range reasons {
+		results[i].DiscardReason = reason
+	}
+	return results, nil
+}
+
+// ResendTransaction adds the transaction to the pool the same way as SendTransaction. If the pool already contains
+// the transaction, it is published to the network again instead of being discarded as a duplicate.
+func (api *localShardApiRw) ResendTransaction(
+	ctx context.Context, encoded []byte, replacement txnpool.ReplacementPolicy,
+) (txnpool.DiscardReason, error) {
 	if api.txnpool == nil {
-		return 0, errors.New("transaction pool is not available")
+		return 0, errTxnPoolNotAvailable
 	}
 
 	var extTxn types.ExternalTransaction
@@ -19,11 +371,94 @@ func (api *localShardApiRw) SendTransaction(ctx context.Context, encoded []byte)
 		return 0, fmt.Errorf("failed to decode transaction: %w", err)
 	}
 
-	reasons, err := api.txnpool.Add(ctx, extTxn.ToTransaction())
+	return api.txnpool.Resend(ctx, extTxn.ToTransaction(), replacement)
+}
+
+// GetTransactionStatus looks up the incoming transaction in the blocks of the shard first and then in the pool.
+// The transactions that failed the validation are only known until they are evicted from the failure receipt cache.
+func (api *localShardApiRw) GetTransactionStatus(
+	ctx context.Context,
+	hash common.Hash,
+) (*rawapitypes.TransactionStatusInfo, error) {
+	tx, err := api.roApi.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, receipt, err := api.getIncludedTransaction(tx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	if block != nil {
+		if !receipt.Success {
+			errMsg, err := readTransactionError(tx, hash)
+			if err != nil {
+				return nil, err
+			}
+			return &rawapitypes.TransactionStatusInfo{
+				Status:       rawapitypes.TransactionStatusFailed,
+				ErrorMessage: errMsg,
+			}, nil
+		}
+
+		includedInMain, err := api.roApi.isIncludedInMain(ctx, tx, block, methodNameChecked("GetTransactionStatus"))
+		if err != nil {
+			return nil, err
+		}
+		if includedInMain {
+			return &rawapitypes.TransactionStatusInfo{Status: rawapitypes.TransactionStatusFinalized}, nil
+		}
+		return &rawapitypes.TransactionStatusInfo{Status: rawapitypes.TransactionStatusIncluded}, nil
+	}
+
+	if api.txnpool != nil {
+		if inPool, queued := api.txnpool.IsQueued(hash); inPool {
+			if queued {
+				return &rawapitypes.TransactionStatusInfo{Status: rawapitypes.TransactionStatusQueued}, nil
+			}
+			return &rawapitypes.TransactionStatusInfo{Status: rawapitypes.TransactionStatusPending}, nil
+		}
+	}
+
+	if receiptWithError, ok := execution.FailureReceiptCache.Get(hash); ok {
+		return &rawapitypes.TransactionStatusInfo{
+			Status:       rawapitypes.TransactionStatusFailed,
+			ErrorMessage: receiptWithError.Error.Error(),
+		}, nil
+	}
+	return &rawapitypes.TransactionStatusInfo{Status: rawapitypes.TransactionStatusUnknown}, nil
+}
+
+// getIncludedTransaction returns the block with the incoming transaction and its receipt.
+// The block is nil if the transaction is not included in the shard.
+func (api *localShardApiRw) getIncludedTransaction(
+	tx db.RoTx, hash common.Hash,
+) (*types.Block, *types.Receipt, error) {
+	block, indexes, err := api.roApi.getBlockAndInTransactionIndexByTransactionHash(tx, api.shardId(), hash)
+	if errors.Is(err, db.ErrKeyNotFound) || (err == nil && block == nil) {
+		return nil, nil, nil
+	}
 	if err != nil {
-		return 0, err
+		return nil, nil, err
 	}
-	return reasons[0], nil
+
+	receipt, err := getBlockEntity[*types.Receipt](
+		tx, api.shardId(), db.ReceiptTrieTable, block.ReceiptsRoot, indexes.TransactionIndex.Bytes())
+	if err != nil {
+		return nil, nil, err
+	}
+	return block, receipt, nil
+}
+
+// readTransactionError returns the error message of the failed transaction, it's empty if it's not stored.
+func readTransactionError(tx db.RoTx, hash common.Hash) (string, error) {
+	errMsg, err := db.ReadError(tx, hash)
+	if err != nil && !errors.Is(err, db.ErrKeyNotFound) {
+		return "", err
+	}
+	return errMsg, nil
 }
 
 func (api *localShardApiRw) GetTxpoolStatus(ctx context.Context) (uint64, error) {
@@ -41,3 +476,34 @@ func (api *localShardApiRw) GetTxpoolContent(ctx context.Context) ([]*types.Tran
 	}
 	return res, nil
 }
+
+func (api *localShardApiRw) SubscribePendingTransactions(
+	ctx context.Context,
+	fullTransactions bool,
+) (<-chan *rawapitypes.PendingTransaction, error) {
+	if api.txnpool == nil {
+		return nil, errTxnPoolNotAvailable
+	}
+
+	txns := api.txnpool.SubscribeNewTransactions(ctx, subscriptionBufferSize)
+	pending := make(chan *rawapitypes.PendingTransaction, subscriptionBufferSize)
+	go func() {
+		defer close(pending)
+		for txn := range txns {
+			event := &rawapitypes.PendingTransaction{Hash: txn.Hash()}
+			if fullTransactions {
+				var err error
+				if event.TransactionSSZ, err = txn.MarshalSSZ(); err != nil {
+					api.roApi.logger.Error().Err(err).Msg("Failed to marshal pending transaction")
+					return
+				}
+			}
+			select {
+			case pending <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return pending, nil
+}
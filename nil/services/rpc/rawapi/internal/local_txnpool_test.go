@@ -0,0 +1,242 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentTransactions(t *testing.T) {
+	t.Parallel()
+
+	hash := common.HexToHash("0x1234")
+	peerCtx := func(ctx context.Context, peerId network.PeerID) context.Context {
+		return network.WithRequestPeer(ctx, peerId)
+	}
+	// sender counts the calls and returns the result with the discard reason.
+	sender := func(calls *atomic.Int32, reason txnpool.DiscardReason) func(
+		context.Context,
+	) (*rawapitypes.SendTransactionResult, error) {
+		return func(context.Context) (*rawapitypes.SendTransactionResult, error) {
+			calls.Add(1)
+			return &rawapitypes.SendTransactionResult{Hash: hash, DiscardReason: reason}, nil
+		}
+	}
+
+	t.Run("Retried", func(t *testing.T) {
+		t.Parallel()
+
+		sent := newSentTransactions()
+		var calls atomic.Int32
+		ctx := peerCtx(t.Context(), "peer-a")
+		for range 2 {
+			result, err := sent.send(ctx, "key", hash, sender(&calls, txnpool.NotSet))
+			require.NoError(t, err)
+			require.Equal(t, hash, result.Hash)
+		}
+		require.Equal(t, int32(1), calls.Load())
+
+		_, err := sent.send(ctx, "key", common.HexToHash("0x5678"), sender(&calls, txnpool.NotSet))
+		require.ErrorIs(t, err, errIdempotencyKeyReused)
+	})
+
+	t.Run("ScopedByRequester", func(t *testing.T) {
+		t.Parallel()
+
+		sent := newSentTransactions()
+		var calls atomic.Int32
+		_, err := sent.send(peerCtx(t.Context(), "peer-a"), "key", hash, sender(&calls, txnpool.NotSet))
+		require.NoError(t, err)
+
+		// The key of another peer doesn't return the result, nor is it taken by the transaction.
+		other := common.HexToHash("0x5678")
+		result, err := sent.send(peerCtx(t.Context(), "peer-b"), "key", other,
+			func(context.Context) (*rawapitypes.SendTransactionResult, error) {
+				calls.Add(1)
+				return &rawapitypes.SendTransactionResult{Hash: other}, nil
+			})
+		require.NoError(t, err)
+		require.Equal(t, other, result.Hash)
+		require.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("RejectedNotStored", func(t *testing.T) {
+		t.Parallel()
+
+		sent := newSentTransactions()
+		var calls atomic.Int32
+		ctx := peerCtx(t.Context(), "peer-a")
+		result, err := sent.send(ctx, "key", hash, sender(&calls, txnpool.SeqnoTooLow))
+		require.NoError(t, err)
+		require.Equal(t, txnpool.SeqnoTooLow, result.DiscardReason)
+
+		result, err = sent.send(ctx, "key", hash, sender(&calls, txnpool.NotSet))
+		require.NoError(t, err)
+		require.Equal(t, txnpool.NotSet, result.DiscardReason)
+		require.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("FirstCallCanceled", func(t *testing.T) {
+		t.Parallel()
+
+		sent := newSentTransactions()
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var start sync.Once
+		send := func(ctx context.Context) (*rawapitypes.SendTransactionResult, error) {
+			start.Do(func() { close(started) })
+			<-release
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			return &rawapitypes.SendTransactionResult{Hash: hash}, nil
+		}
+
+		ctx, cancel := context.WithCancel(peerCtx(t.Context(), "peer-a"))
+		first := make(chan error, 1)
+		go func() {
+			_, err := sent.send(ctx, "key", hash, send)
+			first <- err
+		}()
+		<-started
+
+		second := make(chan error, 1)
+		go func() {
+			_, err := sent.send(peerCtx(t.Context(), "peer-a"), "key", hash, send)
+			second <- err
+		}()
+
+		// The first call returns once its context is canceled, the sending goes on for the second one.
+		cancel()
+		require.ErrorIs(t, <-first, context.Canceled)
+		close(release)
+		require.NoError(t, <-second)
+	})
+}
+
+func TestValidateTransaction(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+	execution.GenerateZeroState(t, types.MainShardId, database)
+
+	api := newLocalShardApiRw(newLocalShardApiRo(types.MainShardId, database), nil)
+	to := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+	validate := func(t *testing.T, txn *types.Transaction) rawapitypes.TransactionVerdict {
+		t.Helper()
+
+		verdict, err := api.validateTransaction(t.Context(), txn, types.NewZeroValue())
+		require.NoError(t, err)
+		return verdict
+	}
+
+	t.Run("Passed", func(t *testing.T) {
+		t.Parallel()
+
+		require.True(t, validate(t, execution.NewSendMoneyTransaction(t, to, 0)).Passed())
+	})
+
+	t.Run("SeqnoAhead", func(t *testing.T) {
+		t.Parallel()
+
+		// The transaction waits in the pool for the preceding ones, but its signature is checked.
+		txn := execution.NewSendMoneyTransaction(t, to, 5)
+		require.True(t, validate(t, txn).Passed())
+
+		txn.Signature[0] ^= 1
+		require.Equal(t, rawapitypes.TransactionCheckSignature, validate(t, txn).FailedCheck)
+	})
+
+	t.Run("Signature", func(t *testing.T) {
+		t.Parallel()
+
+		txn := execution.NewSendMoneyTransaction(t, to, 0)
+		txn.Signature[0] ^= 1
+		require.Equal(t, rawapitypes.TransactionCheckSignature, validate(t, txn).FailedCheck)
+	})
+
+	t.Run("ZeroMaxFee", func(t *testing.T) {
+		t.Parallel()
+
+		// The checks of the block generator are done as well.
+		txn := execution.NewSendMoneyTransaction(t, to, 0)
+		txn.MaxFeePerGas = types.NewZeroValue()
+		verdict := validate(t, txn)
+		require.Equal(t, rawapitypes.TransactionCheckFields, verdict.FailedCheck)
+		require.Equal(t, types.ErrorMaxFeePerGasIsZero, verdict.Error.Code())
+	})
+
+	t.Run("Account", func(t *testing.T) {
+		t.Parallel()
+
+		txn := execution.NewSendMoneyTransaction(t, to, 0)
+		txn.To = types.ShardAndHexToAddress(types.MainShardId, "0x5678")
+		require.Equal(t, rawapitypes.TransactionCheckAccount, validate(t, txn).FailedCheck)
+	})
+}
+
+func TestSendInvalidTransaction(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+	execution.GenerateZeroState(t, types.MainShardId, database)
+
+	txn := execution.NewSendMoneyTransaction(t, types.ShardAndHexToAddress(types.BaseShardId, "0x1234"), 0)
+	txn.Signature[0] ^= 1
+	encoded, err := (&types.ExternalTransaction{
+		Kind:                 types.ExecutionTransactionKind,
+		FeeCredit:            txn.FeeCredit,
+		To:                   txn.To,
+		ChainId:              txn.ChainId,
+		Seqno:                txn.Seqno,
+		Data:                 txn.Data,
+		AuthData:             txn.Signature,
+		MaxFeePerGas:         txn.MaxFeePerGas,
+		MaxPriorityFeePerGas: txn.MaxPriorityFeePerGas,
+	}).MarshalSSZ()
+	require.NoError(t, err)
+
+	send := func(t *testing.T, rejectInvalid bool) (*rawapitypes.SendTransactionResult, *txnpool.TxnPool) {
+		t.Helper()
+
+		pool, err := txnpool.New(t.Context(), txnpool.NewConfig(types.MainShardId), nil)
+		require.NoError(t, err)
+		api := newLocalShardApiRw(newLocalShardApiRo(types.MainShardId, database), pool)
+		api.rejectInvalid = rejectInvalid
+		result, err := api.SendTransaction(t.Context(), encoded, txnpool.ReplaceIfFeeBumped, "")
+		require.NoError(t, err)
+		require.Equal(t, rawapitypes.TransactionCheckSignature, result.Verdict.FailedCheck)
+		return result, pool
+	}
+
+	t.Run("Pooled", func(t *testing.T) {
+		t.Parallel()
+
+		// By default, the transaction is added to the pool and fails in a block, the verdict is only reported.
+		result, pool := send(t, false)
+		require.Equal(t, txnpool.NotSet, result.DiscardReason)
+		require.Equal(t, 1, pool.GetSize())
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		t.Parallel()
+
+		result, pool := send(t, true)
+		require.Equal(t, txnpool.Unverified, result.DiscardReason)
+		require.Zero(t, pool.GetSize())
+	})
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+var errTxnPoolNotAvailable = errors.New("transaction pool is not available")
+
+type localShardApiTxpool struct {
+	shard   types.ShardId
+	txnpool txnpool.Pool
+}
+
+var _ shardApiTxpool = (*localShardApiTxpool)(nil)
+
+func newLocalShardApiTxpool(shardId types.ShardId, txnpool txnpool.Pool) *localShardApiTxpool {
+	return &localShardApiTxpool{
+		shard:   shardId,
+		txnpool: txnpool,
+	}
+}
+
+func (api *localShardApiTxpool) shardId() types.ShardId {
+	return api.shard
+}
+
+func (api *localShardApiTxpool) setNodeApi(_ NodeApi) {}
+
+func (api *localShardApiTxpool) setAsP2pRequestHandlersIfAllowed(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	return setRawApiRequestHandlers(
+		ctx,
+		reflect.TypeFor[NetworkTransportProtocolTxpool](),
+		reflect.TypeFor[shardApiTxpool](),
+		api,
+		api.shard,
+		apiNameTxpool,
+		networkManager,
+		cfg,
+		logger)
+}
+
+// GetPoolContent returns all transactions of the pool split into the pending and the queued ones.
+func (api *localShardApiTxpool) GetPoolContent(_ context.Context) (*rawapitypes.PoolContent, error) {
+	if api.txnpool == nil {
+		return nil, errTxnPoolNotAvailable
+	}
+
+	pending, queued := api.txnpool.Content()
+	return &rawapitypes.PoolContent{Pending: pending, Queued: queued}, nil
+}
+
+func (api *localShardApiTxpool) GetPoolStatus(_ context.Context) (*rawapitypes.PoolStatus, error) {
+	if api.txnpool == nil {
+		return nil, errTxnPoolNotAvailable
+	}
+
+	pending, queued := api.txnpool.ContentSize()
+	return &rawapitypes.PoolStatus{
+		Pending: uint64(pending),
+		Queued:  uint64(queued),
+		BaseFee: api.txnpool.GetBaseFee(),
+	}, nil
+}
+
+// GetPoolTransactionsByAccount returns the transactions of the pool sent to the account.
+// A gap in the seqnos of the pending and the queued transactions is the reason the latter are stuck.
+func (api *localShardApiTxpool) GetPoolTransactionsByAccount(
+	_ context.Context,
+	address types.Address,
+) (*rawapitypes.PoolContent, error) {
+	if api.txnpool == nil {
+		return nil, errTxnPoolNotAvailable
+	}
+	if address.ShardId() != api.shard {
+		return nil, fmt.Errorf("%w: address %s doesn't belong to shard %d", rawapitypes.ErrShardMismatch, address, api.shard)
+	}
+
+	pending, queued := api.txnpool.ContentByReceiver(address)
+	return &rawapitypes.PoolContent{Pending: pending, Queued: queued}, nil
+}
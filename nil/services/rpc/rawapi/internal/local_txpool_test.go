@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoolStatus(t *testing.T) {
+	t.Parallel()
+
+	pool, err := txnpool.New(t.Context(), txnpool.NewConfig(types.MainShardId), nil)
+	require.NoError(t, err)
+	address := types.ShardAndHexToAddress(types.MainShardId, "0x1234")
+	newTxn := func(seqno types.Seqno) *types.Transaction {
+		return &types.Transaction{TransactionDigest: types.TransactionDigest{
+			To:           address,
+			Seqno:        seqno,
+			MaxFeePerGas: types.NewValueFromUint64(1000),
+		}}
+	}
+	// The transaction following the gap in the seqnos is queued.
+	reasons, err := pool.Add(t.Context(), newTxn(0), newTxn(1), newTxn(3))
+	require.NoError(t, err)
+	require.Equal(t, []txnpool.DiscardReason{txnpool.NotSet, txnpool.NotSet, txnpool.NotSet}, reasons)
+
+	api := newLocalShardApiTxpool(types.MainShardId, pool)
+	status, err := api.GetPoolStatus(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), status.Pending)
+	require.Equal(t, uint64(1), status.Queued)
+
+	content, err := api.GetPoolTransactionsByAccount(t.Context(), address)
+	require.NoError(t, err)
+	require.Len(t, content.Pending, 2)
+	require.Len(t, content.Queued, 1)
+
+	_, err = api.GetPoolTransactionsByAccount(t.Context(), types.ShardAndHexToAddress(types.BaseShardId, "0x1234"))
+	require.ErrorIs(t, err, rawapitypes.ErrShardMismatch)
+
+	_, err = newLocalShardApiTxpool(types.MainShardId, nil).GetPoolStatus(t.Context())
+	require.ErrorIs(t, err, errTxnPoolNotAvailable)
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/internal/config"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+// validatorWeight is the voting power of every validator. The validators don't stake and IBFT counts
+// their votes rather than weighs them, see ibftQuorum, so all of them have the same weight.
+const validatorWeight = 1
+
+// GetValidators returns the validators committing the block.
+func (api *localShardApiRo) GetValidators(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.ValidatorSet, error) {
+	blockReference, err := api.resolveFinalizedBlock(ctx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	blockHash, err := api.getBlockHashByReference(ctx, tx, blockReference)
+	if err != nil {
+		return nil, err
+	}
+	block, err := db.ReadBlock(tx, api.shardId(), blockHash)
+	if err != nil {
+		return nil, err
+	}
+	validators, err := api.getBlockValidators(tx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	n := uint64(len(validators))
+	return &rawapitypes.ValidatorSet{
+		BlockHash:   blockHash,
+		BlockNumber: block.Id,
+		Validators:  makeValidatorInfos(validators),
+		TotalWeight: n * validatorWeight,
+		Quorum:      ibftQuorum(n) * validatorWeight,
+	}, nil
+}
+
+// GetValidatorInfo returns the validator of the next blocks with the public key.
+func (api *localShardApiRo) GetValidatorInfo(
+	ctx context.Context,
+	publicKey []byte,
+) (*rawapitypes.ValidatorInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, _, err := db.ReadLastBlock(tx, api.shardId())
+	if err != nil {
+		return nil, err
+	}
+	validators, err := api.getNextBlockValidators(tx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(validators, func(validator config.ValidatorInfo) bool {
+		return bytes.Equal(validator.PublicKey[:], publicKey)
+	})
+	if index < 0 {
+		return nil, rawapitypes.NewError(rawapitypes.NotFoundErrorCode,
+			fmt.Errorf("validator %x is not in the shard %d", publicKey, api.shardId()))
+	}
+	return &makeValidatorInfos(validators)[index], nil
+}
+
+// GetEpochInfo returns the proposers of the block following the latest one in its rounds.
+func (api *localShardApiRo) GetEpochInfo(ctx context.Context) (*rawapitypes.EpochInfo, error) {
+	tx, err := api.db.CreateRoTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	block, _, err := db.ReadLastBlock(tx, api.shardId())
+	if err != nil {
+		return nil, err
+	}
+	validators, err := api.getBlockValidators(tx, block)
+	if err != nil {
+		return nil, err
+	}
+	nextValidators, err := api.getNextBlockValidators(tx, block)
+	if err != nil {
+		return nil, err
+	}
+
+	n := uint64(len(nextValidators))
+	info := &rawapitypes.EpochInfo{
+		BlockNumber:    block.Id,
+		Round:          block.Round,
+		ProposerIndex:  block.ProposerIndex,
+		ValidatorCount: n,
+		ValidatorSetChanged: !slices.EqualFunc(validators, nextValidators, func(a, b config.ValidatorInfo) bool {
+			return a.PublicKey == b.PublicKey
+		}),
+		NextProposers: make([]uint64, n),
+	}
+	for round := range n {
+		info.NextProposers[round] = nextProposerIndex(block, round, n)
+	}
+	return info, nil
+}
+
+// nextProposerIndex returns the index of the proposer of the block following the latest one in the round.
+// The proposers rotate from the one of the latest block every round, starting from the first validator
+// for the first block, see ibft.backendIBFT.calcProposer.
+func nextProposerIndex(latest *types.Block, round uint64, validators uint64) uint64 {
+	seed := round
+	if latest.Id > 0 {
+		seed = latest.ProposerIndex + round + 1
+	}
+	return seed % validators
+}
+
+func makeValidatorInfos(validators []config.ValidatorInfo) []rawapitypes.ValidatorInfo {
+	infos := make([]rawapitypes.ValidatorInfo, len(validators))
+	for i := range validators {
+		infos[i] = rawapitypes.ValidatorInfo{
+			Index:             uint64(i),
+			PublicKey:         validators[i].PublicKey[:],
+			WithdrawalAddress: validators[i].WithdrawalAddress,
+			Weight:            validatorWeight,
+		}
+	}
+	return infos
+}
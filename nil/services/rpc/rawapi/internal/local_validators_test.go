@@ -0,0 +1,28 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextProposerIndex(t *testing.T) {
+	t.Parallel()
+
+	// The proposer of the first block is the first validator in the first round.
+	zeroState := &types.Block{}
+	require.Equal(t, uint64(0), nextProposerIndex(zeroState, 0, 4))
+	require.Equal(t, uint64(2), nextProposerIndex(zeroState, 2, 4))
+	require.Equal(t, uint64(1), nextProposerIndex(zeroState, 5, 4))
+
+	// The proposers of the next blocks follow the one of the latest block, a failed round passes to the next one.
+	latest := &types.Block{BlockData: types.BlockData{Id: 7}, ConsensusParams: types.ConsensusParams{ProposerIndex: 2}}
+	require.Equal(t, uint64(3), nextProposerIndex(latest, 0, 4))
+	require.Equal(t, uint64(0), nextProposerIndex(latest, 1, 4))
+	require.Equal(t, uint64(1), nextProposerIndex(latest, 2, 4))
+	require.Equal(t, uint64(3), nextProposerIndex(latest, 4, 4))
+
+	// The validators are changed by the config of the latest block, the index of its proposer may be out of them.
+	require.Equal(t, uint64(1), nextProposerIndex(latest, 0, 2))
+}
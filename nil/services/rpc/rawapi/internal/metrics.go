@@ -0,0 +1,130 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const errorCodeAttribute = "errorCode"
+
+type requestHandlerMetrics struct {
+	requests telemetry.Counter
+	// The calls of a batch are counted as the requests of their methods, so the batches are counted apart.
+	batches      telemetry.Counter
+	errors       telemetry.Counter
+	duration     telemetry.Histogram
+	requestSize  telemetry.Histogram
+	responseSize telemetry.Histogram
+	// The requests caught by the watchdog, see RequestLogConfig.
+	slowRequests   telemetry.Counter
+	largeResponses telemetry.Counter
+}
+
+var getRequestHandlerMetrics = sync.OnceValue(func() *requestHandlerMetrics {
+	meter := telemetry.NewMeter("github.com/NilFoundation/nil/nil/services/rpc/rawapi")
+	return &requestHandlerMetrics{
+		requests:     telemetry.Int64Counter(meter, "requests"),
+		batches:      telemetry.Int64Counter(meter, "batch_requests"),
+		errors:       telemetry.Int64Counter(meter, "request_errors"),
+		duration:     telemetry.Int64Histogram(meter, "request_duration_ms"),
+		requestSize:  telemetry.Int64Histogram(meter, "request_size_bytes"),
+		responseSize: telemetry.Int64Histogram(meter, "response_size_bytes"),
+
+		slowRequests:   telemetry.Int64Counter(meter, "slow_requests"),
+		largeResponses: telemetry.Int64Counter(meter, "large_responses"),
+	}
+})
+
+// requestOutcome is filled by the handler, since the errors it packs into the response can't be told
+// from the successful responses without unpacking them.
+type requestOutcome struct {
+	err error
+}
+
+type requestOutcomeKey struct{}
+
+// recordRequestError remembers the error sent to the client, it does nothing outside of an instrumented handler.
+func recordRequestError(ctx context.Context, err error) {
+	if outcome, ok := ctx.Value(requestOutcomeKey{}).(*requestOutcome); ok && err != nil {
+		outcome.err = err
+	}
+}
+
+// handlerKind tells the instrumentation how the requests of the handler are served.
+type handlerKind int
+
+const (
+	singleRequest handlerKind = iota
+	batchRequest
+	// streamRequest opens a stream or a subscription, the responses are written to the stream by the handler.
+	streamRequest
+)
+
+// instrumentRequestHandler counts the requests of the method and their errors by code, and measures
+// their duration and the sizes of the requests and the responses. Each request is traced with a span,
+// and the sampled requests are logged.
+func instrumentRequestHandler(
+	handler network.RequestHandler,
+	shardId types.ShardId,
+	methodName string,
+	kind handlerKind,
+	requestLogger *requestLogger,
+) network.RequestHandler {
+	return getRequestHandlerMetrics().instrument(handler, shardId, methodName, kind, requestLogger)
+}
+
+func (metrics *requestHandlerMetrics) instrument(
+	handler network.RequestHandler,
+	shardId types.ShardId,
+	methodName string,
+	kind handlerKind,
+	requestLogger *requestLogger,
+) network.RequestHandler {
+	option := telattr.With(telattr.ShardId(shardId), telattr.RpcMethod(methodName))
+	requests := metrics.requests
+	if kind == batchRequest {
+		requests = metrics.batches
+	}
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		outcome := &requestOutcome{}
+		start := time.Now()
+		ctx, span := startServerSpan(ctx, shardId, methodName)
+		response, err := handler(context.WithValue(ctx, requestOutcomeKey{}, outcome), request)
+		duration := time.Since(start)
+
+		requests.Add(ctx, 1, option)
+		metrics.duration.Record(ctx, duration.Milliseconds(), option)
+		metrics.requestSize.Record(ctx, int64(len(request)), option)
+		failure := err
+		if err == nil {
+			if kind != streamRequest {
+				metrics.responseSize.Record(ctx, int64(len(response)), option)
+			}
+			failure = outcome.err
+		}
+		if failure != nil {
+			code := rawapitypes.ErrorCodeOf(failure)
+			metrics.errors.Add(ctx, 1, option,
+				metric.WithAttributes(attribute.String(errorCodeAttribute, code.String())))
+			span.SetAttributes(attribute.String(errorCodeAttribute, code.String()))
+		}
+		if requestLogger.isSlow(duration) {
+			metrics.slowRequests.Add(ctx, 1, option)
+		}
+		if requestLogger.isLarge(len(response)) {
+			metrics.largeResponses.Add(ctx, 1, option)
+		}
+		endSpan(span, failure)
+		requestLogger.log(ctx, request, len(response), duration, failure)
+		return response, err
+	}
+}
@@ -0,0 +1,140 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+type testHistogram struct {
+	noop.Int64Histogram
+
+	recorded int
+}
+
+func (h *testHistogram) Record(context.Context, int64, ...metric.RecordOption) {
+	h.recorded++
+}
+
+type testRequestHandlerMetrics struct {
+	*requestHandlerMetrics
+
+	requests, batches, errors *testCounter
+	responseSize              *testHistogram
+}
+
+func newTestRequestHandlerMetrics() *testRequestHandlerMetrics {
+	m := &testRequestHandlerMetrics{
+		requests:     new(testCounter),
+		batches:      new(testCounter),
+		errors:       new(testCounter),
+		responseSize: new(testHistogram),
+	}
+	m.requestHandlerMetrics = &requestHandlerMetrics{
+		requests:       m.requests,
+		batches:        m.batches,
+		errors:         m.errors,
+		duration:       noop.Int64Histogram{},
+		requestSize:    noop.Int64Histogram{},
+		responseSize:   m.responseSize,
+		slowRequests:   noop.Int64Counter{},
+		largeResponses: noop.Int64Counter{},
+	}
+	return m
+}
+
+func TestInstrumentRequestHandler(t *testing.T) {
+	t.Parallel()
+
+	requestLogger := func(methodName string) *requestLogger {
+		return newMethodRequestLogger(RequestLogConfig{}, logging.Nop(), types.MainShardId, methodName, nil)
+	}
+	respond := func(context.Context, []byte) ([]byte, error) {
+		return []byte{1}, nil
+	}
+
+	t.Run("Errors", func(t *testing.T) {
+		t.Parallel()
+
+		m := newTestRequestHandlerMetrics()
+		// The error packed into the response by the handler is counted as well as the one it returns.
+		handler := m.instrument(func(ctx context.Context, _ []byte) ([]byte, error) {
+			recordRequestError(ctx, rawapitypes.ErrRateLimited)
+			return []byte{1}, nil
+		}, types.MainShardId, "GetBlock", singleRequest, requestLogger("GetBlock"))
+		_, err := handler(t.Context(), nil)
+		require.NoError(t, err)
+
+		failing := m.instrument(func(context.Context, []byte) ([]byte, error) {
+			return nil, errors.New("failed")
+		}, types.MainShardId, "GetBlock", singleRequest, requestLogger("GetBlock"))
+		_, err = failing(t.Context(), nil)
+		require.Error(t, err)
+
+		require.EqualValues(t, 2, m.requests.added.Load())
+		require.EqualValues(t, 2, m.errors.added.Load())
+		require.Equal(t, 1, m.responseSize.recorded)
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		t.Parallel()
+
+		m := newTestRequestHandlerMetrics()
+		call := m.instrument(respond, types.MainShardId, "GetBlock", singleRequest, requestLogger("GetBlock"))
+		batch := m.instrument(func(ctx context.Context, request []byte) ([]byte, error) {
+			for range 3 {
+				if _, err := call(ctx, request); err != nil {
+					return nil, err
+				}
+			}
+			return []byte{1}, nil
+		}, types.MainShardId, batchMethodName, batchRequest, requestLogger(batchMethodName))
+
+		_, err := batch(t.Context(), nil)
+		require.NoError(t, err)
+		// The calls are counted as the requests of their method only.
+		require.EqualValues(t, 3, m.requests.added.Load())
+		require.EqualValues(t, 1, m.batches.added.Load())
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		t.Parallel()
+
+		m := newTestRequestHandlerMetrics()
+		protocol := makeProtocolId(types.MainShardId, apiNameRo, "GetBlocksRange")
+		errRejected := errors.New("rejected")
+		reject := func(_ context.Context, _ network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+			return func(ctx context.Context, request []byte) ([]byte, error) {
+				if len(request) == 0 {
+					return nil, errRejected
+				}
+				return next(ctx, request)
+			}
+		}
+		open := m.instrument(
+			makeStreamOpenHandler(t.Context(), protocol, []RequestInterceptor{reject}),
+			types.MainShardId, "GetBlocksRange", streamRequest, requestLogger("GetBlocksRange"))
+
+		var served int
+		serve := func(ctx context.Context, _ []byte) {
+			served++
+			recordRequestError(ctx, rawapitypes.ErrStatePruned)
+		}
+		require.NoError(t, openStream(t.Context(), open, []byte{1}, serve))
+		require.ErrorIs(t, openStream(t.Context(), open, nil, serve), errRejected)
+		require.Equal(t, 1, served)
+
+		// Both the error of the stream and the rejection are counted, the stream has no response of its own.
+		require.EqualValues(t, 2, m.requests.added.Load())
+		require.EqualValues(t, 2, m.errors.added.Load())
+		require.Zero(t, m.responseSize.recorded)
+	})
+}
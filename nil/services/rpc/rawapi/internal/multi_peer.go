@@ -0,0 +1,332 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	defaultMaxPeerAttempts = 3
+
+	// defaultHedgeDelay is used until enough latencies of the peer are known to estimate the P99.
+	defaultHedgeDelay = 500 * time.Millisecond
+	minLatencySamples = 20
+	latencySamples    = 128
+
+	// peerScoreAlpha is the weight of the latest request in the moving averages of the peer.
+	peerScoreAlpha = 0.2
+	// peerFailurePenalty is the latency a failed request is considered equivalent to when the peers are ranked.
+	peerFailurePenalty = 10 * time.Second
+
+	maxScoredPeers = 1024
+)
+
+// MultiPeerConfig configures the client sending the requests to several peers serving the shard.
+type MultiPeerConfig struct {
+	// Peers are the candidates the requests are sent to. All the peers serving the shard are used if it is empty.
+	Peers []network.PeerID
+	// Directory provides the candidates if Peers is empty. The peers advertising the API of the shard
+	// are used if it is nil or doesn't know any peers serving the shard.
+	Directory *ShardPeerDirectory
+	// MaxAttempts is the number of peers a request is sent to before it fails (3 by default).
+	MaxAttempts int
+	// HedgeDelay is the time after which the request is also sent to the next peer if the previous one
+	// hasn't responded yet. The P99 of the latencies of the peer is used if it is zero,
+	// the hedging is disabled (the next peer is only tried on failure) if it is negative.
+	HedgeDelay time.Duration
+	// ResponseCache configures the cache of the responses of the immutable data, it is disabled by default.
+	ResponseCache ResponseCacheConfig
+}
+
+type peerScore struct {
+	latencies   [latencySamples]time.Duration
+	samples     int
+	latencyEwma float64
+	errorRate   float64
+}
+
+// value is the expected cost of a request to the peer, the lower the better.
+func (s *peerScore) value() float64 {
+	return s.latencyEwma + s.errorRate*float64(peerFailurePenalty)
+}
+
+func (s *peerScore) p99() time.Duration {
+	n := min(s.samples, latencySamples)
+	latencies := slices.Clone(s.latencies[:n])
+	slices.Sort(latencies)
+	return latencies[int(math.Ceil(0.99*float64(n)))-1]
+}
+
+// peerScores tracks the error rates and the latencies of the peers to rank them.
+type peerScores struct {
+	// mu guards the scores, the cache is safe for concurrent use by itself.
+	mu     sync.Mutex
+	scores *lru.Cache[network.PeerID, *peerScore]
+}
+
+func newPeerScores() *peerScores {
+	return &peerScores{scores: mustCreate(lru.New[network.PeerID, *peerScore](maxScoredPeers))}
+}
+
+func (s *peerScores) record(peerId network.PeerID, latency time.Duration, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	score, ok := s.scores.Get(peerId)
+	if !ok {
+		score = &peerScore{}
+		if err == nil {
+			score.latencyEwma = float64(latency)
+		}
+		s.scores.Add(peerId, score)
+	}
+
+	failure := 0.0
+	if err != nil {
+		failure = 1
+	}
+	score.errorRate += peerScoreAlpha * (failure - score.errorRate)
+	if err != nil {
+		return
+	}
+	score.latencyEwma += peerScoreAlpha * (float64(latency) - score.latencyEwma)
+	score.latencies[score.samples%latencySamples] = latency
+	score.samples++
+}
+
+// rank orders the peers by their scores. The unknown peers go first, so they get scored.
+func (s *peerScores) rank(peers []network.PeerID) []network.PeerID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values := make(map[network.PeerID]float64, len(peers))
+	for _, peerId := range peers {
+		if score, ok := s.scores.Peek(peerId); ok {
+			values[peerId] = score.value()
+		}
+	}
+	ranked := slices.Clone(peers)
+	slices.SortStableFunc(ranked, func(a, b network.PeerID) int {
+		switch {
+		case values[a] < values[b]:
+			return -1
+		case values[a] > values[b]:
+			return 1
+		}
+		return 0
+	})
+	return ranked
+}
+
+func (s *peerScores) hedgeDelay(peerId network.PeerID) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	score, ok := s.scores.Peek(peerId)
+	if !ok || score.samples < minLatencySamples {
+		return defaultHedgeDelay
+	}
+	return score.p99()
+}
+
+func (s *peerScores) selectBestPeer(peers []network.PeerID) (network.PeerID, error) {
+	return s.rank(peers)[0], nil
+}
+
+type peerResponse struct {
+	peerId   network.PeerID
+	response []byte
+	err      error
+}
+
+// raceShardApiPeers sends the request to the candidates in order, until one of them responds successfully.
+// The next candidate is tried when the previous one fails or doesn't respond within its hedge delay
+// (a negative delay disables the hedging). The requests still in flight are cancelled once the response arrives.
+func raceShardApiPeers(
+	ctx context.Context,
+	candidates []network.PeerID,
+	hedgeDelay func(peerId network.PeerID) time.Duration,
+	send func(ctx context.Context, peerId network.PeerID) ([]byte, error),
+) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan peerResponse, len(candidates))
+	var hedge <-chan time.Time
+	started, pending := 0, 0
+	startNext := func() {
+		peerId := candidates[started]
+		started++
+		pending++
+		go func() {
+			response, err := send(ctx, peerId)
+			results <- peerResponse{peerId: peerId, response: response, err: err}
+		}()
+
+		hedge = nil
+		if started < len(candidates) {
+			if delay := hedgeDelay(peerId); delay >= 0 {
+				hedge = time.After(delay)
+			}
+		}
+	}
+
+	var errs []error
+	startNext()
+	for pending > 0 {
+		select {
+		case result := <-results:
+			pending--
+			if result.err == nil {
+				return result.response, nil
+			}
+			errs = append(errs, fmt.Errorf("peer %s: %w", result.peerId, result.err))
+			if started < len(candidates) {
+				startNext()
+			}
+		case <-hedge:
+			startNext()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// shardApiRequestPerformerMultiPeer sends the requests to the best peers serving the shard,
+// failing over to the next ones and hedging the slow requests. The peer failing the request by an error
+// specific to it, e.g. being overloaded or having pruned the state, is failed over too.
+// The subscriptions use the best peer.
+type shardApiRequestPerformerMultiPeer struct {
+	shardApiRequestPerformerNetwork
+
+	config MultiPeerConfig
+	scores *peerScores
+}
+
+var _ shardApiRequestPerformer = (*shardApiRequestPerformerMultiPeer)(nil)
+
+func (api *shardApiRequestPerformerMultiPeer) doApiRequest(
+	ctx context.Context, codec *methodCodec, args ...any,
+) ([]byte, error) {
+	candidates := api.candidates(codec.methodName)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no peers with shard %d found", api.shard)
+	}
+
+	requestBody, err := codec.packRequest(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return raceShardApiPeers(ctx, candidates, api.hedgeDelay,
+		func(ctx context.Context, peerId network.PeerID) ([]byte, error) {
+			start := time.Now()
+			response, err := api.sendToPeer(ctx, peerId, codec.methodName, requestBody)
+			if err == nil {
+				err = retryableResponseError(codec, response)
+			}
+			// The requests cancelled in favor of the other peers say nothing about the peer.
+			if err == nil || ctx.Err() == nil {
+				api.scores.record(peerId, time.Since(start), err)
+			}
+			return response, err
+		})
+}
+
+// retryableResponseError returns the error of the response if it is specific to the peer, so the request
+// is to be sent to the next one. The other errors are returned to the caller as is.
+func retryableResponseError(codec *methodCodec, response []byte) error {
+	_, err := codec.unpackResponse(response)
+	if err == nil {
+		return nil
+	}
+	switch rawapitypes.ErrorCodeOf(err) {
+	case rawapitypes.InternalErrorCode,
+		rawapitypes.TimeoutErrorCode,
+		rawapitypes.RateLimitedErrorCode,
+		rawapitypes.StatePrunedErrorCode,
+		rawapitypes.OverloadedErrorCode:
+		return err
+	}
+	return nil
+}
+
+func (api *shardApiRequestPerformerMultiPeer) candidates(methodName string) []network.PeerID {
+	peers := api.config.Peers
+	if len(peers) == 0 && api.config.Directory != nil {
+		peers = api.config.Directory.Peers(api.shard, false)
+	}
+	if len(peers) == 0 {
+		peers = discoverShardApiPeers(api.networkManager, api.shard, api.apiName, methodName)
+	}
+	ranked := api.scores.rank(peers)
+	return ranked[:min(len(ranked), api.config.MaxAttempts)]
+}
+
+func (api *shardApiRequestPerformerMultiPeer) hedgeDelay(peerId network.PeerID) time.Duration {
+	if api.config.HedgeDelay != 0 {
+		return api.config.HedgeDelay
+	}
+	return api.scores.hedgeDelay(peerId)
+}
+
+func (api *shardApiRequestPerformerMultiPeer) sendToPeer(
+	ctx context.Context, peerId network.PeerID, methodName string, requestBody []byte,
+) ([]byte, error) {
+	protocol, err := resolvePeerProtocol(ctx, api.networkManager, peerId, api.shard, api.apiName, methodName)
+	if err != nil {
+		return nil, err
+	}
+	response, err := sendEnvelopedRequest(ctx, api.networkManager, peerId, protocol, requestBody)
+	if err != nil {
+		forgetApiVersion(peerId, api.shard, api.apiName)
+		return nil, err
+	}
+	return response, nil
+}
+
+func newShardApiClientMultiPeer[
+	ClientType shardApiBase,
+	ShardApiType shardApiBase,
+	TransportType any,
+	F func(shardApiRequestPerformer) *ClientType,
+](
+	construct F,
+	shardId types.ShardId,
+	apiName string,
+	networkManager network.Manager,
+	config MultiPeerConfig,
+) (*ClientType, error) {
+	codec, err := newApiCodec(reflect.TypeFor[ShardApiType](), reflect.TypeFor[TransportType]())
+	if err != nil {
+		return nil, err
+	}
+
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxPeerAttempts
+	}
+	scores := newPeerScores()
+	return construct(withResponseCache(&shardApiRequestPerformerMultiPeer{
+		shardApiRequestPerformerNetwork: shardApiRequestPerformerNetwork{
+			shard:          shardId,
+			apiName:        apiName,
+			networkManager: networkManager,
+			selectPeer:     scores.selectBestPeer,
+			codec:          codec,
+		},
+		config: config,
+		scores: scores,
+	}, config.ResponseCache)), nil
+}
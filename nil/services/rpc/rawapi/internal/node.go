@@ -2,13 +2,18 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"sync"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
@@ -17,15 +22,54 @@ import (
 )
 
 type nodeApiOverShardApis struct {
-	apisRo  map[types.ShardId]shardApiRo
-	apisRw  map[types.ShardId]shardApiRw
-	apisDev map[types.ShardId]shardApiDev
+	apisRo     map[types.ShardId]shardApiRo
+	apisRw     map[types.ShardId]shardApiRw
+	apisDev    map[types.ShardId]shardApiDev
+	apisDebug  map[types.ShardId]shardApiDebug
+	apisTxpool map[types.ShardId]shardApiTxpool
+	apisSync   map[types.ShardId]shardApiSync
 
 	allApis []shardApiBase
+	// accessors are the state accessors of the local APIs, whose caches are flushed by the admin API.
+	accessors []*execution.StateAccessor
+
+	// servedShards are the shards with the local APIs.
+	servedShards map[types.ShardId]servedShard
+
+	// p2p is set by SetP2pRequestHandlers.
+	p2p *p2pHandlers
+}
+
+// p2pHandlers is the state of serving the local APIs over the network.
+type p2pHandlers struct {
+	// mu serializes the changes of the served shards.
+	mu sync.Mutex
+	// ctx is the context the handlers were set with, the handlers set later get it as well,
+	// since the contexts of the requests derive from it.
+	ctx            context.Context
+	networkManager network.Manager
+	cfg            RequestHandlersConfig
+	logger         logging.Logger
 }
 
 var _ NodeApi = (*nodeApiOverShardApis)(nil)
 
+func (api *nodeApiOverShardApis) addServedShard(shardId types.ShardId, shard servedShard) {
+	if served, ok := api.servedShards[shardId]; ok {
+		shard.readOnly = shard.readOnly && served.readOnly
+		shard.archive = shard.archive && served.archive
+	}
+	api.servedShards[shardId] = shard
+}
+
+func (api *nodeApiOverShardApis) flushCaches() uint64 {
+	var flushed uint64
+	for _, accessor := range api.accessors {
+		flushed += uint64(accessor.Purge())
+	}
+	return flushed
+}
+
 func methodNameChecked(methodName string) string {
 	if assert.Enable {
 		callerMethodName := extractCallerMethodName(2)
@@ -95,281 +139,1346 @@ func (api *nodeApiOverShardApis) GetBlockTransactionCount(
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetBalance(
+func (api *nodeApiOverShardApis) GetBlockRange(
 	ctx context.Context,
-	address types.Address,
-	blockReference rawapitypes.BlockReference,
-) (types.Value, error) {
-	methodName := methodNameChecked("GetBalance")
-	shardId := address.ShardId()
+	shardId types.ShardId,
+	from types.BlockNumber,
+	count uint64,
+	fullBlocks bool,
+) ([]*types.RawBlockWithExtractedData, error) {
+	methodName := methodNameChecked("GetBlockRange")
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return types.Value{}, makeShardNotFoundError(methodName, shardId)
+		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetBalance(ctx, address, blockReference)
+	result, err := shardApi.GetBlockRange(ctx, from, count, fullBlocks)
 	if err != nil {
-		return types.Value{}, makeCallError(methodName, shardId, err)
+		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetCode(
+func (api *nodeApiOverShardApis) GetShardStats(
 	ctx context.Context,
-	address types.Address,
-	blockReference rawapitypes.BlockReference,
-) (types.Code, error) {
-	methodName := methodNameChecked("GetCode")
-	shardId := address.ShardId()
+	shardId types.ShardId,
+	from types.BlockNumber,
+	count uint64,
+) (*rawapitypes.ShardStats, error) {
+	methodName := methodNameChecked("GetShardStats")
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return types.Code{}, makeShardNotFoundError(methodName, shardId)
+		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetCode(ctx, address, blockReference)
+	result, err := shardApi.GetShardStats(ctx, from, count)
 	if err != nil {
-		return types.Code{}, makeCallError(methodName, shardId, err)
+		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetTokens(
+func (api *nodeApiOverShardApis) GetBlockFinalitySignatures(
 	ctx context.Context,
-	address types.Address,
+	shardId types.ShardId,
 	blockReference rawapitypes.BlockReference,
-) (map[types.TokenId]types.Value, error) {
-	methodName := methodNameChecked("GetTokens")
-	shardId := address.ShardId()
+) (*rawapitypes.FinalitySignatures, error) {
+	methodName := methodNameChecked("GetBlockFinalitySignatures")
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetTokens(ctx, address, blockReference)
+	result, err := shardApi.GetBlockFinalitySignatures(ctx, blockReference)
 	if err != nil {
 		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetContract(
+func (api *nodeApiOverShardApis) GetValidators(
 	ctx context.Context,
-	address types.Address,
+	shardId types.ShardId,
 	blockReference rawapitypes.BlockReference,
-) (*rawapitypes.SmartContract, error) {
-	methodName := methodNameChecked("GetContract")
-	shardId := address.ShardId()
+) (*rawapitypes.ValidatorSet, error) {
+	methodName := methodNameChecked("GetValidators")
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetContract(ctx, address, blockReference)
+	result, err := shardApi.GetValidators(ctx, blockReference)
 	if err != nil {
 		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) Call(
+func (api *nodeApiOverShardApis) GetValidatorInfo(
 	ctx context.Context,
-	args rpctypes.CallArgs,
-	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
-	overrides *rpctypes.StateOverrides,
-) (*rpctypes.CallResWithGasPrice, error) {
-	methodName := methodNameChecked("Call")
-
-	txn, err := args.ToTransaction()
-	if err != nil {
-		return nil, err
-	}
-
-	shardId := txn.To.ShardId()
+	shardId types.ShardId,
+	publicKey []byte,
+) (*rawapitypes.ValidatorInfo, error) {
+	methodName := methodNameChecked("GetValidatorInfo")
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.Call(ctx, args, mainBlockReferenceOrHashWithChildren, overrides)
+	result, err := shardApi.GetValidatorInfo(ctx, publicKey)
 	if err != nil {
 		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetInTransaction(
+func (api *nodeApiOverShardApis) GetEpochInfo(
 	ctx context.Context,
 	shardId types.ShardId,
-	request rawapitypes.TransactionRequest,
-) (*rawapitypes.TransactionInfo, error) {
-	methodName := methodNameChecked("GetInTransaction")
+) (*rawapitypes.EpochInfo, error) {
+	methodName := methodNameChecked("GetEpochInfo")
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetInTransaction(ctx, request)
+	result, err := shardApi.GetEpochInfo(ctx)
 	if err != nil {
 		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetInTransactionReceipt(
+func (api *nodeApiOverShardApis) SubscribeNewHeads(
 	ctx context.Context,
 	shardId types.ShardId,
-	hash common.Hash,
-) (*rawapitypes.ReceiptInfo, error) {
-	methodName := methodNameChecked("GetInTransactionReceipt")
+) (<-chan sszx.SSZEncodedData, error) {
+	methodName := methodNameChecked("SubscribeNewHeads")
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetInTransactionReceipt(ctx, hash)
+	result, err := shardApi.SubscribeNewHeads(ctx)
 	if err != nil {
 		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
-	methodName := methodNameChecked("GasPrice")
+func (api *nodeApiOverShardApis) GetBalance(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (types.Value, error) {
+	methodName := methodNameChecked("GetBalance")
+	shardId := address.ShardId()
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return types.Value{}, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GasPrice(ctx)
+	result, err := shardApi.GetBalance(ctx, address, blockReference)
 	if err != nil {
 		return types.Value{}, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
-	methodName := methodNameChecked("GetShardIdList")
-	shardId := types.MainShardId
+// GetBalances requests the balances of the accounts of each shard at once and restores the order of the addresses.
+func (api *nodeApiOverShardApis) GetBalances(
+	ctx context.Context,
+	addresses []types.Address,
+	blockReference rawapitypes.BlockReference,
+) ([]types.Value, error) {
+	methodName := methodNameChecked("GetBalances")
+	indices := make(map[types.ShardId][]int)
+	for i, address := range addresses {
+		shardId := address.ShardId()
+		indices[shardId] = append(indices[shardId], i)
+	}
+
+	balances := make([]types.Value, len(addresses))
+	for shardId, shardIndices := range indices {
+		shardApi, ok := api.apisRo[shardId]
+		if !ok {
+			return nil, makeShardNotFoundError(methodName, shardId)
+		}
+		shardAddresses := make([]types.Address, len(shardIndices))
+		for i, index := range shardIndices {
+			shardAddresses[i] = addresses[index]
+		}
+		result, err := shardApi.GetBalances(ctx, shardAddresses, blockReference)
+		if err != nil {
+			return nil, makeCallError(methodName, shardId, err)
+		}
+		if len(result) != len(shardAddresses) {
+			return nil, makeCallError(methodName, shardId,
+				fmt.Errorf("expected %d balances, got %d", len(shardAddresses), len(result)))
+		}
+		for i, index := range shardIndices {
+			balances[index] = result[i]
+		}
+	}
+	return balances, nil
+}
+
+func (api *nodeApiOverShardApis) GetStorageAt(
+	ctx context.Context,
+	address types.Address,
+	key common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (types.Uint256, error) {
+	methodName := methodNameChecked("GetStorageAt")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return types.Uint256{}, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetStorageAt(ctx, address, key, blockReference)
+	if err != nil {
+		return types.Uint256{}, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetStorageRange(
+	ctx context.Context,
+	address types.Address,
+	start common.Hash,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.StorageRange, error) {
+	methodName := methodNameChecked("GetStorageRange")
+	shardId := address.ShardId()
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetShardIdList(ctx)
+	result, err := shardApi.GetStorageRange(ctx, address, start, limit, blockReference)
 	if err != nil {
 		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetNumShards(ctx context.Context) (uint64, error) {
-	methodName := methodNameChecked("GetNumShards")
-	shardId := types.MainShardId
+func (api *nodeApiOverShardApis) GetCode(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (types.Code, error) {
+	methodName := methodNameChecked("GetCode")
+	shardId := address.ShardId()
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return 0, makeShardNotFoundError(methodName, shardId)
+		return types.Code{}, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetNumShards(ctx)
+	result, err := shardApi.GetCode(ctx, address, blockReference)
 	if err != nil {
-		return 0, makeCallError(methodName, shardId, err)
+		return types.Code{}, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetTransactionCount(
+func (api *nodeApiOverShardApis) GetTokens(
 	ctx context.Context,
 	address types.Address,
 	blockReference rawapitypes.BlockReference,
-) (uint64, error) {
-	methodName := methodNameChecked("GetTransactionCount")
+	request rawapitypes.TokensRequest,
+) (*rawapitypes.TokensPage, error) {
+	methodName := methodNameChecked("GetTokens")
 	shardId := address.ShardId()
-	shardApi, ok := api.apisRw[shardId]
+	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return 0, makeShardNotFoundError(methodName, shardId)
+		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetTransactionCount(ctx, address, blockReference)
+	result, err := shardApi.GetTokens(ctx, address, blockReference, request)
 	if err != nil {
-		return 0, makeCallError(methodName, shardId, err)
+		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) SendTransaction(
+func (api *nodeApiOverShardApis) GetTokenBalance(
 	ctx context.Context,
-	shardId types.ShardId,
-	transaction []byte,
-) (txnpool.DiscardReason, error) {
-	methodName := methodNameChecked("SendTransaction")
-	shardApi, ok := api.apisRw[shardId]
+	address types.Address,
+	tokenId types.TokenId,
+	blockReference rawapitypes.BlockReference,
+) (types.Value, error) {
+	methodName := methodNameChecked("GetTokenBalance")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return 0, makeShardNotFoundError(methodName, shardId)
+		return types.Value{}, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.SendTransaction(ctx, transaction)
+	result, err := shardApi.GetTokenBalance(ctx, address, tokenId, blockReference)
 	if err != nil {
-		return 0, makeCallError(methodName, shardId, err)
+		return types.Value{}, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) ClientVersion(ctx context.Context) (string, error) {
-	methodName := methodNameChecked("ClientVersion")
-	shardId := types.MainShardId
+func (api *nodeApiOverShardApis) GetTokenInfo(
+	ctx context.Context,
+	tokenId types.TokenId,
+	mainBlockReference rawapitypes.BlockReference,
+) (*rawapitypes.TokenInfo, error) {
+	methodName := methodNameChecked("GetTokenInfo")
+	shardId := types.Address(tokenId).ShardId()
 	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return "", makeShardNotFoundError(methodName, shardId)
+		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.ClientVersion(ctx)
+	result, err := shardApi.GetTokenInfo(ctx, tokenId, mainBlockReference)
 	if err != nil {
-		return "", makeCallError(methodName, shardId, err)
+		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error) {
-	methodName := methodNameChecked("DoPanicOnShard")
-	shardApi, ok := api.apisDev[shardId]
+func (api *nodeApiOverShardApis) ListTokensCreatedBy(
+	ctx context.Context,
+	address types.Address,
+	mainBlockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.TokenInfo, error) {
+	methodName := methodNameChecked("ListTokensCreatedBy")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return 0, makeShardNotFoundError(methodName, shardId)
+		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	return shardApi.DoPanicOnShard(ctx)
+	result, err := shardApi.ListTokensCreatedBy(ctx, address, mainBlockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (uint64, error) {
-	methodName := methodNameChecked("GetTxpoolStatus")
-	shardApi, ok := api.apisRw[shardId]
+func (api *nodeApiOverShardApis) GetContract(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.SmartContract, error) {
+	methodName := methodNameChecked("GetContract")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
 	if !ok {
-		return 0, makeShardNotFoundError(methodName, shardId)
+		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetTxpoolStatus(ctx)
+	result, err := shardApi.GetContract(ctx, address, blockReference)
 	if err != nil {
-		return 0, makeCallError(methodName, shardId, err)
+		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) GetTxpoolContent(
+func (api *nodeApiOverShardApis) GetAccountMeta(
 	ctx context.Context,
-	shardId types.ShardId,
-) ([]*types.Transaction, error) {
-	methodName := methodNameChecked("GetTxpoolContent")
-	shardApi, ok := api.apisRw[shardId]
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.AccountMeta, error) {
+	methodName := methodNameChecked("GetAccountMeta")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
 	if !ok {
 		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	result, err := shardApi.GetTxpoolContent(ctx)
+	result, err := shardApi.GetAccountMeta(ctx, address, blockReference)
 	if err != nil {
 		return nil, makeCallError(methodName, shardId, err)
 	}
 	return result, nil
 }
 
-func (api *nodeApiOverShardApis) SetP2pRequestHandlers(
+func (api *nodeApiOverShardApis) GetProof(
 	ctx context.Context,
-	networkManager network.Manager,
-	logger logging.Logger,
-) error {
-	if networkManager == nil {
-		return nil
+	address types.Address,
+	storageKeys []common.Hash,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.ContractProof, error) {
+	methodName := methodNameChecked("GetProof")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
 	}
-	for _, api := range api.allApis {
-		if err := api.setAsP2pRequestHandlersIfAllowed(ctx, networkManager, logger); err != nil {
-			logger.Error().
-				Err(err).
-				Stringer(logging.FieldShardId, api.shardId()).
-				Msg("Failed to set raw API request handler")
-			return err
-		}
+	result, err := shardApi.GetProof(ctx, address, storageKeys, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
 	}
-	return nil
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) Call(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rpctypes.CallResWithGasPrice, error) {
+	methodName := methodNameChecked("Call")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.Call(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) EstimateFee(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.FeeEstimation, error) {
+	methodName := methodNameChecked("EstimateFee")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.EstimateFee(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) CreateAccessList(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.AccessListResult, error) {
+	methodName := methodNameChecked("CreateAccessList")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.CreateAccessList(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+// SimulateBundle routes the bundle by the destination of the first call, the shard checks the rest of them.
+func (api *nodeApiOverShardApis) SimulateBundle(
+	ctx context.Context,
+	calls []rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) ([]*rawapitypes.BundleCallResult, error) {
+	methodName := methodNameChecked("SimulateBundle")
+	if len(calls) == 0 {
+		return []*rawapitypes.BundleCallResult{}, nil
+	}
+
+	txn, err := calls[0].ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.SimulateBundle(
+		ctx, calls, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) PrepareDeploy(
+	ctx context.Context, request rawapitypes.DeployRequest,
+) (*rawapitypes.DeployPreparation, error) {
+	methodName := methodNameChecked("PrepareDeploy")
+	shardApi, ok := api.apisRo[request.ShardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, request.ShardId)
+	}
+	result, err := shardApi.PrepareDeploy(ctx, request)
+	if err != nil {
+		return nil, makeCallError(methodName, request.ShardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetInTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	request rawapitypes.TransactionRequest,
+) (*rawapitypes.TransactionInfo, error) {
+	methodName := methodNameChecked("GetInTransaction")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetInTransaction(ctx, request)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetReceipt(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*rawapitypes.ReceiptInfo, error) {
+	methodName := methodNameChecked("GetReceipt")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetReceipt(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetBounceInfo(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*rawapitypes.BounceInfo, error) {
+	methodName := methodNameChecked("GetBounceInfo")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetBounceInfo(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetBlockReceipts(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.ReceiptInfo, error) {
+	methodName := methodNameChecked("GetBlockReceipts")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetBlockReceipts(ctx, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetOutTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	transactionRequest rawapitypes.TransactionRequest,
+) (*rawapitypes.TransactionInfo, error) {
+	methodName := methodNameChecked("GetOutTransaction")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetOutTransaction(ctx, transactionRequest)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetOutTransactions(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockReference rawapitypes.BlockReference,
+) ([]*rawapitypes.TransactionInfo, error) {
+	methodName := methodNameChecked("GetOutTransactions")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetOutTransactions(ctx, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetLogs(
+	ctx context.Context,
+	shardId types.ShardId,
+	filter rawapitypes.LogFilter,
+) ([]*rawapitypes.LogInfo, error) {
+	methodName := methodNameChecked("GetLogs")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetLogs(ctx, filter)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) SubscribeLogs(
+	ctx context.Context,
+	shardId types.ShardId,
+	filter rawapitypes.LogFilter,
+) (<-chan *rawapitypes.LogInfo, error) {
+	methodName := methodNameChecked("SubscribeLogs")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.SubscribeLogs(ctx, filter)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
+	methodName := methodNameChecked("GasPrice")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return types.Value{}, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GasPrice(ctx)
+	if err != nil {
+		return types.Value{}, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
+	methodName := methodNameChecked("GetShardIdList")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetShardIdList(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetNumShards(ctx context.Context) (uint64, error) {
+	methodName := methodNameChecked("GetNumShards")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetNumShards(ctx)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) ResolveShard(ctx context.Context, address types.Address) (types.ShardId, error) {
+	methodName := methodNameChecked("ResolveShard")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.ResolveShard(ctx, address)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) ResolveShards(
+	ctx context.Context, addresses []types.Address,
+) ([]*rawapitypes.ShardResolution, error) {
+	methodName := methodNameChecked("ResolveShards")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.ResolveShards(ctx, addresses)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetChainConfig(ctx context.Context) (*rawapitypes.ChainConfig, error) {
+	methodName := methodNameChecked("GetChainConfig")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetChainConfig(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetSyncStatus(
+	ctx context.Context, shardId types.ShardId,
+) (*rawapitypes.SyncStatus, error) {
+	methodName := methodNameChecked("GetSyncStatus")
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetSyncStatus(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) Health(ctx context.Context, maxBlockLag uint64) (*rawapitypes.NodeHealth, error) {
+	health := &rawapitypes.NodeHealth{Healthy: true}
+	for _, shardId := range slices.Sorted(maps.Keys(api.apisRo)) {
+		shard := rawapitypes.ShardHealth{ShardId: shardId}
+		status, err := api.GetSyncStatus(ctx, shardId)
+		if err != nil {
+			shard.Error = err.Error()
+		} else {
+			shard.Status = status
+			shard.Healthy = isShardHealthy(status, maxBlockLag)
+		}
+		health.Healthy = health.Healthy && shard.Healthy
+		health.Shards = append(health.Shards, shard)
+	}
+	return health, nil
+}
+
+func isShardHealthy(status *rawapitypes.SyncStatus, maxBlockLag uint64) bool {
+	if status.Phase == rawapitypes.SyncPhaseDownloading || status.Phase == rawapitypes.SyncPhaseReplaying {
+		return false
+	}
+	return status.HighestKnownBlock <= status.CurrentBlock+types.BlockNumber(maxBlockLag)
+}
+
+func (api *nodeApiOverShardApis) GetTransactionCount(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (uint64, error) {
+	methodName := methodNameChecked("GetTransactionCount")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTransactionCount(ctx, address, blockReference)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetNextValidSeqno(
+	ctx context.Context,
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+) (uint64, error) {
+	methodName := methodNameChecked("GetNextValidSeqno")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetNextValidSeqno(ctx, address, blockReference)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) SendTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	transaction []byte,
+	replacement txnpool.ReplacementPolicy,
+	idempotencyKey string,
+) (*rawapitypes.SendTransactionResult, error) {
+	methodName := methodNameChecked("SendTransaction")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.SendTransaction(ctx, transaction, replacement, idempotencyKey)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) SendTransactionBundle(
+	ctx context.Context,
+	shardId types.ShardId,
+	transactions [][]byte,
+	sameBlock bool,
+) ([]*rawapitypes.SendTransactionResult, error) {
+	methodName := methodNameChecked("SendTransactionBundle")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.SendTransactionBundle(ctx, transactions, sameBlock)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) SendTransactionAndWatch(
+	ctx context.Context,
+	shardId types.ShardId,
+	transaction []byte,
+	replacement txnpool.ReplacementPolicy,
+	idempotencyKey string,
+) (<-chan *rawapitypes.TransactionEvent, error) {
+	methodName := methodNameChecked("SendTransactionAndWatch")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.SendTransactionAndWatch(ctx, transaction, replacement, idempotencyKey)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetTransactionStatus(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*rawapitypes.TransactionStatusInfo, error) {
+	methodName := methodNameChecked("GetTransactionStatus")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTransactionStatus(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) ResendTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	transaction []byte,
+	replacement txnpool.ReplacementPolicy,
+) (txnpool.DiscardReason, error) {
+	methodName := methodNameChecked("ResendTransaction")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.ResendTransaction(ctx, transaction, replacement)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) ClientVersion(ctx context.Context) (string, error) {
+	methodName := methodNameChecked("ClientVersion")
+	shardId := types.MainShardId
+	shardApi, ok := api.apisRo[shardId]
+	if !ok {
+		return "", makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.ClientVersion(ctx)
+	if err != nil {
+		return "", makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error) {
+	methodName := methodNameChecked("DoPanicOnShard")
+	shardApi, ok := api.apisDev[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	return shardApi.DoPanicOnShard(ctx)
+}
+
+func (api *nodeApiOverShardApis) TraceTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*rawapitypes.ExecutionTrace, error) {
+	methodName := methodNameChecked("TraceTransaction")
+	shardApi, ok := api.apisDebug[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceTransaction(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) TraceCall(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.ExecutionTrace, error) {
+	methodName := methodNameChecked("TraceCall")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisDebug[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceCall(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) TraceTransactionStateDiff(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*rawapitypes.StateDiff, error) {
+	methodName := methodNameChecked("TraceTransactionStateDiff")
+	shardApi, ok := api.apisDebug[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceTransactionStateDiff(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) TraceCallStateDiff(
+	ctx context.Context,
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) (*rawapitypes.StateDiff, error) {
+	methodName := methodNameChecked("TraceCallStateDiff")
+
+	txn, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+
+	shardId := txn.To.ShardId()
+	shardApi, ok := api.apisDebug[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.TraceCallStateDiff(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) ReplayTransaction(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+	options rawapitypes.ReplayOptions,
+) (*rawapitypes.ReplayResult, error) {
+	methodName := methodNameChecked("ReplayTransaction")
+	shardApi, ok := api.apisDebug[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.ReplayTransaction(ctx, hash, options)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetPendingResponsesFor(
+	ctx context.Context,
+	address types.Address,
+	start types.TransactionIndex,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.PendingResponses, error) {
+	methodName := methodNameChecked("GetPendingResponsesFor")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisDebug[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetPendingResponsesFor(ctx, address, start, limit, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetAwaitingContext(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*rawapitypes.AwaitingContext, error) {
+	methodName := methodNameChecked("GetAwaitingContext")
+	shardApi, ok := api.apisDebug[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetAwaitingContext(ctx, hash)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (uint64, error) {
+	methodName := methodNameChecked("GetTxpoolStatus")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return 0, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTxpoolStatus(ctx)
+	if err != nil {
+		return 0, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetTxpoolContent(
+	ctx context.Context,
+	shardId types.ShardId,
+) ([]*types.Transaction, error) {
+	methodName := methodNameChecked("GetTxpoolContent")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetTxpoolContent(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) SubscribePendingTransactions(
+	ctx context.Context,
+	shardId types.ShardId,
+	fullTransactions bool,
+) (<-chan *rawapitypes.PendingTransaction, error) {
+	methodName := methodNameChecked("SubscribePendingTransactions")
+	shardApi, ok := api.apisRw[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.SubscribePendingTransactions(ctx, fullTransactions)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetPoolContent(
+	ctx context.Context,
+	shardId types.ShardId,
+) (*rawapitypes.PoolContent, error) {
+	methodName := methodNameChecked("GetPoolContent")
+	shardApi, ok := api.apisTxpool[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetPoolContent(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetPoolStatus(
+	ctx context.Context,
+	shardId types.ShardId,
+) (*rawapitypes.PoolStatus, error) {
+	methodName := methodNameChecked("GetPoolStatus")
+	shardApi, ok := api.apisTxpool[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetPoolStatus(ctx)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetPoolTransactionsByAccount(
+	ctx context.Context,
+	address types.Address,
+) (*rawapitypes.PoolContent, error) {
+	methodName := methodNameChecked("GetPoolTransactionsByAccount")
+	shardId := address.ShardId()
+	shardApi, ok := api.apisTxpool[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetPoolTransactionsByAccount(ctx, address)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetSnapshotManifest(
+	ctx context.Context,
+	shardId types.ShardId,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.SnapshotManifest, error) {
+	methodName := methodNameChecked("GetSnapshotManifest")
+	shardApi, ok := api.apisSync[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetSnapshotManifest(ctx, blockReference)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetSnapshotChunk(
+	ctx context.Context,
+	shardId types.ShardId,
+	request rawapitypes.SnapshotChunkRequest,
+) (*rawapitypes.SnapshotChunk, error) {
+	methodName := methodNameChecked("GetSnapshotChunk")
+	shardApi, ok := api.apisSync[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetSnapshotChunk(ctx, request)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+func (api *nodeApiOverShardApis) GetBlocksSince(
+	ctx context.Context,
+	shardId types.ShardId,
+	sequence types.BlockNumber,
+	limit uint64,
+) ([]*types.RawBlockWithExtractedData, error) {
+	methodName := methodNameChecked("GetBlocksSince")
+	shardApi, ok := api.apisSync[shardId]
+	if !ok {
+		return nil, makeShardNotFoundError(methodName, shardId)
+	}
+	result, err := shardApi.GetBlocksSince(ctx, sequence, limit)
+	if err != nil {
+		return nil, makeCallError(methodName, shardId, err)
+	}
+	return result, nil
+}
+
+var errP2pRequestHandlersNotSet = errors.New("request handlers are not set")
+
+func (api *nodeApiOverShardApis) SetP2pRequestHandlers(
+	ctx context.Context,
+	networkManager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	if networkManager == nil {
+		return nil
+	}
+	cfg.handlers = newHandlerRegistry()
+	cfg.chunks = newChunkStore()
+	api.p2p = &p2pHandlers{
+		ctx:            ctx,
+		networkManager: networkManager,
+		cfg:            cfg,
+		logger:         logger,
+	}
+	for _, api := range api.allApis {
+		if err := api.setAsP2pRequestHandlersIfAllowed(ctx, networkManager, cfg, logger); err != nil {
+			logger.Error().
+				Err(err).
+				Stringer(logging.FieldShardId, api.shardId()).
+				Msg("Failed to set raw API request handler")
+			return err
+		}
+	}
+
+	api.p2p.mu.Lock()
+	defer api.p2p.mu.Unlock()
+	api.announceServedShards()
+	return nil
+}
+
+// announceServedShards serves the changed shards and announces them to the peers.
+// It is called with the served shards locked.
+func (api *nodeApiOverShardApis) announceServedShards() {
+	api.setServedShardsHandler()
+	if err := publishServedShards(api.p2p.ctx, api.p2p.networkManager, api.servedShards); err != nil {
+		api.p2p.logger.Warn().Err(err).Msg("Failed to announce served shards")
+	}
+}
+
+// setServedShardsHandler is called with the served shards locked.
+func (api *nodeApiOverShardApis) setServedShardsHandler() {
+	if len(api.servedShards) == 0 {
+		api.p2p.networkManager.RemoveStreamHandler(servedShardsProtocol)
+		return
+	}
+	api.p2p.networkManager.SetRequestHandler(api.p2p.ctx, servedShardsProtocol, packHandlerErrors(
+		chainInterceptors(
+			api.p2p.ctx,
+			servedShardsProtocol,
+			makeGetServedShardsRequestHandler(api.servedShards),
+			api.p2p.cfg.Interceptors),
+		packGetServedShardsError))
+}
+
+func (api *nodeApiOverShardApis) SetShardP2pRequestHandlers(shardId types.ShardId, shardApis NodeApi) error {
+	if api.p2p == nil {
+		return errP2pRequestHandlersNotSet
+	}
+	source, ok := shardApis.(*nodeApiOverShardApis)
+	if !ok {
+		return fmt.Errorf("unexpected node API %T", shardApis)
+	}
+
+	api.p2p.mu.Lock()
+	defer api.p2p.mu.Unlock()
+
+	handlers := api.p2p.cfg.handlers
+	handlers.beginRound(shardId)
+	for _, shardApi := range source.allApis {
+		if shardApi.shardId() != shardId {
+			continue
+		}
+		// The APIs of the shard call the other shards through the node serving them.
+		shardApi.setNodeApi(api)
+		if err := shardApi.setAsP2pRequestHandlersIfAllowed(
+			api.p2p.ctx, api.p2p.networkManager, api.p2p.cfg, api.p2p.logger,
+		); err != nil {
+			return err
+		}
+	}
+	handlers.endRound(api.p2p.networkManager, shardId)
+
+	if served, ok := source.servedShards[shardId]; ok {
+		api.servedShards[shardId] = served
+	} else {
+		delete(api.servedShards, shardId)
+	}
+	api.announceServedShards()
+	return nil
+}
+
+func (api *nodeApiOverShardApis) SetShardReadOnly(shardId types.ShardId, readOnly bool, shardApis NodeApi) error {
+	if api.p2p == nil {
+		return errP2pRequestHandlersNotSet
+	}
+
+	api.p2p.mu.Lock()
+	defer api.p2p.mu.Unlock()
+
+	served, ok := api.servedShards[shardId]
+	if !ok {
+		return fmt.Errorf("shard %d is not served: %w", shardId, rawapitypes.ErrShardNotFound)
+	}
+	if readOnly {
+		api.p2p.cfg.handlers.remove(api.p2p.networkManager, shardId, apiNameRw)
+	} else {
+		source, ok := shardApis.(*nodeApiOverShardApis)
+		if !ok {
+			return fmt.Errorf("unexpected node API %T", shardApis)
+		}
+		// Only the local read-write API makes the shard served in the full mode.
+		if sourceServed, ok := source.servedShards[shardId]; !ok || sourceServed.readOnly {
+			return fmt.Errorf("no local read-write API of shard %d", shardId)
+		}
+		shardApi := source.apisRw[shardId]
+		shardApi.setNodeApi(api)
+		if err := shardApi.setAsP2pRequestHandlersIfAllowed(
+			api.p2p.ctx, api.p2p.networkManager, api.p2p.cfg, api.p2p.logger,
+		); err != nil {
+			return err
+		}
+	}
+
+	served.readOnly = readOnly
+	api.servedShards[shardId] = served
+	api.announceServedShards()
+	return nil
+}
+
+func (api *nodeApiOverShardApis) UnsetShardP2pRequestHandlers(ctx context.Context, shardId types.ShardId) int {
+	if api.p2p == nil {
+		return 0
+	}
+
+	api.p2p.mu.Lock()
+	delete(api.servedShards, shardId)
+	api.announceServedShards()
+	api.p2p.mu.Unlock()
+
+	return api.p2p.cfg.handlers.unset(ctx, api.p2p.networkManager, []types.ShardId{shardId})
+}
+
+func (api *nodeApiOverShardApis) UnsetP2pRequestHandlers(ctx context.Context) int {
+	if api.p2p == nil {
+		return 0
+	}
+
+	api.p2p.mu.Lock()
+	api.p2p.networkManager.RemoveStreamHandler(servedShardsProtocol)
+	api.p2p.mu.Unlock()
+
+	handlers := api.p2p.cfg.handlers
+	return handlers.unset(ctx, api.p2p.networkManager, handlers.shardIds())
 }
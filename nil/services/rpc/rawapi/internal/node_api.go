@@ -26,49 +26,300 @@ type NodeApi interface {
 	) (*types.RawBlockWithExtractedData, error)
 	GetBlockTransactionCount(
 		ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetBlockRange(
+		ctx context.Context,
+		shardId types.ShardId,
+		from types.BlockNumber,
+		count uint64,
+		fullBlocks bool,
+	) ([]*types.RawBlockWithExtractedData, error)
+	// GetShardStats sums up the production of the range of the blocks of the shard, e.g., for network dashboards.
+	GetShardStats(
+		ctx context.Context, shardId types.ShardId, from types.BlockNumber, count uint64,
+	) (*rawapitypes.ShardStats, error)
+	// GetBlockFinalitySignatures returns the signatures of the validators committing the block.
+	GetBlockFinalitySignatures(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.FinalitySignatures, error)
+	// GetValidators returns the validators committing the block and their voting power.
+	GetValidators(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.ValidatorSet, error)
+	// GetValidatorInfo returns the validator of the shard with the public key.
+	GetValidatorInfo(ctx context.Context, shardId types.ShardId, publicKey []byte) (*rawapitypes.ValidatorInfo, error)
+	// GetEpochInfo returns the rotation of the proposers of the next blocks of the shard.
+	GetEpochInfo(ctx context.Context, shardId types.ShardId) (*rawapitypes.EpochInfo, error)
+	SubscribeNewHeads(ctx context.Context, shardId types.ShardId) (<-chan sszx.SSZEncodedData, error)
 
 	GetInTransaction(
 		ctx context.Context,
 		shardId types.ShardId,
 		transactionRequest rawapitypes.TransactionRequest,
 	) (*rawapitypes.TransactionInfo, error)
-	GetInTransactionReceipt(
+	GetReceipt(
 		ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	// GetBounceInfo returns whether the transaction bounced and the value returned to its senders by the bounce
+	// and the refund transactions.
+	GetBounceInfo(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.BounceInfo, error)
+	GetBlockReceipts(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockReference rawapitypes.BlockReference,
+	) ([]*rawapitypes.ReceiptInfo, error)
+	GetOutTransaction(
+		ctx context.Context,
+		shardId types.ShardId,
+		transactionRequest rawapitypes.TransactionRequest,
+	) (*rawapitypes.TransactionInfo, error)
+	GetOutTransactions(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockReference rawapitypes.BlockReference,
+	) ([]*rawapitypes.TransactionInfo, error)
+
+	GetLogs(
+		ctx context.Context, shardId types.ShardId, filter rawapitypes.LogFilter) ([]*rawapitypes.LogInfo, error)
+	SubscribeLogs(
+		ctx context.Context, shardId types.ShardId, filter rawapitypes.LogFilter) (<-chan *rawapitypes.LogInfo, error)
 
 	GetBalance(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
+	// GetBalances returns the balances of the accounts in the order of the addresses, which may be in different shards.
+	// The block reference is resolved in the shard of each account, it is zero if the account doesn't exist.
+	GetBalances(
+		ctx context.Context, addresses []types.Address, blockReference rawapitypes.BlockReference) ([]types.Value, error)
 	GetCode(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error)
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		key common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (types.Uint256, error)
+	// GetStorageRange returns at most limit entries of the storage of the contract starting with the start key.
+	GetStorageRange(
+		ctx context.Context,
+		address types.Address,
+		start common.Hash,
+		limit uint64,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.StorageRange, error)
+	// GetTokens returns the page of the tokens of the account selected by the request.
 	GetTokens(
 		ctx context.Context,
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
-	) (map[types.TokenId]types.Value, error)
+		request rawapitypes.TokensRequest,
+	) (*rawapitypes.TokensPage, error)
+	// GetTokenBalance returns the balance of a single token of the account, it is zero if the account doesn't hold it.
+	GetTokenBalance(
+		ctx context.Context,
+		address types.Address,
+		tokenId types.TokenId,
+		blockReference rawapitypes.BlockReference,
+	) (types.Value, error)
+	// GetTokenInfo returns the metadata of the token read from its contract on the state of the main shard block.
+	GetTokenInfo(
+		ctx context.Context, tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference,
+	) (*rawapitypes.TokenInfo, error)
+	// ListTokensCreatedBy returns the tokens minted by the account, see GetTokenInfo.
+	ListTokensCreatedBy(
+		ctx context.Context, address types.Address, mainBlockReference rawapitypes.BlockReference,
+	) ([]*rawapitypes.TokenInfo, error)
 	GetTransactionCount(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetNextValidSeqno(
+		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
 	GetContract(
 		ctx context.Context,
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	// GetAccountMeta returns the header of the account, which is not marked as existing if there is no account.
+	GetAccountMeta(
+		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.AccountMeta, error)
+	GetProof(
+		ctx context.Context,
+		address types.Address,
+		storageKeys []common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.ContractProof, error)
 
 	Call(
 		ctx context.Context,
 		args rpctypes.CallArgs,
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	EstimateFee(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.FeeEstimation, error)
+	CreateAccessList(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.AccessListResult, error)
+	// SimulateBundle executes the calls one after another in the same block, each of them on the state left by
+	// the previous ones. All the calls must be sent to the same shard.
+	SimulateBundle(
+		ctx context.Context,
+		calls []rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) ([]*rawapitypes.BundleCallResult, error)
+
+	// PrepareDeploy computes the address of the external deployment of the contract to the shard of the request
+	// and simulates the deployment at the latest block to estimate its fee.
+	PrepareDeploy(ctx context.Context, request rawapitypes.DeployRequest) (*rawapitypes.DeployPreparation, error)
 
 	GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
+	// ResolveShard returns the shard owning the address, rawapitypes.ErrInvalidArgument if the address is empty
+	// or its shard is not in the network.
+	ResolveShard(ctx context.Context, address types.Address) (types.ShardId, error)
+	// ResolveShards resolves the shards of the addresses in their order, the malformed ones don't fail the call,
+	// their resolutions carry the errors instead.
+	ResolveShards(ctx context.Context, addresses []types.Address) ([]*rawapitypes.ShardResolution, error)
+	// GetChainConfig returns the constants of the network and the parameters of its shards read from the main shard.
+	GetChainConfig(ctx context.Context) (*rawapitypes.ChainConfig, error)
+	GetSyncStatus(ctx context.Context, shardId types.ShardId) (*rawapitypes.SyncStatus, error)
+	// Health reports the sync status of all the shards followed by the node. A shard is healthy if it is not
+	// being downloaded or replayed and is at most maxBlockLag blocks behind the highest known block.
+	Health(ctx context.Context, maxBlockLag uint64) (*rawapitypes.NodeHealth, error)
 
 	ClientVersion(ctx context.Context) (string, error)
 
 	GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (uint64, error)
 	GetTxpoolContent(ctx context.Context, shardId types.ShardId) ([]*types.Transaction, error)
+	SubscribePendingTransactions(
+		ctx context.Context,
+		shardId types.ShardId,
+		fullTransactions bool,
+	) (<-chan *rawapitypes.PendingTransaction, error)
 
-	SendTransaction(ctx context.Context, shardId types.ShardId, transaction []byte) (txnpool.DiscardReason, error)
+	SendTransaction(
+		ctx context.Context,
+		shardId types.ShardId,
+		transaction []byte,
+		replacement txnpool.ReplacementPolicy,
+		idempotencyKey string,
+	) (*rawapitypes.SendTransactionResult, error)
+	SendTransactionBundle(
+		ctx context.Context,
+		shardId types.ShardId,
+		transactions [][]byte,
+		sameBlock bool,
+	) ([]*rawapitypes.SendTransactionResult, error)
+	SendTransactionAndWatch(
+		ctx context.Context,
+		shardId types.ShardId,
+		transaction []byte,
+		replacement txnpool.ReplacementPolicy,
+		idempotencyKey string,
+	) (<-chan *rawapitypes.TransactionEvent, error)
+	GetTransactionStatus(
+		ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.TransactionStatusInfo, error)
+	ResendTransaction(
+		ctx context.Context,
+		shardId types.ShardId,
+		transaction []byte,
+		replacement txnpool.ReplacementPolicy,
+	) (txnpool.DiscardReason, error)
 	DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error)
 
-	SetP2pRequestHandlers(ctx context.Context, networkManager network.Manager, logger logging.Logger) error
+	TraceTransaction(
+		ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ExecutionTrace, error)
+	TraceCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.ExecutionTrace, error)
+	TraceTransactionStateDiff(
+		ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.StateDiff, error)
+	TraceCallStateDiff(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.StateDiff, error)
+	// ReplayTransaction re-executes the transaction included in a block and compares the result with its receipt.
+	ReplayTransaction(
+		ctx context.Context,
+		shardId types.ShardId,
+		hash common.Hash,
+		options rawapitypes.ReplayOptions,
+	) (*rawapitypes.ReplayResult, error)
+	// GetPendingResponsesFor returns a page of the async requests of the contract awaiting their responses.
+	GetPendingResponsesFor(
+		ctx context.Context,
+		address types.Address,
+		start types.TransactionIndex,
+		limit uint64,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.PendingResponses, error)
+	// GetAwaitingContext returns the async requests whose responses are delivered after the transaction received or
+	// sent by the shard, and whether their callers still await them.
+	GetAwaitingContext(
+		ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.AwaitingContext, error)
+
+	GetPoolContent(ctx context.Context, shardId types.ShardId) (*rawapitypes.PoolContent, error)
+	GetPoolStatus(ctx context.Context, shardId types.ShardId) (*rawapitypes.PoolStatus, error)
+	GetPoolTransactionsByAccount(ctx context.Context, address types.Address) (*rawapitypes.PoolContent, error)
+
+	GetSnapshotManifest(
+		ctx context.Context,
+		shardId types.ShardId,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.SnapshotManifest, error)
+	GetSnapshotChunk(
+		ctx context.Context,
+		shardId types.ShardId,
+		request rawapitypes.SnapshotChunkRequest,
+	) (*rawapitypes.SnapshotChunk, error)
+	GetBlocksSince(
+		ctx context.Context,
+		shardId types.ShardId,
+		sequence types.BlockNumber,
+		limit uint64,
+	) ([]*types.RawBlockWithExtractedData, error)
+
+	// SetP2pRequestHandlers serves the local shard APIs over the network with the handling configured by cfg.
+	SetP2pRequestHandlers(
+		ctx context.Context,
+		networkManager network.Manager,
+		cfg RequestHandlersConfig,
+		logger logging.Logger,
+	) error
+	// UnsetP2pRequestHandlers stops serving the APIs set by SetP2pRequestHandlers, the new requests
+	// are not accepted. The requests being handled are waited for until the context is done,
+	// the number of the requests that are still being handled is returned.
+	UnsetP2pRequestHandlers(ctx context.Context) int
+	// SetShardP2pRequestHandlers serves the local APIs of the shard of shardApis, e.g., built by NodeApiBuilder
+	// for the shard, with the handling configured by SetP2pRequestHandlers. All the APIs the shard was served by
+	// are replaced, the handlers of each API are swapped atomically, so the requests are not dropped.
+	// The local calls of the node are not affected.
+	SetShardP2pRequestHandlers(shardId types.ShardId, shardApis NodeApi) error
+	// UnsetShardP2pRequestHandlers stops serving the APIs of the shard, see UnsetP2pRequestHandlers.
+	UnsetShardP2pRequestHandlers(ctx context.Context, shardId types.ShardId) int
+	// SetShardReadOnly switches the served shard between the read-only and the full protocol set, e.g.,
+	// when the node loses or gains the validator role. The read-write API of the shard is removed if readOnly is set,
+	// otherwise the local one of shardApis, built by NodeApiBuilder with WithLocalShardApiRw, is served.
+	// The rest of the APIs of the shard are not re-registered. The change is announced to the connected peers.
+	SetShardReadOnly(shardId types.ShardId, readOnly bool, shardApis NodeApi) error
 }
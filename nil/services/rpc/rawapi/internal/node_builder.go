@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"context"
+
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/internal/db"
 	"github.com/NilFoundation/nil/nil/internal/network"
@@ -14,15 +16,33 @@ type nodeApiBuilder struct {
 	// common dependencies
 	db             db.ReadOnlyDB
 	networkManager network.Manager
+
+	responseCache ResponseCacheConfig
+	// peerDirectory routes the requests of the network clients to the peers serving their shards if it is set.
+	peerDirectory *ShardPeerDirectory
+	// retainedStateBlocks is the number of the latest blocks whose state is served by the local APIs,
+	// the state of all the blocks is served if it is zero.
+	retainedStateBlocks uint64
+	// syncProgress are the sources of the sync status reported by the local APIs of the shards.
+	syncProgress map[types.ShardId]SyncProgressSource
+	// newBlocks are the sources of the blocks watched by the subscriptions of the local APIs of the shards.
+	newBlocks map[types.ShardId]NewBlocksSource
+	// rejectInvalidTransactions makes the local read-write APIs reject the transactions that fail the checks.
+	rejectInvalidTransactions bool
 }
 
 func NodeApiBuilder(db db.DB, networkManager network.Manager) *nodeApiBuilder {
 	return &nodeApiBuilder{
 		nodeApi: &nodeApiOverShardApis{
-			apisRo:  make(map[types.ShardId]shardApiRo),
-			apisRw:  make(map[types.ShardId]shardApiRw),
-			apisDev: make(map[types.ShardId]shardApiDev),
-			allApis: make([]shardApiBase, 0),
+			apisRo:     make(map[types.ShardId]shardApiRo),
+			apisRw:     make(map[types.ShardId]shardApiRw),
+			apisDev:    make(map[types.ShardId]shardApiDev),
+			apisDebug:  make(map[types.ShardId]shardApiDebug),
+			apisTxpool: make(map[types.ShardId]shardApiTxpool),
+			apisSync:   make(map[types.ShardId]shardApiSync),
+			allApis:    make([]shardApiBase, 0),
+
+			servedShards: make(map[types.ShardId]servedShard),
 		},
 		db:             db,
 		networkManager: networkManager,
@@ -38,35 +58,120 @@ func (nb *nodeApiBuilder) BuildAndReset() NodeApi {
 	return &rv
 }
 
+// WithPrunedState makes the local APIs added after it serve the state of the latest retainedBlocks blocks only,
+// as the non-archive nodes do. The requests of the state of the older blocks fail with rawapitypes.ErrStatePruned.
+func (nb *nodeApiBuilder) WithPrunedState(retainedBlocks uint64) *nodeApiBuilder {
+	nb.retainedStateBlocks = retainedBlocks
+	return nb
+}
+
+// WithTransactionPreValidation makes the local read-write APIs added after it reject the transactions that fail
+// the checks of the block generator, instead of adding them to the pool to fail in a block.
+func (nb *nodeApiBuilder) WithTransactionPreValidation() *nodeApiBuilder {
+	nb.rejectInvalidTransactions = true
+	return nb
+}
+
+// WithSyncProgress makes the local APIs of the shard added after it report the progress of its synchronization.
+func (nb *nodeApiBuilder) WithSyncProgress(shardId types.ShardId, source SyncProgressSource) *nodeApiBuilder {
+	if nb.syncProgress == nil {
+		nb.syncProgress = make(map[types.ShardId]SyncProgressSource)
+	}
+	nb.syncProgress[shardId] = source
+	return nb
+}
+
+// WithNewBlocks makes the subscriptions of the local APIs of the shard added after it watch the blocks
+// committed by the source. Without it, the subscriptions to the blocks of the shard are not served.
+func (nb *nodeApiBuilder) WithNewBlocks(shardId types.ShardId, source NewBlocksSource) *nodeApiBuilder {
+	if nb.newBlocks == nil {
+		nb.newBlocks = make(map[types.ShardId]NewBlocksSource)
+	}
+	nb.newBlocks[shardId] = source
+	return nb
+}
+
+func (nb *nodeApiBuilder) newLocalShardApiRo(shardId types.ShardId) *localShardApiRo {
+	api := newLocalShardApiRo(shardId, nb.db)
+	api.retainedStateBlocks = nb.retainedStateBlocks
+	if nb.networkManager != nil {
+		api.protocolVersion = nb.networkManager.ProtocolVersion()
+	}
+	api.syncProgress = nb.syncProgress[shardId]
+	api.newBlocks = nb.newBlocks[shardId]
+	nb.nodeApi.accessors = append(nb.nodeApi.accessors, api.accessor)
+	return api
+}
+
 func (nb *nodeApiBuilder) WithLocalShardApiRo(shardId types.ShardId) *nodeApiBuilder {
-	var localShardApi shardApiRo = newLocalShardApiRo(shardId, nb.db)
+	var localShardApi shardApiRo = nb.newLocalShardApiRo(shardId)
 	if assert.Enable {
 		localShardApi = newShardApiClientDirectEmulatorRo(localShardApi)
 	}
 	nb.nodeApi.apisRo[shardId] = localShardApi
 	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	nb.nodeApi.addServedShard(shardId, servedShard{readOnly: true, archive: nb.retainedStateBlocks == 0})
 	return nb
 }
 
 func (nb *nodeApiBuilder) WithLocalShardApiRw(shardId types.ShardId, txnpool txnpool.Pool) *nodeApiBuilder {
-	var localShardApi shardApiRw = newLocalShardApiRw(newLocalShardApiRo(shardId, nb.db), txnpool)
+	api := newLocalShardApiRw(nb.newLocalShardApiRo(shardId), txnpool)
+	api.rejectInvalid = nb.rejectInvalidTransactions
+	var localShardApi shardApiRw = api
 	if assert.Enable {
 		localShardApi = newShardApiClientDirectEmulatorRw(localShardApi)
 	}
 	nb.nodeApi.apisRw[shardId] = localShardApi
 	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	nb.nodeApi.addServedShard(shardId, servedShard{readOnly: false, archive: nb.retainedStateBlocks == 0})
 	return nb
 }
 
+// WithResponseCache enables the cache of the immutable responses for the read-only network clients added after it.
+func (nb *nodeApiBuilder) WithResponseCache(cfg ResponseCacheConfig) *nodeApiBuilder {
+	nb.responseCache = cfg
+	return nb
+}
+
+// WithShardPeerDirectory makes the network clients added after it send the requests to the peers known
+// by the directory to serve the API of the shard.
+func (nb *nodeApiBuilder) WithShardPeerDirectory(directory *ShardPeerDirectory) *nodeApiBuilder {
+	nb.peerDirectory = directory
+	return nb
+}
+
+func (nb *nodeApiBuilder) peerSelector(shardId types.ShardId, writable bool) PeerSelector {
+	if nb.peerDirectory == nil {
+		return selectFirstPeer
+	}
+	return nb.peerDirectory.PeerSelector(shardId, writable)
+}
+
 func (nb *nodeApiBuilder) WithNetworkShardApiClientRo(shardId types.ShardId) *nodeApiBuilder {
-	networkShardApiClient := newShardApiClientNetworkRo(shardId, nb.networkManager)
+	networkShardApiClient := newShardApiClientNetworkRo(
+		shardId, nb.networkManager, nb.responseCache, nb.peerSelector(shardId, false))
+	nb.nodeApi.apisRo[shardId] = networkShardApiClient
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, networkShardApiClient)
+	return nb
+}
+
+// WithCachingNetworkShardApiClientRo adds the read-only network client caching the responses locally,
+// see NewCachingShardApiClient, instead of the one of WithNetworkShardApiClientRo. The peers are chosen
+// by the directory if the config has no selector. The subscription of the client is ended when ctx is done.
+func (nb *nodeApiBuilder) WithCachingNetworkShardApiClientRo(
+	ctx context.Context, shardId types.ShardId, config CachingClientConfig,
+) *nodeApiBuilder {
+	if config.PeerSelector == nil {
+		config.PeerSelector = nb.peerSelector(shardId, false)
+	}
+	networkShardApiClient := newCachingShardApiClient(ctx, nb.networkManager, shardId, config)
 	nb.nodeApi.apisRo[shardId] = networkShardApiClient
 	nb.nodeApi.allApis = append(nb.nodeApi.allApis, networkShardApiClient)
 	return nb
 }
 
 func (nb *nodeApiBuilder) WithNetworkShardApiClientRw(shardId types.ShardId) *nodeApiBuilder {
-	networkShardApiClient := newShardApiClientNetworkRw(shardId, nb.networkManager)
+	networkShardApiClient := newShardApiClientNetworkRw(shardId, nb.networkManager, nb.peerSelector(shardId, true))
 	nb.nodeApi.apisRw[shardId] = networkShardApiClient
 	nb.nodeApi.allApis = append(nb.nodeApi.allApis, networkShardApiClient)
 	return nb
@@ -79,8 +184,113 @@ func (nb *nodeApiBuilder) WithLocalShardApiDev(shardId types.ShardId) *nodeApiBu
 }
 
 func (nb *nodeApiBuilder) WithNetworkShardApiClientDev(shardId types.ShardId) *nodeApiBuilder {
-	networkDevApiClient := newShardApiClientNetworkDev(shardId, nb.networkManager)
+	networkDevApiClient := newShardApiClientNetworkDev(shardId, nb.networkManager, nb.peerSelector(shardId, false))
 	nb.nodeApi.apisDev[shardId] = networkDevApiClient
 	nb.nodeApi.allApis = append(nb.nodeApi.allApis, networkDevApiClient)
 	return nb
 }
+
+func (nb *nodeApiBuilder) WithLocalShardApiDebug(shardId types.ShardId) *nodeApiBuilder {
+	localShardApi := newLocalShardApiDebug(nb.newLocalShardApiRo(shardId))
+	nb.nodeApi.apisDebug[shardId] = localShardApi
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	return nb
+}
+
+func (nb *nodeApiBuilder) WithNetworkShardApiClientDebug(shardId types.ShardId) *nodeApiBuilder {
+	networkDebugApiClient := newShardApiClientNetworkDebug(shardId, nb.networkManager, nb.peerSelector(shardId, false))
+	nb.nodeApi.apisDebug[shardId] = networkDebugApiClient
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, networkDebugApiClient)
+	return nb
+}
+
+func (nb *nodeApiBuilder) WithLocalShardApiTxpool(shardId types.ShardId, txnpool txnpool.Pool) *nodeApiBuilder {
+	localShardApi := newLocalShardApiTxpool(shardId, txnpool)
+	nb.nodeApi.apisTxpool[shardId] = localShardApi
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	return nb
+}
+
+func (nb *nodeApiBuilder) WithNetworkShardApiClientTxpool(shardId types.ShardId) *nodeApiBuilder {
+	networkTxpoolApiClient := newShardApiClientNetworkTxpool(shardId, nb.networkManager, nb.peerSelector(shardId, true))
+	nb.nodeApi.apisTxpool[shardId] = networkTxpoolApiClient
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, networkTxpoolApiClient)
+	return nb
+}
+
+func (nb *nodeApiBuilder) WithLocalShardApiSync(shardId types.ShardId) *nodeApiBuilder {
+	localShardApi := newLocalShardApiSync(nb.newLocalShardApiRo(shardId))
+	nb.nodeApi.apisSync[shardId] = localShardApi
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	return nb
+}
+
+func (nb *nodeApiBuilder) WithNetworkShardApiClientSync(shardId types.ShardId) *nodeApiBuilder {
+	networkSyncApiClient := newShardApiClientNetworkSync(shardId, nb.networkManager, nb.peerSelector(shardId, false))
+	nb.nodeApi.apisSync[shardId] = networkSyncApiClient
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, networkSyncApiClient)
+	return nb
+}
+
+// WithLocalProofApi serves the proofs of the main shard blocks provided by the source, e.g., by the prover.
+// The API is served without a source as well, its requests fail with rawapitypes.ErrNotFound then.
+func (nb *nodeApiBuilder) WithLocalProofApi(source StateProofSource) *nodeApiBuilder {
+	localShardApi := newLocalShardApiProof(nb.newLocalShardApiRo(types.MainShardId), source)
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	return nb
+}
+
+// WithLocalClusterApi serves the queries about the whole network under the main shard. The shards are queried
+// with the APIs of the node, local or network ones, the read-write ones are needed for the pending block only.
+func (nb *nodeApiBuilder) WithLocalClusterApi() *nodeApiBuilder {
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, newLocalShardApiCluster())
+	return nb
+}
+
+// WithLocalFaucetApi serves the faucet of a devnet under the main shard. The transactions of the faucet are sent
+// with the read-write API of the node, which has to serve the shard of the faucet.
+func (nb *nodeApiBuilder) WithLocalFaucetApi(cfg FaucetApiConfig) *nodeApiBuilder {
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, newLocalShardApiFaucet(cfg))
+	return nb
+}
+
+// WithLocalDevnetApi serves the controls of the chains of a single-node devnet under the main shard
+// if the operators can be authenticated.
+// The controls must include the main shard, the blocks of the shards without controls are not mined.
+func (nb *nodeApiBuilder) WithLocalDevnetApi(
+	cfg DevnetApiConfig, controls map[types.ShardId]ShardChainControl,
+) *nodeApiBuilder {
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, newLocalShardApiDevnet(cfg, controls))
+	return nb
+}
+
+// WithLocalAdminApi serves the admin API of the node under the main shard if the operators can be authenticated.
+// FlushCaches drops the caches of all the local APIs of the node, including the ones added after it.
+// The ACL of the node can be managed by the API if accessControl is not nil,
+// and the usage of the peers is exposed if quotas is not nil.
+func (nb *nodeApiBuilder) WithLocalAdminApi(
+	cfg AdminApiConfig, accessControl *AccessControl, quotas *PeerQuotas,
+) *nodeApiBuilder {
+	nodeApi := nb.nodeApi
+	localShardApi := newLocalShardApiAdmin(
+		types.MainShardId, nb.db, nb.networkManager, cfg, accessControl, quotas, nodeApi.flushCaches)
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	return nb
+}
+
+// WithLocalDbApi serves the raw reads of the database of the node under the main shard
+// if the operators can be authenticated.
+func (nb *nodeApiBuilder) WithLocalDbApi(cfg DbApiConfig) *nodeApiBuilder {
+	localShardApi := newLocalShardApiDb(types.MainShardId, nb.db, cfg)
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, localShardApi)
+	return nb
+}
+
+// WithLocalConsensusApi serves the state of the consensus of the shards of the node under the main shard
+// if the operators can be authenticated.
+func (nb *nodeApiBuilder) WithLocalConsensusApi(
+	cfg ConsensusApiConfig, consensuses map[types.ShardId]ShardConsensus,
+) *nodeApiBuilder {
+	nb.nodeApi.allApis = append(nb.nodeApi.allApis, newLocalShardApiConsensus(cfg, consensuses))
+	return nb
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+)
+
+// apiParityError lists all the differences between an API interface and its NetworkTransportProtocol* interface.
+type apiParityError struct {
+	api       reflect.Type
+	transport reflect.Type
+
+	// missing are the API methods not found in the transport interface.
+	missing []string
+	// extra are the transport methods not found in the API interface.
+	extra []string
+	// mismatched are the errors of the API methods not matching their Protobuf messages.
+	mismatched map[string]error
+}
+
+func (e *apiParityError) empty() bool {
+	return len(e.missing) == 0 && len(e.extra) == 0 && len(e.mismatched) == 0
+}
+
+// Error returns the diff of the interfaces, one line per method:
+// "-" marks the methods missing from the transport, "+" marks the extra ones, "~" marks the mismatched ones.
+func (e *apiParityError) Error() string {
+	lines := make([]string, 0, len(e.missing)+len(e.extra)+len(e.mismatched))
+	for _, name := range e.missing {
+		lines = append(lines, fmt.Sprintf("- %s: method %s not found in %s", name, name, e.transport))
+	}
+	for _, name := range e.extra {
+		lines = append(lines, fmt.Sprintf("+ %s: method %s not found in %s", name, name, e.api))
+	}
+	for name, err := range e.mismatched {
+		lines = append(lines, fmt.Sprintf("~ %s: %v", name, err))
+	}
+	slices.SortFunc(lines, func(a, b string) int {
+		return strings.Compare(a[2:], b[2:])
+	})
+	return fmt.Sprintf("%s does not match %s:\n\t%s", e.api, e.transport, strings.Join(lines, "\n\t"))
+}
+
+// servedApis are the API interfaces of the package together with their transport interfaces.
+// The APIs whose methods only read the data are marked as read-only, as listed by ListSupportedMethods.
+var servedApis = []struct {
+	name      string
+	api       reflect.Type
+	transport reflect.Type
+	readOnly  bool
+}{
+	{apiNameRo, reflect.TypeFor[shardApiRo](), reflect.TypeFor[NetworkTransportProtocolRo](), true},
+	{apiNameRw, reflect.TypeFor[shardApiRw](), reflect.TypeFor[NetworkTransportProtocolRw](), false},
+	{apiNameDev, reflect.TypeFor[shardApiDev](), reflect.TypeFor[NetworkTransportProtocolDev](), false},
+	{apiNameDebug, reflect.TypeFor[shardApiDebug](), reflect.TypeFor[NetworkTransportProtocolDebug](), true},
+	{apiNameTxpool, reflect.TypeFor[shardApiTxpool](), reflect.TypeFor[NetworkTransportProtocolTxpool](), true},
+	{apiNameSync, reflect.TypeFor[shardApiSync](), reflect.TypeFor[NetworkTransportProtocolSync](), true},
+	{apiNameProof, reflect.TypeFor[shardApiProof](), reflect.TypeFor[NetworkTransportProtocolProof](), true},
+	{apiNameAdmin, reflect.TypeFor[shardApiAdmin](), reflect.TypeFor[NetworkTransportProtocolAdmin](), false},
+	{apiNameDb, reflect.TypeFor[shardApiDb](), reflect.TypeFor[NetworkTransportProtocolDb](), true},
+	{apiNameConsensus, reflect.TypeFor[shardApiConsensus](), reflect.TypeFor[NetworkTransportProtocolConsensus](), true},
+	{apiNameCluster, reflect.TypeFor[shardApiCluster](), reflect.TypeFor[NetworkTransportProtocolCluster](), true},
+	{apiNameFaucet, reflect.TypeFor[shardApiFaucet](), reflect.TypeFor[NetworkTransportProtocolFaucet](), false},
+	{apiNameDevnet, reflect.TypeFor[shardApiDevnet](), reflect.TypeFor[NetworkTransportProtocolDevnet](), false},
+}
+
+// The APIs are validated on initialization, so that a mismatch fails any binary or test using the package
+// with the diff of all the methods, instead of the first request handler or client being created.
+func init() {
+	check.PanicIfErr(validateServedApis())
+}
+
+func validateServedApis() error {
+	var errs []error
+	methodNames := make(map[string]struct{})
+	for _, served := range servedApis {
+		if _, err := newApiCodec(served.api, served.transport); err != nil {
+			errs = append(errs, err)
+		}
+		for method := range common.Filter(iterMethods(served.api), isExportedMethod) {
+			methodNames[method.Name] = struct{}{}
+		}
+	}
+	errs = append(errs, validateDeprecatedNames(methodNames))
+	return errors.Join(errs...)
+}
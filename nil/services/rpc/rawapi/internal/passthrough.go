@@ -0,0 +1,19 @@
+package internal
+
+import (
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+)
+
+// passthroughResponses encode the successful results of the methods returning the stored encodings
+// straight into the response, instead of building the Protobuf response and marshalling it.
+// The output must be decoded by the response type of the method the same way as the packed response.
+// The errors are packed as usual.
+var passthroughResponses = map[string]func(result any) ([]byte, error){
+	"GetFullBlockData": func(result any) ([]byte, error) {
+		block, ok := result.(*types.RawBlockWithExtractedData)
+		check.PanicIfNotf(ok, "unexpected result type: %T", result)
+		return pb.MarshalRawFullBlockResponse(block)
+	},
+}
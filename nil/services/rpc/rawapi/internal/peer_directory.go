@@ -0,0 +1,293 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// servedShardsVersion is the version of the schema of the served shards. An incompatible change of it
+	// adds a new version, so the nodes don't query nor announce the shards in the schema the peers can't read.
+	servedShardsVersion = "1.0"
+
+	// servedShardsProtocol is served by the node as a whole rather than by the API of a shard.
+	servedShardsProtocol network.ProtocolID = "/rawapi/" + servedShardsVersion + "/GetServedShards"
+
+	// servedShardsTopic is the topic the nodes announce the changes of the shards they serve on.
+	servedShardsTopic = "/rawapi/" + servedShardsVersion + "/servedShards"
+)
+
+// servedShard describes the APIs of a shard served by a node.
+type servedShard struct {
+	// readOnly is set if only the read-only API of the shard is served.
+	readOnly bool
+	// archive is set if the node keeps the state of all the blocks of the shard.
+	archive bool
+}
+
+func packServedShards(servedShards map[types.ShardId]servedShard) []byte {
+	shards := make([]*pb.ServedShard, 0, len(servedShards))
+	for _, shardId := range slices.Sorted(maps.Keys(servedShards)) {
+		shards = append(shards, &pb.ServedShard{
+			ShardId:  uint32(shardId),
+			ReadOnly: servedShards[shardId].readOnly,
+			Archive:  servedShards[shardId].archive,
+		})
+	}
+	response, err := proto.Marshal(
+		&pb.GetServedShardsResponse{Result: &pb.GetServedShardsResponse_Data{Data: &pb.ServedShards{Shards: shards}}})
+	check.PanicIfErr(err)
+	return response
+}
+
+func unpackServedShards(response []byte) (map[types.ShardId]servedShard, error) {
+	var shardsResponse pb.GetServedShardsResponse
+	if err := proto.Unmarshal(response, &shardsResponse); err != nil {
+		return nil, fmt.Errorf("failed to unpack Protobuf response: %w", err)
+	}
+	switch shardsResponse.GetResult().(type) {
+	case *pb.GetServedShardsResponse_Error:
+		return nil, shardsResponse.GetError().UnpackProtoMessage()
+	case *pb.GetServedShardsResponse_Data:
+		shards := make(map[types.ShardId]servedShard, len(shardsResponse.GetData().GetShards()))
+		for _, shard := range shardsResponse.GetData().GetShards() {
+			shards[types.ShardId(shard.GetShardId())] = servedShard{readOnly: shard.GetReadOnly(), archive: shard.GetArchive()}
+		}
+		return shards, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+func makeGetServedShardsRequestHandler(servedShards map[types.ShardId]servedShard) network.RequestHandler {
+	response := packServedShards(servedShards)
+	return func(context.Context, []byte) ([]byte, error) {
+		return response, nil
+	}
+}
+
+// publishServedShards announces the shards served by the node to the peers, so that they don't have to wait
+// for the next refresh of their directories.
+func publishServedShards(
+	ctx context.Context, networkManager network.Manager, servedShards map[types.ShardId]servedShard,
+) error {
+	return networkManager.PubSub().Publish(ctx, servedShardsTopic, packServedShards(servedShards))
+}
+
+func packGetServedShardsError(err error) []byte {
+	response, packErr := proto.Marshal(
+		&pb.GetServedShardsResponse{Result: &pb.GetServedShardsResponse_Error{Error: new(pb.Error).PackProtoMessage(err)}})
+	check.PanicIfErr(packErr)
+	return response
+}
+
+// getServedShards returns the shards served by the peer.
+func getServedShards(
+	ctx context.Context, networkManager network.Manager, peerId network.PeerID,
+) (map[types.ShardId]servedShard, error) {
+	request, err := proto.Marshal(&pb.GetServedShardsRequest{})
+	if err != nil {
+		return nil, err
+	}
+	response, err := networkManager.SendRequestAndGetResponse(ctx, peerId, servedShardsProtocol, request)
+	if err != nil {
+		return nil, err
+	}
+	return unpackServedShards(response)
+}
+
+// listServedShard describes the shard served by the peer by the listing of its methods, see ListSupportedMethods.
+// The listing doesn't tell if the peer keeps the state of all the blocks, so the shard is not an archive one.
+func listServedShard(
+	ctx context.Context, networkManager network.Manager, peerId network.PeerID, shardId types.ShardId,
+) (servedShard, error) {
+	methods, err := ListSupportedMethods(ctx, networkManager, peerId, shardId)
+	if err != nil {
+		return servedShard{}, err
+	}
+	writable := slices.ContainsFunc(methods, func(method SupportedMethod) bool {
+		return method.Api == apiNameRw
+	})
+	return servedShard{readOnly: !writable}, nil
+}
+
+// ShardPeerDirectory tracks the shards served by the connected peers, so the requests to a shard
+// are routed to the peers serving it. The directory is refreshed by querying the peers serving
+// the GetServedShards protocol, the peers that can't be queried keep their previous entries
+// until they disconnect. The entries of the peers announcing the changes of their shards are updated at once.
+// The peers not serving GetServedShards, e.g., the ones predating it, are described by the listings
+// of their methods in every shard of the cluster.
+type ShardPeerDirectory struct {
+	networkManager network.Manager
+	nShards        uint32
+	logger         logging.Logger
+
+	mu    sync.RWMutex
+	peers map[network.PeerID]map[types.ShardId]servedShard
+}
+
+func NewShardPeerDirectory(networkManager network.Manager, nShards uint32) *ShardPeerDirectory {
+	return &ShardPeerDirectory{
+		networkManager: networkManager,
+		nShards:        nShards,
+		logger:         logging.NewLogger("shard_peer_directory"),
+		peers:          make(map[network.PeerID]map[types.ShardId]servedShard),
+	}
+}
+
+// Refresh queries the connected peers for the shards they serve, listing the methods of the ones
+// not serving GetServedShards.
+func (d *ShardPeerDirectory) Refresh(ctx context.Context) error {
+	peers := d.networkManager.GetPeersForProtocol(servedShardsProtocol)
+
+	d.mu.RLock()
+	previous := d.peers
+	d.mu.RUnlock()
+
+	refreshed := make(map[network.PeerID]map[types.ShardId]servedShard, len(peers))
+	var errs []error
+	for _, peerId := range peers {
+		shards, err := getServedShards(ctx, d.networkManager, peerId)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("peer %s: %w", peerId, err))
+			shards = previous[peerId]
+		}
+		if shards != nil {
+			refreshed[peerId] = shards
+		}
+	}
+
+	for shardId := range types.ShardId(d.nShards) {
+		listProtocol := makeProtocolId(shardId, apiNameRo, listSupportedMethodsMethodName)
+		for _, peerId := range d.networkManager.GetPeersForProtocol(listProtocol) {
+			if slices.Contains(peers, peerId) {
+				continue
+			}
+			shard, err := listServedShard(ctx, d.networkManager, peerId, shardId)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("peer %s: shard %d: %w", peerId, shardId, err))
+				var ok bool
+				if shard, ok = previous[peerId][shardId]; !ok {
+					continue
+				}
+			}
+			if refreshed[peerId] == nil {
+				refreshed[peerId] = make(map[types.ShardId]servedShard)
+			}
+			refreshed[peerId][shardId] = shard
+		}
+	}
+
+	d.mu.Lock()
+	d.peers = refreshed
+	d.mu.Unlock()
+	return errors.Join(errs...)
+}
+
+// update replaces the entry of the peer with the shards it has announced.
+func (d *ShardPeerDirectory) update(peerId network.PeerID, shards map[types.ShardId]servedShard) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	peers := maps.Clone(d.peers)
+	peers[peerId] = shards
+	d.peers = peers
+}
+
+// Run refreshes the directory with the given interval and applies the changes announced by the peers
+// until the context is done.
+func (d *ShardPeerDirectory) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var announcements <-chan network.PubSubMessage
+	if sub, err := d.networkManager.PubSub().Subscribe(servedShardsTopic); err != nil {
+		d.logger.Warn().Err(err).Msg("Failed to subscribe to served shards announcements")
+	} else {
+		defer sub.Close()
+		announcements = sub.Start(ctx, true)
+	}
+
+	refresh := true
+	for {
+		if refresh {
+			if err := d.Refresh(ctx); err != nil && ctx.Err() == nil {
+				d.logger.Warn().Err(err).Msg("Failed to refresh shard peer directory")
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh = true
+		case msg, ok := <-announcements:
+			refresh = false
+			if !ok {
+				announcements = nil
+				continue
+			}
+			shards, err := unpackServedShards(msg.Data)
+			if err != nil {
+				d.logger.Warn().Err(err).Stringer(logging.FieldPeerId, msg.From).
+					Msg("Failed to unpack served shards announcement")
+				continue
+			}
+			// The announcements are relayed by the other peers, the entry of the publisher is updated.
+			d.update(msg.From, shards)
+		}
+	}
+}
+
+// Peers returns the known peers serving the shard, only the ones serving its read-write API if writable is set.
+func (d *ShardPeerDirectory) Peers(shardId types.ShardId, writable bool) []network.PeerID {
+	return d.findPeers(shardId, func(shard servedShard) bool {
+		return !shard.readOnly || !writable
+	})
+}
+
+// ArchivePeers returns the known peers keeping the state of all the blocks of the shard,
+// which can serve the requests of the historical state.
+func (d *ShardPeerDirectory) ArchivePeers(shardId types.ShardId) []network.PeerID {
+	return d.findPeers(shardId, func(shard servedShard) bool {
+		return shard.archive
+	})
+}
+
+func (d *ShardPeerDirectory) findPeers(shardId types.ShardId, matches func(servedShard) bool) []network.PeerID {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var peers []network.PeerID
+	for peerId, shards := range d.peers {
+		if shard, ok := shards[shardId]; ok && matches(shard) {
+			peers = append(peers, peerId)
+		}
+	}
+	slices.Sort(peers)
+	return peers
+}
+
+// PeerSelector returns the selector preferring the peers known to serve the shard.
+// The first of the peers is chosen if none of them is known, e.g., before the directory is refreshed.
+func (d *ShardPeerDirectory) PeerSelector(shardId types.ShardId, writable bool) PeerSelector {
+	return func(peers []network.PeerID) (network.PeerID, error) {
+		known := d.Peers(shardId, writable)
+		for _, peerId := range peers {
+			if slices.Contains(known, peerId) {
+				return peerId, nil
+			}
+		}
+		return peers[0], nil
+	}
+}
@@ -0,0 +1,293 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+const (
+	// defaultQuotaWindow is the period the usage of a peer is limited over if the window is not configured.
+	defaultQuotaWindow = time.Minute
+
+	// maxAccountedPeers is the number of peers whose usage is accounted. The least recently seen peers
+	// are forgotten.
+	maxAccountedPeers = 4096
+
+	// maxBannedPeers is the number of bans kept. The bans are kept apart from the usages, so that
+	// a peer can't lift its ban by pushing itself out of the accounted peers with other peer IDs.
+	maxBannedPeers = 4096
+)
+
+// executingMethods are the methods executing the contracts, the CPU time they take is accounted as the call time.
+var executingMethods = map[string]struct{}{
+	"Call":               {},
+	"EstimateFee":        {},
+	"CreateAccessList":   {},
+	"SimulateBundle":     {},
+	"PrepareDeploy":      {},
+	"TraceCall":          {},
+	"TraceCallStateDiff": {},
+	"TraceTransaction":   {},
+	"ReplayTransaction":  {},
+}
+
+// PeerQuota limits the usage of the raw API by a peer within a window. Zero values disable the corresponding limit.
+type PeerQuota struct {
+	Requests uint64 `yaml:"requests,omitempty"`
+	// Bytes limits the total size of the requests and the responses.
+	Bytes uint64 `yaml:"bytes,omitempty"`
+	// CallTime limits the CPU time spent executing the calls of the peer, e.g., of Call and EstimateFee.
+	// It is only accounted on Linux.
+	CallTime time.Duration `yaml:"callTime,omitempty"`
+}
+
+// PeerQuotaConfig configures the accounting of the usage of the raw API by peer.
+type PeerQuotaConfig struct {
+	// Window is the period the quota applies to, the usage counted towards it is reset every window.
+	// The default window is used if it is zero.
+	Window time.Duration `yaml:"window,omitempty"`
+	Quota  PeerQuota     `yaml:"quota,omitempty"`
+	// BanDuration is the time the requests of a peer exceeding its quota are rejected for.
+	// The peers are only reported if it is zero.
+	BanDuration time.Duration `yaml:"banDuration,omitempty"`
+}
+
+type peerUsage struct {
+	total rawapitypes.PeerUsage
+
+	windowStart    time.Time
+	windowRequests uint64
+	windowBytes    uint64
+	windowCallTime time.Duration
+	// reported is set once the peer is reported in the window.
+	reported bool
+}
+
+// exceeded returns the limits of the quota exceeded in the window, empty if there are none.
+func (u *peerUsage) exceeded(quota PeerQuota) []string {
+	var limits []string
+	if quota.Requests > 0 && u.windowRequests > quota.Requests {
+		limits = append(limits, "requests")
+	}
+	if quota.Bytes > 0 && u.windowBytes > quota.Bytes {
+		limits = append(limits, "bytes")
+	}
+	if quota.CallTime > 0 && u.windowCallTime > quota.CallTime {
+		limits = append(limits, "callTime")
+	}
+	return limits
+}
+
+// PeerQuotas accounts the usage of the raw API by peer and bans the peers exceeding their quotas for a while.
+// The peers exceeding the quotas are logged and reported to the peer reputation tracker of the network.
+// The usage is exposed by the admin API.
+type PeerQuotas struct {
+	config PeerQuotaConfig
+	// networkManager is nil if the peers are not reported to the network.
+	networkManager network.Manager
+	logger         logging.Logger
+
+	// mu protects the usages and the bans, since they are updated on every request.
+	mu    sync.Mutex
+	peers *lru.Cache[network.PeerID, *peerUsage]
+	// bans are the ends of the bans of the peers.
+	bans map[network.PeerID]time.Time
+}
+
+func NewPeerQuotas(config PeerQuotaConfig, networkManager network.Manager) *PeerQuotas {
+	if config.Window <= 0 {
+		config.Window = defaultQuotaWindow
+	}
+	return &PeerQuotas{
+		config:         config,
+		networkManager: networkManager,
+		logger:         logging.NewLogger("rawapi_quotas"),
+		peers:          mustCreate(lru.New[network.PeerID, *peerUsage](maxAccountedPeers)),
+		bans:           make(map[network.PeerID]time.Time),
+	}
+}
+
+// Usage returns the usage of the accounted peers and the banned ones ordered by peer ID.
+func (q *PeerQuotas) Usage() []rawapitypes.PeerUsage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.pruneBans(time.Now())
+	usages := make([]rawapitypes.PeerUsage, 0, q.peers.Len())
+	for _, usage := range q.peers.Values() {
+		total := usage.total
+		total.BannedUntil = q.bans[total.PeerId]
+		usages = append(usages, total)
+	}
+	for peerId, bannedUntil := range q.bans {
+		// The usage of the banned peers that are no longer accounted is forgotten.
+		if !q.peers.Contains(peerId) {
+			usages = append(usages, rawapitypes.PeerUsage{PeerId: peerId, BannedUntil: bannedUntil})
+		}
+	}
+	slices.SortFunc(usages, func(a, b rawapitypes.PeerUsage) int {
+		return strings.Compare(string(a.PeerId), string(b.PeerId))
+	})
+	return usages
+}
+
+// Interceptor creates an interceptor accounting the requests of the peers and rejecting the requests
+// of the banned ones with rawapitypes.ErrRateLimited. The requests of unknown peers and the service
+// protocols are not accounted.
+func (q *PeerQuotas) Interceptor() RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		if isServiceProtocol(protocol) {
+			return next
+		}
+		_, executing := executingMethods[path.Base(string(protocol))]
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			peerId, ok := network.RequestPeer(ctx)
+			if !ok {
+				return next(ctx, request)
+			}
+			if bannedUntil, banned := q.bannedUntil(peerId); banned {
+				return nil, rawapitypes.NewError(rawapitypes.RateLimitedErrorCode, fmt.Errorf(
+					"%w: peer is banned for exceeding its quota until %s",
+					rawapitypes.ErrRateLimited, bannedUntil.Format(time.RFC3339)))
+			}
+
+			var response []byte
+			var err error
+			var callTime time.Duration
+			if executing {
+				callTime = callCpuTime(func() {
+					response, err = next(ctx, request)
+				})
+			} else {
+				response, err = next(ctx, request)
+			}
+			q.account(peerId, len(request), len(response), callTime)
+			return response, err
+		}
+	}
+}
+
+func (q *PeerQuotas) bannedUntil(peerId network.PeerID) (time.Time, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	bannedUntil, ok := q.bans[peerId]
+	if !ok || !bannedUntil.After(time.Now()) {
+		return time.Time{}, false
+	}
+	return bannedUntil, true
+}
+
+// ban bans the peer until the given time. If there are too many bans, the expired ones are dropped,
+// and then the one ending first.
+// +checklocks:q.mu
+func (q *PeerQuotas) ban(peerId network.PeerID, now, bannedUntil time.Time) {
+	if _, ok := q.bans[peerId]; !ok && len(q.bans) >= maxBannedPeers {
+		q.pruneBans(now)
+		if len(q.bans) >= maxBannedPeers {
+			var first network.PeerID
+			for id, until := range q.bans {
+				if first == "" || until.Before(q.bans[first]) {
+					first = id
+				}
+			}
+			delete(q.bans, first)
+		}
+	}
+	q.bans[peerId] = bannedUntil
+}
+
+// pruneBans drops the expired bans.
+// +checklocks:q.mu
+func (q *PeerQuotas) pruneBans(now time.Time) {
+	for peerId, bannedUntil := range q.bans {
+		if !bannedUntil.After(now) {
+			delete(q.bans, peerId)
+		}
+	}
+}
+
+func (q *PeerQuotas) account(peerId network.PeerID, requestSize, responseSize int, callTime time.Duration) {
+	q.mu.Lock()
+	now := time.Now()
+	usage, ok := q.peers.Get(peerId)
+	if !ok {
+		usage = &peerUsage{total: rawapitypes.PeerUsage{PeerId: peerId}}
+		q.peers.Add(peerId, usage)
+	}
+	if now.Sub(usage.windowStart) >= q.config.Window {
+		*usage = peerUsage{total: usage.total, windowStart: now}
+	}
+
+	usage.total.Requests++
+	usage.total.RequestBytes += uint64(requestSize)
+	usage.total.ResponseBytes += uint64(responseSize)
+	usage.total.CallTime += callTime
+	usage.windowRequests++
+	usage.windowBytes += uint64(requestSize + responseSize)
+	usage.windowCallTime += callTime
+
+	limits := usage.exceeded(q.config.Quota)
+	report := len(limits) != 0 && !usage.reported
+	var bannedUntil time.Time
+	if report {
+		usage.reported = true
+		usage.total.Bans++
+		if q.config.BanDuration > 0 {
+			bannedUntil = now.Add(q.config.BanDuration)
+			q.ban(peerId, now, bannedUntil)
+			// The requests are not accounted during the ban, the quota is renewed afterwards.
+			*usage = peerUsage{total: usage.total, windowStart: bannedUntil}
+		}
+	}
+	q.mu.Unlock()
+
+	if report {
+		q.report(peerId, limits, bannedUntil)
+	}
+}
+
+// report logs the peer exceeding the limits of its quota and lowers its reputation in the network.
+func (q *PeerQuotas) report(peerId network.PeerID, limits []string, bannedUntil time.Time) {
+	event := q.logger.Warn().
+		Stringer(logging.FieldPeerId, peerId).
+		Str("limits", strings.Join(limits, ","))
+	if q.config.BanDuration > 0 {
+		event = event.Str("bannedUntil", bannedUntil.Format(time.RFC3339))
+	}
+	event.Msg("Peer exceeded its raw API quota")
+
+	if q.networkManager == nil {
+		return
+	}
+	if tracker := network.TryGetPeerReputationTracker(q.networkManager); tracker != nil {
+		tracker.ReportPeer(peerId, cm.ReputationChangeRawApiQuotaExceeded)
+	}
+}
+
+// callCpuTime runs the call locked to the OS thread of the caller and returns the CPU time the thread
+// spent on it. The work the call hands over to other goroutines is not accounted.
+func callCpuTime(call func()) time.Duration {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	start, ok := threadCpuTime()
+	call()
+	end, endOk := threadCpuTime()
+	if !ok || !endOk || end < start {
+		return 0
+	}
+	return end - start
+}
@@ -0,0 +1,126 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	cm "github.com/NilFoundation/nil/nil/internal/network/connection_manager"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func newQuotaHandler(
+	t *testing.T, quotas *PeerQuotas, method string, handler network.RequestHandler,
+) func(peerId network.PeerID) error {
+	t.Helper()
+
+	protocol := makeProtocolId(1, "quotaapi", method)
+	intercepted := quotas.Interceptor()(t.Context(), protocol, handler)
+	return func(peerId network.PeerID) error {
+		_, err := intercepted(network.WithRequestPeer(t.Context(), peerId), []byte{1})
+		return err
+	}
+}
+
+func nopRequestHandler(context.Context, []byte) ([]byte, error) {
+	return nil, nil
+}
+
+func TestPeerQuotaBans(t *testing.T) {
+	t.Parallel()
+
+	config := PeerQuotaConfig{Quota: PeerQuota{Requests: 1}, BanDuration: time.Hour}
+	// ban exceeds the quota of the peer.
+	ban := func(t *testing.T, request func(network.PeerID) error, peerId network.PeerID) {
+		t.Helper()
+
+		for range 2 {
+			require.NoError(t, request(peerId))
+		}
+		require.ErrorIs(t, request(peerId), rawapitypes.ErrRateLimited)
+	}
+
+	t.Run("NotEvicted", func(t *testing.T) {
+		t.Parallel()
+
+		quotas := NewPeerQuotas(config, nil)
+		request := newQuotaHandler(t, quotas, "TestMethod", nopRequestHandler)
+		ban(t, request, "peer-a")
+
+		// The ban outlives the usage of the peer pushed out of the accounted peers by the others.
+		for i := range maxAccountedPeers {
+			require.NoError(t, request(network.PeerID(fmt.Sprintf("peer-%d", i))))
+		}
+		require.ErrorIs(t, request("peer-a"), rawapitypes.ErrRateLimited)
+
+		usage := quotas.Usage()
+		require.Len(t, usage, maxAccountedPeers+1)
+		banned := usage[len(usage)-1]
+		require.Equal(t, network.PeerID("peer-a"), banned.PeerId)
+		require.Zero(t, banned.Requests)
+		require.True(t, banned.BannedUntil.After(time.Now()))
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		t.Parallel()
+
+		quotas := NewPeerQuotas(config, nil)
+		request := newQuotaHandler(t, quotas, "TestMethod", nopRequestHandler)
+		for i := range maxBannedPeers + 1 {
+			ban(t, request, network.PeerID(fmt.Sprintf("peer-%d", i)))
+		}
+
+		var banned int
+		for _, usage := range quotas.Usage() {
+			if !usage.BannedUntil.IsZero() {
+				banned++
+			}
+		}
+		require.Equal(t, maxBannedPeers, banned)
+	})
+}
+
+func TestPeerQuotaCallTime(t *testing.T) {
+	t.Parallel()
+
+	quotas := NewPeerQuotas(PeerQuotaConfig{}, nil)
+	// The time the call waits for is not accounted as its call time.
+	request := newQuotaHandler(t, quotas, "Call", func(context.Context, []byte) ([]byte, error) {
+		time.Sleep(100 * time.Millisecond)
+		return nil, nil
+	})
+	require.NoError(t, request("peer-a"))
+
+	usage := quotas.Usage()
+	require.Len(t, usage, 1)
+	require.Less(t, usage[0].CallTime, 50*time.Millisecond)
+}
+
+func TestPeerQuotasReported(t *testing.T) {
+	t.Parallel()
+
+	// The server disconnects from the peers it reports.
+	config := network.NewDefaultConfig()
+	config.ConnectionManagerConfig.ReputationBanThreshold = //
+		config.ConnectionManagerConfig.ReputationChangeSettings[cm.ReputationChangeRawApiQuotaExceeded] / 2
+	initialTcpPort.CompareAndSwap(0, 9010)
+	config.TcpPort = int(initialTcpPort.Add(2))
+	server := network.NewTestManagerWithBaseConfig(t.Context(), t, config)
+	defer server.Close()
+	client := network.NewTestManagers(t.Context(), t, int(initialTcpPort.Add(2)), 1)[0]
+	defer client.Close()
+
+	clientId, _ := network.ConnectManagers(t, client, server)
+	require.Contains(t, server.ConnectedPeers(), clientId)
+
+	quotas := NewPeerQuotas(PeerQuotaConfig{Quota: PeerQuota{Requests: 1}}, server)
+	request := newQuotaHandler(t, quotas, "TestMethod", nopRequestHandler)
+	require.NoError(t, request(clientId))
+	require.Contains(t, server.ConnectedPeers(), clientId)
+
+	require.NoError(t, request(clientId))
+	require.NotContains(t, server.ConnectedPeers(), clientId)
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitedPeers is the number of peers whose request rates are tracked by a method.
+// The least recently seen peers are forgotten and start with a full burst again.
+const maxRateLimitedPeers = 1024
+
+// RateLimit contains the limits of a raw API method. Zero values disable the corresponding limit.
+type RateLimit struct {
+	// MaxConcurrent is the number of requests handled at the same time, the excess requests are rejected.
+	MaxConcurrent int `yaml:"maxConcurrent,omitempty"`
+	// PeerRequestsPerSecond is the sustained number of requests accepted from a single peer.
+	PeerRequestsPerSecond float64 `yaml:"peerRequestsPerSecond,omitempty"`
+	// PeerBurst is the number of requests a peer can send at once. It is at least 1 if the rate is limited.
+	PeerBurst int `yaml:"peerBurst,omitempty"`
+}
+
+// RateLimits maps a protocol ID (e.g., "/shard/1/rawapi_ro/Call") or a method name (e.g., "Call")
+// to the limits of the method. The limits of the protocol ID take precedence.
+type RateLimits map[string]RateLimit
+
+// NewRateLimitInterceptor creates an interceptor rejecting the requests above the limits
+// with rawapitypes.ErrRateLimited. The requests are never queued.
+// Each protocol has its own limits, i.e., the method name limits apply to every shard separately.
+// The methods fanning out to the shards charge the rate of the peer for every shard, see chargeRequests.
+func NewRateLimitInterceptor(limits RateLimits) RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		limit, ok := findProtocolEntry(limits, protocol)
+		if !ok {
+			return next
+		}
+		limiter := newRequestLimiter(limit)
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			if !limiter.allowPeer(ctx) {
+				return nil, rawapitypes.ErrRateLimited
+			}
+			if !limiter.acquire() {
+				return nil, rawapitypes.ErrRateLimited
+			}
+			defer limiter.release()
+			return next(context.WithValue(ctx, requestLimiterKey{}, limiter), request)
+		}
+	}
+}
+
+type requestLimiterKey struct{}
+
+// chargeRequests charges the rate of the peer with n more requests of the method being handled,
+// e.g., for the shards the request is fanned out to. It fails with rawapitypes.ErrRateLimited
+// if the rate is exceeded.
+func chargeRequests(ctx context.Context, n int) error {
+	limiter, ok := ctx.Value(requestLimiterKey{}).(*requestLimiter)
+	if !ok || n <= 0 || limiter.allowPeerN(ctx, n) {
+		return nil
+	}
+	return rawapitypes.ErrRateLimited
+}
+
+type requestLimiter struct {
+	limit RateLimit
+
+	// slots is nil if the number of concurrent requests is not limited.
+	slots chan struct{}
+
+	// mu protects peers, since the limiter of a peer is created on its first request.
+	mu    sync.Mutex
+	peers *lru.Cache[network.PeerID, *rate.Limiter]
+}
+
+func newRequestLimiter(limit RateLimit) *requestLimiter {
+	l := &requestLimiter{limit: limit}
+	if limit.MaxConcurrent > 0 {
+		l.slots = make(chan struct{}, limit.MaxConcurrent)
+	}
+	if limit.PeerRequestsPerSecond > 0 {
+		l.limit.PeerBurst = max(limit.PeerBurst, 1)
+		var err error
+		l.peers, err = lru.New[network.PeerID, *rate.Limiter](maxRateLimitedPeers)
+		check.PanicIfErr(err)
+	}
+	return l
+}
+
+func (l *requestLimiter) acquire() bool {
+	if l.slots == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *requestLimiter) release() {
+	if l.slots != nil {
+		<-l.slots
+	}
+}
+
+// allowPeer reports whether the peer that sent the request hasn't exceeded its rate.
+// The requests of unknown peers (e.g., of the local calls) are not limited.
+func (l *requestLimiter) allowPeer(ctx context.Context) bool {
+	return l.allowPeerN(ctx, 1)
+}
+
+func (l *requestLimiter) allowPeerN(ctx context.Context, n int) bool {
+	if l.peers == nil {
+		return true
+	}
+	peerId, ok := network.RequestPeer(ctx)
+	if !ok {
+		return true
+	}
+
+	l.mu.Lock()
+	limiter, ok := l.peers.Get(peerId)
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(l.limit.PeerRequestsPerSecond), l.limit.PeerBurst)
+		l.peers.Add(peerId, limiter)
+	}
+	l.mu.Unlock()
+
+	return limiter.AllowN(time.Now(), n)
+}
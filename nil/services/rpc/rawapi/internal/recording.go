@@ -0,0 +1,341 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// RecordedRequest is a raw API request handled by the node together with its response.
+// The request and the response are the Protobuf messages of the method, without the envelope.
+type RecordedRequest struct {
+	Protocol network.ProtocolID `json:"protocol"`
+	Time     time.Time          `json:"time"`
+	Latency  time.Duration      `json:"latency"`
+	Request  []byte             `json:"request"`
+	Response []byte             `json:"response,omitempty"`
+	// Error is the error returned by the handler, e.g., the rejection of an interceptor.
+	Error string `json:"error,omitempty"`
+}
+
+// RequestRecorder stores the recorded requests, it is called concurrently by the handlers.
+type RequestRecorder interface {
+	Record(request RecordedRequest)
+}
+
+// unrecordedApis are the APIs whose requests are not recorded, since they carry the auth tokens
+// and the content of the database.
+var unrecordedApis = map[string]struct{}{
+	apiNameAdmin: {},
+	apiNameDb:    {},
+}
+
+// NewRecordingInterceptor creates an interceptor passing the handled requests to the recorder,
+// so that they can be replayed later with ReplayRecordedRequests. Only the requests reaching it are recorded,
+// so the interceptors preceding it may reject the requests without them being recorded.
+// The requests of unrecordedApis are not recorded.
+func NewRecordingInterceptor(recorder RequestRecorder) RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		if _, ok := unrecordedApis[protocolApiName(protocol)]; ok {
+			return next
+		}
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			start := time.Now()
+			response, err := next(ctx, request)
+			recorded := RecordedRequest{
+				Protocol: protocol,
+				Time:     start.UTC(),
+				Latency:  time.Since(start),
+				Request:  bytes.Clone(request),
+				Response: bytes.Clone(response),
+			}
+			if err != nil {
+				recorded.Error = err.Error()
+			}
+			recorder.Record(recorded)
+			return response, err
+		}
+	}
+}
+
+// protocolApiName returns the name of the API of the protocol ID made by makeProtocolId
+// or makeVersionedProtocolId.
+func protocolApiName(protocol network.ProtocolID) string {
+	segments := strings.Split(string(protocol), "/")
+	if len(segments) < 4 {
+		return ""
+	}
+	return segments[3]
+}
+
+// RequestRing keeps the latest recorded requests in memory.
+type RequestRing struct {
+	mu       sync.Mutex
+	requests []RecordedRequest
+	next     int
+	full     bool
+}
+
+var _ RequestRecorder = (*RequestRing)(nil)
+
+func NewRequestRing(size int) *RequestRing {
+	return &RequestRing{requests: make([]RecordedRequest, max(size, 1))}
+}
+
+func (r *RequestRing) Record(request RecordedRequest) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests[r.next] = request
+	r.next = (r.next + 1) % len(r.requests)
+	r.full = r.full || r.next == 0
+}
+
+// Requests returns the kept requests from the oldest to the latest one.
+func (r *RequestRing) Requests() []RecordedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]RecordedRequest(nil), r.requests[:r.next]...)
+	}
+	return append(append([]RecordedRequest(nil), r.requests[r.next:]...), r.requests[:r.next]...)
+}
+
+const (
+	// DefaultRequestRecordMaxSize is the size of the record file the requests stop being recorded at.
+	DefaultRequestRecordMaxSize = 1 << 30
+
+	requestRecorderQueueSize = 1024
+)
+
+// RequestFileRecorder appends the recorded requests to a file as JSON lines, which are read
+// with ReadRecordedRequests. The requests are queued and written by Run, so that the handlers don't wait
+// for the disk; they are dropped if the queue is full. The recording stops once the file reaches its maximal size.
+type RequestFileRecorder struct {
+	file     *os.File
+	size     int64
+	maxSize  int64
+	requests chan RecordedRequest
+	dropped  atomic.Uint64
+	logger   logging.Logger
+}
+
+var _ RequestRecorder = (*RequestFileRecorder)(nil)
+
+// NewRequestFileRecorder opens the file to append the requests to. The file is not written beyond maxSize,
+// DefaultRequestRecordMaxSize is used if it is not positive.
+func NewRequestFileRecorder(path string, maxSize int64) (*RequestFileRecorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open request record file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to stat request record file: %w", err), file.Close())
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultRequestRecordMaxSize
+	}
+	return &RequestFileRecorder{
+		file:     file,
+		size:     info.Size(),
+		maxSize:  maxSize,
+		requests: make(chan RecordedRequest, requestRecorderQueueSize),
+		logger:   logging.NewLogger("rawapi_recorder"),
+	}, nil
+}
+
+func (r *RequestFileRecorder) Record(request RecordedRequest) {
+	select {
+	case r.requests <- request:
+	default:
+		r.dropped.Add(1)
+	}
+}
+
+// Run writes the queued requests until the context is done, then writes the queued ones left and closes the file.
+func (r *RequestFileRecorder) Run(ctx context.Context) error {
+	for {
+		select {
+		case request := <-r.requests:
+			r.write(request)
+		case <-ctx.Done():
+			for {
+				select {
+				case request := <-r.requests:
+					r.write(request)
+				default:
+					if dropped := r.dropped.Load(); dropped != 0 {
+						r.logger.Warn().Uint64("dropped", dropped).Msg("Requests were not recorded, the queue was full")
+					}
+					return r.file.Close()
+				}
+			}
+		}
+	}
+}
+
+func (r *RequestFileRecorder) write(request RecordedRequest) {
+	if r.size >= r.maxSize {
+		return
+	}
+	line, err := json.Marshal(request)
+	if err != nil {
+		r.logger.Warn().Err(err).Str(logging.FieldProtocolID, string(request.Protocol)).Msg("Failed to record request")
+		return
+	}
+	line = append(line, '\n')
+	if r.size+int64(len(line)) > r.maxSize {
+		r.size = r.maxSize
+		r.logger.Warn().Int64("maxSize", r.maxSize).Msg("Request record file is full, recording stopped")
+		return
+	}
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	if err != nil {
+		r.logger.Warn().Err(err).Str(logging.FieldProtocolID, string(request.Protocol)).Msg("Failed to record request")
+	}
+}
+
+// ReadRecordedRequests reads the requests written by RequestFileRecorder.
+func ReadRecordedRequests(reader io.Reader) ([]RecordedRequest, error) {
+	decoder := json.NewDecoder(reader)
+	requests := make([]RecordedRequest, 0)
+	for {
+		var request RecordedRequest
+		err := decoder.Decode(&request)
+		if errors.Is(err, io.EOF) {
+			return requests, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recorded request %d: %w", len(requests), err)
+		}
+		requests = append(requests, request)
+	}
+}
+
+// ReplayMismatch is a replayed request whose response differs from the recorded one.
+type ReplayMismatch struct {
+	Recorded RecordedRequest
+	Response []byte
+	Error    string
+}
+
+// ReplayReport is the outcome of ReplayRecordedRequests.
+type ReplayReport struct {
+	Replayed int
+	// Skipped are the requests of the other shards, APIs and methods, e.g., the batches and the subscriptions.
+	Skipped    int
+	Mismatches []ReplayMismatch
+}
+
+// ReplayRecordedRequests handles the recorded requests of the read-only API of the shard with the API
+// and compares the responses with the recorded ones, e.g., to check that a change of the codec or the schema
+// doesn't change them. The responses are compared as Protobuf messages, not byte by byte.
+func ReplayRecordedRequests(
+	ctx context.Context,
+	shardId types.ShardId,
+	api ShardApiRo,
+	requests []RecordedRequest,
+) (*ReplayReport, error) {
+	return replayRecordedRequests(
+		ctx, reflect.TypeFor[NetworkTransportProtocolRo](), reflect.TypeFor[ShardApiRo](), api,
+		shardId, apiNameRo, requests)
+}
+
+func replayRecordedRequests(
+	ctx context.Context,
+	protocolInterfaceType reflect.Type,
+	apiType reflect.Type,
+	api any,
+	shardId types.ShardId,
+	apiName string,
+	requests []RecordedRequest,
+) (*ReplayReport, error) {
+	codec, err := newApiCodec(apiType, protocolInterfaceType)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logging.NewLogger("rawapi_replay")
+	apiValue := reflect.ValueOf(api)
+	dispatchers := newDispatchers(apiType, api)
+	handlers := make(map[network.ProtocolID]network.RequestHandler)
+	codecs := make(map[network.ProtocolID]*methodCodec)
+	for method := range common.Filter(iterMethods(apiType), isExportedMethod) {
+		methodCodec := codec[method.Name]
+		if methodCodec.kind != singleResponse {
+			continue
+		}
+		protocol := makeProtocolId(shardId, apiName, method.Name)
+		if dispatch, ok := dispatchers[method.Name]; ok {
+			handlers[protocol] = makeDispatchedRequestHandler(dispatch, methodCodec, logger)
+		} else {
+			handlers[protocol] = makeRequestHandler(apiValue.MethodByName(method.Name), methodCodec, logger)
+		}
+		codecs[protocol] = methodCodec
+	}
+
+	report := &ReplayReport{Mismatches: make([]ReplayMismatch, 0)}
+	for _, recorded := range requests {
+		handler, ok := handlers[recorded.Protocol]
+		if !ok {
+			report.Skipped++
+			continue
+		}
+		report.Replayed++
+
+		response, err := handler(ctx, recorded.Request)
+		var errText string
+		if err != nil {
+			errText = err.Error()
+		}
+		equal, err := responsesEqual(codecs[recorded.Protocol], recorded.Response, response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare responses of %s: %w", recorded.Protocol, err)
+		}
+		if !equal || errText != recorded.Error {
+			report.Mismatches = append(report.Mismatches, ReplayMismatch{
+				Recorded: recorded,
+				Response: response,
+				Error:    errText,
+			})
+		}
+	}
+	return report, nil
+}
+
+// responsesEqual compares the responses as messages, since the encoding of the maps is not deterministic.
+func responsesEqual(codec *methodCodec, a, b []byte) (bool, error) {
+	unmarshal := func(data []byte) (proto.Message, error) {
+		message, ok := reflect.New(codec.pbResponseType).Interface().(proto.Message)
+		check.PanicIfNotf(ok, "failed to create proto message %s", codec.pbResponseType)
+		return message, proto.Unmarshal(data, message)
+	}
+	messageA, err := unmarshal(a)
+	if err != nil {
+		return false, err
+	}
+	messageB, err := unmarshal(b)
+	if err != nil {
+		return false, err
+	}
+	return proto.Equal(messageA, messageB), nil
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordingInterceptor(t *testing.T) {
+	t.Parallel()
+
+	ring := NewRequestRing(4)
+	interceptor := NewRecordingInterceptor(ring)
+	for _, protocol := range []network.ProtocolID{
+		makeProtocolId(1, apiNameRo, "GetBlock"),
+		makeVersionedProtocolId(1, apiNameAdmin, sszApiVersion, "GetPeers"),
+		makeProtocolId(1, apiNameDb, "Get"),
+	} {
+		_, err := interceptor(t.Context(), protocol, nopRequestHandler)(t.Context(), []byte{1})
+		require.NoError(t, err)
+	}
+
+	// The requests of the admin and the database APIs are not recorded.
+	recorded := ring.Requests()
+	require.Len(t, recorded, 1)
+	require.Equal(t, makeProtocolId(1, apiNameRo, "GetBlock"), recorded[0].Protocol)
+}
+
+func TestRequestFileRecorder(t *testing.T) {
+	t.Parallel()
+
+	request := RecordedRequest{Protocol: makeProtocolId(1, apiNameRo, "GetBlock"), Request: []byte{1, 2, 3}}
+	// record records the requests and returns the ones written to the file with the maximal size.
+	record := func(t *testing.T, maxSize int64, requests int) []RecordedRequest {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "requests.jsonl")
+		recorder, err := NewRequestFileRecorder(path, maxSize)
+		require.NoError(t, err)
+		for range requests {
+			recorder.Record(request)
+		}
+
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		require.NoError(t, recorder.Run(ctx))
+
+		file, err := os.Open(path)
+		require.NoError(t, err)
+		defer file.Close()
+		read, err := ReadRecordedRequests(file)
+		require.NoError(t, err)
+		return read
+	}
+
+	t.Run("Written", func(t *testing.T) {
+		t.Parallel()
+
+		read := record(t, 0, 3)
+		require.Len(t, read, 3)
+		require.Equal(t, request.Protocol, read[2].Protocol)
+		require.Equal(t, request.Request, read[2].Request)
+	})
+
+	t.Run("Bounded", func(t *testing.T) {
+		t.Parallel()
+
+		// The requests beyond the queue are dropped instead of blocking the handlers.
+		require.Len(t, record(t, 0, requestRecorderQueueSize+1), requestRecorderQueueSize)
+	})
+
+	t.Run("MaxSize", func(t *testing.T) {
+		t.Parallel()
+
+		read := record(t, 0, 1)
+		require.Len(t, read, 1)
+		line, err := json.Marshal(read[0])
+		require.NoError(t, err)
+
+		// The request that would exceed the size is not written, nor are the following ones.
+		require.Len(t, record(t, int64(2*len(line)+3), 4), 2)
+	})
+}
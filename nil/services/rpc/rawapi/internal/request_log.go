@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	defaultErrorLogSampleRate   = 1
+	defaultSuccessLogSampleRate = 0.01
+
+	// maxArgumentSummaryLength limits the length of each argument logged by the watchdog.
+	maxArgumentSummaryLength = 256
+)
+
+// RequestLogConfig configures the logging of the handled requests, which are sampled so that the logs
+// are not flooded under load. The default rate is used if a rate is zero, no requests are logged if it is negative.
+// The requests exceeding the watchdog thresholds are always logged with their arguments.
+type RequestLogConfig struct {
+	// ErrorSampleRate is the fraction of the failed requests logged, all of them by default.
+	ErrorSampleRate float64 `yaml:"errorSampleRate,omitempty"`
+	// SuccessSampleRate is the fraction of the successful requests logged, 1% of them by default.
+	SuccessSampleRate float64 `yaml:"successSampleRate,omitempty"`
+	// SlowRequestThreshold is the duration of the slow requests, they are not watched for if it is zero.
+	SlowRequestThreshold time.Duration `yaml:"slowRequestThreshold,omitempty"`
+	// LargeResponseThreshold is the size of the large responses, they are not watched for if it is zero.
+	LargeResponseThreshold int `yaml:"largeResponseThreshold,omitempty"`
+}
+
+func sampleRate(rate float64, defaultRate float64) float64 {
+	if rate == 0 {
+		return defaultRate
+	}
+	return rate
+}
+
+// requestLogger logs the sampled requests of a method and the requests caught by the watchdog.
+type requestLogger struct {
+	logger      logging.Logger
+	cfg         RequestLogConfig
+	errorRate   float64
+	successRate float64
+	// codec unpacks the arguments of the requests caught by the watchdog, it is nil for the batches.
+	codec *methodCodec
+}
+
+func newMethodRequestLogger(
+	cfg RequestLogConfig,
+	logger logging.Logger,
+	shardId types.ShardId,
+	methodName string,
+	codec *methodCodec,
+) *requestLogger {
+	return &requestLogger{
+		logger: logger.With().
+			Stringer(logging.FieldShardId, shardId).
+			Str(logging.FieldRpcMethod, methodName).
+			Logger(),
+		cfg:         cfg,
+		errorRate:   sampleRate(cfg.ErrorSampleRate, defaultErrorLogSampleRate),
+		successRate: sampleRate(cfg.SuccessSampleRate, defaultSuccessLogSampleRate),
+		codec:       codec,
+	}
+}
+
+func (l *requestLogger) isSlow(duration time.Duration) bool {
+	return l.cfg.SlowRequestThreshold > 0 && duration > l.cfg.SlowRequestThreshold
+}
+
+func (l *requestLogger) isLarge(responseSize int) bool {
+	return l.cfg.LargeResponseThreshold > 0 && responseSize > l.cfg.LargeResponseThreshold
+}
+
+func (l *requestLogger) sampled(err error) bool {
+	rate := l.successRate
+	if err != nil {
+		rate = l.errorRate
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// log logs the request if it is sampled or caught by the watchdog, the logger of the request
+// is only created in that case.
+func (l *requestLogger) log(ctx context.Context, request []byte, responseSize int, duration time.Duration, err error) {
+	slow, large := l.isSlow(duration), l.isLarge(responseSize)
+	if !slow && !large && !l.sampled(err) {
+		return
+	}
+
+	loggerCtx := l.logger.With()
+	if peerId, ok := network.RequestPeer(ctx); ok {
+		loggerCtx = loggerCtx.Stringer(logging.FieldPeerId, peerId)
+	}
+	logger := loggerCtx.Int("requestSize", len(request)).Logger()
+
+	if slow || large {
+		// The arguments are unpacked once more, which is only affordable for the rare pathological requests.
+		logger.Warn().
+			Err(err).
+			Bool("slow", slow).
+			Bool("large", large).
+			Int("responseSize", responseSize).
+			Dur(logging.FieldDuration, duration).
+			Str(logging.FieldRpcParams, l.summarizeArguments(request)).
+			Msg("Request exceeded the watchdog thresholds")
+		return
+	}
+	if err != nil {
+		logger.Warn().Err(err).Dur(logging.FieldDuration, duration).Msg("Request failed")
+		return
+	}
+	logger.Info().
+		Int("responseSize", responseSize).
+		Dur(logging.FieldDuration, duration).
+		Msg("Request handled")
+}
+
+// summarizeArguments returns the decoded arguments of the request truncated to a readable length.
+func (l *requestLogger) summarizeArguments(request []byte) string {
+	if l.codec == nil {
+		return ""
+	}
+	args, err := l.codec.unpackRequest(request)
+	if err != nil {
+		return fmt.Sprintf("<invalid request: %s>", err)
+	}
+	summaries := make([]string, 0, len(args))
+	for _, arg := range args {
+		summaries = append(summaries, truncateArgumentSummary(fmt.Sprintf("%+v", arg.Interface())))
+	}
+	return strings.Join(summaries, ", ")
+}
+
+// truncateArgumentSummary cuts the summary to maxArgumentSummaryLength bytes at most,
+// the cut doesn't split the UTF-8 encoding of a character.
+func truncateArgumentSummary(summary string) string {
+	if len(summary) <= maxArgumentSummaryLength {
+		return summary
+	}
+	cut := maxArgumentSummaryLength
+	for cut > 0 && !utf8.RuneStart(summary[cut]) {
+		cut--
+	}
+	return summary[:cut] + "..."
+}
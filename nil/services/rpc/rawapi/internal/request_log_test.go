@@ -0,0 +1,23 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateArgumentSummary(t *testing.T) {
+	t.Parallel()
+
+	short := strings.Repeat("a", maxArgumentSummaryLength)
+	require.Equal(t, short, truncateArgumentSummary(short))
+
+	require.Equal(t, short+"...", truncateArgumentSummary(short+"a"))
+
+	// The character crossing the limit is dropped as a whole.
+	summary := truncateArgumentSummary(strings.Repeat("a", maxArgumentSummaryLength-1) + "€")
+	require.True(t, utf8.ValidString(summary))
+	require.Equal(t, strings.Repeat("a", maxArgumentSummaryLength-1)+"...", summary)
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"path"
 	"reflect"
+	"runtime/debug"
+	"slices"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/check"
@@ -12,6 +16,8 @@ import (
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/google/uuid"
 )
 
 var errRequestHandlerCreation = errors.New("failed to create request handler")
@@ -22,60 +28,430 @@ type NetworkTransportProtocolRo interface {
 	GetBlockHeader(request pb.BlockRequest) pb.RawBlockResponse
 	GetFullBlockData(request pb.BlockRequest) pb.RawFullBlockResponse
 	GetBlockTransactionCount(request pb.BlockRequest) pb.Uint64Response
+	GetBlockRange(request pb.BlockRangeRequest) pb.RawBlockRangeResponse
+	GetShardStats(request pb.ShardStatsRequest) pb.ShardStatsResponse
+	GetBlockFinalitySignatures(request pb.BlockRequest) pb.FinalitySignaturesResponse
+	GetValidators(request pb.BlockRequest) pb.ValidatorSetResponse
+	GetValidatorInfo(request pb.ValidatorRequest) pb.ValidatorInfoResponse
+	GetEpochInfo() pb.EpochInfoResponse
+	SubscribeNewHeads() pb.RawBlockResponse
 
 	GetInTransaction(pb.TransactionRequest) pb.TransactionResponse
-	GetInTransactionReceipt(pb.Hash) pb.ReceiptResponse
+	GetInTransactionByIndex(pb.BlockTransactionIndexRequest) pb.TransactionResponse
+	GetReceipt(pb.Hash) pb.ReceiptResponse
+	GetBounceInfo(pb.Hash) pb.BounceInfoResponse
+	GetBlockReceipts(pb.BlockRequest) pb.ReceiptsResponse
+	GetOutTransaction(pb.TransactionRequest) pb.TransactionResponse
+	GetOutTransactions(pb.BlockRequest) pb.TransactionsResponse
+
+	GetLogs(request pb.LogFilterRequest) pb.LogsResponse
+	SubscribeLogs(request pb.LogFilterRequest) pb.LogResponse
 
 	GetBalance(request pb.AccountRequest) pb.BalanceResponse
+	GetBalances(request pb.MultiAccountRequest) pb.BalancesResponse
 	GetCode(request pb.AccountRequest) pb.CodeResponse
-	GetTokens(request pb.AccountRequest) pb.TokensResponse
+	GetStorageAt(request pb.StorageRequest) pb.Uint256Response
+	GetStorageRange(request pb.StorageRangeRequest) pb.StorageRangeResponse
+	GetTokens(request pb.TokensRequest) pb.TokensResponse
+	GetTokenBalance(request pb.TokenBalanceRequest) pb.BalanceResponse
+	GetTokenInfo(request pb.TokenRequest) pb.TokenInfoResponse
+	ListTokensCreatedBy(request pb.AccountRequest) pb.TokenInfosResponse
 	GetContract(request pb.AccountRequest) pb.RawContractResponse
+	GetAccountMeta(request pb.AccountRequest) pb.AccountMetaResponse
+	GetProof(request pb.ProofRequest) pb.ProofResponse
 
 	Call(pb.CallRequest) pb.CallResponse
+	EstimateFee(pb.CallRequest) pb.FeeEstimationResponse
+	CreateAccessList(pb.CallRequest) pb.AccessListResponse
+	SimulateBundle(pb.BundleRequest) pb.BundleResponse
+	PrepareDeploy(pb.DeployRequest) pb.DeployPreparationResponse
 
 	GasPrice() pb.GasPriceResponse
 	GetShardIdList() pb.ShardIdListResponse
 	GetNumShards() pb.Uint64Response
+	ResolveShard(pb.ResolveShardRequest) pb.ShardIdResponse
+	ResolveShards(pb.ResolveShardsRequest) pb.ShardResolutionsResponse
+	GetChainConfig() pb.ChainConfigResponse
+	GetSyncStatus() pb.SyncStatusResponse
 
 	ClientVersion() pb.StringResponse
 }
 
 type NetworkTransportProtocolRw interface {
 	SendTransaction(pb.SendTransactionRequest) pb.SendTransactionResponse
+	SendTransactionBundle(pb.SendBundleRequest) pb.SendBundleResponse
+	SendTransactionAndWatch(pb.SendTransactionRequest) pb.TransactionEventResponse
 	GetTransactionCount(pb.AccountRequest) pb.Uint64Response
+	GetNextValidSeqno(pb.AccountRequest) pb.Uint64Response
+	GetTransactionStatus(pb.Hash) pb.TransactionStatusResponse
+	ResendTransaction(pb.ResendTransactionRequest) pb.SendTransactionResponse
 
 	GetTxpoolStatus() pb.Uint64Response
 	GetTxpoolContent() pb.RawTxnsResponse
+	SubscribePendingTransactions(pb.PendingTransactionsRequest) pb.PendingTransactionResponse
 }
 
 type NetworkTransportProtocolDev interface {
 	DoPanicOnShard() pb.Uint64Response
 }
 
+type NetworkTransportProtocolDebug interface {
+	TraceTransaction(pb.Hash) pb.ExecutionTraceResponse
+	TraceCall(pb.CallRequest) pb.ExecutionTraceResponse
+	TraceTransactionStateDiff(pb.Hash) pb.StateDiffResponse
+	TraceCallStateDiff(pb.CallRequest) pb.StateDiffResponse
+	ReplayTransaction(pb.ReplayTransactionRequest) pb.ReplayResponse
+	GetPendingResponsesFor(pb.PendingResponsesRequest) pb.PendingResponsesResponse
+	GetAwaitingContext(pb.Hash) pb.AwaitingContextResponse
+}
+
+type NetworkTransportProtocolTxpool interface {
+	GetPoolContent() pb.PoolContentResponse
+	GetPoolStatus() pb.PoolStatusResponse
+	GetPoolTransactionsByAccount(pb.PoolAccountRequest) pb.PoolContentResponse
+}
+
+type NetworkTransportProtocolSync interface {
+	GetSnapshotManifest(pb.BlockRequest) pb.SnapshotManifestResponse
+	GetSnapshotChunk(pb.SnapshotChunkRequest) pb.SnapshotChunkResponse
+	GetBlocksSince(pb.BlocksSinceRequest) pb.RawFullBlockResponse
+}
+
+type NetworkTransportProtocolProof interface {
+	GetStateProofForBlock(pb.BlockRequest) pb.StateProofResponse
+	GetProofTaskStatus(pb.ProofTaskRequest) pb.ProofTaskResponse
+}
+
+type NetworkTransportProtocolAdmin interface {
+	SetLogLevel(pb.SetLogLevelRequest) pb.StringResponse
+	DisconnectPeer(pb.DisconnectPeerRequest) pb.Uint64Response
+	AddPeer(pb.AddPeerRequest) pb.Uint64Response
+	RemovePeer(pb.PeerRequest) pb.Uint64Response
+	ProtectPeer(pb.ProtectPeerRequest) pb.BoolResponse
+	SetPeerTag(pb.SetPeerTagRequest) pb.Int64Response
+	FlushCaches() pb.Uint64Response
+	TriggerSnapshot() pb.StringResponse
+	SetPeerAcl(pb.PeerAcl) pb.Uint64Response
+	GetPeerAcl() pb.PeerAclResponse
+	GetPeerUsage() pb.PeerUsageResponse
+	GetPeers() pb.PeerInfosResponse
+	GetPeerInfo(pb.PeerRequest) pb.PeerInfoResponse
+	GetBandwidthByProtocol() pb.ProtocolBandwidthResponse
+}
+
+type NetworkTransportProtocolDb interface {
+	GetRaw(pb.DbGetRawRequest) pb.DbValueResponse
+	ListKeys(pb.DbListKeysRequest) pb.DbKeysResponse
+}
+
+type NetworkTransportProtocolConsensus interface {
+	GetConsensusState(pb.ConsensusShardRequest) pb.ConsensusStateResponse
+	GetRoundTimeouts(pb.ConsensusShardRequest) pb.RoundTimeoutsResponse
+}
+
+type NetworkTransportProtocolCluster interface {
+	GetFamilyTransactionCount(pb.AccountRequest) pb.FamilyTransactionCountResponse
+	GetLatestBlocks() pb.ShardBlockHeadsResponse
+	GetGasPriceStats() pb.GasPriceStatsResponse
+}
+
+type NetworkTransportProtocolFaucet interface {
+	RequestFunds(pb.FaucetRequest) pb.SendTransactionResponse
+	GetFaucetLimits() pb.FaucetLimitsResponse
+}
+
+type NetworkTransportProtocolDevnet interface {
+	MineBlock(pb.MineBlockRequest) pb.Uint64Response
+	SetNextBlockTimestamp(pb.SetNextBlockTimestampRequest) pb.Uint64Response
+	SnapshotState() pb.Uint64Response
+	RevertToSnapshot(pb.RevertToSnapshotRequest) pb.RevertToSnapshotResponse
+}
+
+// RequestInterceptor wraps the handler of a raw API method, e.g., to log, authorize or limit the requests.
+// It is called once for every method when the handlers are set, the returned handler serves the requests.
+// The protocol passed to the interceptor has no version segment, the returned handler serves all the versions.
+// The requests opening the streams and the subscriptions are intercepted as well, the responses to them are empty.
+type RequestInterceptor func(
+	ctx context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler
+
+// RequestHandlersConfig configures the handling of the raw API requests served to the other nodes.
+type RequestHandlersConfig struct {
+	// Interceptors wrap the handlers of the methods, the first one receives the request first.
+	Interceptors []RequestInterceptor
+	// CompressionThreshold is the minimal size of the responses compressed for the clients accepting compression.
+	// The default threshold is used if it is zero, the compression is disabled if it is negative.
+	CompressionThreshold int
+	// ChunkSize is the maximal size of a response sent at once to the clients accepting chunked responses.
+	// The default size is used if it is zero, the chunking is disabled if it is negative.
+	ChunkSize int
+	// ResponseCache configures the cache of the responses of the immutable data, e.g., the blocks by hash.
+	ResponseCache ResponseCacheConfig
+	// DisableDeduplication makes the identical concurrent requests of the read-only APIs handled separately.
+	DisableDeduplication bool
+	// Logging configures the sampling of the logged requests and the watchdog of the slow and large ones.
+	Logging RequestLogConfig
+	// RequestSizeLimits reject the requests larger than the limits of their methods before any interceptor,
+	// the limits are also listed by ListSupportedMethods.
+	RequestSizeLimits RequestSizeLimits
+	// EnableJsonCodec also serves the methods by the protocol IDs with the "/json" suffix, which accept
+	// and return the JSON form of the Protobuf messages for debugging.
+	EnableJsonCodec bool
+
+	// handlers keep the handlers set, so that they can be swapped and unset, it is set by NodeApi.SetP2pRequestHandlers.
+	handlers *handlerRegistry
+	// sszResponses replace the default sszResponses, e.g., to encode the results of the test APIs in SSZ.
+	sszResponses map[string]*sszResponseCodec
+	// chunks keep the chunked responses of all the APIs of the node, it is set by NodeApi.SetP2pRequestHandlers.
+	// The handlers set separately store the responses of their API only.
+	chunks *chunkStore
+}
+
+// findProtocolEntry returns the entry of the configuration of the interceptor for the protocol.
+// The entries are looked up by protocol ID first and then by method name.
+func findProtocolEntry[T any](entries map[string]T, protocol network.ProtocolID) (T, bool) {
+	if entry, ok := entries[string(protocol)]; ok {
+		return entry, true
+	}
+	entry, ok := entries[path.Base(string(protocol))]
+	return entry, ok
+}
+
+// chainInterceptors wraps the handler so that the first interceptor receives the request first.
+func chainInterceptors(
+	ctx context.Context,
+	protocol network.ProtocolID,
+	handler network.RequestHandler,
+	interceptors []RequestInterceptor,
+) network.RequestHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		handler = interceptors[i](ctx, protocol, handler)
+	}
+	return handler
+}
+
+func makeProtocolId(shardId types.ShardId, apiName string, methodName string) network.ProtocolID {
+	return network.ProtocolID(fmt.Sprintf("/shard/%d/%s/%s", shardId, apiName, methodName))
+}
+
 func getRawApiRequestHandlers(
+	ctx context.Context,
 	protocolInterfaceType reflect.Type,
 	apiType reflect.Type,
 	api any,
 	shardId types.ShardId,
 	apiName string,
-) (map[network.ProtocolID]network.RequestHandler, error) {
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) (map[network.ProtocolID]network.RequestHandler, map[network.ProtocolID]network.StreamHandler, error) {
 	check.PanicIfNotf(reflect.ValueOf(api).Type().Implements(apiType), "api does not implement %s", apiType)
+	if len(cfg.RequestSizeLimits) != 0 {
+		cfg.Interceptors = append(
+			[]RequestInterceptor{NewRequestSizeInterceptor(cfg.RequestSizeLimits)}, cfg.Interceptors...)
+	}
 	requestHandlers := make(map[network.ProtocolID]network.RequestHandler)
-	codec, err := newApiCodec(apiType, protocolInterfaceType)
+	streamHandlers := make(map[network.ProtocolID]network.StreamHandler)
+	batchHandlers := make(map[string]network.RequestHandler)
+	// sszStreamHandlers are served by sszApiVersion instead of the ones of streamHandlers.
+	sszStreamHandlers := make(map[network.ProtocolID]network.StreamHandler)
+	var sszMethods []string
+	// envelopedProtocols are the protocol IDs without a version of the handlers unwrapping the requests.
+	var envelopedProtocols []network.ProtocolID
+	// jsonHandlers are only served by the protocol IDs without a version.
+	jsonHandlers := make(map[network.ProtocolID]network.RequestHandler)
+	chunks := cfg.chunks
+	if chunks == nil {
+		chunks = newChunkStore()
+	}
+	signed := newSignedRequests()
+	cache := newResponseCache(cfg.ResponseCache, shardId, "server")
+	if source, ok := api.(committedBlocksSource); ok && cache != nil {
+		if blocks := source.subscribeCommittedBlocks(ctx); blocks != nil {
+			go cache.purgeOnResets(ctx, blocks)
+		}
+	}
+	deduplicator := newRequestDeduplicator(apiName, shardId, cfg.DisableDeduplication)
+	sszCodecs := cfg.sszResponses
+	if sszCodecs == nil {
+		sszCodecs = sszResponses
+	}
+	codec, err := newApiCodecWithSSZResponses(apiType, protocolInterfaceType, sszCodecs)
 	if err != nil {
-		return nil, fmt.Errorf("%w: %w", errRequestHandlerCreation, err)
+		return nil, nil, fmt.Errorf("%w: %w", errRequestHandlerCreation, err)
 	}
 
 	apiValue := reflect.ValueOf(api)
+	dispatchers := newDispatchers(apiType, api)
 	for method := range common.Filter(iterMethods(apiType), isExportedMethod) {
 		methodName := method.Name
 		methodCodec, ok := codec[methodName]
 		check.PanicIfNotf(ok, "Appropriate codec is not found for method %s", methodName)
 
-		protocol := network.ProtocolID(fmt.Sprintf("/shard/%d/%s/%s", shardId, apiName, methodName))
-		requestHandlers[protocol] = makeRequestHandler(apiValue.MethodByName(methodName), methodCodec)
+		protocol := makeProtocolId(shardId, apiName, methodName)
+		switch methodCodec.kind {
+		case streamingResponse:
+			streamLogger := logger.With().Str(logging.FieldProtocolID, string(protocol)).Logger()
+			open := instrumentRequestHandler(
+				makeStreamOpenHandler(ctx, protocol, cfg.Interceptors),
+				shardId,
+				methodName,
+				streamRequest,
+				newMethodRequestLogger(cfg.Logging, streamLogger, shardId, methodName, methodCodec))
+			streamHandlers[protocol] = makeStreamHandler(
+				ctx, apiValue.MethodByName(methodName), methodCodec, open, streamLogger)
+			continue
+		case subscriptionResponse:
+			streamLogger := logger.With().Str(logging.FieldProtocolID, string(protocol)).Logger()
+			// The handlers of both the encodings share the interceptors, e.g., the limits of the method.
+			// A subscription is measured until it's accepted.
+			open := instrumentRequestHandler(
+				makeStreamOpenHandler(ctx, protocol, cfg.Interceptors),
+				shardId,
+				methodName,
+				streamRequest,
+				newMethodRequestLogger(cfg.Logging, streamLogger, shardId, methodName, methodCodec))
+			streamHandlers[protocol] = makeSubscriptionHandler(
+				ctx, apiValue.MethodByName(methodName), methodCodec, open, streamLogger)
+			if sszCodec := methodCodec.sszEncoding(); sszCodec != nil {
+				sszStreamHandlers[protocol] = makeSubscriptionHandler(
+					ctx, apiValue.MethodByName(methodName), sszCodec, open, streamLogger)
+			}
+			continue
+		case singleResponse:
+		}
+		methodLogger := logger.With().Str(logging.FieldProtocolID, string(protocol)).Logger()
+		var handler network.RequestHandler
+		if dispatch, ok := dispatchers[methodName]; ok {
+			handler = makeDispatchedRequestHandler(dispatch, methodCodec, methodLogger)
+		} else {
+			handler = makeRequestHandler(apiValue.MethodByName(methodName), methodCodec, methodLogger)
+		}
+		// The SSZ responses are packed by reflection, the dispatchers only pack the Protobuf ones.
+		if sszCodec := methodCodec.sszEncoding(); sszCodec != nil {
+			handler = selectSSZResponses(
+				makeRequestHandler(apiValue.MethodByName(methodName), sszCodec, methodLogger), handler)
+			sszMethods = append(sszMethods, methodName)
+		}
+		// The cached responses are still intercepted, e.g., to be authorized. The requests missing the cache
+		// are deduplicated.
+		handler = deduplicator.wrapRequestHandler(methodCodec, handler)
+		handler = chainInterceptors(ctx, protocol, cache.wrapRequestHandler(methodCodec, handler), cfg.Interceptors)
+		// The rejections of the interceptors are counted and logged as well.
+		requestLogger := newMethodRequestLogger(cfg.Logging, methodLogger, shardId, methodName, methodCodec)
+		handler = instrumentRequestHandler(handler, shardId, methodName, singleRequest, requestLogger)
+		requestHandlers[protocol] = makeEnvelopeRequestHandler(
+			handler, protocol, methodCodec.packError, cfg, chunks, signed)
+		envelopedProtocols = append(envelopedProtocols, protocol)
+		if cfg.EnableJsonCodec {
+			jsonHandlers[makeJsonProtocolId(protocol)] = makeJsonRequestHandler(requestHandlers[protocol], methodCodec)
+		}
+		// The calls of a batch share the envelope of the batch.
+		batchHandlers[methodName] = packHandlerErrors(handler, methodCodec.packError)
+		for _, deprecatedName := range deprecatedMethodNames[methodName] {
+			batchHandlers[deprecatedName] = withDeprecationNotice(
+				batchHandlers[methodName], newDeprecationNotice(shardId, deprecatedName, methodName, methodLogger))
+		}
+	}
+	// The calls of a batch are intercepted both as a part of the batch and individually.
+	batchProtocol := makeProtocolId(shardId, apiName, batchMethodName)
+	requestHandlers[batchProtocol] = makeEnvelopeRequestHandler(
+		instrumentRequestHandler(
+			chainInterceptors(ctx, batchProtocol, makeBatchRequestHandler(batchHandlers), cfg.Interceptors),
+			shardId,
+			batchMethodName,
+			batchRequest,
+			newMethodRequestLogger(
+				cfg.Logging,
+				logger.With().Str(logging.FieldProtocolID, string(batchProtocol)).Logger(),
+				shardId,
+				batchMethodName,
+				nil)),
+		batchProtocol,
+		func(err error) []byte {
+			response, packErr := packBatchError(err)
+			check.PanicIfErr(packErr)
+			return response
+		},
+		cfg,
+		chunks,
+		signed)
+	envelopedProtocols = append(envelopedProtocols, batchProtocol)
+
+	fetchChunkProtocol := makeProtocolId(shardId, apiName, fetchChunkMethodName)
+	requestHandlers[fetchChunkProtocol] = packHandlerErrors(
+		chainInterceptors(
+			ctx, fetchChunkProtocol, makeFetchChunkRequestHandler(chunks, chunkSize(cfg.ChunkSize)), cfg.Interceptors),
+		packFetchChunkError)
+
+	requestHandlers = registerApiVersions(requestHandlers, shardId, apiName)
+	streamHandlers = registerApiVersions(streamHandlers, shardId, apiName)
+	// The legacy nodes send the requests unwrapped by the IDs without a version.
+	for _, protocol := range envelopedProtocols {
+		if handler, ok := requestHandlers[protocol]; ok {
+			requestHandlers[protocol] = acceptUnenvelopedRequests(handler)
+		}
+	}
+	for _, methodName := range sszMethods {
+		protocol := makeVersionedProtocolId(shardId, apiName, sszApiVersion, methodName)
+		requestHandlers[protocol] = requestSSZResponses(requestHandlers[protocol])
+	}
+	for protocol, handler := range sszStreamHandlers {
+		streamHandlers[makeVersionedProtocolId(shardId, apiName, sszApiVersion, path.Base(string(protocol)))] = handler
+	}
+	registerDeprecatedNames(requestHandlers, withDeprecationNotice, shardId, logger)
+	registerDeprecatedNames(streamHandlers, withStreamDeprecationNotice, shardId, logger)
+	maps.Copy(requestHandlers, jsonHandlers)
+
+	// The handshake is served by the ID without a version, since the version is not known before it.
+	versionProtocol := makeProtocolId(shardId, apiName, getApiVersionMethodName)
+	requestHandlers[versionProtocol] = packHandlerErrors(
+		chainInterceptors(ctx, versionProtocol, makeGetApiVersionRequestHandler(), cfg.Interceptors),
+		packGetApiVersionError)
+
+	// The listing is served by the ID without a version as well, so that it is found before the handshake.
+	listProtocol := makeProtocolId(shardId, apiName, listSupportedMethodsMethodName)
+	supportedMethods := describeSupportedMethods(
+		append(slices.Collect(maps.Keys(requestHandlers)), listProtocol),
+		slices.Collect(maps.Keys(streamHandlers)),
+		shardId,
+		apiName,
+		cfg)
+	requestHandlers[listProtocol] = packHandlerErrors(
+		chainInterceptors(ctx, listProtocol, makeListSupportedMethodsRequestHandler(supportedMethods), cfg.Interceptors),
+		packListSupportedMethodsError)
+	return requestHandlers, streamHandlers, nil
+}
+
+// packHandlerErrors sends the errors returned by the handler (e.g., by an interceptor rejecting the request)
+// to the client in the error envelope of the response instead of dropping the stream.
+func packHandlerErrors(handler network.RequestHandler, packError func(error) []byte) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		response, err := handler(ctx, request)
+		if err != nil {
+			return packError(err), nil
+		}
+		return response, nil
+	}
+}
+
+// SetRawApiRequestHandlers sets the handlers of the methods of the transport protocol served by the API
+// of the shard, e.g., to serve a ShardApiRo with NetworkTransportProtocolRo on the in-process managers
+// of rawapitest. Api is the interface of the API, the handlers call its methods through reflection.
+func SetRawApiRequestHandlers[Transport, Api any](
+	ctx context.Context,
+	api Api,
+	shardId types.ShardId,
+	manager network.Manager,
+	cfg RequestHandlersConfig,
+	logger logging.Logger,
+) error {
+	transportType := reflect.TypeFor[Transport]()
+	for _, served := range servedApis {
+		if served.transport == transportType {
+			return setRawApiRequestHandlers(
+				ctx, transportType, reflect.TypeFor[Api](), api, shardId, served.name, manager, cfg, logger)
+		}
 	}
-	return requestHandlers, nil
+	return fmt.Errorf("transport protocol %s is not served", transportType)
 }
 
 func setRawApiRequestHandlers(
@@ -86,30 +462,75 @@ func setRawApiRequestHandlers(
 	shardId types.ShardId,
 	apiName string,
 	manager network.Manager,
+	cfg RequestHandlersConfig,
 	logger logging.Logger,
 ) error {
-	requestHandlers, err := getRawApiRequestHandlers(protocolInterfaceType, apiType, api, shardId, apiName)
+	requestHandlers, streamHandlers, err := getRawApiRequestHandlers(
+		ctx, protocolInterfaceType, apiType, api, shardId, apiName, cfg, logger)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to create request handlers")
 		return err
 	}
+	if cfg.handlers != nil {
+		cfg.handlers.set(ctx, manager, shardId, apiName, &apiHandlers{requests: requestHandlers, streams: streamHandlers})
+		return nil
+	}
 	for name, handler := range requestHandlers {
 		manager.SetRequestHandler(ctx, name, handler)
 	}
+	for name, handler := range streamHandlers {
+		manager.SetStreamHandler(ctx, name, handler)
+	}
 	return nil
 }
 
-func makeRequestHandler(apiMethod reflect.Value, codec *methodCodec) network.RequestHandler {
+func makeRequestHandler(apiMethod reflect.Value, codec *methodCodec, logger logging.Logger) network.RequestHandler {
 	return func(ctx context.Context, request []byte) ([]byte, error) {
+		_, span := startRequestPhase(ctx, requestPhaseUnpack)
 		unpackedArguments, err := codec.unpackRequest(request)
+		endSpan(span, err)
 		if err != nil {
+			recordRequestError(ctx, err)
 			return codec.packError(err), nil
 		}
 
-		apiArguments := []reflect.Value{reflect.ValueOf(ctx)}
+		dispatchCtx, span := startRequestPhase(ctx, requestPhaseDispatch)
+		apiArguments := []reflect.Value{reflect.ValueOf(dispatchCtx)}
 		apiArguments = append(apiArguments, unpackedArguments...)
-		apiCallResults := apiMethod.Call(apiArguments)
+		apiCallResults, err := callApiMethod(apiMethod, apiArguments, logger)
+		if err == nil {
+			err, _ = apiCallResults[len(apiCallResults)-1].Interface().(error)
+		}
+		endSpan(span, err)
+		recordRequestError(ctx, err)
+		if apiCallResults == nil {
+			return codec.packError(err), nil
+		}
 
+		_, span = startRequestPhase(ctx, requestPhasePack)
+		defer span.End()
 		return codec.packResponse(apiCallResults...)
 	}
 }
+
+// callApiMethod calls the API method converting its panic into an internal error, so that the client
+// gets a response. The details of the panic are logged with the incident ID sent to the client.
+func callApiMethod(
+	apiMethod reflect.Value,
+	apiArguments []reflect.Value,
+	logger logging.Logger,
+) (results []reflect.Value, err error) {
+	defer recoverApiPanic(logger, &err)
+	return apiMethod.Call(apiArguments), nil
+}
+
+// recoverApiPanic is deferred by the callers of the API methods.
+func recoverApiPanic(logger logging.Logger, err *error) {
+	if r := recover(); r != nil {
+		incidentId := uuid.NewString()
+		logger.Error().
+			Str(logging.FieldIncidentId, incidentId).
+			Msgf("API method crashed: %v. Stack:\n%s", r, string(debug.Stack()))
+		*err = rawapitypes.NewInternalError(incidentId)
+	}
+}
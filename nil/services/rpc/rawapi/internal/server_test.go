@@ -1,18 +1,30 @@
 package internal
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"reflect"
 	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -46,20 +58,53 @@ type testApiIface interface {
 }
 
 type testApi struct {
-	handler func() (sszx.SSZEncodedData, error)
+	handler func(ctx context.Context) (sszx.SSZEncodedData, error)
 }
 
 func (t *testApi) TestMethod(
 	ctx context.Context,
 	blockReference rawapitypes.BlockReference,
 ) (sszx.SSZEncodedData, error) {
-	return t.handler()
+	return t.handler(ctx)
 }
 
 type testNetworkTransportProtocol interface {
 	TestMethod(pb.BlockRequest) pb.RawBlockResponse
 }
 
+// testDispatchedApiIface is served by the dispatcher written the way dispatchgen generates it.
+type testDispatchedApiIface interface {
+	testApiIface
+}
+
+// testSSZResponses encode the results of the test API in SSZ.
+var testSSZResponses = map[string]*sszResponseCodec{"TestMethod": sszEncodedDataResponse}
+
+func init() {
+	deprecatedMethodNames["TestMethod"] = []string{"LegacyTestMethod"}
+
+	registerDispatchers(func(api testDispatchedApiIface) map[string]methodDispatcher {
+		return map[string]methodDispatcher{
+			"TestMethod": func(ctx context.Context, request []byte) ([]byte, error) {
+				var pbRequest pb.BlockRequest
+				if err := unmarshalRequest(request, &pbRequest); err != nil {
+					return nil, err
+				}
+				blockReference, err := pbRequest.UnpackProtoMessage()
+				if err != nil {
+					return nil, wrapRequestUnpackError(err)
+				}
+				result, err := api.TestMethod(ctx, blockReference)
+				var pbResponse pb.RawBlockResponse
+				if packErr := pbResponse.PackProtoMessage(result, err); packErr != nil {
+					return nil, packErr
+				}
+				return proto.Marshal(&pbResponse)
+			},
+		}
+	})
+}
+
 type ApiServerTestSuite struct {
 	RawApiTestSuite
 
@@ -84,10 +129,20 @@ func (s *ApiServerTestSuite) SetupTest() {
 		types.BaseShardId,
 		"testapi",
 		s.serverNetworkManager,
+		RequestHandlersConfig{sszResponses: testSSZResponses},
 		s.logger)
 	s.Require().NoError(err)
 }
 
+func (s *ApiServerTestSuite) packRequestEnvelope(request []byte, timeout time.Duration) []byte {
+	s.T().Helper()
+
+	envelope, err := proto.Marshal(
+		new(pb.RequestEnvelope).PackProtoMessage(request, timeout, pb.Compression_NoCompression, false))
+	s.Require().NoError(err)
+	return envelope
+}
+
 func (s *ApiServerTestSuite) makeValidLatestBlockRequest() []byte {
 	s.T().Helper()
 
@@ -100,7 +155,7 @@ func (s *ApiServerTestSuite) makeValidLatestBlockRequest() []byte {
 	}
 	requestBytes, err := proto.Marshal(request)
 	s.Require().NoError(err)
-	return requestBytes
+	return s.packRequestEnvelope(requestBytes, 0)
 }
 
 func (s *ApiServerTestSuite) makeInvalidBlockRequest() []byte {
@@ -111,12 +166,12 @@ func (s *ApiServerTestSuite) makeInvalidBlockRequest() []byte {
 	}
 	requestBytes, err := proto.Marshal(request)
 	s.Require().NoError(err)
-	return requestBytes
+	return s.packRequestEnvelope(requestBytes, 0)
 }
 
 func (s *ApiServerTestSuite) TestValidResponse() {
 	var index types.TransactionIndex
-	s.api.handler = func() (sszx.SSZEncodedData, error) {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
 		index++
 		return index.Bytes(), nil
 	}
@@ -134,7 +189,7 @@ func (s *ApiServerTestSuite) TestValidResponse() {
 }
 
 func (s *ApiServerTestSuite) TestNilResponse() {
-	s.api.handler = func() (sszx.SSZEncodedData, error) {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
 		return nil, nil
 	}
 
@@ -150,8 +205,32 @@ func (s *ApiServerTestSuite) TestNilResponse() {
 	s.Require().Equal("block should not be nil", pbResponse.GetError().GetMessage())
 }
 
+func (s *ApiServerTestSuite) TestUnenvelopedRequest() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	// The legacy nodes send the requests unwrapped, and receive the responses unwrapped as well.
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+	response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+		s.ctx, s.serverPeerId, "/shard/1/testapi/TestMethod", request)
+	s.Require().NoError(err)
+
+	var pbResponse pb.RawBlockResponse
+	s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+	s.Require().Nil(pbResponse.GetError())
+	s.Require().EqualValues(1, types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()))
+}
+
 func (s *ApiServerTestSuite) TestInvalidSchemaRequest() {
-	s.api.handler = func() (sszx.SSZEncodedData, error) {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
 		return sszx.SSZEncodedData{}, nil
 	}
 
@@ -170,7 +249,7 @@ func (s *ApiServerTestSuite) TestInvalidSchemaRequest() {
 }
 
 func (s *ApiServerTestSuite) TestInvalidDataRequest() {
-	s.api.handler = func() (sszx.SSZEncodedData, error) {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
 		return sszx.SSZEncodedData{}, nil
 	}
 
@@ -184,10 +263,71 @@ func (s *ApiServerTestSuite) TestInvalidDataRequest() {
 	s.Require().NoError(err)
 	s.Require().NotNil(pbResponse.GetError())
 	s.Require().Equal("unexpected block reference type", pbResponse.GetError().GetMessage())
+	s.Require().Equal(pb.ErrorCode_InvalidBlockReferenceError, pbResponse.GetError().GetCode())
+}
+
+func (s *ApiServerTestSuite) TestResponseBlockTag() {
+	blockHash := common.HexToHash("0x1234")
+	resolvedBlocks := []common.Hash{blockHash}
+	s.api.handler = func(ctx context.Context) (sszx.SSZEncodedData, error) {
+		for _, hash := range resolvedBlocks {
+			recordResolvedBlock(ctx, hash)
+		}
+		return sszx.SSZEncodedData{1, 2, 3}, nil
+	}
+
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+
+	send := func(ctx context.Context) (*pb.RawBlockResponse, common.Hash) {
+		var block ResponseBlock
+		payload, err := sendEnvelopedRequest(
+			WithResponseBlock(ctx, &block), s.clientNetworkManager, s.serverPeerId,
+			"/shard/1/testapi/1.0/TestMethod", request)
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(payload, &pbResponse))
+		return &pbResponse, block.Hash()
+	}
+
+	s.Run("Tagged", func() {
+		response, tag := send(s.ctx)
+		s.Require().Equal([]byte{1, 2, 3}, response.GetData().GetBlockSSZ())
+		s.Require().Equal(blockHash, tag)
+	})
+
+	s.Run("Modified", func() {
+		response, tag := send(WithIfBlockChanged(s.ctx, common.HexToHash("0x5678")))
+		s.Require().Equal([]byte{1, 2, 3}, response.GetData().GetBlockSSZ())
+		s.Require().Equal(blockHash, tag)
+	})
+
+	s.Run("NotModified", func() {
+		response, tag := send(WithIfBlockChanged(s.ctx, blockHash))
+		s.Require().Equal(pb.ErrorCode_NotModifiedError, response.GetError().GetCode())
+		s.Require().ErrorIs(response.GetError().UnpackProtoMessage(), rawapitypes.ErrNotModified)
+		s.Require().Equal(blockHash, tag)
+	})
+
+	s.Run("Ambiguous", func() {
+		// The data read at the other block after the one the request is conditional on is modified.
+		resolvedBlocks = []common.Hash{blockHash, common.HexToHash("0x5678")}
+		defer func() { resolvedBlocks = []common.Hash{blockHash} }()
+
+		response, tag := send(WithIfBlockChanged(s.ctx, blockHash))
+		s.Require().Equal([]byte{1, 2, 3}, response.GetData().GetBlockSSZ())
+		s.Require().Empty(tag)
+	})
 }
 
 func (s *ApiServerTestSuite) TestHandlerPanic() {
-	s.api.handler = func() (sszx.SSZEncodedData, error) {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
 		panic("test panic")
 	}
 
@@ -196,11 +336,1751 @@ func (s *ApiServerTestSuite) TestHandlerPanic() {
 		s.ctx, s.serverPeerId, "/shard/1/testapi/TestMethod", request)
 	s.Require().NoError(err)
 
-	s.Require().Empty(response)
+	var pbResponse pb.RawBlockResponse
+	s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+	s.Require().Equal(pb.ErrorCode_InternalError, pbResponse.GetError().GetCode())
+	s.Require().NotEmpty(pbResponse.GetError().GetIncidentId())
+	s.Require().NotContains(pbResponse.GetError().GetMessage(), "test panic")
 }
 
-func TestApiServerResponses(t *testing.T) {
-	t.Parallel()
+func (s *ApiServerTestSuite) TestBatchRequest() {
+	var index types.TransactionIndex
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		index++
+		return index.Bytes(), nil
+	}
 
-	suite.Run(t, new(ApiServerTestSuite))
+	codec, err := newApiCodec(reflect.TypeFor[testApiIface](), reflect.TypeFor[testNetworkTransportProtocol]())
+	s.Require().NoError(err)
+
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	calls := []batchCall{
+		{codec: codec["TestMethod"], args: []any{latest}},
+		{codec: codec["TestMethod"], args: []any{latest}},
+	}
+	s.Eventually(
+		func() bool {
+			return len(s.clientNetworkManager.GetPeersForProtocol("/shard/1/testapi/Batch")) != 0
+		},
+		10*time.Second,
+		100*time.Millisecond)
+
+	results, err := doNetworkShardApiBatchRequest(
+		s.ctx, s.clientNetworkManager, selectFirstPeer, types.BaseShardId, "testapi", calls)
+	s.Require().NoError(err)
+	s.Require().Len(results, 2)
+	for i, result := range results {
+		s.Require().NoError(result.Err)
+		s.Require().EqualValues(i+1, types.BytesToTransactionIndex(result.Result.(sszx.SSZEncodedData)))
+	}
+}
+
+func (s *ApiServerTestSuite) TestBatchRequestUnknownMethod() {
+	request, err := proto.Marshal(&pb.BatchRequest{
+		Requests: []*pb.BatchSubRequest{{Method: "UnknownMethod"}},
+	})
+	s.Require().NoError(err)
+
+	response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+		s.ctx, s.serverPeerId, "/shard/1/testapi/Batch", s.packRequestEnvelope(request, 0))
+	s.Require().NoError(err)
+
+	var pbResponse pb.BatchResponse
+	s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+	s.Require().Len(pbResponse.GetData().GetResponses(), 1)
+	s.Require().Equal(
+		"method UnknownMethod not found", pbResponse.GetData().GetResponses()[0].GetError().GetMessage())
+}
+
+// batchedShardApiRo serves the accounts read by TestShardApiRoBatch, the balance of the other accounts fails.
+type batchedShardApiRo struct {
+	shardApiRo
+
+	known types.Address
+}
+
+func (api batchedShardApiRo) GetBalance(
+	_ context.Context, address types.Address, _ rawapitypes.BlockReference,
+) (types.Value, error) {
+	if address != api.known {
+		return types.Value{}, errors.New("unknown account")
+	}
+	return types.NewValueFromUint64(10), nil
+}
+
+func (api batchedShardApiRo) GetCode(
+	context.Context, types.Address, rawapitypes.BlockReference,
+) (types.Code, error) {
+	return types.Code{1, 2, 3}, nil
+}
+
+func (api batchedShardApiRo) GetAccountMeta(
+	context.Context, types.Address, rawapitypes.BlockReference,
+) (*rawapitypes.AccountMeta, error) {
+	return &rawapitypes.AccountMeta{Exists: true, ExtSeqno: 5}, nil
+}
+
+func (s *ApiServerTestSuite) TestShardApiRoBatch() {
+	known := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[NetworkTransportProtocolRo](),
+		reflect.TypeFor[shardApiRo](),
+		batchedShardApiRo{known: known},
+		types.BaseShardId,
+		apiNameRo,
+		s.serverNetworkManager,
+		RequestHandlersConfig{},
+		s.logger)
+	s.Require().NoError(err)
+	s.Eventually(
+		func() bool {
+			return len(s.clientNetworkManager.GetPeersForProtocol("/shard/1/rawapi_ro/Batch")) != 0
+		},
+		10*time.Second,
+		100*time.Millisecond)
+
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	batch := NewNetworkShardApiRoBatch(s.clientNetworkManager, types.BaseShardId, nil)
+	balance := batch.GetBalance(known, latest)
+	code := batch.GetCode(known, latest)
+	meta := batch.GetAccountMeta(known, latest)
+	unknown := batch.GetBalance(types.ShardAndHexToAddress(types.BaseShardId, "0x5678"), latest)
+	s.Require().NoError(batch.Send(s.ctx))
+
+	s.Require().NoError(balance.Err)
+	s.Equal(types.NewValueFromUint64(10), balance.Result)
+	s.Require().NoError(code.Err)
+	s.Equal(types.Code{1, 2, 3}, code.Result)
+	s.Require().NoError(meta.Err)
+	s.Equal(types.Seqno(5), meta.Result.ExtSeqno)
+	// The failure of a call doesn't fail the others.
+	s.Require().ErrorContains(unknown.Err, "unknown account")
+
+	// The calls are sent once.
+	s.Require().NoError(batch.Send(s.ctx))
+}
+
+func (s *ApiServerTestSuite) TestInterceptors() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	var calls []string
+	makeInterceptor := func(name string) RequestInterceptor {
+		return func(
+			_ context.Context, protocol network.ProtocolID, next network.RequestHandler,
+		) network.RequestHandler {
+			return func(ctx context.Context, request []byte) ([]byte, error) {
+				calls = append(calls, name+" "+string(protocol))
+				return next(ctx, request)
+			}
+		}
+	}
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"interceptedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{Interceptors: []RequestInterceptor{makeInterceptor("first"), makeInterceptor("second")}},
+		s.logger)
+	s.Require().NoError(err)
+
+	request := s.makeValidLatestBlockRequest()
+	_, err = s.clientNetworkManager.SendRequestAndGetResponse(
+		s.ctx, s.serverPeerId, "/shard/1/interceptedapi/TestMethod", request)
+	s.Require().NoError(err)
+	s.Require().Equal([]string{
+		"first /shard/1/interceptedapi/TestMethod",
+		"second /shard/1/interceptedapi/TestMethod",
+	}, calls)
+}
+
+func (s *ApiServerTestSuite) TestRecordAndReplay() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	ring := NewRequestRing(2)
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"recordedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{Interceptors: []RequestInterceptor{NewRecordingInterceptor(ring)}},
+		s.logger)
+	s.Require().NoError(err)
+
+	for range 3 {
+		_, err = s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/recordedapi/TestMethod", s.makeValidLatestBlockRequest())
+		s.Require().NoError(err)
+	}
+	recorded := ring.Requests()
+	s.Require().Len(recorded, 2)
+	s.Equal(network.ProtocolID("/shard/1/recordedapi/TestMethod"), recorded[0].Protocol)
+
+	var file bytes.Buffer
+	for _, request := range recorded {
+		s.Require().NoError(json.NewEncoder(&file).Encode(request))
+	}
+	read, err := ReadRecordedRequests(&file)
+	s.Require().NoError(err)
+	s.Require().Len(read, 2)
+	s.Equal(recorded[1].Request, read[1].Request)
+	s.Equal(recorded[1].Response, read[1].Response)
+
+	replay := func() *ReplayReport {
+		s.T().Helper()
+
+		report, err := replayRecordedRequests(
+			s.ctx,
+			reflect.TypeFor[testNetworkTransportProtocol](),
+			reflect.TypeFor[testApiIface](),
+			s.api,
+			types.BaseShardId,
+			"recordedapi",
+			append(read, RecordedRequest{Protocol: "/shard/1/recordedapi/Batch"}))
+		s.Require().NoError(err)
+		return report
+	}
+
+	report := replay()
+	s.Equal(2, report.Replayed)
+	s.Equal(1, report.Skipped)
+	s.Empty(report.Mismatches)
+
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(2).Bytes(), nil
+	}
+	report = replay()
+	s.Len(report.Mismatches, 2)
+}
+
+func (s *ApiServerTestSuite) TestRateLimit() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"limitedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{Interceptors: []RequestInterceptor{NewRateLimitInterceptor(RateLimits{
+			"TestMethod": {PeerRequestsPerSecond: 0.001},
+		})}},
+		s.logger)
+	s.Require().NoError(err)
+
+	request := s.makeValidLatestBlockRequest()
+	sendRequest := func() *pb.RawBlockResponse {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/limitedapi/TestMethod", request)
+		s.Require().NoError(err)
+
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		return &pbResponse
+	}
+
+	s.Require().Nil(sendRequest().GetError())
+
+	pbError := sendRequest().GetError()
+	s.Require().NotNil(pbError)
+	s.Require().ErrorIs(pbError.UnpackProtoMessage(), rawapitypes.ErrRateLimited)
+}
+
+func (s *ApiServerTestSuite) TestRequestTimeout() {
+	var deadline time.Time
+	s.api.handler = func(ctx context.Context) (sszx.SSZEncodedData, error) {
+		var ok bool
+		deadline, ok = ctx.Deadline()
+		s.True(ok)
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+
+	sent := time.Now()
+	_, err = s.clientNetworkManager.SendRequestAndGetResponse(
+		s.ctx, s.serverPeerId, "/shard/1/testapi/TestMethod", s.packRequestEnvelope(request, time.Second))
+	s.Require().NoError(err)
+	s.Require().WithinDuration(sent.Add(time.Second), deadline, 500*time.Millisecond)
+}
+
+func (s *ApiServerTestSuite) TestTimeoutInterceptor() {
+	s.api.handler = func(ctx context.Context) (sszx.SSZEncodedData, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"timedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{Interceptors: []RequestInterceptor{
+			NewTimeoutInterceptor(MethodTimeouts{"TestMethod": 10 * time.Millisecond}),
+		}},
+		s.logger)
+	s.Require().NoError(err)
+
+	response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+		s.ctx, s.serverPeerId, "/shard/1/timedapi/TestMethod", s.makeValidLatestBlockRequest())
+	s.Require().NoError(err)
+
+	var pbResponse pb.RawBlockResponse
+	s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+	s.Require().Equal(context.DeadlineExceeded.Error(), pbResponse.GetError().GetMessage())
+}
+
+func (s *ApiServerTestSuite) TestRequestSizeInterceptor() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	setHandlers := func(apiName string, limit int) {
+		err := setRawApiRequestHandlers(
+			s.ctx,
+			reflect.TypeFor[testNetworkTransportProtocol](),
+			reflect.TypeFor[testApiIface](),
+			s.api,
+			types.BaseShardId,
+			apiName,
+			s.serverNetworkManager,
+			RequestHandlersConfig{Interceptors: []RequestInterceptor{
+				NewRequestSizeInterceptor(RequestSizeLimits{"TestMethod": limit}),
+			}},
+			s.logger)
+		s.Require().NoError(err)
+	}
+	setHandlers("smallapi", 1)
+	setHandlers("largeapi", 1024)
+
+	sendRequest := func(protocol network.ProtocolID) *pb.Error {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, protocol, s.makeValidLatestBlockRequest())
+		s.Require().NoError(err)
+
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		return pbResponse.GetError()
+	}
+
+	pbErr := sendRequest("/shard/1/smallapi/TestMethod")
+	s.Require().NotNil(pbErr)
+	s.Require().Equal(pb.ErrorCode_InvalidArgumentError, pbErr.GetCode())
+	s.Require().Nil(sendRequest("/shard/1/largeapi/TestMethod"))
+}
+
+func (s *ApiServerTestSuite) TestAuthInterceptor() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	setHandlers := func(apiName string, auth RequestAuth) {
+		err := setRawApiRequestHandlers(
+			s.ctx,
+			reflect.TypeFor[testNetworkTransportProtocol](),
+			reflect.TypeFor[testApiIface](),
+			s.api,
+			types.BaseShardId,
+			apiName,
+			s.serverNetworkManager,
+			RequestHandlersConfig{Interceptors: []RequestInterceptor{NewAuthInterceptor(auth)}},
+			s.logger)
+		s.Require().NoError(err)
+	}
+	setHandlers("secretapi", RequestAuth{SharedSecret: "secret"})
+	setHandlers("peerapi", RequestAuth{AllowedPeers: []network.PeerID{s.clientNetworkManager.ID()}})
+	setHandlers("otherpeerapi", RequestAuth{AllowedPeers: []network.PeerID{s.serverPeerId}})
+
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+	sendRequest := func(protocol network.ProtocolID, token string) error {
+		envelope := new(pb.RequestEnvelope).PackProtoMessage(request, 0, pb.Compression_NoCompression, false)
+		envelope.AuthToken = token
+		envelopeBytes, err := proto.Marshal(envelope)
+		s.Require().NoError(err)
+
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, protocol, envelopeBytes)
+		s.Require().NoError(err)
+
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		if pbResponse.GetError() != nil {
+			return pbResponse.GetError().UnpackProtoMessage()
+		}
+		return nil
+	}
+
+	s.Require().NoError(sendRequest("/shard/1/secretapi/TestMethod", "secret"))
+	s.Require().ErrorIs(sendRequest("/shard/1/secretapi/TestMethod", "wrong"), rawapitypes.ErrUnauthorized)
+	s.Require().ErrorIs(sendRequest("/shard/1/secretapi/TestMethod", ""), rawapitypes.ErrUnauthorized)
+	s.Require().NoError(sendRequest("/shard/1/peerapi/TestMethod", ""))
+	s.Require().ErrorIs(sendRequest("/shard/1/otherpeerapi/TestMethod", "secret"), rawapitypes.ErrUnauthorized)
+}
+
+func (s *ApiServerTestSuite) TestPeerAcl() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	accessControl := NewAccessControl(PeerAcl{"TestMethod": {s.clientNetworkManager.ID()}})
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"aclapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{Interceptors: []RequestInterceptor{accessControl.Interceptor()}},
+		s.logger)
+	s.Require().NoError(err)
+
+	request := s.makeValidLatestBlockRequest()
+	sendRequest := func() *pb.RawBlockResponse {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/aclapi/TestMethod", request)
+		s.Require().NoError(err)
+
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		return &pbResponse
+	}
+
+	s.Require().Nil(sendRequest().GetError())
+
+	// The ACL is applied to the handlers that are already set.
+	accessControl.Set(PeerAcl{"/shard/1/aclapi/TestMethod": {s.serverPeerId}})
+	pbError := sendRequest().GetError()
+	s.Require().NotNil(pbError)
+	s.Require().ErrorIs(pbError.UnpackProtoMessage(), rawapitypes.ErrUnauthorized)
+
+	accessControl.Set(nil)
+	s.Require().Nil(sendRequest().GetError())
+}
+
+func (s *ApiServerTestSuite) TestPeerQuotas() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	quotas := NewPeerQuotas(PeerQuotaConfig{Quota: PeerQuota{Requests: 1}, BanDuration: time.Hour}, nil)
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"quotaapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{Interceptors: []RequestInterceptor{quotas.Interceptor()}},
+		s.logger)
+	s.Require().NoError(err)
+
+	request := s.makeValidLatestBlockRequest()
+	sendRequest := func() *pb.RawBlockResponse {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/quotaapi/TestMethod", request)
+		s.Require().NoError(err)
+
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		return &pbResponse
+	}
+
+	s.Require().Nil(sendRequest().GetError())
+	// The request exceeding the quota is served, and the peer is banned afterwards.
+	s.Require().Nil(sendRequest().GetError())
+
+	pbError := sendRequest().GetError()
+	s.Require().NotNil(pbError)
+	s.Require().ErrorIs(pbError.UnpackProtoMessage(), rawapitypes.ErrRateLimited)
+
+	usage := quotas.Usage()
+	s.Require().Len(usage, 1)
+	s.Equal(s.clientNetworkManager.ID(), usage[0].PeerId)
+	s.EqualValues(2, usage[0].Requests)
+	s.EqualValues(len(request)*2, usage[0].RequestBytes)
+	s.Positive(usage[0].ResponseBytes)
+	s.EqualValues(1, usage[0].Bans)
+	s.True(usage[0].BannedUntil.After(time.Now()))
+}
+
+func (s *ApiServerTestSuite) TestTraceContextPropagation() {
+	var serverSpanContext trace.SpanContext
+	s.api.handler = func(ctx context.Context) (sszx.SSZEncodedData, error) {
+		serverSpanContext = trace.SpanContextFromContext(ctx)
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	clientSpanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(s.ctx, clientSpanContext)
+
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+	envelope, err := packRequestEnvelope(ctx, "/shard/1/testapi/TestMethod", request)
+	s.Require().NoError(err)
+
+	_, err = s.clientNetworkManager.SendRequestAndGetResponse(
+		s.ctx, s.serverPeerId, "/shard/1/testapi/TestMethod", envelope)
+	s.Require().NoError(err)
+	s.Require().Equal(clientSpanContext.TraceID(), serverSpanContext.TraceID())
+}
+
+func (s *ApiServerTestSuite) TestRequestSigning() {
+	var signer RequestSigner
+	var signed bool
+	s.api.handler = func(ctx context.Context) (sszx.SSZEncodedData, error) {
+		signer, signed = GetRequestSigner(ctx)
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	key, err := crypto.GenerateKey()
+	s.Require().NoError(err)
+	publicKey := crypto.CompressPubkey(&key.PublicKey)
+	salt := common.IntToHash(123)
+	address := smartAccountAddress(types.BaseShardId, salt, publicKey)
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+	sendRequest := func(envelope []byte) *pb.RawBlockResponse {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/testapi/TestMethod", envelope)
+		s.Require().NoError(err)
+
+		var responseEnvelope pb.ResponseEnvelope
+		s.Require().NoError(proto.Unmarshal(response, &responseEnvelope))
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(responseEnvelope.GetPayload(), &pbResponse))
+		return &pbResponse
+	}
+	requireUnauthorized := func(envelope []byte) {
+		pbError := sendRequest(envelope).GetError()
+		s.Require().NotNil(pbError)
+		s.Require().ErrorIs(pbError.UnpackProtoMessage(), rawapitypes.ErrUnauthorized)
+	}
+	ctx := WithRequestSigningKey(s.ctx, address, salt, key)
+
+	// The versioned protocol is signed without the version, so the request is valid for the legacy one.
+	envelope, err := packRequestEnvelope(ctx, "/shard/1/testapi/1.0/TestMethod", request)
+	s.Require().NoError(err)
+	s.Require().Nil(sendRequest(envelope).GetError())
+	s.Require().True(signed)
+	s.Equal(address, signer.Address)
+	s.Equal(publicKey, signer.PublicKey)
+
+	s.Run("Replayed", func() {
+		requireUnauthorized(envelope)
+	})
+
+	s.Run("Unsigned", func() {
+		envelope, err := packRequestEnvelope(s.ctx, "/shard/1/testapi/TestMethod", request)
+		s.Require().NoError(err)
+		s.Require().Nil(sendRequest(envelope).GetError())
+		s.False(signed)
+	})
+
+	s.Run("OtherMethod", func() {
+		envelope, err := packRequestEnvelope(ctx, "/shard/1/testapi/OtherMethod", request)
+		s.Require().NoError(err)
+		requireUnauthorized(envelope)
+	})
+
+	s.Run("OtherShard", func() {
+		envelope, err := packRequestEnvelope(ctx, "/shard/2/testapi/TestMethod", request)
+		s.Require().NoError(err)
+		requireUnauthorized(envelope)
+	})
+
+	s.Run("OtherAccount", func() {
+		otherAddress := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+		envelope, err := packRequestEnvelope(
+			WithRequestSigningKey(s.ctx, otherAddress, salt, key), "/shard/1/testapi/TestMethod", request)
+		s.Require().NoError(err)
+		requireUnauthorized(envelope)
+	})
+
+	s.Run("Expired", func() {
+		signature := &pb.RequestSignature{
+			Address:  address.Bytes(),
+			Salt:     salt.Bytes(),
+			SignedAt: time.Now().Add(-time.Hour).UnixMilli(),
+			Nonce:    make([]byte, requestNonceSize),
+		}
+		hash := requestSigningHash("/shard/1/testapi/TestMethod", signature, request)
+		signature.Signature, err = crypto.Sign(hash, key)
+		s.Require().NoError(err)
+		pbEnvelope := new(pb.RequestEnvelope).PackProtoMessage(request, 0, pb.Compression_NoCompression, true)
+		pbEnvelope.Signature = signature
+		envelope, err := proto.Marshal(pbEnvelope)
+		s.Require().NoError(err)
+		requireUnauthorized(envelope)
+	})
+}
+
+func (s *ApiServerTestSuite) TestRequestLogSampling() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	setHandlers := func(apiName string, cfg RequestLogConfig) *bytes.Buffer {
+		var logs bytes.Buffer
+		err := setRawApiRequestHandlers(
+			s.ctx,
+			reflect.TypeFor[testNetworkTransportProtocol](),
+			reflect.TypeFor[testApiIface](),
+			s.api,
+			types.BaseShardId,
+			apiName,
+			s.serverNetworkManager,
+			RequestHandlersConfig{Logging: cfg},
+			logging.NewLoggerWithWriter("Test", &logs))
+		s.Require().NoError(err)
+		return &logs
+	}
+	allLogs := setHandlers("loggedapi", RequestLogConfig{SuccessSampleRate: 1})
+	noLogs := setHandlers("unloggedapi", RequestLogConfig{SuccessSampleRate: -1})
+
+	request := s.makeValidLatestBlockRequest()
+	for _, protocol := range []network.ProtocolID{"/shard/1/loggedapi/TestMethod", "/shard/1/unloggedapi/TestMethod"} {
+		_, err := s.clientNetworkManager.SendRequestAndGetResponse(s.ctx, s.serverPeerId, protocol, request)
+		s.Require().NoError(err)
+	}
+
+	s.Require().Contains(allLogs.String(), "Request handled")
+	s.Require().Contains(allLogs.String(), "/shard/1/loggedapi/TestMethod")
+	s.Require().Contains(allLogs.String(), s.clientNetworkManager.ID().String())
+	s.Require().NotContains(noLogs.String(), "Request handled")
+}
+
+func (s *ApiServerTestSuite) TestRequestWatchdog() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	var logs bytes.Buffer
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"watchedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{Logging: RequestLogConfig{SuccessSampleRate: -1, LargeResponseThreshold: 1}},
+		logging.NewLoggerWithWriter("Test", &logs))
+	s.Require().NoError(err)
+
+	_, err = s.clientNetworkManager.SendRequestAndGetResponse(
+		s.ctx, s.serverPeerId, "/shard/1/watchedapi/TestMethod", s.makeValidLatestBlockRequest())
+	s.Require().NoError(err)
+
+	s.Require().Contains(logs.String(), "Request exceeded the watchdog thresholds")
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	s.Require().Contains(logs.String(), fmt.Sprintf("%+v", latest))
+}
+
+func (s *ApiServerTestSuite) TestUnsetRequestHandlers() {
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		entered <- struct{}{}
+		<-release
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	handlers := newHandlerRegistry()
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"drainedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{handlers: handlers},
+		s.logger)
+	s.Require().NoError(err)
+
+	const protocol = "/shard/1/drainedapi/TestMethod"
+	request := s.makeValidLatestBlockRequest()
+	done := make(chan error)
+	go func() {
+		_, err := s.clientNetworkManager.SendRequestAndGetResponse(s.ctx, s.serverPeerId, protocol, request)
+		done <- err
+	}()
+	<-entered
+
+	// The drain is not waited for, the request being handled is reported.
+	ctx, cancel := context.WithCancel(s.ctx)
+	cancel()
+	s.Require().Equal(1, handlers.unset(ctx, s.serverNetworkManager, []types.ShardId{types.BaseShardId}))
+
+	// The new requests are not accepted, while the one being handled completes.
+	_, err = s.clientNetworkManager.SendRequestAndGetResponse(s.ctx, s.serverPeerId, protocol, request)
+	s.Require().Error(err)
+
+	close(release)
+	s.Require().NoError(<-done)
+}
+
+func (s *ApiServerTestSuite) TestSwapRequestHandlers() {
+	handlers := newHandlerRegistry()
+	setHandlers := func(index types.TransactionIndex) {
+		s.T().Helper()
+		api := &testApi{handler: func(context.Context) (sszx.SSZEncodedData, error) {
+			return index.Bytes(), nil
+		}}
+		err := setRawApiRequestHandlers(
+			s.ctx,
+			reflect.TypeFor[testNetworkTransportProtocol](),
+			reflect.TypeFor[testApiIface](),
+			api,
+			types.BaseShardId,
+			"swappedapi",
+			s.serverNetworkManager,
+			RequestHandlersConfig{handlers: handlers},
+			s.logger)
+		s.Require().NoError(err)
+	}
+
+	const protocol = "/shard/1/swappedapi/TestMethod"
+	request := s.makeValidLatestBlockRequest()
+	sendRequest := func() (types.TransactionIndex, error) {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(s.ctx, s.serverPeerId, protocol, request)
+		if err != nil {
+			return 0, err
+		}
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		return types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()), nil
+	}
+
+	setHandlers(1)
+	index, err := sendRequest()
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, index)
+
+	// The handlers set again are served without removing the protocol.
+	setHandlers(2)
+	index, err = sendRequest()
+	s.Require().NoError(err)
+	s.Require().EqualValues(2, index)
+
+	// The APIs not set in the round are removed.
+	handlers.beginRound(types.BaseShardId)
+	handlers.endRound(s.serverNetworkManager, types.BaseShardId)
+	_, err = sendRequest()
+	s.Require().Error(err)
+}
+
+func (s *ApiServerTestSuite) TestCompressedResponse() {
+	blockSSZ := make(sszx.SSZEncodedData, 1024)
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return blockSSZ, nil
+	}
+
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"compressedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{CompressionThreshold: 512},
+		s.logger)
+	s.Require().NoError(err)
+
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+
+	for _, compression := range []pb.Compression{pb.Compression_ZstdCompression, pb.Compression_SnappyCompression} {
+		envelope, err := proto.Marshal(new(pb.RequestEnvelope).PackProtoMessage(request, 0, compression, false))
+		s.Require().NoError(err)
+
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/compressedapi/TestMethod", envelope)
+		s.Require().NoError(err)
+
+		var responseEnvelope pb.ResponseEnvelope
+		s.Require().NoError(proto.Unmarshal(response, &responseEnvelope))
+		s.Require().Equal(compression, responseEnvelope.GetCompression())
+		s.Require().Less(len(responseEnvelope.GetPayload()), len(blockSSZ))
+
+		payload, err := decompressResponse(&responseEnvelope)
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(payload, &pbResponse))
+		s.Require().Equal([]byte(blockSSZ), pbResponse.GetData().GetBlockSSZ())
+	}
+}
+
+func (s *ApiServerTestSuite) TestChunkedResponse() {
+	blockSSZ := make(sszx.SSZEncodedData, 1024)
+	for i := range blockSSZ {
+		blockSSZ[i] = byte(i)
+	}
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return blockSSZ, nil
+	}
+
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"chunkedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{CompressionThreshold: -1, ChunkSize: 100},
+		s.logger)
+	s.Require().NoError(err)
+
+	request, err := proto.Marshal(&pb.BlockRequest{
+		Reference: &pb.BlockReference{
+			Reference: &pb.BlockReference_NamedBlockReference{
+				NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+			},
+		},
+	})
+	s.Require().NoError(err)
+
+	s.Run("Fetch", func() {
+		payload, err := sendEnvelopedRequest(
+			s.ctx, s.clientNetworkManager, s.serverPeerId, "/shard/1/chunkedapi/1.0/TestMethod", request)
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(payload, &pbResponse))
+		s.Require().Equal([]byte(blockSSZ), pbResponse.GetData().GetBlockSSZ())
+	})
+
+	s.Run("Resume", func() {
+		envelope, err := proto.Marshal(
+			new(pb.RequestEnvelope).PackProtoMessage(request, 0, pb.Compression_NoCompression, true))
+		s.Require().NoError(err)
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/chunkedapi/TestMethod", envelope)
+		s.Require().NoError(err)
+
+		var responseEnvelope pb.ResponseEnvelope
+		s.Require().NoError(proto.Unmarshal(response, &responseEnvelope))
+		chunked := responseEnvelope.GetChunked()
+		s.Require().NotNil(chunked)
+		s.Require().Empty(responseEnvelope.GetPayload())
+
+		// A chunk can be requested again, e.g., after the stream was dropped.
+		fetch := func(offset uint64) ([]byte, error) {
+			return fetchChunk(s.ctx, s.clientNetworkManager, s.serverPeerId, "/shard/1/chunkedapi/FetchChunk",
+				&pb.FetchChunkRequest{Handle: chunked.GetHandle(), Offset: offset})
+		}
+		first, err := fetch(100)
+		s.Require().NoError(err)
+		s.Require().Len(first, 100)
+		second, err := fetch(100)
+		s.Require().NoError(err)
+		s.Require().Equal(first, second)
+
+		_, err = fetch(chunked.GetTotalSize())
+		s.Require().ErrorIs(err, rawapitypes.ErrInvalidArgument)
+	})
+
+	s.Run("UnknownHandle", func() {
+		_, err := fetchChunk(s.ctx, s.clientNetworkManager, s.serverPeerId, "/shard/1/chunkedapi/FetchChunk",
+			&pb.FetchChunkRequest{Handle: "unknown"})
+		s.Require().ErrorIs(err, rawapitypes.ErrNotFound)
+	})
+}
+
+func (s *ApiServerTestSuite) TestApiVersion() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	s.Run("Negotiate", func() {
+		apiVersion, err := getApiVersion(s.ctx, s.clientNetworkManager, s.serverPeerId, types.BaseShardId, "testapi")
+		s.Require().NoError(err)
+		s.Require().Equal(sszApiVersion, apiVersion.GetVersion())
+		s.Require().Equal(supportedApiVersions, apiVersion.GetSupportedVersions())
+	})
+
+	s.Run("Incompatible", func() {
+		request, err := proto.Marshal(&pb.ApiVersionRequest{SupportedVersions: []string{"0.1"}})
+		s.Require().NoError(err)
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/testapi/GetApiVersion", request)
+		s.Require().NoError(err)
+
+		var pbResponse pb.ApiVersionResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		s.Require().Empty(pbResponse.GetData().GetVersion())
+		s.Require().Equal(supportedApiVersions, pbResponse.GetData().GetSupportedVersions())
+	})
+
+	s.Run("OlderClient", func() {
+		request, err := proto.Marshal(&pb.ApiVersionRequest{SupportedVersions: []string{legacyApiVersion}})
+		s.Require().NoError(err)
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/testapi/GetApiVersion", request)
+		s.Require().NoError(err)
+
+		var pbResponse pb.ApiVersionResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		s.Require().Equal(legacyApiVersion, pbResponse.GetData().GetVersion())
+	})
+
+	s.Run("OlderServer", func() {
+		// The legacy node serves the method by the ID without a version and neither wraps nor unwraps the messages.
+		s.serverNetworkManager.SetRequestHandler(
+			s.ctx,
+			"/shard/1/legacyapi/TestMethod",
+			func(ctx context.Context, request []byte) ([]byte, error) {
+				var blockRequest pb.BlockRequest
+				if err := proto.Unmarshal(request, &blockRequest); err != nil {
+					return nil, err
+				}
+				return proto.Marshal(&pb.RawBlockResponse{
+					Result: &pb.RawBlockResponse_Data{Data: &pb.RawBlock{BlockSSZ: types.TransactionIndex(2).Bytes()}},
+				})
+			})
+		s.Require().Eventually(func() bool {
+			return len(s.clientNetworkManager.GetPeersForProtocol("/shard/1/legacyapi/TestMethod")) != 0
+		}, 10*time.Second, 100*time.Millisecond)
+
+		peerId, protocol, err := discoverVersionedProtocol(
+			s.ctx, s.clientNetworkManager, selectFirstPeer, types.BaseShardId, "legacyapi", "TestMethod")
+		s.Require().NoError(err)
+		s.Require().Equal(network.ProtocolID("/shard/1/legacyapi/TestMethod"), protocol)
+
+		request, err := proto.Marshal(&pb.BlockRequest{
+			Reference: &pb.BlockReference{
+				Reference: &pb.BlockReference_NamedBlockReference{
+					NamedBlockReference: pb.NamedBlockReference_LatestBlock,
+				},
+			},
+		})
+		s.Require().NoError(err)
+		payload, err := sendEnvelopedRequest(s.ctx, s.clientNetworkManager, peerId, protocol, request)
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(payload, &pbResponse))
+		s.Require().EqualValues(2, types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()))
+	})
+
+	s.Run("AllVersionsServed", func() {
+		for _, protocol := range []network.ProtocolID{"/shard/1/testapi/TestMethod", "/shard/1/testapi/1.0/TestMethod"} {
+			response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+				s.ctx, s.serverPeerId, protocol, s.makeValidLatestBlockRequest())
+			s.Require().NoError(err)
+
+			var pbResponse pb.RawBlockResponse
+			s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+			s.Require().EqualValues(1, types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()))
+		}
+	})
+
+	s.Run("Discover", func() {
+		s.Require().Eventually(func() bool {
+			return len(s.clientNetworkManager.GetPeersForProtocol("/shard/1/testapi/GetApiVersion")) != 0
+		}, 10*time.Second, 100*time.Millisecond)
+
+		peerId, protocol, err := discoverVersionedProtocol(
+			s.ctx, s.clientNetworkManager, selectFirstPeer, types.BaseShardId, "testapi", "TestMethod")
+		s.Require().NoError(err)
+		s.Require().Equal(s.serverPeerId, peerId)
+		s.Require().Equal(network.ProtocolID("/shard/1/testapi/1.1/TestMethod"), protocol)
+	})
+
+	s.Run("SSZ", func() {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/testapi/1.1/TestMethod", s.makeValidLatestBlockRequest())
+		s.Require().NoError(err)
+		s.Require().Equal(append([]byte{sszResponseMarker}, types.TransactionIndex(1).Bytes()...), response)
+
+		// The older versions are still answered in Protobuf.
+		response, err = s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/testapi/1.0/TestMethod", s.makeValidLatestBlockRequest())
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		s.Require().EqualValues(1, types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()))
+	})
+}
+
+func (s *ApiServerTestSuite) TestJsonCodec() {
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		return types.TransactionIndex(1).Bytes(), nil
+	}
+
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"jsonapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{EnableJsonCodec: true},
+		s.logger)
+	s.Require().NoError(err)
+
+	doRequest := func(request string) *pb.RawBlockResponse {
+		s.T().Helper()
+
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/jsonapi/TestMethod/json", []byte(request))
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(protojson.Unmarshal(response, &pbResponse))
+		return &pbResponse
+	}
+
+	s.Run("Valid", func() {
+		pbResponse := doRequest(`{"reference": {"namedBlockReference": "LatestBlock"}}`)
+		s.Require().EqualValues(1, types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()))
+	})
+
+	s.Run("Error", func() {
+		pbResponse := doRequest(`{"reference": {}}`)
+		s.Require().Equal(pb.ErrorCode_InvalidBlockReferenceError, pbResponse.GetError().GetCode())
+	})
+
+	s.Run("Malformed", func() {
+		pbResponse := doRequest(`{"reference": `)
+		s.Require().Equal(pb.ErrorCode_InvalidArgumentError, pbResponse.GetError().GetCode())
+		s.Require().Contains(pbResponse.GetError().GetMessage(), "failed to unpack JSON request")
+	})
+
+	s.Run("Disabled", func() {
+		_, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/testapi/TestMethod/json", []byte(`{}`))
+		s.Require().Error(err)
+	})
+}
+
+func (s *ApiServerTestSuite) TestDeprecatedMethodName() {
+	var index types.TransactionIndex
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		index++
+		return index.Bytes(), nil
+	}
+
+	for _, protocol := range []network.ProtocolID{
+		"/shard/1/testapi/LegacyTestMethod",
+		"/shard/1/testapi/1.0/LegacyTestMethod",
+		"/shard/1/testapi/1.1/LegacyTestMethod",
+	} {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, protocol, s.makeValidLatestBlockRequest())
+		s.Require().NoError(err, protocol)
+		s.Require().NotEmpty(response, protocol)
+	}
+	s.Require().EqualValues(3, index)
+
+	codec, err := newApiCodec(reflect.TypeFor[testApiIface](), reflect.TypeFor[testNetworkTransportProtocol]())
+	s.Require().NoError(err)
+	legacyCodec := *codec["TestMethod"]
+	legacyCodec.methodName = "LegacyTestMethod"
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	results, err := doNetworkShardApiBatchRequest(
+		s.ctx, s.clientNetworkManager, selectFirstPeer, types.BaseShardId, "testapi",
+		[]batchCall{{codec: &legacyCodec, args: []any{latest}}})
+	s.Require().NoError(err)
+	s.Require().Len(results, 1)
+	s.Require().NoError(results[0].Err)
+	s.Require().EqualValues(4, types.BytesToTransactionIndex(results[0].Result.(sszx.SSZEncodedData)))
+}
+
+func (s *ApiServerTestSuite) TestListSupportedMethods() {
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"methodsapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{RequestSizeLimits: RequestSizeLimits{"TestMethod": 100}, EnableJsonCodec: true},
+		s.logger)
+	s.Require().NoError(err)
+
+	methods, err := listApiMethods(s.ctx, s.clientNetworkManager, s.serverPeerId, types.BaseShardId, "methodsapi")
+	s.Require().NoError(err)
+	byName := make(map[string]SupportedMethod, len(methods))
+	for _, method := range methods {
+		s.Require().Equal("methodsapi", method.Api)
+		s.Require().False(method.ReadOnly)
+		byName[method.Method] = method
+	}
+	s.Require().Contains(byName, batchMethodName)
+	s.Require().Contains(byName, listSupportedMethodsMethodName)
+
+	method := byName["TestMethod"]
+	s.Require().Equal([]string{sszApiVersion, legacyApiVersion}, method.Versions)
+	s.Require().Equal(100, method.MaxRequestSize)
+	s.Require().True(method.Json)
+	s.Require().False(method.Stream)
+	s.Require().Empty(method.CurrentMethod)
+	s.Require().Contains(method.ProtocolIds, network.ProtocolID("/shard/1/methodsapi/1.1/TestMethod"))
+	s.Require().Contains(method.ProtocolIds, network.ProtocolID("/shard/1/methodsapi/TestMethod/json"))
+
+	deprecated := byName["LegacyTestMethod"]
+	s.Require().Equal("TestMethod", deprecated.CurrentMethod)
+	s.Require().Equal(100, deprecated.MaxRequestSize)
+
+	s.Require().Empty(byName[getApiVersionMethodName].Versions)
+
+	s.Run("SizeLimit", func() {
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/methodsapi/TestMethod", s.packRequestEnvelope(make([]byte, 101), 0))
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		s.Require().Equal(pb.ErrorCode_InvalidArgumentError, pbResponse.GetError().GetCode())
+	})
+}
+
+func (s *ApiServerTestSuite) TestDispatchedRequest() {
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testDispatchedApiIface](),
+		s.api,
+		types.BaseShardId,
+		"dispatchedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{},
+		s.logger)
+	s.Require().NoError(err)
+
+	doRequest := func(request []byte) *pb.RawBlockResponse {
+		s.T().Helper()
+		response, err := s.clientNetworkManager.SendRequestAndGetResponse(
+			s.ctx, s.serverPeerId, "/shard/1/dispatchedapi/TestMethod", request)
+		s.Require().NoError(err)
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(response, &pbResponse))
+		return &pbResponse
+	}
+
+	s.Run("Valid", func() {
+		s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+			return types.TransactionIndex(1).Bytes(), nil
+		}
+		pbResponse := doRequest(s.makeValidLatestBlockRequest())
+		s.Require().EqualValues(1, types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()))
+	})
+
+	s.Run("InvalidRequest", func() {
+		pbResponse := doRequest(s.makeInvalidBlockRequest())
+		s.Require().Equal(pb.ErrorCode_InvalidBlockReferenceError, pbResponse.GetError().GetCode())
+	})
+
+	s.Run("Panic", func() {
+		s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+			panic("test panic")
+		}
+		pbResponse := doRequest(s.makeValidLatestBlockRequest())
+		s.Require().Equal(pb.ErrorCode_InternalError, pbResponse.GetError().GetCode())
+		s.Require().NotEmpty(pbResponse.GetError().GetIncidentId())
+	})
+}
+
+func (s *ApiServerTestSuite) TestResponseCache() {
+	var calls int
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		calls++
+		return types.TransactionIndex(calls).Bytes(), nil
+	}
+
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		"cachedapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{ResponseCache: ResponseCacheConfig{
+			Size:      16,
+			immutable: map[string]immutableResponseCheck{"TestMethod": isBlockReferencedByHash(0)},
+		}},
+		s.logger)
+	s.Require().NoError(err)
+
+	doRequest := func(blockReference rawapitypes.BlockReference) types.TransactionIndex {
+		s.T().Helper()
+
+		var pbRequest pb.BlockRequest
+		s.Require().NoError(pbRequest.PackProtoMessage(blockReference))
+		request, err := proto.Marshal(&pbRequest)
+		s.Require().NoError(err)
+		payload, err := sendEnvelopedRequest(
+			s.ctx, s.clientNetworkManager, s.serverPeerId, "/shard/1/cachedapi/1.0/TestMethod", request)
+		s.Require().NoError(err)
+
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(payload, &pbResponse))
+		return types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ())
+	}
+
+	byHash := rawapitypes.BlockHashAsBlockReference(common.HexToHash("0x1234"))
+	s.Require().EqualValues(1, doRequest(byHash))
+	s.Require().EqualValues(1, doRequest(byHash))
+
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	s.Require().EqualValues(2, doRequest(latest))
+	s.Require().EqualValues(3, doRequest(latest))
+	s.Require().Equal(3, calls)
+}
+
+func (s *ApiServerTestSuite) TestRequestDeduplication() {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	s.api.handler = func(context.Context) (sszx.SSZEncodedData, error) {
+		calls.Add(1)
+		<-release
+		return types.TransactionIndex(calls.Load()).Bytes(), nil
+	}
+
+	// The identical requests of the read-only API are handled separately if the deduplication is disabled,
+	// it's tested by TestRequestDeduplicator otherwise.
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		reflect.TypeFor[testNetworkTransportProtocol](),
+		reflect.TypeFor[testApiIface](),
+		s.api,
+		types.BaseShardId,
+		apiNameRo,
+		s.serverNetworkManager,
+		RequestHandlersConfig{DisableDeduplication: true},
+		s.logger)
+	s.Require().NoError(err)
+
+	var pbRequest pb.BlockRequest
+	s.Require().NoError(pbRequest.PackProtoMessage(rawapitypes.NamedBlockIdentifierAsBlockReference(
+		rawapitypes.LatestBlock)))
+	request, err := proto.Marshal(&pbRequest)
+	s.Require().NoError(err)
+
+	const requests = 5
+	responses := make(chan []byte, requests)
+	for range requests {
+		go func() {
+			payload, err := sendEnvelopedRequest(
+				s.ctx, s.clientNetworkManager, s.serverPeerId, "/shard/1/rawapi_ro/1.0/TestMethod", request)
+			s.NoError(err)
+			responses <- payload
+		}()
+	}
+
+	// All the requests reach the handler before the first one is answered.
+	s.Require().Eventually(func() bool {
+		return calls.Load() == requests
+	}, time.Second, 10*time.Millisecond)
+	close(release)
+
+	for range requests {
+		var pbResponse pb.RawBlockResponse
+		s.Require().NoError(proto.Unmarshal(<-responses, &pbResponse))
+		s.EqualValues(requests, types.BytesToTransactionIndex(pbResponse.GetData().GetBlockSSZ()))
+	}
+}
+
+func (s *ApiServerTestSuite) TestWorkerPool() {
+	pool := NewWorkerPool(WorkerPoolConfig{
+		Workers:    1,
+		Classes:    map[RequestPriority]WorkerClass{DebugRequestPriority: {QueueSize: 1}},
+		Priorities: map[string]RequestPriority{"/shard/1/rawapi_ro/Call": DebugRequestPriority},
+	})
+	s.Equal(CriticalRequestPriority, pool.priorityOf("/shard/1/rawapi_ro/GetBlockHeader"))
+	s.Equal(UserRequestPriority, pool.priorityOf("/shard/2/rawapi_ro/Call"))
+	s.Equal(DebugRequestPriority, pool.priorityOf("/shard/1/rawapi_ro/Call"))
+	s.Equal(DebugRequestPriority, pool.priorityOf("/shard/1/debugapi/TraceCall"))
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	started := make(chan string, 3)
+	release := make(chan struct{})
+	next := func(_ context.Context, request []byte) ([]byte, error) {
+		started <- string(request)
+		<-release
+		return request, nil
+	}
+	interceptor := pool.Interceptor()
+	trace := interceptor(ctx, "/shard/1/debugapi/TraceCall", next)
+	// The requests are queued before the workers run.
+	first := make(chan error, 1)
+	go func() {
+		_, err := trace(ctx, []byte("trace1"))
+		first <- err
+	}()
+	go func() {
+		s.NoError(pool.Run(ctx))
+	}()
+
+	// The first trace occupies the only worker, and the second one fills the queue of the traces.
+	s.Equal("trace1", <-started)
+	queued := func(priority RequestPriority, request string) *workerTask {
+		task := &workerTask{ctx: ctx, handler: next, request: []byte(request), result: make(chan workerResult, 1)}
+		s.Require().True(pool.submit(priority, task))
+		return task
+	}
+	trace2 := queued(DebugRequestPriority, "trace2")
+	_, err := trace(ctx, []byte("trace3"))
+	s.Require().ErrorIs(err, rawapitypes.ErrOverloaded)
+
+	// The header requested after the queued trace is served before it.
+	header := queued(CriticalRequestPriority, "header")
+	close(release)
+	s.Equal("header", <-started)
+	s.Equal("trace2", <-started)
+	s.Require().NoError(<-first)
+	s.Equal([]byte("header"), (<-header.result).response)
+	s.Equal([]byte("trace2"), (<-trace2.result).response)
+
+}
+
+func (s *ApiServerTestSuite) TestWorkerPoolStreams() {
+	// The pool isn't run, so the queued request keeps the queue of the class full.
+	pool := NewWorkerPool(WorkerPoolConfig{
+		Workers: 1,
+		Classes: map[RequestPriority]WorkerClass{UserRequestPriority: {QueueSize: 1}},
+	})
+	s.Require().True(pool.submit(UserRequestPriority, &workerTask{ctx: s.ctx, result: make(chan workerResult, 1)}))
+
+	// The streams wait for the workers as the other requests do.
+	open := makeStreamOpenHandler(s.ctx, "/shard/1/rawapi_ro/SubscribeLogs", []RequestInterceptor{pool.Interceptor()})
+	err := openStream(s.ctx, open, nil, func(context.Context, []byte) {
+		s.Fail("the stream is served")
+	})
+	s.Require().ErrorIs(err, rawapitypes.ErrOverloaded)
+}
+
+func (s *ApiServerTestSuite) TestLoadShedding() {
+	retryAfter := 50 * time.Millisecond
+	interceptor := NewLoadSheddingInterceptor(LoadSheddingConfig{
+		Thresholds: map[string]SheddingThresholds{
+			"Call":           {MaxInFlight: 1},
+			"GetBlockHeader": {MaxInFlight: 1},
+			"EstimateFee":    {MaxLatency: 10 * time.Millisecond},
+		},
+		RetryAfter: retryAfter,
+	})
+
+	started := make(chan struct{}, 3)
+	release := make(chan struct{})
+	blocking := func(_ context.Context, request []byte) ([]byte, error) {
+		started <- struct{}{}
+		<-release
+		return request, nil
+	}
+
+	s.Run("InFlight", func() {
+		call := interceptor(s.ctx, "/shard/1/rawapi_ro/Call", blocking)
+		header := interceptor(s.ctx, "/shard/1/rawapi_ro/GetBlockHeader", blocking)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, err := call(s.ctx, []byte("call1"))
+			s.NoError(err)
+		}()
+		<-started
+		_, err := call(s.ctx, []byte("call2"))
+		s.Require().ErrorIs(err, rawapitypes.ErrOverloaded)
+		hint, ok := rawapitypes.RetryAfter(err)
+		s.Require().True(ok)
+		s.Equal(retryAfter, hint)
+
+		// The critical requests are never shed.
+		headers := make(chan error, 2)
+		for _, request := range []string{"header1", "header2"} {
+			go func() {
+				_, err := header(s.ctx, []byte(request))
+				headers <- err
+			}()
+		}
+		<-started
+		<-started
+		close(release)
+		s.Require().NoError(<-headers)
+		s.Require().NoError(<-headers)
+		<-done
+
+		_, err = call(s.ctx, []byte("call3"))
+		s.NoError(err)
+	})
+
+	s.Run("Latency", func() {
+		slow := func(_ context.Context, request []byte) ([]byte, error) {
+			time.Sleep(20 * time.Millisecond)
+			return request, nil
+		}
+		estimate := interceptor(s.ctx, "/shard/1/rawapi_ro/EstimateFee", slow)
+
+		_, err := estimate(s.ctx, []byte("estimate1"))
+		s.Require().NoError(err)
+		_, err = estimate(s.ctx, []byte("estimate2"))
+		s.Require().ErrorIs(err, rawapitypes.ErrOverloaded)
+
+		// The latency isn't considered anymore once no requests have been handled for a while.
+		time.Sleep(2 * retryAfter)
+		_, err = estimate(s.ctx, []byte("estimate3"))
+		s.Require().NoError(err)
+	})
+}
+
+func (s *ApiServerTestSuite) TestShardPeerDirectory() {
+	s.serverNetworkManager.SetRequestHandler(s.ctx, servedShardsProtocol, makeGetServedShardsRequestHandler(
+		map[types.ShardId]servedShard{
+			types.MainShardId: {readOnly: true, archive: true},
+			types.BaseShardId: {readOnly: false},
+		}))
+	s.Require().Eventually(func() bool {
+		return len(s.clientNetworkManager.GetPeersForProtocol(servedShardsProtocol)) != 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	directory := NewShardPeerDirectory(s.clientNetworkManager, 3)
+	selectPeer := directory.PeerSelector(types.BaseShardId, true)
+
+	peerId, err := selectPeer([]network.PeerID{"unknown", s.serverPeerId})
+	s.Require().NoError(err)
+	s.Require().Equal(network.PeerID("unknown"), peerId)
+
+	s.Require().NoError(directory.Refresh(s.ctx))
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, directory.Peers(types.MainShardId, false))
+	s.Require().Empty(directory.Peers(types.MainShardId, true))
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, directory.Peers(types.BaseShardId, true))
+	s.Require().Empty(directory.Peers(types.ShardId(2), false))
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, directory.ArchivePeers(types.MainShardId))
+	s.Require().Empty(directory.ArchivePeers(types.BaseShardId))
+
+	peerId, err = selectPeer([]network.PeerID{"unknown", s.serverPeerId})
+	s.Require().NoError(err)
+	s.Require().Equal(s.serverPeerId, peerId)
+}
+
+func (s *ApiServerTestSuite) TestShardPeerDirectoryListing() {
+	setApiHandlers := func(apiName string) {
+		err := setRawApiRequestHandlers(
+			s.ctx,
+			reflect.TypeFor[testNetworkTransportProtocol](),
+			reflect.TypeFor[testApiIface](),
+			s.api,
+			types.BaseShardId,
+			apiName,
+			s.serverNetworkManager,
+			RequestHandlersConfig{},
+			s.logger)
+		s.Require().NoError(err)
+		listProtocol := makeProtocolId(types.BaseShardId, apiName, listSupportedMethodsMethodName)
+		s.Require().Eventually(func() bool {
+			return len(s.clientNetworkManager.GetPeersForProtocol(listProtocol)) != 0
+		}, 10*time.Second, 100*time.Millisecond)
+	}
+	directory := NewShardPeerDirectory(s.clientNetworkManager, 3)
+
+	// The peer not serving GetServedShards is described by the listing of its methods.
+	setApiHandlers(apiNameRo)
+	methods, err := ListSupportedMethods(s.ctx, s.clientNetworkManager, s.serverPeerId, types.BaseShardId)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(methods)
+	for _, method := range methods {
+		s.Require().Equal(apiNameRo, method.Api)
+		s.Require().True(method.ReadOnly)
+	}
+	s.Require().NoError(directory.Refresh(s.ctx))
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, directory.Peers(types.BaseShardId, false))
+	s.Require().Empty(directory.Peers(types.BaseShardId, true))
+	s.Require().Empty(directory.ArchivePeers(types.BaseShardId))
+	s.Require().Empty(directory.Peers(types.MainShardId, false))
+
+	setApiHandlers(apiNameRw)
+	s.Require().NoError(directory.Refresh(s.ctx))
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, directory.Peers(types.BaseShardId, true))
+}
+
+func (s *ApiServerTestSuite) TestServedShardsAnnouncement() {
+	directory := NewShardPeerDirectory(s.clientNetworkManager, 3)
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+	go directory.Run(ctx, time.Hour)
+
+	// The announcements are published until the subscription of the directory is propagated.
+	servedShards := map[types.ShardId]servedShard{types.BaseShardId: {readOnly: false}}
+	s.Require().Eventually(func() bool {
+		s.Require().NoError(publishServedShards(s.ctx, s.serverNetworkManager, servedShards))
+		return len(directory.Peers(types.BaseShardId, true)) != 0
+	}, 10*time.Second, 100*time.Millisecond)
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, directory.Peers(types.BaseShardId, true))
+
+	servedShards[types.BaseShardId] = servedShard{readOnly: true}
+	s.Require().Eventually(func() bool {
+		s.Require().NoError(publishServedShards(s.ctx, s.serverNetworkManager, servedShards))
+		return len(directory.Peers(types.BaseShardId, true)) == 0
+	}, 10*time.Second, 100*time.Millisecond)
+	s.Require().Equal([]network.PeerID{s.serverPeerId}, directory.Peers(types.BaseShardId, false))
+}
+
+func (s *ApiServerTestSuite) TestSetShardReadOnly() {
+	database, err := db.NewBadgerDbInMemory()
+	s.Require().NoError(err)
+	s.T().Cleanup(database.Close)
+	execution.GenerateZeroState(s.T(), types.MainShardId, database)
+	execution.GenerateZeroState(s.T(), types.BaseShardId, database)
+
+	shardId := types.BaseShardId
+	newShardApis := func() NodeApi {
+		pool, err := txnpool.New(s.ctx, txnpool.NewConfig(shardId), nil)
+		s.Require().NoError(err)
+		return NodeApiBuilder(database, nil).
+			WithLocalShardApiRo(shardId).
+			WithLocalShardApiRw(shardId, pool).
+			BuildAndReset()
+	}
+	server := newShardApis()
+	s.Require().NoError(server.SetP2pRequestHandlers(s.ctx, s.serverNetworkManager, RequestHandlersConfig{}, s.logger))
+	s.T().Cleanup(func() { server.UnsetP2pRequestHandlers(s.ctx) })
+	client := NodeApiBuilder(nil, s.clientNetworkManager).WithNetworkShardApiClientRw(shardId).BuildAndReset()
+
+	txn := execution.NewSendMoneyTransaction(s.T(), types.ShardAndHexToAddress(types.MainShardId, "0x1234"), 0)
+	encoded, err := (&types.ExternalTransaction{
+		Kind:                 types.ExecutionTransactionKind,
+		FeeCredit:            txn.FeeCredit,
+		To:                   txn.To,
+		ChainId:              txn.ChainId,
+		Seqno:                txn.Seqno,
+		Data:                 txn.Data,
+		AuthData:             txn.Signature,
+		MaxFeePerGas:         txn.MaxFeePerGas,
+		MaxPriorityFeePerGas: txn.MaxPriorityFeePerGas,
+	}).MarshalSSZ()
+	s.Require().NoError(err)
+	sendTransaction := func() error {
+		_, err := client.SendTransaction(s.ctx, shardId, encoded, txnpool.ReplaceIfFeeBumped, "")
+		return err
+	}
+	requireAccepted := func() {
+		s.T().Helper()
+		s.Require().Eventually(func() bool {
+			return sendTransaction() == nil
+		}, 10*time.Second, 100*time.Millisecond)
+	}
+
+	requireAccepted()
+
+	// The read-write protocols of the shard are removed, while the read-only ones are still served.
+	s.Require().NoError(server.SetShardReadOnly(shardId, true, nil))
+	s.Require().Error(sendTransaction())
+	s.Require().Error(server.SetShardReadOnly(types.ShardId(2), true, nil))
+
+	// The read-write API of the shard is served again.
+	s.Require().NoError(server.SetShardReadOnly(shardId, false, newShardApis()))
+	requireAccepted()
+}
+
+func TestApiServerResponses(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(ApiServerTestSuite))
+}
+
+type testSubscriptionApiIface interface {
+	Subscribe(ctx context.Context, blockReference rawapitypes.BlockReference) (<-chan sszx.SSZEncodedData, error)
+}
+
+type testSubscriptionApi struct {
+	events chan sszx.SSZEncodedData
+	err    error
+	calls  atomic.Int32
+}
+
+func (t *testSubscriptionApi) Subscribe(
+	ctx context.Context,
+	blockReference rawapitypes.BlockReference,
+) (<-chan sszx.SSZEncodedData, error) {
+	t.calls.Add(1)
+	return t.events, t.err
+}
+
+type testSubscriptionNetworkTransportProtocol interface {
+	Subscribe(pb.BlockRequest) pb.RawBlockResponse
+}
+
+type ApiSubscriptionTestSuite struct {
+	RawApiTestSuite
+
+	api   *testSubscriptionApi
+	codec apiCodec
+	// reject makes the interceptor of the server reject the subscriptions.
+	reject        atomic.Bool
+	accessControl *AccessControl
+}
+
+func (s *ApiSubscriptionTestSuite) SetupTest() {
+	s.RawApiTestSuite.SetupTest()
+
+	protocolInterfaceType := reflect.TypeFor[testSubscriptionNetworkTransportProtocol]()
+	apiInterfaceType := reflect.TypeFor[testSubscriptionApiIface]()
+	s.api = &testSubscriptionApi{events: make(chan sszx.SSZEncodedData)}
+	s.accessControl = NewAccessControl(nil)
+	err := setRawApiRequestHandlers(
+		s.ctx,
+		protocolInterfaceType,
+		apiInterfaceType,
+		s.api,
+		types.BaseShardId,
+		"testapi",
+		s.serverNetworkManager,
+		RequestHandlersConfig{
+			Interceptors: []RequestInterceptor{
+				func(_ context.Context, _ network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+					return func(ctx context.Context, request []byte) ([]byte, error) {
+						if s.reject.Load() {
+							return nil, rawapitypes.ErrUnauthorized
+						}
+						return next(ctx, request)
+					}
+				},
+				s.accessControl.Interceptor(),
+			},
+		},
+		s.logger)
+	s.Require().NoError(err)
+
+	s.codec, err = newApiCodec(apiInterfaceType, protocolInterfaceType)
+	s.Require().NoError(err)
+	s.Require().Equal(subscriptionResponse, s.codec["Subscribe"].kind)
+
+	s.Eventually(
+		func() bool {
+			return len(s.clientNetworkManager.GetPeersForProtocol("/shard/1/testapi/Subscribe")) != 0
+		},
+		10*time.Second,
+		100*time.Millisecond)
+}
+
+func (s *ApiSubscriptionTestSuite) subscribe(ctx context.Context) (<-chan sszx.SSZEncodedData, error) {
+	s.T().Helper()
+
+	return subscribe[sszx.SSZEncodedData](
+		ctx,
+		func(ctx context.Context, codec *methodCodec, args ...any) (<-chan []byte, error) {
+			return doNetworkShardApiSubscription(
+				ctx, s.clientNetworkManager, selectFirstPeer, types.BaseShardId, "testapi", codec, args...)
+		},
+		s.codec,
+		"Subscribe",
+		rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+}
+
+func (s *ApiSubscriptionTestSuite) TestEvents() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	events, err := s.subscribe(ctx)
+	s.Require().NoError(err)
+
+	for i := range types.TransactionIndex(3) {
+		s.api.events <- i.Bytes()
+		s.Require().Equal(sszx.SSZEncodedData(i.Bytes()), <-events)
+	}
+
+	close(s.api.events)
+	_, ok := <-events
+	s.Require().False(ok)
+}
+
+func (s *ApiSubscriptionTestSuite) TestError() {
+	s.api.err = errors.New("subscription failed")
+
+	_, err := s.subscribe(s.ctx)
+	s.Require().ErrorContains(err, "subscription failed")
+}
+
+func (s *ApiSubscriptionTestSuite) TestInterceptorRejection() {
+	s.reject.Store(true)
+
+	_, err := s.subscribe(s.ctx)
+	s.Require().ErrorIs(err, rawapitypes.ErrUnauthorized)
+	s.Require().Zero(s.api.calls.Load())
+
+	s.reject.Store(false)
+
+	_, err = s.subscribe(s.ctx)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, s.api.calls.Load())
+}
+
+func (s *ApiSubscriptionTestSuite) TestPeerAcl() {
+	s.accessControl.Set(PeerAcl{"Subscribe": {s.serverPeerId}})
+
+	_, err := s.subscribe(s.ctx)
+	s.Require().ErrorIs(err, rawapitypes.ErrUnauthorized)
+	s.Require().Zero(s.api.calls.Load())
+
+	s.accessControl.Set(PeerAcl{"/shard/1/testapi/Subscribe": {s.clientNetworkManager.ID()}})
+
+	_, err = s.subscribe(s.ctx)
+	s.Require().NoError(err)
+	s.Require().EqualValues(1, s.api.calls.Load())
+}
+
+func TestApiSubscription(t *testing.T) {
+	t.Parallel()
+
+	suite.Run(t, new(ApiSubscriptionTestSuite))
 }
@@ -11,52 +11,154 @@ import (
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/google/uuid"
 )
 
 type shardApiBase interface {
 	shardId() types.ShardId
 	setNodeApi(nodeApi NodeApi)
-	setAsP2pRequestHandlersIfAllowed(ctx context.Context, networkManager network.Manager, logger logging.Logger) error
+	setAsP2pRequestHandlersIfAllowed(
+		ctx context.Context,
+		networkManager network.Manager,
+		cfg RequestHandlersConfig,
+		logger logging.Logger,
+	) error
 }
 
 const apiNameRo = "rawapi_ro"
 
 type shardApiRo interface {
 	shardApiBase
+	ShardApiRo
+}
 
+// ShardApiRo is the read-only raw API of a shard.
+type ShardApiRo interface {
 	GetBlockHeader(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error)
 	GetFullBlockData(
 		ctx context.Context, blockReference rawapitypes.BlockReference) (*types.RawBlockWithExtractedData, error)
 	GetBlockTransactionCount(ctx context.Context, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetBlockRange(
+		ctx context.Context,
+		from types.BlockNumber,
+		count uint64,
+		fullBlocks bool,
+	) ([]*types.RawBlockWithExtractedData, error)
+	GetShardStats(ctx context.Context, from types.BlockNumber, count uint64) (*rawapitypes.ShardStats, error)
+	GetBlockFinalitySignatures(
+		ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.FinalitySignatures, error)
+	GetValidators(ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.ValidatorSet, error)
+	GetValidatorInfo(ctx context.Context, publicKey []byte) (*rawapitypes.ValidatorInfo, error)
+	GetEpochInfo(ctx context.Context) (*rawapitypes.EpochInfo, error)
+	SubscribeNewHeads(ctx context.Context) (<-chan sszx.SSZEncodedData, error)
 
 	GetInTransaction(
 		ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
-	GetInTransactionReceipt(ctx context.Context, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetInTransactionByIndex(
+		ctx context.Context,
+		blockReference rawapitypes.BlockReference,
+		index types.TransactionIndex,
+	) (*rawapitypes.TransactionInfo, error)
+	GetReceipt(ctx context.Context, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetBounceInfo(ctx context.Context, hash common.Hash) (*rawapitypes.BounceInfo, error)
+	GetBlockReceipts(
+		ctx context.Context, blockReference rawapitypes.BlockReference) ([]*rawapitypes.ReceiptInfo, error)
+	GetOutTransaction(
+		ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
+	GetOutTransactions(
+		ctx context.Context, blockReference rawapitypes.BlockReference) ([]*rawapitypes.TransactionInfo, error)
+
+	GetLogs(ctx context.Context, filter rawapitypes.LogFilter) ([]*rawapitypes.LogInfo, error)
+	SubscribeLogs(ctx context.Context, filter rawapitypes.LogFilter) (<-chan *rawapitypes.LogInfo, error)
 
 	GetBalance(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
+	GetBalances(
+		ctx context.Context, addresses []types.Address, blockReference rawapitypes.BlockReference) ([]types.Value, error)
 	GetCode(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error)
+	GetStorageAt(
+		ctx context.Context,
+		address types.Address,
+		key common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (types.Uint256, error)
+	GetStorageRange(
+		ctx context.Context,
+		address types.Address,
+		start common.Hash,
+		limit uint64,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.StorageRange, error)
 	GetTokens(
 		ctx context.Context,
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
-	) (map[types.TokenId]types.Value, error)
+		request rawapitypes.TokensRequest,
+	) (*rawapitypes.TokensPage, error)
+	GetTokenBalance(
+		ctx context.Context,
+		address types.Address,
+		tokenId types.TokenId,
+		blockReference rawapitypes.BlockReference,
+	) (types.Value, error)
+	GetTokenInfo(
+		ctx context.Context, tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference,
+	) (*rawapitypes.TokenInfo, error)
+	ListTokensCreatedBy(
+		ctx context.Context, address types.Address, mainBlockReference rawapitypes.BlockReference,
+	) ([]*rawapitypes.TokenInfo, error)
 	GetContract(
 		ctx context.Context,
 		address types.Address,
 		blockReference rawapitypes.BlockReference,
 	) (*rawapitypes.SmartContract, error)
+	GetAccountMeta(
+		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.AccountMeta, error)
+	GetProof(
+		ctx context.Context,
+		address types.Address,
+		storageKeys []common.Hash,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.ContractProof, error)
 
 	Call(
 		ctx context.Context,
 		args rpctypes.CallArgs,
 		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
 		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
 	) (*rpctypes.CallResWithGasPrice, error)
+	EstimateFee(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.FeeEstimation, error)
+	CreateAccessList(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.AccessListResult, error)
+	SimulateBundle(
+		ctx context.Context,
+		calls []rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) ([]*rawapitypes.BundleCallResult, error)
+	PrepareDeploy(ctx context.Context, request rawapitypes.DeployRequest) (*rawapitypes.DeployPreparation, error)
 
 	GasPrice(ctx context.Context) (types.Value, error)
 	GetShardIdList(ctx context.Context) ([]types.ShardId, error)
 	GetNumShards(ctx context.Context) (uint64, error)
+	ResolveShard(ctx context.Context, address types.Address) (types.ShardId, error)
+	ResolveShards(ctx context.Context, addresses []types.Address) ([]*rawapitypes.ShardResolution, error)
+	GetChainConfig(ctx context.Context) (*rawapitypes.ChainConfig, error)
+	GetSyncStatus(ctx context.Context) (*rawapitypes.SyncStatus, error)
 
 	ClientVersion(ctx context.Context) (string, error)
 }
@@ -65,13 +167,43 @@ const apiNameRw = "rawapi_rw"
 
 type shardApiRw interface {
 	shardApiBase
+	ShardApiRw
+}
 
-	SendTransaction(ctx context.Context, transaction []byte) (txnpool.DiscardReason, error)
+// ShardApiRw is the raw API of a shard sending the transactions and serving its pool.
+type ShardApiRw interface {
+	SendTransaction(
+		ctx context.Context,
+		transaction []byte,
+		replacement txnpool.ReplacementPolicy,
+		idempotencyKey string,
+	) (*rawapitypes.SendTransactionResult, error)
+	SendTransactionBundle(
+		ctx context.Context, transactions [][]byte, sameBlock bool) ([]*rawapitypes.SendTransactionResult, error)
+	SendTransactionAndWatch(
+		ctx context.Context,
+		transaction []byte,
+		replacement txnpool.ReplacementPolicy,
+		idempotencyKey string,
+	) (<-chan *rawapitypes.TransactionEvent, error)
 	GetTransactionCount(
 		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetNextValidSeqno(
+		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetTransactionStatus(ctx context.Context, hash common.Hash) (*rawapitypes.TransactionStatusInfo, error)
+	ResendTransaction(
+		ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy) (txnpool.DiscardReason, error)
 
 	GetTxpoolStatus(ctx context.Context) (uint64, error)
 	GetTxpoolContent(ctx context.Context) ([]*types.Transaction, error)
+	SubscribePendingTransactions(
+		ctx context.Context, fullTransactions bool) (<-chan *rawapitypes.PendingTransaction, error)
+}
+
+// ShardApi is the read-only and the read-write raw APIs of a shard.
+type ShardApi interface {
+	ShardApiRo
+	ShardApiRw
 }
 
 const apiNameDev = "rawapi_dev"
@@ -81,3 +213,216 @@ type shardApiDev interface {
 
 	DoPanicOnShard(ctx context.Context) (uint64, error)
 }
+
+const apiNameDebug = "debugapi"
+
+type shardApiDebug interface {
+	shardApiBase
+
+	TraceTransaction(ctx context.Context, hash common.Hash) (*rawapitypes.ExecutionTrace, error)
+	TraceCall(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.ExecutionTrace, error)
+	TraceTransactionStateDiff(ctx context.Context, hash common.Hash) (*rawapitypes.StateDiff, error)
+	TraceCallStateDiff(
+		ctx context.Context,
+		args rpctypes.CallArgs,
+		mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+		overrides *rpctypes.StateOverrides,
+		blockOverrides *rpctypes.BlockOverrides,
+	) (*rawapitypes.StateDiff, error)
+	ReplayTransaction(
+		ctx context.Context, hash common.Hash, options rawapitypes.ReplayOptions) (*rawapitypes.ReplayResult, error)
+	GetPendingResponsesFor(
+		ctx context.Context,
+		address types.Address,
+		start types.TransactionIndex,
+		limit uint64,
+		blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.PendingResponses, error)
+	GetAwaitingContext(ctx context.Context, hash common.Hash) (*rawapitypes.AwaitingContext, error)
+}
+
+const apiNameTxpool = "txpoolapi"
+
+type shardApiTxpool interface {
+	shardApiBase
+
+	GetPoolContent(ctx context.Context) (*rawapitypes.PoolContent, error)
+	GetPoolStatus(ctx context.Context) (*rawapitypes.PoolStatus, error)
+	GetPoolTransactionsByAccount(ctx context.Context, address types.Address) (*rawapitypes.PoolContent, error)
+}
+
+const apiNameSync = "syncapi"
+
+// shardApiSync serves the snapshots of the state of the shard, so that a new node can bootstrap it
+// from the peers instead of replaying all the blocks, and the blocks following it.
+type shardApiSync interface {
+	shardApiBase
+
+	GetSnapshotManifest(
+		ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.SnapshotManifest, error)
+	GetSnapshotChunk(
+		ctx context.Context, request rawapitypes.SnapshotChunkRequest) (*rawapitypes.SnapshotChunk, error)
+	GetBlocksSince(
+		ctx context.Context, sequence types.BlockNumber, limit uint64) ([]*types.RawBlockWithExtractedData, error)
+}
+
+const apiNameProof = "proofapi"
+
+type shardApiProof interface {
+	shardApiBase
+	ProofApi
+}
+
+// ProofApi serves the zk proofs of the main shard blocks, so that the rollup verifiers and the bridge operators
+// can fetch them from the nodes of the network. The API is served under the main shard only.
+type ProofApi interface {
+	// GetStateProofForBlock returns the proof of the state transition made by the main shard block.
+	GetStateProofForBlock(
+		ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.StateProof, error)
+	// GetProofTaskStatus returns the state of the task generating a proof.
+	GetProofTaskStatus(ctx context.Context, id uuid.UUID) (*rawapitypes.ProofTaskInfo, error)
+}
+
+const apiNameAdmin = "adminapi"
+
+type shardApiAdmin interface {
+	shardApiBase
+	AdminApi
+}
+
+// AdminApi lets the operators manage the node remotely. The methods act on the node as a whole,
+// the API is served under the main shard only to be routed like the rest of the raw API.
+type AdminApi interface {
+	// SetLogLevel sets the global log level of the node and returns the previous one.
+	SetLogLevel(ctx context.Context, level string) (string, error)
+	// DisconnectPeer closes the connections of the node to the peer and returns their number.
+	DisconnectPeer(ctx context.Context, peerId network.PeerID) (uint64, error)
+	// AddPeer makes the node remember and connect to the peer and returns the number of the open connections to it.
+	AddPeer(ctx context.Context, addr network.AddrInfo) (uint64, error)
+	// RemovePeer makes the node forget and disconnect from the peer and returns the number of the closed connections.
+	RemovePeer(ctx context.Context, peerId network.PeerID) (uint64, error)
+	// ProtectPeer protects the connections of the node to the peer from being trimmed under the tag
+	// or removes the protection. It returns whether the peer stays protected under any tag.
+	ProtectPeer(ctx context.Context, peerId network.PeerID, tag string, protect bool) (bool, error)
+	// SetPeerTag sets the value of the tag the node trims the connections by and returns the previous value.
+	SetPeerTag(ctx context.Context, peerId network.PeerID, tag string, value int64) (int64, error)
+	// FlushCaches drops the caches of the local APIs of the node and returns the number of the dropped entries.
+	FlushCaches(ctx context.Context) (uint64, error)
+	// TriggerSnapshot starts the backup of the database of the node and returns the path of the backup file.
+	TriggerSnapshot(ctx context.Context) (string, error)
+	// SetPeerAcl replaces the ACL of the raw API of the node and returns the number of its entries.
+	SetPeerAcl(ctx context.Context, acl rawapitypes.PeerAcl) (uint64, error)
+	// GetPeerAcl returns the ACL of the raw API of the node.
+	GetPeerAcl(ctx context.Context) (rawapitypes.PeerAcl, error)
+	// GetPeerUsage returns the usage of the raw API of the node by the peers accounted by their quotas.
+	GetPeerUsage(ctx context.Context) ([]rawapitypes.PeerUsage, error)
+	// GetPeers returns the peers the node is connected to with their streams and traffic.
+	GetPeers(ctx context.Context) ([]network.PeerInfo, error)
+	// GetPeerInfo returns the connections of the node to the peer.
+	GetPeerInfo(ctx context.Context, peerId network.PeerID) (*network.PeerInfo, error)
+	// GetBandwidthByProtocol returns the traffic of the node by protocol, e.g., by the method of the raw API.
+	GetBandwidthByProtocol(ctx context.Context) (map[network.ProtocolID]network.BandwidthStats, error)
+}
+
+const apiNameDb = "dbapi"
+
+type shardApiDb interface {
+	shardApiBase
+	DbApi
+}
+
+// DbApi lets the operators read the database of the node remotely to debug it. The keys and the values
+// are the raw ones, the tables of the shards are named as db.ShardTableName does.
+type DbApi interface {
+	// GetRaw returns the value of the key in the table.
+	GetRaw(ctx context.Context, table string, key []byte) ([]byte, error)
+	// ListKeys returns up to limit keys of the table starting with the prefix in the ascending order.
+	// The keys not greater than after are skipped, so the next page starts after the last key of the previous one.
+	ListKeys(ctx context.Context, table string, prefix []byte, after []byte, limit uint64) ([][]byte, error)
+}
+
+const apiNameConsensus = "consensusapi"
+
+type shardApiConsensus interface {
+	shardApiBase
+	ConsensusApi
+}
+
+// ConsensusApi lets the operators diagnose the liveness of the consensus of the shards the node validates,
+// e.g., the rounds stuck on a missing proposer or votes. The API is served under the main shard.
+type ConsensusApi interface {
+	// GetConsensusState returns the state of the round of the consensus of the shard and the votes received in it.
+	GetConsensusState(ctx context.Context, shardId types.ShardId) (*rawapitypes.ConsensusState, error)
+	// GetRoundTimeouts returns the latest expirations of the rounds of the shard, the oldest first.
+	GetRoundTimeouts(ctx context.Context, shardId types.ShardId) ([]rawapitypes.RoundTimeout, error)
+}
+
+const apiNameCluster = "clusterapi"
+
+type shardApiCluster interface {
+	shardApiBase
+	ClusterApi
+}
+
+// ClusterApi answers the queries about the whole network in a single request, the node queries all the shards
+// on behalf of the caller. The API is served under the main shard only.
+type ClusterApi interface {
+	// GetFamilyTransactionCount returns the numbers of the transactions sent by the accounts of the address family
+	// of the address in every shard. The block reference is resolved in each shard, so it can't be a hash.
+	GetFamilyTransactionCount(
+		ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference,
+	) (*rawapitypes.FamilyTransactionCount, error)
+	// GetLatestBlocks returns the latest blocks of all the shards sorted by shard ID.
+	GetLatestBlocks(ctx context.Context) ([]*rawapitypes.ShardBlockHead, error)
+	// GetGasPriceStats returns the gas prices of all the shards together with their statistics.
+	GetGasPriceStats(ctx context.Context) (*rawapitypes.GasPriceStats, error)
+}
+
+const apiNameFaucet = "faucetapi"
+
+type shardApiFaucet interface {
+	shardApiBase
+	FaucetApi
+}
+
+// FaucetApi tops up the accounts of a devnet from its faucet, so that the local and test networks can be funded
+// programmatically without a separate service. The API is served under the main shard only, by the nodes
+// of the dev configurations.
+type FaucetApi interface {
+	// RequestFunds sends the amount from the faucet to the address and returns the result of sending the transaction.
+	// The amount is only counted towards the quota of the address if the transaction is admitted to the pool.
+	RequestFunds(
+		ctx context.Context, address types.Address, amount types.Value) (*rawapitypes.SendTransactionResult, error)
+	// GetFaucetLimits returns the limits of the amounts the faucet sends.
+	GetFaucetLimits(ctx context.Context) (*rawapitypes.FaucetLimits, error)
+}
+
+const apiNameDevnet = "devapi"
+
+type shardApiDevnet interface {
+	shardApiBase
+	DevnetApi
+}
+
+// DevnetApi controls the collation of the blocks of a single-node devnet, as the local development
+// networks of the other chains do. The API is served under the main shard only, by the nodes collating
+// without the consensus, to the authenticated operators. The controls apply to all the shards of the node.
+type DevnetApi interface {
+	// MineBlock collates count blocks in every shard, the main shard last, and returns the number of the last block
+	// of the main shard. The blocks are collated regardless of the pending transactions.
+	MineBlock(ctx context.Context, count uint64) (uint64, error)
+	// SetNextBlockTimestamp makes the transactions of the next block see the timestamp, the following blocks advance
+	// from it. The timestamp can't be before the one the next block would have. It returns the timestamp set.
+	SetNextBlockTimestamp(ctx context.Context, timestamp uint64) (uint64, error)
+	// SnapshotState saves the chains of the shards and returns the ID of the snapshot.
+	SnapshotState(ctx context.Context) (uint64, error)
+	// RevertToSnapshot resets the chains of the shards to the snapshot, dropping the blocks collated after it.
+	// The snapshot and the ones taken after it are discarded. It returns false if there is no such snapshot.
+	RevertToSnapshot(ctx context.Context, id uint64) (bool, error)
+}
@@ -0,0 +1,208 @@
+package internal
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/contracts"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// maxSignatureAge limits the time a signed request is accepted for, in both directions to tolerate the skew
+// of the clocks. The signed requests are remembered by the server for the time, so they can't be replayed.
+const maxSignatureAge = time.Minute
+
+const (
+	// maxSignedRequests bounds the number of the signed requests remembered by the server.
+	// The oldest ones are forgotten first under a flood, so the methods relying on the signer
+	// should still be idempotent by signer, e.g., a faucet claim.
+	maxSignedRequests = 100_000
+
+	requestNonceSize = 16
+)
+
+// requestSigningDomain separates the signatures of the requests from the signatures of the transactions.
+var requestSigningDomain = []byte("nil raw API request")
+
+// RequestSigner is the account that signed a raw API request, see WithRequestSigningKey.
+// The address is checked to be the one of the default smart account of the key deployed with the salt
+// of the signature, so the signer controls the account as long as its code is not replaced.
+type RequestSigner struct {
+	Address types.Address
+	// PublicKey is the compressed public key recovered from the signature.
+	PublicKey []byte
+}
+
+type requestSigningKey struct {
+	address types.Address
+	salt    common.Hash
+	key     *ecdsa.PrivateKey
+}
+
+type (
+	// signingKeyKey holds the key the client signs the requests with.
+	signingKeyKey struct{}
+	// requestSignerKey holds the signer of the request received by the server. It is kept apart from the key
+	// the client signs with, so the calls made while handling a request aren't signed on behalf of the caller.
+	requestSignerKey struct{}
+)
+
+// WithRequestSigningKey makes the raw API requests sent with the context signed by the key on behalf
+// of the smart account deployed with the salt, so that the methods can authorize the account regardless
+// of the transport of the network.
+func WithRequestSigningKey(
+	ctx context.Context, address types.Address, salt common.Hash, key *ecdsa.PrivateKey,
+) context.Context {
+	return context.WithValue(ctx, signingKeyKey{}, requestSigningKey{address: address, salt: salt, key: key})
+}
+
+// GetRequestSigner returns the account that signed the request being handled, false if it isn't signed.
+func GetRequestSigner(ctx context.Context) (RequestSigner, bool) {
+	signer, ok := ctx.Value(requestSignerKey{}).(RequestSigner)
+	return signer, ok
+}
+
+// requesterKey identifies the requester of the request being handled, either the signer or the peer,
+// e.g., to count the quotas of the requesters. The calls made by the node itself share a single key.
+func requesterKey(ctx context.Context) string {
+	if signer, ok := GetRequestSigner(ctx); ok {
+		return "signer/" + signer.Address.Hex()
+	}
+	if peerId, ok := network.RequestPeer(ctx); ok {
+		return "peer/" + peerId.String()
+	}
+	return "local"
+}
+
+// smartAccountAddress returns the address of the default smart account of the key deployed with the salt.
+func smartAccountAddress(shardId types.ShardId, salt common.Hash, publicKey []byte) types.Address {
+	code := contracts.PrepareDefaultSmartAccountForOwnerCode(publicKey)
+	return types.CreateAddress(shardId, types.BuildDeployPayload(code, salt))
+}
+
+// unversionedProtocolId strips the version segment from the ID of a method, see makeVersionedProtocolId.
+// The IDs without the segment are returned as is.
+func unversionedProtocolId(protocol network.ProtocolID) network.ProtocolID {
+	segments := strings.Split(string(protocol), "/")
+	if len(segments) != 6 {
+		return protocol
+	}
+	return network.ProtocolID(strings.Join(append(segments[:4:4], segments[5]), "/"))
+}
+
+// requestSigningHash binds the signature to the shard, the API and the method of the protocol, the account,
+// the time of signing and the nonce, so that it can't be reused for another request.
+// The protocol has no version segment, so the version negotiated by the client doesn't matter.
+func requestSigningHash(protocol network.ProtocolID, signature *pb.RequestSignature, payload []byte) []byte {
+	return crypto.Keccak256(
+		requestSigningDomain,
+		[]byte(protocol),
+		signature.GetAddress(),
+		signature.GetSalt(),
+		binary.BigEndian.AppendUint64(nil, uint64(signature.GetSignedAt())),
+		signature.GetNonce(),
+		crypto.Keccak256(payload))
+}
+
+// signRequestEnvelope signs the request by the key of the context, if any, see WithRequestSigningKey.
+func signRequestEnvelope(ctx context.Context, protocol network.ProtocolID, envelope *pb.RequestEnvelope) error {
+	signingKey, ok := ctx.Value(signingKeyKey{}).(requestSigningKey)
+	if !ok {
+		return nil
+	}
+	nonce := make([]byte, requestNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate request nonce: %w", err)
+	}
+	signature := &pb.RequestSignature{
+		Address:  signingKey.address.Bytes(),
+		Salt:     signingKey.salt.Bytes(),
+		SignedAt: time.Now().UnixMilli(),
+		Nonce:    nonce,
+	}
+	hash := requestSigningHash(unversionedProtocolId(protocol), signature, envelope.GetPayload())
+	signed, err := crypto.Sign(hash, signingKey.key)
+	if err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+	signature.Signature = signed
+	envelope.Signature = signature
+	return nil
+}
+
+// signedRequests remembers the requests signed within maxSignatureAge, so that each of them is handled once.
+type signedRequests struct {
+	mu     sync.Mutex
+	hashes *expirable.LRU[string, struct{}]
+}
+
+func newSignedRequests() *signedRequests {
+	// The signatures are accepted for maxSignatureAge before and after the time of the server.
+	return &signedRequests{hashes: expirable.NewLRU[string, struct{}](maxSignedRequests, nil, 2*maxSignatureAge)}
+}
+
+// verify returns the signer of the request to the protocol, which has no version segment.
+// The requests with invalid, expired or replayed signatures are rejected with rawapitypes.ErrUnauthorized
+// instead of being handled as unsigned.
+func (r *signedRequests) verify(
+	protocol network.ProtocolID, payload []byte, signature *pb.RequestSignature, now time.Time,
+) (RequestSigner, error) {
+	signer, err := r.recoverSigner(protocol, payload, signature, now)
+	if err != nil {
+		return RequestSigner{}, rawapitypes.NewError(
+			rawapitypes.UnauthorizedErrorCode, fmt.Errorf("%w: %w", rawapitypes.ErrUnauthorized, err))
+	}
+	return signer, nil
+}
+
+func (r *signedRequests) recoverSigner(
+	protocol network.ProtocolID, payload []byte, signature *pb.RequestSignature, now time.Time,
+) (RequestSigner, error) {
+	if len(signature.GetAddress()) != types.AddrSize {
+		return RequestSigner{}, errors.New("invalid signer address")
+	}
+	if len(signature.GetSalt()) != common.HashSize {
+		return RequestSigner{}, errors.New("invalid signer salt")
+	}
+	if len(signature.GetNonce()) != requestNonceSize {
+		return RequestSigner{}, errors.New("invalid request nonce")
+	}
+	if age := now.Sub(time.UnixMilli(signature.GetSignedAt())).Abs(); age > maxSignatureAge {
+		return RequestSigner{}, fmt.Errorf("request is signed %s away from now", age.Round(time.Second))
+	}
+
+	hash := requestSigningHash(protocol, signature, payload)
+	publicKey, err := crypto.SigToPub(hash, signature.GetSignature())
+	if err != nil {
+		return RequestSigner{}, fmt.Errorf("invalid request signature: %w", err)
+	}
+	signer := RequestSigner{
+		Address:   types.BytesToAddress(signature.GetAddress()),
+		PublicKey: crypto.CompressPubkey(publicKey),
+	}
+	salt := common.BytesToHash(signature.GetSalt())
+	if smartAccountAddress(signer.Address.ShardId(), salt, signer.PublicKey) != signer.Address {
+		return RequestSigner{}, fmt.Errorf("%s is not the smart account of the signing key", signer.Address)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hashes.Contains(string(hash)) {
+		return RequestSigner{}, errors.New("request is replayed")
+	}
+	r.hashes.Add(string(hash), struct{}{})
+	return signer, nil
+}
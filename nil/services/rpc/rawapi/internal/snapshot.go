@@ -0,0 +1,332 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/mpt"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+var errSnapshotMismatch = errors.New("snapshot doesn't match the block")
+
+// FetchSnapshot bootstraps the state of all the shards from the sync API of the node, e.g., served by the peers.
+// The main shard is fetched at the block, which is trusted, and the other shards are fetched at the child blocks
+// of the block, so that the snapshots of the shards are consistent. The headers of the genesis block and
+// of the main shard blocks the child blocks refer to are imported as well, so that the following blocks
+// of the shards are replayed as usual.
+func FetchSnapshot(
+	ctx context.Context,
+	nodeApi NodeApi,
+	database db.DB,
+	mainBlockReference rawapitypes.BlockReference,
+) error {
+	manifest, err := FetchShardSnapshot(ctx, nodeApi, database, types.MainShardId, mainBlockReference)
+	if err != nil {
+		return fmt.Errorf("failed to fetch snapshot of the main shard: %w", err)
+	}
+	if err := importBlockHeader(
+		ctx, nodeApi, database, rawapitypes.BlockNumberAsBlockReference(0), common.EmptyHash,
+	); err != nil {
+		return fmt.Errorf("failed to import genesis block: %w", err)
+	}
+	for i, childBlock := range manifest.ChildBlocks {
+		// the main shard is omitted
+		shardId := types.ShardId(i + 1)
+		childManifest, err := FetchShardSnapshot(
+			ctx, nodeApi, database, shardId, rawapitypes.BlockHashAsBlockReference(childBlock))
+		if err != nil {
+			return fmt.Errorf("failed to fetch snapshot of shard %d: %w", shardId, err)
+		}
+		childBlockData := new(types.Block)
+		if err := childBlockData.UnmarshalSSZ(childManifest.BlockSSZ); err != nil {
+			return err
+		}
+		mainShardHash := childBlockData.MainShardHash
+		if err := importBlockHeader(
+			ctx, nodeApi, database, rawapitypes.BlockHashAsBlockReference(mainShardHash), mainShardHash,
+		); err != nil {
+			return fmt.Errorf("failed to import main shard block of shard %d: %w", shardId, err)
+		}
+	}
+	return nil
+}
+
+// importBlockHeader writes the header of the main shard block, which doesn't change the last block of the shard.
+// The hash is checked if it is not empty. The blocks by number are also indexed, e.g., the genesis block.
+func importBlockHeader(
+	ctx context.Context,
+	nodeApi NodeApi,
+	database db.DB,
+	blockReference rawapitypes.BlockReference,
+	blockHash common.Hash,
+) error {
+	blockSSZ, err := nodeApi.GetBlockHeader(ctx, types.MainShardId, blockReference)
+	if err != nil {
+		return err
+	}
+	block := new(types.Block)
+	if err := block.UnmarshalSSZ(blockSSZ); err != nil {
+		return err
+	}
+	hash := block.Hash(types.MainShardId)
+	if !blockHash.Empty() && hash != blockHash {
+		return fmt.Errorf("%w: block hash %s != %s", errSnapshotMismatch, hash, blockHash)
+	}
+	isNumber := blockReference.Type() == rawapitypes.NumberBlockReference
+	if isNumber && blockReference.Number() != rawapitypes.BlockNumber(block.Id) {
+		return fmt.Errorf("%w: block number %d != %d", errSnapshotMismatch, block.Id, blockReference.Number())
+	}
+
+	tx, err := database.CreateRwTx(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := db.ReadBlock(tx, types.MainShardId, hash); err == nil {
+		return nil
+	} else if !errors.Is(err, db.ErrKeyNotFound) {
+		return err
+	}
+	if err := db.WriteBlock(tx, types.MainShardId, hash, block); err != nil {
+		return err
+	}
+	if isNumber {
+		err := tx.PutToShard(types.MainShardId, db.BlockHashByNumberIndex, block.Id.Bytes(), hash.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// FetchShardSnapshot bootstraps the state of the shard at the block from the sync API of the node,
+// e.g., served by the peers, instead of replaying all the blocks, and returns the manifest of the snapshot.
+// The contracts are imported by chunks, and the block becomes the last block of the shard once the state
+// is verified against it.
+// The block of a reference by hash or by number is checked to be the referenced one, the named blocks
+// are trusted to the node.
+func FetchShardSnapshot(
+	ctx context.Context,
+	nodeApi NodeApi,
+	database db.DB,
+	shardId types.ShardId,
+	blockReference rawapitypes.BlockReference,
+) (*rawapitypes.SnapshotManifest, error) {
+	manifest, err := nodeApi.GetSnapshotManifest(ctx, shardId, blockReference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot manifest: %w", err)
+	}
+	block := new(types.Block)
+	if err := block.UnmarshalSSZ(manifest.BlockSSZ); err != nil {
+		return nil, err
+	}
+	if blockHash := block.Hash(shardId); blockHash != manifest.BlockHash {
+		return nil, fmt.Errorf("%w: block hash %s != %s", errSnapshotMismatch, blockHash, manifest.BlockHash)
+	}
+	switch blockReference.Type() {
+	case rawapitypes.HashBlockReference:
+		if manifest.BlockHash != blockReference.Hash() {
+			return nil, fmt.Errorf(
+				"%w: block hash %s != %s", errSnapshotMismatch, manifest.BlockHash, blockReference.Hash())
+		}
+	case rawapitypes.NumberBlockReference:
+		if rawapitypes.BlockNumber(block.Id) != blockReference.Number() {
+			return nil, fmt.Errorf(
+				"%w: block number %d != %d", errSnapshotMismatch, block.Id, blockReference.Number())
+		}
+	case rawapitypes.NamedBlockIdentifierReference:
+	}
+
+	// The chunks are committed separately to keep the transactions small, the tries only become reachable
+	// once the last block is written.
+	contractsRoot := common.EmptyHash
+	request := rawapitypes.SnapshotChunkRequest{BlockHash: manifest.BlockHash}
+	for {
+		chunk, err := nodeApi.GetSnapshotChunk(ctx, shardId, request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get snapshot chunk starting at %s: %w", request.Start, err)
+		}
+		contractsRoot, err = importSnapshotChunk(ctx, database, shardId, contractsRoot, chunk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import snapshot chunk starting at %s: %w", request.Start, err)
+		}
+		if chunk.Next == nil {
+			break
+		}
+		// The chunks are fetched in the order of the keys, so the snapshot is fetched in a finite number of them.
+		if bytes.Compare(chunk.Next.Bytes(), request.Start.Bytes()) <= 0 {
+			return nil, fmt.Errorf(
+				"%w: next chunk starts at %s, not after %s", errSnapshotMismatch, chunk.Next, request.Start)
+		}
+		request.Start = *chunk.Next
+	}
+	if contractsRoot != block.SmartContractsRoot {
+		return nil, fmt.Errorf(
+			"%w: smart contracts root %s != %s", errSnapshotMismatch, contractsRoot, block.SmartContractsRoot)
+	}
+
+	tx, err := database.CreateRwTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := importSnapshotBlockData(tx, shardId, block, manifest); err != nil {
+		return nil, err
+	}
+	if err := db.WriteBlock(tx, shardId, manifest.BlockHash, block); err != nil {
+		return nil, err
+	}
+	err = tx.PutToShard(shardId, db.BlockHashByNumberIndex, block.Id.Bytes(), manifest.BlockHash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if err := db.WriteLastBlockHash(tx, shardId, manifest.BlockHash); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// importSnapshotChunk writes the contracts of the chunk to the contract trie with the given root
+// and returns the new root.
+func importSnapshotChunk(
+	ctx context.Context,
+	database db.DB,
+	shardId types.ShardId,
+	contractsRoot common.Hash,
+	chunk *rawapitypes.SnapshotChunk,
+) (common.Hash, error) {
+	tx, err := database.CreateRwTx(ctx)
+	if err != nil {
+		return common.EmptyHash, fmt.Errorf("failed to create transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	keys := make([]common.Hash, 0, len(chunk.Contracts))
+	contracts := make([]*types.SmartContract, 0, len(chunk.Contracts))
+	for _, contractData := range chunk.Contracts {
+		contract, err := importSnapshotContract(tx, shardId, contractData)
+		if err != nil {
+			return common.EmptyHash, err
+		}
+		keys = append(keys, contract.Address.Hash())
+		contracts = append(contracts, contract)
+	}
+
+	contractTrie := execution.NewDbContractTrie(tx, shardId)
+	if contractsRoot != common.EmptyHash {
+		contractTrie.SetRootHash(contractsRoot)
+	}
+	if err := contractTrie.UpdateBatch(keys, contracts); err != nil {
+		return common.EmptyHash, err
+	}
+	if err := tx.Commit(); err != nil {
+		return common.EmptyHash, err
+	}
+	return contractTrie.RootHash(), nil
+}
+
+// importSnapshotContract writes the code and the tries of the contract and checks them against its roots.
+func importSnapshotContract(
+	tx db.RwTx,
+	shardId types.ShardId,
+	contractData *rawapitypes.SmartContract,
+) (*types.SmartContract, error) {
+	contract := new(types.SmartContract)
+	if err := contract.UnmarshalSSZ(contractData.ContractSSZ); err != nil {
+		return nil, err
+	}
+
+	if len(contractData.Code) != 0 {
+		if codeHash := contractData.Code.Hash(); codeHash != contract.CodeHash {
+			return nil, fmt.Errorf("%w: code hash of %s %s != %s",
+				errSnapshotMismatch, contract.Address, codeHash, contract.CodeHash)
+		}
+		if err := db.WriteCode(tx, shardId, contract.CodeHash, contractData.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	storageTrie := execution.NewDbStorageTrie(tx, shardId)
+	if err := execution.UpdateFromMap(
+		storageTrie, contractData.Storage, func(v types.Uint256) *types.Uint256 { return &v },
+	); err != nil {
+		return nil, err
+	}
+	tokenTrie := execution.NewDbTokenTrie(tx, shardId)
+	if err := execution.UpdateFromMap(
+		tokenTrie, contractData.Tokens, func(v types.Value) *types.Value { return &v },
+	); err != nil {
+		return nil, err
+	}
+	asyncContextTrie := execution.NewDbAsyncContextTrie(tx, shardId)
+	if err := execution.UpdateFromMap(
+		asyncContextTrie, contractData.AsyncContext, func(v types.AsyncContext) *types.AsyncContext { return &v },
+	); err != nil {
+		return nil, err
+	}
+
+	if storageTrie.RootHash() != contract.StorageRoot ||
+		tokenTrie.RootHash() != contract.TokenRoot ||
+		asyncContextTrie.RootHash() != contract.AsyncContextRoot {
+		return nil, fmt.Errorf("%w: tries of %s don't match their roots", errSnapshotMismatch, contract.Address)
+	}
+	return contract, nil
+}
+
+// importSnapshotBlockData writes the tries of the block that are not a part of the contract trie.
+func importSnapshotBlockData(
+	tx db.RwTx,
+	shardId types.ShardId,
+	block *types.Block,
+	manifest *rawapitypes.SnapshotManifest,
+) error {
+	if len(manifest.ChildBlocks) != 0 {
+		childBlocks := make(map[types.ShardId]common.Hash, len(manifest.ChildBlocks))
+		for i, childBlock := range manifest.ChildBlocks {
+			// the main shard is omitted
+			childBlocks[types.ShardId(i+1)] = childBlock
+		}
+		treeShards := execution.NewDbShardBlocksTrie(tx, shardId, block.Id)
+		if err := execution.UpdateFromMap(
+			treeShards, childBlocks, func(v common.Hash) *common.Hash { return &v },
+		); err != nil {
+			return err
+		}
+		if treeShards.RootHash() != block.ChildBlocksRootHash {
+			return fmt.Errorf("%w: child blocks root %s != %s",
+				errSnapshotMismatch, treeShards.RootHash(), block.ChildBlocksRootHash)
+		}
+	}
+
+	if len(manifest.Config) != 0 {
+		keys := slices.Sorted(maps.Keys(manifest.Config))
+		rawKeys := make([][]byte, len(keys))
+		values := make([][]byte, len(keys))
+		for i, key := range keys {
+			rawKeys[i] = []byte(key)
+			values[i] = manifest.Config[key]
+		}
+		configTrie := mpt.NewDbMPT(tx, shardId, db.ConfigTrieTable)
+		if err := configTrie.SetBatch(rawKeys, values); err != nil {
+			return err
+		}
+		if configTrie.RootHash() != block.ConfigRoot {
+			return fmt.Errorf("%w: config root %s != %s", errSnapshotMismatch, configTrie.RootHash(), block.ConfigRoot)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+// snapshotNodeApi serves the snapshots of the source node, which can be tampered with by the test.
+type snapshotNodeApi struct {
+	NodeApi
+
+	// otherBlock makes the manifests be taken at the latest block regardless of the requested one.
+	otherBlock bool
+	// stuckChunks makes the chunks never advance.
+	stuckChunks bool
+}
+
+func (api *snapshotNodeApi) GetSnapshotManifest(
+	ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference,
+) (*rawapitypes.SnapshotManifest, error) {
+	if api.otherBlock {
+		blockReference = rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	}
+	return api.NodeApi.GetSnapshotManifest(ctx, shardId, blockReference)
+}
+
+func (api *snapshotNodeApi) GetSnapshotChunk(
+	ctx context.Context, shardId types.ShardId, request rawapitypes.SnapshotChunkRequest,
+) (*rawapitypes.SnapshotChunk, error) {
+	chunk, err := api.NodeApi.GetSnapshotChunk(ctx, shardId, request)
+	if err == nil && api.stuckChunks {
+		chunk.Next = &request.Start
+	}
+	return chunk, err
+}
+
+func newSnapshotSource(t *testing.T) (*snapshotNodeApi, map[types.ShardId]*types.Block) {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	blocks := make(map[types.ShardId]*types.Block)
+	builder := NodeApiBuilder(database, nil)
+	for _, shardId := range []types.ShardId{types.MainShardId, types.BaseShardId} {
+		blocks[shardId] = execution.GenerateZeroState(t, shardId, database)
+		builder.WithLocalShardApiRo(shardId).WithLocalShardApiSync(shardId)
+	}
+	return &snapshotNodeApi{NodeApi: builder.BuildAndReset()}, blocks
+}
+
+func newSnapshotTarget(t *testing.T) db.DB {
+	t.Helper()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+	return database
+}
+
+func readLastBlock(t *testing.T, database db.DB, shardId types.ShardId) (*types.Block, common.Hash) {
+	t.Helper()
+
+	tx, err := database.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	hash, err := db.ReadLastBlockHash(tx, shardId)
+	require.NoError(t, err)
+	block, err := db.ReadBlock(tx, shardId, hash)
+	require.NoError(t, err)
+	return block, hash
+}
+
+func TestFetchShardSnapshot(t *testing.T) {
+	t.Parallel()
+
+	source, blocks := newSnapshotSource(t)
+	shardId := types.BaseShardId
+	blockHash := blocks[shardId].Hash(shardId)
+
+	t.Run("Imported", func(t *testing.T) {
+		t.Parallel()
+
+		target := newSnapshotTarget(t)
+		manifest, err := FetchShardSnapshot(
+			t.Context(), source, target, shardId, rawapitypes.BlockHashAsBlockReference(blockHash))
+		require.NoError(t, err)
+		require.Equal(t, blockHash, manifest.BlockHash)
+
+		block, hash := readLastBlock(t, target, shardId)
+		require.Equal(t, blockHash, hash)
+		require.Equal(t, blocks[shardId].SmartContractsRoot, block.SmartContractsRoot)
+
+		tx, err := target.CreateRoTx(t.Context())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		hashByNumber, err := db.ReadBlockHashByNumber(tx, shardId, block.Id)
+		require.NoError(t, err)
+		require.Equal(t, blockHash, hashByNumber)
+	})
+
+	t.Run("OtherBlock", func(t *testing.T) {
+		t.Parallel()
+
+		source := &snapshotNodeApi{NodeApi: source.NodeApi, otherBlock: true}
+		_, err := FetchShardSnapshot(
+			t.Context(), source, newSnapshotTarget(t), shardId,
+			rawapitypes.BlockHashAsBlockReference(common.HexToHash("0x1234")))
+		require.ErrorIs(t, err, errSnapshotMismatch)
+
+		_, err = FetchShardSnapshot(
+			t.Context(), source, newSnapshotTarget(t), shardId, rawapitypes.BlockNumberAsBlockReference(1))
+		require.ErrorIs(t, err, errSnapshotMismatch)
+	})
+
+	t.Run("StuckChunks", func(t *testing.T) {
+		t.Parallel()
+
+		source := &snapshotNodeApi{NodeApi: source.NodeApi, stuckChunks: true}
+		_, err := FetchShardSnapshot(
+			t.Context(), source, newSnapshotTarget(t), shardId, rawapitypes.BlockHashAsBlockReference(blockHash))
+		require.ErrorIs(t, err, errSnapshotMismatch)
+	})
+}
+
+func TestFetchSnapshot(t *testing.T) {
+	t.Parallel()
+
+	source, blocks := newSnapshotSource(t)
+	target := newSnapshotTarget(t)
+	mainBlockHash := blocks[types.MainShardId].Hash(types.MainShardId)
+	err := FetchSnapshot(
+		t.Context(), source, target, rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock))
+	require.NoError(t, err)
+
+	_, hash := readLastBlock(t, target, types.MainShardId)
+	require.Equal(t, mainBlockHash, hash)
+
+	// The genesis block identifies the chain to the bootstrap peers.
+	tx, err := target.CreateRoTx(t.Context())
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	genesisHash, err := db.ReadBlockHashByNumber(tx, types.MainShardId, 0)
+	require.NoError(t, err)
+	require.Equal(t, mainBlockHash, genesisHash)
+}
@@ -0,0 +1,344 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	// sszApiVersion is the version of the API sending the results of the block and transaction methods
+	// in the SSZ encodings they are stored in instead of converting them to the Protobuf messages.
+	// The requests and the errors are sent in Protobuf as in the previous versions.
+	sszApiVersion = "1.1"
+
+	// sszResponseMarker precedes the SSZ encoding of the result. A Protobuf message never starts with
+	// a zero byte, since there is no field 0, so the client tells the encodings apart without knowing the version.
+	sszResponseMarker byte = 0
+
+	sszOffsetSize = 4
+)
+
+var errInvalidSSZList = errors.New("invalid SSZ list")
+
+// sszResponseCodec encodes the result of a method in SSZ.
+type sszResponseCodec struct {
+	// encode appends the encoding of the result to the buffer, the result is packed in Protobuf
+	// if it returns an error.
+	encode func(buf []byte, result any) ([]byte, error)
+	decode func(data []byte) (any, error)
+}
+
+// sszResponses are the methods whose results are sent in SSZ by sszApiVersion. The data of the results
+// without an SSZ encoding of its own (e.g., the errors of the transactions of a full block) is encoded
+// in the lists of appendSSZList.
+var sszResponses = map[string]*sszResponseCodec{
+	"GetBlockHeader":    sszEncodedDataResponse,
+	"GetFullBlockData":  sszFullBlockResponse,
+	"GetInTransaction":  sszTransactionInfoResponse,
+	"SubscribeNewHeads": sszEncodedDataResponse,
+	"GetTxpoolContent":  sszTransactionsResponse,
+}
+
+var errEmptySSZData = errors.New("empty SSZ data")
+
+var sszEncodedDataResponse = &sszResponseCodec{
+	encode: func(buf []byte, result any) ([]byte, error) {
+		data, _ := result.(sszx.SSZEncodedData)
+		if data == nil {
+			// The Protobuf response reports the missing data.
+			return nil, errEmptySSZData
+		}
+		return append(buf, data...), nil
+	},
+	decode: func(data []byte) (any, error) {
+		// The response may be kept by the cache, so the result must not share its memory.
+		return sszx.SSZEncodedData(bytes.Clone(data)), nil
+	},
+}
+
+var sszTransactionsResponse = &sszResponseCodec{
+	encode: func(buf []byte, result any) ([]byte, error) {
+		transactions, _ := result.([]*types.Transaction)
+		encoded, err := sszx.EncodeContainer(transactions)
+		if err != nil {
+			return nil, err
+		}
+		return appendSSZList(buf, encoded), nil
+	},
+	decode: func(data []byte) (any, error) {
+		encoded, err := splitSSZList(data)
+		if err != nil {
+			return nil, err
+		}
+		return sszx.DecodeContainer[*types.Transaction](encoded)
+	},
+}
+
+// sszTransactionInfoResponse encodes the transaction with its receipt and its position as the list
+// of the transaction, the receipt, the index, the hash and the number of the block.
+var sszTransactionInfoResponse = &sszResponseCodec{
+	encode: func(buf []byte, result any) ([]byte, error) {
+		info, _ := result.(*rawapitypes.TransactionInfo)
+		if info == nil {
+			return nil, errEmptySSZData
+		}
+		return appendSSZList(buf, []sszx.SSZEncodedData{
+			info.TransactionSSZ,
+			info.ReceiptSSZ,
+			binary.LittleEndian.AppendUint64(nil, uint64(info.Index)),
+			info.BlockHash.Bytes(),
+			binary.LittleEndian.AppendUint64(nil, uint64(info.BlockId)),
+		}), nil
+	},
+	decode: func(data []byte) (any, error) {
+		items, err := splitSSZItems(data, 5)
+		if err != nil {
+			return nil, err
+		}
+		if len(items[2]) != 8 || len(items[3]) != common.HashSize || len(items[4]) != 8 {
+			return nil, errInvalidSSZList
+		}
+		return &rawapitypes.TransactionInfo{
+			TransactionSSZ: bytes.Clone(items[0]),
+			ReceiptSSZ:     bytes.Clone(items[1]),
+			Index:          types.TransactionIndex(binary.LittleEndian.Uint64(items[2])),
+			BlockHash:      common.BytesToHash(items[3]),
+			BlockId:        types.BlockNumber(binary.LittleEndian.Uint64(items[4])),
+		}, nil
+	},
+}
+
+// sszFullBlockResponse encodes the block with its transactions as the list of the fields of the block.
+// The lists of the encodings are nested lists, the errors are the hashes of the transactions followed
+// by the messages, the child blocks are the concatenated hashes, and the config is the list of the pairs
+// of the name and the value. The errors and the config are sorted, so that the encoding is deterministic.
+var sszFullBlockResponse = &sszResponseCodec{
+	encode: func(buf []byte, result any) ([]byte, error) {
+		block, _ := result.(*types.RawBlockWithExtractedData)
+		if block == nil || block.Block == nil {
+			return nil, errEmptySSZData
+		}
+
+		errs := make([]sszx.SSZEncodedData, 0, len(block.Errors))
+		hashes := slices.SortedFunc(maps.Keys(block.Errors), func(a, b common.Hash) int {
+			return bytes.Compare(a[:], b[:])
+		})
+		for _, hash := range hashes {
+			errs = append(errs, append(hash.Bytes(), block.Errors[hash]...))
+		}
+		childBlocks := make([]byte, 0, common.HashSize*len(block.ChildBlocks))
+		for _, hash := range block.ChildBlocks {
+			childBlocks = append(childBlocks, hash.Bytes()...)
+		}
+		config := make([]sszx.SSZEncodedData, 0, len(block.Config))
+		for _, name := range slices.Sorted(maps.Keys(block.Config)) {
+			config = append(config, appendSSZList(nil, []sszx.SSZEncodedData{[]byte(name), block.Config[name]}))
+		}
+
+		return appendSSZList(buf, []sszx.SSZEncodedData{
+			block.Block,
+			appendSSZList(nil, block.InTransactions),
+			appendSSZList(nil, block.InTxCounts),
+			appendSSZList(nil, block.OutTransactions),
+			appendSSZList(nil, block.OutTxCounts),
+			appendSSZList(nil, block.Receipts),
+			appendSSZList(nil, errs),
+			childBlocks,
+			binary.LittleEndian.AppendUint64(nil, block.DbTimestamp),
+			appendSSZList(nil, config),
+		}), nil
+	},
+	decode: func(data []byte) (any, error) {
+		fields, err := splitSSZItems(data, 10)
+		if err != nil {
+			return nil, err
+		}
+		block := &types.RawBlockWithExtractedData{Block: bytes.Clone(fields[0])}
+		for i, list := range []*[]sszx.SSZEncodedData{
+			&block.InTransactions, &block.InTxCounts, &block.OutTransactions, &block.OutTxCounts, &block.Receipts,
+		} {
+			if *list, err = splitClonedSSZList(fields[1+i]); err != nil {
+				return nil, err
+			}
+		}
+
+		errs, err := splitSSZList(fields[6])
+		if err != nil {
+			return nil, err
+		}
+		if len(errs) != 0 {
+			block.Errors = make(map[common.Hash]string, len(errs))
+		}
+		for _, item := range errs {
+			if len(item) < common.HashSize {
+				return nil, errInvalidSSZList
+			}
+			block.Errors[common.BytesToHash(item[:common.HashSize])] = string(item[common.HashSize:])
+		}
+
+		if len(fields[7])%common.HashSize != 0 || len(fields[8]) != 8 {
+			return nil, errInvalidSSZList
+		}
+		for hash := range slices.Chunk(fields[7], common.HashSize) {
+			block.ChildBlocks = append(block.ChildBlocks, common.BytesToHash(hash))
+		}
+		block.DbTimestamp = binary.LittleEndian.Uint64(fields[8])
+
+		config, err := splitSSZList(fields[9])
+		if err != nil {
+			return nil, err
+		}
+		if len(config) != 0 {
+			block.Config = make(map[string][]byte, len(config))
+		}
+		for _, item := range config {
+			pair, err := splitSSZItems(item, 2)
+			if err != nil {
+				return nil, err
+			}
+			block.Config[string(pair[0])] = bytes.Clone(pair[1])
+		}
+		return block, nil
+	},
+}
+
+// appendSSZList appends the SSZ encoding of the list of the variable-size items: the offsets of the items
+// from the start of the list followed by the items.
+func appendSSZList(buf []byte, items []sszx.SSZEncodedData) []byte {
+	offset := sszOffsetSize * len(items)
+	for _, item := range items {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(offset))
+		offset += len(item)
+	}
+	for _, item := range items {
+		buf = append(buf, item...)
+	}
+	return buf
+}
+
+// splitSSZList returns the items of the SSZ list encoded by appendSSZList, they share the memory of the data.
+func splitSSZList(data []byte) ([]sszx.SSZEncodedData, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	if len(data) < sszOffsetSize {
+		return nil, errInvalidSSZList
+	}
+	first := binary.LittleEndian.Uint32(data)
+	if first == 0 || first%sszOffsetSize != 0 || uint64(first) > uint64(len(data)) {
+		return nil, errInvalidSSZList
+	}
+
+	count := int(first / sszOffsetSize)
+	items := make([]sszx.SSZEncodedData, count)
+	for i := range count {
+		start := binary.LittleEndian.Uint32(data[i*sszOffsetSize:])
+		end := uint32(len(data))
+		if i+1 < count {
+			end = binary.LittleEndian.Uint32(data[(i+1)*sszOffsetSize:])
+		}
+		if start > end || uint64(end) > uint64(len(data)) {
+			return nil, errInvalidSSZList
+		}
+		items[i] = data[start:end]
+	}
+	return items, nil
+}
+
+// splitSSZItems splits the list of the fields of a result, which has to have the given number of them.
+func splitSSZItems(data []byte, count int) ([]sszx.SSZEncodedData, error) {
+	items, err := splitSSZList(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) != count {
+		return nil, errInvalidSSZList
+	}
+	return items, nil
+}
+
+// splitClonedSSZList splits the list into the items not sharing the memory of the data,
+// since the response may be kept by the cache.
+func splitClonedSSZList(data []byte) ([]sszx.SSZEncodedData, error) {
+	items, err := splitSSZList(data)
+	if err != nil {
+		return nil, err
+	}
+	for i, item := range items {
+		items[i] = bytes.Clone(item)
+	}
+	return items, nil
+}
+
+// sszEncoding returns the copy of the codec packing the successful results in SSZ,
+// nil if the method has no SSZ encoding.
+func (c *methodCodec) sszEncoding() *methodCodec {
+	if c.ssz == nil {
+		return nil
+	}
+	sszCodec := *c
+	sszCodec.encodeSSZ = true
+	return &sszCodec
+}
+
+// appendSSZResponse appends the marked SSZ encoding of the successful result, ok is false
+// if the response has to be packed in Protobuf, e.g., for an error.
+func (c *methodCodec) appendSSZResponse(buf []byte, apiCallResults []reflect.Value) ([]byte, bool) {
+	results, err := splitError(apiCallResults)
+	if err != nil {
+		return nil, false
+	}
+	check.PanicIfNot(len(results) == 1)
+	response, err := c.ssz.encode(append(buf, sszResponseMarker), results[0].Interface())
+	return response, err == nil
+}
+
+type sszResponsesKey struct{}
+
+// requestSSZResponses marks the requests received by the protocol of sszApiVersion,
+// so that the handlers of the methods with an SSZ encoding respond in SSZ.
+func requestSSZResponses(handler network.RequestHandler) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		return handler(context.WithValue(ctx, sszResponsesKey{}, true), request)
+	}
+}
+
+func sszResponsesRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(sszResponsesKey{}).(bool)
+	return requested
+}
+
+// selectSSZResponses passes the requests marked by requestSSZResponses to sszHandler.
+func selectSSZResponses(sszHandler, handler network.RequestHandler) network.RequestHandler {
+	return func(ctx context.Context, request []byte) ([]byte, error) {
+		if sszResponsesRequested(ctx) {
+			return sszHandler(ctx, request)
+		}
+		return handler(ctx, request)
+	}
+}
+
+// responseKey identifies the response of the request of the method for the caches and the deduplication,
+// the same request is answered differently in SSZ and may be answered differently to every signer.
+func responseKey(ctx context.Context, methodName string, request []byte) string {
+	key := methodName + "/"
+	if signer, ok := GetRequestSigner(ctx); ok {
+		key += signer.Address.Hex() + "/"
+	}
+	if sszResponsesRequested(ctx) {
+		key += "ssz/"
+	}
+	return key + string(request)
+}
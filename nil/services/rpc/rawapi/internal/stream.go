@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+)
+
+const (
+	// maxResponseFrameSize limits the size of a single frame of a streaming response.
+	maxResponseFrameSize = 64 * 1024 * 1024
+
+	streamResponseTimeout = 30 * time.Second
+)
+
+// Responses of streaming methods are sent as a sequence of frames.
+// Each frame is a packed Protobuf response preceded by its length encoded as uvarint.
+
+func appendFrame(dst []byte, frame []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(frame)))
+	return append(dst, frame...)
+}
+
+func writeFrame(w io.Writer, frame []byte) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	*buf = appendFrame(*buf, frame)
+	_, err := w.Write(*buf)
+	return err
+}
+
+// readFrame reads the next frame from r. It returns io.EOF if the stream is over.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxResponseFrameSize {
+		return nil, fmt.Errorf("response frame is too large: %d bytes", size)
+	}
+	frame := make([]byte, size)
+	if _, err := io.ReadFull(r, frame); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+func splitFrames(data []byte) ([][]byte, error) {
+	r := bufio.NewReader(bytes.NewReader(data))
+	var frames [][]byte
+	for {
+		frame, err := readFrame(r)
+		if errors.Is(err, io.EOF) {
+			return frames, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, frame)
+	}
+}
+
+var errStreamRejected = errors.New("stream is rejected")
+
+// streamServeKey holds the function serving the stream whose request passes the interceptors.
+type streamServeKey struct{}
+
+// makeStreamOpenHandler chains the interceptors of the protocol for the requests opening the streams,
+// so that the streams and the subscriptions are authorized and limited as the other requests are.
+// The stream is served by the innermost handler, see openStream.
+func makeStreamOpenHandler(
+	ctx context.Context,
+	protocol network.ProtocolID,
+	interceptors []RequestInterceptor,
+) network.RequestHandler {
+	return chainInterceptors(ctx, protocol, func(ctx context.Context, request []byte) ([]byte, error) {
+		serve, _ := ctx.Value(streamServeKey{}).(func(context.Context, []byte))
+		serve(ctx, request)
+		return nil, nil
+	}, interceptors)
+}
+
+// openStream passes the request of the stream through the interceptors and calls serve with it
+// unless an interceptor rejects it, the error of the rejection is returned then.
+func openStream(
+	ctx context.Context,
+	open network.RequestHandler,
+	request []byte,
+	serve func(context.Context, []byte),
+) error {
+	// The interceptors may call serve on another goroutine, e.g., on a worker of the pool.
+	var served atomic.Bool
+	ctx = context.WithValue(ctx, streamServeKey{}, func(ctx context.Context, request []byte) {
+		served.Store(true)
+		serve(ctx, request)
+	})
+	_, err := open(ctx, request)
+	if served.Load() {
+		return nil
+	}
+	if err == nil {
+		err = errStreamRejected
+	}
+	return err
+}
+
+// makeStreamHandler creates a handler for a streaming method. Unlike the ordinary request handler,
+// it writes the response frames to the stream as soon as they are packed.
+// The wire format is compatible with network.Manager.SendRequestAndGetResponse on the client side.
+// The request is served by the interceptors of open, see makeStreamOpenHandler.
+func makeStreamHandler(
+	ctx context.Context,
+	apiMethod reflect.Value,
+	codec *methodCodec,
+	open network.RequestHandler,
+	logger logging.Logger,
+) network.StreamHandler {
+	return func(stream network.Stream) {
+		ctx, cancel := context.WithTimeout(ctx, streamResponseTimeout)
+		defer cancel()
+		ctx = network.WithRequestPeer(ctx, stream.Conn().RemotePeer())
+
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Error().Msgf("Stream handler crashed: %v. Stack:\n%s", err, string(debug.Stack()))
+			}
+		}()
+
+		if err := stream.SetDeadline(time.Now().Add(streamResponseTimeout)); err != nil {
+			logger.Error().Err(err).Msg("Failed to set deadline for stream")
+			return
+		}
+
+		request, err := io.ReadAll(stream)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to read request")
+			return
+		}
+
+		write := func(frame []byte) error {
+			return writeFrame(stream, frame)
+		}
+		writeError := func(err error) {
+			if err := write(codec.packError(err)); err != nil {
+				logger.Error().Err(err).Msg("Failed to write response")
+			}
+		}
+
+		err = openStream(ctx, open, request, func(ctx context.Context, request []byte) {
+			unpackedArguments, err := codec.unpackRequest(request)
+			if err != nil {
+				recordRequestError(ctx, err)
+				writeError(err)
+				return
+			}
+
+			apiArguments := []reflect.Value{reflect.ValueOf(ctx)}
+			apiArguments = append(apiArguments, unpackedArguments...)
+			apiCallResults, err := callApiMethod(apiMethod, apiArguments, logger)
+			if err != nil {
+				recordRequestError(ctx, err)
+				writeError(err)
+				return
+			}
+
+			if err := codec.packResponseFrames(write, apiCallResults...); err != nil {
+				logger.Error().Err(err).Msg("Failed to write response")
+			}
+		})
+		if err != nil {
+			writeError(err)
+		}
+	}
+}
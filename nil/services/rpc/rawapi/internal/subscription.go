@@ -0,0 +1,309 @@
+package internal
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/assert"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	// subscriptionBufferSize is the number of undelivered events kept for a subscriber.
+	subscriptionBufferSize = 100
+
+	// maxSubscriptionsPerMethod limits the number of simultaneously active subscriptions handled by a single method.
+	maxSubscriptionsPerMethod = 256
+
+	subscriptionRequestTimeout = 5 * time.Second
+	subscriptionWriteTimeout   = 10 * time.Second
+)
+
+var errTooManySubscriptions = errors.New("too many active subscriptions")
+
+// Subscription events are sent using the framing of streaming responses.
+// The first frame acknowledges the subscription: it is empty on success or contains the packed error otherwise.
+// Each following frame contains a single event. The server closes the stream when the subscription is over,
+// the client resets it to unsubscribe.
+
+// makeSubscriptionHandler creates a handler for a subscription method. The API method is called with a context
+// that lives as long as the stream, events from the returned channel are written to the stream as they arrive.
+// The request must pass the interceptors of open, see makeStreamOpenHandler. Only the subscribing is intercepted,
+// so the interceptors bounding the handling, e.g., by a timeout or a worker, don't bound the subscription.
+func makeSubscriptionHandler(
+	ctx context.Context,
+	apiMethod reflect.Value,
+	codec *methodCodec,
+	open network.RequestHandler,
+	logger logging.Logger,
+) network.StreamHandler {
+	var active atomic.Int32
+	return func(stream network.Stream) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		ctx = network.WithRequestPeer(ctx, stream.Conn().RemotePeer())
+
+		defer func() {
+			if err := recover(); err != nil {
+				logger.Error().Msgf("Subscription handler crashed: %v. Stack:\n%s", err, string(debug.Stack()))
+			}
+		}()
+
+		write := func(frame []byte) error {
+			if err := stream.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout)); err != nil {
+				return err
+			}
+			return writeFrame(stream, frame)
+		}
+		writeError := func(err error) {
+			if err := write(codec.packError(err)); err != nil {
+				logger.Debug().Err(err).Msg("Failed to write subscription error")
+			}
+		}
+
+		if active.Add(1) > maxSubscriptionsPerMethod {
+			active.Add(-1)
+			writeError(errTooManySubscriptions)
+			return
+		}
+		defer active.Add(-1)
+
+		if err := stream.SetReadDeadline(time.Now().Add(subscriptionRequestTimeout)); err != nil {
+			logger.Error().Err(err).Msg("Failed to set deadline for stream")
+			return
+		}
+		request, err := io.ReadAll(stream)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to read request")
+			return
+		}
+		if err := openStream(ctx, open, request, func(context.Context, []byte) {}); err != nil {
+			writeError(err)
+			return
+		}
+
+		unpackedArguments, err := codec.unpackRequest(request)
+		if err != nil {
+			writeError(err)
+			return
+		}
+
+		apiArguments := []reflect.Value{reflect.ValueOf(ctx)}
+		apiArguments = append(apiArguments, unpackedArguments...)
+		results, err := splitError(apiMethod.Call(apiArguments))
+		if err != nil {
+			writeError(err)
+			return
+		}
+
+		if err := write(nil); err != nil {
+			logger.Debug().Err(err).Msg("Failed to acknowledge subscription")
+			return
+		}
+
+		events := results[0]
+		noError := reflect.Zero(reflect.TypeFor[error]())
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: events},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		}
+		for {
+			chosen, event, ok := reflect.Select(cases)
+			if chosen == 1 || !ok {
+				return
+			}
+
+			frame, err := codec.packSingleResponse(event, noError)
+			if err != nil {
+				logger.Error().Err(err).Msg("Failed to pack subscription event")
+				return
+			}
+			if err := write(frame); err != nil {
+				// Most likely the subscriber is gone.
+				logger.Debug().Err(err).Msg("Failed to write subscription event")
+				return
+			}
+		}
+	}
+}
+
+// doNetworkShardApiSubscription subscribes to the events of the method. Packed events are sent to the returned
+// channel until ctx is done or the server ends the subscription.
+func doNetworkShardApiSubscription(
+	ctx context.Context,
+	networkManager network.Manager,
+	selectPeer PeerSelector,
+	shardId types.ShardId,
+	apiName string,
+	codec *methodCodec,
+	args ...any,
+) (<-chan []byte, error) {
+	serverPeerId, protocol, err := discoverVersionedProtocol(
+		ctx, networkManager, selectPeer, shardId, apiName, codec.methodName)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBody, err := codec.packRequest(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := networkManager.NewStream(ctx, serverPeerId, protocol)
+	if err != nil {
+		forgetApiVersion(serverPeerId, shardId, apiName)
+		return nil, err
+	}
+
+	reader, err := sendSubscriptionRequest(stream, requestBody, codec)
+	if err != nil {
+		_ = stream.Reset()
+		return nil, err
+	}
+
+	events := make(chan []byte, subscriptionBufferSize)
+	go func() {
+		defer close(events)
+		defer stream.Close()
+
+		stop := context.AfterFunc(ctx, func() {
+			_ = stream.Reset()
+		})
+		defer stop()
+
+		for {
+			frame, err := readFrame(reader)
+			if err != nil {
+				return
+			}
+			select {
+			case events <- frame:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// sendSubscriptionRequest sends the request and waits for the acknowledgement of the subscription.
+func sendSubscriptionRequest(stream network.Stream, request []byte, codec *methodCodec) (*bufio.Reader, error) {
+	if err := stream.SetDeadline(time.Now().Add(subscriptionRequestTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := stream.Write(request); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseWrite(); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(stream)
+	ack, err := readFrame(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read subscription acknowledgement: %w", err)
+	}
+	if len(ack) > 0 {
+		if _, err := codec.unpackSingleResponse(ack); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("unexpected subscription acknowledgement")
+	}
+
+	// Events may arrive at any moment, so no deadline is set for the rest of the stream.
+	if err := stream.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	return reader, nil
+}
+
+// subscribeWithCallerMethodName subscribes to the events of the method and unpacks them.
+// The returned channel is closed when the subscription is over.
+func subscribeWithCallerMethodName[EventType any](
+	ctx context.Context,
+	api shardApiRequestPerformer,
+	methodName string,
+	args ...any,
+) (<-chan EventType, error) {
+	if assert.Enable {
+		callerMethodName := extractCallerMethodName(2)
+		check.PanicIfNotf(callerMethodName != "", "Method name not found")
+		check.PanicIfNotf(
+			callerMethodName == methodName, "Method name mismatch: %s != %s", callerMethodName, methodName)
+	}
+	return subscribe[EventType](ctx, api.doApiSubscription, api.apiCodec(), methodName, args...)
+}
+
+func subscribe[EventType any](
+	ctx context.Context,
+	doApiSubscription doApiSubscriptionFunction,
+	apiCodec apiCodec,
+	methodName string,
+	args ...any,
+) (<-chan EventType, error) {
+	codec, ok := apiCodec[methodName]
+	if !ok || codec.kind != subscriptionResponse {
+		return nil, fmt.Errorf("method %s is not a subscription", methodName)
+	}
+
+	packedEvents, err := doApiSubscription(ctx, codec, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan EventType, subscriptionBufferSize)
+	go func() {
+		defer close(events)
+		for packedEvent := range packedEvents {
+			event, err := unpackResponse[EventType](codec, packedEvent)
+			if err != nil {
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// packSubscriptionEvents packs the events received from a subscription API method called directly.
+func packSubscriptionEvents(ctx context.Context, codec *methodCodec, events reflect.Value) <-chan []byte {
+	packedEvents := make(chan []byte, subscriptionBufferSize)
+	go func() {
+		defer close(packedEvents)
+		noError := reflect.Zero(reflect.TypeFor[error]())
+		cases := []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: events},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		}
+		for {
+			chosen, event, ok := reflect.Select(cases)
+			if chosen == 1 || !ok {
+				return
+			}
+			packedEvent, err := codec.packSingleResponse(event, noError)
+			if err != nil {
+				return
+			}
+			select {
+			case packedEvents <- packedEvent:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return packedEvents
+}
@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"context"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+)
+
+// MethodTimeouts maps a protocol ID (e.g., "/shard/1/rawapi_ro/Call") or a method name (e.g., "Call")
+// to the time the handling of a request of the method may take. The timeouts of the protocol ID take precedence.
+type MethodTimeouts map[string]time.Duration
+
+// NewTimeoutInterceptor creates an interceptor cancelling the context of the request after the timeout
+// of the method. The handling can't take longer than the response timeout of the network either way,
+// and it is aborted earlier if the caller has a shorter deadline.
+func NewTimeoutInterceptor(timeouts MethodTimeouts) RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		timeout, ok := findProtocolEntry(timeouts, protocol)
+		if !ok || timeout <= 0 {
+			return next
+		}
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			return next(ctx, request)
+		}
+	}
+}
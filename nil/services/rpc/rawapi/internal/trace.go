@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	// maxTraceDepth limits the length of the chain of cross-shard transactions followed by TransactionTracer.
+	maxTraceDepth = 32
+
+	// maxTraceSize limits the total number of transactions in a single trace.
+	maxTraceSize = 1024
+
+	// maxTraceSteps limits the number of opcode steps kept in an execution trace, the call tree is collected in full.
+	maxTraceSteps = 10_000
+)
+
+var errTraceTooLarge = fmt.Errorf("trace contains more than %d transactions", maxTraceSize)
+
+// TransactionTrace is a node of the execution tree of a transaction.
+// Children are the transactions sent by this one, possibly to other shards.
+type TransactionTrace struct {
+	ShardId     types.ShardId
+	Hash        common.Hash
+	Transaction *types.Transaction
+	// Receipt is nil if the transaction hasn't been processed by the destination shard yet.
+	Receipt   *types.Receipt
+	BlockId   types.BlockNumber
+	BlockHash common.Hash
+	// Truncated is set if the children were not collected because the depth limit was reached.
+	Truncated bool
+	Children  []*TransactionTrace
+}
+
+// TransactionTracer collects the execution tree of a transaction by following the transactions it spawned
+// across shard boundaries. It works on top of NodeApi, so the shards may be served by different nodes.
+type TransactionTracer struct {
+	api NodeApi
+}
+
+func NewTransactionTracer(api NodeApi) *TransactionTracer {
+	return &TransactionTracer{api: api}
+}
+
+func (t *TransactionTracer) Trace(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+) (*TransactionTrace, error) {
+	size := 0
+	return t.trace(ctx, shardId, hash, 0, &size)
+}
+
+func (t *TransactionTracer) trace(
+	ctx context.Context,
+	shardId types.ShardId,
+	hash common.Hash,
+	depth int,
+	size *int,
+) (*TransactionTrace, error) {
+	*size++
+	if *size > maxTraceSize {
+		return nil, errTraceTooLarge
+	}
+
+	node := &TransactionTrace{ShardId: shardId, Hash: hash}
+	info, err := t.api.GetInTransaction(
+		ctx, shardId, rawapitypes.TransactionRequest{ByHash: &rawapitypes.TransactionRequestByHash{Hash: hash}})
+	if errors.Is(err, db.ErrKeyNotFound) {
+		// The transaction is still in flight.
+		return node, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	node.BlockId = info.BlockId
+	node.BlockHash = info.BlockHash
+	node.Transaction = new(types.Transaction)
+	if err := node.Transaction.UnmarshalSSZ(info.TransactionSSZ); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal transaction %s: %w", hash, err)
+	}
+	node.Receipt = new(types.Receipt)
+	if err := node.Receipt.UnmarshalSSZ(info.ReceiptSSZ); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt of transaction %s: %w", hash, err)
+	}
+
+	if node.Receipt.OutTxnNum == 0 {
+		return node, nil
+	}
+	if depth >= maxTraceDepth {
+		node.Truncated = true
+		return node, nil
+	}
+
+	blockRef := rawapitypes.BlockHashAsBlockReference(info.BlockHash)
+	node.Children = make([]*TransactionTrace, 0, node.Receipt.OutTxnNum)
+	for i := node.Receipt.OutTxnIndex; i < node.Receipt.OutTxnIndex+node.Receipt.OutTxnNum; i++ {
+		outInfo, err := t.api.GetOutTransaction(ctx, shardId, rawapitypes.TransactionRequest{
+			ByBlockRefAndIndex: &rawapitypes.TransactionRequestByBlockRefAndIndex{
+				BlockRef: blockRef,
+				Index:    types.TransactionIndex(i),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var outTxn types.Transaction
+		if err := outTxn.UnmarshalSSZ(outInfo.TransactionSSZ); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal outgoing transaction: %w", err)
+		}
+
+		child, err := t.trace(ctx, outTxn.To.ShardId(), outTxn.Hash(), depth+1, size)
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+	return node, nil
+}
+
+// executionTracer collects the opcode steps and the call tree of an EVM execution.
+type executionTracer struct {
+	steps          []rawapitypes.OpcodeStep
+	stepsTruncated bool
+
+	root      *rawapitypes.CallFrame
+	callStack []*rawapitypes.CallFrame
+}
+
+func newExecutionTracer() *executionTracer {
+	return &executionTracer{}
+}
+
+func (t *executionTracer) hooks() *tracing.Hooks {
+	return &tracing.Hooks{
+		OnOpcode: t.onOpcode,
+		OnEnter:  t.onEnter,
+		OnExit:   t.onExit,
+	}
+}
+
+func (t *executionTracer) onOpcode(
+	pc uint64, op byte, gas, cost uint64, scope tracing.OpContext, _ []byte, depth int, err error,
+) {
+	if len(t.steps) >= maxTraceSteps {
+		t.stepsTruncated = true
+		return
+	}
+
+	stackData := scope.StackData()
+	step := rawapitypes.OpcodeStep{
+		Pc:      pc,
+		Op:      vm.OpCode(op).String(),
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   depth,
+		Stack:   make([]types.Uint256, len(stackData)),
+	}
+	for i, item := range stackData {
+		step.Stack[i] = types.Uint256(item)
+	}
+	if err != nil {
+		step.Error = err.Error()
+	}
+	t.steps = append(t.steps, step)
+}
+
+func (t *executionTracer) onEnter(
+	_ int, typ byte, from types.Address, to types.Address, input []byte, gas uint64, value *big.Int,
+) {
+	frame := &rawapitypes.CallFrame{
+		Type:  vm.OpCode(typ).String(),
+		From:  from,
+		To:    to,
+		Input: slices.Clone(input),
+		Gas:   gas,
+		Value: types.NewZeroValue(),
+	}
+	if value != nil {
+		frame.Value = types.NewValueFromBigMust(value)
+	}
+
+	if n := len(t.callStack); n > 0 {
+		parent := t.callStack[n-1]
+		parent.Calls = append(parent.Calls, frame)
+	} else if t.root == nil {
+		t.root = frame
+	}
+	t.callStack = append(t.callStack, frame)
+}
+
+func (t *executionTracer) onExit(_ int, output []byte, gasUsed uint64, err error, _ bool) {
+	n := len(t.callStack)
+	if n == 0 {
+		return
+	}
+	frame := t.callStack[n-1]
+	t.callStack = t.callStack[:n-1]
+
+	frame.Output = slices.Clone(output)
+	frame.GasUsed = gasUsed
+	if err != nil {
+		frame.Error = err.Error()
+	}
+}
+
+func (t *executionTracer) trace(res *execution.ExecutionResult) *rawapitypes.ExecutionTrace {
+	trace := &rawapitypes.ExecutionTrace{
+		Steps:          t.steps,
+		StepsTruncated: t.stepsTruncated,
+		Call:           t.root,
+		GasUsed:        res.GasUsed,
+	}
+	if res.Failed() {
+		trace.Error = res.GetError().Error()
+	}
+	return trace
+}
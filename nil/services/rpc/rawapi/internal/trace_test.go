@@ -0,0 +1,296 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/execution"
+	"github.com/NilFoundation/nil/nil/internal/tracing"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+type testOpContext struct {
+	tracing.OpContext
+
+	stack []uint256.Int
+}
+
+func (c testOpContext) StackData() []uint256.Int {
+	return c.stack
+}
+
+// traceNodeApi serves the transactions received and sent by the blocks of the shards to TransactionTracer.
+type traceNodeApi struct {
+	NodeApi
+
+	received map[common.Hash]*rawapitypes.TransactionInfo
+	sent     map[common.Hash][]*types.Transaction
+}
+
+func newTraceNodeApi() *traceNodeApi {
+	return &traceNodeApi{
+		received: make(map[common.Hash]*rawapitypes.TransactionInfo),
+		sent:     make(map[common.Hash][]*types.Transaction),
+	}
+}
+
+// receive processes the transaction in the block of the shard of its destination, the transactions it sends
+// follow the ones already sent by the block.
+func (api *traceNodeApi) receive(
+	t *testing.T, txn *types.Transaction, blockHash common.Hash, sent ...*types.Transaction,
+) {
+	t.Helper()
+
+	receipt := &types.Receipt{
+		Success:     true,
+		TxnHash:     txn.Hash(),
+		OutTxnIndex: uint32(len(api.sent[blockHash])),
+		OutTxnNum:   uint32(len(sent)),
+	}
+	api.sent[blockHash] = append(api.sent[blockHash], sent...)
+
+	transactionSSZ, err := txn.MarshalSSZ()
+	require.NoError(t, err)
+	receiptSSZ, err := receipt.MarshalSSZ()
+	require.NoError(t, err)
+	api.received[txn.Hash()] = &rawapitypes.TransactionInfo{
+		TransactionSSZ: transactionSSZ,
+		ReceiptSSZ:     receiptSSZ,
+		BlockHash:      blockHash,
+		BlockId:        1,
+	}
+}
+
+func (api *traceNodeApi) GetInTransaction(
+	_ context.Context, shardId types.ShardId, request rawapitypes.TransactionRequest,
+) (*rawapitypes.TransactionInfo, error) {
+	info, ok := api.received[request.ByHash.Hash]
+	if !ok {
+		return nil, db.ErrKeyNotFound
+	}
+	var txn types.Transaction
+	if err := txn.UnmarshalSSZ(info.TransactionSSZ); err != nil {
+		return nil, err
+	}
+	if txn.To.ShardId() != shardId {
+		return nil, db.ErrKeyNotFound
+	}
+	return info, nil
+}
+
+func (api *traceNodeApi) GetOutTransaction(
+	_ context.Context, _ types.ShardId, request rawapitypes.TransactionRequest,
+) (*rawapitypes.TransactionInfo, error) {
+	sent := api.sent[request.ByBlockRefAndIndex.BlockRef.Hash()]
+	index := request.ByBlockRefAndIndex.Index
+	if int(index) >= len(sent) {
+		return nil, db.ErrKeyNotFound
+	}
+	transactionSSZ, err := sent[index].MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.TransactionInfo{TransactionSSZ: transactionSSZ, Index: index}, nil
+}
+
+func TestTransactionTracer(t *testing.T) {
+	t.Parallel()
+
+	newTransaction := func(to types.Address, data string) *types.Transaction {
+		txn := types.NewEmptyTransaction()
+		txn.To = to
+		txn.Data = types.Code(data)
+		return txn
+	}
+	baseAddress := types.ShardAndHexToAddress(types.BaseShardId, "0x1234")
+	mainAddress := types.ShardAndHexToAddress(types.MainShardId, "0x1234")
+	baseBlock := common.HexToHash("0x01")
+	mainBlock := common.HexToHash("0x02")
+
+	t.Run("CrossShard", func(t *testing.T) {
+		t.Parallel()
+
+		// The root sends a transaction to the main shard which sends one back, and one still in flight.
+		root := newTransaction(baseAddress, "root")
+		request := newTransaction(mainAddress, "request")
+		response := newTransaction(baseAddress, "response")
+		inFlight := newTransaction(baseAddress, "in flight")
+		api := newTraceNodeApi()
+		// The block sends a transaction before the root, the children are read at the indices of the receipt.
+		api.receive(t, newTransaction(baseAddress, "other"), baseBlock, newTransaction(mainAddress, "other"))
+		api.receive(t, root, baseBlock, request, inFlight)
+		api.receive(t, request, mainBlock, response)
+		api.receive(t, response, baseBlock)
+
+		trace, err := NewTransactionTracer(api).Trace(t.Context(), types.BaseShardId, root.Hash())
+		require.NoError(t, err)
+		require.Equal(t, root.Hash(), trace.Hash)
+		require.Equal(t, root.Hash(), trace.Transaction.Hash())
+		require.Equal(t, baseBlock, trace.BlockHash)
+		require.True(t, trace.Receipt.Success)
+		require.Len(t, trace.Children, 2)
+
+		requestTrace := trace.Children[0]
+		require.Equal(t, types.MainShardId, requestTrace.ShardId)
+		require.Equal(t, request.Hash(), requestTrace.Hash)
+		require.Equal(t, mainBlock, requestTrace.BlockHash)
+		require.NotNil(t, requestTrace.Receipt)
+		require.Len(t, requestTrace.Children, 1)
+
+		responseTrace := requestTrace.Children[0]
+		require.Equal(t, types.BaseShardId, responseTrace.ShardId)
+		require.Equal(t, response.Hash(), responseTrace.Hash)
+		require.NotNil(t, responseTrace.Receipt)
+		require.Empty(t, responseTrace.Children)
+
+		// The transaction not processed yet has neither the transaction nor the receipt.
+		inFlightTrace := trace.Children[1]
+		require.Equal(t, inFlight.Hash(), inFlightTrace.Hash)
+		require.Nil(t, inFlightTrace.Transaction)
+		require.Nil(t, inFlightTrace.Receipt)
+		require.False(t, inFlightTrace.Truncated)
+	})
+
+	t.Run("Depth", func(t *testing.T) {
+		t.Parallel()
+
+		// The transaction sending itself forms an endless chain, it's cut at the depth limit.
+		loop := newTransaction(baseAddress, "loop")
+		api := newTraceNodeApi()
+		api.receive(t, loop, baseBlock, loop)
+
+		trace, err := NewTransactionTracer(api).Trace(t.Context(), types.BaseShardId, loop.Hash())
+		require.NoError(t, err)
+		depth := 0
+		for ; len(trace.Children) != 0; depth++ {
+			require.False(t, trace.Truncated)
+			require.Len(t, trace.Children, 1)
+			trace = trace.Children[0]
+		}
+		require.Equal(t, maxTraceDepth, depth)
+		require.True(t, trace.Truncated)
+	})
+
+	t.Run("Size", func(t *testing.T) {
+		t.Parallel()
+
+		// The chain doubling at each step exceeds the size limit before the depth one.
+		fork := newTransaction(baseAddress, "fork")
+		api := newTraceNodeApi()
+		api.receive(t, fork, baseBlock, fork, fork)
+
+		_, err := NewTransactionTracer(api).Trace(t.Context(), types.BaseShardId, fork.Hash())
+		require.ErrorIs(t, err, errTraceTooLarge)
+	})
+}
+
+func TestExecutionTracer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("CallTree", func(t *testing.T) {
+		t.Parallel()
+
+		from := types.ShardAndHexToAddress(types.MainShardId, "0x01")
+		to := types.ShardAndHexToAddress(types.MainShardId, "0x02")
+		nested := types.ShardAndHexToAddress(types.MainShardId, "0x03")
+
+		tracer := newExecutionTracer()
+		hooks := tracer.hooks()
+		hooks.OnEnter(0, byte(vm.CALL), from, to, []byte{1}, 1000, big.NewInt(5))
+		hooks.OnOpcode(0, byte(vm.PUSH1), 1000, 3, testOpContext{stack: []uint256.Int{*uint256.NewInt(7)}}, nil, 1, nil)
+		hooks.OnEnter(1, byte(vm.STATICCALL), to, nested, nil, 500, nil)
+		hooks.OnExit(1, []byte{2}, 100, errors.New("reverted"), true)
+		hooks.OnEnter(1, byte(vm.DELEGATECALL), to, nested, nil, 300, nil)
+		hooks.OnExit(1, nil, 50, nil, false)
+		hooks.OnExit(0, []byte{3}, 400, nil, false)
+
+		trace := tracer.trace(execution.NewExecutionResult())
+		require.Equal(t, []rawapitypes.OpcodeStep{{
+			Pc: 0, Op: "PUSH1", Gas: 1000, GasCost: 3, Depth: 1, Stack: []types.Uint256{types.Uint256(*uint256.NewInt(7))},
+		}}, trace.Steps)
+		require.False(t, trace.StepsTruncated)
+		require.Empty(t, trace.Error)
+
+		root := trace.Call
+		require.NotNil(t, root)
+		require.Equal(t, "CALL", root.Type)
+		require.Equal(t, from, root.From)
+		require.Equal(t, to, root.To)
+		require.Equal(t, types.NewValueFromUint64(5), root.Value)
+		require.Equal(t, []byte{3}, root.Output)
+		require.Equal(t, uint64(400), root.GasUsed)
+
+		// The nested calls are collected in the order of execution together with their errors.
+		require.Len(t, root.Calls, 2)
+		require.Equal(t, "STATICCALL", root.Calls[0].Type)
+		require.Equal(t, "reverted", root.Calls[0].Error)
+		require.True(t, root.Calls[0].Value.IsZero())
+		require.Equal(t, "DELEGATECALL", root.Calls[1].Type)
+		require.Empty(t, root.Calls[1].Error)
+	})
+
+	t.Run("StepsTruncated", func(t *testing.T) {
+		t.Parallel()
+
+		tracer := newExecutionTracer()
+		for pc := range uint64(maxTraceSteps + 1) {
+			tracer.onOpcode(pc, byte(vm.JUMPDEST), 1000, 1, testOpContext{}, nil, 1, nil)
+		}
+		trace := tracer.trace(execution.NewExecutionResult())
+		require.Len(t, trace.Steps, maxTraceSteps)
+		require.True(t, trace.StepsTruncated)
+	})
+}
+
+func TestTraceCall(t *testing.T) {
+	t.Parallel()
+
+	database, err := db.NewBadgerDbInMemory()
+	require.NoError(t, err)
+	t.Cleanup(database.Close)
+
+	shardId := types.MainShardId
+	hash := execution.GenerateZeroState(t, shardId, database).Hash(shardId)
+	api := NodeApiBuilder(database, nil).
+		WithLocalShardApiRo(shardId).
+		WithLocalShardApiDebug(shardId).
+		BuildAndReset()
+
+	// PUSH1 1, PUSH1 0, SSTORE, STOP
+	code := hexutil.Bytes{byte(vm.PUSH1), 1, byte(vm.PUSH1), 0, byte(vm.SSTORE), byte(vm.STOP)}
+	address := types.ShardAndHexToAddress(shardId, "0x1234")
+	overrides := rpctypes.StateOverrides{address: {Code: &code}}
+	args := rpctypes.CallArgs{
+		To:  address,
+		Fee: types.NewFeePackFromFeeCredit(rpctypes.FeeEstimationFeeCreditCap()),
+	}
+
+	trace, err := api.TraceCall(t.Context(), args,
+		rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(hash, nil), &overrides, nil)
+	require.NoError(t, err)
+	require.Empty(t, trace.Error)
+
+	ops := make([]string, 0, len(trace.Steps))
+	for _, step := range trace.Steps {
+		ops = append(ops, step.Op)
+	}
+	require.Equal(t, []string{"PUSH1", "PUSH1", "SSTORE", "STOP"}, ops)
+	// The stack of SSTORE holds the key on top of the value.
+	require.Equal(t, []types.Uint256{types.Uint256(*uint256.NewInt(1)), types.Uint256(*uint256.NewInt(0))},
+		trace.Steps[2].Stack)
+
+	require.NotNil(t, trace.Call)
+	require.Equal(t, address, trace.Call.To)
+	require.Empty(t, trace.Call.Calls)
+	require.Positive(t, trace.GasUsed)
+}
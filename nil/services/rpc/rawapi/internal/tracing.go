@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/telemetry"
+	"github.com/NilFoundation/nil/nil/internal/telemetry/telattr"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// The phases of handling a request, they are traced as the children of the span of the request.
+const (
+	requestPhaseUnpack   = "unpack"
+	requestPhaseDispatch = "dispatch"
+	requestPhasePack     = "pack"
+)
+
+var tracer = telemetry.NewTracer("github.com/NilFoundation/nil/nil/services/rpc/rawapi")
+
+// traceContextPropagator doesn't depend on the global propagator, so the trace continues on the server
+// regardless of the configuration of the nodes.
+var traceContextPropagator = propagation.TraceContext{}
+
+// injectTraceContext returns the trace context of the caller to be sent in the request envelope,
+// it is nil if the caller is not traced.
+func injectTraceContext(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	traceContextPropagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+func extractTraceContext(ctx context.Context, traceContext map[string]string) context.Context {
+	if len(traceContext) == 0 {
+		return ctx
+	}
+	return traceContextPropagator.Extract(ctx, propagation.MapCarrier(traceContext))
+}
+
+func startClientSpan(ctx context.Context, protocol network.ProtocolID) (context.Context, trace.Span) {
+	return tracer.Start(ctx, string(protocol),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(telattr.ProtocolId(protocol)))
+}
+
+func startServerSpan(ctx context.Context, shardId types.ShardId, methodName string) (context.Context, trace.Span) {
+	ctx, span := tracer.Start(ctx, methodName,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(telattr.ShardId(shardId), telattr.RpcMethod(methodName)))
+	if peerId, ok := network.RequestPeer(ctx); ok {
+		span.SetAttributes(telattr.PeerId(peerId))
+	}
+	return ctx, span
+}
+
+// startRequestPhase starts the span of a phase of the request, the API called by the dispatch phase
+// gets the returned context, so its spans are the children of the phase.
+func startRequestPhase(ctx context.Context, phase string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, phase)
+}
+
+// endSpan marks the span as failed if there is an error and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,270 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+const (
+	// maxCallDataSize limits the data and the transaction of the calls, it leaves room for the deployment
+	// of the largest contracts.
+	maxCallDataSize = 1 << 20
+
+	// maxLogFilterTopics is the number of topics a log can have.
+	maxLogFilterTopics = 4
+
+	// maxLogFilterAlternatives limits the number of the addresses and the alternatives of each topic of a filter.
+	maxLogFilterAlternatives = 1024
+
+	// maxTokensFilterSize limits the number of the tokens a page of the tokens of an account is restricted to.
+	maxTokensFilterSize = 1024
+
+	// maxTokensPageSize limits the number of the tokens of a page of the tokens of an account.
+	maxTokensPageSize = 1024
+
+	// maxMultiAccountRequestSize limits the number of the accounts queried by a single request.
+	maxMultiAccountRequestSize = 1024
+
+	// maxStorageRangeSize limits the number of the entries of a range of the storage of a contract.
+	maxStorageRangeSize = 1024
+
+	// maxPendingResponsesPageSize limits the number of the pending responses of a page of a contract.
+	maxPendingResponsesPageSize = 1024
+
+	// maxBundleSize limits the number of the calls of a simulated bundle.
+	maxBundleSize = 64
+)
+
+// requestValidators check the semantics of the unpacked arguments of the methods before the API is called,
+// so that the requests that can't be served reasonably don't reach the execution.
+// The errors are sent to the client as invalid arguments.
+var requestValidators = map[string]func(args []any) error{
+	"Call":                   validateCallArgs(0),
+	"EstimateFee":            validateCallArgs(0),
+	"CreateAccessList":       validateCallArgs(0),
+	"TraceCall":              validateCallArgs(0),
+	"SimulateBundle":         validateBundle(0),
+	"SendTransactionBundle":  validateTransactionBundle(0),
+	"PrepareDeploy":          validateDeployRequest(0),
+	"GetLogs":                validateLogFilter(0),
+	"SubscribeLogs":          validateLogFilter(0),
+	"GetTokens":              validateTokensRequest(2),
+	"GetBalances":            validateAddresses(0),
+	"ResolveShards":          validateAddresses(0),
+	"GetStorageRange":        validateStorageRangeLimit(2),
+	"GetShardStats":          validateBlockNumber(0),
+	"GetPendingResponsesFor": validatePendingResponsesLimit(2),
+}
+
+// validateRequest is called with the unpacked arguments by the codec and by the generated dispatchers.
+func validateRequest(methodName string, args ...any) error {
+	validate, ok := requestValidators[methodName]
+	if !ok {
+		return nil
+	}
+	if err := validate(args); err != nil {
+		return rawapitypes.NewInvalidArgumentError(err)
+	}
+	return nil
+}
+
+// validateRequestValues validates the arguments unpacked by the reflection.
+func validateRequestValues(methodName string, values []reflect.Value) error {
+	if _, ok := requestValidators[methodName]; !ok {
+		return nil
+	}
+	args := make([]any, len(values))
+	for i, value := range values {
+		args[i] = value.Interface()
+	}
+	return validateRequest(methodName, args...)
+}
+
+func validateCallArgs(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		callArgs, ok := args[argIndex].(rpctypes.CallArgs)
+		if !ok {
+			return nil
+		}
+		if callArgs.Data != nil && len(*callArgs.Data) > maxCallDataSize {
+			return fmt.Errorf("call data must be at most %d bytes", maxCallDataSize)
+		}
+		if callArgs.Transaction != nil && len(*callArgs.Transaction) > maxCallDataSize {
+			return fmt.Errorf("call transaction must be at most %d bytes", maxCallDataSize)
+		}
+		return nil
+	}
+}
+
+func validateBundle(argIndex int) func(args []any) error {
+	validateCall := validateCallArgs(0)
+	return func(args []any) error {
+		calls, ok := args[argIndex].([]rpctypes.CallArgs)
+		if !ok {
+			return nil
+		}
+		if len(calls) > maxBundleSize {
+			return fmt.Errorf("bundle must contain at most %d calls", maxBundleSize)
+		}
+		for i, call := range calls {
+			if err := validateCall([]any{call}); err != nil {
+				return fmt.Errorf("call %d: %w", i, err)
+			}
+		}
+		return nil
+	}
+}
+
+func validateTransactionBundle(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		transactions, ok := args[argIndex].([][]byte)
+		if !ok {
+			return nil
+		}
+		if len(transactions) > txnpool.MaxBundleSize {
+			return fmt.Errorf("bundle must contain at most %d transactions", txnpool.MaxBundleSize)
+		}
+		for i, transaction := range transactions {
+			if len(transaction) > maxCallDataSize {
+				return fmt.Errorf("transaction %d must be at most %d bytes", i, maxCallDataSize)
+			}
+		}
+		return nil
+	}
+}
+
+func validateDeployRequest(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		request, ok := args[argIndex].(rawapitypes.DeployRequest)
+		if !ok {
+			return nil
+		}
+		if len(request.Code) > maxCallDataSize {
+			return fmt.Errorf("code must be at most %d bytes", maxCallDataSize)
+		}
+		return nil
+	}
+}
+
+func validateLogFilter(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		filter, ok := args[argIndex].(rawapitypes.LogFilter)
+		if !ok {
+			return nil
+		}
+		// The range ending with the latest block is checked by GetLogs, since the latest block isn't known here.
+		if filter.ToBlock != nil && *filter.ToBlock >= filter.FromBlock &&
+			*filter.ToBlock-filter.FromBlock >= maxLogsBlockRange {
+			return errLogsBlockRangeTooWide
+		}
+		if len(filter.Topics) > maxLogFilterTopics {
+			return fmt.Errorf("filter must contain at most %d topics", maxLogFilterTopics)
+		}
+		if len(filter.Addresses) > maxLogFilterAlternatives {
+			return fmt.Errorf("filter must contain at most %d addresses", maxLogFilterAlternatives)
+		}
+		for _, alternatives := range filter.Topics {
+			if len(alternatives) > maxLogFilterAlternatives {
+				return fmt.Errorf("filter must contain at most %d alternatives of a topic", maxLogFilterAlternatives)
+			}
+		}
+		return nil
+	}
+}
+
+func validateTokensRequest(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		request, ok := args[argIndex].(rawapitypes.TokensRequest)
+		if !ok {
+			return nil
+		}
+		if len(request.TokenIds) > maxTokensFilterSize {
+			return fmt.Errorf("tokens filter must contain at most %d tokens", maxTokensFilterSize)
+		}
+		if request.Limit > maxTokensPageSize {
+			return fmt.Errorf("tokens page must contain at most %d tokens", maxTokensPageSize)
+		}
+		return nil
+	}
+}
+
+func validateAddresses(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		addresses, ok := args[argIndex].([]types.Address)
+		if !ok {
+			return nil
+		}
+		if len(addresses) > maxMultiAccountRequestSize {
+			return fmt.Errorf("request must contain at most %d addresses", maxMultiAccountRequestSize)
+		}
+		return nil
+	}
+}
+
+func validateStorageRangeLimit(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		limit, ok := args[argIndex].(uint64)
+		if !ok {
+			return nil
+		}
+		if limit > maxStorageRangeSize {
+			return fmt.Errorf("storage range must contain at most %d entries", maxStorageRangeSize)
+		}
+		return nil
+	}
+}
+
+func validatePendingResponsesLimit(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		limit, ok := args[argIndex].(uint64)
+		if !ok {
+			return nil
+		}
+		if limit > maxPendingResponsesPageSize {
+			return fmt.Errorf("pending responses page must contain at most %d responses", maxPendingResponsesPageSize)
+		}
+		return nil
+	}
+}
+
+func validateBlockNumber(argIndex int) func(args []any) error {
+	return func(args []any) error {
+		number, ok := args[argIndex].(types.BlockNumber)
+		if !ok {
+			return nil
+		}
+		if number == types.InvalidBlockNumber {
+			return fmt.Errorf("block number %d is invalid", number)
+		}
+		return nil
+	}
+}
+
+// RequestSizeLimits maps a protocol ID (e.g., "/shard/1/rawapi_ro/Call") or a method name (e.g., "Call")
+// to the maximal size of a request of the method in bytes. The limits of the protocol ID take precedence.
+type RequestSizeLimits map[string]int
+
+// NewRequestSizeInterceptor creates an interceptor rejecting the requests larger than the limit of the method
+// with an invalid argument error before they are unpacked.
+func NewRequestSizeInterceptor(limits RequestSizeLimits) RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		limit, ok := findProtocolEntry(limits, protocol)
+		if !ok || limit <= 0 {
+			return next
+		}
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			if len(request) > limit {
+				return nil, rawapitypes.NewInvalidArgumentError(
+					fmt.Errorf("request of %d bytes exceeds the limit of %d bytes", len(request), limit))
+			}
+			return next(ctx, request)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/internal/types"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAddresses(t *testing.T) {
+	t.Parallel()
+
+	addresses := make([]types.Address, maxMultiAccountRequestSize)
+	for _, method := range []string{"GetBalances", "ResolveShards"} {
+		require.NoError(t, validateRequest(method, addresses))
+
+		err := validateRequest(method, append(addresses, types.EmptyAddress))
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument, method)
+	}
+}
+
+func TestValidateBlockNumber(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateRequest("GetShardStats", types.BlockNumber(0), uint64(10)))
+
+	err := validateRequest("GetShardStats", types.InvalidBlockNumber, uint64(10))
+	require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+}
+
+func TestValidateTokensRequest(t *testing.T) {
+	t.Parallel()
+
+	address := types.EmptyAddress
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	require.NoError(t, validateRequest("GetTokens", address, latest, rawapitypes.TokensRequest{}))
+	require.NoError(t, validateRequest("GetTokens", address, latest,
+		rawapitypes.TokensRequest{Limit: maxTokensPageSize}))
+
+	err := validateRequest("GetTokens", address, latest, rawapitypes.TokensRequest{Limit: maxTokensPageSize + 1})
+	require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+}
+
+func TestValidatePendingResponsesLimit(t *testing.T) {
+	t.Parallel()
+
+	address := types.EmptyAddress
+	latest := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	require.NoError(t, validateRequest("GetPendingResponsesFor", address, types.TransactionIndex(0), uint64(0), latest))
+	require.NoError(t, validateRequest("GetPendingResponsesFor", address, types.TransactionIndex(0),
+		uint64(maxPendingResponsesPageSize), latest))
+
+	err := validateRequest("GetPendingResponsesFor", address, types.TransactionIndex(0),
+		uint64(maxPendingResponsesPageSize+1), latest)
+	require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+}
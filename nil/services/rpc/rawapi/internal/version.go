@@ -0,0 +1,235 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/pb"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	getApiVersionMethodName = "GetApiVersion"
+
+	// legacyApiVersion is the version served by the protocols without a version segment,
+	// which are used by the nodes not supporting the handshake.
+	legacyApiVersion = "1.0"
+
+	// maxNegotiatedPeers is the number of peers whose negotiated API versions are remembered by the client.
+	maxNegotiatedPeers = 1024
+)
+
+// supportedApiVersions are the versions of the API served by the node, the newest one first.
+// An incompatible change of the schema or of the encoding adds a new version, while the handlers of the previous
+// ones are kept until all the nodes are upgraded.
+var supportedApiVersions = []string{sszApiVersion, legacyApiVersion}
+
+var errNoCompatibleApiVersion = errors.New("no compatible API version")
+
+func makeVersionedProtocolId(
+	shardId types.ShardId, apiName string, version string, methodName string,
+) network.ProtocolID {
+	return network.ProtocolID(fmt.Sprintf("/shard/%d/%s/%s/%s", shardId, apiName, version, methodName))
+}
+
+// isVersionedProtocol reports whether the ID of the method has a version segment. The IDs without it
+// address the legacy nodes, which don't support the handshake.
+func isVersionedProtocol(protocol network.ProtocolID) bool {
+	// The versioned IDs are "/shard/<shard>/<api>/<version>/<method>".
+	return strings.Count(string(protocol), "/") == 5
+}
+
+// makeProtocolIds returns the IDs of the method in all the supported versions, including the legacy one.
+func makeProtocolIds(shardId types.ShardId, apiName string, methodName string) []network.ProtocolID {
+	protocols := make([]network.ProtocolID, 0, len(supportedApiVersions)+1)
+	for _, version := range supportedApiVersions {
+		protocols = append(protocols, makeVersionedProtocolId(shardId, apiName, version, methodName))
+	}
+	if slices.Contains(supportedApiVersions, legacyApiVersion) {
+		protocols = append(protocols, makeProtocolId(shardId, apiName, methodName))
+	}
+	return protocols
+}
+
+// registerApiVersions serves the handlers of the protocols without a version segment
+// under the IDs of all the supported versions.
+func registerApiVersions[T any](
+	handlers map[network.ProtocolID]T, shardId types.ShardId, apiName string,
+) map[network.ProtocolID]T {
+	versioned := make(map[network.ProtocolID]T, len(handlers)*(len(supportedApiVersions)+1))
+	for protocol, handler := range handlers {
+		for _, versionedProtocol := range makeProtocolIds(shardId, apiName, path.Base(string(protocol))) {
+			versioned[versionedProtocol] = handler
+		}
+	}
+	return versioned
+}
+
+// selectApiVersion returns the newest of the versions supported by both sides.
+func selectApiVersion(versions []string) string {
+	for _, version := range supportedApiVersions {
+		if slices.Contains(versions, version) {
+			return version
+		}
+	}
+	return ""
+}
+
+func makeGetApiVersionRequestHandler() network.RequestHandler {
+	return func(_ context.Context, request []byte) ([]byte, error) {
+		var versionRequest pb.ApiVersionRequest
+		if err := proto.Unmarshal(request, &versionRequest); err != nil {
+			return nil, fmt.Errorf("failed to unpack Protobuf request: %w", err)
+		}
+		return proto.Marshal(&pb.ApiVersionResponse{Result: &pb.ApiVersionResponse_Data{Data: &pb.ApiVersion{
+			Version:           selectApiVersion(versionRequest.GetSupportedVersions()),
+			SupportedVersions: supportedApiVersions,
+		}}})
+	}
+}
+
+func packGetApiVersionError(err error) []byte {
+	response, packErr := proto.Marshal(
+		&pb.ApiVersionResponse{Result: &pb.ApiVersionResponse_Error{Error: new(pb.Error).PackProtoMessage(err)}})
+	check.PanicIfErr(packErr)
+	return response
+}
+
+// getApiVersion performs the handshake with the peer serving the API of the shard.
+func getApiVersion(
+	ctx context.Context,
+	networkManager network.Manager,
+	peerId network.PeerID,
+	shardId types.ShardId,
+	apiName string,
+) (*pb.ApiVersion, error) {
+	request, err := proto.Marshal(&pb.ApiVersionRequest{SupportedVersions: supportedApiVersions})
+	if err != nil {
+		return nil, err
+	}
+	response, err := networkManager.SendRequestAndGetResponse(
+		ctx, peerId, makeProtocolId(shardId, apiName, getApiVersionMethodName), request)
+	if err != nil {
+		return nil, err
+	}
+
+	var versionResponse pb.ApiVersionResponse
+	if err := proto.Unmarshal(response, &versionResponse); err != nil {
+		return nil, fmt.Errorf("failed to unpack Protobuf response: %w", err)
+	}
+	switch versionResponse.GetResult().(type) {
+	case *pb.ApiVersionResponse_Error:
+		return nil, versionResponse.GetError().UnpackProtoMessage()
+	case *pb.ApiVersionResponse_Data:
+		return versionResponse.GetData(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+type negotiatedApiVersionKey struct {
+	peerId  network.PeerID
+	shardId types.ShardId
+	apiName string
+}
+
+// negotiatedApiVersions caches the results of the handshakes. A version is forgotten if a request using it fails,
+// since the peer may have been restarted with another version.
+var negotiatedApiVersions = mustCreate(lru.New[negotiatedApiVersionKey, string](maxNegotiatedPeers))
+
+// discoverVersionedProtocol finds a peer serving the method of the shard and the ID of the method
+// in the version negotiated with the peer. The peers not supporting the handshake are addressed
+// by the legacy protocol IDs.
+func discoverVersionedProtocol(
+	ctx context.Context,
+	networkManager network.Manager,
+	selectPeer PeerSelector,
+	shardId types.ShardId,
+	apiName string,
+	methodName string,
+) (network.PeerID, network.ProtocolID, error) {
+	peers := networkManager.GetPeersForProtocol(makeProtocolId(shardId, apiName, getApiVersionMethodName))
+	if len(peers) == 0 {
+		protocol := makeProtocolId(shardId, apiName, methodName)
+		peerId, err := discoverAppropriatePeer(networkManager, selectPeer, shardId, protocol)
+		return peerId, protocol, err
+	}
+
+	peerId, err := selectPeer(peers)
+	if err != nil {
+		return "", "", err
+	}
+	protocol, err := negotiateVersionedProtocol(ctx, networkManager, peerId, shardId, apiName, methodName)
+	if err != nil {
+		return "", "", err
+	}
+	return peerId, protocol, nil
+}
+
+// discoverShardApiPeers returns the peers serving the API of the shard. The peers supporting the handshake
+// are preferred, the peers serving the legacy protocol of the method are returned if there are none.
+func discoverShardApiPeers(
+	networkManager network.Manager,
+	shardId types.ShardId,
+	apiName string,
+	methodName string,
+) []network.PeerID {
+	peers := networkManager.GetPeersForProtocol(makeProtocolId(shardId, apiName, getApiVersionMethodName))
+	if len(peers) != 0 {
+		return peers
+	}
+	return networkManager.GetPeersForProtocol(makeProtocolId(shardId, apiName, methodName))
+}
+
+// resolvePeerProtocol returns the ID of the method served by the peer, which is versioned
+// if the peer supports the handshake.
+func resolvePeerProtocol(
+	ctx context.Context,
+	networkManager network.Manager,
+	peerId network.PeerID,
+	shardId types.ShardId,
+	apiName string,
+	methodName string,
+) (network.ProtocolID, error) {
+	versionPeers := networkManager.GetPeersForProtocol(makeProtocolId(shardId, apiName, getApiVersionMethodName))
+	if !slices.Contains(versionPeers, peerId) {
+		return makeProtocolId(shardId, apiName, methodName), nil
+	}
+	return negotiateVersionedProtocol(ctx, networkManager, peerId, shardId, apiName, methodName)
+}
+
+func negotiateVersionedProtocol(
+	ctx context.Context,
+	networkManager network.Manager,
+	peerId network.PeerID,
+	shardId types.ShardId,
+	apiName string,
+	methodName string,
+) (network.ProtocolID, error) {
+	key := negotiatedApiVersionKey{peerId: peerId, shardId: shardId, apiName: apiName}
+	version, ok := negotiatedApiVersions.Get(key)
+	if !ok {
+		apiVersion, err := getApiVersion(ctx, networkManager, peerId, shardId, apiName)
+		if err != nil {
+			return "", fmt.Errorf("failed to negotiate API version: %w", err)
+		}
+		version = apiVersion.GetVersion()
+		if version == "" {
+			return "", fmt.Errorf("%w: peer %s supports %v, the node supports %v",
+				errNoCompatibleApiVersion, peerId, apiVersion.GetSupportedVersions(), supportedApiVersions)
+		}
+		negotiatedApiVersions.Add(key, version)
+	}
+	return makeVersionedProtocolId(shardId, apiName, version, methodName), nil
+}
+
+func forgetApiVersion(peerId network.PeerID, shardId types.ShardId, apiName string) {
+	negotiatedApiVersions.Remove(negotiatedApiVersionKey{peerId: peerId, shardId: shardId, apiName: apiName})
+}
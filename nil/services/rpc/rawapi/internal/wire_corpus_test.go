@@ -0,0 +1,319 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var updateWireCorpus = flag.Bool(
+	"update-wire-corpus", false, "add the samples of the methods missing from the wire corpus to testdata/wire")
+
+// maxSampleDepth limits the nesting of the generated sample values, e.g., of the recursive types.
+const maxSampleDepth = 8
+
+// wireSample is a request of a method and a response to it packed by a revision of the schema.
+type wireSample struct {
+	Request  []byte `json:"request,omitempty"`
+	Response []byte `json:"response"`
+}
+
+// wireCorpus keeps the samples of the methods of an API in a version by method name. The samples are only
+// added, never updated, so that they keep the payloads of the revision of the schema that introduced them.
+type wireCorpus map[string]wireSample
+
+func wireCorpusPath(version string, apiName string) string {
+	return filepath.Join("testdata", "wire", version, apiName+".json")
+}
+
+// readWireCorpus returns nil if the corpus doesn't exist.
+func readWireCorpus(path string) (wireCorpus, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var corpus wireCorpus
+	if err := json.Unmarshal(data, &corpus); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return corpus, nil
+}
+
+func writeWireCorpus(path string, corpus wireCorpus) error {
+	data, err := json.MarshalIndent(corpus, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// sampleValue returns a deterministic value of the type with all the exported fields set,
+// so that the packed samples contain the fields of the messages.
+func sampleValue(t reflect.Type, depth int) reflect.Value {
+	value := reflect.New(t).Elem()
+	if depth > maxSampleDepth {
+		return value
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		value.SetFloat(1)
+	case reflect.String:
+		value.SetString("sample")
+	case reflect.Array:
+		for i := range value.Len() {
+			value.Index(i).Set(sampleValue(t.Elem(), depth+1))
+		}
+	case reflect.Slice:
+		value.Set(reflect.Append(reflect.MakeSlice(t, 0, 1), sampleValue(t.Elem(), depth+1)))
+	case reflect.Map:
+		value.Set(reflect.MakeMap(t))
+		value.SetMapIndex(sampleValue(t.Key(), depth+1), sampleValue(t.Elem(), depth+1))
+	case reflect.Pointer:
+		value.Set(reflect.New(t.Elem()))
+		value.Elem().Set(sampleValue(t.Elem(), depth+1))
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if t.Field(i).IsExported() {
+				value.Field(i).Set(sampleValue(t.Field(i).Type, depth+1))
+			}
+		}
+	case reflect.Interface, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128,
+		reflect.UnsafePointer, reflect.Invalid:
+	}
+	return value
+}
+
+// generateWireSample packs the sample arguments and the sample result of the method the way the version sends them.
+// The sample that can't be packed or unpacked back is not generated, e.g., if the values fail the validation.
+func generateWireSample(apiMethod reflect.Method, codec *methodCodec, version string) (sample wireSample, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	// The first argument of the API method is the context.
+	args := make([]any, 0, apiMethod.Type.NumIn()-1)
+	for i := 1; i < apiMethod.Type.NumIn(); i++ {
+		args = append(args, sampleValue(apiMethod.Type.In(i), 0).Interface())
+	}
+	if sample.Request, err = codec.packRequest(args...); err != nil {
+		return sample, err
+	}
+	if _, err := codec.unpackRequest(sample.Request); err != nil {
+		return sample, err
+	}
+
+	if sszCodec := codec.sszEncoding(); sszCodec != nil && version == sszApiVersion {
+		codec = sszCodec
+	}
+	noError := reflect.Zero(reflect.TypeFor[error]())
+	if codec.kind == subscriptionResponse {
+		// A sample of the subscription is one of its events.
+		sample.Response, err = codec.packSingleResponse(sampleValue(codec.packedResultType(), 0), noError)
+	} else {
+		sample.Response, err = codec.packResponse(sampleValue(codec.apiMethodResultType, 0), noError)
+	}
+	if err != nil {
+		return sample, err
+	}
+	if _, err := unpackSampleResponse(codec, sample.Response); err != nil {
+		return sample, err
+	}
+	return sample, nil
+}
+
+func unpackSampleResponse(codec *methodCodec, response []byte) (any, error) {
+	if codec.kind == subscriptionResponse {
+		return codec.unpackSingleResponse(response)
+	}
+	return codec.unpackResponse(response)
+}
+
+// responseMessages returns the messages of the response, the frames of a streaming one.
+func responseMessages(codec *methodCodec, response []byte) ([][]byte, error) {
+	if codec.kind != streamingResponse {
+		return [][]byte{response}, nil
+	}
+	return splitFrames(response)
+}
+
+func newWireMessage(messageType reflect.Type) protoreflect.Message {
+	message, _ := reflect.New(messageType).Interface().(proto.Message)
+	return message.ProtoReflect()
+}
+
+// requireFieldsKept checks that the message packed by the current revision has all the fields of the one
+// packed by an earlier revision with the same values, so that the readers of the earlier revision get them.
+// The SSZ responses are compared byte by byte.
+func requireFieldsKept(t *testing.T, messageType reflect.Type, old, packed []byte) {
+	t.Helper()
+
+	if len(old) != 0 && old[0] == sszResponseMarker || len(packed) != 0 && packed[0] == sszResponseMarker {
+		require.Equal(t, old, packed, "SSZ encoding changed")
+		return
+	}
+	oldMessage, packedMessage := newWireMessage(messageType), newWireMessage(messageType)
+	require.NoError(t, proto.Unmarshal(old, oldMessage.Interface()))
+	require.NoError(t, proto.Unmarshal(packed, packedMessage.Interface()))
+	require.True(t, messageFieldsKept(oldMessage, packedMessage),
+		"fields of the earlier revision are lost:\nearlier: %v\ncurrent: %v", oldMessage, packedMessage)
+}
+
+func messageFieldsKept(old, packed protoreflect.Message) bool {
+	// The fields unknown to the current schema were removed or renumbered.
+	if len(old.GetUnknown()) != 0 {
+		return false
+	}
+	kept := true
+	old.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		kept = packed.Has(field) && fieldValueKept(field, value, packed.Get(field))
+		return kept
+	})
+	return kept
+}
+
+func fieldValueKept(field protoreflect.FieldDescriptor, old, packed protoreflect.Value) bool {
+	switch {
+	case field.IsList():
+		oldList, packedList := old.List(), packed.List()
+		if oldList.Len() != packedList.Len() {
+			return false
+		}
+		for i := range oldList.Len() {
+			if !singularValueKept(field, oldList.Get(i), packedList.Get(i)) {
+				return false
+			}
+		}
+		return true
+	case field.IsMap():
+		packedMap := packed.Map()
+		kept := true
+		old.Map().Range(func(key protoreflect.MapKey, value protoreflect.Value) bool {
+			kept = packedMap.Has(key) && singularValueKept(field.MapValue(), value, packedMap.Get(key))
+			return kept
+		})
+		return kept
+	}
+	return singularValueKept(field, old, packed)
+}
+
+func singularValueKept(field protoreflect.FieldDescriptor, old, packed protoreflect.Value) bool {
+	switch field.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageFieldsKept(old.Message(), packed.Message())
+	case protoreflect.BytesKind:
+		return bytes.Equal(old.Bytes(), packed.Bytes())
+	}
+	return old.Interface() == packed.Interface()
+}
+
+// TestWireCompatibility checks that the current revision of the schema decodes the payloads stored
+// in testdata/wire by the earlier revisions for all the served versions, and that the readers of the earlier
+// revisions of the read-only API still get all the fields of the responses they know. The samples of the new
+// methods are added by running the test with -update-wire-corpus, the existing samples are never changed.
+func TestWireCompatibility(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range supportedApiVersions {
+		for _, served := range servedApis {
+			t.Run(version+"/"+served.name, func(t *testing.T) {
+				t.Parallel()
+
+				codec, err := newApiCodec(served.api, served.transport)
+				require.NoError(t, err)
+				generated := make(wireCorpus)
+				for apiMethod := range common.Filter(iterMethods(served.api), isExportedMethod) {
+					sample, err := generateWireSample(apiMethod, codec[apiMethod.Name], version)
+					if err != nil {
+						t.Logf("no sample of %s is generated: %v", apiMethod.Name, err)
+						continue
+					}
+					generated[apiMethod.Name] = sample
+				}
+
+				path := wireCorpusPath(version, served.name)
+				corpus, err := readWireCorpus(path)
+				require.NoError(t, err)
+				if *updateWireCorpus {
+					if corpus == nil {
+						corpus = make(wireCorpus)
+					}
+					for methodName, sample := range generated {
+						if _, ok := corpus[methodName]; !ok {
+							corpus[methodName] = sample
+						}
+					}
+					require.NoError(t, writeWireCorpus(path, corpus))
+				}
+				require.NotNil(t, corpus, "no wire corpus at %s, run the test with -update-wire-corpus", path)
+
+				for methodName := range generated {
+					if _, ok := corpus[methodName]; !ok {
+						t.Errorf("no sample of %s in %s, run the test with -update-wire-corpus", methodName, path)
+					}
+				}
+				for _, methodName := range slices.Sorted(maps.Keys(corpus)) {
+					sample := corpus[methodName]
+					t.Run(methodName, func(t *testing.T) {
+						methodCodec, ok := codec[methodName]
+						require.True(t, ok, "method %s is removed while version %s is served", methodName, version)
+
+						if methodCodec.pbRequestType != nil {
+							values, err := methodCodec.unpackRequest(sample.Request)
+							require.NoError(t, err)
+							args := make([]any, len(values))
+							for i, value := range values {
+								args[i] = value.Interface()
+							}
+							repacked, err := methodCodec.packRequest(args...)
+							require.NoError(t, err)
+							requireFieldsKept(t, methodCodec.pbRequestType, sample.Request, repacked)
+						}
+
+						_, err := unpackSampleResponse(methodCodec, sample.Response)
+						require.NoError(t, err)
+
+						fresh, ok := generated[methodName]
+						if served.name != apiNameRo || !ok {
+							return
+						}
+						oldMessages, err := responseMessages(methodCodec, sample.Response)
+						require.NoError(t, err)
+						freshMessages, err := responseMessages(methodCodec, fresh.Response)
+						require.NoError(t, err)
+						require.Len(t, freshMessages, len(oldMessages))
+						for i := range oldMessages {
+							requireFieldsKept(t, methodCodec.pbResponseType, oldMessages[i], freshMessages[i])
+						}
+					})
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+)
+
+const (
+	// defaultWorkerQueueSize is the number of the requests of a priority class waiting for a worker
+	// if the queue size of the class is not set.
+	defaultWorkerQueueSize = 1024
+	// workerQueueRetryAfter is suggested to the clients whose requests are rejected by the full queue.
+	workerQueueRetryAfter = time.Second
+)
+
+// RequestPriority is the class of the requests of a raw API method. When all the workers are busy,
+// the waiting requests of the higher classes are served first.
+type RequestPriority uint8
+
+const (
+	// CriticalRequestPriority is of the reads the consensus and the synchronization of the nodes depend on,
+	// e.g., of the block headers.
+	CriticalRequestPriority RequestPriority = iota
+	// UserRequestPriority is of the requests of the users, e.g., Call. It is the default one.
+	UserRequestPriority
+	// DebugRequestPriority is of the expensive debugging requests, e.g., of the traces.
+	DebugRequestPriority
+
+	numRequestPriorities
+)
+
+var requestPriorityNames = [numRequestPriorities]string{"critical", "user", "debug"}
+
+func (p RequestPriority) String() string {
+	if p < numRequestPriorities {
+		return requestPriorityNames[p]
+	}
+	return fmt.Sprintf("RequestPriority(%d)", p)
+}
+
+func (p RequestPriority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *RequestPriority) UnmarshalText(text []byte) error {
+	for i, name := range requestPriorityNames {
+		if string(text) == name {
+			*p = RequestPriority(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown request priority %q", text)
+}
+
+// methodPriorities are the default priorities of the methods, the rest of the methods of the debug API
+// are of DebugRequestPriority and the others are of UserRequestPriority.
+var methodPriorities = map[string]RequestPriority{
+	getApiVersionMethodName:      CriticalRequestPriority,
+	"GetBlockHeader":             CriticalRequestPriority,
+	"GetFullBlockData":           CriticalRequestPriority,
+	"GetBlockFinalitySignatures": CriticalRequestPriority,
+	"GetSnapshotManifest":        CriticalRequestPriority,
+	"GetSnapshotChunk":           CriticalRequestPriority,
+	"GetLatestBlocks":            CriticalRequestPriority,
+	"DoPanicOnShard":             DebugRequestPriority,
+}
+
+// WorkerClass limits the requests of a priority class.
+type WorkerClass struct {
+	// MaxWorkers is the number of the workers the requests of the class may occupy at the same time,
+	// all of them if zero.
+	MaxWorkers int `yaml:"maxWorkers,omitempty"`
+	// QueueSize is the number of the requests of the class waiting for a worker, the excess requests
+	// are rejected. It is defaultWorkerQueueSize if zero.
+	QueueSize int `yaml:"queueSize,omitempty"`
+}
+
+// WorkerPoolConfig configures the workers serving the raw API requests.
+type WorkerPoolConfig struct {
+	// Workers is the number of the requests handled at the same time by all the methods.
+	Workers int `yaml:"workers"`
+	// Classes limit the requests of the priority classes, e.g., "debug: {maxWorkers: 2}".
+	Classes map[RequestPriority]WorkerClass `yaml:"classes,omitempty"`
+	// Priorities override the default priorities of the methods by protocol ID or method name.
+	Priorities map[string]RequestPriority `yaml:"priorities,omitempty"`
+}
+
+// Interceptor returns the interceptor handling the requests of all the protocols it is applied to
+// on the workers of the pool instead of the goroutines of the network. The requests are queued by priority
+// if all the workers are busy, and rejected with rawapitypes.ErrOverloaded if the queue of their class is full.
+// The streams and the subscriptions are served on the workers as well, and occupy them while they are open.
+func (p *WorkerPool) Interceptor() RequestInterceptor {
+	return func(_ context.Context, protocol network.ProtocolID, next network.RequestHandler) network.RequestHandler {
+		if p.workers <= 0 {
+			return next
+		}
+		priority := p.priorityOf(protocol)
+		return func(ctx context.Context, request []byte) ([]byte, error) {
+			return p.handle(ctx, priority, next, request)
+		}
+	}
+}
+
+func (p *WorkerPool) priorityOf(protocol network.ProtocolID) RequestPriority {
+	return requestPriorityOf(p.priorities, protocol)
+}
+
+// requestPriorityOf returns the priority of the protocol, the given priorities override the default ones.
+func requestPriorityOf(priorities map[string]RequestPriority, protocol network.ProtocolID) RequestPriority {
+	if priority, ok := findProtocolEntry(priorities, protocol); ok {
+		return priority
+	}
+	if priority, ok := methodPriorities[path.Base(string(protocol))]; ok {
+		return priority
+	}
+	if path.Base(path.Dir(string(protocol))) == apiNameDebug {
+		return DebugRequestPriority
+	}
+	return UserRequestPriority
+}
+
+type workerResult struct {
+	response []byte
+	err      error
+}
+
+type workerTask struct {
+	ctx     context.Context
+	handler network.RequestHandler
+	request []byte
+	// result is buffered, so that the worker doesn't wait for the caller that has gone away.
+	result chan workerResult
+}
+
+// WorkerPool serves the raw API requests on a bounded number of workers by the priorities of the methods.
+// The requests are queued until the workers are run by Run.
+type WorkerPool struct {
+	workers    int
+	classes    [numRequestPriorities]WorkerClass
+	priorities map[string]RequestPriority
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	stopped bool
+	queues  [numRequestPriorities][]*workerTask
+	running [numRequestPriorities]int
+}
+
+func NewWorkerPool(cfg WorkerPoolConfig) *WorkerPool {
+	p := &WorkerPool{workers: cfg.Workers, priorities: cfg.Priorities}
+	p.cond = sync.NewCond(&p.mu)
+	for priority := range numRequestPriorities {
+		class := cfg.Classes[priority]
+		if class.MaxWorkers <= 0 || class.MaxWorkers > cfg.Workers {
+			class.MaxWorkers = cfg.Workers
+		}
+		if class.QueueSize <= 0 {
+			class.QueueSize = defaultWorkerQueueSize
+		}
+		p.classes[priority] = class
+	}
+	return p
+}
+
+// Run runs the workers until the context is done, the requests queued then are failed with its error
+// and the new ones are rejected.
+func (p *WorkerPool) Run(ctx context.Context) error {
+	for range p.workers {
+		go p.work()
+	}
+	<-ctx.Done()
+	p.stop(ctx.Err())
+	return nil
+}
+
+func (p *WorkerPool) stop(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stopped = true
+	for priority := range p.queues {
+		for _, task := range p.queues[priority] {
+			task.result <- workerResult{err: err}
+		}
+		p.queues[priority] = nil
+	}
+	p.cond.Broadcast()
+}
+
+func (p *WorkerPool) handle(
+	ctx context.Context, priority RequestPriority, handler network.RequestHandler, request []byte,
+) ([]byte, error) {
+	task := &workerTask{ctx: ctx, handler: handler, request: request, result: make(chan workerResult, 1)}
+	if !p.submit(priority, task) {
+		return nil, rawapitypes.NewOverloadedError(workerQueueRetryAfter)
+	}
+	select {
+	case result := <-task.result:
+		return result.response, result.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *WorkerPool) submit(priority RequestPriority, task *workerTask) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stopped || len(p.queues[priority]) >= p.classes[priority].QueueSize {
+		return false
+	}
+	p.queues[priority] = append(p.queues[priority], task)
+	p.cond.Signal()
+	return true
+}
+
+func (p *WorkerPool) work() {
+	for {
+		priority, task, ok := p.next()
+		if !ok {
+			return
+		}
+		// The caller doesn't wait for the result anymore, e.g., the request has timed out in the queue.
+		if err := task.ctx.Err(); err != nil {
+			task.result <- workerResult{err: err}
+		} else {
+			response, err := task.handler(task.ctx, task.request)
+			task.result <- workerResult{response: response, err: err}
+		}
+		p.finish(priority)
+	}
+}
+
+// next waits for a request of the highest priority class whose requests don't occupy all their workers.
+func (p *WorkerPool) next() (RequestPriority, *workerTask, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for !p.stopped {
+		for priority := range numRequestPriorities {
+			queue := p.queues[priority]
+			if len(queue) == 0 || p.running[priority] >= p.classes[priority].MaxWorkers {
+				continue
+			}
+			task := queue[0]
+			queue[0] = nil
+			p.queues[priority] = queue[1:]
+			p.running[priority]++
+			return priority, task, true
+		}
+		p.cond.Wait()
+	}
+	return 0, nil, false
+}
+
+func (p *WorkerPool) finish(priority RequestPriority) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.running[priority]--
+	// The requests of the class may have waited for its workers.
+	p.cond.Broadcast()
+}
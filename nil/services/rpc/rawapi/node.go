@@ -4,6 +4,108 @@ import (
 	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/internal"
 )
 
-type NodeApi = internal.NodeApi
+type (
+	NodeApi               = internal.NodeApi
+	RequestInterceptor    = internal.RequestInterceptor
+	RequestHandlersConfig = internal.RequestHandlersConfig
+	RateLimit             = internal.RateLimit
+	RateLimits            = internal.RateLimits
+	MethodTimeouts        = internal.MethodTimeouts
+	ShardApiRo            = internal.ShardApiRo
+	ShardApiRw            = internal.ShardApiRw
+	ShardApi              = internal.ShardApi
+	PeerSelector          = internal.PeerSelector
+	MultiPeerConfig       = internal.MultiPeerConfig
+	ShardPeerDirectory    = internal.ShardPeerDirectory
+	ResponseCacheConfig   = internal.ResponseCacheConfig
+	RequestAuth           = internal.RequestAuth
+	AdminApi              = internal.AdminApi
+	AdminApiConfig        = internal.AdminApiConfig
+	DbApi                 = internal.DbApi
+	DbApiConfig           = internal.DbApiConfig
+	ConsensusApi          = internal.ConsensusApi
+	ConsensusApiConfig    = internal.ConsensusApiConfig
+	ShardConsensus        = internal.ShardConsensus
+	PeerAcl               = internal.PeerAcl
+	AccessControl         = internal.AccessControl
+	RequestLogConfig      = internal.RequestLogConfig
+	RequestSizeLimits     = internal.RequestSizeLimits
+	SyncProgressSource    = internal.SyncProgressSource
+	NewBlocksSource       = internal.NewBlocksSource
+	RecordedRequest       = internal.RecordedRequest
+	RequestRecorder       = internal.RequestRecorder
+	RequestRing           = internal.RequestRing
+	RequestFileRecorder   = internal.RequestFileRecorder
+	ReplayReport          = internal.ReplayReport
+	ReplayMismatch        = internal.ReplayMismatch
+	ProofApi              = internal.ProofApi
+	StateProofSource      = internal.StateProofSource
+	GrpcGateway           = internal.GrpcGateway
+	GrpcGatewayConfig     = internal.GrpcGatewayConfig
+	ClusterApi            = internal.ClusterApi
+	FaucetApi             = internal.FaucetApi
+	FaucetApiConfig       = internal.FaucetApiConfig
+	DevnetApi             = internal.DevnetApi
+	DevnetApiConfig       = internal.DevnetApiConfig
+	ShardChainControl     = internal.ShardChainControl
+	RequestPriority       = internal.RequestPriority
+	WorkerClass           = internal.WorkerClass
+	WorkerPoolConfig      = internal.WorkerPoolConfig
+	WorkerPool            = internal.WorkerPool
+	LoadSheddingConfig    = internal.LoadSheddingConfig
+	SheddingThresholds    = internal.SheddingThresholds
+	CachingClientConfig   = internal.CachingClientConfig
+	SupportedMethod       = internal.SupportedMethod
+	PeerQuota             = internal.PeerQuota
+	PeerQuotaConfig       = internal.PeerQuotaConfig
+	PeerQuotas            = internal.PeerQuotas
+	RequestSigner         = internal.RequestSigner
+	ResponseBlock         = internal.ResponseBlock
+	ShardApiRoBatch       = internal.ShardApiRoBatch
+)
+
+var (
+	NodeApiBuilder               = internal.NodeApiBuilder
+	NewRateLimitInterceptor      = internal.NewRateLimitInterceptor
+	NewTimeoutInterceptor        = internal.NewTimeoutInterceptor
+	NewRequestSizeInterceptor    = internal.NewRequestSizeInterceptor
+	NewWorkerPool                = internal.NewWorkerPool
+	NewLoadSheddingInterceptor   = internal.NewLoadSheddingInterceptor
+	NewNetworkShardApiClient     = internal.NewNetworkShardApiClient
+	NewNetworkShardApiRoBatch    = internal.NewNetworkShardApiRoBatch
+	NewMultiPeerShardApiClient   = internal.NewMultiPeerShardApiClient
+	NewCachingShardApiClient     = internal.NewCachingShardApiClient
+	DefaultCachingClientConfig   = internal.DefaultCachingClientConfig
+	NewShardPeerDirectory        = internal.NewShardPeerDirectory
+	FetchShardSnapshot           = internal.FetchShardSnapshot
+	FetchSnapshot                = internal.FetchSnapshot
+	NewAuthInterceptor           = internal.NewAuthInterceptor
+	NewAccessControl             = internal.NewAccessControl
+	WithAuthToken                = internal.WithAuthToken
+	WithRequestSigningKey        = internal.WithRequestSigningKey
+	GetRequestSigner             = internal.GetRequestSigner
+	WithIfBlockChanged           = internal.WithIfBlockChanged
+	WithResponseBlock            = internal.WithResponseBlock
+	NewNetworkAdminApiClient     = internal.NewNetworkAdminApiClient
+	NewNetworkDbApiClient        = internal.NewNetworkDbApiClient
+	NewNetworkConsensusApiClient = internal.NewNetworkConsensusApiClient
+	NewRecordingInterceptor      = internal.NewRecordingInterceptor
+	NewRequestRing               = internal.NewRequestRing
+	NewRequestFileRecorder       = internal.NewRequestFileRecorder
+	ReadRecordedRequests         = internal.ReadRecordedRequests
+	ReplayRecordedRequests       = internal.ReplayRecordedRequests
+	NewNetworkProofApiClient     = internal.NewNetworkProofApiClient
+	NewGrpcGateway               = internal.NewGrpcGateway
+	NewNetworkClusterApiClient   = internal.NewNetworkClusterApiClient
+	NewNetworkFaucetApiClient    = internal.NewNetworkFaucetApiClient
+	NewNetworkDevnetApiClient    = internal.NewNetworkDevnetApiClient
+	ListSupportedMethods         = internal.ListSupportedMethods
+	NewPeerQuotas                = internal.NewPeerQuotas
+)
+
+type (
+	TransactionTracer = internal.TransactionTracer
+	TransactionTrace  = internal.TransactionTrace
+)
 
-var NodeApiBuilder = internal.NodeApiBuilder
+var NewTransactionTracer = internal.NewTransactionTracer
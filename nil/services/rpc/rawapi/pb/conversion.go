@@ -3,6 +3,9 @@ package pb
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 	"unicode/utf8"
 
 	"github.com/NilFoundation/nil/nil/common"
@@ -11,10 +14,15 @@ import (
 	"github.com/NilFoundation/nil/nil/common/logging"
 	"github.com/NilFoundation/nil/nil/common/sszx"
 	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
 	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/google/uuid"
+	ma "github.com/multiformats/go-multiaddr"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 )
 
 var Logger = logging.NewLogger("pb_conversion")
@@ -61,10 +69,17 @@ func (nbr *NamedBlockReference) UnpackProtoMessage() (rawapitypes.NamedBlockIden
 	case NamedBlockReference_PendingBlock:
 		return rawapitypes.PendingBlock, nil
 
+	case NamedBlockReference_SafeBlock:
+		return rawapitypes.SafeBlock, nil
+
+	case NamedBlockReference_FinalizedBlock:
+		return rawapitypes.FinalizedBlock, nil
+
 	case NamedBlockReference_UnknownNamedRefType:
 		fallthrough
 	default:
-		return 0, errors.New("unexpected named block reference type")
+		return 0, rawapitypes.NewError(
+			rawapitypes.InvalidBlockReferenceErrorCode, errors.New("unexpected named block reference type"))
 	}
 }
 
@@ -79,6 +94,12 @@ func (nbr *NamedBlockReference) PackProtoMessage(namedBlockIdentifier rawapitype
 	case rawapitypes.PendingBlock:
 		*nbr = NamedBlockReference_PendingBlock
 
+	case rawapitypes.SafeBlock:
+		*nbr = NamedBlockReference_SafeBlock
+
+	case rawapitypes.FinalizedBlock:
+		*nbr = NamedBlockReference_FinalizedBlock
+
 	default:
 		return errors.New("unexpected named block reference type")
 	}
@@ -103,7 +124,8 @@ func (br *BlockReference) UnpackProtoMessage() (rawapitypes.BlockReference, erro
 		return rawapitypes.NamedBlockIdentifierAsBlockReference(namedBlockReference), nil
 
 	default:
-		return rawapitypes.BlockReference{}, errors.New("unexpected block reference type")
+		return rawapitypes.BlockReference{}, rawapitypes.NewError(
+			rawapitypes.InvalidBlockReferenceErrorCode, errors.New("unexpected block reference type"))
 	}
 }
 
@@ -187,17 +209,58 @@ func (ar *AccountRequest) PackProtoMessage(address types.Address, blockReference
 // Error converters
 
 func (e *Error) UnpackProtoMessage() error {
+	err := errors.New(e.GetMessage())
 	if e.GetMessage() == db.ErrKeyNotFound.Error() {
-		return db.ErrKeyNotFound
+		err = db.ErrKeyNotFound
+	}
+	if e.GetCode() == ErrorCode_UnknownErrorCode {
+		return err
+	}
+	return &rawapitypes.Error{
+		Code:                   rawapitypes.ErrorCode(e.GetCode()),
+		IncidentId:             e.GetIncidentId(),
+		RevertData:             e.GetRevertData(),
+		EarliestAvailableBlock: types.BlockNumber(e.GetEarliestAvailableBlock()),
+		RetryAfter:             time.Duration(e.GetRetryAfter()) * time.Millisecond,
+		Err:                    err,
 	}
-	return errors.New(e.GetMessage())
 }
 
 func (e *Error) PackProtoMessage(err error) *Error {
 	e.Message = err.Error()
+	e.Code = ErrorCode(rawapitypes.ErrorCodeOf(err))
+	var apiErr *rawapitypes.Error
+	if errors.As(err, &apiErr) {
+		e.IncidentId = apiErr.IncidentId
+		e.RevertData = apiErr.RevertData
+		e.EarliestAvailableBlock = uint64(apiErr.EarliestAvailableBlock)
+		if apiErr.RetryAfter > 0 {
+			e.RetryAfter = uint64(max(apiErr.RetryAfter.Milliseconds(), 1))
+		}
+	}
+	return e
+}
+
+// RequestEnvelope converters
+
+func (e *RequestEnvelope) PackProtoMessage(
+	payload []byte, timeout time.Duration, acceptedCompression Compression, acceptChunked bool,
+) *RequestEnvelope {
+	e.Payload = payload
+	e.Enveloped = true
+	if timeout > 0 {
+		e.Timeout = uint64(max(timeout.Milliseconds(), 1))
+	}
+	e.AcceptedCompression = acceptedCompression
+	e.AcceptChunked = acceptChunked
 	return e
 }
 
+func (e *RequestEnvelope) UnpackProtoMessage() ([]byte, time.Duration, Compression, bool) {
+	timeout := time.Duration(e.GetTimeout()) * time.Millisecond
+	return e.GetPayload(), timeout, e.GetAcceptedCompression(), e.GetAcceptChunked()
+}
+
 // Map of Errors converters
 
 func packErrorMap(errors map[common.Hash]string) map[string]*Error {
@@ -370,1059 +433,4617 @@ func (br *RawFullBlockResponse) UnpackProtoMessage() (*types.RawBlockWithExtract
 	}
 }
 
-// Uint64Response converters
-func (br *Uint64Response) PackProtoMessage(count uint64, err error) error {
-	br.Result = &Uint64Response_Count{Count: count}
+// MarshalRawFullBlockResponse encodes the block into RawFullBlockResponse the same way as PackProtoMessage
+// followed by proto.Marshal does, but the stored encodings of the block are copied into the output directly
+// without building the intermediate messages. Only the small fields are marshalled as a message, the Protobuf
+// decoder merges its encoding with the rest of the fields.
+func MarshalRawFullBlockResponse(block *types.RawBlockWithExtractedData) ([]byte, error) {
+	if block == nil {
+		var response RawFullBlockResponse
+		response.fromData(nil)
+		return proto.Marshal(&response)
+	}
+
+	rest, err := proto.Marshal(&RawFullBlock{
+		Errors:      packErrorMap(block.Errors),
+		ChildBlocks: PackHashes(block.ChildBlocks),
+		DbTimestamp: block.DbTimestamp,
+	})
 	if err != nil {
-		br.Result = &Uint64Response_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil, err
 	}
-	return nil
-}
 
-func (br *Uint64Response) UnpackProtoMessage() (uint64, error) {
-	switch br.GetResult().(type) {
-	case *Uint64Response_Error:
-		return 0, br.GetError().UnpackProtoMessage()
-	case *Uint64Response_Count:
-		return br.GetCount(), nil
-	default:
-		return 0, errors.New("unexpected response type")
+	size := len(rest)
+	if len(block.Block) > 0 {
+		size += protowire.SizeTag(1) + protowire.SizeBytes(len(block.Block))
+	}
+	for _, field := range rawFullBlockRepeatedFields(block) {
+		for _, data := range field.values {
+			size += protowire.SizeTag(field.number) + protowire.SizeBytes(len(data))
+		}
+	}
+	for key, value := range block.Config {
+		size += protowire.SizeTag(8) + protowire.SizeBytes(configEntrySize(key, value))
+	}
+
+	response := make([]byte, 0, protowire.SizeTag(2)+protowire.SizeBytes(size))
+	response = protowire.AppendTag(response, 2, protowire.BytesType)
+	response = protowire.AppendVarint(response, uint64(size))
+	if len(block.Block) > 0 {
+		response = protowire.AppendTag(response, 1, protowire.BytesType)
+		response = protowire.AppendBytes(response, block.Block)
+	}
+	for _, field := range rawFullBlockRepeatedFields(block) {
+		for _, data := range field.values {
+			response = protowire.AppendTag(response, field.number, protowire.BytesType)
+			response = protowire.AppendBytes(response, data)
+		}
 	}
+	for key, value := range block.Config {
+		response = protowire.AppendTag(response, 8, protowire.BytesType)
+		response = protowire.AppendVarint(response, uint64(configEntrySize(key, value)))
+		response = protowire.AppendTag(response, 1, protowire.BytesType)
+		response = protowire.AppendString(response, key)
+		response = protowire.AppendTag(response, 2, protowire.BytesType)
+		response = protowire.AppendBytes(response, value)
+	}
+	return append(response, rest...), nil
 }
 
-// StringResponse converters
-func (br *StringResponse) PackProtoMessage(value string, err error) error {
-	br.Result = &StringResponse_Value{Value: value}
-	if err != nil {
-		br.Result = &StringResponse_Error{Error: new(Error).PackProtoMessage(err)}
+type rawFullBlockRepeatedField struct {
+	number protowire.Number
+	values []sszx.SSZEncodedData
+}
+
+func rawFullBlockRepeatedFields(block *types.RawBlockWithExtractedData) []rawFullBlockRepeatedField {
+	return []rawFullBlockRepeatedField{
+		{2, block.InTransactions},
+		{3, block.OutTransactions},
+		{4, block.Receipts},
+		{9, block.InTxCounts},
+		{10, block.OutTxCounts},
 	}
+}
+
+func configEntrySize(key string, value []byte) int {
+	return protowire.SizeTag(1) + protowire.SizeBytes(len(key)) + protowire.SizeTag(2) + protowire.SizeBytes(len(value))
+}
+
+// BlockRangeRequest converters
+
+func (br *BlockRangeRequest) PackProtoMessage(from types.BlockNumber, count uint64, fullBlocks bool) error {
+	br.From = uint64(from)
+	br.Count = count
+	br.FullBlocks = fullBlocks
 	return nil
 }
 
-func (br *StringResponse) UnpackProtoMessage() (string, error) {
-	switch br.GetResult().(type) {
-	case *StringResponse_Error:
-		return "", br.GetError().UnpackProtoMessage()
-	case *StringResponse_Value:
-		return br.GetValue(), nil
-	default:
-		return "", errors.New("unexpected response type")
-	}
+func (br *BlockRangeRequest) UnpackProtoMessage() (types.BlockNumber, uint64, bool, error) {
+	return types.BlockNumber(br.GetFrom()), br.GetCount(), br.GetFullBlocks(), nil
 }
 
-func (br *BalanceResponse) PackProtoMessage(balance types.Value, err error) error {
+// ShardStatsRequest converters
+
+func (r *ShardStatsRequest) PackProtoMessage(from types.BlockNumber, count uint64) error {
+	r.From = uint64(from)
+	r.Count = count
+	return nil
+}
+
+func (r *ShardStatsRequest) UnpackProtoMessage() (types.BlockNumber, uint64, error) {
+	return types.BlockNumber(r.GetFrom()), r.GetCount(), nil
+}
+
+// ShardStatsResponse converters
+
+func (r *ShardStatsResponse) PackProtoMessage(stats *rawapitypes.ShardStats, err error) error {
 	if err != nil {
-		br.Result = &BalanceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &ShardStatsResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	if balance.Uint256 == nil {
-		balance.Uint256 = new(types.Uint256)
-	}
-	br.Result = &BalanceResponse_Data{Data: new(Uint256).PackProtoMessage(*balance.Uint256)}
+	r.Result = &ShardStatsResponse_Data{Data: &ShardStats{
+		FromBlock:           uint64(stats.FromBlock),
+		ToBlock:             uint64(stats.ToBlock),
+		BlockCount:          stats.BlockCount,
+		EmptyBlockCount:     stats.EmptyBlockCount,
+		GasUsed:             uint64(stats.GasUsed),
+		AverageGasUsed:      uint64(stats.AverageGasUsed),
+		MaxGasUsed:          uint64(stats.MaxGasUsed),
+		InTransactionCount:  stats.InTransactionCount,
+		OutTransactionCount: stats.OutTransactionCount,
+		CrossShardInCount:   stats.CrossShardInCount,
+		CrossShardOutCount:  stats.CrossShardOutCount,
+	}}
 	return nil
 }
 
-func (br *BalanceResponse) UnpackProtoMessage() (types.Value, error) {
-	switch br.GetResult().(type) {
-	case *BalanceResponse_Error:
-		return types.Value{}, br.GetError().UnpackProtoMessage()
+func (r *ShardStatsResponse) UnpackProtoMessage() (*rawapitypes.ShardStats, error) {
+	switch r.GetResult().(type) {
+	case *ShardStatsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
 
-	case *BalanceResponse_Data:
-		return newValueFromUint256(br.GetData()), nil
+	case *ShardStatsResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.ShardStats{
+			FromBlock:           types.BlockNumber(data.GetFromBlock()),
+			ToBlock:             types.BlockNumber(data.GetToBlock()),
+			BlockCount:          data.GetBlockCount(),
+			EmptyBlockCount:     data.GetEmptyBlockCount(),
+			GasUsed:             types.Gas(data.GetGasUsed()),
+			AverageGasUsed:      types.Gas(data.GetAverageGasUsed()),
+			MaxGasUsed:          types.Gas(data.GetMaxGasUsed()),
+			InTransactionCount:  data.GetInTransactionCount(),
+			OutTransactionCount: data.GetOutTransactionCount(),
+			CrossShardInCount:   data.GetCrossShardInCount(),
+			CrossShardOutCount:  data.GetCrossShardOutCount(),
+		}, nil
 
 	default:
-		return types.Value{}, errors.New("unexpected response type")
+		return nil, errors.New("unexpected response type")
 	}
 }
 
-// CodeResponse converters
-func (br *CodeResponse) PackProtoMessage(code types.Code, err error) error {
+// RawBlockRangeResponse converters
+
+func (br *RawBlockRangeResponse) PackProtoMessage(blocks []*types.RawBlockWithExtractedData, err error) error {
 	if err != nil {
-		br.Result = &CodeResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		br.Result = &RawBlockRangeResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	br.Result = &CodeResponse_Data{Data: code}
+	rawBlocks := &RawFullBlocks{Blocks: make([]*RawFullBlock, len(blocks))}
+	for i, block := range blocks {
+		rawBlocks.Blocks[i] = new(RawFullBlock)
+		if err := rawBlocks.GetBlocks()[i].PackProtoMessage(block); err != nil {
+			br.Result = &RawBlockRangeResponse_Error{Error: new(Error).PackProtoMessage(err)}
+			return nil
+		}
+	}
+	br.Result = &RawBlockRangeResponse_Data{Data: rawBlocks}
 	return nil
 }
 
-func (br *CodeResponse) UnpackProtoMessage() (types.Code, error) {
+func (br *RawBlockRangeResponse) UnpackProtoMessage() ([]*types.RawBlockWithExtractedData, error) {
 	switch br.GetResult().(type) {
-	case *CodeResponse_Error:
+	case *RawBlockRangeResponse_Error:
 		return nil, br.GetError().UnpackProtoMessage()
 
-	case *CodeResponse_Data:
-		return br.GetData(), nil
+	case *RawBlockRangeResponse_Data:
+		rawBlocks := br.GetData().GetBlocks()
+		blocks := make([]*types.RawBlockWithExtractedData, len(rawBlocks))
+		for i, rawBlock := range rawBlocks {
+			var err error
+			if blocks[i], err = rawBlock.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
+		}
+		return blocks, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
 	}
-	return nil, errors.New("unexpected response type")
 }
 
-// TokenResponse converters
-func (cr *TokensResponse) PackProtoMessage(tokens map[types.TokenId]types.Value, err error) error {
+// FinalitySignaturesResponse converters
+
+func (r *FinalitySignaturesResponse) PackProtoMessage(
+	signatures *rawapitypes.FinalitySignatures, err error,
+) error {
 	if err != nil {
-		cr.Result = &TokensResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &FinalitySignaturesResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	result := Tokens{Data: make(map[string]*Uint256)}
-	for k, v := range tokens {
-		result.Data[k.String()] = new(Uint256).PackProtoMessage(*v.Uint256)
+	data := &FinalitySignatures{
+		BlockHash:     new(Hash),
+		BlockNumber:   uint64(signatures.BlockNumber),
+		Round:         signatures.Round,
+		ProposerIndex: signatures.ProposerIndex,
+		Signature:     signatures.Signature,
+		SignersMask:   signatures.SignersMask,
+		PublicKeys:    signatures.PublicKeys,
+		Signers:       signatures.Signers,
+		Quorum:        signatures.Quorum,
 	}
-	cr.Result = &TokensResponse_Data{Data: &result}
+	if err := data.GetBlockHash().PackProtoMessage(signatures.BlockHash); err != nil {
+		return err
+	}
+	r.Result = &FinalitySignaturesResponse_Data{Data: data}
 	return nil
 }
 
-func (cr *TokensResponse) UnpackProtoMessage() (map[types.TokenId]types.Value, error) {
-	switch cr.GetResult().(type) {
-	case *TokensResponse_Error:
-		return nil, cr.GetError().UnpackProtoMessage()
+func (r *FinalitySignaturesResponse) UnpackProtoMessage() (*rawapitypes.FinalitySignatures, error) {
+	switch r.GetResult().(type) {
+	case *FinalitySignaturesResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
 
-	case *TokensResponse_Data:
-		data := cr.GetData().GetData()
-		result := make(map[types.TokenId]types.Value, len(data))
-		for k, v := range data {
-			tokenId := types.TokenId(types.HexToAddress(k))
-			result[tokenId] = newValueFromUint256(v)
+	case *FinalitySignaturesResponse_Data:
+		data := r.GetData()
+		blockHash, err := data.GetBlockHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
 		}
-		return result, nil
+		return &rawapitypes.FinalitySignatures{
+			BlockHash:     blockHash,
+			BlockNumber:   types.BlockNumber(data.GetBlockNumber()),
+			Round:         data.GetRound(),
+			ProposerIndex: data.GetProposerIndex(),
+			Signature:     data.GetSignature(),
+			SignersMask:   data.GetSignersMask(),
+			PublicKeys:    data.GetPublicKeys(),
+			Signers:       data.GetSigners(),
+			Quorum:        data.GetQuorum(),
+		}, nil
 	}
 	return nil, errors.New("unexpected response type")
 }
 
-// AsyncContext converters
-
-func (ac *AsyncContext) PackProtoMessage(context *types.AsyncContext) {
-	if context == nil {
-		return
-	}
+// ValidatorRequest converters
 
-	ac.ResponseProcessingGas = context.ResponseProcessingGas.Uint64()
+func (r *ValidatorRequest) PackProtoMessage(publicKey []byte) error {
+	r.PublicKey = publicKey
+	return nil
 }
 
-func (rc *AsyncContext) UnpackProtoMessage() types.AsyncContext {
-	if rc == nil {
-		return types.AsyncContext{}
-	}
-	return types.AsyncContext{
-		ResponseProcessingGas: types.Gas(rc.GetResponseProcessingGas()),
-	}
+func (r *ValidatorRequest) UnpackProtoMessage() ([]byte, error) {
+	return r.GetPublicKey(), nil
 }
 
-// RawContract converters
+// ValidatorInfo converters
 
-func (rc *RawContract) PackProtoMessage(contract *rawapitypes.SmartContract) error {
-	rc.ContractSSZ = contract.ContractSSZ
-	rc.Code = contract.Code
-	rc.ProofEncoded = contract.ProofEncoded
+func (v *ValidatorInfo) PackProtoMessage(info rawapitypes.ValidatorInfo) *ValidatorInfo {
+	v.Index = info.Index
+	v.PublicKey = info.PublicKey
+	v.WithdrawalAddress = new(Address).PackProtoMessage(info.WithdrawalAddress)
+	v.Weight = info.Weight
+	return v
+}
 
-	if contract.Storage != nil {
-		rc.Storage = make(map[string]*Uint256)
-		for k, v := range contract.Storage {
-			rc.Storage[k.Hex()] = new(Uint256).PackProtoMessage(v)
-		}
+func (v *ValidatorInfo) UnpackProtoMessage() rawapitypes.ValidatorInfo {
+	return rawapitypes.ValidatorInfo{
+		Index:             v.GetIndex(),
+		PublicKey:         v.GetPublicKey(),
+		WithdrawalAddress: v.GetWithdrawalAddress().UnpackProtoMessage(),
+		Weight:            v.GetWeight(),
 	}
+}
 
-	if contract.Tokens != nil {
-		rc.Tokens = make(map[string]*Uint256)
-		for k, v := range contract.Tokens {
-			u := new(Uint256)
-			if v.Uint256 != nil {
-				u = u.PackProtoMessage(*v.Uint256)
-			}
-			rc.Tokens[k.String()] = u
-		}
-	}
+// ValidatorInfoResponse converters
 
-	if contract.AsyncContext != nil {
-		rc.AsyncContext = make(map[uint64]*AsyncContext)
-		for k, v := range contract.AsyncContext {
-			rc.AsyncContext[uint64(k)] = new(AsyncContext)
-			rc.GetAsyncContext()[uint64(k)].PackProtoMessage(&v)
-		}
+func (r *ValidatorInfoResponse) PackProtoMessage(info *rawapitypes.ValidatorInfo, err error) error {
+	if err != nil {
+		r.Result = &ValidatorInfoResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
 
+	r.Result = &ValidatorInfoResponse_Data{Data: new(ValidatorInfo).PackProtoMessage(*info)}
 	return nil
 }
 
-func (rc *RawContract) UnpackProtoMessage() (*rawapitypes.SmartContract, error) {
-	contract := &rawapitypes.SmartContract{
-		ContractSSZ:  rc.GetContractSSZ(),
-		Code:         rc.GetCode(),
-		ProofEncoded: rc.GetProofEncoded(),
+func (r *ValidatorInfoResponse) UnpackProtoMessage() (*rawapitypes.ValidatorInfo, error) {
+	switch r.GetResult().(type) {
+	case *ValidatorInfoResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ValidatorInfoResponse_Data:
+		info := r.GetData().UnpackProtoMessage()
+		return &info, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
 	}
+}
 
-	if len(rc.GetStorage()) > 0 {
-		storage := make(map[common.Hash]types.Uint256)
-		for k, v := range rc.GetStorage() {
-			storage[common.HexToHash(k)] = v.UnpackProtoMessage()
-		}
-		contract.Storage = storage
+// ValidatorSetResponse converters
+
+func (r *ValidatorSetResponse) PackProtoMessage(set *rawapitypes.ValidatorSet, err error) error {
+	if err != nil {
+		r.Result = &ValidatorSetResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
 
-	if len(rc.GetTokens()) > 0 {
-		tokens := make(map[types.TokenId]types.Value)
-		for k, v := range rc.GetTokens() {
-			tokens[types.TokenId(types.HexToAddress(k))] = newValueFromUint256(v)
-		}
-		contract.Tokens = tokens
+	data := &ValidatorSet{
+		BlockHash:   new(Hash),
+		BlockNumber: uint64(set.BlockNumber),
+		Validators:  make([]*ValidatorInfo, len(set.Validators)),
+		TotalWeight: set.TotalWeight,
+		Quorum:      set.Quorum,
 	}
+	if err := data.GetBlockHash().PackProtoMessage(set.BlockHash); err != nil {
+		return err
+	}
+	for i, validator := range set.Validators {
+		data.Validators[i] = new(ValidatorInfo).PackProtoMessage(validator)
+	}
+	r.Result = &ValidatorSetResponse_Data{Data: data}
+	return nil
+}
 
-	if len(rc.GetAsyncContext()) > 0 {
-		asyncContext := make(map[types.TransactionIndex]types.AsyncContext)
-		for k, v := range rc.GetAsyncContext() {
-			asyncContext[types.TransactionIndex(k)] = v.UnpackProtoMessage()
+func (r *ValidatorSetResponse) UnpackProtoMessage() (*rawapitypes.ValidatorSet, error) {
+	switch r.GetResult().(type) {
+	case *ValidatorSetResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ValidatorSetResponse_Data:
+		data := r.GetData()
+		blockHash, err := data.GetBlockHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
 		}
-		contract.AsyncContext = asyncContext
-	}
+		validators := make([]rawapitypes.ValidatorInfo, len(data.GetValidators()))
+		for i, validator := range data.GetValidators() {
+			validators[i] = validator.UnpackProtoMessage()
+		}
+		return &rawapitypes.ValidatorSet{
+			BlockHash:   blockHash,
+			BlockNumber: types.BlockNumber(data.GetBlockNumber()),
+			Validators:  validators,
+			TotalWeight: data.GetTotalWeight(),
+			Quorum:      data.GetQuorum(),
+		}, nil
 
-	return contract, nil
+	default:
+		return nil, errors.New("unexpected response type")
+	}
 }
 
-// RawContractResponse converters
+// EpochInfoResponse converters
 
-func (rcr *RawContractResponse) PackProtoMessage(contract *rawapitypes.SmartContract, err error) error {
+func (r *EpochInfoResponse) PackProtoMessage(info *rawapitypes.EpochInfo, err error) error {
 	if err != nil {
-		rcr.Result = &RawContractResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &EpochInfoResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	rawContract := new(RawContract)
-	if err := rawContract.PackProtoMessage(contract); err != nil {
-		return err
-	}
-
-	rcr.Result = &RawContractResponse_Data{Data: rawContract}
+	r.Result = &EpochInfoResponse_Data{Data: &EpochInfo{
+		BlockNumber:         uint64(info.BlockNumber),
+		Round:               info.Round,
+		ProposerIndex:       info.ProposerIndex,
+		ValidatorCount:      info.ValidatorCount,
+		ValidatorSetChanged: info.ValidatorSetChanged,
+		NextProposers:       info.NextProposers,
+	}}
 	return nil
 }
 
-func (rcr *RawContractResponse) UnpackProtoMessage() (*rawapitypes.SmartContract, error) {
-	switch rcr.GetResult().(type) {
-	case *RawContractResponse_Error:
-		return nil, rcr.GetError().UnpackProtoMessage()
+func (r *EpochInfoResponse) UnpackProtoMessage() (*rawapitypes.EpochInfo, error) {
+	switch r.GetResult().(type) {
+	case *EpochInfoResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
 
-	case *RawContractResponse_Data:
-		return rcr.GetData().UnpackProtoMessage()
+	case *EpochInfoResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.EpochInfo{
+			BlockNumber:         types.BlockNumber(data.GetBlockNumber()),
+			Round:               data.GetRound(),
+			ProposerIndex:       data.GetProposerIndex(),
+			ValidatorCount:      data.GetValidatorCount(),
+			ValidatorSetChanged: data.GetValidatorSetChanged(),
+			NextProposers:       data.GetNextProposers(),
+		}, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
 	}
-	return nil, errors.New("unexpected response type")
 }
 
-func (x *Contract) PackProtoMessage(contract rpctypes.Contract) *Contract {
-	if contract.Seqno != nil {
-		x.Seqno = (*uint64)(contract.Seqno)
+// Uint64Response converters
+func (br *Uint64Response) PackProtoMessage(count uint64, err error) error {
+	br.Result = &Uint64Response_Count{Count: count}
+	if err != nil {
+		br.Result = &Uint64Response_Error{Error: new(Error).PackProtoMessage(err)}
 	}
-	if contract.ExtSeqno != nil {
-		x.ExtSeqno = (*uint64)(contract.ExtSeqno)
+	return nil
+}
+
+func (br *Uint64Response) UnpackProtoMessage() (uint64, error) {
+	switch br.GetResult().(type) {
+	case *Uint64Response_Error:
+		return 0, br.GetError().UnpackProtoMessage()
+	case *Uint64Response_Count:
+		return br.GetCount(), nil
+	default:
+		return 0, errors.New("unexpected response type")
+	}
+}
+
+// StringResponse converters
+func (br *StringResponse) PackProtoMessage(value string, err error) error {
+	br.Result = &StringResponse_Value{Value: value}
+	if err != nil {
+		br.Result = &StringResponse_Error{Error: new(Error).PackProtoMessage(err)}
+	}
+	return nil
+}
+
+func (br *StringResponse) UnpackProtoMessage() (string, error) {
+	switch br.GetResult().(type) {
+	case *StringResponse_Error:
+		return "", br.GetError().UnpackProtoMessage()
+	case *StringResponse_Value:
+		return br.GetValue(), nil
+	default:
+		return "", errors.New("unexpected response type")
+	}
+}
+
+// BoolResponse converters
+func (br *BoolResponse) PackProtoMessage(value bool, err error) error {
+	br.Result = &BoolResponse_Value{Value: value}
+	if err != nil {
+		br.Result = &BoolResponse_Error{Error: new(Error).PackProtoMessage(err)}
+	}
+	return nil
+}
+
+func (br *BoolResponse) UnpackProtoMessage() (bool, error) {
+	switch br.GetResult().(type) {
+	case *BoolResponse_Error:
+		return false, br.GetError().UnpackProtoMessage()
+	case *BoolResponse_Value:
+		return br.GetValue(), nil
+	default:
+		return false, errors.New("unexpected response type")
+	}
+}
+
+// Int64Response converters
+func (br *Int64Response) PackProtoMessage(value int64, err error) error {
+	br.Result = &Int64Response_Value{Value: value}
+	if err != nil {
+		br.Result = &Int64Response_Error{Error: new(Error).PackProtoMessage(err)}
+	}
+	return nil
+}
+
+func (br *Int64Response) UnpackProtoMessage() (int64, error) {
+	switch br.GetResult().(type) {
+	case *Int64Response_Error:
+		return 0, br.GetError().UnpackProtoMessage()
+	case *Int64Response_Value:
+		return br.GetValue(), nil
+	default:
+		return 0, errors.New("unexpected response type")
+	}
+}
+
+func (br *BalanceResponse) PackProtoMessage(balance types.Value, err error) error {
+	if err != nil {
+		br.Result = &BalanceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	if balance.Uint256 == nil {
+		balance.Uint256 = new(types.Uint256)
+	}
+	br.Result = &BalanceResponse_Data{Data: new(Uint256).PackProtoMessage(*balance.Uint256)}
+	return nil
+}
+
+func (br *BalanceResponse) UnpackProtoMessage() (types.Value, error) {
+	switch br.GetResult().(type) {
+	case *BalanceResponse_Error:
+		return types.Value{}, br.GetError().UnpackProtoMessage()
+
+	case *BalanceResponse_Data:
+		return newValueFromUint256(br.GetData()), nil
+
+	default:
+		return types.Value{}, errors.New("unexpected response type")
+	}
+}
+
+// BalancesResponse converters
+
+func (br *BalancesResponse) PackProtoMessage(balances []types.Value, err error) error {
+	if err != nil {
+		br.Result = &BalancesResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &Balances{Balances: make([]*Uint256, len(balances))}
+	for i, balance := range balances {
+		if balance.Uint256 == nil {
+			balance.Uint256 = new(types.Uint256)
+		}
+		data.Balances[i] = new(Uint256).PackProtoMessage(*balance.Uint256)
+	}
+	br.Result = &BalancesResponse_Data{Data: data}
+	return nil
+}
+
+func (br *BalancesResponse) UnpackProtoMessage() ([]types.Value, error) {
+	switch br.GetResult().(type) {
+	case *BalancesResponse_Error:
+		return nil, br.GetError().UnpackProtoMessage()
+
+	case *BalancesResponse_Data:
+		balances := make([]types.Value, len(br.GetData().GetBalances()))
+		for i, balance := range br.GetData().GetBalances() {
+			balances[i] = newValueFromUint256(balance)
+		}
+		return balances, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// StorageRequest converters
+
+func (sr *StorageRequest) PackProtoMessage(
+	address types.Address,
+	key common.Hash,
+	blockReference rawapitypes.BlockReference,
+) error {
+	sr.Address = new(Address).PackProtoMessage(address)
+	sr.Key = new(Hash)
+	if err := sr.GetKey().PackProtoMessage(key); err != nil {
+		return err
+	}
+	sr.BlockReference = &BlockReference{}
+	return sr.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (sr *StorageRequest) UnpackProtoMessage() (types.Address, common.Hash, rawapitypes.BlockReference, error) {
+	key, err := sr.GetKey().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+
+	blockReference, err := sr.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, rawapitypes.BlockReference{}, err
+	}
+
+	return sr.GetAddress().UnpackProtoMessage(), key, blockReference, nil
+}
+
+// StorageRangeRequest converters
+
+func (r *StorageRangeRequest) PackProtoMessage(
+	address types.Address,
+	start common.Hash,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.Start = new(Hash)
+	if err := r.GetStart().PackProtoMessage(start); err != nil {
+		return err
+	}
+	r.Limit = limit
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *StorageRangeRequest) UnpackProtoMessage() (
+	types.Address, common.Hash, uint64, rawapitypes.BlockReference, error,
+) {
+	start, err := r.GetStart().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, 0, rawapitypes.BlockReference{}, err
+	}
+
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, common.EmptyHash, 0, rawapitypes.BlockReference{}, err
+	}
+
+	return r.GetAddress().UnpackProtoMessage(), start, r.GetLimit(), blockReference, nil
+}
+
+// StorageRangeResponse converters
+
+func (r *StorageRangeResponse) PackProtoMessage(storageRange *rawapitypes.StorageRange, err error) error {
+	if err != nil {
+		r.Result = &StorageRangeResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &StorageRange{Entries: make([]*StorageEntry, len(storageRange.Entries))}
+	for i, entry := range storageRange.Entries {
+		data.Entries[i] = &StorageEntry{Key: new(Hash), Value: new(Uint256).PackProtoMessage(entry.Value)}
+		if err := data.Entries[i].GetKey().PackProtoMessage(entry.Key); err != nil {
+			return err
+		}
+	}
+	if storageRange.Next != nil {
+		data.Next = new(Hash)
+		if err := data.GetNext().PackProtoMessage(*storageRange.Next); err != nil {
+			return err
+		}
+	}
+	r.Result = &StorageRangeResponse_Data{Data: data}
+	return nil
+}
+
+func (r *StorageRangeResponse) UnpackProtoMessage() (*rawapitypes.StorageRange, error) {
+	switch r.GetResult().(type) {
+	case *StorageRangeResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *StorageRangeResponse_Data:
+		data := r.GetData()
+		storageRange := &rawapitypes.StorageRange{
+			Entries: make([]rawapitypes.StorageEntry, len(data.GetEntries())),
+		}
+		for i, entry := range data.GetEntries() {
+			key, err := entry.GetKey().UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			storageRange.Entries[i] = rawapitypes.StorageEntry{Key: key, Value: entry.GetValue().UnpackProtoMessage()}
+		}
+		if data.GetNext() != nil {
+			next, err := data.GetNext().UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			storageRange.Next = &next
+		}
+		return storageRange, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// Uint256Response converters
+
+func (ur *Uint256Response) PackProtoMessage(value types.Uint256, err error) error {
+	if err != nil {
+		ur.Result = &Uint256Response_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	ur.Result = &Uint256Response_Data{Data: new(Uint256).PackProtoMessage(value)}
+	return nil
+}
+
+func (ur *Uint256Response) UnpackProtoMessage() (types.Uint256, error) {
+	switch ur.GetResult().(type) {
+	case *Uint256Response_Error:
+		return types.Uint256{}, ur.GetError().UnpackProtoMessage()
+
+	case *Uint256Response_Data:
+		return ur.GetData().UnpackProtoMessage(), nil
+
+	default:
+		return types.Uint256{}, errors.New("unexpected response type")
+	}
+}
+
+// CodeResponse converters
+func (br *CodeResponse) PackProtoMessage(code types.Code, err error) error {
+	if err != nil {
+		br.Result = &CodeResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	br.Result = &CodeResponse_Data{Data: code}
+	return nil
+}
+
+func (br *CodeResponse) UnpackProtoMessage() (types.Code, error) {
+	switch br.GetResult().(type) {
+	case *CodeResponse_Error:
+		return nil, br.GetError().UnpackProtoMessage()
+
+	case *CodeResponse_Data:
+		return br.GetData(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// TokenResponse converters
+func (cr *TokensResponse) PackProtoMessage(page *rawapitypes.TokensPage, err error) error {
+	if err != nil {
+		cr.Result = &TokensResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	result := Tokens{Data: make(map[string]*Uint256)}
+	for k, v := range page.Tokens {
+		result.Data[k.String()] = new(Uint256).PackProtoMessage(*v.Uint256)
+	}
+	if page.Next != nil {
+		result.Next = new(Address).PackProtoMessage(types.Address(*page.Next))
+	}
+	cr.Result = &TokensResponse_Data{Data: &result}
+	return nil
+}
+
+func (cr *TokensResponse) UnpackProtoMessage() (*rawapitypes.TokensPage, error) {
+	switch cr.GetResult().(type) {
+	case *TokensResponse_Error:
+		return nil, cr.GetError().UnpackProtoMessage()
+
+	case *TokensResponse_Data:
+		data := cr.GetData().GetData()
+		page := &rawapitypes.TokensPage{Tokens: make(map[types.TokenId]types.Value, len(data))}
+		for k, v := range data {
+			tokenId := types.TokenId(types.HexToAddress(k))
+			page.Tokens[tokenId] = newValueFromUint256(v)
+		}
+		if cr.GetData().GetNext() != nil {
+			next := types.TokenId(cr.GetData().GetNext().UnpackProtoMessage())
+			page.Next = &next
+		}
+		return page, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// TokensRequest converters
+
+func (r *TokensRequest) PackProtoMessage(
+	address types.Address,
+	blockReference rawapitypes.BlockReference,
+	request rawapitypes.TokensRequest,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.BlockReference = &BlockReference{}
+	if err := r.GetBlockReference().PackProtoMessage(blockReference); err != nil {
+		return err
+	}
+	if request.Start != nil {
+		r.Start = new(Address).PackProtoMessage(types.Address(*request.Start))
+	}
+	r.Limit = request.Limit
+	for _, tokenId := range request.TokenIds {
+		r.TokenIds = append(r.TokenIds, new(Address).PackProtoMessage(types.Address(tokenId)))
+	}
+	return nil
+}
+
+func (r *TokensRequest) UnpackProtoMessage() (
+	types.Address, rawapitypes.BlockReference, rawapitypes.TokensRequest, error,
+) {
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, rawapitypes.BlockReference{}, rawapitypes.TokensRequest{}, err
+	}
+
+	request := rawapitypes.TokensRequest{Limit: r.GetLimit()}
+	if r.GetStart() != nil {
+		start := types.TokenId(r.GetStart().UnpackProtoMessage())
+		request.Start = &start
+	}
+	for _, tokenId := range r.GetTokenIds() {
+		request.TokenIds = append(request.TokenIds, types.TokenId(tokenId.UnpackProtoMessage()))
+	}
+	return r.GetAddress().UnpackProtoMessage(), blockReference, request, nil
+}
+
+// TokenBalanceRequest converters
+
+func (r *TokenBalanceRequest) PackProtoMessage(
+	address types.Address,
+	tokenId types.TokenId,
+	blockReference rawapitypes.BlockReference,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.TokenId = new(Address).PackProtoMessage(types.Address(tokenId))
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *TokenBalanceRequest) UnpackProtoMessage() (types.Address, types.TokenId, rawapitypes.BlockReference, error) {
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, types.TokenId{}, rawapitypes.BlockReference{}, err
+	}
+	return r.GetAddress().UnpackProtoMessage(), types.TokenId(r.GetTokenId().UnpackProtoMessage()), blockReference, nil
+}
+
+// MultiAccountRequest converters
+
+func (r *MultiAccountRequest) PackProtoMessage(
+	addresses []types.Address,
+	blockReference rawapitypes.BlockReference,
+) error {
+	r.Addresses = make([]*Address, len(addresses))
+	for i, address := range addresses {
+		r.Addresses[i] = new(Address).PackProtoMessage(address)
+	}
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *MultiAccountRequest) UnpackProtoMessage() ([]types.Address, rawapitypes.BlockReference, error) {
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return nil, rawapitypes.BlockReference{}, err
+	}
+	addresses := make([]types.Address, len(r.GetAddresses()))
+	for i, address := range r.GetAddresses() {
+		addresses[i] = address.UnpackProtoMessage()
+	}
+	return addresses, blockReference, nil
+}
+
+// TokenRequest converters
+
+func (r *TokenRequest) PackProtoMessage(tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference) error {
+	r.TokenId = new(Address).PackProtoMessage(types.Address(tokenId))
+	r.MainBlockReference = &BlockReference{}
+	return r.GetMainBlockReference().PackProtoMessage(mainBlockReference)
+}
+
+func (r *TokenRequest) UnpackProtoMessage() (types.TokenId, rawapitypes.BlockReference, error) {
+	mainBlockReference, err := r.GetMainBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.TokenId{}, rawapitypes.BlockReference{}, err
+	}
+	return types.TokenId(r.GetTokenId().UnpackProtoMessage()), mainBlockReference, nil
+}
+
+// AccountMetaResponse converters
+
+func (r *AccountMetaResponse) PackProtoMessage(meta *rawapitypes.AccountMeta, err error) error {
+	if err != nil {
+		r.Result = &AccountMetaResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &AccountMeta{
+		Exists:         meta.Exists,
+		IsSmartAccount: meta.IsSmartAccount,
+		CodeHash:       new(Hash),
+		StorageRoot:    new(Hash),
+		ExtSeqno:       uint64(meta.ExtSeqno),
+	}
+	if err := data.GetCodeHash().PackProtoMessage(meta.CodeHash); err != nil {
+		return err
+	}
+	if err := data.GetStorageRoot().PackProtoMessage(meta.StorageRoot); err != nil {
+		return err
+	}
+	r.Result = &AccountMetaResponse_Data{Data: data}
+	return nil
+}
+
+func (r *AccountMetaResponse) UnpackProtoMessage() (*rawapitypes.AccountMeta, error) {
+	switch r.GetResult().(type) {
+	case *AccountMetaResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *AccountMetaResponse_Data:
+		data := r.GetData()
+		codeHash, err := data.GetCodeHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		storageRoot, err := data.GetStorageRoot().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		return &rawapitypes.AccountMeta{
+			Exists:         data.GetExists(),
+			IsSmartAccount: data.GetIsSmartAccount(),
+			CodeHash:       codeHash,
+			StorageRoot:    storageRoot,
+			ExtSeqno:       types.Seqno(data.GetExtSeqno()),
+		}, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// TokenInfo converters
+
+func (ti *TokenInfo) PackProtoMessage(info *rawapitypes.TokenInfo) *TokenInfo {
+	totalSupply := info.TotalSupply
+	if totalSupply.Uint256 == nil {
+		totalSupply = types.NewZeroValue()
+	}
+	ti.Id = new(Address).PackProtoMessage(types.Address(info.Id))
+	ti.Name = info.Name
+	ti.Symbol = info.Symbol
+	ti.Decimals = uint32(info.Decimals)
+	ti.Owner = new(Address).PackProtoMessage(info.Owner)
+	ti.TotalSupply = new(Uint256).PackProtoMessage(*totalSupply.Uint256)
+	return ti
+}
+
+func (ti *TokenInfo) UnpackProtoMessage() *rawapitypes.TokenInfo {
+	return &rawapitypes.TokenInfo{
+		Id:          types.TokenId(ti.GetId().UnpackProtoMessage()),
+		Name:        ti.GetName(),
+		Symbol:      ti.GetSymbol(),
+		Decimals:    uint8(ti.GetDecimals()),
+		Owner:       ti.GetOwner().UnpackProtoMessage(),
+		TotalSupply: newValueFromUint256(ti.GetTotalSupply()),
+	}
+}
+
+// TokenInfoResponse converters
+
+func (r *TokenInfoResponse) PackProtoMessage(info *rawapitypes.TokenInfo, err error) error {
+	if err != nil {
+		r.Result = &TokenInfoResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &TokenInfoResponse_Data{Data: new(TokenInfo).PackProtoMessage(info)}
+	return nil
+}
+
+func (r *TokenInfoResponse) UnpackProtoMessage() (*rawapitypes.TokenInfo, error) {
+	switch r.GetResult().(type) {
+	case *TokenInfoResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *TokenInfoResponse_Data:
+		return r.GetData().UnpackProtoMessage(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// TokenInfosResponse converters
+
+func (r *TokenInfosResponse) PackProtoMessage(infos []*rawapitypes.TokenInfo, err error) error {
+	if err != nil {
+		r.Result = &TokenInfosResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &TokenInfos{Tokens: make([]*TokenInfo, len(infos))}
+	for i, info := range infos {
+		data.Tokens[i] = new(TokenInfo).PackProtoMessage(info)
+	}
+	r.Result = &TokenInfosResponse_Data{Data: data}
+	return nil
+}
+
+func (r *TokenInfosResponse) UnpackProtoMessage() ([]*rawapitypes.TokenInfo, error) {
+	switch r.GetResult().(type) {
+	case *TokenInfosResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *TokenInfosResponse_Data:
+		infos := make([]*rawapitypes.TokenInfo, len(r.GetData().GetTokens()))
+		for i, info := range r.GetData().GetTokens() {
+			infos[i] = info.UnpackProtoMessage()
+		}
+		return infos, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// AsyncContext converters
+
+func (ac *AsyncContext) PackProtoMessage(context *types.AsyncContext) {
+	if context == nil {
+		return
+	}
+
+	ac.ResponseProcessingGas = context.ResponseProcessingGas.Uint64()
+}
+
+func (rc *AsyncContext) UnpackProtoMessage() types.AsyncContext {
+	if rc == nil {
+		return types.AsyncContext{}
+	}
+	return types.AsyncContext{
+		ResponseProcessingGas: types.Gas(rc.GetResponseProcessingGas()),
+	}
+}
+
+// RawContract converters
+
+func (rc *RawContract) PackProtoMessage(contract *rawapitypes.SmartContract) error {
+	rc.ContractSSZ = contract.ContractSSZ
+	rc.Code = contract.Code
+	rc.ProofEncoded = contract.ProofEncoded
+
+	if contract.Storage != nil {
+		rc.Storage = make(map[string]*Uint256)
+		for k, v := range contract.Storage {
+			rc.Storage[k.Hex()] = new(Uint256).PackProtoMessage(v)
+		}
+	}
+
+	if contract.Tokens != nil {
+		rc.Tokens = make(map[string]*Uint256)
+		for k, v := range contract.Tokens {
+			u := new(Uint256)
+			if v.Uint256 != nil {
+				u = u.PackProtoMessage(*v.Uint256)
+			}
+			rc.Tokens[k.String()] = u
+		}
+	}
+
+	if contract.AsyncContext != nil {
+		rc.AsyncContext = make(map[uint64]*AsyncContext)
+		for k, v := range contract.AsyncContext {
+			rc.AsyncContext[uint64(k)] = new(AsyncContext)
+			rc.GetAsyncContext()[uint64(k)].PackProtoMessage(&v)
+		}
+	}
+
+	return nil
+}
+
+func (rc *RawContract) UnpackProtoMessage() (*rawapitypes.SmartContract, error) {
+	contract := &rawapitypes.SmartContract{
+		ContractSSZ:  rc.GetContractSSZ(),
+		Code:         rc.GetCode(),
+		ProofEncoded: rc.GetProofEncoded(),
+	}
+
+	if len(rc.GetStorage()) > 0 {
+		storage := make(map[common.Hash]types.Uint256)
+		for k, v := range rc.GetStorage() {
+			storage[common.HexToHash(k)] = v.UnpackProtoMessage()
+		}
+		contract.Storage = storage
+	}
+
+	if len(rc.GetTokens()) > 0 {
+		tokens := make(map[types.TokenId]types.Value)
+		for k, v := range rc.GetTokens() {
+			tokens[types.TokenId(types.HexToAddress(k))] = newValueFromUint256(v)
+		}
+		contract.Tokens = tokens
+	}
+
+	if len(rc.GetAsyncContext()) > 0 {
+		asyncContext := make(map[types.TransactionIndex]types.AsyncContext)
+		for k, v := range rc.GetAsyncContext() {
+			asyncContext[types.TransactionIndex(k)] = v.UnpackProtoMessage()
+		}
+		contract.AsyncContext = asyncContext
+	}
+
+	return contract, nil
+}
+
+// RawContractResponse converters
+
+func (rcr *RawContractResponse) PackProtoMessage(contract *rawapitypes.SmartContract, err error) error {
+	if err != nil {
+		rcr.Result = &RawContractResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	rawContract := new(RawContract)
+	if err := rawContract.PackProtoMessage(contract); err != nil {
+		return err
+	}
+
+	rcr.Result = &RawContractResponse_Data{Data: rawContract}
+	return nil
+}
+
+func (rcr *RawContractResponse) UnpackProtoMessage() (*rawapitypes.SmartContract, error) {
+	switch rcr.GetResult().(type) {
+	case *RawContractResponse_Error:
+		return nil, rcr.GetError().UnpackProtoMessage()
+
+	case *RawContractResponse_Data:
+		return rcr.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// ProofRequest converters
+
+func (pr *ProofRequest) PackProtoMessage(
+	address types.Address,
+	storageKeys []common.Hash,
+	blockReference rawapitypes.BlockReference,
+) error {
+	pr.Address = new(Address).PackProtoMessage(address)
+	pr.StorageKeys = PackHashes(storageKeys)
+	pr.BlockReference = &BlockReference{}
+	return pr.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (pr *ProofRequest) UnpackProtoMessage() (types.Address, []common.Hash, rawapitypes.BlockReference, error) {
+	blockReference, err := pr.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, nil, rawapitypes.BlockReference{}, err
+	}
+
+	return pr.GetAddress().UnpackProtoMessage(), UnpackHashes(pr.GetStorageKeys()), blockReference, nil
+}
+
+// ProofResponse converters
+
+func (cp *ContractProof) PackProtoMessage(proof *rawapitypes.ContractProof) error {
+	cp.StateRoot = new(Hash)
+	if err := cp.GetStateRoot().PackProtoMessage(proof.StateRoot); err != nil {
+		return err
+	}
+	cp.ContractSSZ = proof.ContractSSZ
+	cp.ProofEncoded = proof.ProofEncoded
+
+	cp.StorageProofs = make([]*StorageProof, len(proof.StorageProofs))
+	for i, storageProof := range proof.StorageProofs {
+		key := new(Hash)
+		if err := key.PackProtoMessage(storageProof.Key); err != nil {
+			return err
+		}
+		cp.StorageProofs[i] = &StorageProof{
+			Key:          key,
+			Value:        new(Uint256).PackProtoMessage(storageProof.Value),
+			ProofEncoded: storageProof.ProofEncoded,
+		}
+	}
+	return nil
+}
+
+func (cp *ContractProof) UnpackProtoMessage() (*rawapitypes.ContractProof, error) {
+	stateRoot, err := cp.GetStateRoot().UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+
+	proof := &rawapitypes.ContractProof{
+		StateRoot:    stateRoot,
+		ContractSSZ:  cp.GetContractSSZ(),
+		ProofEncoded: cp.GetProofEncoded(),
+	}
+	if len(cp.GetStorageProofs()) > 0 {
+		proof.StorageProofs = make([]rawapitypes.StorageProof, len(cp.GetStorageProofs()))
+		for i, storageProof := range cp.GetStorageProofs() {
+			key, err := storageProof.GetKey().UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			proof.StorageProofs[i] = rawapitypes.StorageProof{
+				Key:          key,
+				Value:        storageProof.GetValue().UnpackProtoMessage(),
+				ProofEncoded: storageProof.GetProofEncoded(),
+			}
+		}
+	}
+	return proof, nil
+}
+
+func (pr *ProofResponse) PackProtoMessage(proof *rawapitypes.ContractProof, err error) error {
+	if err != nil {
+		pr.Result = &ProofResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	contractProof := new(ContractProof)
+	if err := contractProof.PackProtoMessage(proof); err != nil {
+		return err
+	}
+
+	pr.Result = &ProofResponse_Data{Data: contractProof}
+	return nil
+}
+
+func (pr *ProofResponse) UnpackProtoMessage() (*rawapitypes.ContractProof, error) {
+	switch pr.GetResult().(type) {
+	case *ProofResponse_Error:
+		return nil, pr.GetError().UnpackProtoMessage()
+
+	case *ProofResponse_Data:
+		return pr.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+func (x *Contract) PackProtoMessage(contract rpctypes.Contract) *Contract {
+	if contract.Seqno != nil {
+		x.Seqno = (*uint64)(contract.Seqno)
+	}
+	if contract.ExtSeqno != nil {
+		x.ExtSeqno = (*uint64)(contract.ExtSeqno)
+	}
+	if contract.Code != nil {
+		x.Code = *contract.Code
+	}
+	if contract.Balance != nil {
+		balance := new(Uint256)
+		if contract.Balance.Uint256 != nil {
+			balance.PackProtoMessage(*contract.Balance.Uint256)
+		}
+		x.Balance = balance
+	}
+	if contract.State != nil {
+		x.State = make(map[string]*Hash)
+		for k, v := range *contract.State {
+			kHex := k.Hex()
+			x.State[kHex] = &Hash{}
+			check.PanicIfErr(x.GetState()[kHex].PackProtoMessage(v))
+		}
+	}
+	if contract.StateDiff != nil {
+		x.StateDiff = make(map[string]*Hash)
+		for k, v := range *contract.StateDiff {
+			kHex := k.Hex()
+			x.StateDiff[kHex] = &Hash{}
+			check.PanicIfErr(x.GetStateDiff()[kHex].PackProtoMessage(v))
+		}
+	}
+	if contract.AsyncContext != nil {
+		x.AsyncContext = make(map[uint64]*AsyncContext)
+		for k, v := range *contract.AsyncContext {
+			if v != nil {
+				x.AsyncContext[uint64(k)] = &AsyncContext{}
+				x.GetAsyncContext()[uint64(k)].PackProtoMessage(v)
+			}
+		}
+	}
+	return x
+}
+
+func (x *CallArgs) PackProtoMessage(args rpctypes.CallArgs) *CallArgs {
+	x.Flags = uint32(args.Flags.Bits)
+	if args.From != nil {
+		x.From = new(Address).PackProtoMessage(*args.From)
+	}
+	x.To = new(Address).PackProtoMessage(args.To)
+	if args.Fee.FeeCredit.Uint256 != nil {
+		x.FeeCredit = new(Uint256).PackProtoMessage(*args.Fee.FeeCredit.Uint256)
+	}
+	if args.Fee.MaxFeePerGas.Uint256 != nil {
+		x.MaxFeePerGas = new(Uint256).PackProtoMessage(*args.Fee.MaxFeePerGas.Uint256)
+	}
+	if args.Fee.MaxPriorityFeePerGas.Uint256 != nil {
+		x.MaxPriorityFeePerGas = new(Uint256).PackProtoMessage(*args.Fee.MaxPriorityFeePerGas.Uint256)
+	}
+	if args.Value.Uint256 != nil {
+		x.Value = new(Uint256).PackProtoMessage(*args.Value.Uint256)
+	}
+	x.Seqno = args.Seqno.Uint64()
+	if args.Data != nil {
+		x.Data = *args.Data
+	}
+	if args.Transaction != nil {
+		x.Transaction = *args.Transaction
+	}
+	x.ChainId = uint64(args.ChainId)
+	return x
+}
+
+func (o *StateOverrides) PackProtoMessage(overrides *rpctypes.StateOverrides) *StateOverrides {
+	if overrides != nil {
+		o.Overrides = make(map[string]*Contract)
+		for k, v := range *overrides {
+			o.Overrides[k.Hex()] = new(Contract).PackProtoMessage(v)
+		}
+	}
+	return o
+}
+
+func (brd *BlockReferenceOrHashWithChildren) PackProtoMessage(
+	blockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+) error {
+	if blockReferenceOrHashWithChildren.IsReference() {
+		blockReference := new(BlockReference)
+		if err := blockReference.PackProtoMessage(blockReferenceOrHashWithChildren.Reference()); err != nil {
+			return err
+		}
+		brd.BlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren_BlockReference{
+			BlockReference: blockReference,
+		}
+	} else {
+		hash, childBlocks := blockReferenceOrHashWithChildren.HashAndChildren()
+		blockHashWithChildren := new(BlockHashWithChildren)
+
+		blockHashWithChildren.Hash = new(Hash)
+		if err := blockHashWithChildren.GetHash().PackProtoMessage(hash); err != nil {
+			return err
+		}
+
+		for _, childBlock := range childBlocks {
+			childBlockHash := new(Hash)
+			if err := childBlockHash.PackProtoMessage(childBlock); err != nil {
+				return err
+			}
+			blockHashWithChildren.Children = append(blockHashWithChildren.Children, childBlockHash)
+		}
+		brd.BlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren_BlockHashWithChildren{
+			BlockHashWithChildren: blockHashWithChildren,
+		}
+	}
+	return nil
+}
+
+func (brd *BlockReferenceOrHashWithChildren) UnpackProtoMessage() (
+	rawapitypes.BlockReferenceOrHashWithChildren, error,
+) {
+	switch brd.GetBlockReferenceOrHashWithChildren().(type) {
+	case *BlockReferenceOrHashWithChildren_BlockReference:
+		blockReference, err := brd.GetBlockReference().UnpackProtoMessage()
+		return rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(blockReference), err
+
+	case *BlockReferenceOrHashWithChildren_BlockHashWithChildren:
+		blockWithChildren := brd.GetBlockHashWithChildren()
+		hash, err := blockWithChildren.GetHash().UnpackProtoMessage()
+		if err != nil {
+			return rawapitypes.BlockReferenceOrHashWithChildren{}, err
+		}
+		children := make([]common.Hash, len(blockWithChildren.GetChildren()))
+		for i, child := range blockWithChildren.GetChildren() {
+			children[i], err = child.UnpackProtoMessage()
+			if err != nil {
+				return rawapitypes.BlockReferenceOrHashWithChildren{}, err
+			}
+		}
+		return rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(hash, children), err
+	}
+	return rawapitypes.BlockReferenceOrHashWithChildren{}, errors.New("unexpected block reference or data type")
+}
+
+func (cr *CallRequest) PackProtoMessage(
+	args rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) error {
+	cr.Args = new(CallArgs).PackProtoMessage(args)
+
+	cr.MainBlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren{}
+	err := cr.GetMainBlockReferenceOrHashWithChildren().PackProtoMessage(mainBlockReferenceOrHashWithChildren)
+	if err != nil {
+		return err
+	}
+
+	if overrides != nil {
+		cr.StateOverrides = new(StateOverrides).PackProtoMessage(overrides)
+	}
+	if blockOverrides != nil {
+		cr.BlockOverrides = new(BlockOverrides).PackProtoMessage(blockOverrides)
+	}
+
+	return nil
+}
+
+func (o *BlockOverrides) PackProtoMessage(overrides *rpctypes.BlockOverrides) *BlockOverrides {
+	if overrides.Number != nil {
+		number := uint64(*overrides.Number)
+		o.Number = &number
+	}
+	o.Timestamp = overrides.Timestamp
+	if overrides.BaseFee != nil && overrides.BaseFee.Uint256 != nil {
+		o.BaseFee = new(Uint256).PackProtoMessage(*overrides.BaseFee.Uint256)
+	}
+	return o
+}
+
+func (o *BlockOverrides) UnpackProtoMessage() *rpctypes.BlockOverrides {
+	if o == nil {
+		return nil
+	}
+
+	overrides := &rpctypes.BlockOverrides{
+		Number:    (*types.BlockNumber)(o.Number), //nolint: protogetter
+		Timestamp: o.Timestamp,                    //nolint: protogetter
+	}
+	if o.GetBaseFee() != nil {
+		baseFee := newValueFromUint256(o.GetBaseFee())
+		overrides.BaseFee = &baseFee
+	}
+	return overrides
+}
+
+func (x *CallArgs) UnpackProtoMessage() rpctypes.CallArgs {
+	args := rpctypes.CallArgs{}
+	args.Flags = types.TransactionFlags{BitFlags: types.BitFlags[uint8]{Bits: uint8(x.GetFlags())}}
+	if x.GetFrom() != nil {
+		a := x.GetFrom().UnpackProtoMessage()
+		args.From = &a
+	}
+	args.To = x.GetTo().UnpackProtoMessage()
+
+	args.Fee.FeeCredit = newValueFromUint256(x.GetFeeCredit())
+	args.Fee.MaxFeePerGas = newValueFromUint256(x.GetMaxFeePerGas())
+	args.Fee.MaxPriorityFeePerGas = newValueFromUint256(x.GetMaxPriorityFeePerGas())
+	args.Value = newValueFromUint256(x.GetValue())
+	args.Seqno = types.Seqno(x.GetSeqno())
+
+	if x.Data != nil {
+		args.Data = (*hexutil.Bytes)(&x.Data)
+	}
+
+	if x.Transaction != nil {
+		args.Transaction = (*hexutil.Bytes)(&x.Transaction)
+	}
+
+	args.ChainId = types.ChainId(x.GetChainId())
+	return args
+}
+
+func (x *Contract) UnpackProtoMessage() rpctypes.Contract {
+	c := rpctypes.Contract{}
+
+	c.Seqno = (*types.Seqno)(x.Seqno)       //nolint: protogetter
+	c.ExtSeqno = (*types.Seqno)(x.ExtSeqno) //nolint: protogetter
+
+	if len(x.GetCode()) > 0 {
+		c.Code = (*hexutil.Bytes)(&x.Code)
+	}
+
+	if x.GetBalance() != nil {
+		v := newValueFromUint256(x.GetBalance())
+		c.Balance = &v
+	}
+
+	if len(x.GetState()) > 0 {
+		m := make(map[common.Hash]common.Hash)
+		for k, v := range x.GetState() {
+			var err error
+			m[common.HexToHash(k)], err = v.UnpackProtoMessage()
+			check.PanicIfErr(err)
+		}
+		c.State = &m
+	}
+
+	if len(x.GetStateDiff()) > 0 {
+		m := make(map[common.Hash]common.Hash)
+		for k, v := range x.GetStateDiff() {
+			var err error
+			m[common.HexToHash(k)], err = v.UnpackProtoMessage()
+			check.PanicIfErr(err)
+		}
+		c.StateDiff = &m
+	}
+
+	if len(x.GetAsyncContext()) > 0 {
+		m := make(map[types.TransactionIndex]*types.AsyncContext)
+		for k, v := range x.GetAsyncContext() {
+			var ac *types.AsyncContext
+			if v != nil {
+				v := v.UnpackProtoMessage()
+				ac = &v
+			}
+			m[types.TransactionIndex(k)] = ac
+		}
+		c.AsyncContext = &m
+	}
+
+	return c
+}
+
+func (cr *StateOverrides) UnpackProtoMessage() *rpctypes.StateOverrides {
+	if cr == nil {
+		return nil
+	}
+
+	args := make(rpctypes.StateOverrides)
+	for k, v := range cr.GetOverrides() {
+		args[types.HexToAddress(k)] = v.UnpackProtoMessage()
+	}
+	return &args
+}
+
+func (cr *CallRequest) UnpackProtoMessage() (
+	rpctypes.CallArgs,
+	rawapitypes.BlockReferenceOrHashWithChildren,
+	*rpctypes.StateOverrides,
+	*rpctypes.BlockOverrides,
+	error,
+) {
+	br, err := cr.GetMainBlockReferenceOrHashWithChildren().UnpackProtoMessage()
+	if err != nil {
+		return rpctypes.CallArgs{}, rawapitypes.BlockReferenceOrHashWithChildren{}, nil, nil, err
+	}
+	return cr.GetArgs().UnpackProtoMessage(),
+		br,
+		cr.GetStateOverrides().UnpackProtoMessage(),
+		cr.GetBlockOverrides().UnpackProtoMessage(),
+		nil
+}
+
+func (m *OutTransaction) PackProtoMessage(txn *rpctypes.OutTransaction) *OutTransaction {
+	coinsUsed := new(Uint256)
+	if txn.CoinsUsed.Uint256 != nil {
+		coinsUsed.PackProtoMessage(*txn.CoinsUsed.Uint256)
+	}
+
+	gasPrice := new(Uint256)
+	if txn.BaseFee.Uint256 != nil {
+		gasPrice.PackProtoMessage(*txn.BaseFee.Uint256)
+	}
+
+	out := &OutTransaction{
+		TransactionSSZ: txn.TransactionSSZ,
+		ForwardKind:    uint64(txn.ForwardKind),
+		Data:           txn.Data,
+		CoinsUsed:      coinsUsed,
+		GasPrice:       gasPrice,
+		Error:          txn.Error,
+		Logs:           packLogs(txn.Logs),
+		DebugLogs:      packDebugLogs(txn.DebugLogs),
+	}
+
+	if len(txn.OutTransactions) > 0 {
+		out.OutTransactions = make([]*OutTransaction, len(txn.OutTransactions))
+		for i, outTxn := range txn.OutTransactions {
+			out.OutTransactions[i] = new(OutTransaction).PackProtoMessage(outTxn)
+		}
+	}
+
+	return out
+}
+
+func newValueFromUint256(v *Uint256) types.Value {
+	if v == nil {
+		return types.NewZeroValue()
+	}
+	value := v.UnpackProtoMessage()
+	return types.Value{Uint256: &value}
+}
+
+func newUint256FromValue(v types.Value) *Uint256 {
+	if v.Uint256 == nil {
+		return nil
+	}
+	return new(Uint256).PackProtoMessage(*v.Uint256)
+}
+
+func (m *OutTransaction) UnpackProtoMessage() *rpctypes.OutTransaction {
+	txn := &rpctypes.OutTransaction{
+		TransactionSSZ: m.GetTransactionSSZ(),
+		ForwardKind:    types.ForwardKind(m.GetForwardKind()),
+		Data:           m.GetData(),
+		Error:          m.GetError(),
+		Logs:           unpackLogs(m.GetLogs()),
+		DebugLogs:      unpackDebugLogs(m.GetDebugLogs()),
+	}
+
+	txn.CoinsUsed = newValueFromUint256(m.GetCoinsUsed())
+	txn.BaseFee = newValueFromUint256(m.GetGasPrice())
+
+	if len(m.GetOutTransactions()) > 0 {
+		txn.OutTransactions = make([]*rpctypes.OutTransaction, len(m.GetOutTransactions()))
+		for i, outTxn := range m.GetOutTransactions() {
+			txn.OutTransactions[i] = outTxn.UnpackProtoMessage()
+		}
+	}
+	return txn
+}
+
+func (l *Log) PackProtoMessage(log *types.Log) {
+	l.Address = new(Address).PackProtoMessage(log.Address)
+	l.Topics = PackHashes(log.Topics)
+	l.Data = log.Data
+}
+
+func (l *Log) UnpackProtoMessage() *types.Log {
+	return &types.Log{
+		Address: l.GetAddress().UnpackProtoMessage(),
+		Topics:  UnpackHashes(l.GetTopics()),
+		Data:    l.GetData(),
+	}
+}
+
+func (l *DebugLog) PackProtoMessage(log *types.DebugLog) {
+	l.Message = log.Message
+	l.Data = make([]*Uint256, len(log.Data))
+	for i, data := range log.Data {
+		l.Data[i] = new(Uint256).PackProtoMessage(data)
+	}
+}
+
+func (l *DebugLog) UnpackProtoMessage() *types.DebugLog {
+	data := make([]types.Uint256, len(l.GetData()))
+	for i, value := range l.GetData() {
+		data[i] = value.UnpackProtoMessage()
+	}
+	return &types.DebugLog{
+		Message: l.GetMessage(),
+		Data:    data,
+	}
+}
+
+func packLogs(logs []*types.Log) []*Log {
+	res := make([]*Log, len(logs))
+	for i, log := range logs {
+		res[i] = new(Log)
+		res[i].PackProtoMessage(log)
+	}
+	return res
+}
+
+func unpackLogs(logs []*Log) []*types.Log {
+	if logs == nil {
+		return nil
+	}
+	res := make([]*types.Log, len(logs))
+	for i, log := range logs {
+		res[i] = log.UnpackProtoMessage()
+	}
+	return res
+}
+
+func packDebugLogs(logs []*types.DebugLog) []*DebugLog {
+	res := make([]*DebugLog, len(logs))
+	for i, log := range logs {
+		res[i] = new(DebugLog)
+		res[i].PackProtoMessage(log)
+	}
+	return res
+}
+
+func unpackDebugLogs(logs []*DebugLog) []*types.DebugLog {
+	if logs == nil {
+		return nil
+	}
+	res := make([]*types.DebugLog, len(logs))
+	for i, log := range logs {
+		res[i] = log.UnpackProtoMessage()
+	}
+	return res
+}
+
+func (cr *CallResponse) PackProtoMessage(args *rpctypes.CallResWithGasPrice, err error) error {
+	if err != nil {
+		cr.Result = &CallResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	res := &CallResult{}
+	res.Data = args.Data
+	res.Logs = packLogs(args.Logs)
+	res.DebugLogs = packDebugLogs(args.DebugLogs)
+
+	if args.CoinsUsed.Uint256 != nil {
+		res.CoinsUsed = new(Uint256).PackProtoMessage(*args.CoinsUsed.Uint256)
+	}
+
+	res.OutTransactions = make([]*OutTransaction, len(args.OutTransactions))
+	for i, outTxn := range res.GetOutTransactions() {
+		res.OutTransactions[i] = outTxn.PackProtoMessage(args.OutTransactions[i])
+	}
+
+	if len(args.Error) > 0 {
+		res.Error = &Error{Message: args.Error}
+	}
+	if args.StateOverrides != nil {
+		res.StateOverrides = new(StateOverrides).PackProtoMessage(&args.StateOverrides)
+	}
+
+	if args.BaseFee.Uint256 != nil {
+		res.GasPrice = new(Uint256).PackProtoMessage(*args.BaseFee.Uint256)
+	}
+
+	cr.Result = &CallResponse_Data{Data: res}
+	return nil
+}
+
+func (cr *CallResponse) UnpackProtoMessage() (*rpctypes.CallResWithGasPrice, error) {
+	if err := cr.GetError(); err != nil {
+		return nil, err.UnpackProtoMessage()
+	}
+
+	data := cr.GetData()
+	if data == nil {
+		return nil, errors.New("unexpected response type")
+	}
+	check.PanicIfNot(data != nil)
+
+	res := &rpctypes.CallResWithGasPrice{}
+	res.Data = data.GetData()
+	res.CoinsUsed = newValueFromUint256(data.GetCoinsUsed())
+	res.BaseFee = newValueFromUint256(data.GetGasPrice())
+	res.Logs = unpackLogs(data.GetLogs())
+	res.DebugLogs = unpackDebugLogs(data.GetDebugLogs())
+
+	res.OutTransactions = make([]*rpctypes.OutTransaction, len(data.GetOutTransactions()))
+	for i, outTxn := range data.GetOutTransactions() {
+		res.OutTransactions[i] = outTxn.UnpackProtoMessage()
+	}
+
+	if data.GetStateOverrides() != nil {
+		res.StateOverrides = *data.GetStateOverrides().UnpackProtoMessage()
+	}
+
+	if data.GetError() != nil {
+		res.Error = data.GetError().GetMessage()
+	}
+
+	return res, nil
+}
+
+// BundleRequest converters
+
+func (r *BundleRequest) PackProtoMessage(
+	calls []rpctypes.CallArgs,
+	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
+	overrides *rpctypes.StateOverrides,
+	blockOverrides *rpctypes.BlockOverrides,
+) error {
+	r.Calls = make([]*CallArgs, len(calls))
+	for i, args := range calls {
+		r.Calls[i] = new(CallArgs).PackProtoMessage(args)
+	}
+
+	r.MainBlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren{}
+	err := r.GetMainBlockReferenceOrHashWithChildren().PackProtoMessage(mainBlockReferenceOrHashWithChildren)
+	if err != nil {
+		return err
+	}
+
+	if overrides != nil {
+		r.StateOverrides = new(StateOverrides).PackProtoMessage(overrides)
+	}
+	if blockOverrides != nil {
+		r.BlockOverrides = new(BlockOverrides).PackProtoMessage(blockOverrides)
+	}
+	return nil
+}
+
+func (r *BundleRequest) UnpackProtoMessage() (
+	[]rpctypes.CallArgs,
+	rawapitypes.BlockReferenceOrHashWithChildren,
+	*rpctypes.StateOverrides,
+	*rpctypes.BlockOverrides,
+	error,
+) {
+	br, err := r.GetMainBlockReferenceOrHashWithChildren().UnpackProtoMessage()
+	if err != nil {
+		return nil, rawapitypes.BlockReferenceOrHashWithChildren{}, nil, nil, err
+	}
+
+	calls := make([]rpctypes.CallArgs, len(r.GetCalls()))
+	for i, args := range r.GetCalls() {
+		calls[i] = args.UnpackProtoMessage()
+	}
+	return calls,
+		br,
+		r.GetStateOverrides().UnpackProtoMessage(),
+		r.GetBlockOverrides().UnpackProtoMessage(),
+		nil
+}
+
+// BundleResponse converters
+
+func (r *BundleResponse) PackProtoMessage(results []*rawapitypes.BundleCallResult, err error) error {
+	if err != nil {
+		r.Result = &BundleResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &BundleResult{Results: make([]*BundleCallResult, len(results))}
+	for i, result := range results {
+		res := &BundleCallResult{
+			Data:            result.Data,
+			CoinsUsed:       newUint256FromValue(result.CoinsUsed),
+			GasUsed:         uint64(result.GasUsed),
+			Logs:            packLogs(result.Logs),
+			OutTransactions: result.OutTransactions,
+		}
+		if len(result.Error) > 0 {
+			res.Error = &Error{Message: result.Error}
+		}
+		data.Results[i] = res
+	}
+	r.Result = &BundleResponse_Data{Data: data}
+	return nil
+}
+
+func (r *BundleResponse) UnpackProtoMessage() ([]*rawapitypes.BundleCallResult, error) {
+	switch r.GetResult().(type) {
+	case *BundleResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *BundleResponse_Data:
+		results := make([]*rawapitypes.BundleCallResult, len(r.GetData().GetResults()))
+		for i, res := range r.GetData().GetResults() {
+			results[i] = &rawapitypes.BundleCallResult{
+				Data:            res.GetData(),
+				CoinsUsed:       newValueFromUint256(res.GetCoinsUsed()),
+				GasUsed:         types.Gas(res.GetGasUsed()),
+				Logs:            unpackLogs(res.GetLogs()),
+				OutTransactions: res.GetOutTransactions(),
+				Error:           res.GetError().GetMessage(),
+			}
+		}
+		return results, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// FeeEstimation converters
+
+func (e *FeeEstimation) PackProtoMessage(estimation *rawapitypes.FeeEstimation) *FeeEstimation {
+	e.FeeCredit = newUint256FromValue(estimation.FeeCredit)
+	e.ExecutionFee = newUint256FromValue(estimation.ExecutionFee)
+	e.ForwardingFee = newUint256FromValue(estimation.ForwardingFee)
+	e.VerificationFee = newUint256FromValue(estimation.VerificationFee)
+	e.BaseFee = newUint256FromValue(estimation.BaseFee)
+	e.MaxBaseFee = newUint256FromValue(estimation.MaxBaseFee)
+	return e
+}
+
+func (e *FeeEstimation) UnpackProtoMessage() *rawapitypes.FeeEstimation {
+	return &rawapitypes.FeeEstimation{
+		FeeCredit:       newValueFromUint256(e.GetFeeCredit()),
+		ExecutionFee:    newValueFromUint256(e.GetExecutionFee()),
+		ForwardingFee:   newValueFromUint256(e.GetForwardingFee()),
+		VerificationFee: newValueFromUint256(e.GetVerificationFee()),
+		BaseFee:         newValueFromUint256(e.GetBaseFee()),
+		MaxBaseFee:      newValueFromUint256(e.GetMaxBaseFee()),
+	}
+}
+
+// FeeEstimationResponse converters
+
+func (r *FeeEstimationResponse) PackProtoMessage(estimation *rawapitypes.FeeEstimation, err error) error {
+	if err != nil {
+		r.Result = &FeeEstimationResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &FeeEstimationResponse_Data{Data: new(FeeEstimation).PackProtoMessage(estimation)}
+	return nil
+}
+
+func (r *FeeEstimationResponse) UnpackProtoMessage() (*rawapitypes.FeeEstimation, error) {
+	switch r.GetResult().(type) {
+	case *FeeEstimationResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *FeeEstimationResponse_Data:
+		return r.GetData().UnpackProtoMessage(), nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// DeployRequest converters
+
+func (r *DeployRequest) PackProtoMessage(request rawapitypes.DeployRequest) error {
+	r.ShardId = uint32(request.ShardId)
+	r.Code = request.Code
+	r.Salt = new(Hash)
+	if err := r.Salt.PackProtoMessage(request.Salt); err != nil {
+		return err
+	}
+	r.ValidateConstructor = request.ValidateConstructor
+	return nil
+}
+
+func (r *DeployRequest) UnpackProtoMessage() (rawapitypes.DeployRequest, error) {
+	salt, err := r.GetSalt().UnpackProtoMessage()
+	if err != nil {
+		return rawapitypes.DeployRequest{}, err
+	}
+	return rawapitypes.DeployRequest{
+		ShardId:             types.ShardId(r.GetShardId()),
+		Code:                r.GetCode(),
+		Salt:                salt,
+		ValidateConstructor: r.GetValidateConstructor(),
+	}, nil
+}
+
+// DeployPreparationResponse converters
+
+func (r *DeployPreparationResponse) PackProtoMessage(preparation *rawapitypes.DeployPreparation, err error) error {
+	if err != nil {
+		r.Result = &DeployPreparationResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &DeployPreparation{
+		Address:         new(Address).PackProtoMessage(preparation.Address),
+		Payload:         preparation.Payload,
+		Deployed:        preparation.Deployed,
+		Balance:         newUint256FromValue(preparation.Balance),
+		SimulationError: preparation.SimulationError,
+		RevertData:      preparation.RevertData,
+	}
+	if preparation.Fee != nil {
+		data.Fee = new(FeeEstimation).PackProtoMessage(preparation.Fee)
+	}
+	r.Result = &DeployPreparationResponse_Data{Data: data}
+	return nil
+}
+
+func (r *DeployPreparationResponse) UnpackProtoMessage() (*rawapitypes.DeployPreparation, error) {
+	switch r.GetResult().(type) {
+	case *DeployPreparationResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *DeployPreparationResponse_Data:
+		data := r.GetData()
+		preparation := &rawapitypes.DeployPreparation{
+			Address:         data.GetAddress().UnpackProtoMessage(),
+			Payload:         data.GetPayload(),
+			Deployed:        data.GetDeployed(),
+			Balance:         newValueFromUint256(data.GetBalance()),
+			SimulationError: data.GetSimulationError(),
+			RevertData:      data.GetRevertData(),
+		}
+		if data.GetFee() != nil {
+			preparation.Fee = data.GetFee().UnpackProtoMessage()
+		}
+		return preparation, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// AccessListResponse converters
+
+func (r *AccessListResponse) PackProtoMessage(result *rawapitypes.AccessListResult, err error) error {
+	if err != nil {
+		r.Result = &AccessListResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &AccessListResult{AccessList: make([]*AccessTuple, len(result.AccessList))}
+	for i, tuple := range result.AccessList {
+		keys := make([]*Hash, len(tuple.StorageKeys))
+		for j, key := range tuple.StorageKeys {
+			keys[j] = new(Hash)
+			if err := keys[j].PackProtoMessage(key); err != nil {
+				return err
+			}
+		}
+		data.AccessList[i] = &AccessTuple{
+			Address:     new(Address).PackProtoMessage(tuple.Address),
+			StorageKeys: keys,
+		}
+	}
+	if len(result.Error) > 0 {
+		data.Error = &Error{Message: result.Error}
+	}
+	r.Result = &AccessListResponse_Data{Data: data}
+	return nil
+}
+
+func (r *AccessListResponse) UnpackProtoMessage() (*rawapitypes.AccessListResult, error) {
+	switch r.GetResult().(type) {
+	case *AccessListResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *AccessListResponse_Data:
+		data := r.GetData()
+		result := &rawapitypes.AccessListResult{
+			AccessList: make([]rawapitypes.AccessTuple, len(data.GetAccessList())),
+			Error:      data.GetError().GetMessage(),
+		}
+		for i, tuple := range data.GetAccessList() {
+			keys := make([]common.Hash, len(tuple.GetStorageKeys()))
+			for j, key := range tuple.GetStorageKeys() {
+				var err error
+				if keys[j], err = key.UnpackProtoMessage(); err != nil {
+					return nil, err
+				}
+			}
+			result.AccessList[i] = rawapitypes.AccessTuple{
+				Address:     tuple.GetAddress().UnpackProtoMessage(),
+				StorageKeys: keys,
+			}
+		}
+		return result, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// ExecutionTraceResponse converters
+
+func (s *OpcodeStep) PackProtoMessage(step rawapitypes.OpcodeStep) *OpcodeStep {
+	s.Pc = step.Pc
+	s.Op = step.Op
+	s.Gas = step.Gas
+	s.GasCost = step.GasCost
+	s.Depth = uint32(step.Depth)
+	s.Stack = make([]*Uint256, len(step.Stack))
+	for i, item := range step.Stack {
+		s.Stack[i] = new(Uint256).PackProtoMessage(item)
+	}
+	if len(step.Error) > 0 {
+		s.Error = &Error{Message: step.Error}
+	}
+	return s
+}
+
+func (s *OpcodeStep) UnpackProtoMessage() rawapitypes.OpcodeStep {
+	step := rawapitypes.OpcodeStep{
+		Pc:      s.GetPc(),
+		Op:      s.GetOp(),
+		Gas:     s.GetGas(),
+		GasCost: s.GetGasCost(),
+		Depth:   int(s.GetDepth()),
+		Stack:   make([]types.Uint256, len(s.GetStack())),
+		Error:   s.GetError().GetMessage(),
+	}
+	for i, item := range s.GetStack() {
+		step.Stack[i] = item.UnpackProtoMessage()
+	}
+	return step
+}
+
+func (f *CallFrame) PackProtoMessage(frame *rawapitypes.CallFrame) *CallFrame {
+	f.Type = frame.Type
+	f.From = new(Address).PackProtoMessage(frame.From)
+	f.To = new(Address).PackProtoMessage(frame.To)
+	f.Input = frame.Input
+	f.Output = frame.Output
+	f.Gas = frame.Gas
+	f.GasUsed = frame.GasUsed
+	f.Value = newUint256FromValue(frame.Value)
+	if len(frame.Error) > 0 {
+		f.Error = &Error{Message: frame.Error}
+	}
+	f.Calls = make([]*CallFrame, len(frame.Calls))
+	for i, call := range frame.Calls {
+		f.Calls[i] = new(CallFrame).PackProtoMessage(call)
+	}
+	return f
+}
+
+func (f *CallFrame) UnpackProtoMessage() *rawapitypes.CallFrame {
+	frame := &rawapitypes.CallFrame{
+		Type:    f.GetType(),
+		From:    f.GetFrom().UnpackProtoMessage(),
+		To:      f.GetTo().UnpackProtoMessage(),
+		Input:   f.GetInput(),
+		Output:  f.GetOutput(),
+		Gas:     f.GetGas(),
+		GasUsed: f.GetGasUsed(),
+		Value:   newValueFromUint256(f.GetValue()),
+		Error:   f.GetError().GetMessage(),
+		Calls:   make([]*rawapitypes.CallFrame, len(f.GetCalls())),
+	}
+	for i, call := range f.GetCalls() {
+		frame.Calls[i] = call.UnpackProtoMessage()
+	}
+	return frame
+}
+
+func (t *ExecutionTrace) PackProtoMessage(trace *rawapitypes.ExecutionTrace) *ExecutionTrace {
+	t.Steps = make([]*OpcodeStep, len(trace.Steps))
+	for i, step := range trace.Steps {
+		t.Steps[i] = new(OpcodeStep).PackProtoMessage(step)
+	}
+	t.StepsTruncated = trace.StepsTruncated
+	if trace.Call != nil {
+		t.Call = new(CallFrame).PackProtoMessage(trace.Call)
+	}
+	t.GasUsed = uint64(trace.GasUsed)
+	if len(trace.Error) > 0 {
+		t.Error = &Error{Message: trace.Error}
+	}
+	return t
+}
+
+func (t *ExecutionTrace) UnpackProtoMessage() *rawapitypes.ExecutionTrace {
+	trace := &rawapitypes.ExecutionTrace{
+		Steps:          make([]rawapitypes.OpcodeStep, len(t.GetSteps())),
+		StepsTruncated: t.GetStepsTruncated(),
+		GasUsed:        types.Gas(t.GetGasUsed()),
+		Error:          t.GetError().GetMessage(),
+	}
+	for i, step := range t.GetSteps() {
+		trace.Steps[i] = step.UnpackProtoMessage()
+	}
+	if t.GetCall() != nil {
+		trace.Call = t.GetCall().UnpackProtoMessage()
+	}
+	return trace
+}
+
+func (r *ExecutionTraceResponse) PackProtoMessage(trace *rawapitypes.ExecutionTrace, err error) error {
+	if err != nil {
+		r.Result = &ExecutionTraceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &ExecutionTraceResponse_Data{Data: new(ExecutionTrace).PackProtoMessage(trace)}
+	return nil
+}
+
+func (r *ExecutionTraceResponse) UnpackProtoMessage() (*rawapitypes.ExecutionTrace, error) {
+	switch r.GetResult().(type) {
+	case *ExecutionTraceResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ExecutionTraceResponse_Data:
+		return r.GetData().UnpackProtoMessage(), nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// ReplayTransactionRequest converters
+
+func (r *ReplayTransactionRequest) PackProtoMessage(hash common.Hash, options rawapitypes.ReplayOptions) error {
+	r.Hash = new(Hash)
+	if err := r.GetHash().PackProtoMessage(hash); err != nil {
+		return err
+	}
+	r.Options = &ReplayOptions{
+		DisableSteps:    options.DisableSteps,
+		DisableCallTree: options.DisableCallTree,
+	}
+	return nil
+}
+
+func (r *ReplayTransactionRequest) UnpackProtoMessage() (common.Hash, rawapitypes.ReplayOptions, error) {
+	hash, err := r.GetHash().UnpackProtoMessage()
+	if err != nil {
+		return common.EmptyHash, rawapitypes.ReplayOptions{}, err
+	}
+	return hash, rawapitypes.ReplayOptions{
+		DisableSteps:    r.GetOptions().GetDisableSteps(),
+		DisableCallTree: r.GetOptions().GetDisableCallTree(),
+	}, nil
+}
+
+// ReplayResponse converters
+
+func (r *ReplayResponse) PackProtoMessage(result *rawapitypes.ReplayResult, err error) error {
+	if err != nil {
+		r.Result = &ReplayResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &ReplayResponse_Data{Data: &ReplayResult{
+		ReturnData: result.ReturnData,
+		Logs:       packLogs(result.Logs),
+		Trace:      new(ExecutionTrace).PackProtoMessage(result.Trace),
+		Mismatches: result.Mismatches,
+	}}
+	return nil
+}
+
+func (r *ReplayResponse) UnpackProtoMessage() (*rawapitypes.ReplayResult, error) {
+	switch r.GetResult().(type) {
+	case *ReplayResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ReplayResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.ReplayResult{
+			ReturnData: data.GetReturnData(),
+			Logs:       unpackLogs(data.GetLogs()),
+			Trace:      data.GetTrace().UnpackProtoMessage(),
+			Mismatches: data.GetMismatches(),
+		}, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// PendingResponsesRequest converters
+
+func (r *PendingResponsesRequest) PackProtoMessage(
+	address types.Address,
+	start types.TransactionIndex,
+	limit uint64,
+	blockReference rawapitypes.BlockReference,
+) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.Start = uint64(start)
+	r.Limit = limit
+	r.BlockReference = &BlockReference{}
+	return r.GetBlockReference().PackProtoMessage(blockReference)
+}
+
+func (r *PendingResponsesRequest) UnpackProtoMessage() (
+	types.Address, types.TransactionIndex, uint64, rawapitypes.BlockReference, error,
+) {
+	blockReference, err := r.GetBlockReference().UnpackProtoMessage()
+	if err != nil {
+		return types.EmptyAddress, 0, 0, rawapitypes.BlockReference{}, err
+	}
+
+	return r.GetAddress().UnpackProtoMessage(), types.TransactionIndex(r.GetStart()), r.GetLimit(), blockReference, nil
+}
+
+// PendingResponsesResponse converters
+
+func (r *PendingResponsesResponse) PackProtoMessage(page *rawapitypes.PendingResponses, err error) error {
+	if err != nil {
+		r.Result = &PendingResponsesResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &PendingResponses{
+		Responses: make([]*PendingResponse, len(page.Responses)),
+		Next:      (*uint64)(page.Next),
+	}
+	for i, response := range page.Responses {
+		data.Responses[i] = &PendingResponse{
+			RequestId:             uint64(response.RequestId),
+			ResponseProcessingGas: uint64(response.ResponseProcessingGas),
+		}
+	}
+	r.Result = &PendingResponsesResponse_Data{Data: data}
+	return nil
+}
+
+func (r *PendingResponsesResponse) UnpackProtoMessage() (*rawapitypes.PendingResponses, error) {
+	switch r.GetResult().(type) {
+	case *PendingResponsesResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *PendingResponsesResponse_Data:
+		data := r.GetData()
+		page := &rawapitypes.PendingResponses{
+			Responses: make([]*rawapitypes.PendingResponse, len(data.GetResponses())),
+			Next:      (*types.TransactionIndex)(data.Next), //nolint: protogetter
+		}
+		for i, response := range data.GetResponses() {
+			page.Responses[i] = &rawapitypes.PendingResponse{
+				RequestId:             types.TransactionIndex(response.GetRequestId()),
+				ResponseProcessingGas: types.Gas(response.GetResponseProcessingGas()),
+			}
+		}
+		return page, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// AwaitingContextResponse converters
+
+func (r *AwaitingContextResponse) PackProtoMessage(awaiting *rawapitypes.AwaitingContext, err error) error {
+	if err != nil {
+		r.Result = &AwaitingContextResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &AwaitingContext{
+		Flags:    uint32(awaiting.Flags.Bits),
+		From:     new(Address).PackProtoMessage(awaiting.From),
+		To:       new(Address).PackProtoMessage(awaiting.To),
+		Received: awaiting.Received,
+		Requests: make([]*AwaitedRequest, len(awaiting.Requests)),
+	}
+	for i, request := range awaiting.Requests {
+		data.Requests[i] = &AwaitedRequest{
+			Caller:    new(Address).PackProtoMessage(request.Caller),
+			RequestId: request.RequestId,
+			Pending:   request.Pending,
+		}
+	}
+	r.Result = &AwaitingContextResponse_Data{Data: data}
+	return nil
+}
+
+func (r *AwaitingContextResponse) UnpackProtoMessage() (*rawapitypes.AwaitingContext, error) {
+	switch r.GetResult().(type) {
+	case *AwaitingContextResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *AwaitingContextResponse_Data:
+		data := r.GetData()
+		awaiting := &rawapitypes.AwaitingContext{
+			Flags:    types.NewTransactionFlagsFromBits(uint8(data.GetFlags())),
+			From:     data.GetFrom().UnpackProtoMessage(),
+			To:       data.GetTo().UnpackProtoMessage(),
+			Received: data.GetReceived(),
+			Requests: make([]rawapitypes.AwaitedRequest, len(data.GetRequests())),
+		}
+		for i, request := range data.GetRequests() {
+			awaiting.Requests[i] = rawapitypes.AwaitedRequest{
+				Caller:    request.GetCaller().UnpackProtoMessage(),
+				RequestId: request.GetRequestId(),
+				Pending:   request.GetPending(),
+			}
+		}
+		return awaiting, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// StateDiffResponse converters
+
+func (d *StorageDiff) PackProtoMessage(diff rawapitypes.StorageDiff) error {
+	d.Key, d.Pre, d.Post = new(Hash), new(Hash), new(Hash)
+	if err := d.Key.PackProtoMessage(diff.Key); err != nil {
+		return err
+	}
+	if err := d.Pre.PackProtoMessage(diff.Pre); err != nil {
+		return err
+	}
+	return d.Post.PackProtoMessage(diff.Post)
+}
+
+func (d *StorageDiff) UnpackProtoMessage() (rawapitypes.StorageDiff, error) {
+	var diff rawapitypes.StorageDiff
+	var err error
+	if diff.Key, err = d.GetKey().UnpackProtoMessage(); err != nil {
+		return diff, err
+	}
+	if diff.Pre, err = d.GetPre().UnpackProtoMessage(); err != nil {
+		return diff, err
+	}
+	diff.Post, err = d.GetPost().UnpackProtoMessage()
+	return diff, err
+}
+
+func (d *AccountDiff) PackProtoMessage(diff rawapitypes.AccountDiff) error {
+	d.Address = new(Address).PackProtoMessage(diff.Address)
+	d.Deleted = diff.Deleted
+	if diff.Balance != nil {
+		d.Balance = &BalanceDiff{
+			Pre:  newUint256FromValue(diff.Balance.Pre),
+			Post: newUint256FromValue(diff.Balance.Post),
+		}
+	}
+	d.Storage = make([]*StorageDiff, len(diff.Storage))
+	for i, storageDiff := range diff.Storage {
+		d.Storage[i] = new(StorageDiff)
+		if err := d.Storage[i].PackProtoMessage(storageDiff); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *AccountDiff) UnpackProtoMessage() (rawapitypes.AccountDiff, error) {
+	diff := rawapitypes.AccountDiff{
+		Address: d.GetAddress().UnpackProtoMessage(),
+		Deleted: d.GetDeleted(),
+		Storage: make([]rawapitypes.StorageDiff, len(d.GetStorage())),
+	}
+	if d.GetBalance() != nil {
+		diff.Balance = &rawapitypes.BalanceDiff{
+			Pre:  newValueFromUint256(d.GetBalance().GetPre()),
+			Post: newValueFromUint256(d.GetBalance().GetPost()),
+		}
+	}
+	for i, storageDiff := range d.GetStorage() {
+		var err error
+		if diff.Storage[i], err = storageDiff.UnpackProtoMessage(); err != nil {
+			return diff, err
+		}
+	}
+	return diff, nil
+}
+
+func (r *StateDiffResponse) PackProtoMessage(stateDiff *rawapitypes.StateDiff, err error) error {
+	if err != nil {
+		r.Result = &StateDiffResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &StateDiff{Accounts: make([]*AccountDiff, len(stateDiff.Accounts))}
+	for i, accountDiff := range stateDiff.Accounts {
+		data.Accounts[i] = new(AccountDiff)
+		if err := data.Accounts[i].PackProtoMessage(accountDiff); err != nil {
+			return err
+		}
+	}
+	if len(stateDiff.Error) > 0 {
+		data.Error = &Error{Message: stateDiff.Error}
+	}
+	r.Result = &StateDiffResponse_Data{Data: data}
+	return nil
+}
+
+func (r *StateDiffResponse) UnpackProtoMessage() (*rawapitypes.StateDiff, error) {
+	switch r.GetResult().(type) {
+	case *StateDiffResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *StateDiffResponse_Data:
+		data := r.GetData()
+		stateDiff := &rawapitypes.StateDiff{
+			Accounts: make([]rawapitypes.AccountDiff, len(data.GetAccounts())),
+			Error:    data.GetError().GetMessage(),
+		}
+		for i, accountDiff := range data.GetAccounts() {
+			var err error
+			if stateDiff.Accounts[i], err = accountDiff.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
+		}
+		return stateDiff, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// Transaction converters
+func (ti *TransactionInfo) PackProtoMessage(info *rawapitypes.TransactionInfo) error {
+	var hash Hash
+	if err := hash.PackProtoMessage(info.BlockHash); err != nil {
+		return err
+	}
+
+	ti.TransactionSSZ = info.TransactionSSZ
+	ti.ReceiptSSZ = info.ReceiptSSZ
+	ti.Index = uint64(info.Index)
+	ti.BlockHash = &hash
+	ti.BlockId = uint64(info.BlockId)
+	return nil
+}
+
+func (ti *TransactionInfo) UnpackProtoMessage() (*rawapitypes.TransactionInfo, error) {
+	hash, err := ti.GetBlockHash().UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.TransactionInfo{
+		TransactionSSZ: ti.GetTransactionSSZ(),
+		ReceiptSSZ:     ti.GetReceiptSSZ(),
+		Index:          types.TransactionIndex(ti.GetIndex()),
+		BlockHash:      hash,
+		BlockId:        types.BlockNumber(ti.GetBlockId()),
+	}, nil
+}
+
+func (r *TransactionResponse) PackProtoMessage(info *rawapitypes.TransactionInfo, err error) error {
+	if err != nil {
+		r.Result = &TransactionResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := new(TransactionInfo)
+	if err := data.PackProtoMessage(info); err != nil {
+		return err
+	}
+	r.Result = &TransactionResponse_Data{Data: data}
+	return nil
+}
+
+func (r *TransactionResponse) UnpackProtoMessage() (*rawapitypes.TransactionInfo, error) {
+	switch r.GetResult().(type) {
+	case *TransactionResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *TransactionResponse_Data:
+		return r.GetData().UnpackProtoMessage()
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+func (r *TransactionsResponse) PackProtoMessage(infos []*rawapitypes.TransactionInfo, err error) error {
+	if err != nil {
+		r.Result = &TransactionsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &TransactionInfos{Transactions: make([]*TransactionInfo, len(infos))}
+	for i, info := range infos {
+		data.Transactions[i] = new(TransactionInfo)
+		if err := data.GetTransactions()[i].PackProtoMessage(info); err != nil {
+			return err
+		}
+	}
+	r.Result = &TransactionsResponse_Data{Data: data}
+	return nil
+}
+
+func (r *TransactionsResponse) UnpackProtoMessage() ([]*rawapitypes.TransactionInfo, error) {
+	switch r.GetResult().(type) {
+	case *TransactionsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *TransactionsResponse_Data:
+		transactions := r.GetData().GetTransactions()
+		infos := make([]*rawapitypes.TransactionInfo, len(transactions))
+		for i, transaction := range transactions {
+			var err error
+			if infos[i], err = transaction.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
+		}
+		return infos, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+func (r *TransactionRequestByBlockRefAndIndex) PackProtoMessage(
+	ref rawapitypes.BlockReference, index types.TransactionIndex,
+) error {
+	r.BlockRef = &BlockReference{}
+	if err := r.GetBlockRef().PackProtoMessage(ref); err != nil {
+		return err
+	}
+	r.Index = uint64(index)
+	return nil
+}
+
+func (r *TransactionRequestByBlockRefAndIndex) UnpackProtoMessage() (
+	rawapitypes.BlockReference, types.TransactionIndex, error,
+) {
+	ref, err := r.GetBlockRef().UnpackProtoMessage()
+	if err != nil {
+		return rawapitypes.BlockReference{}, 0, err
+	}
+	return ref, types.TransactionIndex(r.GetIndex()), nil
+}
+
+func (r *TransactionRequestByHash) PackProtoMessage(hash common.Hash) error {
+	r.Hash = &Hash{}
+	return r.GetHash().PackProtoMessage(hash)
+}
+
+func (r *TransactionRequestByHash) UnpackProtoMessage() (common.Hash, error) {
+	return r.GetHash().UnpackProtoMessage()
+}
+
+func (r *TransactionRequest) PackProtoMessage(request rawapitypes.TransactionRequest) error {
+	if request.ByHash != nil {
+		byHash := &TransactionRequestByHash{}
+		if err := byHash.PackProtoMessage(request.ByHash.Hash); err != nil {
+			return err
+		}
+		r.Request = &TransactionRequest_ByHash{
+			ByHash: byHash,
+		}
+	} else {
+		byBlockRefAndIndex := &TransactionRequestByBlockRefAndIndex{}
+		if err := byBlockRefAndIndex.PackProtoMessage(
+			request.ByBlockRefAndIndex.BlockRef,
+			request.ByBlockRefAndIndex.Index,
+		); err != nil {
+			return err
+		}
+		r.Request = &TransactionRequest_ByBlockRefAndIndex{
+			ByBlockRefAndIndex: byBlockRefAndIndex,
+		}
+	}
+	return nil
+}
+
+func (r *TransactionRequest) UnpackProtoMessage() (rawapitypes.TransactionRequest, error) {
+	byHash := r.GetByHash()
+	if byHash != nil {
+		hash, err := byHash.UnpackProtoMessage()
+		if err != nil {
+			return rawapitypes.TransactionRequest{}, err
+		}
+		return rawapitypes.TransactionRequest{
+			ByHash: &rawapitypes.TransactionRequestByHash{Hash: hash},
+		}, nil
+	}
+
+	byBlockRefAndIndex := r.GetByBlockRefAndIndex()
+	if byBlockRefAndIndex != nil {
+		ref, index, err := byBlockRefAndIndex.UnpackProtoMessage()
+		if err != nil {
+			return rawapitypes.TransactionRequest{}, err
+		}
+		return rawapitypes.TransactionRequest{
+			ByBlockRefAndIndex: &rawapitypes.TransactionRequestByBlockRefAndIndex{
+				BlockRef: ref,
+				Index:    index,
+			},
+		}, nil
+	}
+	return rawapitypes.TransactionRequest{}, errors.New("unexpected request type")
+}
+
+func (r *BlockTransactionIndexRequest) PackProtoMessage(
+	ref rawapitypes.BlockReference, index types.TransactionIndex,
+) error {
+	r.BlockRef = &BlockReference{}
+	if err := r.GetBlockRef().PackProtoMessage(ref); err != nil {
+		return err
+	}
+	r.Index = uint64(index)
+	return nil
+}
+
+func (r *BlockTransactionIndexRequest) UnpackProtoMessage() (
+	rawapitypes.BlockReference, types.TransactionIndex, error,
+) {
+	ref, err := r.GetBlockRef().UnpackProtoMessage()
+	if err != nil {
+		return rawapitypes.BlockReference{}, 0, err
+	}
+	return ref, types.TransactionIndex(r.GetIndex()), nil
+}
+
+// BounceInfoResponse converters
+
+func (r *BounceInfoResponse) PackProtoMessage(info *rawapitypes.BounceInfo, err error) error {
+	if err != nil {
+		r.Result = &BounceInfoResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &BounceInfo{
+		Success:            info.Success,
+		Bounced:            info.Bounced,
+		BounceTo:           new(Address).PackProtoMessage(info.BounceTo),
+		BouncedValue:       newUint256FromValue(info.BouncedValue),
+		BounceTransaction:  new(Hash),
+		RefundTo:           new(Address).PackProtoMessage(info.RefundTo),
+		Refunded:           newUint256FromValue(info.Refunded),
+		RefundTransactions: PackHashes(info.RefundTransactions),
+	}
+	if err := data.BounceTransaction.PackProtoMessage(info.BounceTransaction); err != nil {
+		return err
+	}
+	if len(info.ErrorMessage) > 0 {
+		data.ErrorMessage = &Error{Message: info.ErrorMessage}
+	}
+	r.Result = &BounceInfoResponse_Data{Data: data}
+	return nil
+}
+
+func (r *BounceInfoResponse) UnpackProtoMessage() (*rawapitypes.BounceInfo, error) {
+	switch r.GetResult().(type) {
+	case *BounceInfoResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *BounceInfoResponse_Data:
+		data := r.GetData()
+		bounceTransaction, err := data.GetBounceTransaction().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		return &rawapitypes.BounceInfo{
+			Success:            data.GetSuccess(),
+			ErrorMessage:       data.GetErrorMessage().GetMessage(),
+			Bounced:            data.GetBounced(),
+			BounceTo:           data.GetBounceTo().UnpackProtoMessage(),
+			BouncedValue:       newValueFromUint256(data.GetBouncedValue()),
+			BounceTransaction:  bounceTransaction,
+			RefundTo:           data.GetRefundTo().UnpackProtoMessage(),
+			Refunded:           newValueFromUint256(data.GetRefunded()),
+			RefundTransactions: UnpackHashes(data.GetRefundTransactions()),
+		}, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
+	}
+}
+
+// Receipt converters
+func (r *ReceiptInfo) PackProtoMessage(info *rawapitypes.ReceiptInfo) *ReceiptInfo {
+	if info == nil || info.ReceiptSSZ == nil {
+		return nil
+	}
+
+	var outReceipts []*ReceiptInfo
+	if len(info.OutReceipts) > 0 {
+		outReceipts = make([]*ReceiptInfo, len(info.OutReceipts))
+		for i, outReceipt := range info.OutReceipts {
+			if outReceipt != nil {
+				outReceipts[i] = new(ReceiptInfo).PackProtoMessage(outReceipt)
+			}
+		}
+	}
+
+	var gp *Uint256
+	if info.GasPrice.Uint256 != nil {
+		gp = new(Uint256).PackProtoMessage(*info.GasPrice.Uint256)
+	}
+
+	blockHash := &Hash{}
+	check.PanicIfErr(blockHash.PackProtoMessage(info.BlockHash))
+
+	return &ReceiptInfo{
+		Flags:           uint32(info.Flags.Bits),
+		ReceiptSSZ:      info.ReceiptSSZ,
+		Index:           uint64(info.Index),
+		BlockHash:       blockHash,
+		BlockId:         uint64(info.BlockId),
+		OutTransactions: PackHashes(info.OutTransactions),
+		OutReceipts:     outReceipts,
+		IncludedInMain:  info.IncludedInMain,
+		ErrorMessage:    &Error{Message: info.ErrorMessage},
+		GasPrice:        gp,
+		Temporary:       info.Temporary,
 	}
-	if contract.Code != nil {
-		x.Code = *contract.Code
+}
+
+func (r *ReceiptResponse) PackProtoMessage(info *rawapitypes.ReceiptInfo, err error) error {
+	if err != nil {
+		r.Result = &ReceiptResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
-	if contract.Balance != nil {
-		balance := new(Uint256)
-		if contract.Balance.Uint256 != nil {
-			balance.PackProtoMessage(*contract.Balance.Uint256)
+
+	r.Result = &ReceiptResponse_Data{
+		Data: new(ReceiptInfo).PackProtoMessage(info),
+	}
+	return nil
+}
+
+func (r *ReceiptInfo) UnpackProtoMessage() *rawapitypes.ReceiptInfo {
+	if r == nil || r.ReceiptSSZ == nil {
+		return nil
+	}
+
+	var outReceipts []*rawapitypes.ReceiptInfo
+	if len(r.GetOutReceipts()) > 0 {
+		outReceipts = make([]*rawapitypes.ReceiptInfo, len(r.GetOutReceipts()))
+		for i, outReceipt := range r.GetOutReceipts() {
+			outReceipts[i] = outReceipt.UnpackProtoMessage()
+		}
+	}
+
+	var errorMessage string
+	if r.GetErrorMessage() != nil {
+		errorMessage = r.GetErrorMessage().GetMessage()
+	}
+
+	blockHash, err := r.GetBlockHash().UnpackProtoMessage()
+	check.PanicIfErr(err)
+	return &rawapitypes.ReceiptInfo{
+		Flags:           types.NewTransactionFlagsFromBits(uint8(r.GetFlags())),
+		ReceiptSSZ:      r.GetReceiptSSZ(),
+		Index:           types.TransactionIndex(r.GetIndex()),
+		BlockHash:       blockHash,
+		BlockId:         types.BlockNumber(r.GetBlockId()),
+		OutTransactions: UnpackHashes(r.GetOutTransactions()),
+		OutReceipts:     outReceipts,
+		IncludedInMain:  r.GetIncludedInMain(),
+		ErrorMessage:    errorMessage,
+		GasPrice:        newValueFromUint256(r.GetGasPrice()),
+		Temporary:       r.GetTemporary(),
+	}
+}
+
+func (r *ReceiptResponse) UnpackProtoMessage() (*rawapitypes.ReceiptInfo, error) {
+	err := r.GetError()
+	if err != nil {
+		return nil, err.UnpackProtoMessage()
+	}
+	return r.GetData().UnpackProtoMessage(), nil
+}
+
+func (r *ReceiptsResponse) PackProtoMessage(infos []*rawapitypes.ReceiptInfo, err error) error {
+	if err != nil {
+		r.Result = &ReceiptsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &ReceiptInfos{Receipts: make([]*ReceiptInfo, len(infos))}
+	for i, info := range infos {
+		data.Receipts[i] = new(ReceiptInfo).PackProtoMessage(info)
+	}
+	r.Result = &ReceiptsResponse_Data{Data: data}
+	return nil
+}
+
+func (r *ReceiptsResponse) UnpackProtoMessage() ([]*rawapitypes.ReceiptInfo, error) {
+	switch r.GetResult().(type) {
+	case *ReceiptsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *ReceiptsResponse_Data:
+		receipts := r.GetData().GetReceipts()
+		infos := make([]*rawapitypes.ReceiptInfo, len(receipts))
+		for i, receipt := range receipts {
+			infos[i] = receipt.UnpackProtoMessage()
+		}
+		return infos, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+func (r *GasPriceResponse) PackProtoMessage(v types.Value, err error) error {
+	if err != nil {
+		r.Result = &GasPriceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	value := v.Uint256
+	if value == nil {
+		value = &types.Uint256{}
+	}
+
+	r.Result = &GasPriceResponse_Data{Data: new(Uint256).PackProtoMessage(*value)}
+	return nil
+}
+
+func (r *GasPriceResponse) UnpackProtoMessage() (types.Value, error) {
+	err := r.GetError()
+	if err != nil {
+		return types.Value{}, err.UnpackProtoMessage()
+	}
+	v := r.GetData()
+	if v == nil {
+		return types.NewZeroValue(), nil
+	}
+	return newValueFromUint256(v), nil
+}
+
+func (sr *ShardIdListResponse) PackProtoMessage(shardIdList []types.ShardId, err error) error {
+	if err != nil {
+		sr.Result = &ShardIdListResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	result := &ShardIdList{
+		Ids: make([]uint32, 0, len(shardIdList)),
+	}
+	for _, shardId := range shardIdList {
+		result.Ids = append(result.Ids, uint32(shardId))
+	}
+	sr.Result = &ShardIdListResponse_Data{Data: result}
+	return nil
+}
+
+func (sr *ShardIdListResponse) UnpackProtoMessage() ([]types.ShardId, error) {
+	switch sr.GetResult().(type) {
+	case *ShardIdListResponse_Error:
+		return nil, sr.GetError().UnpackProtoMessage()
+
+	case *ShardIdListResponse_Data:
+		data := sr.GetData()
+		if data == nil {
+			return nil, errors.New("unexpected response")
+		}
+
+		shardIdList := make([]types.ShardId, 0, len(data.GetIds()))
+		for _, id := range data.GetIds() {
+			shardIdList = append(shardIdList, types.ShardId(id))
+		}
+		return shardIdList, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// ChainConfigResponse converters
+
+func (r *ChainConfigResponse) PackProtoMessage(chainConfig *rawapitypes.ChainConfig, err error) error {
+	if err != nil {
+		r.Result = &ChainConfigResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &ChainConfig{
+		ChainId:         uint64(chainConfig.ChainId),
+		ProtocolVersion: chainConfig.ProtocolVersion,
+		GenesisHash:     new(Hash),
+		PatchLevel:      chainConfig.PatchLevel,
+		Shards:          make([]*ShardConfig, len(chainConfig.Shards)),
+	}
+	if err := data.GetGenesisHash().PackProtoMessage(chainConfig.GenesisHash); err != nil {
+		return err
+	}
+	for i, shard := range chainConfig.Shards {
+		data.Shards[i] = &ShardConfig{
+			Id:         uint32(shard.Id),
+			GasPrice:   newUint256FromValue(shard.GasPrice),
+			Validators: shard.Validators,
+		}
+	}
+	r.Result = &ChainConfigResponse_Data{Data: data}
+	return nil
+}
+
+func (r *ChainConfigResponse) UnpackProtoMessage() (*rawapitypes.ChainConfig, error) {
+	switch r.GetResult().(type) {
+	case *ChainConfigResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ChainConfigResponse_Data:
+		data := r.GetData()
+		genesisHash, err := data.GetGenesisHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		chainConfig := &rawapitypes.ChainConfig{
+			ChainId:         types.ChainId(data.GetChainId()),
+			ProtocolVersion: data.GetProtocolVersion(),
+			GenesisHash:     genesisHash,
+			PatchLevel:      data.GetPatchLevel(),
+			Shards:          make([]rawapitypes.ShardConfig, len(data.GetShards())),
+		}
+		for i, shard := range data.GetShards() {
+			chainConfig.Shards[i] = rawapitypes.ShardConfig{
+				Id:         types.ShardId(shard.GetId()),
+				GasPrice:   newValueFromUint256(shard.GetGasPrice()),
+				Validators: shard.GetValidators(),
+			}
+		}
+		return chainConfig, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// SyncStatusResponse converters
+
+func (r *SyncStatusResponse) PackProtoMessage(status *rawapitypes.SyncStatus, err error) error {
+	if err != nil {
+		r.Result = &SyncStatusResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &SyncStatusResponse_Data{Data: &SyncStatus{
+		CurrentBlock:      uint64(status.CurrentBlock),
+		HighestKnownBlock: uint64(status.HighestKnownBlock),
+		Phase:             uint32(status.Phase),
+		Peers:             status.Peers,
+	}}
+	return nil
+}
+
+func (r *SyncStatusResponse) UnpackProtoMessage() (*rawapitypes.SyncStatus, error) {
+	switch r.GetResult().(type) {
+	case *SyncStatusResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *SyncStatusResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.SyncStatus{
+			CurrentBlock:      types.BlockNumber(data.GetCurrentBlock()),
+			HighestKnownBlock: types.BlockNumber(data.GetHighestKnownBlock()),
+			Phase:             rawapitypes.SyncPhase(data.GetPhase()),
+			Peers:             data.GetPeers(),
+		}, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+func (v *TransactionVerdict) PackProtoMessage(verdict rawapitypes.TransactionVerdict) *TransactionVerdict {
+	v.FailedCheck = uint32(verdict.FailedCheck)
+	if verdict.Error != nil {
+		v.ErrorCode = uint32(verdict.Error.Code())
+		v.ErrorMessage = verdict.Error.Error()
+	}
+	return v
+}
+
+func (v *TransactionVerdict) UnpackProtoMessage() rawapitypes.TransactionVerdict {
+	verdict := rawapitypes.TransactionVerdict{FailedCheck: rawapitypes.TransactionCheck(v.GetFailedCheck())}
+	if verdict.Passed() {
+		return verdict
+	}
+
+	code := types.ErrorCode(v.GetErrorCode())
+	if details, ok := strings.CutPrefix(v.GetErrorMessage(), code.String()+": "); ok {
+		verdict.Error = types.NewVerboseError(code, details)
+	} else {
+		verdict.Error = types.NewError(code)
+	}
+	return verdict
+}
+
+func (r *SendTransactionResponse) PackProtoMessage(result *rawapitypes.SendTransactionResult, err error) error {
+	if err != nil {
+		r.Result = &SendTransactionResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &SendTransactionResponse_Status{
+		Status: uint32(result.DiscardReason),
+	}
+	r.Hash = &Hash{}
+	if err := r.GetHash().PackProtoMessage(result.Hash); err != nil {
+		return err
+	}
+	r.ShardId = uint32(result.ShardId)
+	r.Verdict = new(TransactionVerdict).PackProtoMessage(result.Verdict)
+	return nil
+}
+
+func (r *SendTransactionResponse) UnpackProtoMessage() (*rawapitypes.SendTransactionResult, error) {
+	err := r.GetError()
+	if err != nil {
+		return nil, err.UnpackProtoMessage()
+	}
+
+	hash, unpackErr := r.GetHash().UnpackProtoMessage()
+	if unpackErr != nil {
+		return nil, unpackErr
+	}
+	return &rawapitypes.SendTransactionResult{
+		Hash:          hash,
+		ShardId:       types.ShardId(r.GetShardId()),
+		Verdict:       r.GetVerdict().UnpackProtoMessage(),
+		DiscardReason: txnpool.DiscardReason(r.GetStatus()),
+	}, nil
+}
+
+func (r *SendTransactionRequest) PackProtoMessage(
+	transactionSSZ []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string,
+) error {
+	r.TransactionSSZ = transactionSSZ
+	r.Replacement = ReplacementPolicy(replacement)
+	r.IdempotencyKey = idempotencyKey
+	return nil
+}
+
+func (r *SendTransactionRequest) UnpackProtoMessage() ([]byte, txnpool.ReplacementPolicy, string, error) {
+	return r.GetTransactionSSZ(), txnpool.ReplacementPolicy(r.GetReplacement()), r.GetIdempotencyKey(), nil
+}
+
+func (r *ResendTransactionRequest) PackProtoMessage(
+	transactionSSZ []byte, replacement txnpool.ReplacementPolicy,
+) error {
+	r.TransactionSSZ = transactionSSZ
+	r.Replacement = ReplacementPolicy(replacement)
+	return nil
+}
+
+func (r *ResendTransactionRequest) UnpackProtoMessage() ([]byte, txnpool.ReplacementPolicy, error) {
+	return r.GetTransactionSSZ(), txnpool.ReplacementPolicy(r.GetReplacement()), nil
+}
+
+// SendBundleResponse converters
+
+func (r *SendTransactionResult) PackProtoMessage(result *rawapitypes.SendTransactionResult) error {
+	r.Hash = &Hash{}
+	if err := r.GetHash().PackProtoMessage(result.Hash); err != nil {
+		return err
+	}
+	r.ShardId = uint32(result.ShardId)
+	r.Verdict = new(TransactionVerdict).PackProtoMessage(result.Verdict)
+	r.Status = uint32(result.DiscardReason)
+	return nil
+}
+
+func (r *SendTransactionResult) UnpackProtoMessage() (*rawapitypes.SendTransactionResult, error) {
+	hash, err := r.GetHash().UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.SendTransactionResult{
+		Hash:          hash,
+		ShardId:       types.ShardId(r.GetShardId()),
+		Verdict:       r.GetVerdict().UnpackProtoMessage(),
+		DiscardReason: txnpool.DiscardReason(r.GetStatus()),
+	}, nil
+}
+
+func (r *SendBundleResponse) PackProtoMessage(results []*rawapitypes.SendTransactionResult, err error) error {
+	if err != nil {
+		r.Result = &SendBundleResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &SendBundleResults{Results: make([]*SendTransactionResult, len(results))}
+	for i, result := range results {
+		data.Results[i] = &SendTransactionResult{}
+		if err := data.Results[i].PackProtoMessage(result); err != nil {
+			return err
 		}
-		x.Balance = balance
 	}
-	if contract.State != nil {
-		x.State = make(map[string]*Hash)
-		for k, v := range *contract.State {
-			kHex := k.Hex()
-			x.State[kHex] = &Hash{}
-			check.PanicIfErr(x.GetState()[kHex].PackProtoMessage(v))
+	r.Result = &SendBundleResponse_Data{Data: data}
+	return nil
+}
+
+func (r *SendBundleResponse) UnpackProtoMessage() ([]*rawapitypes.SendTransactionResult, error) {
+	switch r.GetResult().(type) {
+	case *SendBundleResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *SendBundleResponse_Data:
+		data := r.GetData().GetResults()
+		results := make([]*rawapitypes.SendTransactionResult, len(data))
+		for i, result := range data {
+			var err error
+			if results[i], err = result.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
 		}
+		return results, nil
 	}
-	if contract.StateDiff != nil {
-		x.StateDiff = make(map[string]*Hash)
-		for k, v := range *contract.StateDiff {
-			kHex := k.Hex()
-			x.StateDiff[kHex] = &Hash{}
-			check.PanicIfErr(x.GetStateDiff()[kHex].PackProtoMessage(v))
+	return nil, errors.New("unexpected response type")
+}
+
+func (r *SendBundleRequest) PackProtoMessage(transactionsSSZ [][]byte, sameBlock bool) error {
+	r.TransactionsSSZ = transactionsSSZ
+	r.SameBlock = sameBlock
+	return nil
+}
+
+func (r *SendBundleRequest) UnpackProtoMessage() ([][]byte, bool, error) {
+	return r.GetTransactionsSSZ(), r.GetSameBlock(), nil
+}
+
+// TransactionEventResponse converters
+
+func (r *TransactionEventResponse) PackProtoMessage(event *rawapitypes.TransactionEvent, err error) error {
+	if err != nil {
+		r.Result = &TransactionEventResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &TransactionEvent{
+		Type:         uint32(event.Type),
+		BlockNumber:  uint64(event.BlockNumber),
+		BlockHash:    &Hash{},
+		ErrorMessage: event.ErrorMessage,
+	}
+	if err := data.GetBlockHash().PackProtoMessage(event.BlockHash); err != nil {
+		return err
+	}
+	if event.Result != nil {
+		data.Result = &SendTransactionResult{}
+		if err := data.GetResult().PackProtoMessage(event.Result); err != nil {
+			return err
 		}
 	}
-	if contract.AsyncContext != nil {
-		x.AsyncContext = make(map[uint64]*AsyncContext)
-		for k, v := range *contract.AsyncContext {
-			if v != nil {
-				x.AsyncContext[uint64(k)] = &AsyncContext{}
-				x.GetAsyncContext()[uint64(k)].PackProtoMessage(v)
+	r.Result = &TransactionEventResponse_Data{Data: data}
+	return nil
+}
+
+func (r *TransactionEventResponse) UnpackProtoMessage() (*rawapitypes.TransactionEvent, error) {
+	switch r.GetResult().(type) {
+	case *TransactionEventResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *TransactionEventResponse_Data:
+		data := r.GetData()
+		blockHash, err := data.GetBlockHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		event := &rawapitypes.TransactionEvent{
+			Type:         rawapitypes.TransactionEventType(data.GetType()),
+			BlockNumber:  types.BlockNumber(data.GetBlockNumber()),
+			BlockHash:    blockHash,
+			ErrorMessage: data.GetErrorMessage(),
+		}
+		if data.GetResult() != nil {
+			if event.Result, err = data.GetResult().UnpackProtoMessage(); err != nil {
+				return nil, err
 			}
 		}
+		return event, nil
 	}
-	return x
+	return nil, errors.New("unexpected response type")
 }
 
-func (x *CallArgs) PackProtoMessage(args rpctypes.CallArgs) *CallArgs {
-	x.Flags = uint32(args.Flags.Bits)
-	if args.From != nil {
-		x.From = new(Address).PackProtoMessage(*args.From)
-	}
-	x.To = new(Address).PackProtoMessage(args.To)
-	if args.Fee.FeeCredit.Uint256 != nil {
-		x.FeeCredit = new(Uint256).PackProtoMessage(*args.Fee.FeeCredit.Uint256)
-	}
-	if args.Fee.MaxFeePerGas.Uint256 != nil {
-		x.MaxFeePerGas = new(Uint256).PackProtoMessage(*args.Fee.MaxFeePerGas.Uint256)
+// TransactionStatusResponse converters
+
+func (r *TransactionStatusResponse) PackProtoMessage(info *rawapitypes.TransactionStatusInfo, err error) error {
+	if err != nil {
+		r.Result = &TransactionStatusResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
-	if args.Fee.MaxPriorityFeePerGas.Uint256 != nil {
-		x.MaxPriorityFeePerGas = new(Uint256).PackProtoMessage(*args.Fee.MaxPriorityFeePerGas.Uint256)
+
+	r.Result = &TransactionStatusResponse_Data{
+		Data: &TransactionStatusInfo{
+			Status:       uint32(info.Status),
+			ErrorMessage: info.ErrorMessage,
+		},
 	}
-	if args.Value.Uint256 != nil {
-		x.Value = new(Uint256).PackProtoMessage(*args.Value.Uint256)
+	return nil
+}
+
+func (r *TransactionStatusResponse) UnpackProtoMessage() (*rawapitypes.TransactionStatusInfo, error) {
+	switch r.GetResult().(type) {
+	case *TransactionStatusResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *TransactionStatusResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.TransactionStatusInfo{
+			Status:       rawapitypes.TransactionStatus(data.GetStatus()),
+			ErrorMessage: data.GetErrorMessage(),
+		}, nil
 	}
-	x.Seqno = args.Seqno.Uint64()
-	if args.Data != nil {
-		x.Data = *args.Data
+	return nil, errors.New("unexpected response type")
+}
+
+func (txn *RawTxnsResponse) PackProtoMessage(txns []*types.Transaction, err error) error {
+	if err != nil {
+		txn.Result = &RawTxnsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
-	if args.Transaction != nil {
-		x.Transaction = *args.Transaction
+
+	var rawTxns RawTxns
+	rawTxns.Data, err = sszx.EncodeContainer[*types.Transaction](txns)
+	if err != nil {
+		return err
 	}
-	x.ChainId = uint64(args.ChainId)
-	return x
+	txn.Result = &RawTxnsResponse_Data{Data: &rawTxns}
+	return nil
 }
 
-func (o *StateOverrides) PackProtoMessage(overrides *rpctypes.StateOverrides) *StateOverrides {
-	if overrides != nil {
-		o.Overrides = make(map[string]*Contract)
-		for k, v := range *overrides {
-			o.Overrides[k.Hex()] = new(Contract).PackProtoMessage(v)
+func (txn *RawTxnsResponse) UnpackProtoMessage() ([]*types.Transaction, error) {
+	switch txn.GetResult().(type) {
+	case *RawTxnsResponse_Error:
+		return nil, txn.GetError().UnpackProtoMessage()
+
+	case *RawTxnsResponse_Data:
+		dataResult := txn.GetData()
+		if dataResult == nil {
+			return nil, errors.New("unexpected response")
+		}
+		data := dataResult.GetData()
+		if data == nil {
+			return []*types.Transaction{}, nil
 		}
+		return sszx.DecodeContainer[*types.Transaction](data)
 	}
-	return o
+	return nil, errors.New("unexpected response type")
 }
 
-func (brd *BlockReferenceOrHashWithChildren) PackProtoMessage(
-	blockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
-) error {
-	if blockReferenceOrHashWithChildren.IsReference() {
-		blockReference := new(BlockReference)
-		if err := blockReference.PackProtoMessage(blockReferenceOrHashWithChildren.Reference()); err != nil {
-			return err
+// PoolContentResponse converters
+
+func (r *PoolAccountRequest) PackProtoMessage(address types.Address) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	return nil
+}
+
+func (r *PoolAccountRequest) UnpackProtoMessage() (types.Address, error) {
+	return r.GetAddress().UnpackProtoMessage(), nil
+}
+
+func (r *PoolContentResponse) PackProtoMessage(content *rawapitypes.PoolContent, err error) error {
+	if err != nil {
+		r.Result = &PoolContentResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	pending, err := sszx.EncodeContainer[*types.Transaction](content.Pending)
+	if err != nil {
+		return err
+	}
+	queued, err := sszx.EncodeContainer[*types.Transaction](content.Queued)
+	if err != nil {
+		return err
+	}
+	r.Result = &PoolContentResponse_Data{Data: &PoolContent{
+		Pending: &RawTxns{Data: pending},
+		Queued:  &RawTxns{Data: queued},
+	}}
+	return nil
+}
+
+func (r *PoolContentResponse) UnpackProtoMessage() (*rawapitypes.PoolContent, error) {
+	switch r.GetResult().(type) {
+	case *PoolContentResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *PoolContentResponse_Data:
+		pending, err := sszx.DecodeContainer[*types.Transaction](r.GetData().GetPending().GetData())
+		if err != nil {
+			return nil, err
 		}
-		brd.BlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren_BlockReference{
-			BlockReference: blockReference,
+		queued, err := sszx.DecodeContainer[*types.Transaction](r.GetData().GetQueued().GetData())
+		if err != nil {
+			return nil, err
 		}
-	} else {
-		hash, childBlocks := blockReferenceOrHashWithChildren.HashAndChildren()
-		blockHashWithChildren := new(BlockHashWithChildren)
+		return &rawapitypes.PoolContent{Pending: pending, Queued: queued}, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
 
-		blockHashWithChildren.Hash = new(Hash)
-		if err := blockHashWithChildren.GetHash().PackProtoMessage(hash); err != nil {
-			return err
+// PoolStatusResponse converters
+
+func (r *PoolStatusResponse) PackProtoMessage(status *rawapitypes.PoolStatus, err error) error {
+	if err != nil {
+		r.Result = &PoolStatusResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &PoolStatusResponse_Data{Data: &PoolStatus{
+		Pending: status.Pending,
+		Queued:  status.Queued,
+		BaseFee: newUint256FromValue(status.BaseFee),
+	}}
+	return nil
+}
+
+func (r *PoolStatusResponse) UnpackProtoMessage() (*rawapitypes.PoolStatus, error) {
+	switch r.GetResult().(type) {
+	case *PoolStatusResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *PoolStatusResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.PoolStatus{
+			Pending: data.GetPending(),
+			Queued:  data.GetQueued(),
+			BaseFee: newValueFromUint256(data.GetBaseFee()),
+		}, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// SnapshotManifestResponse converters
+
+func (r *SnapshotManifestResponse) PackProtoMessage(manifest *rawapitypes.SnapshotManifest, err error) error {
+	if err != nil {
+		r.Result = &SnapshotManifestResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	blockHash := new(Hash)
+	if err := blockHash.PackProtoMessage(manifest.BlockHash); err != nil {
+		return err
+	}
+	r.Result = &SnapshotManifestResponse_Data{Data: &SnapshotManifest{
+		BlockSSZ:    manifest.BlockSSZ,
+		BlockHash:   blockHash,
+		ChildBlocks: PackHashes(manifest.ChildBlocks),
+		Config:      manifest.Config,
+	}}
+	return nil
+}
+
+func (r *SnapshotManifestResponse) UnpackProtoMessage() (*rawapitypes.SnapshotManifest, error) {
+	switch r.GetResult().(type) {
+	case *SnapshotManifestResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *SnapshotManifestResponse_Data:
+		data := r.GetData()
+		blockHash, err := data.GetBlockHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
 		}
+		return &rawapitypes.SnapshotManifest{
+			BlockSSZ:    data.GetBlockSSZ(),
+			BlockHash:   blockHash,
+			ChildBlocks: UnpackHashes(data.GetChildBlocks()),
+			Config:      data.GetConfig(),
+		}, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
 
-		for _, childBlock := range childBlocks {
-			childBlockHash := new(Hash)
-			if err := childBlockHash.PackProtoMessage(childBlock); err != nil {
-				return err
-			}
-			blockHashWithChildren.Children = append(blockHashWithChildren.Children, childBlockHash)
+// SnapshotChunkRequest converters
+
+func (r *SnapshotChunkRequest) PackProtoMessage(request rawapitypes.SnapshotChunkRequest) error {
+	r.BlockHash = new(Hash)
+	if err := r.BlockHash.PackProtoMessage(request.BlockHash); err != nil {
+		return err
+	}
+	r.Start = new(Hash)
+	if err := r.Start.PackProtoMessage(request.Start); err != nil {
+		return err
+	}
+	r.Limit = request.Limit
+	return nil
+}
+
+func (r *SnapshotChunkRequest) UnpackProtoMessage() (rawapitypes.SnapshotChunkRequest, error) {
+	blockHash, err := r.GetBlockHash().UnpackProtoMessage()
+	if err != nil {
+		return rawapitypes.SnapshotChunkRequest{}, err
+	}
+	start, err := r.GetStart().UnpackProtoMessage()
+	if err != nil {
+		return rawapitypes.SnapshotChunkRequest{}, err
+	}
+	return rawapitypes.SnapshotChunkRequest{BlockHash: blockHash, Start: start, Limit: r.GetLimit()}, nil
+}
+
+// SnapshotChunkResponse converters
+
+func (r *SnapshotChunkResponse) PackProtoMessage(chunk *rawapitypes.SnapshotChunk, err error) error {
+	if err != nil {
+		r.Result = &SnapshotChunkResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	data := &SnapshotChunk{Contracts: make([]*RawContract, len(chunk.Contracts))}
+	for i, contract := range chunk.Contracts {
+		data.Contracts[i] = new(RawContract)
+		if err := data.Contracts[i].PackProtoMessage(contract); err != nil {
+			return err
 		}
-		brd.BlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren_BlockHashWithChildren{
-			BlockHashWithChildren: blockHashWithChildren,
+	}
+	if chunk.Next != nil {
+		data.Next = new(Hash)
+		if err := data.Next.PackProtoMessage(*chunk.Next); err != nil {
+			return err
 		}
 	}
+	r.Result = &SnapshotChunkResponse_Data{Data: data}
 	return nil
 }
 
-func (brd *BlockReferenceOrHashWithChildren) UnpackProtoMessage() (
-	rawapitypes.BlockReferenceOrHashWithChildren, error,
-) {
-	switch brd.GetBlockReferenceOrHashWithChildren().(type) {
-	case *BlockReferenceOrHashWithChildren_BlockReference:
-		blockReference, err := brd.GetBlockReference().UnpackProtoMessage()
-		return rawapitypes.BlockReferenceAsBlockReferenceOrHashWithChildren(blockReference), err
+func (r *SnapshotChunkResponse) UnpackProtoMessage() (*rawapitypes.SnapshotChunk, error) {
+	switch r.GetResult().(type) {
+	case *SnapshotChunkResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
 
-	case *BlockReferenceOrHashWithChildren_BlockHashWithChildren:
-		blockWithChildren := brd.GetBlockHashWithChildren()
-		hash, err := blockWithChildren.GetHash().UnpackProtoMessage()
-		if err != nil {
-			return rawapitypes.BlockReferenceOrHashWithChildren{}, err
+	case *SnapshotChunkResponse_Data:
+		data := r.GetData()
+		chunk := &rawapitypes.SnapshotChunk{Contracts: make([]*rawapitypes.SmartContract, len(data.GetContracts()))}
+		for i, contract := range data.GetContracts() {
+			var err error
+			if chunk.Contracts[i], err = contract.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
 		}
-		children := make([]common.Hash, len(blockWithChildren.GetChildren()))
-		for i, child := range blockWithChildren.GetChildren() {
-			children[i], err = child.UnpackProtoMessage()
+		if data.GetNext() != nil {
+			next, err := data.GetNext().UnpackProtoMessage()
 			if err != nil {
-				return rawapitypes.BlockReferenceOrHashWithChildren{}, err
+				return nil, err
 			}
+			chunk.Next = &next
 		}
-		return rawapitypes.BlockHashWithChildrenAsBlockReferenceOrHashWithChildren(hash, children), err
+		return chunk, nil
 	}
-	return rawapitypes.BlockReferenceOrHashWithChildren{}, errors.New("unexpected block reference or data type")
+	return nil, errors.New("unexpected response type")
 }
 
-func (cr *CallRequest) PackProtoMessage(
-	args rpctypes.CallArgs,
-	mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren,
-	overrides *rpctypes.StateOverrides,
-) error {
-	cr.Args = new(CallArgs).PackProtoMessage(args)
+// BlocksSinceRequest converters
 
-	cr.MainBlockReferenceOrHashWithChildren = &BlockReferenceOrHashWithChildren{}
-	err := cr.GetMainBlockReferenceOrHashWithChildren().PackProtoMessage(mainBlockReferenceOrHashWithChildren)
-	if err != nil {
-		return err
-	}
+func (r *BlocksSinceRequest) PackProtoMessage(sequence types.BlockNumber, limit uint64) error {
+	r.Sequence = uint64(sequence)
+	r.Limit = limit
+	return nil
+}
 
-	if overrides != nil {
-		cr.StateOverrides = new(StateOverrides).PackProtoMessage(overrides)
-	}
+func (r *BlocksSinceRequest) UnpackProtoMessage() (types.BlockNumber, uint64, error) {
+	return types.BlockNumber(r.GetSequence()), r.GetLimit(), nil
+}
+
+// SetLogLevelRequest converters
 
+func (r *SetLogLevelRequest) PackProtoMessage(level string) error {
+	r.Level = level
 	return nil
 }
 
-func (x *CallArgs) UnpackProtoMessage() rpctypes.CallArgs {
-	args := rpctypes.CallArgs{}
-	args.Flags = types.TransactionFlags{BitFlags: types.BitFlags[uint8]{Bits: uint8(x.GetFlags())}}
-	if x.GetFrom() != nil {
-		a := x.GetFrom().UnpackProtoMessage()
-		args.From = &a
-	}
-	args.To = x.GetTo().UnpackProtoMessage()
+func (r *SetLogLevelRequest) UnpackProtoMessage() (string, error) {
+	return r.GetLevel(), nil
+}
 
-	args.Fee.FeeCredit = newValueFromUint256(x.GetFeeCredit())
-	args.Fee.MaxFeePerGas = newValueFromUint256(x.GetMaxFeePerGas())
-	args.Fee.MaxPriorityFeePerGas = newValueFromUint256(x.GetMaxPriorityFeePerGas())
-	args.Value = newValueFromUint256(x.GetValue())
-	args.Seqno = types.Seqno(x.GetSeqno())
+// DisconnectPeerRequest converters
 
-	if x.Data != nil {
-		args.Data = (*hexutil.Bytes)(&x.Data)
+func (r *DisconnectPeerRequest) PackProtoMessage(peerId network.PeerID) error {
+	r.PeerId = []byte(peerId)
+	return nil
+}
+
+func (r *DisconnectPeerRequest) UnpackProtoMessage() (network.PeerID, error) {
+	peerId := network.PeerID(r.GetPeerId())
+	if err := peerId.Validate(); err != nil {
+		return "", rawapitypes.NewInvalidArgumentError(err)
 	}
+	return peerId, nil
+}
 
-	if x.Transaction != nil {
-		args.Transaction = (*hexutil.Bytes)(&x.Transaction)
+// AddPeerRequest converters
+
+func (r *AddPeerRequest) PackProtoMessage(addr network.AddrInfo) error {
+	r.PeerId = []byte(addr.ID)
+	r.Addrs = make([]string, len(addr.Addrs))
+	for i, a := range addr.Addrs {
+		r.Addrs[i] = a.String()
 	}
+	return nil
+}
 
-	args.ChainId = types.ChainId(x.GetChainId())
-	return args
+func (r *AddPeerRequest) UnpackProtoMessage() (network.AddrInfo, error) {
+	addr := network.AddrInfo{ID: network.PeerID(r.GetPeerId())}
+	if err := addr.ID.Validate(); err != nil {
+		return network.AddrInfo{}, rawapitypes.NewInvalidArgumentError(err)
+	}
+	for _, a := range r.GetAddrs() {
+		multiaddr, err := ma.NewMultiaddr(a)
+		if err != nil {
+			return network.AddrInfo{}, rawapitypes.NewInvalidArgumentError(err)
+		}
+		addr.Addrs = append(addr.Addrs, multiaddr)
+	}
+	return addr, nil
 }
 
-func (x *Contract) UnpackProtoMessage() rpctypes.Contract {
-	c := rpctypes.Contract{}
+// ProtectPeerRequest converters
 
-	c.Seqno = (*types.Seqno)(x.Seqno)       //nolint: protogetter
-	c.ExtSeqno = (*types.Seqno)(x.ExtSeqno) //nolint: protogetter
+func (r *ProtectPeerRequest) PackProtoMessage(peerId network.PeerID, tag string, protect bool) error {
+	r.PeerId = []byte(peerId)
+	r.Tag = tag
+	r.Protect = protect
+	return nil
+}
 
-	if len(x.GetCode()) > 0 {
-		c.Code = (*hexutil.Bytes)(&x.Code)
+func (r *ProtectPeerRequest) UnpackProtoMessage() (network.PeerID, string, bool, error) {
+	peerId := network.PeerID(r.GetPeerId())
+	if err := peerId.Validate(); err != nil {
+		return "", "", false, rawapitypes.NewInvalidArgumentError(err)
 	}
-
-	if x.GetBalance() != nil {
-		v := newValueFromUint256(x.GetBalance())
-		c.Balance = &v
+	if r.GetTag() == "" {
+		return "", "", false, rawapitypes.NewInvalidArgumentError(errors.New("tag is empty"))
 	}
+	return peerId, r.GetTag(), r.GetProtect(), nil
+}
 
-	if len(x.GetState()) > 0 {
-		m := make(map[common.Hash]common.Hash)
-		for k, v := range x.GetState() {
-			var err error
-			m[common.HexToHash(k)], err = v.UnpackProtoMessage()
-			check.PanicIfErr(err)
-		}
-		c.State = &m
+// SetPeerTagRequest converters
+
+func (r *SetPeerTagRequest) PackProtoMessage(peerId network.PeerID, tag string, value int64) error {
+	r.PeerId = []byte(peerId)
+	r.Tag = tag
+	r.Value = value
+	return nil
+}
+
+func (r *SetPeerTagRequest) UnpackProtoMessage() (network.PeerID, string, int64, error) {
+	peerId := network.PeerID(r.GetPeerId())
+	if err := peerId.Validate(); err != nil {
+		return "", "", 0, rawapitypes.NewInvalidArgumentError(err)
 	}
+	if r.GetTag() == "" {
+		return "", "", 0, rawapitypes.NewInvalidArgumentError(errors.New("tag is empty"))
+	}
+	return peerId, r.GetTag(), r.GetValue(), nil
+}
 
-	if len(x.GetStateDiff()) > 0 {
-		m := make(map[common.Hash]common.Hash)
-		for k, v := range x.GetStateDiff() {
-			var err error
-			m[common.HexToHash(k)], err = v.UnpackProtoMessage()
-			check.PanicIfErr(err)
+// PeerAcl converters
+
+func (a *PeerAcl) PackProtoMessage(acl rawapitypes.PeerAcl) error {
+	a.Entries = make(map[string]*PeerIds, len(acl))
+	for protocol, peers := range acl {
+		peerIds := &PeerIds{PeerIds: make([][]byte, len(peers))}
+		for i, peerId := range peers {
+			peerIds.PeerIds[i] = []byte(peerId)
 		}
-		c.StateDiff = &m
+		a.Entries[protocol] = peerIds
 	}
+	return nil
+}
 
-	if len(x.GetAsyncContext()) > 0 {
-		m := make(map[types.TransactionIndex]*types.AsyncContext)
-		for k, v := range x.GetAsyncContext() {
-			var ac *types.AsyncContext
-			if v != nil {
-				v := v.UnpackProtoMessage()
-				ac = &v
+func (a *PeerAcl) UnpackProtoMessage() (rawapitypes.PeerAcl, error) {
+	acl := make(rawapitypes.PeerAcl, len(a.GetEntries()))
+	for protocol, peerIds := range a.GetEntries() {
+		peers := make([]network.PeerID, len(peerIds.GetPeerIds()))
+		for i, rawPeerId := range peerIds.GetPeerIds() {
+			peers[i] = network.PeerID(rawPeerId)
+			if err := peers[i].Validate(); err != nil {
+				return nil, rawapitypes.NewInvalidArgumentError(fmt.Errorf("peer %d of %s: %w", i, protocol, err))
 			}
-			m[types.TransactionIndex(k)] = ac
 		}
-		c.AsyncContext = &m
+		acl[protocol] = peers
 	}
-
-	return c
+	return acl, nil
 }
 
-func (cr *StateOverrides) UnpackProtoMessage() *rpctypes.StateOverrides {
-	if cr == nil {
+// PeerAclResponse converters
+
+func (r *PeerAclResponse) PackProtoMessage(acl rawapitypes.PeerAcl, err error) error {
+	if err != nil {
+		r.Result = &PeerAclResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
+	data := new(PeerAcl)
+	if err := data.PackProtoMessage(acl); err != nil {
+		return err
+	}
+	r.Result = &PeerAclResponse_Data{Data: data}
+	return nil
+}
 
-	args := make(rpctypes.StateOverrides)
-	for k, v := range cr.GetOverrides() {
-		args[types.HexToAddress(k)] = v.UnpackProtoMessage()
+func (r *PeerAclResponse) UnpackProtoMessage() (rawapitypes.PeerAcl, error) {
+	switch r.GetResult().(type) {
+	case *PeerAclResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *PeerAclResponse_Data:
+		return r.GetData().UnpackProtoMessage()
 	}
-	return &args
+	return nil, errors.New("unexpected response type")
 }
 
-func (cr *CallRequest) UnpackProtoMessage() (
-	rpctypes.CallArgs,
-	rawapitypes.BlockReferenceOrHashWithChildren,
-	*rpctypes.StateOverrides,
-	error,
-) {
-	br, err := cr.GetMainBlockReferenceOrHashWithChildren().UnpackProtoMessage()
+// PeerUsages converters
+
+func (u *PeerUsages) PackProtoMessage(usages []rawapitypes.PeerUsage) error {
+	u.Peers = make([]*PeerUsage, len(usages))
+	for i, usage := range usages {
+		var bannedUntil int64
+		if !usage.BannedUntil.IsZero() {
+			bannedUntil = usage.BannedUntil.UnixMilli()
+		}
+		u.Peers[i] = &PeerUsage{
+			PeerId:        []byte(usage.PeerId),
+			Requests:      usage.Requests,
+			RequestBytes:  usage.RequestBytes,
+			ResponseBytes: usage.ResponseBytes,
+			CallTime:      uint64(usage.CallTime),
+			Bans:          usage.Bans,
+			BannedUntil:   bannedUntil,
+		}
+	}
+	return nil
+}
+
+func (u *PeerUsages) UnpackProtoMessage() ([]rawapitypes.PeerUsage, error) {
+	usages := make([]rawapitypes.PeerUsage, len(u.GetPeers()))
+	for i, peer := range u.GetPeers() {
+		peerId := network.PeerID(peer.GetPeerId())
+		if err := peerId.Validate(); err != nil {
+			return nil, fmt.Errorf("peer %d: %w", i, err)
+		}
+		usages[i] = rawapitypes.PeerUsage{
+			PeerId:        peerId,
+			Requests:      peer.GetRequests(),
+			RequestBytes:  peer.GetRequestBytes(),
+			ResponseBytes: peer.GetResponseBytes(),
+			CallTime:      time.Duration(peer.GetCallTime()),
+			Bans:          peer.GetBans(),
+		}
+		if peer.GetBannedUntil() != 0 {
+			usages[i].BannedUntil = time.UnixMilli(peer.GetBannedUntil())
+		}
+	}
+	return usages, nil
+}
+
+// PeerUsageResponse converters
+
+func (r *PeerUsageResponse) PackProtoMessage(usages []rawapitypes.PeerUsage, err error) error {
 	if err != nil {
-		return rpctypes.CallArgs{}, rawapitypes.BlockReferenceOrHashWithChildren{}, nil, err
+		r.Result = &PeerUsageResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+	data := new(PeerUsages)
+	if err := data.PackProtoMessage(usages); err != nil {
+		return err
 	}
-	return cr.GetArgs().UnpackProtoMessage(), br, cr.GetStateOverrides().UnpackProtoMessage(), nil
+	r.Result = &PeerUsageResponse_Data{Data: data}
+	return nil
 }
 
-func (m *OutTransaction) PackProtoMessage(txn *rpctypes.OutTransaction) *OutTransaction {
-	coinsUsed := new(Uint256)
-	if txn.CoinsUsed.Uint256 != nil {
-		coinsUsed.PackProtoMessage(*txn.CoinsUsed.Uint256)
+func (r *PeerUsageResponse) UnpackProtoMessage() ([]rawapitypes.PeerUsage, error) {
+	switch r.GetResult().(type) {
+	case *PeerUsageResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *PeerUsageResponse_Data:
+		return r.GetData().UnpackProtoMessage()
 	}
+	return nil, errors.New("unexpected response type")
+}
 
-	gasPrice := new(Uint256)
-	if txn.BaseFee.Uint256 != nil {
-		gasPrice.PackProtoMessage(*txn.BaseFee.Uint256)
+// PeerRequest converters
+
+func (r *PeerRequest) PackProtoMessage(peerId network.PeerID) error {
+	r.PeerId = []byte(peerId)
+	return nil
+}
+
+func (r *PeerRequest) UnpackProtoMessage() (network.PeerID, error) {
+	peerId := network.PeerID(r.GetPeerId())
+	if err := peerId.Validate(); err != nil {
+		return "", rawapitypes.NewInvalidArgumentError(err)
 	}
+	return peerId, nil
+}
 
-	out := &OutTransaction{
-		TransactionSSZ: txn.TransactionSSZ,
-		ForwardKind:    uint64(txn.ForwardKind),
-		Data:           txn.Data,
-		CoinsUsed:      coinsUsed,
-		GasPrice:       gasPrice,
-		Error:          txn.Error,
-		Logs:           packLogs(txn.Logs),
-		DebugLogs:      packDebugLogs(txn.DebugLogs),
+// BandwidthStats converters
+
+func (s *BandwidthStats) PackProtoMessage(stats network.BandwidthStats) *BandwidthStats {
+	s.TotalIn = stats.TotalIn
+	s.TotalOut = stats.TotalOut
+	s.RateIn = stats.RateIn
+	s.RateOut = stats.RateOut
+	return s
+}
+
+func (s *BandwidthStats) UnpackProtoMessage() network.BandwidthStats {
+	return network.BandwidthStats{
+		TotalIn:  s.GetTotalIn(),
+		TotalOut: s.GetTotalOut(),
+		RateIn:   s.GetRateIn(),
+		RateOut:  s.GetRateOut(),
 	}
+}
 
-	if len(txn.OutTransactions) > 0 {
-		out.OutTransactions = make([]*OutTransaction, len(txn.OutTransactions))
-		for i, outTxn := range txn.OutTransactions {
-			out.OutTransactions[i] = new(OutTransaction).PackProtoMessage(outTxn)
-		}
+// PeerInfo converters
+
+func (p *PeerInfo) PackProtoMessage(info network.PeerInfo) *PeerInfo {
+	p.PeerId = []byte(info.Id)
+	p.Addrs = info.Addrs
+	p.ProtocolVersion = info.ProtocolVersion
+	p.Connections = info.Connections
+	p.Streams = make(map[string]uint64, len(info.Streams))
+	for protocol, streams := range info.Streams {
+		p.Streams[string(protocol)] = streams
 	}
+	p.Bandwidth = new(BandwidthStats).PackProtoMessage(info.Bandwidth)
+	return p
+}
 
-	return out
+func (p *PeerInfo) UnpackProtoMessage() (network.PeerInfo, error) {
+	peerId := network.PeerID(p.GetPeerId())
+	if err := peerId.Validate(); err != nil {
+		return network.PeerInfo{}, err
+	}
+	info := network.PeerInfo{
+		Id:              peerId,
+		Addrs:           p.GetAddrs(),
+		ProtocolVersion: p.GetProtocolVersion(),
+		Connections:     p.GetConnections(),
+		Streams:         make(map[network.ProtocolID]uint64, len(p.GetStreams())),
+		Bandwidth:       p.GetBandwidth().UnpackProtoMessage(),
+	}
+	for protocol, streams := range p.GetStreams() {
+		info.Streams[network.ProtocolID(protocol)] = streams
+	}
+	return info, nil
 }
 
-func newValueFromUint256(v *Uint256) types.Value {
-	if v == nil {
-		return types.NewZeroValue()
+// PeerInfoResponse converters
+
+func (r *PeerInfoResponse) PackProtoMessage(info *network.PeerInfo, err error) error {
+	if err != nil {
+		r.Result = &PeerInfoResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
-	value := v.UnpackProtoMessage()
-	return types.Value{Uint256: &value}
+	r.Result = &PeerInfoResponse_Data{Data: new(PeerInfo).PackProtoMessage(*info)}
+	return nil
 }
 
-func (m *OutTransaction) UnpackProtoMessage() *rpctypes.OutTransaction {
-	txn := &rpctypes.OutTransaction{
-		TransactionSSZ: m.GetTransactionSSZ(),
-		ForwardKind:    types.ForwardKind(m.GetForwardKind()),
-		Data:           m.GetData(),
-		Error:          m.GetError(),
-		Logs:           unpackLogs(m.GetLogs()),
-		DebugLogs:      unpackDebugLogs(m.GetDebugLogs()),
+func (r *PeerInfoResponse) UnpackProtoMessage() (*network.PeerInfo, error) {
+	switch r.GetResult().(type) {
+	case *PeerInfoResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *PeerInfoResponse_Data:
+		info, err := r.GetData().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		return &info, nil
 	}
+	return nil, errors.New("unexpected response type")
+}
 
-	txn.CoinsUsed = newValueFromUint256(m.GetCoinsUsed())
-	txn.BaseFee = newValueFromUint256(m.GetGasPrice())
+// PeerInfosResponse converters
 
-	if len(m.GetOutTransactions()) > 0 {
-		txn.OutTransactions = make([]*rpctypes.OutTransaction, len(m.GetOutTransactions()))
-		for i, outTxn := range m.GetOutTransactions() {
-			txn.OutTransactions[i] = outTxn.UnpackProtoMessage()
+func (r *PeerInfosResponse) PackProtoMessage(peers []network.PeerInfo, err error) error {
+	if err != nil {
+		r.Result = &PeerInfosResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+	data := &PeerInfos{Peers: make([]*PeerInfo, len(peers))}
+	for i, info := range peers {
+		data.Peers[i] = new(PeerInfo).PackProtoMessage(info)
+	}
+	r.Result = &PeerInfosResponse_Data{Data: data}
+	return nil
+}
+
+func (r *PeerInfosResponse) UnpackProtoMessage() ([]network.PeerInfo, error) {
+	switch r.GetResult().(type) {
+	case *PeerInfosResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *PeerInfosResponse_Data:
+		peers := make([]network.PeerInfo, len(r.GetData().GetPeers()))
+		for i, peer := range r.GetData().GetPeers() {
+			info, err := peer.UnpackProtoMessage()
+			if err != nil {
+				return nil, fmt.Errorf("peer %d: %w", i, err)
+			}
+			peers[i] = info
 		}
+		return peers, nil
 	}
-	return txn
+	return nil, errors.New("unexpected response type")
 }
 
-func (l *Log) PackProtoMessage(log *types.Log) {
-	l.Address = new(Address).PackProtoMessage(log.Address)
-	l.Topics = PackHashes(log.Topics)
-	l.Data = log.Data
+// ProtocolBandwidthResponse converters
+
+func (r *ProtocolBandwidthResponse) PackProtoMessage(
+	bandwidth map[network.ProtocolID]network.BandwidthStats, err error,
+) error {
+	if err != nil {
+		r.Result = &ProtocolBandwidthResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+	data := &ProtocolBandwidth{Protocols: make(map[string]*BandwidthStats, len(bandwidth))}
+	for protocol, stats := range bandwidth {
+		data.Protocols[string(protocol)] = new(BandwidthStats).PackProtoMessage(stats)
+	}
+	r.Result = &ProtocolBandwidthResponse_Data{Data: data}
+	return nil
 }
 
-func (l *Log) UnpackProtoMessage() *types.Log {
-	return &types.Log{
-		Address: l.GetAddress().UnpackProtoMessage(),
-		Topics:  UnpackHashes(l.GetTopics()),
-		Data:    l.GetData(),
+func (r *ProtocolBandwidthResponse) UnpackProtoMessage() (map[network.ProtocolID]network.BandwidthStats, error) {
+	switch r.GetResult().(type) {
+	case *ProtocolBandwidthResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *ProtocolBandwidthResponse_Data:
+		protocols := r.GetData().GetProtocols()
+		bandwidth := make(map[network.ProtocolID]network.BandwidthStats, len(protocols))
+		for protocol, stats := range protocols {
+			bandwidth[network.ProtocolID(protocol)] = stats.UnpackProtoMessage()
+		}
+		return bandwidth, nil
 	}
+	return nil, errors.New("unexpected response type")
 }
 
-func (l *DebugLog) PackProtoMessage(log *types.DebugLog) {
-	l.Message = log.Message
-	l.Data = make([]*Uint256, len(log.Data))
-	for i, data := range log.Data {
-		l.Data[i] = new(Uint256).PackProtoMessage(data)
+// LogFilterRequest converters
+
+func (r *LogFilterRequest) PackProtoMessage(filter rawapitypes.LogFilter) error {
+	r.FromBlock = uint64(filter.FromBlock)
+	if filter.ToBlock != nil {
+		toBlock := uint64(*filter.ToBlock)
+		r.ToBlock = &toBlock
+	}
+
+	r.Addresses = make([]*Address, len(filter.Addresses))
+	for i, address := range filter.Addresses {
+		r.Addresses[i] = new(Address).PackProtoMessage(address)
 	}
+
+	r.Topics = make([]*LogTopicFilter, len(filter.Topics))
+	for i, alternatives := range filter.Topics {
+		r.Topics[i] = &LogTopicFilter{Alternatives: PackHashes(alternatives)}
+	}
+	r.Finalized = filter.Finalized
+	return nil
 }
 
-func (l *DebugLog) UnpackProtoMessage() *types.DebugLog {
-	data := make([]types.Uint256, len(l.GetData()))
-	for i, value := range l.GetData() {
-		data[i] = value.UnpackProtoMessage()
+func (r *LogFilterRequest) UnpackProtoMessage() (rawapitypes.LogFilter, error) {
+	filter := rawapitypes.LogFilter{
+		FromBlock: types.BlockNumber(r.GetFromBlock()),
+		Finalized: r.GetFinalized(),
 	}
-	return &types.DebugLog{
-		Message: l.GetMessage(),
-		Data:    data,
+	if r.ToBlock != nil {
+		toBlock := types.BlockNumber(r.GetToBlock())
+		filter.ToBlock = &toBlock
+	}
+
+	if len(r.GetAddresses()) > 0 {
+		filter.Addresses = make([]types.Address, len(r.GetAddresses()))
+		for i, address := range r.GetAddresses() {
+			filter.Addresses[i] = address.UnpackProtoMessage()
+		}
+	}
+
+	if len(r.GetTopics()) > 0 {
+		filter.Topics = make([][]common.Hash, len(r.GetTopics()))
+		for i, topic := range r.GetTopics() {
+			filter.Topics[i] = UnpackHashes(topic.GetAlternatives())
+		}
 	}
+	return filter, nil
 }
 
-func packLogs(logs []*types.Log) []*Log {
-	res := make([]*Log, len(logs))
-	for i, log := range logs {
-		res[i] = new(Log)
-		res[i].PackProtoMessage(log)
+// LogsResponse converters
+
+func (l *LogInfo) PackProtoMessage(info *rawapitypes.LogInfo) *LogInfo {
+	l.Log = new(Log)
+	l.Log.PackProtoMessage(info.Log)
+	l.BlockId = uint64(info.BlockId)
+	l.BlockHash = new(Hash)
+	check.PanicIfErr(l.BlockHash.PackProtoMessage(info.BlockHash))
+	l.TransactionHash = new(Hash)
+	check.PanicIfErr(l.TransactionHash.PackProtoMessage(info.TransactionHash))
+	l.TransactionIndex = uint64(info.TransactionIndex)
+	l.LogIndex = info.LogIndex
+	return l
+}
+
+func (l *LogInfo) UnpackProtoMessage() (*rawapitypes.LogInfo, error) {
+	blockHash, err := l.GetBlockHash().UnpackProtoMessage()
+	if err != nil {
+		return nil, err
 	}
-	return res
+	txnHash, err := l.GetTransactionHash().UnpackProtoMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &rawapitypes.LogInfo{
+		Log:              l.GetLog().UnpackProtoMessage(),
+		BlockId:          types.BlockNumber(l.GetBlockId()),
+		BlockHash:        blockHash,
+		TransactionHash:  txnHash,
+		TransactionIndex: types.TransactionIndex(l.GetTransactionIndex()),
+		LogIndex:         l.GetLogIndex(),
+	}, nil
 }
 
-func unpackLogs(logs []*Log) []*types.Log {
-	if logs == nil {
+func (r *LogsResponse) PackProtoMessage(logs []*rawapitypes.LogInfo, err error) error {
+	if err != nil {
+		r.Result = &LogsResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
-	res := make([]*types.Log, len(logs))
-	for i, log := range logs {
-		res[i] = log.UnpackProtoMessage()
+
+	data := &LogInfos{Logs: make([]*LogInfo, len(logs))}
+	for i, info := range logs {
+		data.Logs[i] = new(LogInfo).PackProtoMessage(info)
 	}
-	return res
+	r.Result = &LogsResponse_Data{Data: data}
+	return nil
 }
 
-func packDebugLogs(logs []*types.DebugLog) []*DebugLog {
-	res := make([]*DebugLog, len(logs))
-	for i, log := range logs {
-		res[i] = new(DebugLog)
-		res[i].PackProtoMessage(log)
+func (r *LogsResponse) UnpackProtoMessage() ([]*rawapitypes.LogInfo, error) {
+	switch r.GetResult().(type) {
+	case *LogsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *LogsResponse_Data:
+		infos := r.GetData().GetLogs()
+		logs := make([]*rawapitypes.LogInfo, len(infos))
+		for i, info := range infos {
+			var err error
+			if logs[i], err = info.UnpackProtoMessage(); err != nil {
+				return nil, err
+			}
+		}
+		return logs, nil
+
+	default:
+		return nil, errors.New("unexpected response type")
 	}
-	return res
 }
 
-func unpackDebugLogs(logs []*DebugLog) []*types.DebugLog {
-	if logs == nil {
+// LogResponse converters
+
+func (r *LogResponse) PackProtoMessage(info *rawapitypes.LogInfo, err error) error {
+	if err != nil {
+		r.Result = &LogResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
-	res := make([]*types.DebugLog, len(logs))
-	for i, log := range logs {
-		res[i] = log.UnpackProtoMessage()
-	}
-	return res
+	r.Result = &LogResponse_Data{Data: new(LogInfo).PackProtoMessage(info)}
+	return nil
 }
 
-func (cr *CallResponse) PackProtoMessage(args *rpctypes.CallResWithGasPrice, err error) error {
-	if err != nil {
-		cr.Result = &CallResponse_Error{Error: new(Error).PackProtoMessage(err)}
-		return nil
+func (r *LogResponse) UnpackProtoMessage() (*rawapitypes.LogInfo, error) {
+	switch r.GetResult().(type) {
+	case *LogResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+	case *LogResponse_Data:
+		return r.GetData().UnpackProtoMessage()
+	default:
+		return nil, errors.New("unexpected response type")
 	}
+}
 
-	res := &CallResult{}
-	res.Data = args.Data
-	res.Logs = packLogs(args.Logs)
-	res.DebugLogs = packDebugLogs(args.DebugLogs)
+// PendingTransactionsRequest converters
 
-	if args.CoinsUsed.Uint256 != nil {
-		res.CoinsUsed = new(Uint256).PackProtoMessage(*args.CoinsUsed.Uint256)
-	}
+func (r *PendingTransactionsRequest) PackProtoMessage(fullTransactions bool) error {
+	r.FullTransactions = fullTransactions
+	return nil
+}
 
-	res.OutTransactions = make([]*OutTransaction, len(args.OutTransactions))
-	for i, outTxn := range res.GetOutTransactions() {
-		res.OutTransactions[i] = outTxn.PackProtoMessage(args.OutTransactions[i])
-	}
+func (r *PendingTransactionsRequest) UnpackProtoMessage() (bool, error) {
+	return r.GetFullTransactions(), nil
+}
 
-	if len(args.Error) > 0 {
-		res.Error = &Error{Message: args.Error}
-	}
-	if args.StateOverrides != nil {
-		res.StateOverrides = new(StateOverrides).PackProtoMessage(&args.StateOverrides)
-	}
+// PendingTransactionResponse converters
 
-	if args.BaseFee.Uint256 != nil {
-		res.GasPrice = new(Uint256).PackProtoMessage(*args.BaseFee.Uint256)
+func (r *PendingTransactionResponse) PackProtoMessage(txn *rawapitypes.PendingTransaction, err error) error {
+	if err != nil {
+		r.Result = &PendingTransactionResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
 
-	cr.Result = &CallResponse_Data{Data: res}
+	hash := new(Hash)
+	if err := hash.PackProtoMessage(txn.Hash); err != nil {
+		return err
+	}
+	r.Result = &PendingTransactionResponse_Data{Data: &PendingTransaction{
+		Hash:           hash,
+		TransactionSSZ: txn.TransactionSSZ,
+	}}
 	return nil
 }
 
-func (cr *CallResponse) UnpackProtoMessage() (*rpctypes.CallResWithGasPrice, error) {
-	if err := cr.GetError(); err != nil {
-		return nil, err.UnpackProtoMessage()
-	}
+func (r *PendingTransactionResponse) UnpackProtoMessage() (*rawapitypes.PendingTransaction, error) {
+	switch r.GetResult().(type) {
+	case *PendingTransactionResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
 
-	data := cr.GetData()
-	if data == nil {
+	case *PendingTransactionResponse_Data:
+		data := r.GetData()
+		hash, err := data.GetHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		return &rawapitypes.PendingTransaction{
+			Hash:           hash,
+			TransactionSSZ: data.GetTransactionSSZ(),
+		}, nil
+
+	default:
 		return nil, errors.New("unexpected response type")
 	}
-	check.PanicIfNot(data != nil)
+}
 
-	res := &rpctypes.CallResWithGasPrice{}
-	res.Data = data.GetData()
-	res.CoinsUsed = newValueFromUint256(data.GetCoinsUsed())
-	res.BaseFee = newValueFromUint256(data.GetGasPrice())
-	res.Logs = unpackLogs(data.GetLogs())
-	res.DebugLogs = unpackDebugLogs(data.GetDebugLogs())
+// ProofTaskRequest converters
 
-	res.OutTransactions = make([]*rpctypes.OutTransaction, len(data.GetOutTransactions()))
-	for i, outTxn := range data.GetOutTransactions() {
-		res.OutTransactions[i] = outTxn.UnpackProtoMessage()
+func (r *ProofTaskRequest) PackProtoMessage(id uuid.UUID) error {
+	r.Id = id.String()
+	return nil
+}
+
+func (r *ProofTaskRequest) UnpackProtoMessage() (uuid.UUID, error) {
+	id, err := uuid.Parse(r.GetId())
+	if err != nil {
+		return uuid.Nil, rawapitypes.NewInvalidArgumentError(err)
 	}
+	return id, nil
+}
 
-	if data.GetStateOverrides() != nil {
-		res.StateOverrides = *data.GetStateOverrides().UnpackProtoMessage()
+// ProofTaskResponse converters
+
+func (r *ProofTaskResponse) PackProtoMessage(info *rawapitypes.ProofTaskInfo, err error) error {
+	if err != nil {
+		r.Result = &ProofTaskResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
 
-	if data.GetError() != nil {
-		res.Error = data.GetError().GetMessage()
+	data := &ProofTaskInfo{
+		Id:        info.Id.String(),
+		Status:    uint32(info.Status),
+		BlockHash: new(Hash),
+		Error:     info.Error,
+	}
+	if err := data.GetBlockHash().PackProtoMessage(info.BlockHash); err != nil {
+		return err
 	}
+	r.Result = &ProofTaskResponse_Data{Data: data}
+	return nil
+}
 
-	return res, nil
+func (r *ProofTaskResponse) UnpackProtoMessage() (*rawapitypes.ProofTaskInfo, error) {
+	switch r.GetResult().(type) {
+	case *ProofTaskResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ProofTaskResponse_Data:
+		data := r.GetData()
+		id, err := uuid.Parse(data.GetId())
+		if err != nil {
+			return nil, err
+		}
+		blockHash, err := data.GetBlockHash().UnpackProtoMessage()
+		if err != nil {
+			return nil, err
+		}
+		return &rawapitypes.ProofTaskInfo{
+			Id:        id,
+			Status:    rawapitypes.ProofTaskStatus(data.GetStatus()),
+			BlockHash: blockHash,
+			Error:     data.GetError(),
+		}, nil
+	}
+	return nil, errors.New("unexpected response type")
 }
 
-// Transaction converters
-func (r *TransactionResponse) PackProtoMessage(info *rawapitypes.TransactionInfo, err error) error {
+// StateProofResponse converters
+
+func (r *StateProofResponse) PackProtoMessage(proof *rawapitypes.StateProof, err error) error {
 	if err != nil {
-		r.Result = &TransactionResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &StateProofResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	var hash Hash
-	if err := hash.PackProtoMessage(info.BlockHash); err != nil {
-		return err
+	data := &StateProof{
+		BlockHash:   new(Hash),
+		BlockNumber: uint64(proof.BlockNumber),
+		TaskId:      proof.TaskId.String(),
+		Proof:       proof.Proof,
 	}
-
-	r.Result = &TransactionResponse_Data{
-		Data: &TransactionInfo{
-			TransactionSSZ: info.TransactionSSZ,
-			ReceiptSSZ:     info.ReceiptSSZ,
-			Index:          uint64(info.Index),
-			BlockHash:      &hash,
-			BlockId:        uint64(info.BlockId),
-		},
+	if err := data.GetBlockHash().PackProtoMessage(proof.BlockHash); err != nil {
+		return err
 	}
+	r.Result = &StateProofResponse_Data{Data: data}
 	return nil
 }
 
-func (r *TransactionResponse) UnpackProtoMessage() (*rawapitypes.TransactionInfo, error) {
+func (r *StateProofResponse) UnpackProtoMessage() (*rawapitypes.StateProof, error) {
 	switch r.GetResult().(type) {
-	case *TransactionResponse_Error:
+	case *StateProofResponse_Error:
 		return nil, r.GetError().UnpackProtoMessage()
-	case *TransactionResponse_Data:
+
+	case *StateProofResponse_Data:
 		data := r.GetData()
-		hash, err := data.GetBlockHash().UnpackProtoMessage()
+		blockHash, err := data.GetBlockHash().UnpackProtoMessage()
 		if err != nil {
 			return nil, err
 		}
-		return &rawapitypes.TransactionInfo{
-			TransactionSSZ: data.GetTransactionSSZ(),
-			ReceiptSSZ:     data.GetReceiptSSZ(),
-			Index:          types.TransactionIndex(data.GetIndex()),
-			BlockHash:      hash,
-			BlockId:        types.BlockNumber(data.GetBlockId()),
+		taskId, err := uuid.Parse(data.GetTaskId())
+		if err != nil {
+			return nil, err
+		}
+		return &rawapitypes.StateProof{
+			BlockHash:   blockHash,
+			BlockNumber: types.BlockNumber(data.GetBlockNumber()),
+			TaskId:      taskId,
+			Proof:       data.GetProof(),
 		}, nil
 	}
 	return nil, errors.New("unexpected response type")
 }
 
-func (r *TransactionRequestByBlockRefAndIndex) PackProtoMessage(
-	ref rawapitypes.BlockReference, index types.TransactionIndex,
-) error {
-	r.BlockRef = &BlockReference{}
-	if err := r.GetBlockRef().PackProtoMessage(ref); err != nil {
-		return err
+// DbGetRawRequest converters
+
+func (r *DbGetRawRequest) PackProtoMessage(table string, key []byte) error {
+	r.Table = table
+	r.Key = key
+	return nil
+}
+
+func (r *DbGetRawRequest) UnpackProtoMessage() (string, []byte, error) {
+	return r.GetTable(), r.GetKey(), nil
+}
+
+// DbListKeysRequest converters
+
+func (r *DbListKeysRequest) PackProtoMessage(table string, prefix []byte, after []byte, limit uint64) error {
+	r.Table = table
+	r.Prefix = prefix
+	r.After = after
+	r.Limit = limit
+	return nil
+}
+
+func (r *DbListKeysRequest) UnpackProtoMessage() (string, []byte, []byte, uint64, error) {
+	return r.GetTable(), r.GetPrefix(), r.GetAfter(), r.GetLimit(), nil
+}
+
+// DbValueResponse converters
+
+func (r *DbValueResponse) PackProtoMessage(value []byte, err error) error {
+	if err != nil {
+		r.Result = &DbValueResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
-	r.Index = uint64(index)
+
+	r.Result = &DbValueResponse_Data{Data: value}
 	return nil
 }
 
-func (r *TransactionRequestByBlockRefAndIndex) UnpackProtoMessage() (
-	rawapitypes.BlockReference, types.TransactionIndex, error,
-) {
-	ref, err := r.GetBlockRef().UnpackProtoMessage()
+func (r *DbValueResponse) UnpackProtoMessage() ([]byte, error) {
+	switch r.GetResult().(type) {
+	case *DbValueResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *DbValueResponse_Data:
+		return r.GetData(), nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// DbKeysResponse converters
+
+func (r *DbKeysResponse) PackProtoMessage(keys [][]byte, err error) error {
 	if err != nil {
-		return rawapitypes.BlockReference{}, 0, err
+		r.Result = &DbKeysResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
-	return ref, types.TransactionIndex(r.GetIndex()), nil
+
+	r.Result = &DbKeysResponse_Data{Data: &DbKeys{Keys: keys}}
+	return nil
 }
 
-func (r *TransactionRequestByHash) PackProtoMessage(hash common.Hash) error {
-	r.Hash = &Hash{}
-	return r.GetHash().PackProtoMessage(hash)
+func (r *DbKeysResponse) UnpackProtoMessage() ([][]byte, error) {
+	switch r.GetResult().(type) {
+	case *DbKeysResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *DbKeysResponse_Data:
+		return r.GetData().GetKeys(), nil
+	}
+	return nil, errors.New("unexpected response type")
 }
 
-func (r *TransactionRequestByHash) UnpackProtoMessage() (common.Hash, error) {
-	return r.GetHash().UnpackProtoMessage()
+// ConsensusShardRequest converters
+
+func (r *ConsensusShardRequest) PackProtoMessage(shardId types.ShardId) error {
+	r.ShardId = uint32(shardId)
+	return nil
 }
 
-func (r *TransactionRequest) PackProtoMessage(request rawapitypes.TransactionRequest) error {
-	if request.ByHash != nil {
-		byHash := &TransactionRequestByHash{}
-		if err := byHash.PackProtoMessage(request.ByHash.Hash); err != nil {
-			return err
-		}
-		r.Request = &TransactionRequest_ByHash{
-			ByHash: byHash,
-		}
-	} else {
-		byBlockRefAndIndex := &TransactionRequestByBlockRefAndIndex{}
-		if err := byBlockRefAndIndex.PackProtoMessage(
-			request.ByBlockRefAndIndex.BlockRef,
-			request.ByBlockRefAndIndex.Index,
-		); err != nil {
-			return err
-		}
-		r.Request = &TransactionRequest_ByBlockRefAndIndex{
-			ByBlockRefAndIndex: byBlockRefAndIndex,
-		}
+func (r *ConsensusShardRequest) UnpackProtoMessage() (types.ShardId, error) {
+	return types.ShardId(r.GetShardId()), nil
+}
+
+// ConsensusStateResponse converters
+
+func (r *ConsensusStateResponse) PackProtoMessage(state *rawapitypes.ConsensusState, err error) error {
+	if err != nil {
+		r.Result = &ConsensusStateResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
+	}
+
+	r.Result = &ConsensusStateResponse_Data{Data: &ConsensusState{
+		Height:            uint64(state.Height),
+		Round:             state.Round,
+		Step:              state.Step,
+		ProposerIndex:     state.ProposerIndex,
+		ProposerPublicKey: state.ProposerPublicKey,
+		ProposalHash:      state.ProposalHash,
+		Prepares:          state.Prepares,
+		Commits:           state.Commits,
+		RoundChangeRound:  state.RoundChangeRound,
+		RoundChanges:      state.RoundChanges,
+	}}
+	return nil
+}
+
+func (r *ConsensusStateResponse) UnpackProtoMessage() (*rawapitypes.ConsensusState, error) {
+	switch r.GetResult().(type) {
+	case *ConsensusStateResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ConsensusStateResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.ConsensusState{
+			Height:            types.BlockNumber(data.GetHeight()),
+			Round:             data.GetRound(),
+			Step:              data.GetStep(),
+			ProposerIndex:     data.GetProposerIndex(),
+			ProposerPublicKey: data.GetProposerPublicKey(),
+			ProposalHash:      data.GetProposalHash(),
+			Prepares:          data.GetPrepares(),
+			Commits:           data.GetCommits(),
+			RoundChangeRound:  data.GetRoundChangeRound(),
+			RoundChanges:      data.GetRoundChanges(),
+		}, nil
 	}
-	return nil
+	return nil, errors.New("unexpected response type")
 }
 
-func (r *TransactionRequest) UnpackProtoMessage() (rawapitypes.TransactionRequest, error) {
-	byHash := r.GetByHash()
-	if byHash != nil {
-		hash, err := byHash.UnpackProtoMessage()
-		if err != nil {
-			return rawapitypes.TransactionRequest{}, err
-		}
-		return rawapitypes.TransactionRequest{
-			ByHash: &rawapitypes.TransactionRequestByHash{Hash: hash},
-		}, nil
+// RoundTimeoutsResponse converters
+
+func (r *RoundTimeoutsResponse) PackProtoMessage(timeouts []rawapitypes.RoundTimeout, err error) error {
+	if err != nil {
+		r.Result = &RoundTimeoutsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
 
-	byBlockRefAndIndex := r.GetByBlockRefAndIndex()
-	if byBlockRefAndIndex != nil {
-		ref, index, err := byBlockRefAndIndex.UnpackProtoMessage()
-		if err != nil {
-			return rawapitypes.TransactionRequest{}, err
+	data := &RoundTimeouts{Timeouts: make([]*RoundTimeout, len(timeouts))}
+	for i, timeout := range timeouts {
+		data.Timeouts[i] = &RoundTimeout{
+			Height: uint64(timeout.Height),
+			Round:  timeout.Round,
+			Step:   timeout.Step,
+			Time:   timeout.Time.UnixMilli(),
 		}
-		return rawapitypes.TransactionRequest{
-			ByBlockRefAndIndex: &rawapitypes.TransactionRequestByBlockRefAndIndex{
-				BlockRef: ref,
-				Index:    index,
-			},
-		}, nil
 	}
-	return rawapitypes.TransactionRequest{}, errors.New("unexpected request type")
+	r.Result = &RoundTimeoutsResponse_Data{Data: data}
+	return nil
 }
 
-// Receipt converters
-func (r *ReceiptInfo) PackProtoMessage(info *rawapitypes.ReceiptInfo) *ReceiptInfo {
-	if info == nil || info.ReceiptSSZ == nil {
-		return nil
-	}
+func (r *RoundTimeoutsResponse) UnpackProtoMessage() ([]rawapitypes.RoundTimeout, error) {
+	switch r.GetResult().(type) {
+	case *RoundTimeoutsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
 
-	var outReceipts []*ReceiptInfo
-	if len(info.OutReceipts) > 0 {
-		outReceipts = make([]*ReceiptInfo, len(info.OutReceipts))
-		for i, outReceipt := range info.OutReceipts {
-			if outReceipt != nil {
-				outReceipts[i] = new(ReceiptInfo).PackProtoMessage(outReceipt)
+	case *RoundTimeoutsResponse_Data:
+		data := r.GetData().GetTimeouts()
+		timeouts := make([]rawapitypes.RoundTimeout, len(data))
+		for i, timeout := range data {
+			timeouts[i] = rawapitypes.RoundTimeout{
+				Height: types.BlockNumber(timeout.GetHeight()),
+				Round:  timeout.GetRound(),
+				Step:   timeout.GetStep(),
+				Time:   time.UnixMilli(timeout.GetTime()),
 			}
 		}
+		return timeouts, nil
 	}
+	return nil, errors.New("unexpected response type")
+}
 
-	var gp *Uint256
-	if info.GasPrice.Uint256 != nil {
-		gp = new(Uint256).PackProtoMessage(*info.GasPrice.Uint256)
+// FamilyTransactionCountResponse converters
+
+func (r *FamilyTransactionCountResponse) PackProtoMessage(
+	count *rawapitypes.FamilyTransactionCount, err error,
+) error {
+	if err != nil {
+		r.Result = &FamilyTransactionCountResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		return nil
 	}
 
-	blockHash := &Hash{}
-	check.PanicIfErr(blockHash.PackProtoMessage(info.BlockHash))
+	data := &FamilyTransactionCount{
+		Total:  count.Total,
+		Shards: make([]*ShardTransactionCount, len(count.Shards)),
+	}
+	for i, shard := range count.Shards {
+		data.Shards[i] = &ShardTransactionCount{
+			ShardId: uint32(shard.ShardId),
+			Address: new(Address).PackProtoMessage(shard.Address),
+			Count:   shard.Count,
+		}
+	}
+	r.Result = &FamilyTransactionCountResponse_Data{Data: data}
+	return nil
+}
 
-	return &ReceiptInfo{
-		Flags:           uint32(info.Flags.Bits),
-		ReceiptSSZ:      info.ReceiptSSZ,
-		Index:           uint64(info.Index),
-		BlockHash:       blockHash,
-		BlockId:         uint64(info.BlockId),
-		OutTransactions: PackHashes(info.OutTransactions),
-		OutReceipts:     outReceipts,
-		IncludedInMain:  info.IncludedInMain,
-		ErrorMessage:    &Error{Message: info.ErrorMessage},
-		GasPrice:        gp,
-		Temporary:       info.Temporary,
+func (r *FamilyTransactionCountResponse) UnpackProtoMessage() (*rawapitypes.FamilyTransactionCount, error) {
+	switch r.GetResult().(type) {
+	case *FamilyTransactionCountResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *FamilyTransactionCountResponse_Data:
+		data := r.GetData()
+		count := &rawapitypes.FamilyTransactionCount{
+			Total:  data.GetTotal(),
+			Shards: make([]rawapitypes.ShardTransactionCount, len(data.GetShards())),
+		}
+		for i, shard := range data.GetShards() {
+			count.Shards[i] = rawapitypes.ShardTransactionCount{
+				ShardId: types.ShardId(shard.GetShardId()),
+				Address: shard.GetAddress().UnpackProtoMessage(),
+				Count:   shard.GetCount(),
+			}
+		}
+		return count, nil
 	}
+	return nil, errors.New("unexpected response type")
 }
 
-func (r *ReceiptResponse) PackProtoMessage(info *rawapitypes.ReceiptInfo, err error) error {
+// ShardBlockHeadsResponse converters
+
+func (r *ShardBlockHeadsResponse) PackProtoMessage(heads []*rawapitypes.ShardBlockHead, err error) error {
 	if err != nil {
-		r.Result = &ReceiptResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &ShardBlockHeadsResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	r.Result = &ReceiptResponse_Data{
-		Data: new(ReceiptInfo).PackProtoMessage(info),
+	data := &ShardBlockHeads{Heads: make([]*ShardBlockHead, len(heads))}
+	for i, head := range heads {
+		data.Heads[i] = &ShardBlockHead{
+			ShardId:     uint32(head.ShardId),
+			BlockNumber: uint64(head.BlockNumber),
+			BlockHash:   new(Hash),
+		}
+		if err := data.Heads[i].GetBlockHash().PackProtoMessage(head.BlockHash); err != nil {
+			return err
+		}
 	}
+	r.Result = &ShardBlockHeadsResponse_Data{Data: data}
 	return nil
 }
 
-func (r *ReceiptInfo) UnpackProtoMessage() *rawapitypes.ReceiptInfo {
-	if r == nil || r.ReceiptSSZ == nil {
+func (r *ShardBlockHeadsResponse) UnpackProtoMessage() ([]*rawapitypes.ShardBlockHead, error) {
+	switch r.GetResult().(type) {
+	case *ShardBlockHeadsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *ShardBlockHeadsResponse_Data:
+		heads := make([]*rawapitypes.ShardBlockHead, len(r.GetData().GetHeads()))
+		for i, head := range r.GetData().GetHeads() {
+			blockHash, err := head.GetBlockHash().UnpackProtoMessage()
+			if err != nil {
+				return nil, err
+			}
+			heads[i] = &rawapitypes.ShardBlockHead{
+				ShardId:     types.ShardId(head.GetShardId()),
+				BlockNumber: types.BlockNumber(head.GetBlockNumber()),
+				BlockHash:   blockHash,
+			}
+		}
+		return heads, nil
+	}
+	return nil, errors.New("unexpected response type")
+}
+
+// GasPriceStatsResponse converters
+
+func (r *GasPriceStatsResponse) PackProtoMessage(stats *rawapitypes.GasPriceStats, err error) error {
+	if err != nil {
+		r.Result = &GasPriceStatsResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	var outReceipts []*rawapitypes.ReceiptInfo
-	if len(r.GetOutReceipts()) > 0 {
-		outReceipts = make([]*rawapitypes.ReceiptInfo, len(r.GetOutReceipts()))
-		for i, outReceipt := range r.GetOutReceipts() {
-			outReceipts[i] = outReceipt.UnpackProtoMessage()
+	data := &GasPriceStats{
+		Min:    newUint256FromValue(stats.Min),
+		Max:    newUint256FromValue(stats.Max),
+		Mean:   newUint256FromValue(stats.Mean),
+		Median: newUint256FromValue(stats.Median),
+		Shards: make([]*ShardGasPrice, len(stats.Shards)),
+	}
+	for i, shard := range stats.Shards {
+		data.Shards[i] = &ShardGasPrice{
+			ShardId:  uint32(shard.ShardId),
+			GasPrice: newUint256FromValue(shard.GasPrice),
 		}
 	}
+	r.Result = &GasPriceStatsResponse_Data{Data: data}
+	return nil
+}
 
-	var errorMessage string
-	if r.GetErrorMessage() != nil {
-		errorMessage = r.GetErrorMessage().GetMessage()
+func (r *GasPriceStatsResponse) UnpackProtoMessage() (*rawapitypes.GasPriceStats, error) {
+	switch r.GetResult().(type) {
+	case *GasPriceStatsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *GasPriceStatsResponse_Data:
+		data := r.GetData()
+		stats := &rawapitypes.GasPriceStats{
+			Min:    newValueFromUint256(data.GetMin()),
+			Max:    newValueFromUint256(data.GetMax()),
+			Mean:   newValueFromUint256(data.GetMean()),
+			Median: newValueFromUint256(data.GetMedian()),
+			Shards: make([]rawapitypes.ShardGasPrice, len(data.GetShards())),
+		}
+		for i, shard := range data.GetShards() {
+			stats.Shards[i] = rawapitypes.ShardGasPrice{
+				ShardId:  types.ShardId(shard.GetShardId()),
+				GasPrice: newValueFromUint256(shard.GetGasPrice()),
+			}
+		}
+		return stats, nil
 	}
+	return nil, errors.New("unexpected response type")
+}
 
-	blockHash, err := r.GetBlockHash().UnpackProtoMessage()
-	check.PanicIfErr(err)
-	return &rawapitypes.ReceiptInfo{
-		Flags:           types.NewTransactionFlagsFromBits(uint8(r.GetFlags())),
-		ReceiptSSZ:      r.GetReceiptSSZ(),
-		Index:           types.TransactionIndex(r.GetIndex()),
-		BlockHash:       blockHash,
-		BlockId:         types.BlockNumber(r.GetBlockId()),
-		OutTransactions: UnpackHashes(r.GetOutTransactions()),
-		OutReceipts:     outReceipts,
-		IncludedInMain:  r.GetIncludedInMain(),
-		ErrorMessage:    errorMessage,
-		GasPrice:        newValueFromUint256(r.GetGasPrice()),
-		Temporary:       r.GetTemporary(),
+// ResolveShardRequest converters
+
+func (r *ResolveShardRequest) PackProtoMessage(address types.Address) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	return nil
+}
+
+// UnpackProtoMessage returns the empty address if it is missing, so that it is reported as malformed.
+func (r *ResolveShardRequest) UnpackProtoMessage() (types.Address, error) {
+	return r.GetAddress().UnpackProtoMessage(), nil
+}
+
+// ResolveShardsRequest converters
+
+func (r *ResolveShardsRequest) PackProtoMessage(addresses []types.Address) error {
+	r.Addresses = make([]*Address, len(addresses))
+	for i, address := range addresses {
+		r.Addresses[i] = new(Address).PackProtoMessage(address)
 	}
+	return nil
 }
 
-func (r *ReceiptResponse) UnpackProtoMessage() (*rawapitypes.ReceiptInfo, error) {
-	err := r.GetError()
-	if err != nil {
-		return nil, err.UnpackProtoMessage()
+func (r *ResolveShardsRequest) UnpackProtoMessage() ([]types.Address, error) {
+	addresses := make([]types.Address, len(r.GetAddresses()))
+	for i, address := range r.GetAddresses() {
+		addresses[i] = address.UnpackProtoMessage()
 	}
-	return r.GetData().UnpackProtoMessage(), nil
+	return addresses, nil
 }
 
-func (r *GasPriceResponse) PackProtoMessage(v types.Value, err error) error {
+// ShardIdResponse converters
+
+func (r *ShardIdResponse) PackProtoMessage(shardId types.ShardId, err error) error {
 	if err != nil {
-		r.Result = &GasPriceResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &ShardIdResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	value := v.Uint256
-	if value == nil {
-		value = &types.Uint256{}
-	}
-
-	r.Result = &GasPriceResponse_Data{Data: new(Uint256).PackProtoMessage(*value)}
+	r.Result = &ShardIdResponse_ShardId{ShardId: uint32(shardId)}
 	return nil
 }
 
-func (r *GasPriceResponse) UnpackProtoMessage() (types.Value, error) {
-	err := r.GetError()
-	if err != nil {
-		return types.Value{}, err.UnpackProtoMessage()
-	}
-	v := r.GetData()
-	if v == nil {
-		return types.NewZeroValue(), nil
+func (r *ShardIdResponse) UnpackProtoMessage() (types.ShardId, error) {
+	switch r.GetResult().(type) {
+	case *ShardIdResponse_Error:
+		return 0, r.GetError().UnpackProtoMessage()
+
+	case *ShardIdResponse_ShardId:
+		return types.ShardId(r.GetShardId()), nil
 	}
-	return newValueFromUint256(v), nil
+	return 0, errors.New("unexpected response type")
 }
 
-func (sr *ShardIdListResponse) PackProtoMessage(shardIdList []types.ShardId, err error) error {
+// ShardResolutionsResponse converters
+
+func (r *ShardResolutionsResponse) PackProtoMessage(resolutions []*rawapitypes.ShardResolution, err error) error {
 	if err != nil {
-		sr.Result = &ShardIdListResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &ShardResolutionsResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	result := &ShardIdList{
-		Ids: make([]uint32, 0, len(shardIdList)),
-	}
-	for _, shardId := range shardIdList {
-		result.Ids = append(result.Ids, uint32(shardId))
+	data := &ShardResolutions{Resolutions: make([]*ShardResolution, len(resolutions))}
+	for i, resolution := range resolutions {
+		data.Resolutions[i] = &ShardResolution{
+			Address: new(Address).PackProtoMessage(resolution.Address),
+			ShardId: uint32(resolution.ShardId),
+		}
+		if resolution.Error != nil {
+			data.Resolutions[i].Error = new(Error).PackProtoMessage(resolution.Error)
+		}
 	}
-	sr.Result = &ShardIdListResponse_Data{Data: result}
+	r.Result = &ShardResolutionsResponse_Data{Data: data}
 	return nil
 }
 
-func (sr *ShardIdListResponse) UnpackProtoMessage() ([]types.ShardId, error) {
-	switch sr.GetResult().(type) {
-	case *ShardIdListResponse_Error:
-		return nil, sr.GetError().UnpackProtoMessage()
-
-	case *ShardIdListResponse_Data:
-		data := sr.GetData()
-		if data == nil {
-			return nil, errors.New("unexpected response")
-		}
+func (r *ShardResolutionsResponse) UnpackProtoMessage() ([]*rawapitypes.ShardResolution, error) {
+	switch r.GetResult().(type) {
+	case *ShardResolutionsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
 
-		shardIdList := make([]types.ShardId, 0, len(data.GetIds()))
-		for _, id := range data.GetIds() {
-			shardIdList = append(shardIdList, types.ShardId(id))
+	case *ShardResolutionsResponse_Data:
+		resolutions := make([]*rawapitypes.ShardResolution, len(r.GetData().GetResolutions()))
+		for i, resolution := range r.GetData().GetResolutions() {
+			resolutions[i] = &rawapitypes.ShardResolution{
+				Address: resolution.GetAddress().UnpackProtoMessage(),
+				ShardId: types.ShardId(resolution.GetShardId()),
+			}
+			if resolution.GetError() != nil {
+				resolutions[i].Error = resolution.GetError().UnpackProtoMessage()
+			}
 		}
-		return shardIdList, nil
+		return resolutions, nil
 	}
 	return nil, errors.New("unexpected response type")
 }
 
-func (r *SendTransactionResponse) PackProtoMessage(status txnpool.DiscardReason, err error) error {
+// FaucetRequest converters
+
+func (r *FaucetRequest) PackProtoMessage(address types.Address, amount types.Value) error {
+	r.Address = new(Address).PackProtoMessage(address)
+	r.Amount = newUint256FromValue(amount)
+	return nil
+}
+
+func (r *FaucetRequest) UnpackProtoMessage() (types.Address, types.Value, error) {
+	return r.GetAddress().UnpackProtoMessage(), newValueFromUint256(r.GetAmount()), nil
+}
+
+// FaucetLimitsResponse converters
+
+func (r *FaucetLimitsResponse) PackProtoMessage(limits *rawapitypes.FaucetLimits, err error) error {
 	if err != nil {
-		r.Result = &SendTransactionResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &FaucetLimitsResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	r.Result = &SendTransactionResponse_Status{
-		Status: uint32(status),
-	}
+	r.Result = &FaucetLimitsResponse_Data{Data: &FaucetLimits{
+		Faucet:         new(Address).PackProtoMessage(limits.Faucet),
+		MaxAmount:      newUint256FromValue(limits.MaxAmount),
+		RequesterQuota: newUint256FromValue(limits.RequesterQuota),
+		Window:         uint64(limits.Window.Milliseconds()),
+	}}
 	return nil
 }
 
-func (r *SendTransactionResponse) UnpackProtoMessage() (txnpool.DiscardReason, error) {
-	err := r.GetError()
-	if err != nil {
-		return 0, err.UnpackProtoMessage()
+func (r *FaucetLimitsResponse) UnpackProtoMessage() (*rawapitypes.FaucetLimits, error) {
+	switch r.GetResult().(type) {
+	case *FaucetLimitsResponse_Error:
+		return nil, r.GetError().UnpackProtoMessage()
+
+	case *FaucetLimitsResponse_Data:
+		data := r.GetData()
+		return &rawapitypes.FaucetLimits{
+			Faucet:         data.GetFaucet().UnpackProtoMessage(),
+			MaxAmount:      newValueFromUint256(data.GetMaxAmount()),
+			RequesterQuota: newValueFromUint256(data.GetRequesterQuota()),
+			Window:         time.Duration(data.GetWindow()) * time.Millisecond,
+		}, nil
 	}
+	return nil, errors.New("unexpected response type")
+}
+
+// MineBlockRequest converters
 
-	status := r.GetStatus()
-	return txnpool.DiscardReason(status), nil
+func (r *MineBlockRequest) PackProtoMessage(count uint64) error {
+	r.Count = count
+	return nil
 }
 
-func (r *SendTransactionRequest) PackProtoMessage(transactionSSZ []byte) error {
-	r.TransactionSSZ = transactionSSZ
+func (r *MineBlockRequest) UnpackProtoMessage() (uint64, error) {
+	return r.GetCount(), nil
+}
+
+// SetNextBlockTimestampRequest converters
+
+func (r *SetNextBlockTimestampRequest) PackProtoMessage(timestamp uint64) error {
+	r.Timestamp = timestamp
 	return nil
 }
 
-func (r *SendTransactionRequest) UnpackProtoMessage() ([]byte, error) {
-	return r.GetTransactionSSZ(), nil
+func (r *SetNextBlockTimestampRequest) UnpackProtoMessage() (uint64, error) {
+	return r.GetTimestamp(), nil
 }
 
-func (txn *RawTxnsResponse) PackProtoMessage(txns []*types.Transaction, err error) error {
+// RevertToSnapshotRequest converters
+
+func (r *RevertToSnapshotRequest) PackProtoMessage(id uint64) error {
+	r.Id = id
+	return nil
+}
+
+func (r *RevertToSnapshotRequest) UnpackProtoMessage() (uint64, error) {
+	return r.GetId(), nil
+}
+
+// RevertToSnapshotResponse converters
+
+func (r *RevertToSnapshotResponse) PackProtoMessage(reverted bool, err error) error {
 	if err != nil {
-		txn.Result = &RawTxnsResponse_Error{Error: new(Error).PackProtoMessage(err)}
+		r.Result = &RevertToSnapshotResponse_Error{Error: new(Error).PackProtoMessage(err)}
 		return nil
 	}
 
-	var rawTxns RawTxns
-	rawTxns.Data, err = sszx.EncodeContainer[*types.Transaction](txns)
-	if err != nil {
-		return err
-	}
-	txn.Result = &RawTxnsResponse_Data{Data: &rawTxns}
+	r.Result = &RevertToSnapshotResponse_Reverted{Reverted: reverted}
 	return nil
 }
 
-func (txn *RawTxnsResponse) UnpackProtoMessage() ([]*types.Transaction, error) {
-	switch txn.GetResult().(type) {
-	case *RawTxnsResponse_Error:
-		return nil, txn.GetError().UnpackProtoMessage()
+func (r *RevertToSnapshotResponse) UnpackProtoMessage() (bool, error) {
+	switch r.GetResult().(type) {
+	case *RevertToSnapshotResponse_Error:
+		return false, r.GetError().UnpackProtoMessage()
 
-	case *RawTxnsResponse_Data:
-		dataResult := txn.GetData()
-		if dataResult == nil {
-			return nil, errors.New("unexpected response")
-		}
-		data := dataResult.GetData()
-		if data == nil {
-			return []*types.Transaction{}, nil
-		}
-		return sszx.DecodeContainer[*types.Transaction](data)
+	case *RevertToSnapshotResponse_Reverted:
+		return r.GetReverted(), nil
 	}
-	return nil, errors.New("unexpected response type")
+	return false, errors.New("unexpected response type")
 }
@@ -1,14 +1,22 @@
 package pb
 
 import (
+	"errors"
+	"fmt"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
 	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
 	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/google/uuid"
+	ma "github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
@@ -136,9 +144,13 @@ func TestCallRequest_PackUnpack(t *testing.T) {
 
 			callArgs := getCallArgs()
 			overrides := getStateOverrides()
+			number := types.BlockNumber(10)
+			timestamp := uint64(1000)
+			baseFee := types.NewValueFromUint64(100)
+			blockOverrides := &rpctypes.BlockOverrides{Number: &number, Timestamp: &timestamp, BaseFee: &baseFee}
 
 			callReq := &CallRequest{}
-			require.NoError(t, callReq.PackProtoMessage(callArgs, blockRef, overrides))
+			require.NoError(t, callReq.PackProtoMessage(callArgs, blockRef, overrides, blockOverrides))
 
 			data, err := proto.Marshal(callReq)
 			require.NoError(t, err)
@@ -146,15 +158,73 @@ func TestCallRequest_PackUnpack(t *testing.T) {
 			var unpacked CallRequest
 			require.NoError(t, proto.Unmarshal(data, &unpacked))
 
-			unpackedCallArgs, unpackedBlockRef, unpackedOverrides, err := unpacked.UnpackProtoMessage()
+			unpackedCallArgs, unpackedBlockRef, unpackedOverrides, unpackedBlockOverrides, err :=
+				unpacked.UnpackProtoMessage()
 			require.NoError(t, err)
 			assert.Equal(t, callArgs, unpackedCallArgs)
 			assert.Equal(t, blockRef, unpackedBlockRef)
 			assert.Equal(t, overrides, unpackedOverrides)
+			assert.Equal(t, blockOverrides, unpackedBlockOverrides)
 		})
 	}
 }
 
+func TestBundle_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	calls := []rpctypes.CallArgs{getCallArgs(), getCallArgs()}
+	blockRef := getBlockRef()
+	overrides := getStateOverrides()
+
+	var request BundleRequest
+	require.NoError(t, request.PackProtoMessage(calls, blockRef, overrides, nil))
+	data, err := proto.Marshal(&request)
+	require.NoError(t, err)
+
+	var unpackedRequest BundleRequest
+	require.NoError(t, proto.Unmarshal(data, &unpackedRequest))
+	unpackedCalls, unpackedBlockRef, unpackedOverrides, unpackedBlockOverrides, err :=
+		unpackedRequest.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, calls, unpackedCalls)
+	assert.Equal(t, blockRef, unpackedBlockRef)
+	assert.Equal(t, overrides, unpackedOverrides)
+	assert.Nil(t, unpackedBlockOverrides)
+
+	results := []*rawapitypes.BundleCallResult{
+		{
+			Data:      []byte{0x01},
+			CoinsUsed: types.NewValueFromUint64(100),
+			GasUsed:   10,
+			Logs: []*types.Log{{
+				Address: types.GenerateRandomAddress(1),
+				Topics:  []common.Hash{common.HexToHash("0x04")},
+				Data:    []byte{0x02},
+			}},
+			OutTransactions: [][]byte{{0x03}},
+		},
+		{
+			CoinsUsed: types.NewValueFromUint64(50),
+			GasUsed:   5,
+			Logs:      []*types.Log{},
+			Error:     "execution reverted",
+		},
+	}
+	var response BundleResponse
+	require.NoError(t, response.PackProtoMessage(results, nil))
+	data, err = proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpackedResponse BundleResponse
+	require.NoError(t, proto.Unmarshal(data, &unpackedResponse))
+	unpackedResults, err := unpackedResponse.UnpackProtoMessage()
+	require.NoError(t, err)
+	require.Len(t, unpackedResults, len(results))
+	assert.Equal(t, results[0], unpackedResults[0])
+	assert.Equal(t, results[1].Error, unpackedResults[1].Error)
+	assert.Equal(t, results[1].GasUsed, unpackedResults[1].GasUsed)
+}
+
 func TestOutTransaction_PackUnpack(t *testing.T) {
 	t.Parallel()
 
@@ -255,3 +325,1762 @@ func TestErrorMap_PackUnpack(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, &Error{Message: "<invalid UTF-8 string>"}, val)
 }
+
+func newTestRawFullBlock(transactions int) *types.RawBlockWithExtractedData {
+	block := &types.RawBlockWithExtractedData{
+		Block:       make([]byte, 512),
+		Errors:      map[common.Hash]string{common.HexToHash("0x01"): "Error"},
+		ChildBlocks: []common.Hash{common.HexToHash("0x02"), common.HexToHash("0x03")},
+		DbTimestamp: 42,
+		Config:      map[string][]byte{"gasPrice": {1, 2}, "validators": {3}},
+	}
+	for i := range transactions {
+		block.InTransactions = append(block.InTransactions, []byte(strconv.Itoa(i)))
+		block.OutTransactions = append(block.OutTransactions, []byte("out"+strconv.Itoa(i)))
+		block.Receipts = append(block.Receipts, make([]byte, 128))
+	}
+	block.InTxCounts = [][]byte{{1}}
+	block.OutTxCounts = [][]byte{{2}, {3}}
+	return block
+}
+
+func TestMarshalRawFullBlockResponse(t *testing.T) {
+	t.Parallel()
+
+	for name, block := range map[string]*types.RawBlockWithExtractedData{
+		"full":  newTestRawFullBlock(3),
+		"empty": {},
+		"nil":   nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var expected RawFullBlockResponse
+			require.NoError(t, expected.PackProtoMessage(block, nil))
+
+			data, err := MarshalRawFullBlockResponse(block)
+			require.NoError(t, err)
+			var actual RawFullBlockResponse
+			require.NoError(t, proto.Unmarshal(data, &actual))
+			assert.True(t, proto.Equal(&expected, &actual), "expected %v, got %v", &expected, &actual)
+		})
+	}
+}
+
+func BenchmarkMarshalRawFullBlockResponse(b *testing.B) {
+	block := newTestRawFullBlock(100)
+
+	b.Run("PackProtoMessage", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			var response RawFullBlockResponse
+			require.NoError(b, response.PackProtoMessage(block, nil))
+			_, err := proto.Marshal(&response)
+			require.NoError(b, err)
+		}
+	})
+
+	b.Run("Passthrough", func(b *testing.B) {
+		b.ReportAllocs()
+		for range b.N {
+			_, err := MarshalRawFullBlockResponse(block)
+			require.NoError(b, err)
+		}
+	})
+}
+
+func TestLogFilterRequest_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	toBlock := types.BlockNumber(20)
+	filter := rawapitypes.LogFilter{
+		FromBlock: 10,
+		ToBlock:   &toBlock,
+		Addresses: []types.Address{types.GenerateRandomAddress(1), types.GenerateRandomAddress(2)},
+		Topics: [][]common.Hash{
+			{common.HexToHash("0x01"), common.HexToHash("0x02")},
+			{},
+			{common.HexToHash("0x03")},
+		},
+		Finalized: true,
+	}
+
+	var request LogFilterRequest
+	require.NoError(t, request.PackProtoMessage(filter))
+
+	data, err := proto.Marshal(&request)
+	require.NoError(t, err)
+
+	var unpacked LogFilterRequest
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedFilter, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, filter, unpackedFilter)
+
+	t.Run("OpenRange", func(t *testing.T) {
+		t.Parallel()
+
+		var request LogFilterRequest
+		require.NoError(t, request.PackProtoMessage(rawapitypes.LogFilter{FromBlock: 5}))
+
+		unpackedFilter, err := request.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, rawapitypes.LogFilter{FromBlock: 5}, unpackedFilter)
+	})
+}
+
+func TestProofResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	proof := &rawapitypes.ContractProof{
+		StateRoot:    common.HexToHash("0xabcd"),
+		ContractSSZ:  []byte{0x1, 0x2},
+		ProofEncoded: []byte{0x3, 0x4},
+		StorageProofs: []rawapitypes.StorageProof{
+			{Key: common.HexToHash("0x01"), Value: types.Uint256{1, 2, 3, 4}, ProofEncoded: []byte{0x5}},
+			{Key: common.HexToHash("0x02"), ProofEncoded: []byte{0x6}},
+		},
+	}
+
+	var response ProofResponse
+	require.NoError(t, response.PackProtoMessage(proof, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked ProofResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedProof, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, proof, unpackedProof)
+}
+
+func TestAccessListResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	result := &rawapitypes.AccessListResult{
+		AccessList: []rawapitypes.AccessTuple{
+			{
+				Address:     types.HexToAddress("0x0001111111111111111111111111111111111111"),
+				StorageKeys: []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")},
+			},
+			{
+				Address:     types.HexToAddress("0x0002222222222222222222222222222222222222"),
+				StorageKeys: []common.Hash{},
+			},
+		},
+		Error: "out of gas",
+	}
+
+	var response AccessListResponse
+	require.NoError(t, response.PackProtoMessage(result, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked AccessListResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedResult, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, result, unpackedResult)
+}
+
+func TestDeploy_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	request := rawapitypes.DeployRequest{
+		ShardId:             1,
+		Code:                types.Code{0x60, 0x80},
+		Salt:                common.HexToHash("0x03"),
+		ValidateConstructor: true,
+	}
+	var packedRequest DeployRequest
+	require.NoError(t, packedRequest.PackProtoMessage(request))
+	data, err := proto.Marshal(&packedRequest)
+	require.NoError(t, err)
+
+	var unpackedRequest DeployRequest
+	require.NoError(t, proto.Unmarshal(data, &unpackedRequest))
+	unpackedDeployRequest, err := unpackedRequest.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, request, unpackedDeployRequest)
+
+	preparation := &rawapitypes.DeployPreparation{
+		Address: types.HexToAddress("0x0001111111111111111111111111111111111111"),
+		Payload: []byte{0x60, 0x80, 0x03},
+		Balance: types.NewValueFromUint64(1000),
+		Fee: &rawapitypes.FeeEstimation{
+			FeeCredit:       types.NewValueFromUint64(100),
+			ExecutionFee:    types.NewValueFromUint64(50),
+			ForwardingFee:   types.NewValueFromUint64(20),
+			VerificationFee: types.NewValueFromUint64(10),
+			BaseFee:         types.NewValueFromUint64(2),
+			MaxBaseFee:      types.NewValueFromUint64(4),
+		},
+		SimulationError: "execution reverted",
+		RevertData:      []byte{0x08, 0xc3},
+	}
+	var response DeployPreparationResponse
+	require.NoError(t, response.PackProtoMessage(preparation, nil))
+	data, err = proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpackedResponse DeployPreparationResponse
+	require.NoError(t, proto.Unmarshal(data, &unpackedResponse))
+	unpackedPreparation, err := unpackedResponse.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, preparation, unpackedPreparation)
+}
+
+func TestExecutionTraceResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	trace := &rawapitypes.ExecutionTrace{
+		Steps: []rawapitypes.OpcodeStep{
+			{Pc: 0, Op: "PUSH1", Gas: 1000, GasCost: 3, Depth: 1, Stack: []types.Uint256{}},
+			{Pc: 2, Op: "CALL", Gas: 997, GasCost: 100, Depth: 1, Stack: []types.Uint256{*types.NewUint256(5)}},
+			{Pc: 3, Op: "SSTORE", Gas: 10, GasCost: 20000, Depth: 2, Stack: []types.Uint256{}, Error: "out of gas"},
+		},
+		StepsTruncated: true,
+		Call: &rawapitypes.CallFrame{
+			Type:    "CALL",
+			From:    types.HexToAddress("0x0001111111111111111111111111111111111111"),
+			To:      types.HexToAddress("0x0001222222222222222222222222222222222222"),
+			Input:   []byte{1, 2, 3},
+			Output:  []byte{4},
+			Gas:     1000,
+			GasUsed: 900,
+			Value:   types.NewValueFromUint64(7),
+			Calls: []*rawapitypes.CallFrame{
+				{
+					Type:    "STATICCALL",
+					From:    types.HexToAddress("0x0001222222222222222222222222222222222222"),
+					To:      types.HexToAddress("0x0001333333333333333333333333333333333333"),
+					Input:   []byte{5},
+					Output:  []byte{6},
+					Gas:     500,
+					GasUsed: 500,
+					Value:   types.NewZeroValue(),
+					Error:   "out of gas",
+					Calls:   []*rawapitypes.CallFrame{},
+				},
+			},
+		},
+		GasUsed: 900,
+		Error:   "out of gas",
+	}
+
+	var response ExecutionTraceResponse
+	require.NoError(t, response.PackProtoMessage(trace, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked ExecutionTraceResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedTrace, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, trace, unpackedTrace)
+}
+
+func TestReplay_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	hash := common.HexToHash("0x0001aabbcc")
+	options := rawapitypes.ReplayOptions{DisableSteps: true}
+
+	var request ReplayTransactionRequest
+	require.NoError(t, request.PackProtoMessage(hash, options))
+	data, err := proto.Marshal(&request)
+	require.NoError(t, err)
+
+	var unpackedRequest ReplayTransactionRequest
+	require.NoError(t, proto.Unmarshal(data, &unpackedRequest))
+	unpackedHash, unpackedOptions, err := unpackedRequest.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, hash, unpackedHash)
+	assert.Equal(t, options, unpackedOptions)
+
+	result := &rawapitypes.ReplayResult{
+		ReturnData: []byte{0x01},
+		Logs: []*types.Log{{
+			Address: types.GenerateRandomAddress(1),
+			Topics:  []common.Hash{common.HexToHash("0x04")},
+			Data:    []byte{0x02},
+		}},
+		Trace: &rawapitypes.ExecutionTrace{
+			Steps:   []rawapitypes.OpcodeStep{},
+			GasUsed: 21,
+			Error:   "execution reverted",
+		},
+		Mismatches: []string{"gasUsed", "logs"},
+	}
+	var response ReplayResponse
+	require.NoError(t, response.PackProtoMessage(result, nil))
+	data, err = proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpackedResponse ReplayResponse
+	require.NoError(t, proto.Unmarshal(data, &unpackedResponse))
+	unpackedResult, err := unpackedResponse.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, result, unpackedResult)
+}
+
+func TestAsyncIntrospection_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	next := types.TransactionIndex(5)
+	responses := &rawapitypes.PendingResponses{
+		Responses: []*rawapitypes.PendingResponse{
+			{RequestId: 1, ResponseProcessingGas: 50_000},
+			{RequestId: 3, ResponseProcessingGas: 70_000},
+		},
+		Next: &next,
+	}
+	var pendingResponse PendingResponsesResponse
+	require.NoError(t, pendingResponse.PackProtoMessage(responses, nil))
+	data, err := proto.Marshal(&pendingResponse)
+	require.NoError(t, err)
+
+	var unpackedPendingResponse PendingResponsesResponse
+	require.NoError(t, proto.Unmarshal(data, &unpackedPendingResponse))
+	unpackedResponses, err := unpackedPendingResponse.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, responses, unpackedResponses)
+
+	awaiting := &rawapitypes.AwaitingContext{
+		Flags:    types.NewTransactionFlags(types.TransactionFlagInternal, types.TransactionFlagResponse),
+		From:     types.HexToAddress("0x0002222222222222222222222222222222222222"),
+		To:       types.HexToAddress("0x0001111111111111111111111111111111111111"),
+		Received: true,
+		Requests: []rawapitypes.AwaitedRequest{
+			{Caller: types.HexToAddress("0x0001111111111111111111111111111111111111"), RequestId: 3},
+			{Caller: types.HexToAddress("0x0003333333333333333333333333333333333333"), RequestId: 7, Pending: true},
+		},
+	}
+	var awaitingResponse AwaitingContextResponse
+	require.NoError(t, awaitingResponse.PackProtoMessage(awaiting, nil))
+	data, err = proto.Marshal(&awaitingResponse)
+	require.NoError(t, err)
+
+	var unpackedAwaitingResponse AwaitingContextResponse
+	require.NoError(t, proto.Unmarshal(data, &unpackedAwaitingResponse))
+	unpackedAwaiting, err := unpackedAwaitingResponse.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, awaiting, unpackedAwaiting)
+}
+
+func TestStateDiffResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	stateDiff := &rawapitypes.StateDiff{
+		Accounts: []rawapitypes.AccountDiff{
+			{
+				Address: types.HexToAddress("0x0001111111111111111111111111111111111111"),
+				Balance: &rawapitypes.BalanceDiff{
+					Pre:  types.NewValueFromUint64(100),
+					Post: types.NewValueFromUint64(90),
+				},
+				Storage: []rawapitypes.StorageDiff{},
+			},
+			{
+				Address: types.HexToAddress("0x0001222222222222222222222222222222222222"),
+				Storage: []rawapitypes.StorageDiff{
+					{Key: common.HexToHash("0x01"), Pre: common.EmptyHash, Post: common.HexToHash("0x2a")},
+					{Key: common.HexToHash("0x02"), Pre: common.HexToHash("0x07"), Post: common.EmptyHash},
+				},
+			},
+			{
+				Address: types.HexToAddress("0x0001333333333333333333333333333333333333"),
+				Deleted: true,
+				Storage: []rawapitypes.StorageDiff{},
+			},
+		},
+		Error: "execution reverted",
+	}
+
+	var response StateDiffResponse
+	require.NoError(t, response.PackProtoMessage(stateDiff, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked StateDiffResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedStateDiff, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, stateDiff, unpackedStateDiff)
+}
+
+func TestBounceInfoResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	info := &rawapitypes.BounceInfo{
+		ErrorMessage:       "execution reverted",
+		Bounced:            true,
+		BounceTo:           types.HexToAddress("0x0001111111111111111111111111111111111111"),
+		BouncedValue:       types.NewValueFromUint64(1000),
+		BounceTransaction:  common.HexToHash("0x05"),
+		RefundTo:           types.HexToAddress("0x0002222222222222222222222222222222222222"),
+		Refunded:           types.NewValueFromUint64(30),
+		RefundTransactions: []common.Hash{common.HexToHash("0x06"), common.HexToHash("0x07")},
+	}
+
+	var response BounceInfoResponse
+	require.NoError(t, response.PackProtoMessage(info, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked BounceInfoResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedInfo, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, info, unpackedInfo)
+}
+
+func TestReceiptsResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	infos := []*rawapitypes.ReceiptInfo{
+		{
+			ReceiptSSZ:      []byte{1, 2, 3},
+			Flags:           types.NewTransactionFlags(types.TransactionFlagInternal),
+			Index:           0,
+			BlockHash:       common.HexToHash("0x0a"),
+			BlockId:         10,
+			OutTransactions: []common.Hash{common.HexToHash("0x01")},
+			IncludedInMain:  true,
+			GasPrice:        types.NewValueFromUint64(10),
+		},
+		{
+			ReceiptSSZ:      []byte{4, 5},
+			Index:           1,
+			BlockHash:       common.HexToHash("0x0a"),
+			BlockId:         10,
+			OutTransactions: []common.Hash{},
+			ErrorMessage:    "execution reverted",
+			GasPrice:        types.NewValueFromUint64(10),
+		},
+	}
+
+	var response ReceiptsResponse
+	require.NoError(t, response.PackProtoMessage(infos, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked ReceiptsResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedInfos, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, infos, unpackedInfos)
+}
+
+func TestShardStats_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	var request ShardStatsRequest
+	require.NoError(t, request.PackProtoMessage(10, 100))
+	data, err := proto.Marshal(&request)
+	require.NoError(t, err)
+
+	var unpackedRequest ShardStatsRequest
+	require.NoError(t, proto.Unmarshal(data, &unpackedRequest))
+	from, count, err := unpackedRequest.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, types.BlockNumber(10), from)
+	assert.Equal(t, uint64(100), count)
+
+	stats := &rawapitypes.ShardStats{
+		FromBlock:           10,
+		ToBlock:             109,
+		BlockCount:          100,
+		EmptyBlockCount:     40,
+		GasUsed:             5_000_000,
+		AverageGasUsed:      50_000,
+		MaxGasUsed:          300_000,
+		InTransactionCount:  250,
+		OutTransactionCount: 120,
+		CrossShardInCount:   70,
+		CrossShardOutCount:  90,
+	}
+	var response ShardStatsResponse
+	require.NoError(t, response.PackProtoMessage(stats, nil))
+	data, err = proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpackedResponse ShardStatsResponse
+	require.NoError(t, proto.Unmarshal(data, &unpackedResponse))
+	unpackedStats, err := unpackedResponse.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, stats, unpackedStats)
+}
+
+func TestBlockRequest_PackUnpackNamed(t *testing.T) {
+	t.Parallel()
+
+	for _, identifier := range []rawapitypes.NamedBlockIdentifier{
+		rawapitypes.EarliestBlock,
+		rawapitypes.LatestBlock,
+		rawapitypes.PendingBlock,
+		rawapitypes.SafeBlock,
+		rawapitypes.FinalizedBlock,
+	} {
+		blockReference := rawapitypes.NamedBlockIdentifierAsBlockReference(identifier)
+
+		var request BlockRequest
+		require.NoError(t, request.PackProtoMessage(blockReference))
+
+		data, err := proto.Marshal(&request)
+		require.NoError(t, err)
+
+		var unpacked BlockRequest
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedReference, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, blockReference, unpackedReference)
+	}
+}
+
+func TestTransactionStatusResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	info := &rawapitypes.TransactionStatusInfo{
+		Status:       rawapitypes.TransactionStatusFailed,
+		ErrorMessage: "out of gas",
+	}
+
+	var response TransactionStatusResponse
+	require.NoError(t, response.PackProtoMessage(info, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked TransactionStatusResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedInfo, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, info, unpackedInfo)
+}
+
+func TestPoolContentResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	address := types.ShardAndHexToAddress(1, "deadbeef")
+	content := &rawapitypes.PoolContent{
+		Pending: []*types.Transaction{
+			{TransactionDigest: types.TransactionDigest{To: address, Seqno: 0}},
+		},
+		Queued: []*types.Transaction{
+			{TransactionDigest: types.TransactionDigest{To: address, Seqno: 2}, Value: types.NewValueFromUint64(10)},
+		},
+	}
+
+	var response PoolContentResponse
+	require.NoError(t, response.PackProtoMessage(content, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked PoolContentResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedContent, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	require.Len(t, unpackedContent.Pending, 1)
+	require.Len(t, unpackedContent.Queued, 1)
+	assert.Equal(t, content.Pending[0].Hash(), unpackedContent.Pending[0].Hash())
+	assert.Equal(t, content.Queued[0].Hash(), unpackedContent.Queued[0].Hash())
+}
+
+func TestSnapshotChunkResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	next := common.HexToHash("0x1234")
+	chunk := &rawapitypes.SnapshotChunk{
+		Contracts: []*rawapitypes.SmartContract{{
+			ContractSSZ: []byte{1, 2, 3},
+			Code:        types.Code{4, 5},
+			Storage:     map[common.Hash]types.Uint256{common.HexToHash("0x01"): *types.NewUint256(7)},
+		}},
+		Next: &next,
+	}
+
+	var response SnapshotChunkResponse
+	require.NoError(t, response.PackProtoMessage(chunk, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked SnapshotChunkResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedChunk, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, chunk, unpackedChunk)
+
+	chunk.Next = nil
+	require.NoError(t, response.PackProtoMessage(chunk, nil))
+	unpackedChunk, err = response.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Nil(t, unpackedChunk.Next)
+}
+
+func TestBalances_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	addresses := []types.Address{types.GenerateRandomAddress(1), types.GenerateRandomAddress(2)}
+	blockReference := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+
+	var request MultiAccountRequest
+	require.NoError(t, request.PackProtoMessage(addresses, blockReference))
+	data, err := proto.Marshal(&request)
+	require.NoError(t, err)
+
+	var unpackedRequest MultiAccountRequest
+	require.NoError(t, proto.Unmarshal(data, &unpackedRequest))
+	unpackedAddresses, unpackedBlockReference, err := unpackedRequest.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, addresses, unpackedAddresses)
+	assert.Equal(t, blockReference, unpackedBlockReference)
+
+	balances := []types.Value{types.NewValueFromUint64(5), types.NewZeroValue()}
+	var response BalancesResponse
+	require.NoError(t, response.PackProtoMessage(balances, nil))
+	data, err = proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpackedResponse BalancesResponse
+	require.NoError(t, proto.Unmarshal(data, &unpackedResponse))
+	unpackedBalances, err := unpackedResponse.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, balances, unpackedBalances)
+}
+
+func TestStorageRangeResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	next := common.HexToHash("0x03")
+	storageRange := &rawapitypes.StorageRange{
+		Entries: []rawapitypes.StorageEntry{
+			{Key: common.HexToHash("0x01"), Value: *types.NewUint256(10)},
+			{Key: common.HexToHash("0x02"), Value: *types.NewUint256(20)},
+		},
+		Next: &next,
+	}
+
+	var response StorageRangeResponse
+	require.NoError(t, response.PackProtoMessage(storageRange, nil))
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked StorageRangeResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+	unpackedRange, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, storageRange, unpackedRange)
+
+	// The zero key is distinguished from the absent one.
+	next = common.EmptyHash
+	require.NoError(t, response.PackProtoMessage(storageRange, nil))
+	unpackedRange, err = response.UnpackProtoMessage()
+	require.NoError(t, err)
+	require.NotNil(t, unpackedRange.Next)
+	assert.Equal(t, common.EmptyHash, *unpackedRange.Next)
+}
+
+func TestTokensRequest_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	address := types.GenerateRandomAddress(1)
+	blockReference := rawapitypes.NamedBlockIdentifierAsBlockReference(rawapitypes.LatestBlock)
+	start := types.TokenId(types.GenerateRandomAddress(2))
+	request := rawapitypes.TokensRequest{
+		Start:    &start,
+		Limit:    10,
+		TokenIds: []types.TokenId{start, types.TokenId(types.GenerateRandomAddress(3))},
+	}
+
+	var packed TokensRequest
+	require.NoError(t, packed.PackProtoMessage(address, blockReference, request))
+	data, err := proto.Marshal(&packed)
+	require.NoError(t, err)
+
+	var unpacked TokensRequest
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+	unpackedAddress, unpackedBlockReference, unpackedRequest, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, address, unpackedAddress)
+	assert.Equal(t, blockReference, unpackedBlockReference)
+	assert.Equal(t, request, unpackedRequest)
+
+	// The account request is handled as the request of the first page of the tokens.
+	var accountRequest AccountRequest
+	require.NoError(t, accountRequest.PackProtoMessage(address, blockReference))
+	data, err = proto.Marshal(&accountRequest)
+	require.NoError(t, err)
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+	unpackedAddress, _, unpackedRequest, err = unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, address, unpackedAddress)
+	assert.Equal(t, rawapitypes.TokensRequest{}, unpackedRequest)
+}
+
+func TestTokensResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	next := types.TokenId(types.GenerateRandomAddress(1))
+	page := &rawapitypes.TokensPage{
+		Tokens: map[types.TokenId]types.Value{
+			types.TokenId(types.GenerateRandomAddress(2)): types.NewValueFromUint64(5),
+		},
+		Next: &next,
+	}
+
+	var response TokensResponse
+	require.NoError(t, response.PackProtoMessage(page, nil))
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked TokensResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+	unpackedPage, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, page, unpackedPage)
+
+	page.Next = nil
+	require.NoError(t, response.PackProtoMessage(page, nil))
+	unpackedPage, err = response.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Nil(t, unpackedPage.Next)
+}
+
+func TestAccountMetaResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	meta := &rawapitypes.AccountMeta{
+		Exists:         true,
+		IsSmartAccount: true,
+		CodeHash:       common.HexToHash("0x1234"),
+		StorageRoot:    common.HexToHash("0x5678"),
+		ExtSeqno:       3,
+	}
+
+	var response AccountMetaResponse
+	require.NoError(t, response.PackProtoMessage(meta, nil))
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked AccountMetaResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+	unpackedMeta, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, meta, unpackedMeta)
+}
+
+func TestTokenInfosResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	owner := types.GenerateRandomAddress(1)
+	infos := []*rawapitypes.TokenInfo{{
+		Id:          types.TokenId(owner),
+		Name:        "token",
+		Symbol:      "TKN",
+		Owner:       owner,
+		TotalSupply: types.NewValueFromUint64(100),
+	}}
+
+	var response TokenInfosResponse
+	require.NoError(t, response.PackProtoMessage(infos, nil))
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked TokenInfosResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+	unpackedInfos, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, infos, unpackedInfos)
+}
+
+func TestChainConfigResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	chainConfig := &rawapitypes.ChainConfig{
+		ChainId:         types.DefaultChainId,
+		ProtocolVersion: "1.0",
+		GenesisHash:     common.HexToHash("0x0001aabbcc"),
+		PatchLevel:      2,
+		Shards: []rawapitypes.ShardConfig{
+			{Id: types.MainShardId, GasPrice: types.NewValueFromUint64(10), Validators: 3},
+			{Id: types.BaseShardId, GasPrice: types.NewValueFromUint64(20), Validators: 2},
+		},
+	}
+
+	var response ChainConfigResponse
+	require.NoError(t, response.PackProtoMessage(chainConfig, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked ChainConfigResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedChainConfig, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, chainConfig, unpackedChainConfig)
+}
+
+func TestSyncStatusResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	status := &rawapitypes.SyncStatus{
+		CurrentBlock:      10,
+		HighestKnownBlock: 15,
+		Phase:             rawapitypes.SyncPhaseReplaying,
+		Peers:             3,
+	}
+
+	var response SyncStatusResponse
+	require.NoError(t, response.PackProtoMessage(status, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked SyncStatusResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedStatus, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, status, unpackedStatus)
+}
+
+func TestPeerAclResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := network.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, _, peerId, err := network.SerializeKeys(privKey)
+	require.NoError(t, err)
+
+	acl := rawapitypes.PeerAcl{
+		"SendTransaction":         {peerId},
+		"/shard/1/rawapi_ro/Call": {},
+	}
+
+	var response PeerAclResponse
+	require.NoError(t, response.PackProtoMessage(acl, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked PeerAclResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedAcl, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, acl, unpackedAcl)
+
+	invalidAcl := &PeerAcl{Entries: map[string]*PeerIds{"SendTransaction": {PeerIds: [][]byte{{1, 2, 3}}}}}
+	_, err = invalidAcl.UnpackProtoMessage()
+	require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+}
+
+func TestPeerUsageResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := network.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, _, peerId, err := network.SerializeKeys(privKey)
+	require.NoError(t, err)
+
+	usages := []rawapitypes.PeerUsage{
+		{PeerId: peerId, Requests: 3, RequestBytes: 100, ResponseBytes: 1000, CallTime: 5 * time.Millisecond},
+		{PeerId: peerId, Bans: 1, BannedUntil: time.UnixMilli(1700000000000)},
+	}
+
+	var response PeerUsageResponse
+	require.NoError(t, response.PackProtoMessage(usages, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked PeerUsageResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedUsages, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, usages, unpackedUsages)
+}
+
+func TestPeerControlRequests_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := network.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, _, peerId, err := network.SerializeKeys(privKey)
+	require.NoError(t, err)
+
+	t.Run("AddPeer", func(t *testing.T) {
+		t.Parallel()
+
+		addr := network.AddrInfo{ID: peerId, Addrs: []ma.Multiaddr{ma.StringCast("/ip4/127.0.0.1/tcp/3000")}}
+
+		var request AddPeerRequest
+		require.NoError(t, request.PackProtoMessage(addr))
+
+		data, err := proto.Marshal(&request)
+		require.NoError(t, err)
+
+		var unpacked AddPeerRequest
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedAddr, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, addr, unpackedAddr)
+
+		invalidRequest := &AddPeerRequest{PeerId: []byte(peerId), Addrs: []string{"127.0.0.1:3000"}}
+		_, err = invalidRequest.UnpackProtoMessage()
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+	})
+
+	t.Run("SetPeerTag", func(t *testing.T) {
+		t.Parallel()
+
+		var request SetPeerTagRequest
+		require.NoError(t, request.PackProtoMessage(peerId, "validator", -10))
+
+		data, err := proto.Marshal(&request)
+		require.NoError(t, err)
+
+		var unpacked SetPeerTagRequest
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedPeerId, tag, value, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, peerId, unpackedPeerId)
+		assert.Equal(t, "validator", tag)
+		assert.Equal(t, int64(-10), value)
+
+		_, _, _, err = (&SetPeerTagRequest{PeerId: []byte(peerId)}).UnpackProtoMessage()
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+	})
+
+	t.Run("ProtectPeer", func(t *testing.T) {
+		t.Parallel()
+
+		var request ProtectPeerRequest
+		require.NoError(t, request.PackProtoMessage(peerId, "validator", true))
+
+		data, err := proto.Marshal(&request)
+		require.NoError(t, err)
+
+		var unpacked ProtectPeerRequest
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedPeerId, tag, protect, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, peerId, unpackedPeerId)
+		assert.Equal(t, "validator", tag)
+		assert.True(t, protect)
+
+		_, _, _, err = (&ProtectPeerRequest{PeerId: []byte(peerId)}).UnpackProtoMessage()
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+		_, _, _, err = (&ProtectPeerRequest{PeerId: []byte("invalid"), Tag: "validator"}).UnpackProtoMessage()
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+	})
+}
+
+func TestPeerInfosResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	privKey, err := network.GeneratePrivateKey()
+	require.NoError(t, err)
+	_, _, peerId, err := network.SerializeKeys(privKey)
+	require.NoError(t, err)
+
+	peers := []network.PeerInfo{
+		{
+			Id:              peerId,
+			Addrs:           []string{"/ip4/127.0.0.1/tcp/3000"},
+			ProtocolVersion: "/nil/0.1",
+			Connections:     2,
+			Streams:         map[network.ProtocolID]uint64{"/shard/1/rawapi_ro/GetBlock": 3},
+			Bandwidth:       network.BandwidthStats{TotalIn: 100, TotalOut: 1000, RateIn: 1.5, RateOut: 15},
+		},
+	}
+
+	var response PeerInfosResponse
+	require.NoError(t, response.PackProtoMessage(peers, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked PeerInfosResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedPeers, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, peers, unpackedPeers)
+}
+
+func TestProtocolBandwidthResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	bandwidth := map[network.ProtocolID]network.BandwidthStats{
+		"/shard/1/rawapi_ro/GetBlock": {TotalIn: 100, TotalOut: 1000, RateIn: 1.5, RateOut: 15},
+		"/ipfs/id/1.0.0":              {TotalIn: 10, TotalOut: 10},
+	}
+
+	var response ProtocolBandwidthResponse
+	require.NoError(t, response.PackProtoMessage(bandwidth, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked ProtocolBandwidthResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedBandwidth, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, bandwidth, unpackedBandwidth)
+}
+
+func TestError_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	packUnpack := func(err error) error {
+		t.Helper()
+
+		data, marshalErr := proto.Marshal(new(Error).PackProtoMessage(err))
+		require.NoError(t, marshalErr)
+
+		var unpacked Error
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+		return unpacked.UnpackProtoMessage()
+	}
+
+	t.Run("NotFound", func(t *testing.T) {
+		t.Parallel()
+
+		err := packUnpack(fmt.Errorf("failed to read block: %w", db.ErrKeyNotFound))
+		require.ErrorIs(t, err, rawapitypes.ErrNotFound)
+		require.EqualError(t, err, "failed to read block: "+db.ErrKeyNotFound.Error())
+
+		require.ErrorIs(t, packUnpack(db.ErrKeyNotFound), db.ErrKeyNotFound)
+	})
+
+	t.Run("ExecutionReverted", func(t *testing.T) {
+		t.Parallel()
+
+		revertData := []byte{0x08, 0xc3, 0x79, 0xa0}
+		err := packUnpack(rawapitypes.NewExecutionRevertedError("ExecutionReverted", revertData))
+		require.ErrorIs(t, err, rawapitypes.ErrExecutionReverted)
+		require.NotErrorIs(t, err, rawapitypes.ErrInternal)
+
+		data, ok := rawapitypes.RevertData(err)
+		require.True(t, ok)
+		require.Equal(t, revertData, data)
+	})
+
+	t.Run("StatePruned", func(t *testing.T) {
+		t.Parallel()
+
+		err := packUnpack(rawapitypes.NewStatePrunedError(10, 100))
+		require.ErrorIs(t, err, rawapitypes.ErrStatePruned)
+
+		earliest, ok := rawapitypes.EarliestAvailableBlock(err)
+		require.True(t, ok)
+		require.Equal(t, types.BlockNumber(100), earliest)
+	})
+
+	t.Run("Overloaded", func(t *testing.T) {
+		t.Parallel()
+
+		err := packUnpack(rawapitypes.NewOverloadedError(1500 * time.Millisecond))
+		require.ErrorIs(t, err, rawapitypes.ErrOverloaded)
+
+		retryAfter, ok := rawapitypes.RetryAfter(err)
+		require.True(t, ok)
+		require.Equal(t, 1500*time.Millisecond, retryAfter)
+	})
+
+	t.Run("Internal", func(t *testing.T) {
+		t.Parallel()
+
+		err := packUnpack(errors.New("unclassified"))
+		require.ErrorIs(t, err, rawapitypes.ErrInternal)
+		require.Equal(t, rawapitypes.InternalErrorCode, rawapitypes.ErrorCodeOf(err))
+	})
+
+	t.Run("Classified", func(t *testing.T) {
+		t.Parallel()
+
+		// The error wrapping several sentinels is classified by the first of them in the order of the codes.
+		wrapped := fmt.Errorf("%w: %w", rawapitypes.ErrTimeout, rawapitypes.ErrShardMismatch)
+		for range 10 {
+			require.Equal(t, rawapitypes.ShardMismatchErrorCode, rawapitypes.ErrorCodeOf(wrapped))
+		}
+		require.ErrorIs(t, packUnpack(wrapped), rawapitypes.ErrShardMismatch)
+	})
+}
+
+func TestFinalitySignaturesResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	signatures := &rawapitypes.FinalitySignatures{
+		BlockHash:     common.HexToHash("0x0001aabbcc"),
+		BlockNumber:   10,
+		Round:         1,
+		ProposerIndex: 2,
+		Signature:     []byte{1, 2, 3},
+		SignersMask:   []byte{0b101},
+		PublicKeys:    [][]byte{{4}, {5}, {6}},
+		Signers:       2,
+		Quorum:        3,
+	}
+
+	var response FinalitySignaturesResponse
+	require.NoError(t, response.PackProtoMessage(signatures, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked FinalitySignaturesResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedSignatures, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, signatures, unpackedSignatures)
+}
+
+func TestValidators_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	validators := []rawapitypes.ValidatorInfo{
+		{
+			Index:             0,
+			PublicKey:         []byte{1, 2},
+			WithdrawalAddress: types.HexToAddress("0x0001111111111111111111111111111111111111"),
+			Weight:            1,
+		},
+		{
+			Index:             1,
+			PublicKey:         []byte{3, 4},
+			WithdrawalAddress: types.HexToAddress("0x0001222222222222222222222222222222222222"),
+			Weight:            1,
+		},
+	}
+
+	t.Run("ValidatorSet", func(t *testing.T) {
+		t.Parallel()
+
+		set := &rawapitypes.ValidatorSet{
+			BlockHash:   common.HexToHash("0x0001aabbcc"),
+			BlockNumber: 10,
+			Validators:  validators,
+			TotalWeight: 2,
+			Quorum:      2,
+		}
+
+		var response ValidatorSetResponse
+		require.NoError(t, response.PackProtoMessage(set, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked ValidatorSetResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedSet, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, set, unpackedSet)
+	})
+
+	t.Run("ValidatorInfo", func(t *testing.T) {
+		t.Parallel()
+
+		var request ValidatorRequest
+		require.NoError(t, request.PackProtoMessage(validators[1].PublicKey))
+		publicKey, err := request.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, validators[1].PublicKey, publicKey)
+
+		var response ValidatorInfoResponse
+		require.NoError(t, response.PackProtoMessage(&validators[1], nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked ValidatorInfoResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		info, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, &validators[1], info)
+	})
+
+	t.Run("EpochInfo", func(t *testing.T) {
+		t.Parallel()
+
+		info := &rawapitypes.EpochInfo{
+			BlockNumber:         10,
+			Round:               1,
+			ProposerIndex:       1,
+			ValidatorCount:      2,
+			ValidatorSetChanged: true,
+			NextProposers:       []uint64{0, 1},
+		}
+
+		var response EpochInfoResponse
+		require.NoError(t, response.PackProtoMessage(info, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked EpochInfoResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedInfo, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, info, unpackedInfo)
+	})
+}
+
+func TestProofApi_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("StateProof", func(t *testing.T) {
+		t.Parallel()
+
+		proof := &rawapitypes.StateProof{
+			BlockHash:   common.HexToHash("0x0001aabbcc"),
+			BlockNumber: 10,
+			TaskId:      uuid.New(),
+			Proof:       []byte{1, 2, 3},
+		}
+
+		var response StateProofResponse
+		require.NoError(t, response.PackProtoMessage(proof, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked StateProofResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedProof, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, proof, unpackedProof)
+	})
+
+	t.Run("ProofTask", func(t *testing.T) {
+		t.Parallel()
+
+		info := &rawapitypes.ProofTaskInfo{
+			Id:        uuid.New(),
+			Status:    rawapitypes.ProofTaskStatusFailed,
+			BlockHash: common.HexToHash("0x0001aabbcc"),
+			Error:     "prover is out of memory",
+		}
+
+		var request ProofTaskRequest
+		require.NoError(t, request.PackProtoMessage(info.Id))
+		id, err := request.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, info.Id, id)
+
+		var response ProofTaskResponse
+		require.NoError(t, response.PackProtoMessage(info, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked ProofTaskResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedInfo, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, info, unpackedInfo)
+
+		_, err = (&ProofTaskRequest{Id: "not-a-uuid"}).UnpackProtoMessage()
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+	})
+}
+
+func TestDbApi_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	var request DbListKeysRequest
+	require.NoError(t, request.PackProtoMessage("Blocks:1", []byte{1, 2}, []byte{1, 2, 3}, 10))
+	table, prefix, after, limit, err := request.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, "Blocks:1", table)
+	assert.Equal(t, []byte{1, 2}, prefix)
+	assert.Equal(t, []byte{1, 2, 3}, after)
+	assert.Equal(t, uint64(10), limit)
+
+	keys := [][]byte{{1, 2, 3}, {1, 2, 4}}
+	var response DbKeysResponse
+	require.NoError(t, response.PackProtoMessage(keys, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+
+	var unpacked DbKeysResponse
+	require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+	unpackedKeys, err := unpacked.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, keys, unpackedKeys)
+}
+
+func TestConsensusApi_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	var request ConsensusShardRequest
+	require.NoError(t, request.PackProtoMessage(types.ShardId(2)))
+	shardId, err := request.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, types.ShardId(2), shardId)
+
+	t.Run("ConsensusState", func(t *testing.T) {
+		t.Parallel()
+
+		state := &rawapitypes.ConsensusState{
+			Height:            10,
+			Round:             2,
+			Step:              "prepare",
+			ProposerIndex:     1,
+			ProposerPublicKey: []byte{1, 2},
+			ProposalHash:      []byte{3, 4},
+			Prepares:          [][]byte{{1, 2}, {5, 6}},
+			Commits:           [][]byte{{5, 6}},
+			RoundChangeRound:  3,
+			RoundChanges:      [][]byte{{7, 8}},
+		}
+
+		var response ConsensusStateResponse
+		require.NoError(t, response.PackProtoMessage(state, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked ConsensusStateResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedState, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, state, unpackedState)
+	})
+
+	t.Run("RoundTimeouts", func(t *testing.T) {
+		t.Parallel()
+
+		timeouts := []rawapitypes.RoundTimeout{
+			{Height: 10, Round: 0, Step: "new round", Time: time.UnixMilli(1700000000000)},
+			{Height: 10, Round: 1, Step: "prepare", Time: time.UnixMilli(1700000020000)},
+		}
+
+		var response RoundTimeoutsResponse
+		require.NoError(t, response.PackProtoMessage(timeouts, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked RoundTimeoutsResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedTimeouts, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, timeouts, unpackedTimeouts)
+	})
+}
+
+func TestClusterApi_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("FamilyTransactionCount", func(t *testing.T) {
+		t.Parallel()
+
+		count := &rawapitypes.FamilyTransactionCount{
+			Total: 5,
+			Shards: []rawapitypes.ShardTransactionCount{
+				{ShardId: 0, Address: types.ShardAndHexToAddress(0, "0x11"), Count: 0},
+				{ShardId: 1, Address: types.ShardAndHexToAddress(1, "0x11"), Count: 5},
+			},
+		}
+
+		var response FamilyTransactionCountResponse
+		require.NoError(t, response.PackProtoMessage(count, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked FamilyTransactionCountResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedCount, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, count, unpackedCount)
+	})
+
+	t.Run("LatestBlocks", func(t *testing.T) {
+		t.Parallel()
+
+		heads := []*rawapitypes.ShardBlockHead{
+			{ShardId: 0, BlockNumber: 10, BlockHash: common.HexToHash("0x0000aabbcc")},
+			{ShardId: 1, BlockNumber: 12, BlockHash: common.HexToHash("0x0001aabbcc")},
+		}
+
+		var response ShardBlockHeadsResponse
+		require.NoError(t, response.PackProtoMessage(heads, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked ShardBlockHeadsResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedHeads, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, heads, unpackedHeads)
+	})
+
+	t.Run("GasPriceStats", func(t *testing.T) {
+		t.Parallel()
+
+		stats := &rawapitypes.GasPriceStats{
+			Min:    types.NewValueFromUint64(10),
+			Max:    types.NewValueFromUint64(30),
+			Mean:   types.NewValueFromUint64(20),
+			Median: types.NewValueFromUint64(20),
+			Shards: []rawapitypes.ShardGasPrice{
+				{ShardId: 0, GasPrice: types.NewValueFromUint64(10)},
+				{ShardId: 1, GasPrice: types.NewValueFromUint64(30)},
+			},
+		}
+
+		var response GasPriceStatsResponse
+		require.NoError(t, response.PackProtoMessage(stats, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked GasPriceStatsResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedStats, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, stats, unpackedStats)
+	})
+}
+
+func TestResolveShard_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Request", func(t *testing.T) {
+		t.Parallel()
+
+		address := types.ShardAndHexToAddress(2, "0x11")
+
+		var request ResolveShardRequest
+		require.NoError(t, request.PackProtoMessage(address))
+		unpacked, err := request.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, address, unpacked)
+
+		unpacked, err = new(ResolveShardRequest).UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.True(t, unpacked.IsEmpty())
+	})
+
+	t.Run("ShardId", func(t *testing.T) {
+		t.Parallel()
+
+		var response ShardIdResponse
+		require.NoError(t, response.PackProtoMessage(2, nil))
+		shardId, err := response.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, types.ShardId(2), shardId)
+
+		require.NoError(t, response.PackProtoMessage(0,
+			rawapitypes.NewInvalidArgumentError(errors.New("address is empty"))))
+		_, err = response.UnpackProtoMessage()
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+	})
+
+	t.Run("Resolutions", func(t *testing.T) {
+		t.Parallel()
+
+		resolutions := []*rawapitypes.ShardResolution{
+			{Address: types.ShardAndHexToAddress(1, "0x11"), ShardId: 1},
+			{Error: rawapitypes.NewInvalidArgumentError(errors.New("address is empty"))},
+		}
+
+		var response ShardResolutionsResponse
+		require.NoError(t, response.PackProtoMessage(resolutions, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked ShardResolutionsResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedResolutions, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		require.Len(t, unpackedResolutions, 2)
+		assert.Equal(t, resolutions[0], unpackedResolutions[0])
+		require.ErrorIs(t, unpackedResolutions[1].Error, rawapitypes.ErrInvalidArgument)
+		assert.EqualError(t, unpackedResolutions[1].Error, "address is empty")
+	})
+}
+
+func TestSendTransactionResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	hash := common.HexToHash("0x0001000000000000000000000000000000000000000000000000000000000abc")
+	results := []*rawapitypes.SendTransactionResult{
+		{Hash: hash, ShardId: 1},
+		{Hash: hash, ShardId: 1, DiscardReason: txnpool.NotReplaced},
+		{
+			Hash:    hash,
+			ShardId: 1,
+			Verdict: rawapitypes.TransactionVerdict{
+				FailedCheck: rawapitypes.TransactionCheckSignature,
+				Error:       types.NewError(types.ErrorExternalVerificationFailed),
+			},
+			DiscardReason: txnpool.Unverified,
+		},
+		{
+			Hash:    hash,
+			ShardId: 1,
+			Verdict: rawapitypes.TransactionVerdict{
+				FailedCheck: rawapitypes.TransactionCheckSeqno,
+				Error:       types.NewVerboseError(types.ErrorSeqnoGap, "account 2 > transaction 1"),
+			},
+			DiscardReason: txnpool.SeqnoTooLow,
+		},
+	}
+
+	for _, result := range results {
+		var response SendTransactionResponse
+		require.NoError(t, response.PackProtoMessage(result, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+		var decoded SendTransactionResponse
+		require.NoError(t, proto.Unmarshal(data, &decoded))
+
+		unpacked, err := decoded.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, result, unpacked)
+	}
+
+	var response SendTransactionResponse
+	require.NoError(t, response.PackProtoMessage(nil, errors.New("pool is not available")))
+	_, err := response.UnpackProtoMessage()
+	require.ErrorContains(t, err, "pool is not available")
+}
+
+func TestSendBundleResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	results := []*rawapitypes.SendTransactionResult{
+		{
+			Hash:    common.HexToHash("0x0001000000000000000000000000000000000000000000000000000000000abc"),
+			ShardId: 1,
+			Verdict: rawapitypes.TransactionVerdict{
+				FailedCheck: rawapitypes.TransactionCheckBalance,
+				Error:       types.NewError(types.ErrorInsufficientBalance),
+			},
+			DiscardReason: txnpool.Unverified,
+		},
+		{
+			Hash:          common.HexToHash("0x0001000000000000000000000000000000000000000000000000000000000def"),
+			ShardId:       1,
+			DiscardReason: txnpool.BundleRejected,
+		},
+	}
+
+	var response SendBundleResponse
+	require.NoError(t, response.PackProtoMessage(results, nil))
+
+	data, err := proto.Marshal(&response)
+	require.NoError(t, err)
+	var decoded SendBundleResponse
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+
+	unpacked, err := decoded.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, results, unpacked)
+
+	require.NoError(t, response.PackProtoMessage(nil, errors.New("invalid bundle")))
+	_, err = response.UnpackProtoMessage()
+	require.ErrorContains(t, err, "invalid bundle")
+}
+
+func TestTransactionEventResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	events := []*rawapitypes.TransactionEvent{
+		{
+			Type: rawapitypes.TransactionEventSubmitted,
+			Result: &rawapitypes.SendTransactionResult{
+				Hash:    common.HexToHash("0x0001000000000000000000000000000000000000000000000000000000000abc"),
+				ShardId: 1,
+			},
+		},
+		{
+			Type:         rawapitypes.TransactionEventIncluded,
+			BlockNumber:  10,
+			BlockHash:    common.HexToHash("0x0001000000000000000000000000000000000000000000000000000000000def"),
+			ErrorMessage: "ExecutionReverted",
+		},
+		{Type: rawapitypes.TransactionEventDropped},
+	}
+
+	for _, event := range events {
+		var response TransactionEventResponse
+		require.NoError(t, response.PackProtoMessage(event, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+		var decoded TransactionEventResponse
+		require.NoError(t, proto.Unmarshal(data, &decoded))
+
+		unpacked, err := decoded.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, event, unpacked)
+	}
+}
+
+func TestSendTransactionRequest_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	var request SendTransactionRequest
+	require.NoError(t, request.PackProtoMessage([]byte{1, 2, 3}, txnpool.NoReplacement, "retry-key"))
+
+	data, err := proto.Marshal(&request)
+	require.NoError(t, err)
+
+	// The servers of the earlier versions read the same request without the key.
+	var resend ResendTransactionRequest
+	require.NoError(t, proto.Unmarshal(data, &resend))
+	transaction, replacement, err := resend.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, transaction)
+	assert.Equal(t, txnpool.NoReplacement, replacement)
+
+	var decoded SendTransactionRequest
+	require.NoError(t, proto.Unmarshal(data, &decoded))
+	transaction, replacement, key, err := decoded.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, transaction)
+	assert.Equal(t, txnpool.NoReplacement, replacement)
+	assert.Equal(t, "retry-key", key)
+
+	// The requests without the policy keep the replacement by a bumped fee.
+	require.NoError(t, proto.Unmarshal([]byte{}, &decoded))
+	_, replacement, _, err = decoded.UnpackProtoMessage()
+	require.NoError(t, err)
+	assert.Equal(t, txnpool.ReplaceIfFeeBumped, replacement)
+}
+
+func TestFaucet_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Request", func(t *testing.T) {
+		t.Parallel()
+
+		address := types.ShardAndHexToAddress(1, "0x1234")
+		var request FaucetRequest
+		require.NoError(t, request.PackProtoMessage(address, types.NewValueFromUint64(100)))
+
+		data, err := proto.Marshal(&request)
+		require.NoError(t, err)
+
+		var unpacked FaucetRequest
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedAddress, amount, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, address, unpackedAddress)
+		assert.Equal(t, types.NewValueFromUint64(100), amount)
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		t.Parallel()
+
+		limits := &rawapitypes.FaucetLimits{
+			Faucet:         types.FaucetAddress,
+			MaxAmount:      types.NewValueFromUint64(1000),
+			RequesterQuota: types.NewValueFromUint64(5000),
+			Window:         time.Hour,
+		}
+
+		var response FaucetLimitsResponse
+		require.NoError(t, response.PackProtoMessage(limits, nil))
+
+		data, err := proto.Marshal(&response)
+		require.NoError(t, err)
+
+		var unpacked FaucetLimitsResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+
+		unpackedLimits, err := unpacked.UnpackProtoMessage()
+		require.NoError(t, err)
+		assert.Equal(t, limits, unpackedLimits)
+	})
+}
+
+func TestRevertToSnapshotResponse_PackUnpack(t *testing.T) {
+	t.Parallel()
+
+	packUnpack := func(reverted bool, err error) (bool, error) {
+		t.Helper()
+
+		var response RevertToSnapshotResponse
+		require.NoError(t, response.PackProtoMessage(reverted, err))
+
+		data, marshalErr := proto.Marshal(&response)
+		require.NoError(t, marshalErr)
+
+		var unpacked RevertToSnapshotResponse
+		require.NoError(t, proto.Unmarshal(data, &unpacked))
+		return unpacked.UnpackProtoMessage()
+	}
+
+	t.Run("Reverted", func(t *testing.T) {
+		t.Parallel()
+
+		reverted, err := packUnpack(true, nil)
+		require.NoError(t, err)
+		assert.True(t, reverted)
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+
+		reverted, err := packUnpack(false, nil)
+		require.NoError(t, err)
+		assert.False(t, reverted)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := packUnpack(false, rawapitypes.NewInvalidArgumentError(errors.New("no chains")))
+		require.ErrorIs(t, err, rawapitypes.ErrInvalidArgument)
+	})
+}
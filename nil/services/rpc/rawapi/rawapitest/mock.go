@@ -0,0 +1,69 @@
+//go:build test
+
+package rawapitest
+
+//go:generate go run ../internal/dispatchgen -mock -src ../internal -import github.com/NilFoundation/nil/nil/services/rpc/rawapi/internal -pkg rawapitest -tag test -out mock_generated.go ShardApiRo:NetworkTransportProtocolRo ShardApiRw:NetworkTransportProtocolRw NodeApi
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrNotMocked is returned by the methods of the mocks whose functions are not set.
+var ErrNotMocked = errors.New("method is not mocked")
+
+func newNotMockedError(apiName string, method string) error {
+	return fmt.Errorf("%w: %s.%s", ErrNotMocked, apiName, method)
+}
+
+// Call is a call of a method of a mock, the context is not recorded.
+type Call struct {
+	Method string
+	Args   []any
+}
+
+// Recorder records the calls of the methods of the mock embedding it. The functions of the mocks,
+// e.g., ShardApiRoMock.GetBalanceFunc, are to be set before the mock is used, they are not synchronized.
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *Recorder) Record(method string, args ...any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns the recorded calls in the order they were made.
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.calls)
+}
+
+// CallsOf returns the recorded calls of the method.
+func (r *Recorder) CallsOf(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	calls := make([]Call, 0)
+	for _, call := range r.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset forgets the recorded calls.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = nil
+}
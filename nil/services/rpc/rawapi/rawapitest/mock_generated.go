@@ -0,0 +1,1310 @@
+// Code generated by dispatchgen. DO NOT EDIT.
+
+//go:build test
+
+package rawapitest
+
+import (
+	"context"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/common/sszx"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/internal"
+	rawapitypes "github.com/NilFoundation/nil/nil/services/rpc/rawapi/types"
+	rpctypes "github.com/NilFoundation/nil/nil/services/rpc/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+)
+
+// ShardApiRoMock is a mock of internal.ShardApiRo.
+type ShardApiRoMock struct {
+	Recorder
+
+	GetBlockHeaderFunc             func(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error)
+	GetFullBlockDataFunc           func(ctx context.Context, blockReference rawapitypes.BlockReference) (*types.RawBlockWithExtractedData, error)
+	GetBlockTransactionCountFunc   func(ctx context.Context, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetBlockRangeFunc              func(ctx context.Context, from types.BlockNumber, count uint64, fullBlocks bool) ([]*types.RawBlockWithExtractedData, error)
+	GetShardStatsFunc              func(ctx context.Context, from types.BlockNumber, count uint64) (*rawapitypes.ShardStats, error)
+	GetBlockFinalitySignaturesFunc func(ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.FinalitySignatures, error)
+	GetValidatorsFunc              func(ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.ValidatorSet, error)
+	GetValidatorInfoFunc           func(ctx context.Context, publicKey []byte) (*rawapitypes.ValidatorInfo, error)
+	GetEpochInfoFunc               func(ctx context.Context) (*rawapitypes.EpochInfo, error)
+	SubscribeNewHeadsFunc          func(ctx context.Context) (<-chan sszx.SSZEncodedData, error)
+	GetInTransactionFunc           func(ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
+	GetInTransactionByIndexFunc    func(ctx context.Context, blockReference rawapitypes.BlockReference, index types.TransactionIndex) (*rawapitypes.TransactionInfo, error)
+	GetReceiptFunc                 func(ctx context.Context, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetBounceInfoFunc              func(ctx context.Context, hash common.Hash) (*rawapitypes.BounceInfo, error)
+	GetBlockReceiptsFunc           func(ctx context.Context, blockReference rawapitypes.BlockReference) ([]*rawapitypes.ReceiptInfo, error)
+	GetOutTransactionFunc          func(ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
+	GetOutTransactionsFunc         func(ctx context.Context, blockReference rawapitypes.BlockReference) ([]*rawapitypes.TransactionInfo, error)
+	GetLogsFunc                    func(ctx context.Context, filter rawapitypes.LogFilter) ([]*rawapitypes.LogInfo, error)
+	SubscribeLogsFunc              func(ctx context.Context, filter rawapitypes.LogFilter) (<-chan *rawapitypes.LogInfo, error)
+	GetBalanceFunc                 func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
+	GetBalancesFunc                func(ctx context.Context, addresses []types.Address, blockReference rawapitypes.BlockReference) ([]types.Value, error)
+	GetCodeFunc                    func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error)
+	GetStorageAtFunc               func(ctx context.Context, address types.Address, key common.Hash, blockReference rawapitypes.BlockReference) (types.Uint256, error)
+	GetStorageRangeFunc            func(ctx context.Context, address types.Address, start common.Hash, limit uint64, blockReference rawapitypes.BlockReference) (*rawapitypes.StorageRange, error)
+	GetTokensFunc                  func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference, request rawapitypes.TokensRequest) (*rawapitypes.TokensPage, error)
+	GetTokenBalanceFunc            func(ctx context.Context, address types.Address, tokenId types.TokenId, blockReference rawapitypes.BlockReference) (types.Value, error)
+	GetTokenInfoFunc               func(ctx context.Context, tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference) (*rawapitypes.TokenInfo, error)
+	ListTokensCreatedByFunc        func(ctx context.Context, address types.Address, mainBlockReference rawapitypes.BlockReference) ([]*rawapitypes.TokenInfo, error)
+	GetContractFunc                func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.SmartContract, error)
+	GetAccountMetaFunc             func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.AccountMeta, error)
+	GetProofFunc                   func(ctx context.Context, address types.Address, storageKeys []common.Hash, blockReference rawapitypes.BlockReference) (*rawapitypes.ContractProof, error)
+	CallFunc                       func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rpctypes.CallResWithGasPrice, error)
+	EstimateFeeFunc                func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.FeeEstimation, error)
+	CreateAccessListFunc           func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.AccessListResult, error)
+	SimulateBundleFunc             func(ctx context.Context, calls []rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) ([]*rawapitypes.BundleCallResult, error)
+	PrepareDeployFunc              func(ctx context.Context, request rawapitypes.DeployRequest) (*rawapitypes.DeployPreparation, error)
+	GasPriceFunc                   func(ctx context.Context) (types.Value, error)
+	GetShardIdListFunc             func(ctx context.Context) ([]types.ShardId, error)
+	GetNumShardsFunc               func(ctx context.Context) (uint64, error)
+	ResolveShardFunc               func(ctx context.Context, address types.Address) (types.ShardId, error)
+	ResolveShardsFunc              func(ctx context.Context, addresses []types.Address) ([]*rawapitypes.ShardResolution, error)
+	GetChainConfigFunc             func(ctx context.Context) (*rawapitypes.ChainConfig, error)
+	GetSyncStatusFunc              func(ctx context.Context) (*rawapitypes.SyncStatus, error)
+	ClientVersionFunc              func(ctx context.Context) (string, error)
+}
+
+var _ internal.ShardApiRo = (*ShardApiRoMock)(nil)
+
+func (m *ShardApiRoMock) GetBlockHeader(ctx context.Context, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	m.Record("GetBlockHeader", blockReference)
+	if f := m.GetBlockHeaderFunc; f != nil {
+		return f(ctx, blockReference)
+	}
+	var r0 sszx.SSZEncodedData
+	return r0, newNotMockedError("ShardApiRo", "GetBlockHeader")
+}
+
+func (m *ShardApiRoMock) GetFullBlockData(ctx context.Context, blockReference rawapitypes.BlockReference) (*types.RawBlockWithExtractedData, error) {
+	m.Record("GetFullBlockData", blockReference)
+	if f := m.GetFullBlockDataFunc; f != nil {
+		return f(ctx, blockReference)
+	}
+	var r0 *types.RawBlockWithExtractedData
+	return r0, newNotMockedError("ShardApiRo", "GetFullBlockData")
+}
+
+func (m *ShardApiRoMock) GetBlockTransactionCount(ctx context.Context, blockReference rawapitypes.BlockReference) (uint64, error) {
+	m.Record("GetBlockTransactionCount", blockReference)
+	if f := m.GetBlockTransactionCountFunc; f != nil {
+		return f(ctx, blockReference)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("ShardApiRo", "GetBlockTransactionCount")
+}
+
+func (m *ShardApiRoMock) GetBlockRange(ctx context.Context, from types.BlockNumber, count uint64, fullBlocks bool) ([]*types.RawBlockWithExtractedData, error) {
+	m.Record("GetBlockRange", from, count, fullBlocks)
+	if f := m.GetBlockRangeFunc; f != nil {
+		return f(ctx, from, count, fullBlocks)
+	}
+	var r0 []*types.RawBlockWithExtractedData
+	return r0, newNotMockedError("ShardApiRo", "GetBlockRange")
+}
+
+func (m *ShardApiRoMock) GetShardStats(ctx context.Context, from types.BlockNumber, count uint64) (*rawapitypes.ShardStats, error) {
+	m.Record("GetShardStats", from, count)
+	if f := m.GetShardStatsFunc; f != nil {
+		return f(ctx, from, count)
+	}
+	var r0 *rawapitypes.ShardStats
+	return r0, newNotMockedError("ShardApiRo", "GetShardStats")
+}
+
+func (m *ShardApiRoMock) GetBlockFinalitySignatures(ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.FinalitySignatures, error) {
+	m.Record("GetBlockFinalitySignatures", blockReference)
+	if f := m.GetBlockFinalitySignaturesFunc; f != nil {
+		return f(ctx, blockReference)
+	}
+	var r0 *rawapitypes.FinalitySignatures
+	return r0, newNotMockedError("ShardApiRo", "GetBlockFinalitySignatures")
+}
+
+func (m *ShardApiRoMock) GetValidators(ctx context.Context, blockReference rawapitypes.BlockReference) (*rawapitypes.ValidatorSet, error) {
+	m.Record("GetValidators", blockReference)
+	if f := m.GetValidatorsFunc; f != nil {
+		return f(ctx, blockReference)
+	}
+	var r0 *rawapitypes.ValidatorSet
+	return r0, newNotMockedError("ShardApiRo", "GetValidators")
+}
+
+func (m *ShardApiRoMock) GetValidatorInfo(ctx context.Context, publicKey []byte) (*rawapitypes.ValidatorInfo, error) {
+	m.Record("GetValidatorInfo", publicKey)
+	if f := m.GetValidatorInfoFunc; f != nil {
+		return f(ctx, publicKey)
+	}
+	var r0 *rawapitypes.ValidatorInfo
+	return r0, newNotMockedError("ShardApiRo", "GetValidatorInfo")
+}
+
+func (m *ShardApiRoMock) GetEpochInfo(ctx context.Context) (*rawapitypes.EpochInfo, error) {
+	m.Record("GetEpochInfo")
+	if f := m.GetEpochInfoFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 *rawapitypes.EpochInfo
+	return r0, newNotMockedError("ShardApiRo", "GetEpochInfo")
+}
+
+func (m *ShardApiRoMock) SubscribeNewHeads(ctx context.Context) (<-chan sszx.SSZEncodedData, error) {
+	m.Record("SubscribeNewHeads")
+	if f := m.SubscribeNewHeadsFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 <-chan sszx.SSZEncodedData
+	return r0, newNotMockedError("ShardApiRo", "SubscribeNewHeads")
+}
+
+func (m *ShardApiRoMock) GetInTransaction(ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error) {
+	m.Record("GetInTransaction", transactionRequest)
+	if f := m.GetInTransactionFunc; f != nil {
+		return f(ctx, transactionRequest)
+	}
+	var r0 *rawapitypes.TransactionInfo
+	return r0, newNotMockedError("ShardApiRo", "GetInTransaction")
+}
+
+func (m *ShardApiRoMock) GetInTransactionByIndex(ctx context.Context, blockReference rawapitypes.BlockReference, index types.TransactionIndex) (*rawapitypes.TransactionInfo, error) {
+	m.Record("GetInTransactionByIndex", blockReference, index)
+	if f := m.GetInTransactionByIndexFunc; f != nil {
+		return f(ctx, blockReference, index)
+	}
+	var r0 *rawapitypes.TransactionInfo
+	return r0, newNotMockedError("ShardApiRo", "GetInTransactionByIndex")
+}
+
+func (m *ShardApiRoMock) GetReceipt(ctx context.Context, hash common.Hash) (*rawapitypes.ReceiptInfo, error) {
+	m.Record("GetReceipt", hash)
+	if f := m.GetReceiptFunc; f != nil {
+		return f(ctx, hash)
+	}
+	var r0 *rawapitypes.ReceiptInfo
+	return r0, newNotMockedError("ShardApiRo", "GetReceipt")
+}
+
+func (m *ShardApiRoMock) GetBounceInfo(ctx context.Context, hash common.Hash) (*rawapitypes.BounceInfo, error) {
+	m.Record("GetBounceInfo", hash)
+	if f := m.GetBounceInfoFunc; f != nil {
+		return f(ctx, hash)
+	}
+	var r0 *rawapitypes.BounceInfo
+	return r0, newNotMockedError("ShardApiRo", "GetBounceInfo")
+}
+
+func (m *ShardApiRoMock) GetBlockReceipts(ctx context.Context, blockReference rawapitypes.BlockReference) ([]*rawapitypes.ReceiptInfo, error) {
+	m.Record("GetBlockReceipts", blockReference)
+	if f := m.GetBlockReceiptsFunc; f != nil {
+		return f(ctx, blockReference)
+	}
+	var r0 []*rawapitypes.ReceiptInfo
+	return r0, newNotMockedError("ShardApiRo", "GetBlockReceipts")
+}
+
+func (m *ShardApiRoMock) GetOutTransaction(ctx context.Context, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error) {
+	m.Record("GetOutTransaction", transactionRequest)
+	if f := m.GetOutTransactionFunc; f != nil {
+		return f(ctx, transactionRequest)
+	}
+	var r0 *rawapitypes.TransactionInfo
+	return r0, newNotMockedError("ShardApiRo", "GetOutTransaction")
+}
+
+func (m *ShardApiRoMock) GetOutTransactions(ctx context.Context, blockReference rawapitypes.BlockReference) ([]*rawapitypes.TransactionInfo, error) {
+	m.Record("GetOutTransactions", blockReference)
+	if f := m.GetOutTransactionsFunc; f != nil {
+		return f(ctx, blockReference)
+	}
+	var r0 []*rawapitypes.TransactionInfo
+	return r0, newNotMockedError("ShardApiRo", "GetOutTransactions")
+}
+
+func (m *ShardApiRoMock) GetLogs(ctx context.Context, filter rawapitypes.LogFilter) ([]*rawapitypes.LogInfo, error) {
+	m.Record("GetLogs", filter)
+	if f := m.GetLogsFunc; f != nil {
+		return f(ctx, filter)
+	}
+	var r0 []*rawapitypes.LogInfo
+	return r0, newNotMockedError("ShardApiRo", "GetLogs")
+}
+
+func (m *ShardApiRoMock) SubscribeLogs(ctx context.Context, filter rawapitypes.LogFilter) (<-chan *rawapitypes.LogInfo, error) {
+	m.Record("SubscribeLogs", filter)
+	if f := m.SubscribeLogsFunc; f != nil {
+		return f(ctx, filter)
+	}
+	var r0 <-chan *rawapitypes.LogInfo
+	return r0, newNotMockedError("ShardApiRo", "SubscribeLogs")
+}
+
+func (m *ShardApiRoMock) GetBalance(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error) {
+	m.Record("GetBalance", address, blockReference)
+	if f := m.GetBalanceFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 types.Value
+	return r0, newNotMockedError("ShardApiRo", "GetBalance")
+}
+
+func (m *ShardApiRoMock) GetBalances(ctx context.Context, addresses []types.Address, blockReference rawapitypes.BlockReference) ([]types.Value, error) {
+	m.Record("GetBalances", addresses, blockReference)
+	if f := m.GetBalancesFunc; f != nil {
+		return f(ctx, addresses, blockReference)
+	}
+	var r0 []types.Value
+	return r0, newNotMockedError("ShardApiRo", "GetBalances")
+}
+
+func (m *ShardApiRoMock) GetCode(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error) {
+	m.Record("GetCode", address, blockReference)
+	if f := m.GetCodeFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 types.Code
+	return r0, newNotMockedError("ShardApiRo", "GetCode")
+}
+
+func (m *ShardApiRoMock) GetStorageAt(ctx context.Context, address types.Address, key common.Hash, blockReference rawapitypes.BlockReference) (types.Uint256, error) {
+	m.Record("GetStorageAt", address, key, blockReference)
+	if f := m.GetStorageAtFunc; f != nil {
+		return f(ctx, address, key, blockReference)
+	}
+	var r0 types.Uint256
+	return r0, newNotMockedError("ShardApiRo", "GetStorageAt")
+}
+
+func (m *ShardApiRoMock) GetStorageRange(ctx context.Context, address types.Address, start common.Hash, limit uint64, blockReference rawapitypes.BlockReference) (*rawapitypes.StorageRange, error) {
+	m.Record("GetStorageRange", address, start, limit, blockReference)
+	if f := m.GetStorageRangeFunc; f != nil {
+		return f(ctx, address, start, limit, blockReference)
+	}
+	var r0 *rawapitypes.StorageRange
+	return r0, newNotMockedError("ShardApiRo", "GetStorageRange")
+}
+
+func (m *ShardApiRoMock) GetTokens(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference, request rawapitypes.TokensRequest) (*rawapitypes.TokensPage, error) {
+	m.Record("GetTokens", address, blockReference, request)
+	if f := m.GetTokensFunc; f != nil {
+		return f(ctx, address, blockReference, request)
+	}
+	var r0 *rawapitypes.TokensPage
+	return r0, newNotMockedError("ShardApiRo", "GetTokens")
+}
+
+func (m *ShardApiRoMock) GetTokenBalance(ctx context.Context, address types.Address, tokenId types.TokenId, blockReference rawapitypes.BlockReference) (types.Value, error) {
+	m.Record("GetTokenBalance", address, tokenId, blockReference)
+	if f := m.GetTokenBalanceFunc; f != nil {
+		return f(ctx, address, tokenId, blockReference)
+	}
+	var r0 types.Value
+	return r0, newNotMockedError("ShardApiRo", "GetTokenBalance")
+}
+
+func (m *ShardApiRoMock) GetTokenInfo(ctx context.Context, tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference) (*rawapitypes.TokenInfo, error) {
+	m.Record("GetTokenInfo", tokenId, mainBlockReference)
+	if f := m.GetTokenInfoFunc; f != nil {
+		return f(ctx, tokenId, mainBlockReference)
+	}
+	var r0 *rawapitypes.TokenInfo
+	return r0, newNotMockedError("ShardApiRo", "GetTokenInfo")
+}
+
+func (m *ShardApiRoMock) ListTokensCreatedBy(ctx context.Context, address types.Address, mainBlockReference rawapitypes.BlockReference) ([]*rawapitypes.TokenInfo, error) {
+	m.Record("ListTokensCreatedBy", address, mainBlockReference)
+	if f := m.ListTokensCreatedByFunc; f != nil {
+		return f(ctx, address, mainBlockReference)
+	}
+	var r0 []*rawapitypes.TokenInfo
+	return r0, newNotMockedError("ShardApiRo", "ListTokensCreatedBy")
+}
+
+func (m *ShardApiRoMock) GetContract(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.SmartContract, error) {
+	m.Record("GetContract", address, blockReference)
+	if f := m.GetContractFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 *rawapitypes.SmartContract
+	return r0, newNotMockedError("ShardApiRo", "GetContract")
+}
+
+func (m *ShardApiRoMock) GetAccountMeta(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.AccountMeta, error) {
+	m.Record("GetAccountMeta", address, blockReference)
+	if f := m.GetAccountMetaFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 *rawapitypes.AccountMeta
+	return r0, newNotMockedError("ShardApiRo", "GetAccountMeta")
+}
+
+func (m *ShardApiRoMock) GetProof(ctx context.Context, address types.Address, storageKeys []common.Hash, blockReference rawapitypes.BlockReference) (*rawapitypes.ContractProof, error) {
+	m.Record("GetProof", address, storageKeys, blockReference)
+	if f := m.GetProofFunc; f != nil {
+		return f(ctx, address, storageKeys, blockReference)
+	}
+	var r0 *rawapitypes.ContractProof
+	return r0, newNotMockedError("ShardApiRo", "GetProof")
+}
+
+func (m *ShardApiRoMock) Call(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rpctypes.CallResWithGasPrice, error) {
+	m.Record("Call", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.CallFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rpctypes.CallResWithGasPrice
+	return r0, newNotMockedError("ShardApiRo", "Call")
+}
+
+func (m *ShardApiRoMock) EstimateFee(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.FeeEstimation, error) {
+	m.Record("EstimateFee", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.EstimateFeeFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rawapitypes.FeeEstimation
+	return r0, newNotMockedError("ShardApiRo", "EstimateFee")
+}
+
+func (m *ShardApiRoMock) CreateAccessList(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.AccessListResult, error) {
+	m.Record("CreateAccessList", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.CreateAccessListFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rawapitypes.AccessListResult
+	return r0, newNotMockedError("ShardApiRo", "CreateAccessList")
+}
+
+func (m *ShardApiRoMock) SimulateBundle(ctx context.Context, calls []rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) ([]*rawapitypes.BundleCallResult, error) {
+	m.Record("SimulateBundle", calls, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.SimulateBundleFunc; f != nil {
+		return f(ctx, calls, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 []*rawapitypes.BundleCallResult
+	return r0, newNotMockedError("ShardApiRo", "SimulateBundle")
+}
+
+func (m *ShardApiRoMock) PrepareDeploy(ctx context.Context, request rawapitypes.DeployRequest) (*rawapitypes.DeployPreparation, error) {
+	m.Record("PrepareDeploy", request)
+	if f := m.PrepareDeployFunc; f != nil {
+		return f(ctx, request)
+	}
+	var r0 *rawapitypes.DeployPreparation
+	return r0, newNotMockedError("ShardApiRo", "PrepareDeploy")
+}
+
+func (m *ShardApiRoMock) GasPrice(ctx context.Context) (types.Value, error) {
+	m.Record("GasPrice")
+	if f := m.GasPriceFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 types.Value
+	return r0, newNotMockedError("ShardApiRo", "GasPrice")
+}
+
+func (m *ShardApiRoMock) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
+	m.Record("GetShardIdList")
+	if f := m.GetShardIdListFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 []types.ShardId
+	return r0, newNotMockedError("ShardApiRo", "GetShardIdList")
+}
+
+func (m *ShardApiRoMock) GetNumShards(ctx context.Context) (uint64, error) {
+	m.Record("GetNumShards")
+	if f := m.GetNumShardsFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("ShardApiRo", "GetNumShards")
+}
+
+func (m *ShardApiRoMock) ResolveShard(ctx context.Context, address types.Address) (types.ShardId, error) {
+	m.Record("ResolveShard", address)
+	if f := m.ResolveShardFunc; f != nil {
+		return f(ctx, address)
+	}
+	var r0 types.ShardId
+	return r0, newNotMockedError("ShardApiRo", "ResolveShard")
+}
+
+func (m *ShardApiRoMock) ResolveShards(ctx context.Context, addresses []types.Address) ([]*rawapitypes.ShardResolution, error) {
+	m.Record("ResolveShards", addresses)
+	if f := m.ResolveShardsFunc; f != nil {
+		return f(ctx, addresses)
+	}
+	var r0 []*rawapitypes.ShardResolution
+	return r0, newNotMockedError("ShardApiRo", "ResolveShards")
+}
+
+func (m *ShardApiRoMock) GetChainConfig(ctx context.Context) (*rawapitypes.ChainConfig, error) {
+	m.Record("GetChainConfig")
+	if f := m.GetChainConfigFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 *rawapitypes.ChainConfig
+	return r0, newNotMockedError("ShardApiRo", "GetChainConfig")
+}
+
+func (m *ShardApiRoMock) GetSyncStatus(ctx context.Context) (*rawapitypes.SyncStatus, error) {
+	m.Record("GetSyncStatus")
+	if f := m.GetSyncStatusFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 *rawapitypes.SyncStatus
+	return r0, newNotMockedError("ShardApiRo", "GetSyncStatus")
+}
+
+func (m *ShardApiRoMock) ClientVersion(ctx context.Context) (string, error) {
+	m.Record("ClientVersion")
+	if f := m.ClientVersionFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 string
+	return r0, newNotMockedError("ShardApiRo", "ClientVersion")
+}
+
+// ShardApiRwMock is a mock of internal.ShardApiRw.
+type ShardApiRwMock struct {
+	Recorder
+
+	SendTransactionFunc              func(ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (*rawapitypes.SendTransactionResult, error)
+	SendTransactionBundleFunc        func(ctx context.Context, transactions [][]byte, sameBlock bool) ([]*rawapitypes.SendTransactionResult, error)
+	SendTransactionAndWatchFunc      func(ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (<-chan *rawapitypes.TransactionEvent, error)
+	GetTransactionCountFunc          func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetNextValidSeqnoFunc            func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetTransactionStatusFunc         func(ctx context.Context, hash common.Hash) (*rawapitypes.TransactionStatusInfo, error)
+	ResendTransactionFunc            func(ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy) (txnpool.DiscardReason, error)
+	GetTxpoolStatusFunc              func(ctx context.Context) (uint64, error)
+	GetTxpoolContentFunc             func(ctx context.Context) ([]*types.Transaction, error)
+	SubscribePendingTransactionsFunc func(ctx context.Context, fullTransactions bool) (<-chan *rawapitypes.PendingTransaction, error)
+}
+
+var _ internal.ShardApiRw = (*ShardApiRwMock)(nil)
+
+func (m *ShardApiRwMock) SendTransaction(ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (*rawapitypes.SendTransactionResult, error) {
+	m.Record("SendTransaction", transaction, replacement, idempotencyKey)
+	if f := m.SendTransactionFunc; f != nil {
+		return f(ctx, transaction, replacement, idempotencyKey)
+	}
+	var r0 *rawapitypes.SendTransactionResult
+	return r0, newNotMockedError("ShardApiRw", "SendTransaction")
+}
+
+func (m *ShardApiRwMock) SendTransactionBundle(ctx context.Context, transactions [][]byte, sameBlock bool) ([]*rawapitypes.SendTransactionResult, error) {
+	m.Record("SendTransactionBundle", transactions, sameBlock)
+	if f := m.SendTransactionBundleFunc; f != nil {
+		return f(ctx, transactions, sameBlock)
+	}
+	var r0 []*rawapitypes.SendTransactionResult
+	return r0, newNotMockedError("ShardApiRw", "SendTransactionBundle")
+}
+
+func (m *ShardApiRwMock) SendTransactionAndWatch(ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (<-chan *rawapitypes.TransactionEvent, error) {
+	m.Record("SendTransactionAndWatch", transaction, replacement, idempotencyKey)
+	if f := m.SendTransactionAndWatchFunc; f != nil {
+		return f(ctx, transaction, replacement, idempotencyKey)
+	}
+	var r0 <-chan *rawapitypes.TransactionEvent
+	return r0, newNotMockedError("ShardApiRw", "SendTransactionAndWatch")
+}
+
+func (m *ShardApiRwMock) GetTransactionCount(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error) {
+	m.Record("GetTransactionCount", address, blockReference)
+	if f := m.GetTransactionCountFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("ShardApiRw", "GetTransactionCount")
+}
+
+func (m *ShardApiRwMock) GetNextValidSeqno(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error) {
+	m.Record("GetNextValidSeqno", address, blockReference)
+	if f := m.GetNextValidSeqnoFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("ShardApiRw", "GetNextValidSeqno")
+}
+
+func (m *ShardApiRwMock) GetTransactionStatus(ctx context.Context, hash common.Hash) (*rawapitypes.TransactionStatusInfo, error) {
+	m.Record("GetTransactionStatus", hash)
+	if f := m.GetTransactionStatusFunc; f != nil {
+		return f(ctx, hash)
+	}
+	var r0 *rawapitypes.TransactionStatusInfo
+	return r0, newNotMockedError("ShardApiRw", "GetTransactionStatus")
+}
+
+func (m *ShardApiRwMock) ResendTransaction(ctx context.Context, transaction []byte, replacement txnpool.ReplacementPolicy) (txnpool.DiscardReason, error) {
+	m.Record("ResendTransaction", transaction, replacement)
+	if f := m.ResendTransactionFunc; f != nil {
+		return f(ctx, transaction, replacement)
+	}
+	var r0 txnpool.DiscardReason
+	return r0, newNotMockedError("ShardApiRw", "ResendTransaction")
+}
+
+func (m *ShardApiRwMock) GetTxpoolStatus(ctx context.Context) (uint64, error) {
+	m.Record("GetTxpoolStatus")
+	if f := m.GetTxpoolStatusFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("ShardApiRw", "GetTxpoolStatus")
+}
+
+func (m *ShardApiRwMock) GetTxpoolContent(ctx context.Context) ([]*types.Transaction, error) {
+	m.Record("GetTxpoolContent")
+	if f := m.GetTxpoolContentFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 []*types.Transaction
+	return r0, newNotMockedError("ShardApiRw", "GetTxpoolContent")
+}
+
+func (m *ShardApiRwMock) SubscribePendingTransactions(ctx context.Context, fullTransactions bool) (<-chan *rawapitypes.PendingTransaction, error) {
+	m.Record("SubscribePendingTransactions", fullTransactions)
+	if f := m.SubscribePendingTransactionsFunc; f != nil {
+		return f(ctx, fullTransactions)
+	}
+	var r0 <-chan *rawapitypes.PendingTransaction
+	return r0, newNotMockedError("ShardApiRw", "SubscribePendingTransactions")
+}
+
+// NodeApiMock is a mock of internal.NodeApi.
+type NodeApiMock struct {
+	Recorder
+
+	GetBlockHeaderFunc               func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error)
+	GetFullBlockDataFunc             func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*types.RawBlockWithExtractedData, error)
+	GetBlockTransactionCountFunc     func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetBlockRangeFunc                func(ctx context.Context, shardId types.ShardId, from types.BlockNumber, count uint64, fullBlocks bool) ([]*types.RawBlockWithExtractedData, error)
+	GetShardStatsFunc                func(ctx context.Context, shardId types.ShardId, from types.BlockNumber, count uint64) (*rawapitypes.ShardStats, error)
+	GetBlockFinalitySignaturesFunc   func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*rawapitypes.FinalitySignatures, error)
+	GetValidatorsFunc                func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*rawapitypes.ValidatorSet, error)
+	GetValidatorInfoFunc             func(ctx context.Context, shardId types.ShardId, publicKey []byte) (*rawapitypes.ValidatorInfo, error)
+	GetEpochInfoFunc                 func(ctx context.Context, shardId types.ShardId) (*rawapitypes.EpochInfo, error)
+	SubscribeNewHeadsFunc            func(ctx context.Context, shardId types.ShardId) (<-chan sszx.SSZEncodedData, error)
+	GetInTransactionFunc             func(ctx context.Context, shardId types.ShardId, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
+	GetReceiptFunc                   func(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ReceiptInfo, error)
+	GetBounceInfoFunc                func(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.BounceInfo, error)
+	GetBlockReceiptsFunc             func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) ([]*rawapitypes.ReceiptInfo, error)
+	GetOutTransactionFunc            func(ctx context.Context, shardId types.ShardId, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error)
+	GetOutTransactionsFunc           func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) ([]*rawapitypes.TransactionInfo, error)
+	GetLogsFunc                      func(ctx context.Context, shardId types.ShardId, filter rawapitypes.LogFilter) ([]*rawapitypes.LogInfo, error)
+	SubscribeLogsFunc                func(ctx context.Context, shardId types.ShardId, filter rawapitypes.LogFilter) (<-chan *rawapitypes.LogInfo, error)
+	GetBalanceFunc                   func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error)
+	GetBalancesFunc                  func(ctx context.Context, addresses []types.Address, blockReference rawapitypes.BlockReference) ([]types.Value, error)
+	GetCodeFunc                      func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error)
+	GetStorageAtFunc                 func(ctx context.Context, address types.Address, key common.Hash, blockReference rawapitypes.BlockReference) (types.Uint256, error)
+	GetStorageRangeFunc              func(ctx context.Context, address types.Address, start common.Hash, limit uint64, blockReference rawapitypes.BlockReference) (*rawapitypes.StorageRange, error)
+	GetTokensFunc                    func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference, request rawapitypes.TokensRequest) (*rawapitypes.TokensPage, error)
+	GetTokenBalanceFunc              func(ctx context.Context, address types.Address, tokenId types.TokenId, blockReference rawapitypes.BlockReference) (types.Value, error)
+	GetTokenInfoFunc                 func(ctx context.Context, tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference) (*rawapitypes.TokenInfo, error)
+	ListTokensCreatedByFunc          func(ctx context.Context, address types.Address, mainBlockReference rawapitypes.BlockReference) ([]*rawapitypes.TokenInfo, error)
+	GetTransactionCountFunc          func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetNextValidSeqnoFunc            func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error)
+	GetContractFunc                  func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.SmartContract, error)
+	GetAccountMetaFunc               func(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.AccountMeta, error)
+	GetProofFunc                     func(ctx context.Context, address types.Address, storageKeys []common.Hash, blockReference rawapitypes.BlockReference) (*rawapitypes.ContractProof, error)
+	CallFunc                         func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rpctypes.CallResWithGasPrice, error)
+	EstimateFeeFunc                  func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.FeeEstimation, error)
+	CreateAccessListFunc             func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.AccessListResult, error)
+	SimulateBundleFunc               func(ctx context.Context, calls []rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) ([]*rawapitypes.BundleCallResult, error)
+	PrepareDeployFunc                func(ctx context.Context, request rawapitypes.DeployRequest) (*rawapitypes.DeployPreparation, error)
+	GasPriceFunc                     func(ctx context.Context, shardId types.ShardId) (types.Value, error)
+	GetShardIdListFunc               func(ctx context.Context) ([]types.ShardId, error)
+	GetNumShardsFunc                 func(ctx context.Context) (uint64, error)
+	ResolveShardFunc                 func(ctx context.Context, address types.Address) (types.ShardId, error)
+	ResolveShardsFunc                func(ctx context.Context, addresses []types.Address) ([]*rawapitypes.ShardResolution, error)
+	GetChainConfigFunc               func(ctx context.Context) (*rawapitypes.ChainConfig, error)
+	GetSyncStatusFunc                func(ctx context.Context, shardId types.ShardId) (*rawapitypes.SyncStatus, error)
+	HealthFunc                       func(ctx context.Context, maxBlockLag uint64) (*rawapitypes.NodeHealth, error)
+	ClientVersionFunc                func(ctx context.Context) (string, error)
+	GetTxpoolStatusFunc              func(ctx context.Context, shardId types.ShardId) (uint64, error)
+	GetTxpoolContentFunc             func(ctx context.Context, shardId types.ShardId) ([]*types.Transaction, error)
+	SubscribePendingTransactionsFunc func(ctx context.Context, shardId types.ShardId, fullTransactions bool) (<-chan *rawapitypes.PendingTransaction, error)
+	SendTransactionFunc              func(ctx context.Context, shardId types.ShardId, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (*rawapitypes.SendTransactionResult, error)
+	SendTransactionBundleFunc        func(ctx context.Context, shardId types.ShardId, transactions [][]byte, sameBlock bool) ([]*rawapitypes.SendTransactionResult, error)
+	SendTransactionAndWatchFunc      func(ctx context.Context, shardId types.ShardId, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (<-chan *rawapitypes.TransactionEvent, error)
+	GetTransactionStatusFunc         func(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.TransactionStatusInfo, error)
+	ResendTransactionFunc            func(ctx context.Context, shardId types.ShardId, transaction []byte, replacement txnpool.ReplacementPolicy) (txnpool.DiscardReason, error)
+	DoPanicOnShardFunc               func(ctx context.Context, shardId types.ShardId) (uint64, error)
+	TraceTransactionFunc             func(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ExecutionTrace, error)
+	TraceCallFunc                    func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.ExecutionTrace, error)
+	TraceTransactionStateDiffFunc    func(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.StateDiff, error)
+	TraceCallStateDiffFunc           func(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.StateDiff, error)
+	ReplayTransactionFunc            func(ctx context.Context, shardId types.ShardId, hash common.Hash, options rawapitypes.ReplayOptions) (*rawapitypes.ReplayResult, error)
+	GetPendingResponsesForFunc       func(ctx context.Context, address types.Address, start types.TransactionIndex, limit uint64, blockReference rawapitypes.BlockReference) (*rawapitypes.PendingResponses, error)
+	GetAwaitingContextFunc           func(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.AwaitingContext, error)
+	GetPoolContentFunc               func(ctx context.Context, shardId types.ShardId) (*rawapitypes.PoolContent, error)
+	GetPoolStatusFunc                func(ctx context.Context, shardId types.ShardId) (*rawapitypes.PoolStatus, error)
+	GetPoolTransactionsByAccountFunc func(ctx context.Context, address types.Address) (*rawapitypes.PoolContent, error)
+	GetSnapshotManifestFunc          func(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*rawapitypes.SnapshotManifest, error)
+	GetSnapshotChunkFunc             func(ctx context.Context, shardId types.ShardId, request rawapitypes.SnapshotChunkRequest) (*rawapitypes.SnapshotChunk, error)
+	GetBlocksSinceFunc               func(ctx context.Context, shardId types.ShardId, sequence types.BlockNumber, limit uint64) ([]*types.RawBlockWithExtractedData, error)
+	SetP2pRequestHandlersFunc        func(ctx context.Context, networkManager network.Manager, cfg internal.RequestHandlersConfig, logger logging.Logger) error
+	UnsetP2pRequestHandlersFunc      func(ctx context.Context) int
+	SetShardP2pRequestHandlersFunc   func(shardId types.ShardId, shardApis internal.NodeApi) error
+	UnsetShardP2pRequestHandlersFunc func(ctx context.Context, shardId types.ShardId) int
+	SetShardReadOnlyFunc             func(shardId types.ShardId, readOnly bool, shardApis internal.NodeApi) error
+}
+
+var _ internal.NodeApi = (*NodeApiMock)(nil)
+
+func (m *NodeApiMock) GetBlockHeader(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (sszx.SSZEncodedData, error) {
+	m.Record("GetBlockHeader", shardId, blockReference)
+	if f := m.GetBlockHeaderFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 sszx.SSZEncodedData
+	return r0, newNotMockedError("NodeApi", "GetBlockHeader")
+}
+
+func (m *NodeApiMock) GetFullBlockData(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*types.RawBlockWithExtractedData, error) {
+	m.Record("GetFullBlockData", shardId, blockReference)
+	if f := m.GetFullBlockDataFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 *types.RawBlockWithExtractedData
+	return r0, newNotMockedError("NodeApi", "GetFullBlockData")
+}
+
+func (m *NodeApiMock) GetBlockTransactionCount(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (uint64, error) {
+	m.Record("GetBlockTransactionCount", shardId, blockReference)
+	if f := m.GetBlockTransactionCountFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("NodeApi", "GetBlockTransactionCount")
+}
+
+func (m *NodeApiMock) GetBlockRange(ctx context.Context, shardId types.ShardId, from types.BlockNumber, count uint64, fullBlocks bool) ([]*types.RawBlockWithExtractedData, error) {
+	m.Record("GetBlockRange", shardId, from, count, fullBlocks)
+	if f := m.GetBlockRangeFunc; f != nil {
+		return f(ctx, shardId, from, count, fullBlocks)
+	}
+	var r0 []*types.RawBlockWithExtractedData
+	return r0, newNotMockedError("NodeApi", "GetBlockRange")
+}
+
+func (m *NodeApiMock) GetShardStats(ctx context.Context, shardId types.ShardId, from types.BlockNumber, count uint64) (*rawapitypes.ShardStats, error) {
+	m.Record("GetShardStats", shardId, from, count)
+	if f := m.GetShardStatsFunc; f != nil {
+		return f(ctx, shardId, from, count)
+	}
+	var r0 *rawapitypes.ShardStats
+	return r0, newNotMockedError("NodeApi", "GetShardStats")
+}
+
+func (m *NodeApiMock) GetBlockFinalitySignatures(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*rawapitypes.FinalitySignatures, error) {
+	m.Record("GetBlockFinalitySignatures", shardId, blockReference)
+	if f := m.GetBlockFinalitySignaturesFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 *rawapitypes.FinalitySignatures
+	return r0, newNotMockedError("NodeApi", "GetBlockFinalitySignatures")
+}
+
+func (m *NodeApiMock) GetValidators(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*rawapitypes.ValidatorSet, error) {
+	m.Record("GetValidators", shardId, blockReference)
+	if f := m.GetValidatorsFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 *rawapitypes.ValidatorSet
+	return r0, newNotMockedError("NodeApi", "GetValidators")
+}
+
+func (m *NodeApiMock) GetValidatorInfo(ctx context.Context, shardId types.ShardId, publicKey []byte) (*rawapitypes.ValidatorInfo, error) {
+	m.Record("GetValidatorInfo", shardId, publicKey)
+	if f := m.GetValidatorInfoFunc; f != nil {
+		return f(ctx, shardId, publicKey)
+	}
+	var r0 *rawapitypes.ValidatorInfo
+	return r0, newNotMockedError("NodeApi", "GetValidatorInfo")
+}
+
+func (m *NodeApiMock) GetEpochInfo(ctx context.Context, shardId types.ShardId) (*rawapitypes.EpochInfo, error) {
+	m.Record("GetEpochInfo", shardId)
+	if f := m.GetEpochInfoFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 *rawapitypes.EpochInfo
+	return r0, newNotMockedError("NodeApi", "GetEpochInfo")
+}
+
+func (m *NodeApiMock) SubscribeNewHeads(ctx context.Context, shardId types.ShardId) (<-chan sszx.SSZEncodedData, error) {
+	m.Record("SubscribeNewHeads", shardId)
+	if f := m.SubscribeNewHeadsFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 <-chan sszx.SSZEncodedData
+	return r0, newNotMockedError("NodeApi", "SubscribeNewHeads")
+}
+
+func (m *NodeApiMock) GetInTransaction(ctx context.Context, shardId types.ShardId, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error) {
+	m.Record("GetInTransaction", shardId, transactionRequest)
+	if f := m.GetInTransactionFunc; f != nil {
+		return f(ctx, shardId, transactionRequest)
+	}
+	var r0 *rawapitypes.TransactionInfo
+	return r0, newNotMockedError("NodeApi", "GetInTransaction")
+}
+
+func (m *NodeApiMock) GetReceipt(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ReceiptInfo, error) {
+	m.Record("GetReceipt", shardId, hash)
+	if f := m.GetReceiptFunc; f != nil {
+		return f(ctx, shardId, hash)
+	}
+	var r0 *rawapitypes.ReceiptInfo
+	return r0, newNotMockedError("NodeApi", "GetReceipt")
+}
+
+func (m *NodeApiMock) GetBounceInfo(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.BounceInfo, error) {
+	m.Record("GetBounceInfo", shardId, hash)
+	if f := m.GetBounceInfoFunc; f != nil {
+		return f(ctx, shardId, hash)
+	}
+	var r0 *rawapitypes.BounceInfo
+	return r0, newNotMockedError("NodeApi", "GetBounceInfo")
+}
+
+func (m *NodeApiMock) GetBlockReceipts(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) ([]*rawapitypes.ReceiptInfo, error) {
+	m.Record("GetBlockReceipts", shardId, blockReference)
+	if f := m.GetBlockReceiptsFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 []*rawapitypes.ReceiptInfo
+	return r0, newNotMockedError("NodeApi", "GetBlockReceipts")
+}
+
+func (m *NodeApiMock) GetOutTransaction(ctx context.Context, shardId types.ShardId, transactionRequest rawapitypes.TransactionRequest) (*rawapitypes.TransactionInfo, error) {
+	m.Record("GetOutTransaction", shardId, transactionRequest)
+	if f := m.GetOutTransactionFunc; f != nil {
+		return f(ctx, shardId, transactionRequest)
+	}
+	var r0 *rawapitypes.TransactionInfo
+	return r0, newNotMockedError("NodeApi", "GetOutTransaction")
+}
+
+func (m *NodeApiMock) GetOutTransactions(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) ([]*rawapitypes.TransactionInfo, error) {
+	m.Record("GetOutTransactions", shardId, blockReference)
+	if f := m.GetOutTransactionsFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 []*rawapitypes.TransactionInfo
+	return r0, newNotMockedError("NodeApi", "GetOutTransactions")
+}
+
+func (m *NodeApiMock) GetLogs(ctx context.Context, shardId types.ShardId, filter rawapitypes.LogFilter) ([]*rawapitypes.LogInfo, error) {
+	m.Record("GetLogs", shardId, filter)
+	if f := m.GetLogsFunc; f != nil {
+		return f(ctx, shardId, filter)
+	}
+	var r0 []*rawapitypes.LogInfo
+	return r0, newNotMockedError("NodeApi", "GetLogs")
+}
+
+func (m *NodeApiMock) SubscribeLogs(ctx context.Context, shardId types.ShardId, filter rawapitypes.LogFilter) (<-chan *rawapitypes.LogInfo, error) {
+	m.Record("SubscribeLogs", shardId, filter)
+	if f := m.SubscribeLogsFunc; f != nil {
+		return f(ctx, shardId, filter)
+	}
+	var r0 <-chan *rawapitypes.LogInfo
+	return r0, newNotMockedError("NodeApi", "SubscribeLogs")
+}
+
+func (m *NodeApiMock) GetBalance(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Value, error) {
+	m.Record("GetBalance", address, blockReference)
+	if f := m.GetBalanceFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 types.Value
+	return r0, newNotMockedError("NodeApi", "GetBalance")
+}
+
+func (m *NodeApiMock) GetBalances(ctx context.Context, addresses []types.Address, blockReference rawapitypes.BlockReference) ([]types.Value, error) {
+	m.Record("GetBalances", addresses, blockReference)
+	if f := m.GetBalancesFunc; f != nil {
+		return f(ctx, addresses, blockReference)
+	}
+	var r0 []types.Value
+	return r0, newNotMockedError("NodeApi", "GetBalances")
+}
+
+func (m *NodeApiMock) GetCode(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (types.Code, error) {
+	m.Record("GetCode", address, blockReference)
+	if f := m.GetCodeFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 types.Code
+	return r0, newNotMockedError("NodeApi", "GetCode")
+}
+
+func (m *NodeApiMock) GetStorageAt(ctx context.Context, address types.Address, key common.Hash, blockReference rawapitypes.BlockReference) (types.Uint256, error) {
+	m.Record("GetStorageAt", address, key, blockReference)
+	if f := m.GetStorageAtFunc; f != nil {
+		return f(ctx, address, key, blockReference)
+	}
+	var r0 types.Uint256
+	return r0, newNotMockedError("NodeApi", "GetStorageAt")
+}
+
+func (m *NodeApiMock) GetStorageRange(ctx context.Context, address types.Address, start common.Hash, limit uint64, blockReference rawapitypes.BlockReference) (*rawapitypes.StorageRange, error) {
+	m.Record("GetStorageRange", address, start, limit, blockReference)
+	if f := m.GetStorageRangeFunc; f != nil {
+		return f(ctx, address, start, limit, blockReference)
+	}
+	var r0 *rawapitypes.StorageRange
+	return r0, newNotMockedError("NodeApi", "GetStorageRange")
+}
+
+func (m *NodeApiMock) GetTokens(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference, request rawapitypes.TokensRequest) (*rawapitypes.TokensPage, error) {
+	m.Record("GetTokens", address, blockReference, request)
+	if f := m.GetTokensFunc; f != nil {
+		return f(ctx, address, blockReference, request)
+	}
+	var r0 *rawapitypes.TokensPage
+	return r0, newNotMockedError("NodeApi", "GetTokens")
+}
+
+func (m *NodeApiMock) GetTokenBalance(ctx context.Context, address types.Address, tokenId types.TokenId, blockReference rawapitypes.BlockReference) (types.Value, error) {
+	m.Record("GetTokenBalance", address, tokenId, blockReference)
+	if f := m.GetTokenBalanceFunc; f != nil {
+		return f(ctx, address, tokenId, blockReference)
+	}
+	var r0 types.Value
+	return r0, newNotMockedError("NodeApi", "GetTokenBalance")
+}
+
+func (m *NodeApiMock) GetTokenInfo(ctx context.Context, tokenId types.TokenId, mainBlockReference rawapitypes.BlockReference) (*rawapitypes.TokenInfo, error) {
+	m.Record("GetTokenInfo", tokenId, mainBlockReference)
+	if f := m.GetTokenInfoFunc; f != nil {
+		return f(ctx, tokenId, mainBlockReference)
+	}
+	var r0 *rawapitypes.TokenInfo
+	return r0, newNotMockedError("NodeApi", "GetTokenInfo")
+}
+
+func (m *NodeApiMock) ListTokensCreatedBy(ctx context.Context, address types.Address, mainBlockReference rawapitypes.BlockReference) ([]*rawapitypes.TokenInfo, error) {
+	m.Record("ListTokensCreatedBy", address, mainBlockReference)
+	if f := m.ListTokensCreatedByFunc; f != nil {
+		return f(ctx, address, mainBlockReference)
+	}
+	var r0 []*rawapitypes.TokenInfo
+	return r0, newNotMockedError("NodeApi", "ListTokensCreatedBy")
+}
+
+func (m *NodeApiMock) GetTransactionCount(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error) {
+	m.Record("GetTransactionCount", address, blockReference)
+	if f := m.GetTransactionCountFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("NodeApi", "GetTransactionCount")
+}
+
+func (m *NodeApiMock) GetNextValidSeqno(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (uint64, error) {
+	m.Record("GetNextValidSeqno", address, blockReference)
+	if f := m.GetNextValidSeqnoFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("NodeApi", "GetNextValidSeqno")
+}
+
+func (m *NodeApiMock) GetContract(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.SmartContract, error) {
+	m.Record("GetContract", address, blockReference)
+	if f := m.GetContractFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 *rawapitypes.SmartContract
+	return r0, newNotMockedError("NodeApi", "GetContract")
+}
+
+func (m *NodeApiMock) GetAccountMeta(ctx context.Context, address types.Address, blockReference rawapitypes.BlockReference) (*rawapitypes.AccountMeta, error) {
+	m.Record("GetAccountMeta", address, blockReference)
+	if f := m.GetAccountMetaFunc; f != nil {
+		return f(ctx, address, blockReference)
+	}
+	var r0 *rawapitypes.AccountMeta
+	return r0, newNotMockedError("NodeApi", "GetAccountMeta")
+}
+
+func (m *NodeApiMock) GetProof(ctx context.Context, address types.Address, storageKeys []common.Hash, blockReference rawapitypes.BlockReference) (*rawapitypes.ContractProof, error) {
+	m.Record("GetProof", address, storageKeys, blockReference)
+	if f := m.GetProofFunc; f != nil {
+		return f(ctx, address, storageKeys, blockReference)
+	}
+	var r0 *rawapitypes.ContractProof
+	return r0, newNotMockedError("NodeApi", "GetProof")
+}
+
+func (m *NodeApiMock) Call(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rpctypes.CallResWithGasPrice, error) {
+	m.Record("Call", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.CallFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rpctypes.CallResWithGasPrice
+	return r0, newNotMockedError("NodeApi", "Call")
+}
+
+func (m *NodeApiMock) EstimateFee(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.FeeEstimation, error) {
+	m.Record("EstimateFee", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.EstimateFeeFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rawapitypes.FeeEstimation
+	return r0, newNotMockedError("NodeApi", "EstimateFee")
+}
+
+func (m *NodeApiMock) CreateAccessList(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.AccessListResult, error) {
+	m.Record("CreateAccessList", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.CreateAccessListFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rawapitypes.AccessListResult
+	return r0, newNotMockedError("NodeApi", "CreateAccessList")
+}
+
+func (m *NodeApiMock) SimulateBundle(ctx context.Context, calls []rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) ([]*rawapitypes.BundleCallResult, error) {
+	m.Record("SimulateBundle", calls, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.SimulateBundleFunc; f != nil {
+		return f(ctx, calls, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 []*rawapitypes.BundleCallResult
+	return r0, newNotMockedError("NodeApi", "SimulateBundle")
+}
+
+func (m *NodeApiMock) PrepareDeploy(ctx context.Context, request rawapitypes.DeployRequest) (*rawapitypes.DeployPreparation, error) {
+	m.Record("PrepareDeploy", request)
+	if f := m.PrepareDeployFunc; f != nil {
+		return f(ctx, request)
+	}
+	var r0 *rawapitypes.DeployPreparation
+	return r0, newNotMockedError("NodeApi", "PrepareDeploy")
+}
+
+func (m *NodeApiMock) GasPrice(ctx context.Context, shardId types.ShardId) (types.Value, error) {
+	m.Record("GasPrice", shardId)
+	if f := m.GasPriceFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 types.Value
+	return r0, newNotMockedError("NodeApi", "GasPrice")
+}
+
+func (m *NodeApiMock) GetShardIdList(ctx context.Context) ([]types.ShardId, error) {
+	m.Record("GetShardIdList")
+	if f := m.GetShardIdListFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 []types.ShardId
+	return r0, newNotMockedError("NodeApi", "GetShardIdList")
+}
+
+func (m *NodeApiMock) GetNumShards(ctx context.Context) (uint64, error) {
+	m.Record("GetNumShards")
+	if f := m.GetNumShardsFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("NodeApi", "GetNumShards")
+}
+
+func (m *NodeApiMock) ResolveShard(ctx context.Context, address types.Address) (types.ShardId, error) {
+	m.Record("ResolveShard", address)
+	if f := m.ResolveShardFunc; f != nil {
+		return f(ctx, address)
+	}
+	var r0 types.ShardId
+	return r0, newNotMockedError("NodeApi", "ResolveShard")
+}
+
+func (m *NodeApiMock) ResolveShards(ctx context.Context, addresses []types.Address) ([]*rawapitypes.ShardResolution, error) {
+	m.Record("ResolveShards", addresses)
+	if f := m.ResolveShardsFunc; f != nil {
+		return f(ctx, addresses)
+	}
+	var r0 []*rawapitypes.ShardResolution
+	return r0, newNotMockedError("NodeApi", "ResolveShards")
+}
+
+func (m *NodeApiMock) GetChainConfig(ctx context.Context) (*rawapitypes.ChainConfig, error) {
+	m.Record("GetChainConfig")
+	if f := m.GetChainConfigFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 *rawapitypes.ChainConfig
+	return r0, newNotMockedError("NodeApi", "GetChainConfig")
+}
+
+func (m *NodeApiMock) GetSyncStatus(ctx context.Context, shardId types.ShardId) (*rawapitypes.SyncStatus, error) {
+	m.Record("GetSyncStatus", shardId)
+	if f := m.GetSyncStatusFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 *rawapitypes.SyncStatus
+	return r0, newNotMockedError("NodeApi", "GetSyncStatus")
+}
+
+func (m *NodeApiMock) Health(ctx context.Context, maxBlockLag uint64) (*rawapitypes.NodeHealth, error) {
+	m.Record("Health", maxBlockLag)
+	if f := m.HealthFunc; f != nil {
+		return f(ctx, maxBlockLag)
+	}
+	var r0 *rawapitypes.NodeHealth
+	return r0, newNotMockedError("NodeApi", "Health")
+}
+
+func (m *NodeApiMock) ClientVersion(ctx context.Context) (string, error) {
+	m.Record("ClientVersion")
+	if f := m.ClientVersionFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 string
+	return r0, newNotMockedError("NodeApi", "ClientVersion")
+}
+
+func (m *NodeApiMock) GetTxpoolStatus(ctx context.Context, shardId types.ShardId) (uint64, error) {
+	m.Record("GetTxpoolStatus", shardId)
+	if f := m.GetTxpoolStatusFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("NodeApi", "GetTxpoolStatus")
+}
+
+func (m *NodeApiMock) GetTxpoolContent(ctx context.Context, shardId types.ShardId) ([]*types.Transaction, error) {
+	m.Record("GetTxpoolContent", shardId)
+	if f := m.GetTxpoolContentFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 []*types.Transaction
+	return r0, newNotMockedError("NodeApi", "GetTxpoolContent")
+}
+
+func (m *NodeApiMock) SubscribePendingTransactions(ctx context.Context, shardId types.ShardId, fullTransactions bool) (<-chan *rawapitypes.PendingTransaction, error) {
+	m.Record("SubscribePendingTransactions", shardId, fullTransactions)
+	if f := m.SubscribePendingTransactionsFunc; f != nil {
+		return f(ctx, shardId, fullTransactions)
+	}
+	var r0 <-chan *rawapitypes.PendingTransaction
+	return r0, newNotMockedError("NodeApi", "SubscribePendingTransactions")
+}
+
+func (m *NodeApiMock) SendTransaction(ctx context.Context, shardId types.ShardId, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (*rawapitypes.SendTransactionResult, error) {
+	m.Record("SendTransaction", shardId, transaction, replacement, idempotencyKey)
+	if f := m.SendTransactionFunc; f != nil {
+		return f(ctx, shardId, transaction, replacement, idempotencyKey)
+	}
+	var r0 *rawapitypes.SendTransactionResult
+	return r0, newNotMockedError("NodeApi", "SendTransaction")
+}
+
+func (m *NodeApiMock) SendTransactionBundle(ctx context.Context, shardId types.ShardId, transactions [][]byte, sameBlock bool) ([]*rawapitypes.SendTransactionResult, error) {
+	m.Record("SendTransactionBundle", shardId, transactions, sameBlock)
+	if f := m.SendTransactionBundleFunc; f != nil {
+		return f(ctx, shardId, transactions, sameBlock)
+	}
+	var r0 []*rawapitypes.SendTransactionResult
+	return r0, newNotMockedError("NodeApi", "SendTransactionBundle")
+}
+
+func (m *NodeApiMock) SendTransactionAndWatch(ctx context.Context, shardId types.ShardId, transaction []byte, replacement txnpool.ReplacementPolicy, idempotencyKey string) (<-chan *rawapitypes.TransactionEvent, error) {
+	m.Record("SendTransactionAndWatch", shardId, transaction, replacement, idempotencyKey)
+	if f := m.SendTransactionAndWatchFunc; f != nil {
+		return f(ctx, shardId, transaction, replacement, idempotencyKey)
+	}
+	var r0 <-chan *rawapitypes.TransactionEvent
+	return r0, newNotMockedError("NodeApi", "SendTransactionAndWatch")
+}
+
+func (m *NodeApiMock) GetTransactionStatus(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.TransactionStatusInfo, error) {
+	m.Record("GetTransactionStatus", shardId, hash)
+	if f := m.GetTransactionStatusFunc; f != nil {
+		return f(ctx, shardId, hash)
+	}
+	var r0 *rawapitypes.TransactionStatusInfo
+	return r0, newNotMockedError("NodeApi", "GetTransactionStatus")
+}
+
+func (m *NodeApiMock) ResendTransaction(ctx context.Context, shardId types.ShardId, transaction []byte, replacement txnpool.ReplacementPolicy) (txnpool.DiscardReason, error) {
+	m.Record("ResendTransaction", shardId, transaction, replacement)
+	if f := m.ResendTransactionFunc; f != nil {
+		return f(ctx, shardId, transaction, replacement)
+	}
+	var r0 txnpool.DiscardReason
+	return r0, newNotMockedError("NodeApi", "ResendTransaction")
+}
+
+func (m *NodeApiMock) DoPanicOnShard(ctx context.Context, shardId types.ShardId) (uint64, error) {
+	m.Record("DoPanicOnShard", shardId)
+	if f := m.DoPanicOnShardFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 uint64
+	return r0, newNotMockedError("NodeApi", "DoPanicOnShard")
+}
+
+func (m *NodeApiMock) TraceTransaction(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.ExecutionTrace, error) {
+	m.Record("TraceTransaction", shardId, hash)
+	if f := m.TraceTransactionFunc; f != nil {
+		return f(ctx, shardId, hash)
+	}
+	var r0 *rawapitypes.ExecutionTrace
+	return r0, newNotMockedError("NodeApi", "TraceTransaction")
+}
+
+func (m *NodeApiMock) TraceCall(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.ExecutionTrace, error) {
+	m.Record("TraceCall", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.TraceCallFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rawapitypes.ExecutionTrace
+	return r0, newNotMockedError("NodeApi", "TraceCall")
+}
+
+func (m *NodeApiMock) TraceTransactionStateDiff(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.StateDiff, error) {
+	m.Record("TraceTransactionStateDiff", shardId, hash)
+	if f := m.TraceTransactionStateDiffFunc; f != nil {
+		return f(ctx, shardId, hash)
+	}
+	var r0 *rawapitypes.StateDiff
+	return r0, newNotMockedError("NodeApi", "TraceTransactionStateDiff")
+}
+
+func (m *NodeApiMock) TraceCallStateDiff(ctx context.Context, args rpctypes.CallArgs, mainBlockReferenceOrHashWithChildren rawapitypes.BlockReferenceOrHashWithChildren, overrides *rpctypes.StateOverrides, blockOverrides *rpctypes.BlockOverrides) (*rawapitypes.StateDiff, error) {
+	m.Record("TraceCallStateDiff", args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	if f := m.TraceCallStateDiffFunc; f != nil {
+		return f(ctx, args, mainBlockReferenceOrHashWithChildren, overrides, blockOverrides)
+	}
+	var r0 *rawapitypes.StateDiff
+	return r0, newNotMockedError("NodeApi", "TraceCallStateDiff")
+}
+
+func (m *NodeApiMock) ReplayTransaction(ctx context.Context, shardId types.ShardId, hash common.Hash, options rawapitypes.ReplayOptions) (*rawapitypes.ReplayResult, error) {
+	m.Record("ReplayTransaction", shardId, hash, options)
+	if f := m.ReplayTransactionFunc; f != nil {
+		return f(ctx, shardId, hash, options)
+	}
+	var r0 *rawapitypes.ReplayResult
+	return r0, newNotMockedError("NodeApi", "ReplayTransaction")
+}
+
+func (m *NodeApiMock) GetPendingResponsesFor(ctx context.Context, address types.Address, start types.TransactionIndex, limit uint64, blockReference rawapitypes.BlockReference) (*rawapitypes.PendingResponses, error) {
+	m.Record("GetPendingResponsesFor", address, start, limit, blockReference)
+	if f := m.GetPendingResponsesForFunc; f != nil {
+		return f(ctx, address, start, limit, blockReference)
+	}
+	var r0 *rawapitypes.PendingResponses
+	return r0, newNotMockedError("NodeApi", "GetPendingResponsesFor")
+}
+
+func (m *NodeApiMock) GetAwaitingContext(ctx context.Context, shardId types.ShardId, hash common.Hash) (*rawapitypes.AwaitingContext, error) {
+	m.Record("GetAwaitingContext", shardId, hash)
+	if f := m.GetAwaitingContextFunc; f != nil {
+		return f(ctx, shardId, hash)
+	}
+	var r0 *rawapitypes.AwaitingContext
+	return r0, newNotMockedError("NodeApi", "GetAwaitingContext")
+}
+
+func (m *NodeApiMock) GetPoolContent(ctx context.Context, shardId types.ShardId) (*rawapitypes.PoolContent, error) {
+	m.Record("GetPoolContent", shardId)
+	if f := m.GetPoolContentFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 *rawapitypes.PoolContent
+	return r0, newNotMockedError("NodeApi", "GetPoolContent")
+}
+
+func (m *NodeApiMock) GetPoolStatus(ctx context.Context, shardId types.ShardId) (*rawapitypes.PoolStatus, error) {
+	m.Record("GetPoolStatus", shardId)
+	if f := m.GetPoolStatusFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 *rawapitypes.PoolStatus
+	return r0, newNotMockedError("NodeApi", "GetPoolStatus")
+}
+
+func (m *NodeApiMock) GetPoolTransactionsByAccount(ctx context.Context, address types.Address) (*rawapitypes.PoolContent, error) {
+	m.Record("GetPoolTransactionsByAccount", address)
+	if f := m.GetPoolTransactionsByAccountFunc; f != nil {
+		return f(ctx, address)
+	}
+	var r0 *rawapitypes.PoolContent
+	return r0, newNotMockedError("NodeApi", "GetPoolTransactionsByAccount")
+}
+
+func (m *NodeApiMock) GetSnapshotManifest(ctx context.Context, shardId types.ShardId, blockReference rawapitypes.BlockReference) (*rawapitypes.SnapshotManifest, error) {
+	m.Record("GetSnapshotManifest", shardId, blockReference)
+	if f := m.GetSnapshotManifestFunc; f != nil {
+		return f(ctx, shardId, blockReference)
+	}
+	var r0 *rawapitypes.SnapshotManifest
+	return r0, newNotMockedError("NodeApi", "GetSnapshotManifest")
+}
+
+func (m *NodeApiMock) GetSnapshotChunk(ctx context.Context, shardId types.ShardId, request rawapitypes.SnapshotChunkRequest) (*rawapitypes.SnapshotChunk, error) {
+	m.Record("GetSnapshotChunk", shardId, request)
+	if f := m.GetSnapshotChunkFunc; f != nil {
+		return f(ctx, shardId, request)
+	}
+	var r0 *rawapitypes.SnapshotChunk
+	return r0, newNotMockedError("NodeApi", "GetSnapshotChunk")
+}
+
+func (m *NodeApiMock) GetBlocksSince(ctx context.Context, shardId types.ShardId, sequence types.BlockNumber, limit uint64) ([]*types.RawBlockWithExtractedData, error) {
+	m.Record("GetBlocksSince", shardId, sequence, limit)
+	if f := m.GetBlocksSinceFunc; f != nil {
+		return f(ctx, shardId, sequence, limit)
+	}
+	var r0 []*types.RawBlockWithExtractedData
+	return r0, newNotMockedError("NodeApi", "GetBlocksSince")
+}
+
+func (m *NodeApiMock) SetP2pRequestHandlers(ctx context.Context, networkManager network.Manager, cfg internal.RequestHandlersConfig, logger logging.Logger) error {
+	m.Record("SetP2pRequestHandlers", networkManager, cfg, logger)
+	if f := m.SetP2pRequestHandlersFunc; f != nil {
+		return f(ctx, networkManager, cfg, logger)
+	}
+	return newNotMockedError("NodeApi", "SetP2pRequestHandlers")
+}
+
+func (m *NodeApiMock) UnsetP2pRequestHandlers(ctx context.Context) int {
+	m.Record("UnsetP2pRequestHandlers")
+	if f := m.UnsetP2pRequestHandlersFunc; f != nil {
+		return f(ctx)
+	}
+	var r0 int
+	return r0
+}
+
+func (m *NodeApiMock) SetShardP2pRequestHandlers(shardId types.ShardId, shardApis internal.NodeApi) error {
+	m.Record("SetShardP2pRequestHandlers", shardId, shardApis)
+	if f := m.SetShardP2pRequestHandlersFunc; f != nil {
+		return f(shardId, shardApis)
+	}
+	return newNotMockedError("NodeApi", "SetShardP2pRequestHandlers")
+}
+
+func (m *NodeApiMock) UnsetShardP2pRequestHandlers(ctx context.Context, shardId types.ShardId) int {
+	m.Record("UnsetShardP2pRequestHandlers", shardId)
+	if f := m.UnsetShardP2pRequestHandlersFunc; f != nil {
+		return f(ctx, shardId)
+	}
+	var r0 int
+	return r0
+}
+
+func (m *NodeApiMock) SetShardReadOnly(shardId types.ShardId, readOnly bool, shardApis internal.NodeApi) error {
+	m.Record("SetShardReadOnly", shardId, readOnly, shardApis)
+	if f := m.SetShardReadOnlyFunc; f != nil {
+		return f(shardId, readOnly, shardApis)
+	}
+	return newNotMockedError("NodeApi", "SetShardReadOnly")
+}
@@ -0,0 +1,374 @@
+//go:build test
+
+// Package rawapitest provides an in-process network for the tests of the raw API, so that the handlers set
+// by the raw API serve the requests of its clients through the full codec and dispatch path without libp2p hosts.
+package rawapitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/internal"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const protocolVersion = "rawapitest"
+
+var (
+	// ErrDropped is returned to the client when a stream is dropped by the faults of the network.
+	ErrDropped = errors.New("stream dropped")
+	// ErrNoHandler is returned to the client when the peer serves no handler for the protocol.
+	ErrNoHandler = errors.New("protocol is not supported by the peer")
+	// ErrUnknownPeer is returned to the client when the peer is not in the network or has been closed.
+	ErrUnknownPeer = errors.New("unknown peer")
+)
+
+// Faults are injected into the streams opened to the peers of the network.
+type Faults struct {
+	// Match selects the protocols the faults are injected into, all of them if it is nil.
+	Match func(protocol network.ProtocolID) bool
+	// DropRate is the probability of the stream failing to open with ErrDropped.
+	DropRate float64
+	// Delay is waited for before the stream reaches the handler.
+	Delay time.Duration
+	// TruncateRequest limits the request read by the handler to the number of bytes if it is positive.
+	TruncateRequest int
+	// TruncateResponse limits the response read by the client to the number of bytes if it is positive.
+	TruncateResponse int
+}
+
+func (f *Faults) matches(protocol network.ProtocolID) bool {
+	return f != nil && (f.Match == nil || f.Match(protocol))
+}
+
+// Network links the managers created by it, the streams opened from one of them are handled by another one
+// in a separate goroutine.
+type Network struct {
+	mu       sync.Mutex
+	managers map[network.PeerID]*Manager
+	faults   *Faults
+}
+
+func NewNetwork() *Network {
+	return &Network{managers: make(map[network.PeerID]*Manager)}
+}
+
+// SetFaults replaces the injected faults, nil disables them. The streams opened before are not affected.
+func (n *Network) SetFaults(faults *Faults) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.faults = faults
+}
+
+// NewManager adds a manager with a new identity to the network.
+func (n *Network) NewManager() *Manager {
+	key, err := network.GeneratePrivateKey()
+	check.PanicIfErr(err)
+	id, err := peer.IDFromPrivateKey(key)
+	check.PanicIfErr(err)
+
+	m := &Manager{
+		network:  n,
+		id:       id,
+		handlers: make(map[network.ProtocolID]network.StreamHandler),
+		logger:   logging.NewLogger("rawapitest").With().Str(logging.FieldP2PIdentity, id.String()).Logger(),
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.managers[id] = m
+	return m
+}
+
+func (n *Network) manager(id network.PeerID) (*Manager, *Faults, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	m, ok := n.managers[id]
+	return m, n.faults, ok
+}
+
+func (n *Network) peers(except network.PeerID) []network.PeerID {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	peers := make([]network.PeerID, 0, len(n.managers))
+	for id := range n.managers {
+		if id != except {
+			peers = append(peers, id)
+		}
+	}
+	slices.Sort(peers)
+	return peers
+}
+
+func (n *Network) remove(id network.PeerID) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	delete(n.managers, id)
+}
+
+// Manager is an in-process network.Manager. All the peers of the network are connected to each other,
+// the deadlines of the streams are ignored and PubSub is not supported, so it returns nil.
+type Manager struct {
+	network.NoHost
+
+	network  *Network
+	id       network.PeerID
+	logger   logging.Logger
+	mu       sync.Mutex
+	handlers map[network.ProtocolID]network.StreamHandler
+}
+
+var _ network.Manager = (*Manager)(nil)
+
+func (m *Manager) ID() network.PeerID {
+	return m.id
+}
+
+func (m *Manager) PubSub() *network.PubSub {
+	return nil
+}
+
+func (m *Manager) ProtocolVersion() string {
+	return protocolVersion
+}
+
+func (m *Manager) GetPeerProtocolVersion(peerId network.PeerID) (string, error) {
+	if _, _, ok := m.network.manager(peerId); !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownPeer, peerId)
+	}
+	return protocolVersion, nil
+}
+
+func (m *Manager) AllKnownPeers() []network.PeerID {
+	return m.network.peers(m.id)
+}
+
+func (m *Manager) GetPeersForProtocol(protocol network.ProtocolID) []network.PeerID {
+	peers := make([]network.PeerID, 0)
+	for _, peerId := range m.network.peers(m.id) {
+		if other, _, ok := m.network.manager(peerId); ok && other.handler(protocol) != nil {
+			peers = append(peers, peerId)
+		}
+	}
+	return peers
+}
+
+func (m *Manager) Connect(_ context.Context, addr network.AddrInfo) (network.PeerID, error) {
+	if _, _, ok := m.network.manager(addr.ID); !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownPeer, addr.ID)
+	}
+	return addr.ID, nil
+}
+
+func (m *Manager) Disconnect(_ network.PeerID) (int, error) {
+	return 0, nil
+}
+
+func (m *Manager) AddPeer(ctx context.Context, addr network.AddrInfo) (int, error) {
+	if _, err := m.Connect(ctx, addr); err != nil {
+		return 0, err
+	}
+	return 0, nil
+}
+
+func (m *Manager) RemovePeer(_ network.PeerID) (int, error) {
+	return 0, nil
+}
+
+// ProtectPeer protects nothing, the peers of the network are never disconnected.
+func (m *Manager) ProtectPeer(_ network.PeerID, _ string, protect bool) bool {
+	return protect
+}
+
+func (m *Manager) SetPeerTag(_ network.PeerID, _ string, _ int) int {
+	return 0
+}
+
+func (m *Manager) ConnectedPeers() []network.PeerID {
+	return m.network.peers(m.id)
+}
+
+// GetPeerInfo reports no connections or streams, since the streams of the network don't outlive the requests.
+func (m *Manager) GetPeerInfo(peerId network.PeerID) (network.PeerInfo, error) {
+	if _, _, ok := m.network.manager(peerId); !ok || peerId == m.id {
+		return network.PeerInfo{}, fmt.Errorf("%w: %s", network.ErrPeerNotConnected, peerId)
+	}
+	return network.PeerInfo{
+		Id:              peerId,
+		ProtocolVersion: protocolVersion,
+		Streams:         make(map[network.ProtocolID]uint64),
+	}, nil
+}
+
+// GetBandwidthByProtocol returns no traffic, it is not accounted by the network.
+func (m *Manager) GetBandwidthByProtocol() map[network.ProtocolID]network.BandwidthStats {
+	return make(map[network.ProtocolID]network.BandwidthStats)
+}
+
+// Close removes the manager from the network, the streams being handled are not affected.
+func (m *Manager) Close() {
+	m.network.remove(m.id)
+}
+
+func (m *Manager) handler(protocol network.ProtocolID) network.StreamHandler {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.handlers[protocol]
+}
+
+func (m *Manager) SetStreamHandler(_ context.Context, protocol network.ProtocolID, handler network.StreamHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.handlers[protocol] = handler
+}
+
+func (m *Manager) RemoveStreamHandler(protocol network.ProtocolID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.handlers, protocol)
+}
+
+// SetRequestHandler sets the handler the same way as network.BasicManager does: the handler receives
+// the whole request once the client closes its side of the stream and the stream is dropped if it fails.
+func (m *Manager) SetRequestHandler(ctx context.Context, protocol network.ProtocolID, handler network.RequestHandler) {
+	logger := m.logger.With().Str(logging.FieldProtocolID, string(protocol)).Logger()
+
+	m.SetStreamHandler(ctx, protocol, func(stream network.Stream) {
+		ctx := network.WithRequestPeer(ctx, stream.Conn().RemotePeer())
+
+		request, err := io.ReadAll(stream)
+		if err != nil {
+			logger.Debug().Err(err).Msg("Failed to read request")
+			_ = stream.Reset()
+			return
+		}
+
+		response, err := func() (response []byte, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error().Msgf("Request handler crashed: %v. Stack:\n%s", r, string(debug.Stack()))
+					err = errors.New("method handler crashed")
+				}
+			}()
+			return handler(ctx, request)
+		}()
+		if err != nil {
+			logger.Debug().Err(err).Msg("Failed to handle request")
+			_ = stream.Reset()
+			return
+		}
+
+		if _, err := stream.Write(response); err != nil {
+			logger.Debug().Err(err).Msg("Failed to write response")
+		}
+	})
+}
+
+// NewStream opens a stream handled by the handler of the peer, the faults of the network are injected into it.
+func (m *Manager) NewStream(
+	ctx context.Context,
+	peerId network.PeerID,
+	protocol network.ProtocolID,
+) (network.Stream, error) {
+	other, faults, ok := m.network.manager(peerId)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPeer, peerId)
+	}
+	handler := other.handler(protocol)
+	if handler == nil {
+		return nil, fmt.Errorf("%w: %s", ErrNoHandler, protocol)
+	}
+
+	if !faults.matches(protocol) {
+		faults = nil
+	}
+	if faults != nil {
+		if faults.DropRate > 0 && rand.Float64() < faults.DropRate { //nolint:gosec
+			return nil, fmt.Errorf("%w: %s", ErrDropped, protocol)
+		}
+		if faults.Delay > 0 {
+			select {
+			case <-time.After(faults.Delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	client, server := newStreamPair(m.id, peerId, protocol)
+	if faults != nil {
+		client.truncateRead(faults.TruncateResponse)
+		server.truncateRead(faults.TruncateRequest)
+	}
+	go func() {
+		defer server.Close()
+		handler(server)
+	}()
+	return client, nil
+}
+
+// SendRequestAndGetResponse sends the request the same way as network.BasicManager does, so it is also served
+// by the stream handlers writing the response at once, e.g., the streaming responses of the raw API.
+func (m *Manager) SendRequestAndGetResponse(
+	ctx context.Context,
+	peerId network.PeerID,
+	protocol network.ProtocolID,
+	request []byte,
+) ([]byte, error) {
+	stream, err := m.NewStream(ctx, peerId, protocol)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	if _, err := stream.Write(request); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseWrite(); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(stream)
+}
+
+// ServeShardApiRo sets the handlers of the read-only API of the shard on the manager.
+func ServeShardApiRo(
+	ctx context.Context,
+	manager network.Manager,
+	shardId types.ShardId,
+	api internal.ShardApiRo,
+	cfg internal.RequestHandlersConfig,
+) error {
+	return internal.SetRawApiRequestHandlers[internal.NetworkTransportProtocolRo](
+		ctx, api, shardId, manager, cfg, logging.NewLogger("rawapitest"))
+}
+
+// ServeShardApiRw sets the handlers of the API of the shard sending the transactions on the manager.
+func ServeShardApiRw(
+	ctx context.Context,
+	manager network.Manager,
+	shardId types.ShardId,
+	api internal.ShardApiRw,
+	cfg internal.RequestHandlersConfig,
+) error {
+	return internal.SetRawApiRequestHandlers[internal.NetworkTransportProtocolRw](
+		ctx, api, shardId, manager, cfg, logging.NewLogger("rawapitest"))
+}
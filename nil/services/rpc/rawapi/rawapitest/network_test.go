@@ -0,0 +1,137 @@
+package rawapitest
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/rawapi/internal"
+	p2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	"github.com/stretchr/testify/require"
+)
+
+func isClientVersion(protocol network.ProtocolID) bool {
+	return strings.Contains(string(protocol), "/ClientVersion")
+}
+
+func TestNetwork(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	net := NewNetwork()
+	server := net.NewManager()
+	client := net.NewManager()
+	mock := &ShardApiRoMock{
+		ClientVersionFunc: func(context.Context) (string, error) {
+			return "rawapitest/v1", nil
+		},
+	}
+	require.NoError(t, ServeShardApiRo(ctx, server, types.MainShardId, mock, internal.RequestHandlersConfig{}))
+
+	api := internal.NewNetworkShardApiClient(client, types.MainShardId, nil)
+
+	t.Run("Success", func(t *testing.T) {
+		version, err := api.ClientVersion(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "rawapitest/v1", version)
+		require.Equal(t, []network.PeerID{server.ID()}, client.AllKnownPeers())
+		require.Len(t, mock.CallsOf("ClientVersion"), 1)
+	})
+
+	t.Run("NotMocked", func(t *testing.T) {
+		_, err := api.GetNumShards(ctx)
+		require.ErrorContains(t, err, ErrNotMocked.Error())
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		net.SetFaults(&Faults{Match: isClientVersion, DropRate: 1})
+		defer net.SetFaults(nil)
+
+		_, err := api.ClientVersion(ctx)
+		require.ErrorIs(t, err, ErrDropped)
+		require.Len(t, mock.CallsOf("ClientVersion"), 1)
+	})
+
+	t.Run("Delay", func(t *testing.T) {
+		net.SetFaults(&Faults{Match: isClientVersion, Delay: time.Second})
+		defer net.SetFaults(nil)
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := api.ClientVersion(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("TruncatedResponse", func(t *testing.T) {
+		net.SetFaults(&Faults{Match: isClientVersion, TruncateResponse: 3})
+		defer net.SetFaults(nil)
+
+		_, err := api.ClientVersion(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("TruncatedRequest", func(t *testing.T) {
+		net.SetFaults(&Faults{Match: isClientVersion, TruncateRequest: 1})
+		defer net.SetFaults(nil)
+
+		_, err := api.ClientVersion(ctx)
+		require.Error(t, err)
+	})
+}
+
+func TestShardApiRwMock(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	mock := &ShardApiRwMock{
+		GetTxpoolStatusFunc: func(context.Context) (uint64, error) {
+			return 3, nil
+		},
+	}
+	require.NoError(t, ServeShardApiRw(ctx, NewNetwork().NewManager(), types.MainShardId, mock,
+		internal.RequestHandlersConfig{}))
+
+	status, err := mock.GetTxpoolStatus(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, status)
+	require.Equal(t, []Call{{Method: "GetTxpoolStatus"}}, mock.Calls())
+
+	_, err = mock.GetTxpoolContent(ctx)
+	require.ErrorIs(t, err, ErrNotMocked)
+}
+
+func TestManagerWithoutHost(t *testing.T) {
+	t.Parallel()
+
+	net := NewNetwork()
+	server := net.NewManager()
+	client := net.NewManager()
+	protocol := network.ProtocolID("/rawapitest/echo")
+	server.SetStreamHandler(t.Context(), protocol, func(stream network.Stream) {
+		_, _ = io.Copy(stream, stream)
+	})
+
+	// The functions of the network package requiring a host treat the manager as having none.
+	require.Nil(t, network.TryGetPeerReputationTracker(client))
+
+	stream, err := client.NewStream(t.Context(), server.ID(), protocol)
+	require.NoError(t, err)
+	defer stream.Close()
+
+	conn := stream.Conn()
+	require.Equal(t, client.ID(), conn.LocalPeer())
+	require.Equal(t, server.ID(), conn.RemotePeer())
+	serverKey, err := server.ID().ExtractPublicKey()
+	require.NoError(t, err)
+	require.True(t, serverKey.Equals(conn.RemotePublicKey()))
+	require.Equal(t, p2pnetwork.DirOutbound, stream.Stat().Direction)
+	require.NotNil(t, stream.Scope())
+	require.NotNil(t, conn.Scope())
+	_, err = conn.NewStream(t.Context())
+	require.ErrorIs(t, err, errConnStreams)
+	require.NoError(t, stream.ResetWithError(0))
+}
@@ -0,0 +1,232 @@
+//go:build test
+
+package rawapitest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/internal/network"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	p2pnetwork "github.com/libp2p/go-libp2p/core/network"
+	ma "github.com/multiformats/go-multiaddr"
+)
+
+var streamIds atomic.Uint64
+
+// stream is one side of an in-process stream. The deadlines are ignored and the resources are not accounted.
+type stream struct {
+	id        string
+	protocol  network.ProtocolID
+	conn      *conn
+	direction p2pnetwork.Direction
+	opened    time.Time
+	reader    *io.PipeReader
+	writer    *io.PipeWriter
+
+	// limit is the number of bytes left to read if it is not negative, the rest is discarded.
+	limit   int
+	discard sync.Once
+}
+
+var (
+	_ p2pnetwork.Stream = (*stream)(nil)
+	_ p2pnetwork.Conn   = (*conn)(nil)
+)
+
+var errConnStreams = errors.New("streams are opened by the manager of the network")
+
+// conn is the connection of a stream, it only knows the peers.
+type conn struct {
+	id        string
+	local     network.PeerID
+	remote    network.PeerID
+	direction p2pnetwork.Direction
+	opened    time.Time
+}
+
+func (c *conn) Close() error {
+	return nil
+}
+
+func (c *conn) CloseWithError(p2pnetwork.ConnErrorCode) error {
+	return nil
+}
+
+func (c *conn) ID() string {
+	return c.id
+}
+
+func (c *conn) NewStream(context.Context) (p2pnetwork.Stream, error) {
+	return nil, errConnStreams
+}
+
+func (c *conn) GetStreams() []p2pnetwork.Stream {
+	return nil
+}
+
+func (c *conn) IsClosed() bool {
+	return false
+}
+
+func (c *conn) LocalPeer() network.PeerID {
+	return c.local
+}
+
+func (c *conn) RemotePeer() network.PeerID {
+	return c.remote
+}
+
+// RemotePublicKey returns the key the ID of the remote peer is derived from.
+func (c *conn) RemotePublicKey() crypto.PubKey {
+	key, err := c.remote.ExtractPublicKey()
+	if err != nil {
+		return nil
+	}
+	return key
+}
+
+func (c *conn) ConnState() p2pnetwork.ConnectionState {
+	return p2pnetwork.ConnectionState{}
+}
+
+// LocalMultiaddr returns nil, the peers of the network have no addresses.
+func (c *conn) LocalMultiaddr() ma.Multiaddr {
+	return nil
+}
+
+func (c *conn) RemoteMultiaddr() ma.Multiaddr {
+	return nil
+}
+
+func (c *conn) Stat() p2pnetwork.ConnStats {
+	return p2pnetwork.ConnStats{Stats: p2pnetwork.Stats{Direction: c.direction, Opened: c.opened}, NumStreams: 1}
+}
+
+func (c *conn) Scope() p2pnetwork.ConnScope {
+	return &p2pnetwork.NullScope{}
+}
+
+// newStreamPair returns the sides of a stream opened by the client to the server.
+func newStreamPair(client, server network.PeerID, protocol network.ProtocolID) (*stream, *stream) {
+	id := fmt.Sprintf("rawapitest-%d", streamIds.Add(1))
+	opened := time.Now()
+	requestReader, requestWriter := io.Pipe()
+	responseReader, responseWriter := io.Pipe()
+	return &stream{
+		id:        id,
+		protocol:  protocol,
+		conn:      &conn{id: id, local: client, remote: server, direction: p2pnetwork.DirOutbound, opened: opened},
+		direction: p2pnetwork.DirOutbound,
+		opened:    opened,
+		reader:    responseReader,
+		writer:    requestWriter,
+		limit:     -1,
+	}, &stream{
+		id:        id,
+		protocol:  protocol,
+		conn:      &conn{id: id, local: server, remote: client, direction: p2pnetwork.DirInbound, opened: opened},
+		direction: p2pnetwork.DirInbound,
+		opened:    opened,
+		reader:    requestReader,
+		writer:    responseWriter,
+		limit:     -1,
+	}
+}
+
+// truncateRead makes the stream end after the number of bytes if it is positive,
+// the writer of the other side doesn't notice it.
+func (s *stream) truncateRead(limit int) {
+	if limit > 0 {
+		s.limit = limit
+	}
+}
+
+func (s *stream) Read(p []byte) (int, error) {
+	if s.limit == 0 {
+		s.discard.Do(func() {
+			go func() {
+				_, _ = io.Copy(io.Discard, s.reader)
+			}()
+		})
+		return 0, io.EOF
+	}
+	if s.limit > 0 && len(p) > s.limit {
+		p = p[:s.limit]
+	}
+	n, err := s.reader.Read(p)
+	if s.limit > 0 {
+		s.limit -= n
+	}
+	return n, err
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	return s.writer.Write(p)
+}
+
+func (s *stream) CloseWrite() error {
+	return s.writer.Close()
+}
+
+func (s *stream) CloseRead() error {
+	return s.reader.Close()
+}
+
+func (s *stream) Close() error {
+	_ = s.writer.Close()
+	_ = s.reader.Close()
+	return nil
+}
+
+func (s *stream) Reset() error {
+	s.writer.CloseWithError(p2pnetwork.ErrReset)
+	s.reader.CloseWithError(p2pnetwork.ErrReset)
+	return nil
+}
+
+func (s *stream) ResetWithError(p2pnetwork.StreamErrorCode) error {
+	return s.Reset()
+}
+
+func (s *stream) SetDeadline(time.Time) error {
+	return nil
+}
+
+func (s *stream) SetReadDeadline(time.Time) error {
+	return nil
+}
+
+func (s *stream) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+func (s *stream) ID() string {
+	return s.id
+}
+
+func (s *stream) Protocol() network.ProtocolID {
+	return s.protocol
+}
+
+func (s *stream) SetProtocol(protocol network.ProtocolID) error {
+	s.protocol = protocol
+	return nil
+}
+
+func (s *stream) Conn() p2pnetwork.Conn {
+	return s.conn
+}
+
+func (s *stream) Stat() p2pnetwork.Stats {
+	return p2pnetwork.Stats{Direction: s.direction, Opened: s.opened}
+}
+
+func (s *stream) Scope() p2pnetwork.StreamScope {
+	return &p2pnetwork.NullScope{}
+}
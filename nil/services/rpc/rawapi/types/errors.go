@@ -0,0 +1,206 @@
+package rawapitypes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// ErrorCode is the class of an error returned by a raw API method.
+// The values match the codes of the Protobuf error.
+type ErrorCode uint8
+
+const (
+	UnknownErrorCode ErrorCode = iota
+	InvalidArgumentErrorCode
+	NotFoundErrorCode
+	InternalErrorCode
+	InvalidBlockReferenceErrorCode
+	ShardMismatchErrorCode
+	ExecutionRevertedErrorCode
+	TimeoutErrorCode
+	RateLimitedErrorCode
+	StatePrunedErrorCode
+	UnauthorizedErrorCode
+	OverloadedErrorCode
+	NotModifiedErrorCode
+)
+
+// The errors matching the codes, so that the errors returned by the raw API can be checked with errors.Is.
+var (
+	ErrInvalidArgument       = errors.New("invalid argument")
+	ErrNotFound              = errors.New("not found")
+	ErrInternal              = errors.New("internal error")
+	ErrInvalidBlockReference = errors.New("invalid block reference")
+	ErrShardMismatch         = errors.New("shard mismatch")
+	ErrExecutionReverted     = errors.New("execution reverted")
+	ErrTimeout               = errors.New("timeout")
+	ErrRateLimited           = errors.New("rate limited")
+	ErrStatePruned           = errors.New("state pruned")
+	ErrUnauthorized          = errors.New("unauthorized")
+	ErrOverloaded            = errors.New("overloaded")
+	ErrNotModified           = errors.New("not modified")
+)
+
+// errorCodeSentinels is indexed by the codes, so that the errors wrapping several sentinels are classified
+// the same way every time.
+var errorCodeSentinels = [...]error{
+	InvalidArgumentErrorCode:       ErrInvalidArgument,
+	NotFoundErrorCode:              ErrNotFound,
+	InternalErrorCode:              ErrInternal,
+	InvalidBlockReferenceErrorCode: ErrInvalidBlockReference,
+	ShardMismatchErrorCode:         ErrShardMismatch,
+	ExecutionRevertedErrorCode:     ErrExecutionReverted,
+	TimeoutErrorCode:               ErrTimeout,
+	RateLimitedErrorCode:           ErrRateLimited,
+	StatePrunedErrorCode:           ErrStatePruned,
+	UnauthorizedErrorCode:          ErrUnauthorized,
+	OverloadedErrorCode:            ErrOverloaded,
+	NotModifiedErrorCode:           ErrNotModified,
+}
+
+func (c ErrorCode) String() string {
+	if c == UnknownErrorCode {
+		return "unknown"
+	}
+	if sentinel := c.sentinel(); sentinel != nil {
+		return sentinel.Error()
+	}
+	return fmt.Sprintf("ErrorCode(%d)", c)
+}
+
+func (c ErrorCode) sentinel() error {
+	if int(c) >= len(errorCodeSentinels) {
+		return nil
+	}
+	return errorCodeSentinels[c]
+}
+
+// Error is an error of a raw API method with an explicitly set code.
+// The client reconstructs it from the Protobuf error, so errors.Is matches it with the error of its code.
+type Error struct {
+	Code ErrorCode
+	// IncidentId is set for the internal errors, so that they can be found in the logs of the server.
+	IncidentId string
+	// RevertData is the data returned by the reverted execution.
+	RevertData []byte
+	// EarliestAvailableBlock is the earliest block whose state is kept by the node that pruned the requested one.
+	EarliestAvailableBlock types.BlockNumber
+	// RetryAfter is the time the overloaded node suggests waiting for before retrying the request.
+	RetryAfter time.Duration
+	Err        error
+}
+
+func NewError(code ErrorCode, err error) *Error {
+	return &Error{Code: code, Err: err}
+}
+
+func NewInvalidArgumentError(err error) *Error {
+	return NewError(InvalidArgumentErrorCode, err)
+}
+
+// NewInternalError creates an error that doesn't reveal the details of the failure to the client.
+// The details are expected to be logged with the incident ID.
+func NewInternalError(incidentId string) *Error {
+	return &Error{
+		Code:       InternalErrorCode,
+		IncidentId: incidentId,
+		Err:        fmt.Errorf("internal error, incident %s", incidentId),
+	}
+}
+
+func NewExecutionRevertedError(message string, revertData []byte) *Error {
+	return &Error{Code: ExecutionRevertedErrorCode, RevertData: revertData, Err: errors.New(message)}
+}
+
+// NewStatePrunedError creates an error of a node that doesn't keep the state of the requested block.
+func NewStatePrunedError(blockId, earliestAvailableBlock types.BlockNumber) *Error {
+	return &Error{
+		Code:                   StatePrunedErrorCode,
+		EarliestAvailableBlock: earliestAvailableBlock,
+		Err: fmt.Errorf("%w: state of block %d is not kept, the earliest available block is %d",
+			ErrStatePruned, blockId, earliestAvailableBlock),
+	}
+}
+
+// NewOverloadedError creates an error of a node shedding the requests, the client may retry after the given time.
+func NewOverloadedError(retryAfter time.Duration) *Error {
+	return &Error{
+		Code:       OverloadedErrorCode,
+		RetryAfter: retryAfter,
+		Err:        fmt.Errorf("%w, retry after %s", ErrOverloaded, retryAfter),
+	}
+}
+
+// NewNotModifiedError creates an error of a conditional request whose data is still read at the given block.
+func NewNotModifiedError(blockHash common.Hash) *Error {
+	return &Error{
+		Code: NotModifiedErrorCode,
+		Err:  fmt.Errorf("%w: data is still read at block %s", ErrNotModified, blockHash),
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func (e *Error) Is(target error) bool {
+	sentinel := e.Code.sentinel()
+	return sentinel != nil && target == sentinel
+}
+
+// ErrorCodeOf returns the code of the error. The errors without an explicitly set code are classified
+// by the first of the sentinel errors they wrap in the order of the codes, the rest of them are internal.
+func ErrorCodeOf(err error) ErrorCode {
+	if apiErr := (*Error)(nil); errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	switch {
+	case errors.Is(err, db.ErrKeyNotFound), errors.Is(err, ErrShardNotFound):
+		return NotFoundErrorCode
+	case errors.Is(err, context.DeadlineExceeded):
+		return TimeoutErrorCode
+	}
+	for code, sentinel := range errorCodeSentinels {
+		if sentinel != nil && errors.Is(err, sentinel) {
+			return ErrorCode(code)
+		}
+	}
+	return InternalErrorCode
+}
+
+// RevertData returns the data of the reverted execution the error is caused by.
+func RevertData(err error) ([]byte, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != ExecutionRevertedErrorCode {
+		return nil, false
+	}
+	return apiErr.RevertData, true
+}
+
+// EarliestAvailableBlock returns the earliest block whose state is kept by the node that returned the error.
+func EarliestAvailableBlock(err error) (types.BlockNumber, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != StatePrunedErrorCode {
+		return 0, false
+	}
+	return apiErr.EarliestAvailableBlock, true
+}
+
+// RetryAfter returns the time the overloaded node that returned the error suggests waiting for before retrying.
+func RetryAfter(err error) (time.Duration, bool) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Code != OverloadedErrorCode {
+		return 0, false
+	}
+	return apiErr.RetryAfter, true
+}
@@ -2,11 +2,15 @@ package rawapitypes
 
 import (
 	"errors"
+	"time"
 
 	"github.com/NilFoundation/nil/nil/common"
 	"github.com/NilFoundation/nil/nil/common/assert"
 	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/network"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/txnpool"
+	"github.com/google/uuid"
 )
 
 var ErrShardNotFound = errors.New("shard API not found")
@@ -26,10 +30,16 @@ type BlockNumber uint64
 
 type NamedBlockIdentifier int64
 
+// The named identifiers have the values of the corresponding special block numbers of the RPC.
 const (
 	EarliestBlock = NamedBlockIdentifier(0)
 	LatestBlock   = NamedBlockIdentifier(-1)
 	PendingBlock  = NamedBlockIdentifier(-2)
+	// SafeBlock is the last block committed by the validators of the shard.
+	SafeBlock = NamedBlockIdentifier(-3)
+	// FinalizedBlock is the last block of the shard included in the main chain.
+	// It is the same as SafeBlock for the main shard.
+	FinalizedBlock = NamedBlockIdentifier(-4)
 )
 
 // BlockIdentifier unlike BlockNumber contains special “named” values in the negative range for addressing blocks.
@@ -134,6 +144,60 @@ type ReceiptInfo struct {
 	Temporary       bool
 }
 
+// ShardStats sums up the production of a range of the blocks of a shard, see GetShardStats.
+// The blocks don't record the wall-clock time they are produced at, so the statistics are per block.
+// The rates per second are up to the clients, e.g., from the blocks added between the polls of the statistics.
+type ShardStats struct {
+	// FromBlock and ToBlock are the first and the last blocks of the range, the range is empty if BlockCount is zero.
+	FromBlock  types.BlockNumber
+	ToBlock    types.BlockNumber
+	BlockCount uint64
+	// EmptyBlockCount is the number of the blocks without incoming transactions.
+	EmptyBlockCount uint64
+	GasUsed         types.Gas
+	AverageGasUsed  types.Gas
+	MaxGasUsed      types.Gas
+	// InTransactionCount and OutTransactionCount are the numbers of the transactions executed and sent by the shard.
+	InTransactionCount  uint64
+	OutTransactionCount uint64
+	// CrossShardInCount and CrossShardOutCount count the transactions received from and sent to the other shards.
+	CrossShardInCount  uint64
+	CrossShardOutCount uint64
+}
+
+// BounceInfo describes the funds returned to the senders of a transaction executed by the shard, see GetBounceInfo.
+type BounceInfo struct {
+	Success bool
+	// ErrorMessage is the error the transaction failed with. The failed internal transactions bounce their value,
+	// except for the requests, whose value is returned by the response.
+	ErrorMessage string
+	// Bounced is set if the bounce transaction is sent, it is sent only if there is a value to return.
+	Bounced  bool
+	BounceTo types.Address
+	// BouncedValue is the value returned by the bounce transaction.
+	BouncedValue      types.Value
+	BounceTransaction common.Hash
+	RefundTo          types.Address
+	// Refunded is the total value of the refund transactions, i.e., the fee credit left unused.
+	Refunded           types.Value
+	RefundTransactions []common.Hash
+}
+
+type StorageProof struct {
+	Key          common.Hash
+	Value        types.Uint256
+	ProofEncoded []byte
+}
+
+// ContractProof contains the proofs of the contract and its storage slots against the state root of a block.
+// ContractSSZ is empty if the contract doesn't exist, in which case ProofEncoded proves its absence.
+type ContractProof struct {
+	StateRoot     common.Hash
+	ContractSSZ   []byte
+	ProofEncoded  []byte
+	StorageProofs []StorageProof
+}
+
 type TransactionRequestByBlockRefAndIndex struct {
 	BlockRef BlockReference
 	Index    types.TransactionIndex
@@ -156,3 +220,766 @@ type SmartContract struct {
 	Tokens       map[types.TokenId]types.Value
 	AsyncContext map[types.TransactionIndex]types.AsyncContext
 }
+
+type LogFilter struct {
+	FromBlock types.BlockNumber
+	// ToBlock is the last block of the range (inclusive). The latest block is used if it's nil.
+	ToBlock   *types.BlockNumber
+	Addresses []types.Address
+	// Topics[i] contains alternatives for the i-th topic of a log. An empty list matches any topic.
+	Topics [][]common.Hash
+	// Finalized limits the range to the finalized blocks, see FinalizedBlock.
+	Finalized bool
+}
+
+type LogInfo struct {
+	Log              *types.Log
+	BlockId          types.BlockNumber
+	BlockHash        common.Hash
+	TransactionHash  common.Hash
+	TransactionIndex types.TransactionIndex
+	LogIndex         uint64
+}
+
+type TransactionStatus uint8
+
+const (
+	// TransactionStatusUnknown means that the transaction is neither in a block nor in the pool of the shard.
+	TransactionStatusUnknown TransactionStatus = iota
+	// TransactionStatusPending means that the transaction is in the pool and can be included in the next block.
+	TransactionStatusPending
+	// TransactionStatusQueued means that the transaction is in the pool, but waits for the transaction
+	// with the preceding seqno of the receiver.
+	TransactionStatusQueued
+	// TransactionStatusIncluded means that the transaction is executed successfully in a block of the shard.
+	TransactionStatusIncluded
+	// TransactionStatusFinalized means that the block with the transaction is included in the main chain.
+	TransactionStatusFinalized
+	// TransactionStatusFailed means that the transaction failed the validation or the execution.
+	TransactionStatusFailed
+)
+
+func (s TransactionStatus) String() string {
+	switch s {
+	case TransactionStatusUnknown:
+		return "unknown"
+	case TransactionStatusPending:
+		return "pending"
+	case TransactionStatusQueued:
+		return "queued"
+	case TransactionStatusIncluded:
+		return "included"
+	case TransactionStatusFinalized:
+		return "finalized"
+	case TransactionStatusFailed:
+		return "failed"
+	}
+	return "invalid"
+}
+
+type TransactionStatusInfo struct {
+	Status TransactionStatus
+	// ErrorMessage is only set for the failed transactions.
+	ErrorMessage string
+}
+
+// TransactionCheck is a check of an external transaction done before it's added to the pool.
+type TransactionCheck uint8
+
+const (
+	// TransactionCheckNone means that the transaction passed all the checks.
+	TransactionCheckNone TransactionCheck = iota
+	// TransactionCheckShard means that the transaction is addressed to another shard.
+	TransactionCheckShard
+	TransactionCheckChainId
+	// TransactionCheckDeployPayload means that the payload doesn't match the address of the deployed contract.
+	TransactionCheckDeployPayload
+	// TransactionCheckAccount means that the account doesn't exist or the deployed contract exists already.
+	TransactionCheckAccount
+	// TransactionCheckSeqno means that the account has already processed a transaction with the seqno.
+	TransactionCheckSeqno
+	// TransactionCheckBalance means that the balance of the account doesn't cover the value and the fee credit.
+	TransactionCheckBalance
+	TransactionCheckSignature
+	// TransactionCheckFields means that the fields of the transaction are invalid otherwise, e.g., the max fee is zero.
+	TransactionCheckFields
+)
+
+func (c TransactionCheck) String() string {
+	switch c {
+	case TransactionCheckNone:
+		return "none"
+	case TransactionCheckShard:
+		return "shard"
+	case TransactionCheckChainId:
+		return "chain id"
+	case TransactionCheckDeployPayload:
+		return "deploy payload"
+	case TransactionCheckAccount:
+		return "account"
+	case TransactionCheckSeqno:
+		return "seqno"
+	case TransactionCheckBalance:
+		return "balance"
+	case TransactionCheckSignature:
+		return "signature"
+	case TransactionCheckFields:
+		return "fields"
+	}
+	return "invalid"
+}
+
+// TransactionVerdict is the outcome of the checks of a transaction against the latest state of the shard.
+type TransactionVerdict struct {
+	FailedCheck TransactionCheck
+	// Error is only set if a check failed.
+	Error types.ExecError
+}
+
+func (v TransactionVerdict) Passed() bool {
+	return v.FailedCheck == TransactionCheckNone
+}
+
+// DiscardReason returns the reason reported by the pool for the same failure, if it checks it too.
+func (v TransactionVerdict) DiscardReason() txnpool.DiscardReason {
+	switch v.FailedCheck {
+	case TransactionCheckNone:
+		return txnpool.NotSet
+	case TransactionCheckChainId:
+		return txnpool.InvalidChainId
+	case TransactionCheckSeqno:
+		return txnpool.SeqnoTooLow
+	}
+	return txnpool.Unverified
+}
+
+// SendTransactionResult is returned for a transaction before it's admitted to the pool of the shard.
+type SendTransactionResult struct {
+	Hash    common.Hash
+	ShardId types.ShardId
+	Verdict TransactionVerdict
+	// DiscardReason is the one of the verdict for the transactions rejected by the checks,
+	// otherwise it's the result of adding the transaction to the pool.
+	DiscardReason txnpool.DiscardReason
+}
+
+// TransactionEventType is a stage of the lifecycle of a sent transaction.
+type TransactionEventType uint8
+
+const (
+	// TransactionEventSubmitted is the first event, it contains the result of sending the transaction.
+	TransactionEventSubmitted TransactionEventType = iota
+	// TransactionEventIncluded means that the transaction is executed in a block of the shard.
+	TransactionEventIncluded
+	// TransactionEventFinalized means that the block with the transaction is included in the main chain.
+	TransactionEventFinalized
+	// TransactionEventDropped means that the transaction is not admitted to the pool or is evicted from it.
+	TransactionEventDropped
+)
+
+func (t TransactionEventType) String() string {
+	switch t {
+	case TransactionEventSubmitted:
+		return "submitted"
+	case TransactionEventIncluded:
+		return "included"
+	case TransactionEventFinalized:
+		return "finalized"
+	case TransactionEventDropped:
+		return "dropped"
+	}
+	return "invalid"
+}
+
+// TransactionEvent is sent to the sender of a transaction once it reaches the next stage of its lifecycle.
+// The finalized and dropped events are the last ones.
+type TransactionEvent struct {
+	Type TransactionEventType
+	// Result is only set for the submitted event.
+	Result *SendTransactionResult
+	// BlockNumber and BlockHash are set for the included and finalized events.
+	BlockNumber types.BlockNumber
+	BlockHash   common.Hash
+	// ErrorMessage is set for the transactions failed the execution and for the dropped ones if the reason is known.
+	ErrorMessage string
+}
+
+// PoolContent contains the transactions of the pool sorted by receiver and seqno.
+type PoolContent struct {
+	// Pending transactions can be included in the next block.
+	Pending []*types.Transaction
+	// Queued transactions wait for the transaction with the preceding seqno of their receiver.
+	Queued []*types.Transaction
+}
+
+type PoolStatus struct {
+	Pending uint64
+	Queued  uint64
+	BaseFee types.Value
+}
+
+type PendingTransaction struct {
+	Hash common.Hash
+	// TransactionSSZ is only set if full transactions are requested.
+	TransactionSSZ []byte
+}
+
+// FeeEstimation is the result of the fee estimation of a call.
+type FeeEstimation struct {
+	// FeeCredit is the recommended fee credit of the transaction. It's the sum of the fees below and the transferred
+	// value.
+	FeeCredit types.Value
+	// ExecutionFee is the minimal fee credit the transaction succeeds with on the destination shard.
+	ExecutionFee types.Value
+	// ForwardingFee is the fee of the transactions sent to other shards, including the ones they send in turn.
+	ForwardingFee types.Value
+	// VerificationFee is the fee of the verification of an external transaction by the smart account.
+	VerificationFee types.Value
+	BaseFee         types.Value
+	// MaxBaseFee is the highest base fee among the shards involved in the execution.
+	MaxBaseFee types.Value
+}
+
+// DeployRequest describes the external deployment of a contract, see PrepareDeploy.
+type DeployRequest struct {
+	ShardId types.ShardId
+	// Code is the bytecode of the contract followed by the arguments of its constructor.
+	Code types.Code
+	Salt common.Hash
+	// ValidateConstructor fails the preparation if the constructor fails in the simulation of the deployment.
+	ValidateConstructor bool
+}
+
+// DeployPreparation is what a wallet needs to build the external deploy transaction of a contract.
+type DeployPreparation struct {
+	Address types.Address
+	// Payload is the data of the deploy transaction, the code followed by the salt.
+	Payload []byte
+	// Deployed is set if there is a contract at the address already, the deployment fails then.
+	Deployed bool
+	// Balance is the balance of the address. The fee of the external deployment is paid from it,
+	// so the address has to be funded before the deployment.
+	Balance types.Value
+	// Fee is nil if the deployment isn't simulated, i.e., if the contract is deployed or the simulation fails.
+	Fee *FeeEstimation
+	// SimulationError is the error the simulation failed with if the constructor isn't validated.
+	SimulationError string
+	// RevertData is the data the constructor reverted with in the simulation, if any.
+	RevertData []byte
+}
+
+// AccessTuple contains the storage keys of an account accessed by a call.
+type AccessTuple struct {
+	Address     types.Address
+	StorageKeys []common.Hash
+}
+
+type AccessListResult struct {
+	// AccessList contains the accounts touched by the call and its outgoing transactions, sorted by address.
+	AccessList []AccessTuple
+	// Error is the execution error of the call or one of its outgoing transactions.
+	// The access list collected up to the failure is returned along with it.
+	Error string
+}
+
+// OpcodeStep is the state of the EVM before the execution of an opcode.
+type OpcodeStep struct {
+	Pc      uint64
+	Op      string
+	Gas     uint64
+	GasCost uint64
+	Depth   int
+	// Stack contains the stack items, the top of the stack is the last one.
+	Stack []types.Uint256
+	Error string
+}
+
+// CallFrame is a node of the call tree of an EVM execution.
+type CallFrame struct {
+	// Type is the opcode that created the frame, e.g. CALL or CREATE2. The root frame is CALL or CREATE.
+	Type    string
+	From    types.Address
+	To      types.Address
+	Input   []byte
+	Output  []byte
+	Gas     uint64
+	GasUsed uint64
+	Value   types.Value
+	Error   string
+	Calls   []*CallFrame
+}
+
+// ExecutionTrace is the trace of a transaction execution on its destination shard.
+// The outgoing transactions are executed separately and are not included.
+type ExecutionTrace struct {
+	Steps []OpcodeStep
+	// StepsTruncated is set if the execution made more steps than a trace can hold.
+	StepsTruncated bool
+	// Call is the root of the call tree. It is nil if the transaction failed before the EVM execution.
+	Call    *CallFrame
+	GasUsed types.Gas
+	Error   string
+}
+
+type BalanceDiff struct {
+	Pre  types.Value
+	Post types.Value
+}
+
+type StorageDiff struct {
+	Key  common.Hash
+	Pre  common.Hash
+	Post common.Hash
+}
+
+// AccountDiff contains the changes of an account made by a transaction.
+type AccountDiff struct {
+	Address types.Address
+	// Deleted is set if the account is deleted, the storage of the deleted account is not listed.
+	Deleted bool
+	// Balance is nil if the balance is not changed.
+	Balance *BalanceDiff
+	// Storage contains the modified slots sorted by key.
+	Storage []StorageDiff
+}
+
+// StateDiff contains the balances and the storage slots modified by a transaction on its destination shard.
+type StateDiff struct {
+	// Accounts are sorted by address.
+	Accounts []AccountDiff
+	Error    string
+}
+
+// ReplayOptions select the parts of the trace collected by ReplayTransaction.
+type ReplayOptions struct {
+	// DisableSteps skips the opcode steps, which make up most of the trace.
+	DisableSteps bool
+	// DisableCallTree skips the tree of the calls.
+	DisableCallTree bool
+}
+
+// ReplayResult is the result of the re-execution of a transaction included in a block.
+type ReplayResult struct {
+	ReturnData []byte
+	Logs       []*types.Log
+	Trace      *ExecutionTrace
+	// Mismatches are the fields of the stored receipt the re-execution doesn't reproduce.
+	// It is empty if the receipt matches.
+	Mismatches []string
+}
+
+// PendingResponse is an async request sent by a contract whose response the contract hasn't processed yet.
+type PendingResponse struct {
+	RequestId types.TransactionIndex
+	// ResponseProcessingGas is the gas reserved by the request for the processing of its response.
+	ResponseProcessingGas types.Gas
+}
+
+// PendingResponses contains a page of the pending responses of a contract in the order of the keys of its async
+// context trie, the request IDs are encoded in little-endian so it isn't the order of the IDs.
+type PendingResponses struct {
+	Responses []*PendingResponse
+	// Next is the start of the next page, it is nil if the page is the last one.
+	Next *types.TransactionIndex
+}
+
+// AwaitedRequest is an async request whose response is delivered to its caller after a transaction.
+type AwaitedRequest struct {
+	Caller    types.Address
+	RequestId uint64
+	// Pending is set if the caller still awaits the response at the latest block of its shard.
+	Pending bool
+}
+
+// AwaitingContext describes the async requests a transaction takes part in, see GetAwaitingContext.
+type AwaitingContext struct {
+	Flags types.TransactionFlags
+	From  types.Address
+	To    types.Address
+	// Received is set if the transaction is executed by the shard, otherwise it is sent from the shard to another one.
+	Received bool
+	// Requests are in the order their responses are delivered: the request the transaction is or responds to
+	// goes first, then the requests of its chain. It is empty if the transaction is neither a request nor a response.
+	Requests []AwaitedRequest
+}
+
+// TokensRequest selects the tokens of an account returned by GetTokens.
+type TokensRequest struct {
+	// Start is the least token included in the page, the page starts with the first token if it is nil.
+	Start *types.TokenId
+	// Limit is the maximal number of tokens in the page, up to 1024 tokens are returned if it is zero.
+	Limit uint64
+	// TokenIds restricts the page to the listed tokens, the ones the account doesn't hold are omitted.
+	TokenIds []types.TokenId
+}
+
+// TokensPage contains the balances of a range of the tokens of an account in the order of their IDs.
+type TokensPage struct {
+	Tokens map[types.TokenId]types.Value
+	// Next is the start of the next page, it is nil if the page is the last one.
+	Next *types.TokenId
+}
+
+// BundleCallResult is the result of a call of a bundle executed on the state left by the previous calls.
+type BundleCallResult struct {
+	Data      []byte
+	CoinsUsed types.Value
+	GasUsed   types.Gas
+	Logs      []*types.Log
+	// OutTransactions are the SSZ-encoded transactions sent by the call, they are not executed.
+	OutTransactions [][]byte
+	Error           string
+}
+
+type StorageEntry struct {
+	Key   common.Hash
+	Value types.Uint256
+}
+
+// StorageRange contains a range of the storage of a contract in the order of the keys.
+type StorageRange struct {
+	Entries []StorageEntry
+	// Next is the start of the next range, it is nil if the range is the last one.
+	Next *common.Hash
+}
+
+// TokenInfo is the metadata of a token read from the contract minting it.
+type TokenInfo struct {
+	Id   types.TokenId
+	Name string
+	// Symbol is the ticker of the well-known tokens, it is empty for the rest of them.
+	Symbol string
+	// Decimals is the number of the decimals of the amounts of the well-known tokens, the rest are indivisible.
+	Decimals uint8
+	// Owner is the contract minting the token, its address is the ID of the token.
+	Owner       types.Address
+	TotalSupply types.Value
+}
+
+// AccountMeta describes an account without reading its code and tries.
+type AccountMeta struct {
+	Exists bool
+	// IsSmartAccount is set if the code of the account dispatches all the methods of the smart account ABI.
+	IsSmartAccount bool
+	CodeHash       common.Hash
+	StorageRoot    common.Hash
+	ExtSeqno       types.Seqno
+}
+
+// SnapshotManifest describes the snapshot of the state of a shard at a block.
+type SnapshotManifest struct {
+	BlockSSZ  []byte
+	BlockHash common.Hash
+	// ChildBlocks are the blocks of the other shards included in the block of the main shard.
+	ChildBlocks []common.Hash
+	// Config contains the raw entries of the config trie of the block.
+	Config map[string][]byte
+}
+
+type SnapshotChunkRequest struct {
+	BlockHash common.Hash
+	// Start is the least key of the contract trie included in the chunk.
+	Start common.Hash
+	// Limit is the maximal number of contracts in the chunk, the default one is used if it is zero.
+	Limit uint64
+}
+
+// SnapshotChunk contains the contracts of a range of the contract trie in the order of their keys.
+type SnapshotChunk struct {
+	Contracts []*SmartContract
+	// Next is the start of the next chunk, it is nil if the chunk is the last one.
+	Next *common.Hash
+}
+
+// PeerAcl maps a protocol ID (e.g., "/shard/1/rawapi_rw/SendTransaction") or a method name (e.g., "SendTransaction")
+// to the peers allowed to call the method. The entries of the protocol ID take precedence,
+// the methods without an entry can be called by any peer.
+type PeerAcl map[string][]network.PeerID
+
+// ShardConfig contains the parameters of a shard in the latest config.
+type ShardConfig struct {
+	Id       types.ShardId
+	GasPrice types.Value
+	// Validators is the number of the validators of the shard, the main shard is validated by all of them.
+	Validators uint64
+}
+
+// ChainConfig describes the network the node belongs to, so that the clients don't hardcode its constants.
+type ChainConfig struct {
+	ChainId types.ChainId
+	// ProtocolVersion is the version of the network protocol, it is empty if the node is not connected to a network.
+	ProtocolVersion string
+	// GenesisHash is the hash of the genesis block of the main shard.
+	GenesisHash common.Hash
+	// PatchLevel is the patch level of the latest block of the main shard, which enables the features up to it.
+	PatchLevel uint32
+	// Shards are sorted by ID and include the main shard.
+	Shards []ShardConfig
+}
+
+type SyncPhase uint8
+
+const (
+	// SyncPhaseNone means that the shard is not synchronized with the network, e.g., the node has no peers to follow.
+	SyncPhaseNone SyncPhase = iota
+	// SyncPhaseDownloading means that the snapshot of the state is being downloaded from a peer.
+	SyncPhaseDownloading
+	// SyncPhaseReplaying means that the missed blocks are being fetched from the peers and replayed.
+	SyncPhaseReplaying
+	// SyncPhaseFollowing means that the node is up to date and replays the blocks as they are announced.
+	SyncPhaseFollowing
+)
+
+func (p SyncPhase) String() string {
+	switch p {
+	case SyncPhaseNone:
+		return "none"
+	case SyncPhaseDownloading:
+		return "downloading"
+	case SyncPhaseReplaying:
+		return "replaying"
+	case SyncPhaseFollowing:
+		return "following"
+	}
+	return "invalid"
+}
+
+// SyncProgress is reported by the synchronization of a shard.
+type SyncProgress struct {
+	Phase SyncPhase
+	// HighestKnownBlock is the number of the highest valid block of the shard received from the peers.
+	HighestKnownBlock types.BlockNumber
+	// Peers is the number of the connected peers serving the blocks of the shard.
+	Peers uint64
+}
+
+// SyncStatus describes how far the node is behind the network in a shard.
+type SyncStatus struct {
+	CurrentBlock types.BlockNumber
+	// HighestKnownBlock is never less than CurrentBlock.
+	HighestKnownBlock types.BlockNumber
+	Phase             SyncPhase
+	Peers             uint64
+}
+
+// ShardHealth is the health of a shard followed by the node.
+type ShardHealth struct {
+	ShardId types.ShardId
+	// Status is nil if it can't be obtained, Error is set in that case.
+	Status  *SyncStatus
+	Error   string
+	Healthy bool
+}
+
+// NodeHealth aggregates the health of the shards followed by the node, which is healthy if all of them are.
+type NodeHealth struct {
+	Healthy bool
+	// Shards are sorted by ID.
+	Shards []ShardHealth
+}
+
+// FinalitySignatures is the proof of the commitment of a block by the validators of its shard,
+// which can be checked without trusting the node serving it.
+type FinalitySignatures struct {
+	BlockHash     common.Hash
+	BlockNumber   types.BlockNumber
+	Round         uint64
+	ProposerIndex uint64
+	// Signature is the aggregate BLS signature of the block hash, it is empty for the genesis block.
+	Signature []byte
+	// SignersMask has the bits of the validators that signed the block set.
+	SignersMask []byte
+	// PublicKeys are the BLS public keys of the validators of the block in the order of the bits of the mask.
+	PublicKeys [][]byte
+	Signers    uint64
+	// Quorum is the number of the signers required to commit the block, all the validators have the same voting power.
+	Quorum uint64
+}
+
+// ValidatorInfo is a validator of a shard. The validators don't stake, all of them have the same voting power.
+type ValidatorInfo struct {
+	// Index is the position of the validator in the order of the bits of the signature masks.
+	Index             uint64
+	PublicKey         []byte
+	WithdrawalAddress types.Address
+	// Weight is the voting power of the validator.
+	Weight uint64
+}
+
+// ValidatorSet is the set of the validators of a block, see GetValidators.
+type ValidatorSet struct {
+	BlockHash   common.Hash
+	BlockNumber types.BlockNumber
+	Validators  []ValidatorInfo
+	TotalWeight uint64
+	// Quorum is the total weight of the signers required to commit the block.
+	Quorum uint64
+}
+
+// EpochInfo describes the rotation of the proposers of a shard after its latest block. There are no epochs,
+// the validators are changed by the config and the proposer rotates in the order of the validators every block.
+type EpochInfo struct {
+	BlockNumber   types.BlockNumber
+	Round         uint64
+	ProposerIndex uint64
+	// ValidatorCount is the number of the validators of the next blocks.
+	ValidatorCount uint64
+	// ValidatorSetChanged is set if the validators of the next blocks differ from the ones of the latest block.
+	ValidatorSetChanged bool
+	// NextProposers are the indexes of the proposers of the next block by its round over a full rotation,
+	// i.e., the proposer of the round r is NextProposers[r % ValidatorCount]. The proposers of the blocks after it
+	// rotate from the one committing it, so they are the following ones if it is committed in the first round.
+	NextProposers []uint64
+}
+
+// ConsensusState is the state of the consensus of a shard on the block being committed. The votes are
+// the public keys of the validators whose messages are received, they are not validated yet.
+type ConsensusState struct {
+	Height types.BlockNumber
+	Round  uint64
+	// Step is the state of the round, one of "new round", "prepare", "commit" and "fin".
+	Step              string
+	ProposerIndex     uint64
+	ProposerPublicKey []byte
+	// ProposalHash is the hash of the proposal accepted in the round, empty if there is none yet.
+	ProposalHash []byte
+	Prepares     [][]byte
+	Commits      [][]byte
+	// RoundChanges are the votes for RoundChangeRound, the next round with the most of them.
+	RoundChangeRound uint64
+	RoundChanges     [][]byte
+}
+
+// RoundTimeout is an expiration of the timer of a round of the consensus of a shard.
+type RoundTimeout struct {
+	Height types.BlockNumber
+	Round  uint64
+	// Step is the state the round was in when it expired.
+	Step string
+	Time time.Time
+}
+
+type ProofTaskStatus uint8
+
+const (
+	// ProofTaskStatusPending means that the task waits for its input or for a prover to execute it.
+	ProofTaskStatusPending ProofTaskStatus = iota
+	ProofTaskStatusRunning
+	ProofTaskStatusCompleted
+	ProofTaskStatusFailed
+)
+
+func (s ProofTaskStatus) String() string {
+	switch s {
+	case ProofTaskStatusPending:
+		return "pending"
+	case ProofTaskStatusRunning:
+		return "running"
+	case ProofTaskStatusCompleted:
+		return "completed"
+	case ProofTaskStatusFailed:
+		return "failed"
+	}
+	return "invalid"
+}
+
+// ProofTaskInfo is the state of the generation of the proof of a main shard block.
+type ProofTaskInfo struct {
+	Id     uuid.UUID
+	Status ProofTaskStatus
+	// BlockHash is the hash of the main shard block the proof is generated for.
+	BlockHash common.Hash
+	// Error describes the failure of the task, it is set only if the task has failed.
+	Error string
+}
+
+// StateProof is the zk proof of the state transition made by a main shard block.
+type StateProof struct {
+	BlockHash   common.Hash
+	BlockNumber types.BlockNumber
+	// TaskId is the ID of the task that has generated the proof.
+	TaskId uuid.UUID
+	Proof  []byte
+}
+
+// ShardResolution is the shard owning the address. Error is set instead if the address is malformed,
+// i.e., it is empty or its shard is not in the network.
+type ShardResolution struct {
+	Address types.Address
+	ShardId types.ShardId
+	Error   error
+}
+
+// ShardTransactionCount is the number of the transactions sent by the account of the shard.
+type ShardTransactionCount struct {
+	ShardId types.ShardId
+	Address types.Address
+	Count   uint64
+}
+
+// FamilyTransactionCount is the number of the transactions sent by the accounts of an address family,
+// i.e., the addresses differing in the shard ID only.
+type FamilyTransactionCount struct {
+	Total uint64
+	// Shards are sorted by ID.
+	Shards []ShardTransactionCount
+}
+
+// ShardBlockHead is the latest block of a shard.
+type ShardBlockHead struct {
+	ShardId     types.ShardId
+	BlockNumber types.BlockNumber
+	BlockHash   common.Hash
+}
+
+// ShardGasPrice is the gas price of a shard.
+type ShardGasPrice struct {
+	ShardId  types.ShardId
+	GasPrice types.Value
+}
+
+// GasPriceStats summarizes the gas prices of all the shards of the network.
+type GasPriceStats struct {
+	Min    types.Value
+	Max    types.Value
+	Mean   types.Value
+	Median types.Value
+	// Shards are sorted by ID.
+	Shards []ShardGasPrice
+}
+
+// PeerUsage is the usage of the raw API of a node by a peer since the node started accounting it.
+type PeerUsage struct {
+	PeerId   network.PeerID
+	Requests uint64
+	// RequestBytes and ResponseBytes are the sizes of the payloads of the requests and the responses.
+	RequestBytes  uint64
+	ResponseBytes uint64
+	// CallTime is the CPU time spent executing the calls of the peer, e.g., of Call and EstimateFee.
+	CallTime time.Duration
+	// Bans is the number of times the peer exceeded its quota.
+	Bans uint64
+	// BannedUntil is the end of the current ban of the peer, it is zero if the peer is not banned.
+	BannedUntil time.Time
+}
+
+// FaucetLimits are the limits of the funds the faucet API of a node sends.
+type FaucetLimits struct {
+	// Faucet is the address of the faucet the funds are sent from.
+	Faucet types.Address
+	// MaxAmount limits the amount of a request.
+	MaxAmount types.Value
+	// RequesterQuota limits the amount sent to the requests of a signer or of a peer within the window.
+	RequesterQuota types.Value
+	Window         time.Duration
+}
+
+// ChainSnapshot is the state of the chain of a shard of a single-node devnet the shard can be reset to.
+type ChainSnapshot struct {
+	BlockHash   common.Hash
+	BlockNumber types.BlockNumber
+	// CollatorState is the progress of the shard in the transactions of its neighbors.
+	CollatorState types.CollatorState
+	// TimeOffset is added to the time the blocks of the shard are collated with.
+	TimeOffset uint64
+}
@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+)
+
+const openApiVersion = "3.0.3"
+
+type openApiSpec struct {
+	OpenApi string                                 `json:"openapi"`
+	Info    openApiInfo                            `json:"info"`
+	Paths   map[string]map[string]openApiOperation `json:"paths"`
+}
+
+type openApiInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openApiOperation struct {
+	OperationId string                     `json:"operationId"`
+	Summary     string                     `json:"summary"`
+	Parameters  []openApiParameter         `json:"parameters,omitempty"`
+	Responses   map[string]openApiResponse `json:"responses"`
+}
+
+type openApiParameter struct {
+	Name        string        `json:"name"`
+	In          string        `json:"in"`
+	Description string        `json:"description"`
+	Required    bool          `json:"required"`
+	Schema      openApiSchema `json:"schema"`
+}
+
+type openApiSchema struct {
+	Type string `json:"type"`
+}
+
+type openApiResponse struct {
+	Description string `json:"description"`
+}
+
+// newOpenApiSpec describes the routes, the path parameters are required and the query ones are optional.
+func newOpenApiSpec(routes []route) *openApiSpec {
+	spec := &openApiSpec{
+		OpenApi: openApiVersion,
+		Info:    openApiInfo{Title: "=nil; REST gateway", Version: "1.0"},
+		Paths:   make(map[string]map[string]openApiOperation, len(routes)),
+	}
+	for _, route := range routes {
+		operation := openApiOperation{
+			OperationId: route.name,
+			Summary:     route.summary,
+			Responses:   make(map[string]openApiResponse, len(routeResponses)),
+		}
+		for _, p := range route.params {
+			operation.Parameters = append(operation.Parameters, openApiParameter{
+				Name:        p.name,
+				In:          p.in,
+				Description: p.description,
+				Required:    p.in == "path",
+				Schema:      openApiSchema{Type: p.schemaType},
+			})
+		}
+		for _, status := range routeResponses {
+			operation.Responses[strconv.Itoa(status)] = openApiResponse{Description: http.StatusText(status)}
+		}
+		spec.Paths[route.pattern] = map[string]openApiOperation{"get": operation}
+	}
+	return spec
+}
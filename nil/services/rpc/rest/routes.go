@@ -0,0 +1,211 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+)
+
+var (
+	errInvalidParameter = errors.New("invalid parameter")
+	errNotFound         = errors.New("not found")
+)
+
+// param describes a path or query parameter of a route for the OpenAPI specification.
+type param struct {
+	name        string
+	in          string // "path" or "query"
+	schemaType  string
+	description string
+}
+
+var (
+	shardIdParam  = param{"shardId", "path", "integer", "The ID of the shard."}
+	blockRefParam = param{
+		"ref", "path", "string", "The number of the block, its hash or a tag (latest, earliest, finalized, safe).",
+	}
+	addressParam   = param{"address", "path", "string", "The address of the account or contract."}
+	txnHashParam   = param{"hash", "path", "string", "The hash of the transaction."}
+	fullTxParam    = param{"fullTx", "query", "boolean", "Whether the full transactions are returned (false by default)."}
+	blockTagParam  = param{"block", "query", "string", "The number, hash or tag of the block (latest by default)."}
+	routeResponses = []int{http.StatusOK, http.StatusBadRequest, http.StatusNotFound}
+)
+
+// route maps a REST resource to the read-only API.
+type route struct {
+	name    string // operation ID
+	pattern string
+	summary string
+	params  []param
+	handle  func(api jsonrpc.EthAPIRo, r *http.Request) (any, error)
+}
+
+var routes = []route{
+	{
+		name:    "getShards",
+		pattern: "/shards",
+		summary: "Returns the IDs of all shards.",
+		handle: func(api jsonrpc.EthAPIRo, r *http.Request) (any, error) {
+			return api.GetShardIdList(r.Context())
+		},
+	},
+	{
+		name:    "getBlock",
+		pattern: "/shards/{shardId}/blocks/{ref}",
+		summary: "Returns the block of the shard.",
+		params:  []param{shardIdParam, blockRefParam, fullTxParam},
+		handle: func(api jsonrpc.EthAPIRo, r *http.Request) (any, error) {
+			shardId, err := parseShardId(r.PathValue(shardIdParam.name))
+			if err != nil {
+				return nil, err
+			}
+			ref, err := parseBlockReference(r.PathValue(blockRefParam.name))
+			if err != nil {
+				return nil, err
+			}
+			fullTx, err := parseOptionalBool(r.URL.Query().Get(fullTxParam.name))
+			if err != nil {
+				return nil, err
+			}
+
+			if hash, ok := ref.Hash(); ok {
+				if types.ShardIdFromHash(hash) != shardId {
+					return nil, fmt.Errorf("%w: block %s is not in shard %d", errInvalidParameter, hash, shardId)
+				}
+				return found(api.GetBlockByHash(r.Context(), hash, fullTx))
+			}
+			number, _ := ref.Number()
+			return found(api.GetBlockByNumber(r.Context(), shardId, number, fullTx))
+		},
+	},
+	{
+		name:    "getBalance",
+		pattern: "/accounts/{address}/balance",
+		summary: "Returns the balance of the account.",
+		params:  []param{addressParam, blockTagParam},
+		handle: func(api jsonrpc.EthAPIRo, r *http.Request) (any, error) {
+			address, ref, err := parseAccountRequest(r)
+			if err != nil {
+				return nil, err
+			}
+			return found(api.GetBalance(r.Context(), address, ref))
+		},
+	},
+	{
+		name:    "getCode",
+		pattern: "/accounts/{address}/code",
+		summary: "Returns the bytecode of the contract.",
+		params:  []param{addressParam, blockTagParam},
+		handle: func(api jsonrpc.EthAPIRo, r *http.Request) (any, error) {
+			address, ref, err := parseAccountRequest(r)
+			if err != nil {
+				return nil, err
+			}
+			return api.GetCode(r.Context(), address, ref)
+		},
+	},
+	{
+		name:    "getSeqno",
+		pattern: "/accounts/{address}/seqno",
+		summary: "Returns the transaction count (seqno) of the account.",
+		params:  []param{addressParam, blockTagParam},
+		handle: func(api jsonrpc.EthAPIRo, r *http.Request) (any, error) {
+			address, ref, err := parseAccountRequest(r)
+			if err != nil {
+				return nil, err
+			}
+			return api.GetTransactionCount(r.Context(), address, ref)
+		},
+	},
+	{
+		name:    "getTransaction",
+		pattern: "/transactions/{hash}",
+		summary: "Returns the transaction.",
+		params:  []param{txnHashParam},
+		handle: func(api jsonrpc.EthAPIRo, r *http.Request) (any, error) {
+			hash, err := parseHash(r.PathValue(txnHashParam.name))
+			if err != nil {
+				return nil, err
+			}
+			return found(api.GetInTransactionByHash(r.Context(), hash))
+		},
+	},
+	{
+		name:    "getReceipt",
+		pattern: "/transactions/{hash}/receipt",
+		summary: "Returns the receipt of the transaction.",
+		params:  []param{txnHashParam},
+		handle: func(api jsonrpc.EthAPIRo, r *http.Request) (any, error) {
+			hash, err := parseHash(r.PathValue(txnHashParam.name))
+			if err != nil {
+				return nil, err
+			}
+			return found(api.GetInTransactionReceipt(r.Context(), hash))
+		},
+	},
+}
+
+// found converts the nil results to errNotFound, so they aren't returned as null.
+func found[T any](result *T, err error) (any, error) {
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, errNotFound
+	}
+	return result, nil
+}
+
+func parseShardId(value string) (types.ShardId, error) {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: shard id %q", errInvalidParameter, value)
+	}
+	return types.ShardId(id), nil
+}
+
+func parseBlockReference(value string) (transport.BlockNumberOrHash, error) {
+	if value == "" {
+		return transport.BlockNumberOrHash(transport.LatestBlock), nil
+	}
+	var ref transport.BlockNumberOrHash
+	if err := ref.UnmarshalJSON([]byte(value)); err != nil {
+		return ref, fmt.Errorf("%w: block %q", errInvalidParameter, value)
+	}
+	return ref, nil
+}
+
+func parseOptionalBool(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	result, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%w: %q is not a boolean", errInvalidParameter, value)
+	}
+	return result, nil
+}
+
+func parseHash(value string) (common.Hash, error) {
+	var hash common.Hash
+	if err := hash.Set(value); err != nil {
+		return hash, fmt.Errorf("%w: hash %q", errInvalidParameter, value)
+	}
+	return hash, nil
+}
+
+func parseAccountRequest(r *http.Request) (types.Address, transport.BlockNumberOrHash, error) {
+	var address types.Address
+	if err := address.Set(r.PathValue(addressParam.name)); err != nil {
+		return address, transport.BlockNumberOrHash{}, fmt.Errorf(
+			"%w: address %q", errInvalidParameter, r.PathValue(addressParam.name))
+	}
+	ref, err := parseBlockReference(r.URL.Query().Get(blockTagParam.name))
+	return address, ref, err
+}
@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+)
+
+// SpecPath is the path of the OpenAPI specification of the gateway.
+const SpecPath = "/openapi.json"
+
+var defaultTimeout = 30 * time.Second
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewHandler returns the handler serving the read-only REST resources over the API
+// along with their OpenAPI specification.
+func NewHandler(api jsonrpc.EthAPIRo, logger logging.Logger) http.Handler {
+	mux := http.NewServeMux()
+	for _, route := range routes {
+		mux.HandleFunc(http.MethodGet+" "+route.pattern, func(w http.ResponseWriter, r *http.Request) {
+			result, err := route.handle(api, r)
+			if err != nil {
+				status := errorStatus(err)
+				if status == http.StatusInternalServerError {
+					logger.Error().Err(err).Str("path", r.URL.Path).Msg("Failed to serve REST request")
+				}
+				writeJson(w, status, &errorResponse{Error: err.Error()})
+				return
+			}
+			writeJson(w, http.StatusOK, result)
+		})
+	}
+
+	spec := newOpenApiSpec(routes)
+	mux.HandleFunc(http.MethodGet+" "+SpecPath, func(w http.ResponseWriter, r *http.Request) {
+		writeJson(w, http.StatusOK, spec)
+	})
+	return mux
+}
+
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errInvalidParameter):
+		return http.StatusBadRequest
+	case errors.Is(err, errNotFound), errors.Is(err, db.ErrKeyNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func writeJson(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// StartServer serves the handler of the REST gateway made by NewHandler on the given address until ctx is done.
+// The handler may serve the other read-only endpoints too, e.g., GraphQL.
+func StartServer(ctx context.Context, addr string, handler http.Handler, logger logging.Logger) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := http.Server{Handler: handler, ReadHeaderTimeout: defaultTimeout}
+	defer func() { //nolint:contextcheck
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		logger.Info().Msg("Stopping REST gateway...")
+		_ = srv.Shutdown(shutdownCtx)
+		logger.Info().Msg("Stopped REST gateway")
+	}()
+
+	go func() {
+		logger.Info().Msgf("Serving REST gateway at `%s`", listener.Addr())
+		_ = srv.Serve(listener)
+	}()
+
+	<-ctx.Done()
+	return nil
+}
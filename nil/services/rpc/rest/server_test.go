@@ -0,0 +1,124 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/hexutil"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/db"
+	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/services/rpc/jsonrpc"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport"
+	"github.com/stretchr/testify/require"
+)
+
+// testApi implements the methods used by the tests, the others panic.
+type testApi struct {
+	jsonrpc.EthAPIRo
+}
+
+func (testApi) GetShardIdList(context.Context) ([]types.ShardId, error) {
+	return []types.ShardId{1, 2}, nil
+}
+
+func (testApi) GetBlockByNumber(
+	_ context.Context, shardId types.ShardId, number transport.BlockNumber, _ bool,
+) (*jsonrpc.RPCBlock, error) {
+	if number == transport.LatestBlockNumber {
+		number = 7
+	}
+	return &jsonrpc.RPCBlock{Number: types.BlockNumber(number), ShardId: shardId}, nil
+}
+
+func (testApi) GetBalance(
+	_ context.Context, address types.Address, ref transport.BlockNumberOrHash,
+) (*hexutil.Big, error) {
+	if number, ok := ref.Number(); !ok || number != transport.LatestBlockNumber {
+		return nil, db.ErrKeyNotFound
+	}
+	return hexutil.NewBigFromInt64(int64(address.ShardId())), nil
+}
+
+func (testApi) GetInTransactionReceipt(context.Context, common.Hash) (*jsonrpc.RPCReceipt, error) {
+	return nil, nil
+}
+
+func get(t *testing.T, handler http.Handler, path string) (int, []byte) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder.Code, recorder.Body.Bytes()
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	handler := NewHandler(testApi{}, logging.NewLogger("Test"))
+	address := types.ShardAndHexToAddress(2, "0x11")
+
+	t.Run("Shards", func(t *testing.T) {
+		code, body := get(t, handler, "/shards")
+		require.Equal(t, http.StatusOK, code)
+		require.JSONEq(t, "[1,2]", string(body))
+	})
+
+	t.Run("Block", func(t *testing.T) {
+		var block jsonrpc.RPCBlock
+		code, body := get(t, handler, "/shards/1/blocks/latest")
+		require.Equal(t, http.StatusOK, code)
+		require.NoError(t, json.Unmarshal(body, &block))
+		require.Equal(t, types.BlockNumber(7), block.Number)
+		require.Equal(t, types.ShardId(1), block.ShardId)
+
+		code, body = get(t, handler, "/shards/1/blocks/0x5")
+		require.Equal(t, http.StatusOK, code)
+		require.NoError(t, json.Unmarshal(body, &block))
+		require.Equal(t, types.BlockNumber(5), block.Number)
+	})
+
+	t.Run("InvalidParameter", func(t *testing.T) {
+		code, _ := get(t, handler, "/shards/x/blocks/latest")
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = get(t, handler, "/shards/1/blocks/latest?fullTx=maybe")
+		require.Equal(t, http.StatusBadRequest, code)
+
+		code, _ = get(t, handler, "/accounts/0x11/balance")
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+
+	t.Run("Balance", func(t *testing.T) {
+		code, body := get(t, handler, "/accounts/"+address.Hex()+"/balance")
+		require.Equal(t, http.StatusOK, code)
+		require.JSONEq(t, `"0x2"`, string(body))
+
+		code, _ = get(t, handler, "/accounts/"+address.Hex()+"/balance?block=1")
+		require.Equal(t, http.StatusNotFound, code)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		code, body := get(t, handler, "/transactions/"+common.EmptyHash.Hex()+"/receipt")
+		require.Equal(t, http.StatusNotFound, code)
+		require.JSONEq(t, `{"error":"not found"}`, string(body))
+	})
+
+	t.Run("Spec", func(t *testing.T) {
+		code, body := get(t, handler, SpecPath)
+		require.Equal(t, http.StatusOK, code)
+
+		var spec openApiSpec
+		require.NoError(t, json.Unmarshal(body, &spec))
+		require.Len(t, spec.Paths, len(routes))
+		operation := spec.Paths["/shards/{shardId}/blocks/{ref}"]["get"]
+		require.Equal(t, "getBlock", operation.OperationId)
+		require.Len(t, operation.Parameters, 3)
+		require.True(t, operation.Parameters[0].Required)
+		require.False(t, operation.Parameters[2].Required)
+	})
+}
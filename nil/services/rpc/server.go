@@ -27,6 +27,10 @@ func StartRpcServer(
 
 	defer srv.Stop()
 
+	if cfg.WebsocketSubscriptionLimit > 0 {
+		srv.SetSubscriptionLimit(cfg.WebsocketSubscriptionLimit)
+	}
+
 	var defaultAPIList []transport.API
 
 	for _, api := range rpcAPI {
@@ -50,6 +54,10 @@ func StartRpcServer(
 			nil,
 			cfg.HttpCompression)
 	}
+	if cfg.WebsocketEnabled {
+		// The upgrade requests bypass the stack above, the compression can't be applied to them.
+		httpHandler = srv.WebsocketHandler(ctx, cfg.HttpCORSDomain, httpHandler)
+	}
 
 	listener, httpAddr, err := http.StartHTTPEndpoint(httpEndpoint, &http.HttpEndpointConfig{
 		Timeouts: cfg.HTTPTimeouts,
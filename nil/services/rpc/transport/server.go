@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -23,6 +24,8 @@ const (
 	MetadataApi             = "rpc"
 	defaultBatchConcurrency = 2
 	defaultBatchLimit       = 100
+	// defaultSubscriptionLimit is the maximal number of the subscriptions of a connection.
+	defaultSubscriptionLimit = 128
 )
 
 type ContextKey string
@@ -43,6 +46,7 @@ type Server struct {
 	traceRequests       bool     // Whether to print requests at INFO level
 	debugSingleRequest  bool     // Whether to print requests at INFO level
 	batchLimit          int      // Maximum number of requests in a batch
+	subscriptionLimit   int      // Maximum number of subscriptions of a connection
 	keepHeaders         []string // headers to pass to request handler
 	logger              logging.Logger
 	rpcSlowLogThreshold time.Duration
@@ -68,6 +72,7 @@ func NewServer(
 		traceRequests:       traceRequests,
 		debugSingleRequest:  debugSingleRequest,
 		batchLimit:          defaultBatchLimit,
+		subscriptionLimit:   defaultSubscriptionLimit,
 		keepHeaders:         keepHeaders,
 		logger:              logger,
 		rpcSlowLogThreshold: rpcSlowLogThreshold,
@@ -95,6 +100,11 @@ func (s *Server) SetBatchLimit(limit int) {
 	s.batchLimit = limit
 }
 
+// SetSubscriptionLimit sets limit of number of subscriptions of a connection, non-positive means unlimited
+func (s *Server) SetSubscriptionLimit(limit int) {
+	s.subscriptionLimit = limit
+}
+
 func newHTTPServerConn(r *http.Request, w http.ResponseWriter) ServerCodec {
 	conn := &nil_http.HttpServerConn{Writer: w, Request: r}
 	// if the request is a GET request, and the body is empty, we turn the request into fake json rpc request, see below
@@ -140,11 +150,7 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 		return
 	}
 
-	headers := http.Header{}
-	for _, h := range s.keepHeaders {
-		headers.Add(h, r.Header.Get(h))
-	}
-	ctx = context.WithValue(ctx, HeadersContextKey, headers)
+	ctx = context.WithValue(ctx, HeadersContextKey, s.requestHeaders(r))
 
 	h := newHandler(
 		ctx,
@@ -175,6 +181,81 @@ func (s *Server) ServeSingleRequest(ctx context.Context, r *http.Request, w http
 	}
 }
 
+// ServeCodec reads and processes the RPC requests from the given codec until it's closed or ctx is done.
+// The requests are processed concurrently and can create subscriptions. This is used to serve
+// websocket connections.
+func (s *Server) ServeCodec(ctx context.Context, codec ServerCodec) {
+	defer codec.Close()
+
+	// Don't serve if the server is stopped.
+	if atomic.LoadInt32(&s.run) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	subs := newSubscriptions(ctx, codec, s.subscriptionLimit)
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+		subs.wait()
+	}()
+
+	// Unblock the reading below once ctx is done.
+	go func() {
+		<-ctx.Done()
+		codec.Close()
+	}()
+
+	for atomic.LoadInt32(&s.run) == 1 {
+		reqs, batch, err := codec.Read()
+		if err != nil {
+			// A stream generally can't be resynchronized after malformed input, but the client is told about it.
+			if syntaxErr := (*json.SyntaxError)(nil); errors.As(err, &syntaxErr) {
+				_ = codec.WriteJSON(ctx, errorMessage(&invalidMessageError{"parse error"}))
+			}
+			return
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			notifier := newNotifier(subs)
+			h := newHandler(
+				context.WithValue(ctx, notifierKey{}, notifier),
+				codec,
+				&s.services,
+				s.batchConcurrency,
+				s.traceRequests,
+				s.logger,
+				s.rpcSlowLogThreshold,
+				s.mh)
+			defer h.cancelRoot()
+
+			if batch {
+				if s.batchLimit > 0 && len(reqs) > s.batchLimit {
+					_ = codec.WriteJSON(ctx, errorMessage(fmt.Errorf(
+						"batch limit %d exceeded. Requested batch of size: %d", s.batchLimit, len(reqs))))
+				} else {
+					h.handleBatch(reqs)
+				}
+			} else {
+				h.handleMsg(reqs[0])
+			}
+			notifier.activate()
+		}()
+	}
+}
+
+func (s *Server) requestHeaders(r *http.Request) http.Header {
+	headers := http.Header{}
+	for _, h := range s.keepHeaders {
+		headers.Add(h, r.Header.Get(h))
+	}
+	return headers
+}
+
 // Stop stops reading new requests, waits for stopPendingRequestTimeout to allow pending
 // requests to finish, then closes all codecs that will cancel pending requests.
 func (s *Server) Stop() {
@@ -0,0 +1,149 @@
+package transport
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrNotificationsUnsupported is returned when the connection doesn't support notifications (e.g., over HTTP).
+	ErrNotificationsUnsupported = errors.New("notifications not supported")
+	// ErrTooManySubscriptions is returned when the connection already has the maximal number of subscriptions.
+	ErrTooManySubscriptions = errors.New("too many subscriptions")
+)
+
+// SubscriptionID is the identifier of a subscription sent along with its notifications.
+type SubscriptionID string
+
+// NewSubscriptionID generates a random subscription ID.
+func NewSubscriptionID() SubscriptionID {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return SubscriptionID("0x" + hex.EncodeToString(id[:]))
+}
+
+type notifierKey struct{}
+
+// NotifierFromContext returns the notifier of the connection the request was received from
+// if the connection supports notifications.
+func NotifierFromContext(ctx context.Context) (*Notifier, bool) {
+	n, ok := ctx.Value(notifierKey{}).(*Notifier)
+	return n, ok
+}
+
+// subscriptions tracks the active subscriptions of a connection.
+type subscriptions struct {
+	ctx    context.Context // canceled when the connection is closed
+	conn   JsonWriter
+	limit  int // maximal number of the active subscriptions, unlimited if not positive
+	mu     sync.Mutex
+	active map[SubscriptionID]context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newSubscriptions(ctx context.Context, conn JsonWriter, limit int) *subscriptions {
+	return &subscriptions{
+		ctx:    ctx,
+		conn:   conn,
+		limit:  limit,
+		active: make(map[SubscriptionID]context.CancelFunc),
+	}
+}
+
+func (s *subscriptions) unsubscribe(id SubscriptionID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cancel, ok := s.active[id]
+	if ok {
+		cancel()
+		delete(s.active, id)
+	}
+	return ok
+}
+
+// wait blocks until all the subscriptions are over. The connection context must be canceled before.
+func (s *subscriptions) wait() {
+	s.wg.Wait()
+}
+
+// Notifier is tied to the request that creates subscriptions. The subscriptions are started
+// once the response to the request is sent, so that the client knows the subscription ID
+// before it receives the first notification.
+type Notifier struct {
+	subs    *subscriptions
+	mu      sync.Mutex
+	pending []func()
+}
+
+func newNotifier(subs *subscriptions) *Notifier {
+	return &Notifier{subs: subs}
+}
+
+// Subscribe creates a subscription that sends notifications with the given method. run is called
+// on a background goroutine and sends the notifications with notify until ctx is done,
+// which happens when the subscription is canceled or the connection is closed.
+// Returns ErrTooManySubscriptions if the connection already has the maximal number of subscriptions.
+func (n *Notifier) Subscribe(
+	method string,
+	run func(ctx context.Context, notify func(result any) error) error,
+) (SubscriptionID, error) {
+	id := NewSubscriptionID()
+	ctx, cancel := context.WithCancel(n.subs.ctx)
+
+	n.subs.mu.Lock()
+	if n.subs.limit > 0 && len(n.subs.active) >= n.subs.limit {
+		n.subs.mu.Unlock()
+		cancel()
+		return "", ErrTooManySubscriptions
+	}
+	n.subs.active[id] = cancel
+	n.subs.wg.Add(1)
+	n.subs.mu.Unlock()
+
+	notify := func(result any) error {
+		params, err := json.Marshal(&subscriptionResult{ID: id, Result: result})
+		if err != nil {
+			return err
+		}
+		return n.subs.conn.WriteJSON(ctx, &Message{Version: Version, Method: method, Params: params})
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.pending = append(n.pending, func() {
+		go func() {
+			defer n.subs.wg.Done()
+			defer n.subs.unsubscribe(id)
+
+			_ = run(ctx, notify)
+		}()
+	})
+	return id, nil
+}
+
+// Unsubscribe cancels the subscription of the connection with the given ID.
+// Returns false if there is no such subscription.
+func (n *Notifier) Unsubscribe(id SubscriptionID) bool {
+	return n.subs.unsubscribe(id)
+}
+
+// activate starts the subscriptions created while handling the request.
+func (n *Notifier) activate() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for _, start := range n.pending {
+		start()
+	}
+	n.pending = nil
+}
+
+type subscriptionResult struct {
+	ID     SubscriptionID `json:"subscription"`
+	Result any            `json:"result"`
+}
@@ -0,0 +1,187 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	nil_http "github.com/NilFoundation/nil/nil/services/rpc/internal/http"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport/pb"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// WebsocketJsonProtocol frames every JSON-RPC message or batch as a text message. It is used by default.
+	WebsocketJsonProtocol = "nil-json"
+	// WebsocketProtobufProtocol frames every JSON-RPC message or batch as a binary message
+	// holding its pb.RpcFrame. The params and the results are method-specific and stay encoded as JSON.
+	WebsocketProtobufProtocol = "nil-protobuf"
+
+	wsReadBufferSize  = 1024
+	wsWriteBufferSize = 1024
+)
+
+var (
+	errUnexpectedFrameType = errors.New("unexpected websocket frame type")
+	errInvalidFrame        = errors.New("frame must hold a batch or a single message")
+)
+
+// WebsocketHandler returns a handler that serves the websocket upgrade requests and passes
+// the rest to next. The connections are served until they are closed or ctx is done.
+// Browser requests must come from one of allowedOrigins, "*" allows any origin.
+func (s *Server) WebsocketHandler(ctx context.Context, allowedOrigins []string, next http.Handler) http.Handler {
+	upgrader := websocket.Upgrader{
+		ReadBufferSize:  wsReadBufferSize,
+		WriteBufferSize: wsWriteBufferSize,
+		Subprotocols:    []string{WebsocketJsonProtocol, WebsocketProtobufProtocol},
+		CheckOrigin:     wsOriginChecker(allowedOrigins),
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !websocket.IsWebSocketUpgrade(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// The upgrader has already replied with an error.
+			s.logger.Debug().Err(err).Msg("Failed to upgrade websocket connection")
+			return
+		}
+		conn.SetReadLimit(nil_http.MaxRequestContentLength)
+		// The HTTP server may have set the deadline for reading the upgrade request.
+		_ = conn.SetReadDeadline(time.Time{})
+		s.ServeCodec(context.WithValue(ctx, HeadersContextKey, s.requestHeaders(r)), newWebsocketCodec(conn))
+	})
+}
+
+func wsOriginChecker(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Not a browser request.
+			return true
+		}
+		for _, allowed := range allowedOrigins {
+			if allowed == "*" || strings.EqualFold(allowed, origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// websocketConn reports the remote address as a string to be used by the codec.
+type websocketConn struct {
+	*websocket.Conn
+}
+
+func (c websocketConn) RemoteAddr() string {
+	return c.Conn.RemoteAddr().String()
+}
+
+func newWebsocketCodec(conn *websocket.Conn) ServerCodec {
+	if conn.Subprotocol() == WebsocketProtobufProtocol {
+		return NewFuncCodec(websocketConn{conn}, encodeProtobufFrame(conn), decodeProtobufFrame(conn))
+	}
+	return NewFuncCodec(websocketConn{conn}, conn.WriteJSON, conn.ReadJSON)
+}
+
+func encodeProtobufFrame(conn *websocket.Conn) func(v any) error {
+	return func(v any) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		messages, batch := parseMessage(data)
+		frame := &pb.RpcFrame{Messages: make([]*pb.RpcMessage, len(messages)), Batch: batch}
+		for i, msg := range messages {
+			if frame.Messages[i], err = packRpcMessage(msg); err != nil {
+				return err
+			}
+		}
+		encoded, err := proto.Marshal(frame)
+		if err != nil {
+			return err
+		}
+		return conn.WriteMessage(websocket.BinaryMessage, encoded)
+	}
+}
+
+func decodeProtobufFrame(conn *websocket.Conn) func(v any) error {
+	return func(v any) error {
+		frameType, encoded, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+		if frameType != websocket.BinaryMessage {
+			return errUnexpectedFrameType
+		}
+		var frame pb.RpcFrame
+		if err := proto.Unmarshal(encoded, &frame); err != nil {
+			return err
+		}
+		messages := make([]*Message, len(frame.GetMessages()))
+		for i, msg := range frame.GetMessages() {
+			messages[i] = unpackRpcMessage(msg)
+		}
+
+		var data []byte
+		switch {
+		case frame.GetBatch():
+			data, err = json.Marshal(messages)
+		case len(messages) == 1:
+			data, err = json.Marshal(messages[0])
+		default:
+			return errInvalidFrame
+		}
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(data, v)
+	}
+}
+
+func packRpcMessage(msg *Message) (*pb.RpcMessage, error) {
+	if msg == nil {
+		return &pb.RpcMessage{}, nil
+	}
+	packed := &pb.RpcMessage{
+		Jsonrpc: msg.Version,
+		Id:      msg.ID,
+		Method:  msg.Method,
+		Params:  msg.Params,
+		Result:  msg.Result,
+	}
+	if msg.Error != nil {
+		packed.Error = &pb.RpcError{Code: int64(msg.Error.Code), Message: msg.Error.Message}
+		if msg.Error.Data != nil {
+			data, err := json.Marshal(msg.Error.Data)
+			if err != nil {
+				return nil, err
+			}
+			packed.Error.Data = data
+		}
+	}
+	return packed, nil
+}
+
+func unpackRpcMessage(msg *pb.RpcMessage) *Message {
+	unpacked := &Message{
+		Version: msg.GetJsonrpc(),
+		ID:      msg.GetId(),
+		Method:  msg.GetMethod(),
+		Params:  msg.GetParams(),
+		Result:  msg.GetResult(),
+	}
+	if msg.GetError() != nil {
+		unpacked.Error = &jsonError{Code: int(msg.GetError().GetCode()), Message: msg.GetError().GetMessage()}
+		if data := msg.GetError().GetData(); len(data) > 0 {
+			unpacked.Error.Data = json.RawMessage(data)
+		}
+	}
+	return unpacked
+}
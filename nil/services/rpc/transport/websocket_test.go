@@ -0,0 +1,209 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/services/rpc/transport/pb"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type wsTestService struct{}
+
+func (wsTestService) Echo(_ context.Context, value string) (string, error) {
+	return value, nil
+}
+
+// Cancel cancels the subscription created by Count.
+func (wsTestService) Cancel(ctx context.Context, id SubscriptionID) (bool, error) {
+	notifier, ok := NotifierFromContext(ctx)
+	if !ok {
+		return false, ErrNotificationsUnsupported
+	}
+	return notifier.Unsubscribe(id), nil
+}
+
+// Count sends the numbers up to n as notifications and waits for the cancellation.
+func (wsTestService) Count(ctx context.Context, n int) (SubscriptionID, error) {
+	notifier, ok := NotifierFromContext(ctx)
+	if !ok {
+		return "", ErrNotificationsUnsupported
+	}
+	return notifier.Subscribe("test_subscription", func(ctx context.Context, notify func(any) error) error {
+		for i := range n {
+			if err := notify(i); err != nil {
+				return err
+			}
+		}
+		<-ctx.Done()
+		return nil
+	})
+}
+
+func newWebsocketTestServer(t *testing.T, subscriptionLimit int) string {
+	t.Helper()
+
+	server := NewServer(false, false, logging.NewLogger("Test server"), 0, nil)
+	if subscriptionLimit > 0 {
+		server.SetSubscriptionLimit(subscriptionLimit)
+	}
+	require.NoError(t, server.RegisterName("test", wsTestService{}))
+
+	httpServer := httptest.NewServer(server.WebsocketHandler(t.Context(), nil, http.NotFoundHandler()))
+	t.Cleanup(httpServer.Close)
+	return "ws" + strings.TrimPrefix(httpServer.URL, "http")
+}
+
+func dialWebsocket(t *testing.T, url string, protocols ...string) *websocket.Conn {
+	t.Helper()
+
+	dialer := websocket.Dialer{Subprotocols: protocols}
+	conn, resp, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestWebsocketJson(t *testing.T) {
+	t.Parallel()
+
+	conn := dialWebsocket(t, newWebsocketTestServer(t, 0))
+
+	t.Run("Call", func(t *testing.T) {
+		require.NoError(t, conn.WriteJSON(&Message{Version: Version, ID: json.RawMessage("1"),
+			Method: "test_echo", Params: json.RawMessage(`["hello"]`)}))
+		var response Message
+		require.NoError(t, conn.ReadJSON(&response))
+		require.JSONEq(t, `"hello"`, string(response.Result))
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		require.NoError(t, conn.WriteJSON([]*Message{
+			{Version: Version, ID: json.RawMessage("1"), Method: "test_echo", Params: json.RawMessage(`["a"]`)},
+			{Version: Version, ID: json.RawMessage("2"), Method: "test_echo", Params: json.RawMessage(`["b"]`)},
+		}))
+		var responses []Message
+		require.NoError(t, conn.ReadJSON(&responses))
+		require.Len(t, responses, 2)
+		require.JSONEq(t, `"a"`, string(responses[0].Result))
+		require.JSONEq(t, `"b"`, string(responses[1].Result))
+	})
+
+	t.Run("Subscription", func(t *testing.T) {
+		require.NoError(t, conn.WriteJSON(&Message{Version: Version, ID: json.RawMessage("1"),
+			Method: "test_count", Params: json.RawMessage(`[2]`)}))
+		var response Message
+		require.NoError(t, conn.ReadJSON(&response))
+		require.Nil(t, response.Error)
+		var id SubscriptionID
+		require.NoError(t, json.Unmarshal(response.Result, &id))
+
+		for i := range 2 {
+			var notification Message
+			require.NoError(t, conn.ReadJSON(&notification))
+			require.Equal(t, "test_subscription", notification.Method)
+			var result subscriptionResult
+			require.NoError(t, json.Unmarshal(notification.Params, &result))
+			require.Equal(t, id, result.ID)
+			require.InDelta(t, i, result.Result, 0)
+		}
+	})
+}
+
+func TestWebsocketProtobuf(t *testing.T) {
+	t.Parallel()
+
+	conn := dialWebsocket(t, newWebsocketTestServer(t, 0), WebsocketProtobufProtocol)
+	require.Equal(t, WebsocketProtobufProtocol, conn.Subprotocol())
+
+	roundtrip := func(t *testing.T, request *pb.RpcFrame) *pb.RpcFrame {
+		t.Helper()
+
+		frame, err := proto.Marshal(request)
+		require.NoError(t, err)
+		require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, frame))
+
+		frameType, frame, err := conn.ReadMessage()
+		require.NoError(t, err)
+		require.Equal(t, websocket.BinaryMessage, frameType)
+		var response pb.RpcFrame
+		require.NoError(t, proto.Unmarshal(frame, &response))
+		return &response
+	}
+
+	t.Run("Call", func(t *testing.T) {
+		response := roundtrip(t, &pb.RpcFrame{Messages: []*pb.RpcMessage{
+			{Jsonrpc: Version, Id: []byte("1"), Method: "test_echo", Params: []byte(`["hello"]`)},
+		}})
+		require.False(t, response.GetBatch())
+		require.Len(t, response.GetMessages(), 1)
+		require.Equal(t, []byte("1"), response.GetMessages()[0].GetId())
+		require.JSONEq(t, `"hello"`, string(response.GetMessages()[0].GetResult()))
+	})
+
+	t.Run("Batch", func(t *testing.T) {
+		response := roundtrip(t, &pb.RpcFrame{Batch: true, Messages: []*pb.RpcMessage{
+			{Jsonrpc: Version, Id: []byte("1"), Method: "test_echo", Params: []byte(`["a"]`)},
+			{Jsonrpc: Version, Id: []byte("2"), Method: "test_unknown"},
+		}})
+		require.True(t, response.GetBatch())
+		require.Len(t, response.GetMessages(), 2)
+		require.JSONEq(t, `"a"`, string(response.GetMessages()[0].GetResult()))
+		require.NotNil(t, response.GetMessages()[1].GetError())
+		require.NotZero(t, response.GetMessages()[1].GetError().GetCode())
+	})
+}
+
+func TestWebsocketSubscriptionLimit(t *testing.T) {
+	t.Parallel()
+
+	conn := dialWebsocket(t, newWebsocketTestServer(t, 1))
+	subscribe := func(t *testing.T) *Message {
+		t.Helper()
+
+		require.NoError(t, conn.WriteJSON(&Message{Version: Version, ID: json.RawMessage("1"),
+			Method: "test_count", Params: json.RawMessage(`[0]`)}))
+		var response Message
+		require.NoError(t, conn.ReadJSON(&response))
+		return &response
+	}
+
+	response := subscribe(t)
+	require.Nil(t, response.Error)
+	var id SubscriptionID
+	require.NoError(t, json.Unmarshal(response.Result, &id))
+
+	response = subscribe(t)
+	require.NotNil(t, response.Error)
+	require.Equal(t, ErrTooManySubscriptions.Error(), response.Error.Message)
+
+	// The subscription can be created again once the previous one is canceled.
+	require.NoError(t, conn.WriteJSON(&Message{Version: Version, ID: json.RawMessage("2"),
+		Method: "test_cancel", Params: json.RawMessage(`["` + string(id) + `"]`)}))
+	require.NoError(t, conn.ReadJSON(&response))
+	require.JSONEq(t, "true", string(response.Result))
+	require.Nil(t, subscribe(t).Error)
+}
+
+func TestWebsocketOrigin(t *testing.T) {
+	t.Parallel()
+
+	check := wsOriginChecker([]string{"https://nil.foundation"})
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	require.True(t, check(request))
+
+	request.Header.Set("Origin", "https://nil.foundation")
+	require.True(t, check(request))
+
+	request.Header.Set("Origin", "https://example.com")
+	require.False(t, check(request))
+	require.True(t, wsOriginChecker([]string{"*"})(request))
+}
@@ -0,0 +1,32 @@
+package types
+
+import (
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/internal/params"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+const (
+	// SstoreSentryGas is required to execute SSTORE, although less gas may be actually spent.
+	// It's added to the estimated fee as a reasonable upper bound.
+	SstoreSentryGas = types.Gas(params.SstoreSentryGasEIP2200)
+
+	// ExternalVerificationGas is the heuristic price of the external transaction verification by the smart account.
+	ExternalVerificationGas = types.Gas(10_000)
+)
+
+// FeeEstimationBalanceCap returns the balance the called account is overridden with by the fee estimation.
+func FeeEstimationBalanceCap() types.Value {
+	return mustValueFromDecimal("1000000000000000000000000") // 1 MEther
+}
+
+// FeeEstimationFeeCreditCap returns the maximal fee credit the call is estimated with.
+func FeeEstimationFeeCreditCap() types.Value {
+	return mustValueFromDecimal("500000000000000000000000") // 0.5 MEther
+}
+
+func mustValueFromDecimal(str string) types.Value {
+	v, err := types.NewValueFromDecimal(str)
+	check.PanicIfErr(err)
+	return v
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/NilFoundation/nil/nil/common/hexutil"
 	"github.com/NilFoundation/nil/nil/internal/execution"
 	"github.com/NilFoundation/nil/nil/internal/types"
+	"github.com/NilFoundation/nil/nil/internal/vm"
 )
 
 // @component Contract contract object "Overriding fields of contract during the execution of a transaction call."
@@ -88,3 +89,32 @@ func (overrides *StateOverrides) Override(state *execution.ExecutionState) error
 
 	return nil
 }
+
+// @component BlockOverrides blockOverrides object "Overriding fields of the block during a transaction call."
+// @componentprop Number number integer true "The number of the block."
+// @componentprop Timestamp timestamp integer true "The timestamp of the block."
+// @componentprop BaseFee baseFee integer true "The base fee of the block."
+
+type BlockOverrides struct {
+	Number    *types.BlockNumber `json:"number"`
+	Timestamp *uint64            `json:"timestamp"`
+	BaseFee   *types.Value       `json:"baseFee"`
+}
+
+// Override applies the overrides to the block the call is executed in. The base fee also sets the price of the gas.
+func (overrides *BlockOverrides) Override(state *execution.ExecutionState) {
+	if overrides.BaseFee != nil {
+		state.BaseFee = *overrides.BaseFee
+	}
+	state.BlockContextOverride = func(blockContext *vm.BlockContext) {
+		if overrides.Number != nil {
+			blockContext.BlockNumber = overrides.Number.Uint64()
+		}
+		if overrides.Timestamp != nil {
+			blockContext.Time = *overrides.Timestamp
+		}
+		if overrides.BaseFee != nil {
+			blockContext.BaseFee = overrides.BaseFee.ToBig()
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package txnpool
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/NilFoundation/nil/nil/common"
+	"github.com/NilFoundation/nil/nil/common/check"
+	"github.com/NilFoundation/nil/nil/common/logging"
+	"github.com/NilFoundation/nil/nil/internal/types"
+)
+
+// ErrInvalidBundle is returned for the bundles that can't be executed in their order.
+var ErrInvalidBundle = errors.New("invalid bundle")
+
+// MaxBundleSize limits the number of the transactions of a bundle, including the ones received from the network.
+const MaxBundleSize = 64
+
+// bundle is a group of transactions that must be included in the same block in its order.
+// The transactions of the bundles without this requirement are not tracked after they are added.
+type bundle struct {
+	txns []*metaTxn
+}
+
+func (b *bundle) hashes() []common.Hash {
+	hashes := make([]common.Hash, len(b.txns))
+	for i, txn := range b.txns {
+		hashes[i] = txn.Hash()
+	}
+	return hashes
+}
+
+// checkBundleOrder ensures that the transactions to the same receiver have consecutive seqnos in the order
+// of the bundle, otherwise they can't be executed one after another.
+func checkBundleOrder(txns []*types.Transaction) error {
+	last := make(map[types.Address]types.Seqno)
+	for _, txn := range txns {
+		if seqno, ok := last[txn.To]; ok && txn.Seqno != seqno+1 {
+			return fmt.Errorf("%w: transaction to %s with seqno %d follows the one with seqno %d",
+				ErrInvalidBundle, txn.To, txn.Seqno, seqno)
+		}
+		last[txn.To] = txn.Seqno
+	}
+	return nil
+}
+
+// AddBundle adds either all the transactions or none of them. If some of them are discarded, the rest are discarded
+// as BundleRejected. The pending transactions are never replaced by the ones of a bundle. The transactions of
+// a same-block bundle are only returned by Peek together, and they are discarded together too.
+func (p *TxnPool) AddBundle(
+	ctx context.Context, txns []*types.Transaction, sameBlock bool,
+) ([]DiscardReason, error) {
+	if len(txns) == 0 {
+		return nil, nil
+	}
+	if len(txns) > MaxBundleSize {
+		return nil, fmt.Errorf("%w: bundle must contain at most %d transactions", ErrInvalidBundle, MaxBundleSize)
+	}
+	if sameBlock {
+		if err := checkBundleOrder(txns); err != nil {
+			return nil, err
+		}
+	}
+
+	mms := make([]*metaTxn, len(txns))
+	baseFee := p.GetBaseFee()
+	for i, txn := range txns {
+		mms[i] = newMetaTxn(txn, baseFee)
+	}
+
+	reasons, err := p.addBundle(sameBlock, mms)
+	if err != nil {
+		return nil, err
+	}
+	// Either all the transactions are added or none of them.
+	if reasons[0] != NotSet {
+		return reasons, nil
+	}
+
+	if err := PublishPendingBundle(ctx, p.networkManager, p.cfg.ShardId, sameBlock, mms); err != nil {
+		p.logger.Error().Err(err).
+			Stringer(logging.FieldTransactionHash, mms[0].Hash()).
+			Msg("Failed to publish bundle to network")
+	}
+	return reasons, nil
+}
+
+func (p *TxnPool) addBundle(sameBlock bool, txns []*metaTxn) ([]DiscardReason, error) {
+	reasons := make([]DiscardReason, len(txns))
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	type slot struct {
+		to    types.Address
+		seqno types.Seqno
+	}
+	hashes := make(map[common.Hash]struct{}, len(txns))
+	slots := make(map[slot]struct{}, len(txns))
+	rejected := false
+	for i, txn := range txns {
+		if txn.To.ShardId() != p.cfg.ShardId {
+			return nil, fmt.Errorf(
+				"transaction shard id %d does not match pool shard id %d", txn.To.ShardId(), p.cfg.ShardId)
+		}
+
+		if reason, ok := p.validateTxn(txn); !ok {
+			reasons[i] = reason
+			rejected = true
+			continue
+		}
+
+		if _, ok := hashes[txn.Hash()]; ok || p.idHashKnownLocked(txn.Hash()) {
+			reasons[i] = DuplicateHash
+			rejected = true
+			continue
+		}
+		hashes[txn.Hash()] = struct{}{}
+
+		key := slot{txn.To, txn.Seqno}
+		if _, ok := slots[key]; ok || p.all.get(txn.To, txn.Seqno) != nil {
+			reasons[i] = NotReplaced
+			rejected = true
+			continue
+		}
+		slots[key] = struct{}{}
+	}
+
+	// Each of the transactions may take a place in the queue.
+	if !rejected && uint64(p.queue.Len()+len(txns)) > p.cfg.Size {
+		reasons[0] = PoolOverflow
+		rejected = true
+	}
+
+	if rejected {
+		for i := range reasons {
+			if reasons[i] == NotSet {
+				reasons[i] = BundleRejected
+			}
+		}
+		return reasons, nil
+	}
+
+	var b *bundle
+	if sameBlock {
+		b = &bundle{txns: txns}
+	}
+	for _, txn := range txns {
+		txn.bundle = b
+		check.PanicIfNot(p.addLocked(txn, false) == NotSet)
+		p.logger.Debug().
+			Stringer(logging.FieldTransactionHash, txn.Hash()).
+			Stringer(logging.FieldTransactionTo, txn.To).
+			Int(logging.FieldTransactionSeqno, int(txn.Seqno)).
+			Bool("sameBlock", sameBlock).
+			Msg("Added new transaction of bundle.")
+		p.notifySubscribersLocked(txn.TxnWithHash)
+	}
+	return reasons, nil
+}
+
+func (p *TxnPool) SameBlockBundle(hash common.Hash) []common.Hash {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	txn := p.getLocked(hash)
+	if txn == nil || txn.bundle == nil {
+		return nil
+	}
+	return txn.bundle.hashes()
+}
+
+// discardBundleLocked discards the rest of the same-block bundle of the transaction, since it can't be completed.
+func (p *TxnPool) discardBundleLocked(txn *metaTxn, reason DiscardReason) {
+	b := txn.bundle
+	for _, t := range b.txns {
+		t.bundle = nil
+	}
+	for _, t := range b.txns {
+		if t != txn && p.getLocked(t.Hash()) == t {
+			p.discardLocked(t, reason)
+		}
+	}
+}
+
+// arrangeBundles keeps the transactions of every same-block bundle together in its order.
+// The transactions are ordered as they are given, except that a bundle is placed at the position
+// of its last transaction, and the transactions following the ones of a bundle by seqno are placed after it.
+// The bundles that are not given in full are held back along with the transactions following them,
+// which may hold back other bundles too.
+func arrangeBundles(txns []*metaTxn) []*metaTxn {
+	units := make([]*bundleUnit, 0, len(txns))
+	bundles := make(map[*bundle]*bundleUnit)
+	last := make(map[types.Address]*bundleUnit)
+	for i, txn := range txns {
+		u, ok := bundles[txn.bundle]
+		if !ok {
+			u = &bundleUnit{bundle: txn.bundle}
+			units = append(units, u)
+			if txn.bundle != nil {
+				bundles[txn.bundle] = u
+			}
+		}
+		u.txns = append(u.txns, txn)
+		u.position = i
+
+		// The transactions to the same receiver are given in the order of their seqnos.
+		if prev := last[txn.To]; prev != nil && prev != u {
+			prev.next = append(prev.next, u)
+			u.blockers++
+		}
+		last[txn.To] = u
+	}
+
+	ready := make(bundleUnitQueue, 0, len(units))
+	for _, u := range units {
+		if u.blockers == 0 && u.complete() {
+			ready = append(ready, u)
+		}
+	}
+	heap.Init(&ready)
+
+	res := make([]*metaTxn, 0, len(txns))
+	for ready.Len() > 0 {
+		u, ok := heap.Pop(&ready).(*bundleUnit)
+		check.PanicIfNot(ok)
+		res = append(res, u.ordered()...)
+		for _, next := range u.next {
+			next.blockers--
+			if next.blockers == 0 && next.complete() {
+				heap.Push(&ready, next)
+			}
+		}
+	}
+	return res
+}
+
+// bundleUnit is either a transaction or the transactions of a same-block bundle, which are placed together.
+type bundleUnit struct {
+	bundle   *bundle
+	txns     []*metaTxn
+	position int
+	// blockers is the number of the units preceding the unit by the seqnos of its receivers.
+	blockers int
+	next     []*bundleUnit
+}
+
+func (u *bundleUnit) complete() bool {
+	return u.bundle == nil || len(u.txns) == len(u.bundle.txns)
+}
+
+// ordered returns the transactions in the order of the bundle.
+func (u *bundleUnit) ordered() []*metaTxn {
+	if u.bundle == nil {
+		return u.txns
+	}
+	byHash := make(map[common.Hash]*metaTxn, len(u.txns))
+	for _, txn := range u.txns {
+		byHash[txn.Hash()] = txn
+	}
+	res := make([]*metaTxn, len(u.bundle.txns))
+	for i, t := range u.bundle.txns {
+		res[i] = byHash[t.Hash()]
+	}
+	return res
+}
+
+// bundleUnitQueue orders the units by their positions.
+type bundleUnitQueue []*bundleUnit
+
+func (q bundleUnitQueue) Len() int { return len(q) }
+
+func (q bundleUnitQueue) Less(i, j int) bool { return q[i].position < q[j].position }
+
+func (q bundleUnitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *bundleUnitQueue) Push(x any) {
+	u, ok := x.(*bundleUnit)
+	check.PanicIfNot(ok)
+	*q = append(*q, u)
+}
+
+func (q *bundleUnitQueue) Pop() any {
+	old := *q
+	n := len(old)
+	u := old[n-1]
+	*q = old[:n-1]
+	return u
+}
@@ -10,6 +10,8 @@ type metaTxn struct {
 	effectivePriorityFee types.Value
 	bestIndex            int
 	valid                bool
+	// bundle is only set for the transactions of a same-block bundle.
+	bundle *bundle
 }
 
 func newMetaTxn(txn *types.Transaction, baseFee types.Value) *metaTxn {
@@ -28,6 +30,7 @@ func (m *metaTxn) Clone() *metaTxn {
 		effectivePriorityFee: m.effectivePriorityFee,
 		bestIndex:            m.bestIndex,
 		valid:                m.valid,
+		bundle:               m.bundle,
 	}
 }
 
@@ -2,6 +2,8 @@ package txnpool
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 
 	"github.com/NilFoundation/nil/nil/internal/network"
@@ -30,3 +32,71 @@ func PublishPendingTransaction(
 
 	return networkManager.PubSub().Publish(ctx, topicPendingTransactions(shardId), data)
 }
+
+func topicPendingBundles(shardId types.ShardId) string {
+	return fmt.Sprintf("/shard/%s/pending-bundles", shardId)
+}
+
+// encodeBundle encodes the same-block flag followed by the length-prefixed transactions.
+func encodeBundle(sameBlock bool, txns []*metaTxn) ([]byte, error) {
+	data := []byte{0}
+	if sameBlock {
+		data[0] = 1
+	}
+	for _, txn := range txns {
+		encoded, err := txn.MarshalSSZ()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal txn: %w", err)
+		}
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(encoded)))
+		data = append(data, encoded...)
+	}
+	return data, nil
+}
+
+func decodeBundle(data []byte) (bool, []*types.Transaction, error) {
+	if len(data) == 0 || data[0] > 1 {
+		return false, nil, errors.New("invalid bundle header")
+	}
+	sameBlock := data[0] == 1
+
+	var txns []*types.Transaction
+	for rest := data[1:]; len(rest) > 0; {
+		if len(txns) == MaxBundleSize {
+			return false, nil, fmt.Errorf("bundle must contain at most %d transactions", MaxBundleSize)
+		}
+		if len(rest) < 4 {
+			return false, nil, errors.New("truncated bundle")
+		}
+		size := binary.LittleEndian.Uint32(rest)
+		rest = rest[4:]
+		if uint64(len(rest)) < uint64(size) {
+			return false, nil, errors.New("truncated bundle")
+		}
+		txn := &types.Transaction{}
+		if err := txn.UnmarshalSSZ(rest[:size]); err != nil {
+			return false, nil, err
+		}
+		txns = append(txns, txn)
+		rest = rest[size:]
+	}
+	return sameBlock, txns, nil
+}
+
+func PublishPendingBundle(
+	ctx context.Context,
+	networkManager network.Manager,
+	shardId types.ShardId,
+	sameBlock bool,
+	txns []*metaTxn,
+) error {
+	if networkManager == nil {
+		return nil
+	}
+
+	data, err := encodeBundle(sameBlock, txns)
+	if err != nil {
+		return err
+	}
+	return networkManager.PubSub().Publish(ctx, topicPendingBundles(shardId), data)
+}
@@ -21,6 +21,15 @@ const FeeBumpPercentage = 5
 
 type Pool interface {
 	Add(ctx context.Context, txns ...*types.Transaction) ([]DiscardReason, error)
+	// AddBundle adds either all the transactions or none of them. The transactions of a same-block bundle
+	// are returned by Peek together after each other and are discarded together.
+	AddBundle(ctx context.Context, txns []*types.Transaction, sameBlock bool) ([]DiscardReason, error)
+	// AddWithoutReplacement is the same as Add, but the transaction is discarded as NotReplaced if the pool
+	// contains a transaction with the same receiver and seqno, regardless of the fee bump.
+	AddWithoutReplacement(ctx context.Context, txn *types.Transaction) (DiscardReason, error)
+	// Resend publishes the transaction to the network again if the pool already contains it,
+	// otherwise the transaction is added the same way as by Add or AddWithoutReplacement, depending on the policy.
+	Resend(ctx context.Context, txn *types.Transaction, replacement ReplacementPolicy) (DiscardReason, error)
 	Discard(ctx context.Context, txns []common.Hash, reason DiscardReason) error
 	OnCommitted(ctx context.Context, baseFee types.Value, committed []*types.Transaction) error
 	// IdHashKnown check whether transaction with given Id hash is known to the pool
@@ -28,10 +37,31 @@ type Pool interface {
 	Started() bool
 
 	Peek(n int) ([]*types.TxnWithHash, error)
+	// SameBlockBundle returns the hashes of the transactions of the same-block bundle of the transaction
+	// in the order of the bundle. It's empty if the transaction is not in such a bundle.
+	SameBlockBundle(hash common.Hash) []common.Hash
 	SeqnoToAddress(addr types.Address) (seqno types.Seqno, inPool bool)
+	// NextSeqno returns the first seqno, not lower than both the given one and the committed seqno of the address,
+	// that the pool has no transaction to the address with.
+	NextSeqno(addr types.Address, seqno types.Seqno) types.Seqno
 	Get(hash common.Hash) (*types.Transaction, error)
+	// IsQueued reports whether the pool contains the transaction and whether it waits for the transaction
+	// with the preceding seqno of the receiver, i.e., is not returned by Peek yet.
+	IsQueued(hash common.Hash) (inPool bool, queued bool)
 	GetPendingLength() (int, error)
 	GetSize() int
+	GetBaseFee() types.Value
+
+	// Content returns the transactions of the pool split the same way as by IsQueued, sorted by receiver and seqno.
+	Content() (pending []*types.Transaction, queued []*types.Transaction)
+	// ContentByReceiver is the same as Content, but only for the transactions sent to the address.
+	ContentByReceiver(addr types.Address) (pending []*types.Transaction, queued []*types.Transaction)
+	// ContentSize returns the lengths of the lists returned by Content without copying them.
+	ContentSize() (pending int, queued int)
+
+	// SubscribeNewTransactions returns a channel receiving the transactions accepted by the pool.
+	// The channel is closed when ctx is done.
+	SubscribeNewTransactions(ctx context.Context, bufferSize int) <-chan *types.TxnWithHash
 }
 
 type TxnPool struct {
@@ -49,6 +79,9 @@ type TxnPool struct {
 	all    *ByReceiverAndSeqno // from => (sorted map of txn seqno => *txn)
 	queue  *TxnQueue
 	logger logging.Logger
+
+	// subscribers receive the transactions accepted by the pool, guarded by lock.
+	subscribers map[chan *types.TxnWithHash]struct{}
 }
 
 func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnPool, error) {
@@ -67,6 +100,8 @@ func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnP
 		all:    NewBySenderAndSeqno(logger),
 		queue:  &TxnQueue{},
 		logger: logger,
+
+		subscribers: make(map[chan *types.TxnWithHash]struct{}),
 	}
 
 	if networkManager == nil {
@@ -78,10 +113,18 @@ func New(ctx context.Context, cfg Config, networkManager network.Manager) (*TxnP
 	if err != nil {
 		return nil, err
 	}
+	bundleSub, err := networkManager.PubSub().Subscribe(topicPendingBundles(cfg.ShardId))
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
 
 	go func() {
 		res.listen(ctx, sub)
 	}()
+	go func() {
+		res.listenBundles(ctx, bundleSub)
+	}()
 
 	return res, nil
 }
@@ -99,7 +142,9 @@ func (p *TxnPool) listen(ctx context.Context, sub *network.Subscription) {
 
 		mm := newMetaTxn(txn, p.GetBaseFee())
 
-		reasons, err := p.add(mm)
+		// The transactions from the network may replace the pending ones, since the replacement
+		// was allowed by the node they were sent to.
+		reasons, err := p.add(true, mm)
 		if err != nil {
 			p.logger.Error().Err(err).
 				Stringer(logging.FieldTransactionHash, mm.Hash()).
@@ -115,7 +160,59 @@ func (p *TxnPool) listen(ctx context.Context, sub *network.Subscription) {
 	}
 }
 
+func (p *TxnPool) listenBundles(ctx context.Context, sub *network.Subscription) {
+	defer sub.Close()
+
+	for m := range sub.Start(ctx, true) {
+		sameBlock, txns, err := decodeBundle(m.Data)
+		if err != nil {
+			p.logger.Error().Err(err).
+				Msg("Failed to unmarshal bundle from network")
+			continue
+		}
+		if sameBlock {
+			if err := checkBundleOrder(txns); err != nil {
+				p.logger.Error().Err(err).
+					Msg("Discarded bundle from network")
+				continue
+			}
+		}
+
+		baseFee := p.GetBaseFee()
+		mms := make([]*metaTxn, len(txns))
+		for i, txn := range txns {
+			mms[i] = newMetaTxn(txn, baseFee)
+		}
+		reasons, err := p.addBundle(sameBlock, mms)
+		if err != nil {
+			p.logger.Error().Err(err).
+				Msg("Failed to add bundle from network")
+			continue
+		}
+
+		if len(reasons) > 0 && reasons[0] != NotSet {
+			p.logger.Debug().
+				Stringer(logging.FieldTransactionHash, mms[0].Hash()).
+				Msgf("Discarded bundle from network with reason %s", reasons[0])
+		}
+	}
+}
+
 func (p *TxnPool) Add(ctx context.Context, txns ...*types.Transaction) ([]DiscardReason, error) {
+	return p.addAndPublish(ctx, true, txns...)
+}
+
+func (p *TxnPool) AddWithoutReplacement(ctx context.Context, txn *types.Transaction) (DiscardReason, error) {
+	reasons, err := p.addAndPublish(ctx, false, txn)
+	if err != nil {
+		return 0, err
+	}
+	return reasons[0], nil
+}
+
+func (p *TxnPool) addAndPublish(
+	ctx context.Context, replace bool, txns ...*types.Transaction,
+) ([]DiscardReason, error) {
 	if len(txns) == 0 {
 		return nil, nil
 	}
@@ -126,7 +223,7 @@ func (p *TxnPool) Add(ctx context.Context, txns ...*types.Transaction) ([]Discar
 		mms[i] = newMetaTxn(txn, baseFee)
 	}
 
-	reasons, err := p.add(mms...)
+	reasons, err := p.add(replace, mms...)
 	if err != nil {
 		return nil, err
 	}
@@ -146,7 +243,28 @@ func (p *TxnPool) Add(ctx context.Context, txns ...*types.Transaction) ([]Discar
 	return reasons, nil
 }
 
-func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
+func (p *TxnPool) Resend(
+	ctx context.Context, txn *types.Transaction, replacement ReplacementPolicy,
+) (DiscardReason, error) {
+	p.lock.Lock()
+	mm := p.getLocked(txn.Hash())
+	p.lock.Unlock()
+
+	if mm == nil {
+		reasons, err := p.addAndPublish(ctx, replacement != NoReplacement, txn)
+		if err != nil {
+			return 0, err
+		}
+		return reasons[0], nil
+	}
+
+	if err := PublishPendingTransaction(ctx, p.networkManager, p.cfg.ShardId, mm); err != nil {
+		return 0, fmt.Errorf("failed to publish transaction to network: %w", err)
+	}
+	return NotSet, nil
+}
+
+func (p *TxnPool) add(replace bool, txns ...*metaTxn) ([]DiscardReason, error) {
 	discardReasons := make([]DiscardReason, len(txns))
 
 	p.lock.Lock()
@@ -168,7 +286,7 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 			continue
 		}
 
-		if reason := p.addLocked(txn); reason != NotSet {
+		if reason := p.addLocked(txn, replace); reason != NotSet {
 			discardReasons[i] = reason
 			continue
 		}
@@ -179,6 +297,7 @@ func (p *TxnPool) add(txns ...*metaTxn) ([]DiscardReason, error) {
 			Int(logging.FieldTransactionSeqno, int(txn.Seqno)).
 			Int("total", p.all.tree.Len()).
 			Msg("Added new transaction.")
+		p.notifySubscribersLocked(txn.TxnWithHash)
 	}
 
 	return discardReasons, nil
@@ -224,6 +343,19 @@ func (p *TxnPool) SeqnoToAddress(addr types.Address) (seqno types.Seqno, inPool
 	return p.all.seqno(addr)
 }
 
+func (p *TxnPool) NextSeqno(addr types.Address, seqno types.Seqno) types.Seqno {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if committed, ok := p.seqnoMap[addr]; ok && committed > seqno {
+		seqno = committed
+	}
+	for p.all.get(addr, seqno) != nil {
+		seqno++
+	}
+	return seqno
+}
+
 func (p *TxnPool) GetBaseFee() (baseFee types.Value) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
@@ -240,6 +372,90 @@ func (p *TxnPool) Get(hash common.Hash) (*types.Transaction, error) {
 	return txn.Transaction, nil
 }
 
+func (p *TxnPool) IsQueued(hash common.Hash) (inPool bool, queued bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	txn := p.getLocked(hash)
+	if txn == nil {
+		return false, false
+	}
+	_, ready := p.readyOfReceiverLocked(txn.To)[txn]
+	return true, !ready
+}
+
+func (p *TxnPool) Content() (pending []*types.Transaction, queued []*types.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	ready := p.readyLocked()
+	p.all.ascendAll(func(txn *metaTxn) bool {
+		if _, ok := ready[txn]; ok {
+			pending = append(pending, txn.Transaction)
+		} else {
+			queued = append(queued, txn.Transaction)
+		}
+		return true
+	})
+	return pending, queued
+}
+
+func (p *TxnPool) ContentByReceiver(addr types.Address) (pending []*types.Transaction, queued []*types.Transaction) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	ready := p.readyOfReceiverLocked(addr)
+	p.all.ascend(addr, func(txn *metaTxn) bool {
+		if _, ok := ready[txn]; ok {
+			pending = append(pending, txn.Transaction)
+		} else {
+			queued = append(queued, txn.Transaction)
+		}
+		return true
+	})
+	return pending, queued
+}
+
+func (p *TxnPool) ContentSize() (pending int, queued int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	for _, txn := range p.queue.txns {
+		for ; txn != nil; txn = p.nextSenderTxnLocked(txn.To, txn.Seqno) {
+			pending++
+		}
+	}
+	return pending, p.all.tree.Len() - pending
+}
+
+// readyLocked returns the transactions that would be returned by Peek.
+func (p *TxnPool) readyLocked() map[*metaTxn]struct{} {
+	ready := make(map[*metaTxn]struct{})
+	for _, txn := range p.queue.txns {
+		p.addReadyLocked(ready, txn)
+	}
+	return ready
+}
+
+func (p *TxnPool) readyOfReceiverLocked(addr types.Address) map[*metaTxn]struct{} {
+	ready := make(map[*metaTxn]struct{})
+	for _, txn := range p.queue.txns {
+		if txn.To == addr {
+			p.addReadyLocked(ready, txn)
+			break
+		}
+	}
+	return ready
+}
+
+// addReadyLocked adds the transaction from the queue together with the ones following it by seqno.
+// Only the first executable transaction of a receiver is kept in the queue.
+func (p *TxnPool) addReadyLocked(ready map[*metaTxn]struct{}, txn *metaTxn) {
+	for ; txn != nil; txn = p.nextSenderTxnLocked(txn.To, txn.Seqno) {
+		ready[txn] = struct{}{}
+	}
+}
+
 func (p *TxnPool) GetPendingLength() (int, error) {
 	res, err := p.Peek(0)
 	if err != nil {
@@ -252,6 +468,35 @@ func (p *TxnPool) GetSize() int {
 	return p.all.tree.Len()
 }
 
+func (p *TxnPool) SubscribeNewTransactions(ctx context.Context, bufferSize int) <-chan *types.TxnWithHash {
+	ch := make(chan *types.TxnWithHash, bufferSize)
+
+	p.lock.Lock()
+	p.subscribers[ch] = struct{}{}
+	p.lock.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		delete(p.subscribers, ch)
+		close(ch)
+	})
+	return ch
+}
+
+// notifySubscribersLocked never blocks: subscribers that don't keep up miss the transaction.
+func (p *TxnPool) notifySubscribersLocked(txn *types.TxnWithHash) {
+	for ch := range p.subscribers {
+		select {
+		case ch <- txn:
+		default:
+			p.logger.Debug().
+				Stringer(logging.FieldTransactionHash, txn.Hash()).
+				Msg("Subscriber is too slow, dropping new transaction notification")
+		}
+	}
+}
+
 func (p *TxnPool) getLocked(hash common.Hash) *metaTxn {
 	txn, ok := p.byHash[string(hash.Bytes())]
 	if ok {
@@ -265,12 +510,14 @@ func shouldReplace(existing, candidate *metaTxn) bool {
 	return candidate.effectivePriorityFee.Cmp(adjustedFee) >= 0
 }
 
-func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
+func (p *TxnPool) addLocked(txn *metaTxn, replace bool) DiscardReason {
 	// Insert to pending pool, if pool doesn't have a txn with the same dst and seqno.
-	// If pool has a txn with the same dst and seqno, only fee bump is possible; otherwise NotReplaced is returned.
+	// If pool has a txn with the same dst and seqno, only fee bump is possible if replacement is allowed;
+	// otherwise NotReplaced is returned.
 	found := p.all.get(txn.To, txn.Seqno)
 	if found != nil {
-		if !shouldReplace(found, txn) {
+		// The transaction of a same-block bundle can't be replaced without breaking the bundle.
+		if !replace || found.bundle != nil || !shouldReplace(found, txn) {
 			return NotReplaced
 		}
 		p.discardLocked(found, ReplacedByHigherTip)
@@ -308,6 +555,10 @@ func (p *TxnPool) addLocked(txn *metaTxn) DiscardReason {
 // dropping transaction from all sub-structures and from db
 // Important: don't call it while iterating by "all"
 func (p *TxnPool) discardLocked(txn *metaTxn, reason DiscardReason) {
+	if txn.bundle != nil && reason != Committed {
+		p.discardBundleLocked(txn, reason)
+	}
+
 	hashStr := string(txn.Hash().Bytes())
 	delete(p.byHash, hashStr)
 	p.all.delete(txn, reason)
@@ -448,16 +699,21 @@ func (p *TxnPool) Peek(n int) ([]*types.TxnWithHash, error) {
 
 	// Peek algorithm will alter the queue, so we need to clone it first.
 	q := p.queue.Clone()
-	res := make([]*types.TxnWithHash, 0, q.Len())
+	txns := make([]*metaTxn, 0, q.Len())
 
-	for q.Len() > 0 && len(res) < n {
+	for q.Len() > 0 && len(txns) < n {
 		txn, ok := heap.Pop(q).(*metaTxn)
 		check.PanicIfNot(ok)
-		res = append(res, txn.TxnWithHash)
+		txns = append(txns, txn)
 		if txn = p.nextSenderTxnLocked(txn.To, txn.Seqno); txn != nil {
 			heap.Push(q, txn.Clone())
 		}
 	}
 
+	txns = arrangeBundles(txns)
+	res := make([]*types.TxnWithHash, len(txns))
+	for i, txn := range txns {
+		res[i] = txn.TxnWithHash
+	}
 	return res, nil
 }
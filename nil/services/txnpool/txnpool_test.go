@@ -382,6 +382,188 @@ func (s *SuiteTxnPool) TestUnverifiedDuplicates() {
 	s.Require().NoError(err)
 }
 
+func (s *SuiteTxnPool) TestAddWithoutReplacement() {
+	txn1 := newTransaction(defaultAddress, 0, 123)
+	s.addTransactionsSuccessfully(txn1)
+
+	// The fee bump is enough, but the replacement is not allowed
+	txn2 := common.CopyPtr(txn1)
+	txn2.MaxPriorityFeePerGas = txn2.MaxPriorityFeePerGas.Add64(100)
+	reason, err := s.pool.AddWithoutReplacement(s.ctx, txn2)
+	s.Require().NoError(err)
+	s.Equal(NotReplaced, reason)
+
+	poolTxn, err := s.pool.Get(txn1.Hash())
+	s.Require().NoError(err)
+	s.Equal(txn1, poolTxn)
+
+	reason, err = s.pool.AddWithoutReplacement(s.ctx, newTransaction(defaultAddress, 1, 123))
+	s.Require().NoError(err)
+	s.Equal(NotSet, reason)
+}
+
+func (s *SuiteTxnPool) TestNextSeqno() {
+	s.EqualValues(0, s.pool.NextSeqno(defaultAddress, 0))
+
+	txn0 := newTransaction(defaultAddress, 0, 123)
+	txn1 := newTransaction(defaultAddress, 1, 123)
+	txn3 := newTransaction(defaultAddress, 3, 123)
+	s.addTransactionsSuccessfully(txn0, txn1, txn3)
+
+	// The gap is returned rather than the seqno following the last transaction
+	s.EqualValues(2, s.pool.NextSeqno(defaultAddress, 0))
+
+	err := s.pool.OnCommitted(s.ctx, defaultBaseFee, []*types.Transaction{txn0, txn1})
+	s.Require().NoError(err)
+	s.EqualValues(2, s.pool.NextSeqno(defaultAddress, 0))
+	s.EqualValues(4, s.pool.NextSeqno(defaultAddress, 3))
+}
+
+func (s *SuiteTxnPool) TestIsQueued() {
+	txn0 := newTransaction(defaultAddress, 0, 123)
+	txn2 := newTransaction(defaultAddress, 2, 123)
+	s.addTransactionsSuccessfully(txn0, txn2)
+
+	inPool, queued := s.pool.IsQueued(txn0.Hash())
+	s.True(inPool)
+	s.False(queued)
+
+	// Seqno gap
+	inPool, queued = s.pool.IsQueued(txn2.Hash())
+	s.True(inPool)
+	s.True(queued)
+
+	txn1 := newTransaction(defaultAddress, 1, 123)
+	s.addTransactionsSuccessfully(txn1)
+	inPool, queued = s.pool.IsQueued(txn2.Hash())
+	s.True(inPool)
+	s.False(queued)
+
+	inPool, _ = s.pool.IsQueued(newTransaction(defaultAddress, 3, 123).Hash())
+	s.False(inPool)
+}
+
+func (s *SuiteTxnPool) TestContent() {
+	otherAddress := types.ShardAndHexToAddress(0, "22")
+	txn0 := newTransaction(defaultAddress, 0, 123)
+	txn2 := newTransaction(defaultAddress, 2, 123)
+	otherTxn := newTransaction(otherAddress, 0, 123)
+	s.addTransactionsSuccessfully(txn0, txn2, otherTxn)
+
+	pending, queued := s.pool.Content()
+	s.Equal([]*types.Transaction{txn0, otherTxn}, pending)
+	s.Equal([]*types.Transaction{txn2}, queued)
+
+	pendingSize, queuedSize := s.pool.ContentSize()
+	s.Equal(2, pendingSize)
+	s.Equal(1, queuedSize)
+
+	pending, queued = s.pool.ContentByReceiver(defaultAddress)
+	s.Equal([]*types.Transaction{txn0}, pending)
+	s.Equal([]*types.Transaction{txn2}, queued)
+
+	pending, queued = s.pool.ContentByReceiver(types.ShardAndHexToAddress(0, "33"))
+	s.Empty(pending)
+	s.Empty(queued)
+}
+
+func (s *SuiteTxnPool) TestResend() {
+	txn := newTransaction(defaultAddress, 0, 123)
+
+	// Unknown transaction is added
+	reason, err := s.pool.Resend(s.ctx, txn, ReplaceIfFeeBumped)
+	s.Require().NoError(err)
+	s.Equal(NotSet, reason)
+	s.Equal(1, s.pool.GetSize())
+
+	// Known transaction is not discarded as a duplicate
+	reason, err = s.pool.Resend(s.ctx, txn, ReplaceIfFeeBumped)
+	s.Require().NoError(err)
+	s.Equal(NotSet, reason)
+	s.Equal(1, s.pool.GetSize())
+}
+
+func (s *SuiteTxnPool) TestSubscribeNewTransactions() {
+	ctx, cancel := context.WithCancel(s.ctx)
+	txns := s.pool.SubscribeNewTransactions(ctx, 10)
+
+	txn1 := newTransaction(defaultAddress, 0, 123)
+	s.addTransactionsSuccessfully(txn1)
+	// Discarded transactions are not reported
+	s.addTransactionWithDiscardReason(txn1, DuplicateHash)
+	txn2 := newTransaction(defaultAddress, 1, 123)
+	s.addTransactionsSuccessfully(txn2)
+
+	s.Equal(txn1.Hash(), (<-txns).Hash())
+	s.Equal(txn2.Hash(), (<-txns).Hash())
+
+	cancel()
+	s.Eventually(func() bool {
+		_, ok := <-txns
+		return !ok
+	}, time.Second, 10*time.Millisecond)
+}
+
+func (s *SuiteTxnPool) TestAddBundle() {
+	address2 := types.ShardAndHexToAddress(0, "deadbeef02")
+	s.addTransactionsSuccessfully(newTransaction(defaultAddress, 0, 123))
+
+	// The bundle conflicting with a pending transaction is rejected altogether
+	reasons, err := s.pool.AddBundle(s.ctx, []*types.Transaction{
+		newTransaction(address2, 0, 123),
+		newTransaction(defaultAddress, 0, 200),
+	}, false)
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{BundleRejected, NotReplaced}, reasons)
+	s.Equal(1, s.pool.GetSize())
+
+	txn := newTransaction(defaultAddress, 1, 123)
+	reasons, err = s.pool.AddBundle(s.ctx, []*types.Transaction{newTransaction(address2, 0, 123), txn}, false)
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{NotSet, NotSet}, reasons)
+	s.Equal(3, s.pool.GetSize())
+	s.Empty(s.pool.SameBlockBundle(txn.Hash()))
+
+	// The transactions to the same receiver must follow each other by seqno
+	_, err = s.pool.AddBundle(s.ctx, []*types.Transaction{
+		newTransaction(address2, 1, 123),
+		newTransaction(address2, 3, 123),
+	}, true)
+	s.Require().ErrorIs(err, ErrInvalidBundle)
+}
+
+func (s *SuiteTxnPool) TestSameBlockBundle() {
+	address2 := types.ShardAndHexToAddress(0, "deadbeef02")
+
+	txn1 := newTransaction2(address2, 0, 20, defaultMaxFee, 1)
+	txn2 := newTransaction2(defaultAddress, 1, 10, defaultMaxFee, 2)
+	reasons, err := s.pool.AddBundle(s.ctx, []*types.Transaction{txn2, txn1}, true)
+	s.Require().NoError(err)
+	s.Equal([]DiscardReason{NotSet, NotSet}, reasons)
+	s.Equal([]common.Hash{txn2.Hash(), txn1.Hash()}, s.pool.SameBlockBundle(txn1.Hash()))
+
+	s.checkTransactionsOrder(2, 1)
+
+	s.addTransactionsSuccessfully(
+		newTransaction2(defaultAddress, 0, 300, defaultMaxFee, 3),
+		newTransaction2(defaultAddress, 2, 300, defaultMaxFee, 4))
+	s.checkTransactionsOrder(3, 2, 1, 4)
+
+	// The bundle is held back if it doesn't fit in full
+	txns, err := s.pool.Peek(2)
+	s.Require().NoError(err)
+	s.Require().Len(txns, 1)
+	s.Equal(uint64(3), txns[0].Value.Uint64())
+
+	// The transactions of the bundle are not replaced
+	s.addTransactionWithDiscardReason(newTransaction2(defaultAddress, 1, 300, defaultMaxFee, 5), NotReplaced)
+
+	// The bundle is discarded altogether
+	s.Require().NoError(s.pool.Discard(s.ctx, []common.Hash{txn1.Hash()}, Unverified))
+	s.Equal(2, s.pool.GetSize())
+	s.checkTransactionsOrder(3)
+}
+
 func (s *SuiteTxnPool) checkTransactionsOrder(vals ...int) {
 	s.T().Helper()
 
@@ -20,6 +20,17 @@ func NewConfig(shardId types.ShardId) Config {
 	}
 }
 
+// ReplacementPolicy tells whether a transaction may replace the pending one with the same receiver and seqno.
+type ReplacementPolicy uint8
+
+const (
+	// ReplaceIfFeeBumped replaces the pending transaction if the fee is bumped by FeeBumpPercentage.
+	// It is the zero value, so that the requests not specifying the policy keep the fee-bump replacement.
+	ReplaceIfFeeBumped ReplacementPolicy = 0
+	// NoReplacement discards the transaction as NotReplaced if there is a pending one.
+	NoReplacement ReplacementPolicy = 1
+)
+
 type DiscardReason uint8
 
 const (
@@ -42,6 +53,8 @@ const (
 	Unverified DiscardReason = 22
 	// Transaction max fee is too small
 	TooSmallMaxFee DiscardReason = 23
+	// Another transaction of the same bundle was discarded
+	BundleRejected DiscardReason = 24
 )
 
 func (r DiscardReason) String() string {
@@ -72,6 +85,8 @@ func (r DiscardReason) String() string {
 		return "verification failed"
 	case TooSmallMaxFee:
 		return "max fee too small"
+	case BundleRejected:
+		return "bundle rejected"
 	default:
 		panic(fmt.Sprintf("discard reason: %d", r))
 	}
@@ -168,6 +168,7 @@ func (s *SuiteRegression) TestInsufficientFundsIncExtSeqno() {
 		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
 	}
 
+	// The node doesn't reject the invalid transactions, so the transaction is pooled and fails in a block.
 	txHash, err := s.Client.SendTransaction(s.T().Context(), txn)
 	s.Require().NoError(err)
 	receipt := s.WaitIncludedInMain(txHash)
@@ -220,6 +221,7 @@ func (s *SuiteRegression) TestInsufficientFundsDeploy() {
 		MaxPriorityFeePerGas: fee.MaxPriorityFeePerGas,
 	}
 
+	// The deploy isn't rejected by the node either, it fails in a block without increasing the seqno.
 	txHash, err = s.Client.SendTransaction(s.T().Context(), txn)
 	s.Require().NoError(err)
 	receipt = s.WaitIncludedInMain(txHash)
@@ -113,6 +113,9 @@ func (s *RpcSuite) Start(cfg *nilservice.Config) {
 	if cfg.RunMode == nilservice.CollatorsOnlyRunMode {
 		service := <-serviceInterop
 		nodeApiBuilder := rawapi.NodeApiBuilder(s.Db, nil)
+		if cfg.RejectInvalidTransactions {
+			nodeApiBuilder.WithTransactionPreValidation()
+		}
 		for shardId := range types.ShardId(s.ShardsNum) {
 			nodeApiBuilder.
 				WithLocalShardApiRo(shardId).